@@ -0,0 +1,258 @@
+// cmd/typegorm/approval.go
+package main
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// approvalToken holds the --approval-token flag of destructive commands.
+var approvalToken string
+
+// Flags of the approve command.
+var (
+	approveTTL     time.Duration
+	approveKeyFile string
+	approveSteps   int
+)
+
+// approvalPrivateKeyEnv holds the private key of the approve command when no
+// --key-file is given. It must never be available where the guarded commands run.
+const approvalPrivateKeyEnv = "TYPEGORM_APPROVAL_PRIVATE_KEY"
+
+var approveCmd = &cobra.Command{
+	Use:   "approve <operation>",
+	Short: "Sign an approval token for a destructive operation",
+	Long: `Prints a token approving one destructive operation (e.g. "migrate down") on the
+database of the loaded config, to be passed with --approval-token where the
+confirmation phrase cannot be typed (CI). Tokens are signed with an ed25519 private key
+(--key-file, or $TYPEGORM_APPROVAL_PRIVATE_KEY) that only approvers hold; the guarded
+commands verify them with safety.approvalPublicKey. A token is bound to the operation,
+to the target database, to --steps for "migrate down", and expires after --ttl.
+Generate a key pair with 'typegorm approve keygen'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if approveTTL <= 0 {
+			return fmt.Errorf("approve: --ttl must be positive")
+		}
+		key, err := loadApprovalPrivateKey(approveKeyFile)
+		if err != nil {
+			return fmt.Errorf("approve: %w", err)
+		}
+		claim := approvalClaim{
+			Operation: args[0],
+			Scope:     approvalScope(args[0], approveSteps),
+			Target:    approvalTarget(),
+			Expires:   time.Now().Add(approveTTL),
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Approving %q (%s) on %s until %s\n",
+			claim.Operation, claim.Scope, claim.Target, claim.Expires.UTC().Format(time.RFC3339))
+		fmt.Fprintln(cmd.OutOrStdout(), signApproval(key, claim))
+		return nil
+	},
+}
+
+var approveKeygenCmd = &cobra.Command{
+	Use:   "keygen",
+	Short: "Generate an ed25519 key pair for approval tokens",
+	Long: `Prints a new key pair: keep the private key with the approvers (--key-file or
+$TYPEGORM_APPROVAL_PRIVATE_KEY) and set the public key as safety.approvalPublicKey.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return fmt.Errorf("approve keygen: %w", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "private key: %s\npublic key:  %s\n",
+			base64.StdEncoding.EncodeToString(private.Seed()), base64.StdEncoding.EncodeToString(public))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(approveCmd)
+	approveCmd.AddCommand(approveKeygenCmd)
+	approveCmd.Flags().DurationVar(&approveTTL, "ttl", time.Hour, "How long the token stays valid")
+	approveCmd.Flags().StringVar(&approveKeyFile, "key-file", "", "File holding the base64 ed25519 private key (default $"+approvalPrivateKeyEnv+")")
+	approveCmd.Flags().IntVarP(&approveSteps, "steps", "s", 1, "Number of migrations approved for \"migrate down\"")
+}
+
+// auditRecord is one line of the audit log of destructive operations.
+type auditRecord struct {
+	Time      time.Time `json:"time"`
+	Event     string    `json:"event"` // "approved", "refused" or "completed"
+	Operation string    `json:"operation"`
+	Approval  string    `json:"approval,omitempty"` // "phrase" or "token"
+	User      string    `json:"user"`
+	Host      string    `json:"host"`
+	Dialect   string    `json:"dialect"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// requireApproval guards a destructive operation on a production config: the operation
+// name must be typed back on the command's input, or a valid --approval-token passed.
+// Every decision is written to the audit log; call the returned function with the
+// outcome of the operation to record it too. Non-production configs pass through.
+func requireApproval(cmd *cobra.Command, operation, scope string) (func(error), error) {
+	if !cfg.Safety.Production {
+		return func(error) {}, nil
+	}
+	method := "phrase"
+	var err error
+	if approvalToken != "" {
+		method = "token"
+		claim := approvalClaim{Operation: operation, Scope: scope, Target: approvalTarget()}
+		err = verifyApproval(cfg.Safety.ApprovalPublicKey, claim, approvalToken, time.Now())
+	} else {
+		err = confirmPhrase(cmd.InOrStdin(), cmd.ErrOrStderr(), operation)
+	}
+	if err != nil {
+		writeAudit(auditRecord{Event: "refused", Operation: operation, Approval: method, Error: err.Error()})
+		return nil, fmt.Errorf("%s refused on a production config: %w", operation, err)
+	}
+	writeAudit(auditRecord{Event: "approved", Operation: operation, Approval: method})
+	return func(opErr error) {
+		record := auditRecord{Event: "completed", Operation: operation, Approval: method}
+		if opErr != nil {
+			record.Error = opErr.Error()
+		}
+		writeAudit(record)
+	}, nil
+}
+
+// confirmPhrase asks for the operation name to be typed back.
+func confirmPhrase(in io.Reader, out io.Writer, operation string) error {
+	fmt.Fprintf(out, "This config is flagged as production. Type %q to continue: ", operation)
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("reading the confirmation: %w", err)
+	}
+	if strings.TrimSpace(line) != operation {
+		return fmt.Errorf("confirmation phrase does not match (or use --approval-token)")
+	}
+	return nil
+}
+
+// approvalClaim is what an approval token approves.
+type approvalClaim struct {
+	Operation string    // e.g. "migrate down"
+	Scope     string    // Parameters of the operation, e.g. "steps=2"
+	Target    string    // Database of the config, see approvalTarget
+	Expires   time.Time // Only set when signing; read from the token
+}
+
+// approvalScope returns the parameters of operation bound to its tokens.
+func approvalScope(operation string, steps int) string {
+	if operation == "migrate down" {
+		return "steps=" + strconv.Itoa(steps)
+	}
+	return ""
+}
+
+// approvalTarget identifies the database of the loaded config, without revealing the
+// DSN (which may hold a password): "<dialect>:<sha256 prefix of DSN and hosts>".
+func approvalTarget() string {
+	sum := sha256.Sum256([]byte(cfg.Database.DSN + "|" + strings.Join(cfg.Database.Hosts, ",")))
+	return cfg.Database.Dialect + ":" + hex.EncodeToString(sum[:8])
+}
+
+// approvalMessage is the signed payload of a claim expiring at expiry.
+func approvalMessage(claim approvalClaim, expiry string) []byte {
+	return []byte(strings.Join([]string{claim.Operation, claim.Scope, claim.Target, expiry}, "|"))
+}
+
+// signApproval returns the token approving claim:
+// <expiry unix seconds>.<base64url ed25519 signature of "operation|scope|target|expiry">.
+func signApproval(key ed25519.PrivateKey, claim approvalClaim) string {
+	expiry := strconv.FormatInt(claim.Expires.Unix(), 10)
+	return expiry + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(key, approvalMessage(claim, expiry)))
+}
+
+// verifyApproval checks a token from signApproval against the base64 public key.
+func verifyApproval(publicKey string, claim approvalClaim, token string, now time.Time) error {
+	if publicKey == "" {
+		return fmt.Errorf("approval tokens require safety.approvalPublicKey")
+	}
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("safety.approvalPublicKey is not a base64 ed25519 public key")
+	}
+	expiry, encoded, ok := strings.Cut(token, ".")
+	seconds, err := strconv.ParseInt(expiry, 10, 64)
+	signature, sigErr := base64.RawURLEncoding.DecodeString(encoded)
+	if !ok || err != nil || sigErr != nil {
+		return fmt.Errorf("malformed approval token")
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), approvalMessage(claim, expiry), signature) {
+		return fmt.Errorf("approval token is not valid for %q (%s) on %s", claim.Operation, claim.Scope, claim.Target)
+	}
+	if now.After(time.Unix(seconds, 0)) {
+		return fmt.Errorf("approval token expired at %s", time.Unix(seconds, 0).UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// loadApprovalPrivateKey reads the base64 ed25519 private key (seed) from path, or
+// from $TYPEGORM_APPROVAL_PRIVATE_KEY when path is empty.
+func loadApprovalPrivateKey(path string) (ed25519.PrivateKey, error) {
+	encoded := os.Getenv(approvalPrivateKeyEnv)
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the private key: %w", err)
+		}
+		encoded = string(data)
+	}
+	if encoded == "" {
+		return nil, fmt.Errorf("no private key: pass --key-file or set $%s", approvalPrivateKeyEnv)
+	}
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("the private key is not a base64 ed25519 seed (see 'typegorm approve keygen')")
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// writeAudit appends a record to the audit log (stderr when none is configured).
+func writeAudit(record auditRecord) {
+	safety := cfg.Safety
+	record.Time = time.Now().UTC()
+	record.Dialect = cfg.Database.Dialect
+	record.Host, _ = os.Hostname()
+	if current, err := user.Current(); err == nil {
+		record.User = current.Username
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not encode audit record: %v\n", err)
+		return
+	}
+	if safety.AuditLog == "" {
+		fmt.Fprintf(os.Stderr, "AUDIT %s\n", line)
+		return
+	}
+	f, err := os.OpenFile(safety.AuditLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not open audit log %s: %v\nAUDIT %s\n", safety.AuditLog, err, line)
+		return
+	}
+	defer f.Close()
+	if _, err := fmt.Fprintf(f, "%s\n", line); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not write audit log %s: %v\nAUDIT %s\n", safety.AuditLog, err, line)
+	}
+}
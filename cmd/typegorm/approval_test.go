@@ -0,0 +1,122 @@
+// cmd/typegorm/approval_test.go
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+)
+
+// testApprovalKeys returns a key pair for approval tokens and the base64 public key.
+func testApprovalKeys(t *testing.T) (ed25519.PrivateKey, string) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return private, base64.StdEncoding.EncodeToString(public)
+}
+
+func TestApprovalToken(t *testing.T) {
+	private, public := testApprovalKeys(t)
+	_, otherPublic := testApprovalKeys(t)
+	now := time.Now()
+	claim := approvalClaim{Operation: "migrate down", Scope: "steps=1", Target: "mysql:0123456789abcdef", Expires: now.Add(time.Hour)}
+	token := signApproval(private, claim)
+
+	assert.NoError(t, verifyApproval(public, claim, token, now))
+	for name, other := range map[string]approvalClaim{
+		"operation": {Operation: "schema drop", Scope: claim.Scope, Target: claim.Target},
+		"steps":     {Operation: claim.Operation, Scope: "steps=5", Target: claim.Target},
+		"database":  {Operation: claim.Operation, Scope: claim.Scope, Target: "mysql:fedcba9876543210"},
+	} {
+		assert.ErrorContains(t, verifyApproval(public, other, token, now), "not valid", name)
+	}
+	assert.ErrorContains(t, verifyApproval(otherPublic, claim, token, now), "not valid")
+	assert.ErrorContains(t, verifyApproval(public, claim, token, now.Add(2*time.Hour)), "expired")
+	assert.ErrorContains(t, verifyApproval(public, claim, "garbage", now), "malformed")
+	assert.ErrorContains(t, verifyApproval("c2VjcmV0", claim, token, now), "not a base64 ed25519 public key")
+	assert.Error(t, verifyApproval("", claim, token, now))
+}
+
+func TestApprovalTarget(t *testing.T) {
+	saved := cfg
+	defer func() { cfg = saved }()
+	cfg = config.Config{Database: config.DatabaseConfig{Dialect: "mysql", DSN: "app:secret@tcp(db1:3306)/app"}}
+	target := approvalTarget()
+	assert.Regexp(t, `^mysql:[0-9a-f]{16}$`, target)
+	assert.NotContains(t, target, "secret")
+
+	cfg.Database.DSN = "app:secret@tcp(db2:3306)/app"
+	assert.NotEqual(t, target, approvalTarget(), "another database gets another target")
+}
+
+func TestLoadApprovalPrivateKey(t *testing.T) {
+	private, _ := testApprovalKeys(t)
+	path := filepath.Join(t.TempDir(), "approval.key")
+	require.NoError(t, os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(private.Seed())+"\n"), 0o600))
+
+	key, err := loadApprovalPrivateKey(path)
+	require.NoError(t, err)
+	assert.Equal(t, private, key)
+
+	t.Setenv(approvalPrivateKeyEnv, "")
+	_, err = loadApprovalPrivateKey("")
+	assert.ErrorContains(t, err, "no private key")
+	t.Setenv(approvalPrivateKeyEnv, "not-a-key")
+	_, err = loadApprovalPrivateKey("")
+	assert.ErrorContains(t, err, "not a base64 ed25519 seed")
+}
+
+func TestRequireApproval(t *testing.T) {
+	saved := cfg
+	defer func() { cfg, approvalToken = saved, "" }()
+	auditLog := filepath.Join(t.TempDir(), "audit.log")
+	private, public := testApprovalKeys(t)
+	cfg = config.Config{Safety: config.SafetyConfig{Production: true, ApprovalPublicKey: public, AuditLog: auditLog}}
+
+	cmd := &cobra.Command{}
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetIn(strings.NewReader("migrate\n"))
+	_, err := requireApproval(cmd, "migrate down", "steps=1")
+	assert.ErrorContains(t, err, "migrate down refused on a production config")
+
+	cmd.SetIn(strings.NewReader("migrate down\n"))
+	audit, err := requireApproval(cmd, "migrate down", "steps=1")
+	require.NoError(t, err)
+	audit(errors.New("boom"))
+
+	approvalToken = signApproval(private, approvalClaim{Operation: "migrate down", Scope: "steps=1", Target: approvalTarget(), Expires: time.Now().Add(time.Minute)})
+	cmd.SetIn(strings.NewReader(""))
+	_, err = requireApproval(cmd, "migrate down", "steps=3")
+	assert.ErrorContains(t, err, "not valid", "the token approves 1 step")
+	_, err = requireApproval(cmd, "migrate down", "steps=1")
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(auditLog)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 5)
+	assert.Contains(t, lines[0], `"event":"refused"`)
+	assert.Contains(t, lines[1], `"event":"approved","operation":"migrate down","approval":"phrase"`)
+	assert.Contains(t, lines[2], `"event":"completed"`)
+	assert.Contains(t, lines[2], `"error":"boom"`)
+	assert.Contains(t, lines[3], `"event":"refused","operation":"migrate down","approval":"token"`)
+	assert.Contains(t, lines[4], `"event":"approved","operation":"migrate down","approval":"token"`)
+
+	cfg.Safety.Production = false
+	approvalToken = ""
+	_, err = requireApproval(cmd, "migrate down", "steps=1")
+	assert.NoError(t, err, "non-production configs are not gated")
+}
@@ -15,6 +15,8 @@ import (
 var (
 	// cfgFile will store the configuration file path provided via the --config flag
 	cfgFile string
+	// envName stores the config profile from the --env flag (TYPEGORM_ENV when empty)
+	envName string
 
 	// cfg will hold the loaded and validated configuration.
 	// Making it accessible to other files within the 'main' package (cmd/typegorm).
@@ -37,7 +39,14 @@ following principles similar to TypeORM's migrations.`,
 		// Call the LoadConfig function we created.
 		// Pass the cfgFile flag value. If it's an empty string, LoadConfig
 		// will try to find the default files (typegorm.yaml, etc.).
-		loadedCfg, err := config.LoadConfig(cfgFile)
+		// The --env flag selects the profile; LoadConfig falls back to TYPEGORM_ENV.
+		var loadedCfg config.Config
+		var err error
+		if envName != "" {
+			loadedCfg, err = config.LoadConfigEnv(cfgFile, envName)
+		} else {
+			loadedCfg, err = config.LoadConfig(cfgFile)
+		}
 		if err != nil {
 			// If LoadConfig returns an error (file not found AND specified,
 			// parsing error, or validation error), return the error.
@@ -77,6 +86,8 @@ func init() {
 	// - Fourth is the default value ("" - empty string, causing LoadConfig to check defaults).
 	// - Fifth is the help description.
 	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "config file (default is typegorm.yaml in ., $HOME/.typegorm, /etc/typegorm/)")
+	// Add the persistent --env flag selecting the config profile (development, test, production...).
+	rootCmd.PersistentFlags().StringVarP(&envName, "env", "e", "", "config profile (default is $TYPEGORM_ENV)")
 
 	// Add the 'migrate' command (defined in migrate.go) as a subcommand of rootCmd.
 	rootCmd.AddCommand(migrateCmd)
@@ -1,38 +1,46 @@
-// // cmd/typegorm/migrate_down.go
+// cmd/typegorm/migrate_down.go
 package main
 
-// import (
-// 	"fmt"
+import (
+	"fmt"
 
-// 	"github.com/spf13/cobra"
-// 	// Import the migration package
-// 	"github.com/chmenegatti/typegorm/pkg/migration"
-// )
+	"github.com/spf13/cobra"
+	// Import the migration package
+	"github.com/chmenegatti/typegorm/pkg/migration"
+)
 
-// var (
-// 	// Flag variable to store the number of steps from --steps flag
-// 	downSteps int
-// )
+var (
+	// Flag variable to store the number of steps from --steps flag
+	downSteps int
+)
 
-// var migrateDownCmd = &cobra.Command{
-// 	Use:   "down",
-// 	Short: "Revert the last applied migration or a specific number of steps",
-// 	Long:  `Reverts migrations that have already been applied. By default, it reverts the last applied migration. Use --steps N to revert N migrations.`,
-// 	RunE: func(cmd *cobra.Command, args []string) error {
-// 		fmt.Println("Executing 'migrate down' command...")
+var migrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Revert the last applied migration or a specific number of steps",
+	Long: `Reverts migrations that have already been applied. By default, it reverts the last applied migration. Use --steps N to revert N migrations.
+On a production config (safety.production), "migrate down" must be typed back to
+confirm, or an --approval-token from 'typegorm approve "migrate down" --steps N' passed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Println("Executing 'migrate down' command...")
+		audit, err := requireApproval(cmd, "migrate down", approvalScope("migrate down", downSteps))
+		if err != nil {
+			return err
+		}
 
-// 		// Call the RunDown function, passing the loaded config and the steps flag value
-// 		err := migration.RunDown(cfg, downSteps)
-// 		if err != nil {
-// 			return fmt.Errorf("migration down command failed: %w", err)
-// 		}
-// 		// Success message is handled within RunDown in this example
-// 		return nil
-// 	},
-// }
+		// Call the RunDown function, passing the loaded config and the steps flag value
+		err = migration.RunDown(cfg, downSteps)
+		audit(err)
+		if err != nil {
+			return fmt.Errorf("migration down command failed: %w", err)
+		}
+		// Success message is handled within RunDown in this example
+		return nil
+	},
+}
 
-// func init() {
-// 	migrateCmd.AddCommand(migrateDownCmd)
-// 	// Define the --steps flag
-// 	migrateDownCmd.Flags().IntVarP(&downSteps, "steps", "s", 1, "Number of migrations to revert (default: 1)")
-// }
+func init() {
+	migrateCmd.AddCommand(migrateDownCmd)
+	// Define the --steps flag
+	migrateDownCmd.Flags().IntVarP(&downSteps, "steps", "s", 1, "Number of migrations to revert (default: 1)")
+	migrateDownCmd.Flags().StringVar(&approvalToken, "approval-token", "", "Signed approval for production configs (see 'typegorm approve')")
+}
@@ -0,0 +1,37 @@
+// cmd/typegorm/migrate_lint.go
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chmenegatti/typegorm/pkg/migration"
+)
+
+var migrateLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Flag destructive operations in migrations",
+	Long: `Checks the Up section of every SQL migration for destructive operations (dropped
+tables or columns, truncations, column type changes, NOT NULL columns without a default)
+and suggests safe multi-step alternatives. Exits with an error when any is found.
+Does not connect to the database.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		issues, err := migration.RunLint(cfg)
+		if err != nil {
+			return fmt.Errorf("migration lint command failed: %w", err)
+		}
+		for _, issue := range issues {
+			fmt.Fprintln(cmd.OutOrStdout(), issue.String())
+		}
+		if len(issues) > 0 {
+			return fmt.Errorf("%d destructive operation(s) found", len(issues))
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), "No destructive operations found.")
+		return nil
+	},
+}
+
+func init() {
+	migrateCmd.AddCommand(migrateLintCmd)
+}
@@ -9,12 +9,20 @@ import (
 	"github.com/chmenegatti/typegorm/pkg/migration"
 )
 
+// allowDestructive holds the --allow-destructive flag of migrate up.
+var allowDestructive bool
+
 var migrateUpCmd = &cobra.Command{
 	Use:   "up",
 	Short: "Apply all pending migrations",
-	Long:  `Applies all migrations that have not yet been run.`,
+	Long: `Applies all migrations that have not yet been run.
+Pending SQL migrations with destructive operations (see 'migrate lint') are refused
+unless --allow-destructive is passed.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		fmt.Println("Executing 'migrate up' command...")
+		if allowDestructive {
+			cfg.Migration.AllowDestructive = true
+		}
 
 		// Call the RunUp function from the migration package, passing the loaded config
 		err := migration.RunUp(cfg)
@@ -29,4 +37,5 @@ var migrateUpCmd = &cobra.Command{
 
 func init() {
 	migrateCmd.AddCommand(migrateUpCmd)
+	migrateUpCmd.Flags().BoolVar(&allowDestructive, "allow-destructive", false, "Apply migrations with destructive operations (DROP COLUMN, type changes, ...)")
 }
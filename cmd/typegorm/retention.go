@@ -0,0 +1,69 @@
+// cmd/typegorm/retention.go
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chmenegatti/typegorm/pkg/typegorm"
+)
+
+var (
+	retentionBatchSize int
+	retentionArchive   bool
+)
+
+var retentionCmd = &cobra.Command{
+	Use:   "retention",
+	Short: "Delete (or archive) expired rows",
+	Long: `Removes the rows of every table in the schema file whose retention column is older
+than its period (e.g. "retention: 90d" on a time column), in bounded batches.
+With --archive, rows are first copied into <table>_archive.
+On a production config (safety.production), "retention" must be typed back to
+confirm, or an --approval-token from 'typegorm approve retention' passed.`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		models, err := loadSchemaFile()
+		if err != nil {
+			return err
+		}
+		audit, err := requireApproval(cmd, "retention", approvalScope("retention", 0))
+		if err != nil {
+			return err
+		}
+		defer func() { audit(err) }()
+		retentionCfg := cfg
+		retentionCfg.Safety.ReadOnly = false // The approval covers the deletes
+		db, err := typegorm.Open(retentionCfg)
+		if err != nil {
+			return fmt.Errorf("retention: %w", err)
+		}
+		defer db.Close()
+
+		values := []any{typegorm.RetentionBatchSize(retentionBatchSize)}
+		if retentionArchive {
+			values = append(values, typegorm.ArchiveExpired())
+		}
+		for _, model := range models {
+			values = append(values, model)
+		}
+		reports, err := db.RunRetention(context.Background(), values...)
+		for _, report := range reports {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: removed %d row(s) older than %s in %d batch(es)\n",
+				report.Table, report.Removed, report.Cutoff.Format("2006-01-02 15:04:05"), report.Batches)
+		}
+		if err != nil {
+			return fmt.Errorf("retention failed: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(retentionCmd)
+	retentionCmd.Flags().StringVarP(&schemaFile, "file", "f", "schema.yaml", "Declarative schema file")
+	retentionCmd.Flags().IntVar(&retentionBatchSize, "batch-size", 1000, "Rows removed per batch")
+	retentionCmd.Flags().BoolVar(&retentionArchive, "archive", false, "Copy expired rows into <table>_archive before deleting them")
+	retentionCmd.Flags().StringVar(&approvalToken, "approval-token", "", "Signed approval for production configs (see 'typegorm approve')")
+}
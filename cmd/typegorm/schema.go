@@ -0,0 +1,58 @@
+// cmd/typegorm/schema.go
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects"
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// schemaFile holds the --file flag shared by the schema subcommands.
+var schemaFile string
+
+// schemaComments holds the --comments flag (also enabled by schema.comments in the config).
+var schemaComments bool
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Work with declarative schema files",
+	Long:  `Reads a declarative schema file (YAML) and generates artifacts from it, such as DDL statements.`,
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+	schemaCmd.PersistentFlags().StringVarP(&schemaFile, "file", "f", "schema.yaml", "Declarative schema file")
+	schemaCmd.PersistentFlags().BoolVar(&schemaComments, "comments", false, "Include table/column comments in generated DDL")
+}
+
+// loadSchemaFile loads the models declared in the --file schema file.
+func loadSchemaFile() ([]*schema.Model, error) {
+	var opts []schema.ParserOption
+	if schemaComments || cfg.Schema.Comments {
+		opts = append(opts, schema.WithComments())
+	}
+	f, err := os.Open(schemaFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schema file: %w", err)
+	}
+	defer f.Close()
+	models, err := schema.NewParser(nil, opts...).LoadYAML(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schema file: %w", err)
+	}
+	return models, nil
+}
+
+// configuredDialect returns the dialect selected in the loaded config without connecting.
+func configuredDialect() (common.Dialect, error) {
+	factory := dialects.Get(cfg.Database.Dialect)
+	if factory == nil {
+		return nil, fmt.Errorf("unsupported or unregistered dialect: '%s'", cfg.Database.Dialect)
+	}
+	return factory().Dialect(), nil
+}
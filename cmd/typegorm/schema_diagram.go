@@ -0,0 +1,48 @@
+// cmd/typegorm/schema_diagram.go
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+var schemaDiagramFormat string
+
+var schemaDiagramCmd = &cobra.Command{
+	Use:   "diagram",
+	Short: "Generate an ER diagram of the schema",
+	Long: `Renders the tables, keys and relations of the schema file as an entity-relationship
+diagram. Use --format mermaid (default) or --format dot (Graphviz).`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		models, err := loadSchemaFile()
+		if err != nil {
+			return err
+		}
+		dialect, err := configuredDialect()
+		if err != nil {
+			return err
+		}
+		doc, err := schema.NewDocument(dialect.GetDataType, models...)
+		if err != nil {
+			return fmt.Errorf("failed to build schema document: %w", err)
+		}
+
+		switch strings.ToLower(schemaDiagramFormat) {
+		case "mermaid":
+			return schema.WriteMermaid(cmd.OutOrStdout(), doc)
+		case "dot":
+			return schema.WriteDOT(cmd.OutOrStdout(), doc)
+		default:
+			return fmt.Errorf("invalid diagram format '%s', must be 'mermaid' or 'dot'", schemaDiagramFormat)
+		}
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaDiagramCmd)
+	schemaDiagramCmd.Flags().StringVar(&schemaDiagramFormat, "format", "mermaid", "Diagram format ('mermaid' or 'dot')")
+}
@@ -0,0 +1,75 @@
+// cmd/typegorm/schema_diagram_test.go
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+)
+
+const diagramSchema = `
+tables:
+  - name: app.customers
+    columns:
+      - { name: id, type: uint, primaryKey: true, autoIncrement: true }
+  - name: order-items
+    columns:
+      - { name: id, type: uint, primaryKey: true, autoIncrement: true }
+      - { name: customer_id, type: uint, references: app.customers.id }
+      - { name: referrer_id, type: uint, nullable: true, references: app.customers.id }
+`
+
+// runSchemaDiagram runs "schema diagram --format format" on diagramSchema.
+func runSchemaDiagram(t *testing.T, format string) (string, error) {
+	savedCfg, savedFile, savedFormat := cfg, schemaFile, schemaDiagramFormat
+	defer func() { cfg, schemaFile, schemaDiagramFormat = savedCfg, savedFile, savedFormat }()
+	schemaFile = filepath.Join(t.TempDir(), "schema.yaml")
+	require.NoError(t, os.WriteFile(schemaFile, []byte(diagramSchema), 0o644))
+	cfg = config.Config{Database: config.DatabaseConfig{Dialect: "mysql"}}
+	schemaDiagramFormat = format
+
+	out := new(bytes.Buffer)
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+	err := schemaDiagramCmd.RunE(cmd, nil)
+	return out.String(), err
+}
+
+func TestSchemaDiagramCommand(t *testing.T) {
+	out, err := runSchemaDiagram(t, "mermaid")
+	require.NoError(t, err)
+	assert.Equal(t, `erDiagram
+    "app.customers" {
+        INT id PK
+    }
+    "order-items" {
+        INT id PK
+        INT customer_id FK
+        INT referrer_id FK
+    }
+    "order-items" }o--|| "app.customers" : "customer_id"
+    "order-items" }o--o| "app.customers" : "referrer_id"
+`, out)
+
+	out, err = runSchemaDiagram(t, "DOT")
+	require.NoError(t, err)
+	assert.Equal(t, `digraph schema {
+    rankdir=LR;
+    node [shape=record, fontname="Helvetica"];
+    "app.customers" [label="{app.customers|id : INT (PK)\l}"];
+    "order-items" [label="{order-items|id : INT (PK)\lcustomer_id : INT (FK)\lreferrer_id : INT (FK)\l}"];
+    "order-items" -> "app.customers" [label="customer_id"];
+    "order-items" -> "app.customers" [label="referrer_id"];
+}
+`, out)
+
+	_, err = runSchemaDiagram(t, "png")
+	assert.ErrorContains(t, err, "invalid diagram format 'png'")
+}
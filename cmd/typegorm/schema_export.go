@@ -0,0 +1,59 @@
+// cmd/typegorm/schema_export.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+var schemaExportOut string
+
+var schemaExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the schema as a JSON document",
+	Long: `Writes a versioned JSON document describing the tables, columns, types, constraints,
+indexes and relations of the schema file, for use by external tooling.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		models, err := loadSchemaFile()
+		if err != nil {
+			return err
+		}
+		dialect, err := configuredDialect()
+		if err != nil {
+			return err
+		}
+		doc, err := schema.NewDocument(dialect.GetDataType, models...)
+		if err != nil {
+			return fmt.Errorf("failed to build schema document: %w", err)
+		}
+		doc.Dialect = dialect.Name()
+
+		var out io.Writer = cmd.OutOrStdout()
+		if schemaExportOut != "" && schemaExportOut != "-" {
+			f, err := os.Create(schemaExportOut)
+			if err != nil {
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(doc); err != nil {
+			return fmt.Errorf("failed to write schema document: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaExportCmd)
+	schemaExportCmd.Flags().StringVarP(&schemaExportOut, "out", "o", "-", "Output file ('-' for stdout)")
+}
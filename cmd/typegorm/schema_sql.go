@@ -0,0 +1,52 @@
+// cmd/typegorm/schema_sql.go
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chmenegatti/typegorm/pkg/typegorm"
+)
+
+// schemaRenames holds the --renames flag of schema sql.
+var schemaRenames bool
+
+var schemaSQLCmd = &cobra.Command{
+	Use:   "sql",
+	Short: "Print the CREATE TABLE statements for a schema file",
+	Long: `Generates the DDL for every table declared in the schema file using the configured dialect.
+The output can be pasted into the Up section of a SQL migration. With --renames, the
+tables and columns declaring renamedFrom are renamed first instead of being recreated.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		models, err := loadSchemaFile()
+		if err != nil {
+			return err
+		}
+		dialect, err := configuredDialect()
+		if err != nil {
+			return err
+		}
+		for _, model := range models {
+			if schemaRenames {
+				for _, rename := range typegorm.RenameStatements(dialect, model) {
+					fmt.Fprintln(cmd.OutOrStdout(), rename+";")
+				}
+			}
+			stmt, err := typegorm.CreateTableSQL(dialect, model)
+			if err != nil {
+				return fmt.Errorf("table %s: %w", model.TableName, err)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), stmt)
+			for _, comment := range typegorm.CommentStatements(dialect, model) {
+				fmt.Fprintln(cmd.OutOrStdout(), comment)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaSQLCmd)
+	schemaSQLCmd.Flags().BoolVar(&schemaRenames, "renames", false, "Emit RENAME statements for tables and columns declaring renamedFrom")
+}
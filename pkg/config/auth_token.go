@@ -0,0 +1,288 @@
+// pkg/config/auth_token.go
+package config
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuthTarget is the database an auth token is requested for.
+type AuthTarget struct {
+	Host string // Host name, as in the DSN
+	Port int
+	User string
+}
+
+// AuthTokenProvider issues short-lived database passwords, such as cloud IAM tokens,
+// and reports when they expire.
+type AuthTokenProvider interface {
+	Token(ctx context.Context, target AuthTarget) (token string, expires time.Time, err error)
+}
+
+// AuthTokenProviderFunc adapts a function to AuthTokenProvider.
+type AuthTokenProviderFunc func(ctx context.Context, target AuthTarget) (string, time.Time, error)
+
+// Token calls f.
+func (f AuthTokenProviderFunc) Token(ctx context.Context, target AuthTarget) (string, time.Time, error) {
+	return f(ctx, target)
+}
+
+var authTokenProviders = struct {
+	sync.RWMutex
+	byName map[string]AuthTokenProvider
+}{byName: map[string]AuthTokenProvider{
+	"rds-iam":  AuthTokenProviderFunc(rdsIAMToken),
+	"gcp-iam":  AuthTokenProviderFunc(gcpIAMToken),
+	"azure-ad": AuthTokenProviderFunc(azureADToken),
+}}
+
+// RegisterAuthTokenProvider makes an auth token provider available to database.authToken
+// (replacing the built-in one of the same name, if any). Built-in providers:
+//
+//	rds-iam    AWS RDS IAM authentication with static credentials from the environment
+//	           (AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN and AWS_REGION)
+//	gcp-iam    Cloud SQL IAM database authentication (GCE metadata server, or GOOGLE_OAUTH_ACCESS_TOKEN)
+//	azure-ad   Azure AD through the managed identity of the host (AZURE_CLIENT_ID selects one)
+func RegisterAuthTokenProvider(name string, provider AuthTokenProvider) {
+	authTokenProviders.Lock()
+	defer authTokenProviders.Unlock()
+	authTokenProviders.byName[strings.ToLower(name)] = provider
+}
+
+// AuthTokenSource hands out the tokens of a provider for one database, fetching a new
+// one shortly before the current one expires. It is safe for concurrent use.
+type AuthTokenSource struct {
+	name     string
+	provider AuthTokenProvider
+	target   AuthTarget
+
+	mu        sync.Mutex
+	token     string
+	refreshAt time.Time // When the token is replaced
+	now       func() time.Time
+}
+
+// NewAuthTokenSource returns the token source of the named provider for target.
+func NewAuthTokenSource(name string, target AuthTarget) (*AuthTokenSource, error) {
+	authTokenProviders.RLock()
+	provider, ok := authTokenProviders.byName[strings.ToLower(name)]
+	names := make([]string, 0, len(authTokenProviders.byName))
+	for known := range authTokenProviders.byName {
+		names = append(names, known)
+	}
+	authTokenProviders.RUnlock()
+	if !ok {
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown auth token provider %q (known: %s)", name, strings.Join(names, ", "))
+	}
+	return &AuthTokenSource{name: name, provider: provider, target: target, now: time.Now}, nil
+}
+
+// Token returns a valid token; rotated reports that it replaces an earlier one, so that
+// connections opened with the old token can be recycled.
+func (s *AuthTokenSource) Token(ctx context.Context) (token string, rotated bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if s.token != "" && now.Before(s.refreshAt) {
+		return s.token, false, nil
+	}
+	token, expires, err := s.provider.Token(ctx, s.target)
+	if err != nil {
+		return "", false, fmt.Errorf("%s auth token: %w", s.name, err)
+	}
+	if expires.IsZero() {
+		expires = now.Add(15 * time.Minute)
+	}
+	// Replaced a minute before it expires, or at four fifths of the lifetime of
+	// short-lived tokens
+	margin := time.Minute
+	if lifetime := expires.Sub(now); lifetime/5 < margin {
+		margin = lifetime / 5
+	}
+	rotated = s.token != "" && token != s.token
+	s.token, s.refreshAt = token, expires.Add(-margin)
+	return token, rotated, nil
+}
+
+// --- Built-in Providers ---
+
+// rdsIAMToken builds an RDS IAM auth token: a presigned rds-db:connect request, valid
+// for 15 minutes. The region is AWS_REGION, or taken from the RDS host name. Only the
+// static credentials of the environment variables are used, not the rest of the AWS
+// credential chain (shared config, SSO, instance or task roles, web identity): in those
+// setups, register a provider built on the AWS SDK (feature/rds/auth.BuildAuthToken).
+func rdsIAMToken(_ context.Context, target AuthTarget) (string, time.Time, error) {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		region = rdsRegion(target.Host)
+	}
+	accessKey, secretKey := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if region == "" || accessKey == "" || secretKey == "" {
+		return "", time.Time{}, fmt.Errorf("AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_REGION must be set")
+	}
+	now := time.Now()
+	endpoint := target.Host + ":" + strconv.Itoa(target.Port)
+	token := presignRDSConnect(endpoint, target.User, region, accessKey, secretKey, os.Getenv("AWS_SESSION_TOKEN"), now)
+	return token, now.Add(15 * time.Minute), nil
+}
+
+// rdsRegion extracts the region of an RDS host name (name.id.region.rds.amazonaws.com).
+func rdsRegion(host string) string {
+	parts := strings.Split(host, ".")
+	for i, part := range parts {
+		if part == "rds" && i > 0 {
+			return parts[i-1]
+		}
+	}
+	return ""
+}
+
+// presignRDSConnect returns the presigned "host:port/?Action=connect&..." URL used as
+// the password of RDS IAM authentication (AWS Signature Version 4, query string).
+func presignRDSConnect(endpoint, user, region, accessKey, secretKey, sessionToken string, now time.Time) string {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	scope := amzDate[:8] + "/" + region + "/rds-db/aws4_request"
+	params := map[string]string{
+		"Action":              "connect",
+		"DBUser":              user,
+		"X-Amz-Algorithm":     "AWS4-HMAC-SHA256",
+		"X-Amz-Credential":    accessKey + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       "900",
+		"X-Amz-SignedHeaders": "host",
+	}
+	if sessionToken != "" {
+		params["X-Amz-Security-Token"] = sessionToken
+	}
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	query := make([]string, len(keys))
+	for i, key := range keys {
+		query[i] = awsEscape(key) + "=" + awsEscape(params[key])
+	}
+	canonicalQuery := strings.Join(query, "&")
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet, "/", canonicalQuery, "host:" + endpoint + "\n", "host", sha256Hex(nil),
+	}, "\n")
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+	signingKey := []byte("AWS4" + secretKey)
+	for _, part := range []string{amzDate[:8], region, "rds-db", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	signature := hmacSHA256(signingKey, stringToSign)
+	return fmt.Sprintf("%s/?%s&X-Amz-Signature=%x", endpoint, canonicalQuery, signature)
+}
+
+// awsEscape is the URI encoding of AWS signatures (RFC 3986 unreserved characters kept).
+func awsEscape(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(url.QueryEscape(s), "+", "%20"), "%7E", "~")
+}
+
+// gcpIAMToken returns the OAuth2 access token of the service account, used as the
+// password of Cloud SQL IAM database users. GOOGLE_OAUTH_ACCESS_TOKEN (e.g. from
+// "gcloud auth print-access-token") takes precedence over the metadata server
+// (GCE_METADATA_HOST overrides its address).
+func gcpIAMToken(ctx context.Context, _ AuthTarget) (string, time.Time, error) {
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		return token, time.Time{}, nil
+	}
+	host := os.Getenv("GCE_METADATA_HOST")
+	if host == "" {
+		host = "metadata.google.internal"
+	}
+	var payload struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	url := "http://" + host + "/computeMetadata/v1/instance/service-accounts/default/token"
+	if err := getTokenJSON(ctx, url, map[string]string{"Metadata-Flavor": "Google"}, &payload); err != nil {
+		return "", time.Time{}, err
+	}
+	return payload.AccessToken, time.Now().Add(time.Duration(payload.ExpiresIn) * time.Second), nil
+}
+
+// azureIMDSEndpoint is the token endpoint of the Azure Instance Metadata Service.
+var azureIMDSEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+// azureADToken returns an Azure AD access token for Azure Database (MySQL/PostgreSQL)
+// from the managed identity of the host; AZURE_CLIENT_ID selects a user-assigned one.
+func azureADToken(ctx context.Context, _ AuthTarget) (string, time.Time, error) {
+	query := url.Values{
+		"api-version": {"2018-02-01"},
+		"resource":    {"https://ossrdbms-aad.database.windows.net"},
+	}
+	if clientID := os.Getenv("AZURE_CLIENT_ID"); clientID != "" {
+		query.Set("client_id", clientID)
+	}
+	var payload struct {
+		AccessToken string `json:"access_token"`
+		ExpiresOn   string `json:"expires_on"` // Unix seconds
+	}
+	if err := getTokenJSON(ctx, azureIMDSEndpoint+"?"+query.Encode(), map[string]string{"Metadata": "true"}, &payload); err != nil {
+		return "", time.Time{}, err
+	}
+	var expires time.Time
+	if seconds, err := strconv.ParseInt(payload.ExpiresOn, 10, 64); err == nil {
+		expires = time.Unix(seconds, 0)
+	}
+	return payload.AccessToken, expires, nil
+}
+
+// tokenHTTPClient is used by the GCP and Azure providers.
+var tokenHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// getTokenJSON GETs a token endpoint and decodes its JSON response into dest.
+func getTokenJSON(ctx context.Context, url string, headers map[string]string, dest any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := tokenHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("token endpoint returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dest); err != nil {
+		return fmt.Errorf("decoding token response: %w", err)
+	}
+	return nil
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthTokenSource_CachesAndRotates(t *testing.T) {
+	calls := 0
+	RegisterAuthTokenProvider("counting", AuthTokenProviderFunc(func(ctx context.Context, target AuthTarget) (string, time.Time, error) {
+		calls++
+		return target.User + "-" + strconv.Itoa(calls), time.Date(2026, 1, 1, 12, 10, 0, 0, time.UTC), nil
+	}))
+	source, err := NewAuthTokenSource("counting", AuthTarget{Host: "db", Port: 3306, User: "app"})
+	require.NoError(t, err)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	source.now = func() time.Time { return now }
+
+	token, rotated, err := source.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "app-1", token)
+	assert.False(t, rotated, "The first token replaces nothing")
+
+	now = now.Add(8 * time.Minute)
+	token, rotated, _ = source.Token(context.Background())
+	assert.Equal(t, "app-1", token, "Reused until a minute before expiry")
+	assert.False(t, rotated)
+
+	now = now.Add(90 * time.Second)
+	token, rotated, _ = source.Token(context.Background())
+	assert.Equal(t, "app-2", token)
+	assert.True(t, rotated)
+
+	_, err = NewAuthTokenSource("kerberos", AuthTarget{})
+	assert.ErrorContains(t, err, `unknown auth token provider "kerberos"`)
+}
+
+func TestRDSIAMToken(t *testing.T) {
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+
+	token, expires, err := rdsIAMToken(context.Background(), AuthTarget{Host: "app.abc123.eu-west-1.rds.amazonaws.com", Port: 3306, User: "app_user"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, "app.abc123.eu-west-1.rds.amazonaws.com:3306/?Action=connect&DBUser=app_user&"), token)
+	assert.Contains(t, token, "X-Amz-Credential=AKIDEXAMPLE%2F")
+	assert.Contains(t, token, "%2Feu-west-1%2Frds-db%2Faws4_request")
+	assert.Contains(t, token, "X-Amz-Expires=900")
+	assert.Regexp(t, `&X-Amz-Signature=[0-9a-f]{64}$`, token)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), expires, time.Minute)
+
+	// Deterministic for a fixed time
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t,
+		presignRDSConnect("db:3306", "app", "us-east-1", "AK", "SK", "", at),
+		presignRDSConnect("db:3306", "app", "us-east-1", "AK", "SK", "", at))
+	assert.NotEqual(t,
+		presignRDSConnect("db:3306", "app", "us-east-1", "AK", "SK", "", at),
+		presignRDSConnect("db:3306", "other", "us-east-1", "AK", "SK", "", at))
+}
+
+func TestGCPAndAzureTokens(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/computeMetadata/v1/instance/service-accounts/default/token":
+			assert.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "gcp-token", "expires_in": 3599})
+		case "/metadata/identity/oauth2/token":
+			assert.Equal(t, "true", r.Header.Get("Metadata"))
+			assert.Equal(t, "https://ossrdbms-aad.database.windows.net", r.URL.Query().Get("resource"))
+			assert.Equal(t, "client-1", r.URL.Query().Get("client_id"))
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "azure-token", "expires_on": "1767225600"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "")
+	t.Setenv("GCE_METADATA_HOST", strings.TrimPrefix(server.URL, "http://"))
+	token, expires, err := gcpIAMToken(context.Background(), AuthTarget{})
+	require.NoError(t, err)
+	assert.Equal(t, "gcp-token", token)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expires, time.Minute)
+
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "from-gcloud")
+	token, _, err = gcpIAMToken(context.Background(), AuthTarget{})
+	require.NoError(t, err)
+	assert.Equal(t, "from-gcloud", token)
+
+	defer func(endpoint string) { azureIMDSEndpoint = endpoint }(azureIMDSEndpoint)
+	azureIMDSEndpoint = server.URL + "/metadata/identity/oauth2/token"
+	t.Setenv("AZURE_CLIENT_ID", "client-1")
+	token, expires, err = azureADToken(context.Background(), AuthTarget{})
+	require.NoError(t, err)
+	assert.Equal(t, "azure-token", token)
+	assert.Equal(t, time.Unix(1767225600, 0), expires)
+}
@@ -30,25 +30,164 @@ type DatabaseConfig struct {
 	Dialect string     `mapstructure:"dialect" validate:"required"` // Ex: "mysql", "sqlite", "mongodb"
 	DSN     string     `mapstructure:"dsn"     validate:"required"` // Data Source Name específico do dialeto
 	Pool    PoolConfig `mapstructure:"pool"`
+	// Hosts lista os servidores candidatos ("host:porta" ou caminho de socket unix),
+	// tentados em ordem a cada nova conexão; substitui o endereço do DSN. No MySQL o
+	// DSN também aceita uma lista: "app@tcp(db1:3306,db2:3306)/app".
+	Hosts []string `mapstructure:"hosts"`
+	// Socket conecta pelo socket unix nesse caminho (tentado antes dos Hosts).
+	Socket string `mapstructure:"socket"`
+	// ConnectTimeout limita a conexão com cada host, para que um servidor fora do ar não
+	// atrase o próximo. Zero usa o padrão do driver.
+	ConnectTimeout time.Duration `mapstructure:"connectTimeout"`
+	// TargetSessionAttrs escolhe o servidor aceito entre os hosts, como o
+	// target_session_attrs do PostgreSQL: "any" (padrão), "read-write"/"primary" ou
+	// "read-only"/"standby". No MySQL é verificado com @@global.read_only.
+	TargetSessionAttrs string `mapstructure:"targetSessionAttrs"`
+	// Failover reconecta ao novo primário quando o atual cai ou passa a recusar escritas
+	// (read-only): os hosts são tentados de novo (e os nomes resolvidos outra vez no
+	// DNS) e as leituras que falharam são repetidas. Veja DB.UseFailoverListener.
+	Failover bool `mapstructure:"failover"`
+	// Replicas são DSNs de réplicas de leitura; Find/FindFirst/FindByID fora de
+	// transações são distribuídos entre elas.
+	Replicas []string `mapstructure:"replicas"`
+	// ReadAttempts limita as tentativas de uma leitura quando a conexão com a réplica
+	// falha (próxima réplica, depois o primário). Zero tenta todas.
+	ReadAttempts int `mapstructure:"readAttempts"`
+	// Pools define pools de conexão adicionais no mesmo DSN (ex: "batch" com poucas
+	// conexões), selecionados com typegorm.WithPool(ctx, "batch"), para que consultas
+	// longas não esgotem o pool principal.
+	Pools map[string]PoolConfig `mapstructure:"pools"`
+	// Password é a senha do banco, aplicada ao DSN pelo dialeto (no MySQL, substitui a
+	// do DSN). Normalmente vem de PasswordFrom em vez do arquivo.
+	Password string `mapstructure:"password"`
+	// PasswordFrom e DSNFrom leem a senha / o DSN de um provedor de segredos ao carregar
+	// a configuração: "env:DB_PASSWORD", "file:/run/secrets/db", "vault:kv/db#password",
+	// "aws:prod/db#password" ou um esquema registrado com RegisterSecretProvider.
+	PasswordFrom string `mapstructure:"passwordFrom"`
+	DSNFrom      string `mapstructure:"dsnFrom"`
+	// SecretRefresh relê os segredos nesse intervalo; quando mudam, as novas conexões
+	// do pool usam as credenciais novas e as ociosas são fechadas. Zero desativa.
+	SecretRefresh time.Duration `mapstructure:"secretRefresh"`
+	// AuthToken autentica com tokens de curta duração no lugar da senha: "rds-iam" (AWS
+	// RDS IAM), "gcp-iam" (Cloud SQL IAM), "azure-ad" (identidade gerenciada do Azure) ou
+	// um provedor registrado com RegisterAuthTokenProvider. Os tokens são renovados antes
+	// de expirar e as conexões ociosas recriadas; no MySQL, use TLS no DSN (tls=true).
+	AuthToken string `mapstructure:"authToken"`
 }
 
 // LoggingConfig define as configurações de logging.
 type LoggingConfig struct {
 	Level  string `mapstructure:"level"`  // Ex: "debug", "info", "warn", "error"
 	Format string `mapstructure:"format"` // Ex: "text", "json"
+	// LeakDetection rastreia Rows e transações abertas e registra onde foram criadas
+	// quando são coletadas sem Close/Commit/Rollback. Ativado automaticamente com level "debug".
+	LeakDetection bool `mapstructure:"leakDetection"`
 }
 
 // MigrationConfig define as configurações do sistema de migration.
 type MigrationConfig struct {
 	Directory string `mapstructure:"directory"` // Diretório onde os arquivos de migration estão localizados
 	TableName string `mapstructure:"tableName"` // Nome da tabela de controle de migrations
+	// Schemas lista os schemas migrados um a um no mesmo banco (ex: um schema por
+	// tenant): cada um é criado se não existir e tem sua própria tabela de controle.
+	// Vazio migra o schema padrão da conexão.
+	Schemas []string `mapstructure:"schemas"`
+	// SchemaOwner é o dono dos schemas de Schemas (CREATE SCHEMA ... AUTHORIZATION e
+	// ALTER SCHEMA ... OWNER TO no Postgres); vazio mantém o usuário da conexão.
+	SchemaOwner string `mapstructure:"schemaOwner"`
+	// AllowDestructive aplica migrations pendentes com operações destrutivas (DROP
+	// COLUMN, mudança de tipo, NOT NULL sem default...), bloqueadas por padrão pelo
+	// lint do migrate up (flag --allow-destructive).
+	AllowDestructive bool `mapstructure:"allowDestructive"`
+}
+
+// ExportConfig define as regras usadas ao exportar dados para outros ambientes.
+type ExportConfig struct {
+	// Anonymize mapeia tabela -> coluna -> regra (ex: users.email: email).
+	// Regras declaradas aqui têm precedência sobre a tag `anonymize` dos modelos.
+	Anonymize map[string]map[string]string `mapstructure:"anonymize"`
+	// AnonymizeKey é a chave do HMAC-SHA256 da regra `hash`, obrigatória para usá-la.
+	// Guarde-a fora do repositório (ex: variável de ambiente).
+	AnonymizeKey string `mapstructure:"anonymizeKey"`
+}
+
+// TransactionConfig define a vigilância de transações abertas por tempo demais.
+type TransactionConfig struct {
+	// WatchdogThreshold: transações abertas por mais tempo que isso geram um aviso
+	// com o stack trace capturado no Begin. Zero desativa o watchdog.
+	WatchdogThreshold time.Duration `mapstructure:"watchdogThreshold"`
+	// WatchdogRollback força o rollback da transação ao atingir o limite, em vez de apenas avisar.
+	WatchdogRollback bool `mapstructure:"watchdogRollback"`
+}
+
+// SchemaConfig define como os modelos Go são interpretados pelo parser.
+type SchemaConfig struct {
+	// GormTags usa as tags `gorm` (traduzidas) nos campos sem tag `typegorm`,
+	// facilitando a migração incremental de modelos escritos para o GORM.
+	GormTags bool `mapstructure:"gormTags"`
+	// Comments gera cláusulas COMMENT de tabela/coluna a partir da tag `comment` e dos
+	// comentários de documentação Go dos pacotes listados em CommentSources.
+	Comments       bool     `mapstructure:"comments"`
+	CommentSources []string `mapstructure:"commentSources"` // Diretórios com o código-fonte dos modelos
+	// PreserveCase mantém o nome exato (com maiúsculas) dos structs e campos Go como
+	// nomes de tabela/coluna, em vez de convertê-los para snake_case minúsculo.
+	PreserveCase bool `mapstructure:"preserveCase"`
+	// DefaultSchema qualifica as tabelas dos modelos sem schema explícito (tag de modelo
+	// "table:schema.nome"), p.ex. "accounting" no Postgres ou SQL Server.
+	DefaultSchema string `mapstructure:"defaultSchema"`
+	// StrictTags rejeita opções de tag desconhecidas (erro em vez de aviso); ativado
+	// por padrão no perfil production.
+	StrictTags bool `mapstructure:"strictTags"`
+}
+
+// HooksConfig define o pool de workers dos callbacks assíncronos (typegorm.Async)
+// e se os callbacks After* dentro de transações esperam o commit.
+type HooksConfig struct {
+	AsyncWorkers       int           `mapstructure:"asyncWorkers"`       // Número de workers (padrão 4)
+	AsyncQueueSize     int           `mapstructure:"asyncQueueSize"`     // Tamanho da fila (padrão 1024)
+	AsyncQueueTimeout  time.Duration `mapstructure:"asyncQueueTimeout"`  // Espera por espaço na fila cheia; depois o callback é descartado (padrão 1s)
+	DeferInTransaction bool          `mapstructure:"deferInTransaction"` // Callbacks After* em uma Tx rodam só após o commit (padrão false)
+}
+
+// QueryConfig define limites aplicados às consultas.
+type QueryConfig struct {
+	// MaxRows aborta um Find que retornaria mais linhas que isso (typegorm.ErrTooManyRows).
+	// Pode ser sobrescrito por chamada com typegorm.MaxRows(n). Zero desativa.
+	MaxRows int `mapstructure:"maxRows"`
+}
+
+// SafetyConfig protege ambientes sensíveis contra operações destrutivas da CLI.
+type SafetyConfig struct {
+	// Production marca a configuração como de produção: operações destrutivas da CLI
+	// (migrate down) exigem a frase de confirmação digitada ou um token de aprovação
+	// assinado (--approval-token), e são registradas em AuditLog.
+	Production bool `mapstructure:"production"`
+	// ApprovalPublicKey é a chave pública ed25519 (base64) que verifica os tokens de
+	// aprovação de `typegorm approve`; a chave privada fica só com quem aprova, nunca
+	// com a CLI que executa. Vazia, só a frase digitada é aceita.
+	ApprovalPublicKey string `mapstructure:"approvalPublicKey"`
+	// AuditLog é o arquivo onde cada operação destrutiva é registrada (uma linha JSON
+	// por evento); vazio registra na saída de erro.
+	AuditLog string `mapstructure:"auditLog"`
+	// ReadOnly faz o DB recusar escritas (ErrReadOnlyDatabase) e abrir as transações
+	// em modo somente leitura. Ativado por padrão no perfil production: serviços que
+	// escrevem em produção o desligam no bloco profiles.production.
+	ReadOnly bool `mapstructure:"readOnly"`
 }
 
 // Config é a struct principal que agrega todas as configurações.
 type Config struct {
-	Database  DatabaseConfig  `mapstructure:"database"`
-	Logging   LoggingConfig   `mapstructure:"logging"`
-	Migration MigrationConfig `mapstructure:"migration"`
+	// Env é o perfil selecionado (--env ou TYPEGORM_ENV), vazio sem perfil.
+	Env         string            `mapstructure:"-"`
+	Database    DatabaseConfig    `mapstructure:"database"`
+	Logging     LoggingConfig     `mapstructure:"logging"`
+	Migration   MigrationConfig   `mapstructure:"migration"`
+	Export      ExportConfig      `mapstructure:"export"`
+	Transaction TransactionConfig `mapstructure:"transaction"`
+	Schema      SchemaConfig      `mapstructure:"schema"`
+	Hooks       HooksConfig       `mapstructure:"hooks"`
+	Query       QueryConfig       `mapstructure:"query"`
+	Safety      SafetyConfig      `mapstructure:"safety"`
 }
 
 // NewDefaultConfig cria uma configuração com valores padrão.
@@ -2,8 +2,10 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"log" // Import log for temporary debugging
+	"os"
 	"strings"
 	"time"
 
@@ -11,10 +13,27 @@ import (
 	"github.com/spf13/viper"
 )
 
-// LoadConfig loads the TypeGORM configuration from various sources.
+// LoadConfig loads the TypeGORM configuration from various sources, with the profile
+// named by TYPEGORM_ENV (see LoadConfigEnv).
 // Precedence order: Environment Variables > Config File > Default Values.
 // Validates the resulting configuration.
 func LoadConfig(configPath string) (Config, error) {
+	return LoadConfigEnv(configPath, os.Getenv("TYPEGORM_ENV"))
+}
+
+// LoadConfigEnv loads the configuration like LoadConfig, for the given profile
+// ("development", "test", "production" or any block under "profiles" in the config
+// file; "" for none). The profile block overrides the top-level settings of the file,
+// and well-known profiles bring their own defaults:
+//
+//	database:
+//	  dialect: mysql
+//	  dsn: "root@tcp(localhost:3306)/app"
+//	profiles:
+//	  production:
+//	    database:
+//	      dsn: "app@tcp(db.internal:3306)/app"
+func LoadConfigEnv(configPath string, env string) (Config, error) {
 	// 1. Create a new local Viper instance
 	v := viper.New()
 
@@ -59,6 +78,16 @@ func LoadConfig(configPath string) (Config, error) {
 		}
 	}
 
+	// 3.1 Apply the selected profile: its defaults, then its block of the config file
+	env = normalizeEnv(env)
+	cfg.Env = env
+	if env != "" {
+		applyProfileDefaults(&cfg, env)
+		if err := mergeProfile(v, env); err != nil {
+			return cfg, err
+		}
+	}
+
 	// 4. Populate the 'cfg' struct with values read by Viper
 	// Viper merges sources (file, env) onto the 'v' instance.
 	// Unmarshal attempts to place these values into the 'cfg' struct,
@@ -88,6 +117,21 @@ func LoadConfig(configPath string) (Config, error) {
 		log.Println("[LoadConfig DEBUG] Reinforcing database.dsn: IsSet=false") // Debug log
 	}
 	// Apply for other relevant fields...
+	if v.IsSet("database.hosts") {
+		cfg.Database.Hosts = v.GetStringSlice("database.hosts")
+	}
+	if v.IsSet("database.socket") {
+		cfg.Database.Socket = v.GetString("database.socket")
+	}
+	if v.IsSet("database.connecttimeout") {
+		cfg.Database.ConnectTimeout = v.GetDuration("database.connecttimeout")
+	}
+	if v.IsSet("database.targetsessionattrs") {
+		cfg.Database.TargetSessionAttrs = v.GetString("database.targetsessionattrs")
+	}
+	if v.IsSet("database.failover") {
+		cfg.Database.Failover = v.GetBool("database.failover")
+	}
 	if v.IsSet("logging.level") {
 		cfg.Logging.Level = v.GetString("logging.level")
 	}
@@ -117,8 +161,36 @@ func LoadConfig(configPath string) (Config, error) {
 	if v.IsSet("migration.tablename") {
 		cfg.Migration.TableName = v.GetString("migration.tablename")
 	}
+	if v.IsSet("safety.production") {
+		cfg.Safety.Production = v.GetBool("safety.production")
+	}
+	if v.IsSet("safety.approvalpublickey") {
+		cfg.Safety.ApprovalPublicKey = v.GetString("safety.approvalpublickey")
+	}
+	if v.IsSet("safety.readonly") {
+		cfg.Safety.ReadOnly = v.GetBool("safety.readonly")
+	}
 	log.Println("[LoadConfig DEBUG] Finished reinforcement.") // Debug log
 
+	// 4.2 Resolve secret references (database.dsnFrom, database.passwordFrom)
+	if v.IsSet("database.password") {
+		cfg.Database.Password = v.GetString("database.password")
+	}
+	if v.IsSet("database.passwordfrom") {
+		cfg.Database.PasswordFrom = v.GetString("database.passwordfrom")
+	}
+	if v.IsSet("database.dsnfrom") {
+		cfg.Database.DSNFrom = v.GetString("database.dsnfrom")
+	}
+	if v.IsSet("database.authtoken") {
+		cfg.Database.AuthToken = v.GetString("database.authtoken")
+	}
+	resolved, err := cfg.Database.ResolveCredentials(context.Background())
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Database = resolved
+
 	// 5. Validate the final 'cfg' struct (after all sources have been applied)
 	validate := validator.New()
 	log.Println("[LoadConfig DEBUG] Performing validation...") // Debug log
@@ -298,3 +298,64 @@ logging: level: debug # Invalid mapping here
 	// Ensure it does NOT contain the decoding error message, as reading failed first
 	assert.NotContains(t, err.Error(), "error decoding configuration", "Error should be from reading, not decoding")
 }
+
+// Test profile selection: profile defaults, profile blocks and their precedence.
+func TestLoadConfigEnv_Profiles(t *testing.T) {
+	log.Println("--- Running TestLoadConfigEnv_Profiles ---")
+	configContent := `
+database:
+  dialect: "mysql"
+  dsn: "root@tcp(localhost:3306)/app"
+migration:
+  directory: "db/migrations"
+profiles:
+  production:
+    database:
+      dsn: "app@tcp(db.internal:3306)/app"
+  staging:
+    database:
+      dsn: "app@tcp(staging:3306)/app"
+    logging:
+      level: "info"
+`
+	configFile := createTempConfigFile(t, configContent)
+	t.Setenv("TYPEGORM_ENV", "")
+	t.Setenv("TYPEGORM_LOGGING_LEVEL", "")
+
+	cfg, err := LoadConfigEnv(configFile, "prod")
+	require.NoError(t, err)
+	assert.Equal(t, EnvProduction, cfg.Env)
+	assert.Equal(t, "app@tcp(db.internal:3306)/app", cfg.Database.DSN, "Profile block > File")
+	assert.Equal(t, "db/migrations", cfg.Migration.Directory, "File values not in the profile are kept")
+	assert.True(t, cfg.Safety.Production, "production guards destructive operations")
+	assert.True(t, cfg.Schema.StrictTags, "production uses strict tags")
+	assert.True(t, cfg.Safety.ReadOnly, "production refuses writes by default")
+	assert.Equal(t, "warn", cfg.Logging.Level)
+
+	t.Setenv("TYPEGORM_SAFETY_READONLY", "false")
+	cfg, err = LoadConfigEnv(configFile, "production")
+	require.NoError(t, err)
+	assert.False(t, cfg.Safety.ReadOnly, "Env > profile defaults")
+
+	cfg, err = LoadConfigEnv(configFile, "development")
+	require.NoError(t, err)
+	assert.Equal(t, "root@tcp(localhost:3306)/app", cfg.Database.DSN, "No block: file values only")
+	assert.Equal(t, "debug", cfg.Logging.Level)
+	assert.False(t, cfg.Safety.Production)
+
+	// Custom profiles need a block; TYPEGORM_ENV selects the profile of LoadConfig
+	t.Setenv("TYPEGORM_ENV", "staging")
+	cfg, err = LoadConfig(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, "app@tcp(staging:3306)/app", cfg.Database.DSN)
+	assert.Equal(t, "info", cfg.Logging.Level)
+
+	t.Setenv("TYPEGORM_DATABASE_DSN", "env-dsn")
+	cfg, err = LoadConfig(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, "env-dsn", cfg.Database.DSN, "Env > Profile block")
+
+	_, err = LoadConfigEnv(configFile, "qa")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown config profile "qa"`)
+}
@@ -0,0 +1,69 @@
+// pkg/config/profile.go
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Well-known profile names, which come with defaults (see applyProfileDefaults).
+const (
+	EnvDevelopment = "development"
+	EnvTest        = "test"
+	EnvProduction  = "production"
+)
+
+// applyProfileDefaults sets the defaults of a well-known profile on cfg. They sit
+// between NewDefaultConfig and the config file: anything the file, the profile block
+// or the environment sets wins.
+//   - development: debug logging (which also enables leak detection).
+//   - test: warnings only, to keep test output readable.
+//   - production: warnings only (which hides the executed SQL), destructive CLI
+//     operations gated (safety.production), writes refused (safety.readOnly; services
+//     that write turn it off in their profile block) and unknown struct tag options
+//     rejected (schema.strictTags).
+func applyProfileDefaults(cfg *Config, env string) {
+	switch env {
+	case EnvDevelopment:
+		cfg.Logging.Level = "debug"
+	case EnvTest:
+		cfg.Logging.Level = "warn"
+	case EnvProduction:
+		cfg.Logging.Level = "warn"
+		cfg.Safety.Production = true
+		cfg.Safety.ReadOnly = true
+		cfg.Schema.StrictTags = true
+	}
+}
+
+// mergeProfile merges the "profiles.<env>" block of the config file over its top-level
+// settings. Unknown profiles without a block are an error (most likely a typo).
+func mergeProfile(v *viper.Viper, env string) error {
+	profile := v.Sub("profiles." + env)
+	if profile == nil {
+		switch env {
+		case EnvDevelopment, EnvTest, EnvProduction:
+			return nil // Built-in defaults only
+		}
+		return fmt.Errorf("unknown config profile %q: add a profiles.%s block or use %s, %s or %s",
+			env, env, EnvDevelopment, EnvTest, EnvProduction)
+	}
+	if err := v.MergeConfigMap(profile.AllSettings()); err != nil {
+		return fmt.Errorf("error merging config profile %q: %w", env, err)
+	}
+	return nil
+}
+
+// normalizeEnv lowercases a profile name and expands the usual abbreviations.
+func normalizeEnv(env string) string {
+	env = strings.ToLower(strings.TrimSpace(env))
+	switch env {
+	case "dev":
+		return EnvDevelopment
+	case "prod":
+		return EnvProduction
+	}
+	return env
+}
@@ -0,0 +1,158 @@
+// pkg/config/secrets.go
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SecretProvider resolves secret references of one scheme, e.g. "kv/db#password" for
+// "vault:kv/db#password".
+type SecretProvider interface {
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// SecretProviderFunc adapts a function to SecretProvider.
+type SecretProviderFunc func(ctx context.Context, ref string) (string, error)
+
+// Resolve calls f.
+func (f SecretProviderFunc) Resolve(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+var secretProviders = struct {
+	sync.RWMutex
+	byScheme map[string]SecretProvider
+}{byScheme: map[string]SecretProvider{
+	"env":  SecretProviderFunc(resolveEnvSecret),
+	"file": SecretProviderFunc(resolveFileSecret),
+}}
+
+// optionalSecretProviders are the schemes registered by the subpackages of
+// pkg/config/secrets, which keep the cloud SDKs out of the programs not using them.
+var optionalSecretProviders = map[string]string{
+	"aws":   "github.com/chmenegatti/typegorm/pkg/config/secrets/aws",
+	"vault": "github.com/chmenegatti/typegorm/pkg/config/secrets/vault",
+}
+
+// RegisterSecretProvider makes a secret provider available for references starting with
+// "scheme:" (replacing the built-in one of the same scheme, if any). Built-in schemes:
+//
+//	env:DB_PASSWORD            environment variable
+//	file:/run/secrets/db       file contents, trailing newline removed
+//
+// Importing pkg/config/secrets/vault or pkg/config/secrets/aws for its side effects adds
+//
+//	vault:kv/db#password       HashiCorp Vault KV
+//	aws:prod/db#password       AWS Secrets Manager
+func RegisterSecretProvider(scheme string, provider SecretProvider) {
+	secretProviders.Lock()
+	defer secretProviders.Unlock()
+	secretProviders.byScheme[strings.ToLower(scheme)] = provider
+}
+
+// ResolveSecret resolves a "scheme:reference" secret with the provider registered for
+// the scheme.
+func ResolveSecret(ctx context.Context, ref string) (string, error) {
+	scheme, rest, ok := strings.Cut(ref, ":")
+	if !ok || scheme == "" {
+		return "", fmt.Errorf("invalid secret reference %q: expected scheme:reference", ref)
+	}
+	secretProviders.RLock()
+	provider, found := secretProviders.byScheme[strings.ToLower(scheme)]
+	secretProviders.RUnlock()
+	if !found {
+		if pkg, ok := optionalSecretProviders[strings.ToLower(scheme)]; ok {
+			return "", fmt.Errorf("secret provider %q in %q is not registered: import _ %q", scheme, ref, pkg)
+		}
+		return "", fmt.Errorf("unknown secret provider %q in %q (known: %s)", scheme, ref, strings.Join(secretSchemes(), ", "))
+	}
+	value, err := provider.Resolve(ctx, rest)
+	if err != nil {
+		return "", fmt.Errorf("resolving secret %q: %w", ref, err)
+	}
+	return value, nil
+}
+
+func secretSchemes() []string {
+	secretProviders.RLock()
+	defer secretProviders.RUnlock()
+	schemes := make([]string, 0, len(secretProviders.byScheme))
+	for scheme := range secretProviders.byScheme {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// ResolveCredentials returns the configuration with the DSN and password read from
+// their secret references (DSNFrom, PasswordFrom), when set. LoadConfig calls it; call
+// it again to pick up rotated credentials.
+func (cfg DatabaseConfig) ResolveCredentials(ctx context.Context) (DatabaseConfig, error) {
+	if cfg.DSNFrom != "" {
+		dsn, err := ResolveSecret(ctx, cfg.DSNFrom)
+		if err != nil {
+			return cfg, fmt.Errorf("database.dsnFrom: %w", err)
+		}
+		cfg.DSN = dsn
+	}
+	if cfg.PasswordFrom != "" {
+		password, err := ResolveSecret(ctx, cfg.PasswordFrom)
+		if err != nil {
+			return cfg, fmt.Errorf("database.passwordFrom: %w", err)
+		}
+		cfg.Password = password
+	}
+	return cfg, nil
+}
+
+func resolveEnvSecret(_ context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
+
+func resolveFileSecret(_ context.Context, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// SplitSecretKey splits "path#key" references; key is empty without '#'.
+func SplitSecretKey(ref string) (path, key string) {
+	path, key, _ = strings.Cut(ref, "#")
+	return path, key
+}
+
+// PickSecretKey returns the value of key in a secret's key/value data; without a key,
+// the secret must hold a single value.
+func PickSecretKey(data map[string]any, key string) (string, error) {
+	if key == "" {
+		if len(data) != 1 {
+			return "", fmt.Errorf("secret has %d keys: name one with #key", len(data))
+		}
+		for _, value := range data {
+			return secretString(value), nil
+		}
+	}
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("secret has no key %q", key)
+	}
+	return secretString(value), nil
+}
+
+func secretString(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
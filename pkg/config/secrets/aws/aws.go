@@ -0,0 +1,72 @@
+// pkg/config/secrets/aws/aws.go
+
+// Package aws resolves "aws:secret-id#key" secret references from AWS Secrets Manager,
+// using the AWS SDK and its default credential chain (environment, shared config and
+// SSO profiles, web identity/IRSA, ECS task and EC2 instance roles). Import it for its
+// side effects to register the "aws" scheme:
+//
+//	import _ "github.com/chmenegatti/typegorm/pkg/config/secrets/aws"
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/chmenegatti/typegorm/pkg/config"
+)
+
+func init() {
+	config.RegisterSecretProvider("aws", &Provider{})
+}
+
+// Provider reads secrets from AWS Secrets Manager. Without #key the whole SecretString
+// is returned; with it, the SecretString is read as a JSON object.
+type Provider struct {
+	// Client is the Secrets Manager client; nil creates one from the default AWS
+	// configuration on first use.
+	Client *secretsmanager.Client
+
+	mu sync.Mutex
+}
+
+// Resolve reads "secret-id#key".
+func (p *Provider) Resolve(ctx context.Context, ref string) (string, error) {
+	client, err := p.client(ctx)
+	if err != nil {
+		return "", err
+	}
+	secretID, key := config.SplitSecretKey(ref)
+	out, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(secretID)})
+	if err != nil {
+		return "", err
+	}
+	if out.SecretString == nil {
+		return "", fmt.Errorf("secret %s has no SecretString (binary secrets are not supported)", secretID)
+	}
+	if key == "" {
+		return *out.SecretString, nil
+	}
+	var data map[string]any
+	if err := json.Unmarshal([]byte(*out.SecretString), &data); err != nil {
+		return "", fmt.Errorf("secret %s is not a JSON object, cannot read #%s", secretID, key)
+	}
+	return config.PickSecretKey(data, key)
+}
+
+func (p *Provider) client(ctx context.Context) (*secretsmanager.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.Client == nil {
+		cfg, err := awsconfig.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("loading AWS configuration: %w", err)
+		}
+		p.Client = secretsmanager.NewFromConfig(cfg)
+	}
+	return p.Client, nil
+}
@@ -0,0 +1,74 @@
+// pkg/config/secrets/vault/vault.go
+
+// Package vault resolves "vault:mount/path#key" secret references from the KV secrets
+// engine of HashiCorp Vault, using the official Vault client. Import it for its side
+// effects to register the "vault" scheme:
+//
+//	import _ "github.com/chmenegatti/typegorm/pkg/config/secrets/vault"
+//
+// The default client is configured from the environment (VAULT_ADDR, VAULT_TOKEN,
+// VAULT_NAMESPACE, VAULT_CACERT, ...). To log in with another auth method (Kubernetes,
+// AppRole, ...), register a Provider with an authenticated client instead.
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+	"github.com/hashicorp/vault/api"
+)
+
+func init() {
+	config.RegisterSecretProvider("vault", &Provider{})
+}
+
+// Provider reads secrets from Vault's KV engine, version 2 first and version 1 when the
+// mount has no such secret.
+type Provider struct {
+	// Client is the Vault client; nil creates one from the environment on first use.
+	Client *api.Client
+
+	mu sync.Mutex
+}
+
+// Resolve reads "mount/path#key"; without #key the secret must hold a single value.
+func (p *Provider) Resolve(ctx context.Context, ref string) (string, error) {
+	client, err := p.client()
+	if err != nil {
+		return "", err
+	}
+	path, key := config.SplitSecretKey(ref)
+	mount, secretPath, ok := strings.Cut(strings.Trim(path, "/"), "/")
+	if !ok {
+		return "", fmt.Errorf("expected vault:mount/path#key, got %q", ref)
+	}
+
+	secret, err := client.KVv2(mount).Get(ctx, secretPath)
+	if errors.Is(err, api.ErrSecretNotFound) {
+		secret, err = client.KVv1(mount).Get(ctx, secretPath)
+	}
+	if err != nil {
+		return "", err
+	}
+	return config.PickSecretKey(secret.Data, key)
+}
+
+func (p *Provider) client() (*api.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.Client == nil {
+		client, err := api.NewClient(api.DefaultConfig())
+		if err != nil {
+			return nil, fmt.Errorf("creating vault client: %w", err)
+		}
+		if client.Token() == "" {
+			return nil, fmt.Errorf("VAULT_TOKEN must be set, or register a vault Provider with an authenticated client")
+		}
+		p.Client = client
+	}
+	return p.Client, nil
+}
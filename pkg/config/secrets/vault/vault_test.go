@@ -0,0 +1,43 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider_ReadsKVv2AndFallsBackToKVv1(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "root-token", r.Header.Get("X-Vault-Token"))
+		switch r.URL.Path {
+		case "/v1/kv/data/app/db":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"data": map[string]any{"password": "v-pass", "user": "app"}}})
+		case "/v1/legacy/app/db":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"password": "v1-pass"}})
+		default: // Vault's answer for a missing secret
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer server.Close()
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "root-token")
+	config.RegisterSecretProvider("vault", &Provider{})
+
+	value, err := config.ResolveSecret(context.Background(), "vault:kv/app/db#password")
+	require.NoError(t, err)
+	assert.Equal(t, "v-pass", value)
+
+	value, err = config.ResolveSecret(context.Background(), "vault:legacy/app/db")
+	require.NoError(t, err)
+	assert.Equal(t, "v1-pass", value)
+
+	_, err = config.ResolveSecret(context.Background(), "vault:kv/app/db")
+	assert.ErrorContains(t, err, "name one with #key")
+}
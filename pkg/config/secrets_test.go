@@ -0,0 +1,64 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSecret_BuiltInAndCustomProviders(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("TG_TEST_DB_PASSWORD", "s3cret")
+	value, err := ResolveSecret(ctx, "env:TG_TEST_DB_PASSWORD")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", value)
+
+	path := filepath.Join(t.TempDir(), "db-password")
+	require.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0o600))
+	value, err = ResolveSecret(ctx, "file:"+path)
+	require.NoError(t, err)
+	assert.Equal(t, "from-file", value)
+
+	RegisterSecretProvider("static", SecretProviderFunc(func(ctx context.Context, ref string) (string, error) {
+		return "static-" + ref, nil
+	}))
+	value, err = ResolveSecret(ctx, "static:db")
+	require.NoError(t, err)
+	assert.Equal(t, "static-db", value)
+
+	_, err = ResolveSecret(ctx, "env:TG_TEST_MISSING")
+	assert.ErrorContains(t, err, "TG_TEST_MISSING is not set")
+	_, err = ResolveSecret(ctx, "gcp:db")
+	assert.ErrorContains(t, err, `unknown secret provider "gcp"`)
+}
+
+func TestResolveSecret_OptionalProvidersNeedTheirPackage(t *testing.T) {
+	_, err := ResolveSecret(context.Background(), "vault:kv/app/db#password")
+	assert.ErrorContains(t, err, `import _ "github.com/chmenegatti/typegorm/pkg/config/secrets/vault"`)
+}
+
+func TestLoadConfig_ResolvesSecretReferences(t *testing.T) {
+	configFile := createTempConfigFile(t, `
+database:
+  dialect: "mysql"
+  dsnFrom: "env:TG_TEST_DSN"
+  passwordFrom: "env:TG_TEST_PASSWORD"
+`)
+	t.Setenv("TYPEGORM_ENV", "")
+	t.Setenv("TYPEGORM_DATABASE_DSN", "")
+	t.Setenv("TG_TEST_DSN", "app@tcp(db:3306)/app")
+	t.Setenv("TG_TEST_PASSWORD", "rotated")
+
+	cfg, err := LoadConfig(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, "app@tcp(db:3306)/app", cfg.Database.DSN)
+	assert.Equal(t, "rotated", cfg.Database.Password)
+
+	os.Unsetenv("TG_TEST_PASSWORD")
+	_, err = LoadConfig(configFile)
+	assert.ErrorContains(t, err, "database.passwordFrom")
+}
@@ -0,0 +1,24 @@
+// pkg/dialects/common/conn.go
+package common
+
+import "context"
+
+// ConnPinner is an optional capability of a DataSource: a dedicated connection of the
+// pool, for statements that share session state (SET, USE, temporary tables) without
+// the implicit transaction of a Tx.
+type ConnPinner interface {
+	// PinConn reserves a connection until its Close or Discard.
+	PinConn(ctx context.Context) (Conn, error)
+}
+
+// Conn is a connection reserved by ConnPinner.PinConn.
+type Conn interface {
+	Exec(ctx context.Context, query string, args ...any) (Result, error)
+	QueryRow(ctx context.Context, query string, args ...any) RowScanner
+	Query(ctx context.Context, query string, args ...any) (Rows, error)
+	// Close returns the connection to the pool.
+	Close() error
+	// Discard closes the connection for good instead, when its session state could
+	// not be restored (e.g. a session variable left changed).
+	Discard() error
+}
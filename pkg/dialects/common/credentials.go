@@ -0,0 +1,13 @@
+// pkg/dialects/common/credentials.go
+package common
+
+import "github.com/chmenegatti/typegorm/pkg/config"
+
+// CredentialRefresher is an optional capability of a DataSource: rotated credentials
+// (a new DSN or password, see config.DatabaseConfig.ResolveCredentials) are used by the
+// connections opened from then on, without reconnecting the pool. Idle connections
+// opened with the old credentials are closed; busy ones are recycled by
+// connMaxLifetime.
+type CredentialRefresher interface {
+	RefreshCredentials(cfg config.DatabaseConfig) error
+}
@@ -0,0 +1,13 @@
+// pkg/dialects/common/failover.go
+package common
+
+import "context"
+
+// PrimaryFailoverer is an optional capability of a DataSource connected to several
+// primary candidates (config.DatabaseConfig.Hosts, or a DNS name following the
+// primary): IsPrimaryLost classifies the errors meaning the primary is gone or was
+// demoted, and Failover reconnects to the new primary, returning its address.
+type PrimaryFailoverer interface {
+	IsPrimaryLost(err error) bool
+	Failover(ctx context.Context) (string, error)
+}
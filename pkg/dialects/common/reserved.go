@@ -0,0 +1,88 @@
+// pkg/dialects/common/reserved.go
+package common
+
+import "strings"
+
+// sqlReserved are keywords reserved by all supported dialects (SQL standard core).
+var sqlReserved = []string{
+	"all", "and", "any", "as", "asc", "between", "both", "by", "case", "check", "collate",
+	"column", "constraint", "create", "cross", "current_date", "current_time",
+	"current_timestamp", "current_user", "default", "delete", "desc", "distinct", "drop",
+	"else", "end", "except", "exists", "false", "fetch", "for", "foreign", "from", "grant",
+	"group", "having", "in", "inner", "insert", "intersect", "into", "is", "join",
+	"leading", "left", "like", "limit", "natural", "not", "null", "on", "or", "order",
+	"outer", "primary", "references", "right", "select", "set", "table", "then", "to",
+	"trailing", "true", "union", "unique", "update", "using", "values", "when", "where",
+	"with",
+}
+
+// dialectReserved are the additional reserved words of each dialect.
+var dialectReserved = map[string][]string{
+	"mysql": {
+		"add", "alter", "analyze", "before", "change", "condition", "database", "databases",
+		"dec", "decimal", "delayed", "describe", "div", "double", "dual", "explain", "float",
+		"force", "function", "groups", "ignore", "index", "int", "integer", "interval",
+		"key", "keys", "kill", "lead", "lag", "load", "lock", "long", "match", "mod",
+		"modifies", "option", "optimize", "out", "partition", "range", "rank", "read",
+		"release", "rename", "repeat", "replace", "require", "restrict", "return", "revoke",
+		"row", "rows", "schema", "separator", "show", "signal", "spatial", "sql", "system",
+		"trigger", "undo", "unlock", "unsigned", "usage", "use", "varchar", "while",
+		"window", "write", "xor", "year_month", "zerofill",
+	},
+	"postgres": {
+		"analyse", "analyze", "array", "asymmetric", "authorization", "binary", "cast",
+		"concurrently", "deferrable", "do", "freeze", "full", "ilike", "initially",
+		"isnull", "lateral", "localtime", "localtimestamp", "notnull", "offset", "only",
+		"overlaps", "placing", "returning", "session_user", "similar", "some", "symmetric",
+		"tablesample", "user", "variadic", "verbose", "window",
+	},
+	"sqlite": {
+		"abort", "action", "add", "after", "alter", "analyze", "attach", "autoincrement",
+		"before", "begin", "cascade", "cast", "commit", "conflict", "database", "deferrable",
+		"deferred", "detach", "each", "escape", "exclusive", "explain", "fail", "full",
+		"glob", "if", "ignore", "immediate", "index", "indexed", "initially", "instead",
+		"isnull", "key", "match", "no", "notnull", "of", "offset", "plan", "pragma",
+		"query", "raise", "recursive", "regexp", "reindex", "release", "rename", "replace",
+		"restrict", "rollback", "row", "savepoint", "temp", "temporary", "transaction",
+		"trigger", "vacuum", "view", "virtual",
+	},
+}
+
+var reservedWords = buildReservedWords()
+
+func buildReservedWords() map[string]map[string]bool {
+	sets := make(map[string]map[string]bool, len(dialectReserved))
+	for dialect, extra := range dialectReserved {
+		set := make(map[string]bool, len(sqlReserved)+len(extra))
+		for _, word := range sqlReserved {
+			set[word] = true
+		}
+		for _, word := range extra {
+			set[word] = true
+		}
+		sets[dialect] = set
+	}
+	return sets
+}
+
+// IsReservedWord reports whether word (case-insensitive) is a reserved keyword of the
+// dialect, so it must be quoted to be used as a table or column name (e.g. "order",
+// "group", "user"). Unknown dialects only check the SQL standard core keywords.
+func IsReservedWord(dialectName, word string) bool {
+	word = strings.ToLower(word)
+	switch dialectName {
+	case "postgresql", "pgx":
+		dialectName = "postgres"
+	case "sqlite3":
+		dialectName = "sqlite"
+	}
+	if set, ok := reservedWords[dialectName]; ok {
+		return set[word]
+	}
+	for _, reserved := range sqlReserved {
+		if reserved == word {
+			return true
+		}
+	}
+	return false
+}
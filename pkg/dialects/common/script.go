@@ -0,0 +1,158 @@
+// pkg/dialects/common/script.go
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SplitScript splits a multi-statement SQL script into statements that can be executed
+// one by one (drivers run a single statement per Exec unless multi-statements are
+// enabled). Separators inside strings, quoted identifiers, comments and Postgres
+// dollar-quoted bodies are ignored. Dialect specifics:
+//   - MySQL: DELIMITER lines change the separator (e.g. "DELIMITER //" around
+//     procedure bodies) and are removed; '#' starts a comment; backslash escapes in strings.
+//   - SQL Server ("sqlserver", "mssql"): lines containing only GO separate batches,
+//     and ';' does not split a batch.
+//
+// Statements are returned trimmed, without the separator; comment-only statements are dropped.
+func SplitScript(dialect, script string) ([]string, error) {
+	mysql := dialect == "mysql"
+	sqlserver := dialect == "sqlserver" || dialect == "mssql"
+	postgres := dialect == "postgres"
+
+	var (
+		statements []string
+		current    strings.Builder
+		hasCode    bool // current contains more than whitespace and comments
+		delimiter  = ";"
+	)
+	flush := func() {
+		if stmt := strings.TrimSpace(current.String()); stmt != "" && hasCode {
+			statements = append(statements, stmt)
+		}
+		current.Reset()
+		hasCode = false
+	}
+
+	for i := 0; i < len(script); {
+		if i == 0 || script[i-1] == '\n' {
+			lineEnd := strings.IndexByte(script[i:], '\n')
+			if lineEnd < 0 {
+				lineEnd = len(script)
+			} else {
+				lineEnd += i
+			}
+			line := strings.TrimSpace(script[i:lineEnd])
+			if mysql && len(line) >= 9 && strings.EqualFold(line[:9], "DELIMITER") && (len(line) == 9 || line[9] == ' ' || line[9] == '\t') {
+				flush()
+				delimiter = strings.TrimSpace(line[9:])
+				if delimiter == "" {
+					return nil, fmt.Errorf("script: DELIMITER without a delimiter")
+				}
+				i = lineEnd
+				continue
+			}
+			if sqlserver && strings.EqualFold(line, "GO") {
+				flush()
+				i = lineEnd
+				continue
+			}
+		}
+
+		c := script[i]
+		var next byte
+		if i+1 < len(script) {
+			next = script[i+1]
+		}
+		switch {
+		case c == '\'' || c == '"' || (c == '`' && mysql) || (c == '[' && sqlserver):
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			end, err := skipQuoted(script, i, closing, mysql && c != '`')
+			if err != nil {
+				return nil, err
+			}
+			current.WriteString(script[i:end])
+			hasCode = true
+			i = end
+			continue
+		case c == '-' && next == '-' && (!mysql || i+2 >= len(script) || script[i+2] <= ' '), c == '#' && mysql:
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				end = len(script) - i
+			}
+			current.WriteString(script[i : i+end])
+			i += end
+			continue
+		case c == '/' && next == '*':
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("script: unterminated block comment at offset %d", i)
+			}
+			current.WriteString(script[i : i+2+end+2])
+			i += 2 + end + 2
+			continue
+		case c == '$' && postgres:
+			if tag := dollarTag(script[i:]); tag != "" {
+				end := strings.Index(script[i+len(tag):], tag)
+				if end < 0 {
+					return nil, fmt.Errorf("script: unterminated %s quoted string at offset %d", tag, i)
+				}
+				stop := i + len(tag) + end + len(tag)
+				current.WriteString(script[i:stop])
+				hasCode = true
+				i = stop
+				continue
+			}
+		case !sqlserver && strings.HasPrefix(script[i:], delimiter):
+			flush()
+			i += len(delimiter)
+			continue
+		}
+		current.WriteByte(c)
+		if c > ' ' {
+			hasCode = true
+		}
+		i++
+	}
+	flush()
+	return statements, nil
+}
+
+// skipQuoted returns the offset just past the quoted section starting at start.
+// A doubled closing character is an escaped one; with backslash, \x escapes as well.
+func skipQuoted(script string, start int, closing byte, backslash bool) (int, error) {
+	for i := start + 1; i < len(script); i++ {
+		switch script[i] {
+		case '\\':
+			if backslash {
+				i++
+			}
+		case closing:
+			if i+1 < len(script) && script[i+1] == closing {
+				i++
+				continue
+			}
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("script: unterminated %c quoted section at offset %d", script[start], start)
+}
+
+// dollarTag returns the Postgres dollar-quote tag ($$ or $name$) at the start of s, or "".
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '$':
+			return s[:i+1]
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (i > 1 && c >= '0' && c <= '9'):
+		default:
+			return "" // $1 placeholders and stray dollars
+		}
+	}
+	return ""
+}
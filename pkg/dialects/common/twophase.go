@@ -0,0 +1,41 @@
+// pkg/dialects/common/twophase.go
+package common
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// TwoPhaseCommitter is an optional capability of a DataSource: distributed transactions
+// prepared here and committed later by an external transaction manager
+// (MySQL XA, Postgres PREPARE TRANSACTION).
+type TwoPhaseCommitter interface {
+	// BeginTwoPhase starts a transaction identified by the global transaction ID xid.
+	BeginTwoPhase(ctx context.Context, xid string) (PreparableTx, error)
+	// CommitPrepared commits a previously prepared transaction (from any connection).
+	CommitPrepared(ctx context.Context, xid string) error
+	// RollbackPrepared aborts a previously prepared transaction (from any connection).
+	RollbackPrepared(ctx context.Context, xid string) error
+}
+
+// PreparableTx is a transaction that can be prepared for a two-phase commit.
+// Commit and Rollback still work as a regular (one-phase) transaction.
+type PreparableTx interface {
+	Tx
+	// Prepare ends the transaction in the prepared state; it must then be resolved
+	// with CommitPrepared or RollbackPrepared.
+	Prepare(ctx context.Context) error
+}
+
+var xidPattern = regexp.MustCompile(`^[A-Za-z0-9_.:\-]{1,64}$`)
+
+// ValidateXID checks a global transaction ID. XIDs are embedded as literals in the
+// XA / PREPARE TRANSACTION statements (they cannot be bound), so only a safe
+// character set is accepted.
+func ValidateXID(xid string) error {
+	if !xidPattern.MatchString(xid) {
+		return fmt.Errorf("invalid transaction id %q: use 1-64 characters from [A-Za-z0-9_.:-]", xid)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+// pkg/dialects/common/upsert.go
+package common
+
+// Upserter is an optional capability of a Dialect: the clause turning an INSERT into an
+// upsert. Dialects without it get the standard ON CONFLICT (...) DO UPDATE SET
+// col = EXCLUDED.col (Postgres, SQLite).
+type Upserter interface {
+	// UpsertClause returns the clause appended to "INSERT INTO t (...) VALUES (...)" so
+	// that a row conflicting on conflictColumns updates updateColumns with the values
+	// of the new row instead. Column names are unquoted.
+	UpsertClause(conflictColumns, updateColumns []string) string
+}
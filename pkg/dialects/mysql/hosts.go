@@ -0,0 +1,300 @@
+// pkg/dialects/mysql/hosts.go
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+)
+
+// --- Multi-Host Connections ---
+
+// dbHost is one server new connections may be opened on.
+type dbHost struct {
+	network string // "tcp" or "unix"
+	addr    string // "host:port" or socket path
+}
+
+// addrListRe matches a DSN address list, e.g. "@tcp(db1:3306,db2:3306)/".
+var addrListRe = regexp.MustCompile(`@(tcp6?)\(([^)]*,[^)]*)\)`)
+
+// splitDSNHosts removes the address list of a DSN, which the driver cannot parse,
+// keeping its first address; it returns the listed hosts (none without a list).
+func splitDSNHosts(dsn string) (string, []string) {
+	match := addrListRe.FindStringSubmatchIndex(dsn)
+	if match == nil {
+		return dsn, nil
+	}
+	var hosts []string
+	for _, host := range strings.Split(dsn[match[4]:match[5]], ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	if len(hosts) == 0 {
+		return dsn, nil
+	}
+	return dsn[:match[0]] + "@" + dsn[match[2]:match[3]] + "(" + hosts[0] + ")" + dsn[match[1]:], hosts
+}
+
+// parseHost reads a host entry: a socket path ("/var/run/mysqld/mysqld.sock" or
+// "unix:/path"), or "host[:port]" (port 3306 by default).
+func parseHost(entry string) dbHost {
+	entry = strings.TrimSpace(entry)
+	if path, ok := strings.CutPrefix(entry, "unix:"); ok {
+		return dbHost{network: "unix", addr: path}
+	}
+	if strings.HasPrefix(entry, "/") {
+		return dbHost{network: "unix", addr: entry}
+	}
+	if _, _, err := net.SplitHostPort(entry); err != nil {
+		entry = net.JoinHostPort(strings.Trim(entry, "[]"), "3306")
+	}
+	return dbHost{network: "tcp", addr: entry}
+}
+
+// hostCandidates returns the servers to connect to, in order: database.socket, then
+// database.hosts, then the address list of the DSN. Nil keeps the DSN's address.
+func hostCandidates(cfg config.DatabaseConfig, dsnHosts []string) []dbHost {
+	var hosts []dbHost
+	if cfg.Socket != "" {
+		hosts = append(hosts, dbHost{network: "unix", addr: cfg.Socket})
+	}
+	entries := cfg.Hosts
+	if len(entries) == 0 {
+		entries = dsnHosts
+	}
+	for _, entry := range entries {
+		hosts = append(hosts, parseHost(entry))
+	}
+	return hosts
+}
+
+// sessionRole normalizes database.targetSessionAttrs: "" (any server), "read-write"
+// or "read-only".
+func sessionRole(attrs string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(attrs)) {
+	case "", "any":
+		return "", nil
+	case "read-write", "primary":
+		return "read-write", nil
+	case "read-only", "standby":
+		return "read-only", nil
+	default:
+		return "", fmt.Errorf("invalid targetSessionAttrs %q (expected any, read-write, primary, read-only or standby)", attrs)
+	}
+}
+
+// hostConnector is the connector of one host.
+type hostConnector struct {
+	host      dbHost
+	connector driver.Connector
+}
+
+// failoverConnector opens connections on the first host that accepts them and has
+// the requested role, trying the hosts in order; each attempt is bounded by the
+// connect timeout so that an unreachable server does not delay the next one.
+type failoverConnector struct {
+	hosts   []hostConnector
+	role    string        // "", "read-write" or "read-only"
+	timeout time.Duration // Per host; zero: the caller's context only
+
+	generation atomic.Uint64 // Bumped by failover: connections of older generations are discarded
+	current    atomic.Value  // Address of the host of the last connection opened
+}
+
+// newFailoverConnector returns a connector trying hosts with the settings of driverCfg.
+func newFailoverConnector(driverCfg *mysqldriver.Config, hosts []dbHost, role string, timeout time.Duration) (*failoverConnector, error) {
+	dsnHost, _, _ := net.SplitHostPort(driverCfg.Addr)
+	c := &failoverConnector{role: role, timeout: timeout}
+	for _, host := range hosts {
+		hostCfg := driverCfg.Clone()
+		hostCfg.Net, hostCfg.Addr = host.network, host.addr
+		if hostCfg.TLS != nil && hostCfg.TLS.ServerName == dsnHost {
+			// Verify each server against its own name, not the DSN's
+			hostCfg.TLS.ServerName = ""
+			if name, _, err := net.SplitHostPort(host.addr); err == nil && host.network == "tcp" {
+				hostCfg.TLS.ServerName = name
+			}
+		}
+		connector, err := mysqldriver.NewConnector(hostCfg)
+		if err != nil {
+			return nil, fmt.Errorf("mysql host %s: %w", host.addr, err)
+		}
+		c.hosts = append(c.hosts, hostConnector{host: host, connector: connector})
+	}
+	return c, nil
+}
+
+// Connect implements driver.Connector.
+func (c *failoverConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	var errs []error
+	generation := c.generation.Load()
+	for _, h := range c.hosts {
+		conn, err := c.connect(ctx, h)
+		if err == nil {
+			c.current.Store(h.host.addr)
+			return &failoverConn{Conn: conn, connector: c, generation: generation}, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", h.host.addr, err))
+	}
+	return nil, fmt.Errorf("no usable mysql host: %w", errors.Join(errs...))
+}
+
+func (c *failoverConnector) connect(ctx context.Context, h hostConnector) (driver.Conn, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	conn, err := h.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c.role == "" {
+		return conn, nil
+	}
+	readOnly, err := serverReadOnly(ctx, conn)
+	if err == nil && readOnly != (c.role == "read-only") {
+		err = fmt.Errorf("server is not %s", c.role)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// Driver implements driver.Connector.
+func (c *failoverConnector) Driver() driver.Driver {
+	return c.hosts[0].connector.Driver()
+}
+
+// failover discards the connections opened so far: the next ones look for a server
+// with the requested role again, resolving the host names anew.
+func (c *failoverConnector) failover() {
+	c.generation.Add(1)
+}
+
+// currentHost returns the address of the host of the last connection opened.
+func (c *failoverConnector) currentHost() string {
+	addr, _ := c.current.Load().(string)
+	return addr
+}
+
+// failoverConn is a connection of the failoverConnector. After a failover it reports
+// itself invalid, so that database/sql closes it instead of reusing it.
+type failoverConn struct {
+	driver.Conn
+	connector  *failoverConnector
+	generation uint64
+}
+
+func (c *failoverConn) stale() bool {
+	return c.generation != c.connector.generation.Load()
+}
+
+// IsValid implements driver.Validator.
+func (c *failoverConn) IsValid() bool {
+	if c.stale() {
+		return false
+	}
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+// ResetSession implements driver.SessionResetter.
+func (c *failoverConn) ResetSession(ctx context.Context) error {
+	if c.stale() {
+		return driver.ErrBadConn
+	}
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+// The methods below forward the optional interfaces of the driver's connection.
+
+func (c *failoverConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *failoverConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin() // Drivers without BeginTx
+}
+
+func (c *failoverConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if e, ok := c.Conn.(driver.ExecerContext); ok {
+		return e.ExecContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *failoverConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if q, ok := c.Conn.(driver.QueryerContext); ok {
+		return q.QueryContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *failoverConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *failoverConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// serverReadOnly reports whether the server of conn refuses writes (@@global.read_only,
+// set on replicas and on demoted primaries).
+func serverReadOnly(ctx context.Context, conn driver.Conn) (bool, error) {
+	queryer, ok := conn.(driver.QueryerContext)
+	if !ok {
+		return false, fmt.Errorf("connection cannot check the server role")
+	}
+	rows, err := queryer.QueryContext(ctx, "SELECT @@global.read_only", nil)
+	if err != nil {
+		return false, fmt.Errorf("checking the server role: %w", err)
+	}
+	defer rows.Close()
+	dest := make([]driver.Value, 1)
+	if err := rows.Next(dest); err != nil {
+		return false, fmt.Errorf("checking the server role: %w", err)
+	}
+	switch v := dest[0].(type) {
+	case int64:
+		return v != 0, nil
+	case []byte:
+		return string(v) != "0", nil
+	default:
+		return fmt.Sprint(v) != "0", nil
+	}
+}
@@ -0,0 +1,143 @@
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+)
+
+func TestHostCandidates(t *testing.T) {
+	dsn, hosts := splitDSNHosts("app:pw@tcp(db1:3306, db2)/app?parseTime=true")
+	assert.Equal(t, "app:pw@tcp(db1:3306)/app?parseTime=true", dsn)
+	assert.Equal(t, []string{"db1:3306", "db2"}, hosts)
+
+	dsn, hosts = splitDSNHosts("app@tcp(db1:3306)/app")
+	assert.Equal(t, "app@tcp(db1:3306)/app", dsn)
+	assert.Nil(t, hosts)
+
+	candidates := hostCandidates(config.DatabaseConfig{Socket: "/run/mysqld.sock"}, hosts)
+	assert.Equal(t, []dbHost{{network: "unix", addr: "/run/mysqld.sock"}}, candidates)
+
+	candidates = hostCandidates(config.DatabaseConfig{Hosts: []string{"db3", "unix:/tmp/my.sock", "[::1]:3307"}}, []string{"ignored"})
+	assert.Equal(t, []dbHost{
+		{network: "tcp", addr: "db3:3306"},
+		{network: "unix", addr: "/tmp/my.sock"},
+		{network: "tcp", addr: "[::1]:3307"},
+	}, candidates)
+
+	role, err := sessionRole("Primary")
+	require.NoError(t, err)
+	assert.Equal(t, "read-write", role)
+	_, err = sessionRole("prefer-standby")
+	assert.ErrorContains(t, err, "invalid targetSessionAttrs")
+}
+
+// stubConnector fails, or returns connections reporting read_only.
+type stubConnector struct {
+	err      error
+	readOnly bool
+	calls    int
+}
+
+func (c *stubConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &stubConn{readOnly: c.readOnly}, nil
+}
+func (c *stubConnector) Driver() driver.Driver { return nil }
+
+type stubConn struct {
+	driver.Conn
+	readOnly bool
+	closed   bool
+}
+
+func (c *stubConn) Close() error { c.closed = true; return nil }
+func (c *stubConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	value := int64(0)
+	if c.readOnly {
+		value = 1
+	}
+	return &stubRows{value: value}, nil
+}
+
+type stubRows struct {
+	value int64
+	done  bool
+}
+
+func (r *stubRows) Columns() []string { return []string{"@@global.read_only"} }
+func (r *stubRows) Close() error      { return nil }
+func (r *stubRows) Next(dest []driver.Value) error {
+	if r.done {
+		return errors.New("EOF")
+	}
+	r.done, dest[0] = true, r.value
+	return nil
+}
+
+func TestFailoverConnector(t *testing.T) {
+	down := &stubConnector{err: errors.New("connection refused")}
+	replica := &stubConnector{readOnly: true}
+	primary := &stubConnector{}
+	c := &failoverConnector{role: "read-write", timeout: time.Second, hosts: []hostConnector{
+		{host: dbHost{addr: "db1:3306"}, connector: down},
+		{host: dbHost{addr: "db2:3306"}, connector: replica},
+		{host: dbHost{addr: "db3:3306"}, connector: primary},
+	}}
+
+	conn, err := c.Connect(context.Background())
+	require.NoError(t, err)
+	assert.False(t, conn.(*failoverConn).Conn.(*stubConn).readOnly, "The read-write server is chosen")
+	assert.Equal(t, []int{1, 1, 1}, []int{down.calls, replica.calls, primary.calls})
+
+	c.role = ""
+	conn, err = c.Connect(context.Background())
+	require.NoError(t, err)
+	assert.True(t, conn.(*failoverConn).Conn.(*stubConn).readOnly, "Any server: the first reachable one")
+
+	assert.Equal(t, "db2:3306", c.currentHost())
+	assert.True(t, conn.(*failoverConn).IsValid())
+	c.failover()
+	assert.False(t, conn.(*failoverConn).IsValid(), "Connections opened before a failover are discarded")
+
+	c.hosts = c.hosts[:1]
+	_, err = c.Connect(context.Background())
+	assert.ErrorContains(t, err, "no usable mysql host: db1:3306: connection refused")
+}
+
+func TestConnect_AuthTokenRequiresTLS(t *testing.T) {
+	for _, dsn := range []string{"app@tcp(db:3306)/app", "app@tcp(db:3306)/app?tls=preferred"} {
+		ds := &mysqlDataSource{dialect: &mysqlDialect{}}
+		err := ds.Connect(config.DatabaseConfig{Dialect: "mysql", DSN: dsn, AuthToken: "rds-iam"})
+		assert.ErrorContains(t, err, "database.authToken requires TLS", dsn)
+	}
+}
+
+func TestApplyCredentials_SignsTokensPerHost(t *testing.T) {
+	var hosts []string
+	config.RegisterAuthTokenProvider("test-per-host", config.AuthTokenProviderFunc(func(ctx context.Context, target config.AuthTarget) (string, time.Time, error) {
+		hosts = append(hosts, target.Host)
+		return "token-for-" + target.Host, time.Now().Add(time.Hour), nil
+	}))
+	ds := &mysqlDataSource{authToken: "test-per-host", tokens: map[string]*config.AuthTokenSource{}}
+
+	for _, addr := range []string{"db1:3306", "db2:3306", "db1:3306"} {
+		cfg := &mysqldriver.Config{Net: "tcp", Addr: addr, User: "app"}
+		require.NoError(t, ds.applyCredentials(context.Background(), cfg))
+		host, _, _ := strings.Cut(addr, ":")
+		assert.Equal(t, "token-for-"+host, cfg.Passwd)
+	}
+	assert.Equal(t, []string{"db1", "db2"}, hosts, "one token source per server, reused")
+}
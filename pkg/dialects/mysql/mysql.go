@@ -4,13 +4,17 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"net"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql" // Register driver
+	mysqldriver "github.com/go-sql-driver/mysql" // Also registers the driver
 
 	"github.com/chmenegatti/typegorm/pkg/config"
 	"github.com/chmenegatti/typegorm/pkg/dialects"
@@ -132,6 +136,8 @@ func (d mysqlDialect) GetDataType(field *schema.Field) (string, error) {
 		// Check if the underlying type (after pointer dereference) is time.Time
 		if underlyingType == timeType {
 			baseType = "DATETIME(6)"
+		} else if typer, ok := reflect.New(underlyingType).Interface().(schema.DataTyper); ok {
+			baseType = typer.DataType(d.Name()) // Value types choosing their column type (e.g., JSON)
 		} else {
 			// TODO: Handle sql.Null* types (e.g., check underlyingType.PkgPath() and .Name())
 			return "", fmt.Errorf("unsupported struct type for mysql: %s", goType.String())
@@ -176,14 +182,14 @@ func (d mysqlDialect) GetDataType(field *schema.Field) (string, error) {
 	isTimeField := (underlyingType == timeType)
 
 	if isTimeField && !hasDefault {
-		if field.GoName == "CreatedAt" {
+		if field.IsAutoCreateTime() {
 			constraints = append(constraints, "DEFAULT CURRENT_TIMESTAMP(6)")
 			// Add NOT NULL if it's not already required and underlying Go type wasn't a pointer
 			if !field.IsRequired && goType.Kind() != reflect.Pointer {
 				constraints = append(constraints, "NOT NULL")
 			}
 			hasDefault = true // Ensure we don't add another default later
-		} else if field.GoName == "UpdatedAt" {
+		} else if field.IsAutoUpdateTime() {
 			// Handle UpdatedAt with ON UPDATE clause
 			// Default to NULL unless required, updates automatically
 			constraints = append(constraints, "DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP(6)")
@@ -219,28 +225,28 @@ func formatDefaultValue(value string) string {
 
 // CreateSchemaMigrationsTableSQL returns the SQL for creating the migrations table in MySQL.
 func (d *mysqlDialect) CreateSchemaMigrationsTableSQL(tableName string) string {
-	// Use the dialect's Quote method for the table name.
+	// Quote the table name (optionally schema-qualified).
 	// Use DATETIME(6) for applied_at to store microsecond precision.
 	return fmt.Sprintf(
 		`CREATE TABLE IF NOT EXISTS %s (
     id VARCHAR(255) NOT NULL PRIMARY KEY COMMENT 'Migration identifier (e.g., timestamp_name)',
     applied_at DATETIME(6) NOT NULL COMMENT 'Timestamp when the migration was applied UTC'
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Tracks applied schema migrations';`,
-		d.Quote(tableName),
+		d.quoteTable(tableName),
 	)
 }
 
 // GetAppliedMigrationsSQL returns the SQL to get applied migration IDs and timestamps from MySQL.
 func (d *mysqlDialect) GetAppliedMigrationsSQL(tableName string) string {
 	// Order by ID ASC for consistent processing.
-	return fmt.Sprintf("SELECT id, applied_at FROM %s ORDER BY id ASC;", d.Quote(tableName))
+	return fmt.Sprintf("SELECT id, applied_at FROM %s ORDER BY id ASC;", d.quoteTable(tableName))
 }
 
 // InsertMigrationSQL returns the SQL for inserting a migration record in MySQL.
 func (d *mysqlDialect) InsertMigrationSQL(tableName string) string {
 	// Use the dialect's BindVar for placeholders. Expects parameters: id (string), applied_at (time.Time)
 	return fmt.Sprintf("INSERT INTO %s (id, applied_at) VALUES (%s, %s);",
-		d.Quote(tableName),
+		d.quoteTable(tableName),
 		d.BindVar(1), // Placeholder for id
 		d.BindVar(2), // Placeholder for applied_at (should be UTC)
 	)
@@ -250,13 +256,31 @@ func (d *mysqlDialect) InsertMigrationSQL(tableName string) string {
 func (d *mysqlDialect) DeleteMigrationSQL(tableName string) string {
 	// Use the dialect's BindVar for the placeholder. Expects parameter: id (string)
 	return fmt.Sprintf("DELETE FROM %s WHERE id = %s;",
-		d.Quote(tableName),
+		d.quoteTable(tableName),
 		d.BindVar(1), // Placeholder for id
 	)
 }
 
+// quoteTable quotes a migration history table given as "table" or "schema.table".
+func (d *mysqlDialect) quoteTable(tableName string) string {
+	if schemaName, table, ok := strings.Cut(tableName, "."); ok {
+		return d.Quote(schemaName) + "." + d.Quote(table)
+	}
+	return d.Quote(tableName)
+}
+
 // --- End of Migration Specific Methods ---
 
+// UpsertClause implements common.Upserter with ON DUPLICATE KEY UPDATE. MySQL checks
+// every unique key of the table, so the conflict columns are not named.
+func (d *mysqlDialect) UpsertClause(conflictColumns, updateColumns []string) string {
+	assignments := make([]string, len(updateColumns))
+	for i, col := range updateColumns {
+		assignments[i] = fmt.Sprintf("%s = VALUES(%s)", d.Quote(col), d.Quote(col))
+	}
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(assignments, ", ")
+}
+
 // --- DataSource Implementation (mysqlDataSource) ---
 // (Keep your existing mysqlDataSource struct and its methods: Connect, Close, Ping, Dialect, BeginTx, Exec, QueryRow, Query)
 // ... (Your existing DataSource code here) ...
@@ -264,6 +288,17 @@ func (d *mysqlDialect) DeleteMigrationSQL(tableName string) string {
 type mysqlDataSource struct {
 	db      *sql.DB        // Connection pool
 	dialect common.Dialect // Instance of mysqlDialect
+
+	credMu   sync.RWMutex // Guards user/password, swapped by RefreshCredentials
+	user     string       // User of new connections
+	password string       // Password of new connections
+	maxIdle  int          // MaxIdleConns, restored after closing stale idle connections
+
+	authToken string                             // Provider of short-lived passwords (database.authToken), "" when unused
+	tokensMu  sync.Mutex                         // Guards tokens
+	tokens    map[string]*config.AuthTokenSource // Token sources by server address: tokens are signed for one host
+
+	failover *failoverConnector // Multi-host connector, nil with a single host and no role to check
 }
 
 // Connect establishes the database connection pool.
@@ -291,20 +326,66 @@ func (ds *mysqlDataSource) Connect(cfg config.DatabaseConfig) error {
 	// Consider adding multiStatements=true if needed for running migration scripts directly,
 	// but be aware of SQL injection risks if not handled carefully.
 
-	db, err := sql.Open(ds.dialect.Name(), dsn)
+	// Open through a connector so that every new connection takes the current
+	// credentials (see RefreshCredentials)
+	dsn, dsnHosts := splitDSNHosts(dsn)
+	driverCfg, err := mysqldriver.ParseDSN(dsn)
+	if err != nil {
+		return fmt.Errorf("invalid mysql DSN: %w", err)
+	}
+	if cfg.ConnectTimeout > 0 {
+		driverCfg.Timeout = cfg.ConnectTimeout
+	}
+	role, err := sessionRole(cfg.TargetSessionAttrs)
+	if err != nil {
+		return err
+	}
+	if cfg.Failover && role == "" {
+		role = "read-write" // Failover looks for the new primary
+	}
+	ds.setCredentials(driverCfg, cfg.Password)
+	hosts := hostCandidates(cfg, dsnHosts)
+	if cfg.AuthToken != "" {
+		// Tokens are sent as cleartext passwords: only over TLS or local sockets
+		if !requiresTLS(driverCfg) && !onlySockets(driverCfg, hosts) {
+			return fmt.Errorf("database.authToken requires TLS (tls=true in the DSN, without allowFallbackToPlaintext): the token is sent as a cleartext password")
+		}
+		tokens, err := newAuthTokenSource(cfg.AuthToken, driverCfg)
+		if err != nil {
+			return err
+		}
+		ds.authToken = cfg.AuthToken
+		ds.tokens = map[string]*config.AuthTokenSource{driverCfg.Addr: tokens}
+		driverCfg.AllowCleartextPasswords = true
+	}
+	if err := driverCfg.Apply(mysqldriver.BeforeConnect(ds.applyCredentials)); err != nil {
+		return fmt.Errorf("failed to configure mysql connector: %w", err)
+	}
+	// Several hosts (or a server role to check): try them in order on every new connection
+	var connector driver.Connector
+	if len(hosts) > 0 || role != "" {
+		if len(hosts) == 0 {
+			hosts = []dbHost{{network: driverCfg.Net, addr: driverCfg.Addr}}
+		}
+		ds.failover, err = newFailoverConnector(driverCfg, hosts, role, cfg.ConnectTimeout)
+		connector = ds.failover
+	} else {
+		connector, err = mysqldriver.NewConnector(driverCfg)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to open mysql connection using driver '%s': %w", ds.dialect.Name(), err)
 	}
+	db := sql.OpenDB(connector)
 
 	// Apply connection pool settings from config (ensure Pool struct exists in config.DatabaseConfig)
 	// Check if Pool is non-nil before accessing members if it's a pointer
 	// Assuming Pool is a struct value based on previous context:
-	if cfg.Pool.MaxIdleConns > 0 {
-		db.SetMaxIdleConns(cfg.Pool.MaxIdleConns)
-	} else {
+	ds.maxIdle = cfg.Pool.MaxIdleConns
+	if ds.maxIdle <= 0 {
 		// Set a reasonable default if not specified? e.g., 2
-		db.SetMaxIdleConns(2)
+		ds.maxIdle = 2
 	}
+	db.SetMaxIdleConns(ds.maxIdle)
 	if cfg.Pool.MaxOpenConns > 0 {
 		db.SetMaxOpenConns(cfg.Pool.MaxOpenConns)
 	}
@@ -328,6 +409,149 @@ func (ds *mysqlDataSource) Connect(cfg config.DatabaseConfig) error {
 	return nil
 }
 
+// setCredentials records the user and password of new connections: those of the DSN,
+// with password (when set) replacing the DSN's.
+func (ds *mysqlDataSource) setCredentials(driverCfg *mysqldriver.Config, password string) {
+	if password != "" {
+		driverCfg.Passwd = password
+	}
+	ds.credMu.Lock()
+	defer ds.credMu.Unlock()
+	ds.user, ds.password = driverCfg.User, driverCfg.Passwd
+}
+
+// newAuthTokenSource returns the token source of the database addressed by driverCfg.
+func newAuthTokenSource(name string, driverCfg *mysqldriver.Config) (*config.AuthTokenSource, error) {
+	target := config.AuthTarget{Host: driverCfg.Addr, Port: 3306, User: driverCfg.User}
+	if host, port, err := net.SplitHostPort(driverCfg.Addr); err == nil {
+		target.Host = host
+		if n, err := strconv.Atoi(port); err == nil {
+			target.Port = n
+		}
+	}
+	tokens, err := config.NewAuthTokenSource(name, target)
+	if err != nil {
+		return nil, fmt.Errorf("database.authToken: %w", err)
+	}
+	return tokens, nil
+}
+
+// requiresTLS reports whether the connections of driverCfg are always encrypted.
+func requiresTLS(driverCfg *mysqldriver.Config) bool {
+	return driverCfg.TLS != nil && !driverCfg.AllowFallbackToPlaintext
+}
+
+// onlySockets reports whether every connection goes through a unix socket.
+func onlySockets(driverCfg *mysqldriver.Config, hosts []dbHost) bool {
+	if len(hosts) == 0 {
+		return driverCfg.Net == "unix"
+	}
+	for _, host := range hosts {
+		if host.network != "unix" {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenSource returns the auth token source of the server driverCfg connects to
+// (with several hosts, the connector sets the address of each).
+func (ds *mysqlDataSource) tokenSource(driverCfg *mysqldriver.Config) (*config.AuthTokenSource, error) {
+	ds.tokensMu.Lock()
+	defer ds.tokensMu.Unlock()
+	if tokens, ok := ds.tokens[driverCfg.Addr]; ok {
+		return tokens, nil
+	}
+	tokens, err := newAuthTokenSource(ds.authToken, driverCfg)
+	if err != nil {
+		return nil, err
+	}
+	ds.tokens[driverCfg.Addr] = tokens
+	return tokens, nil
+}
+
+// applyCredentials is the BeforeConnect hook of the connector. With an auth token
+// provider, the password is the current token of the server; when the token rotates,
+// the idle connections opened with the previous one are closed.
+func (ds *mysqlDataSource) applyCredentials(ctx context.Context, driverCfg *mysqldriver.Config) error {
+	ds.credMu.RLock()
+	driverCfg.User, driverCfg.Passwd = ds.user, ds.password
+	ds.credMu.RUnlock()
+	if ds.authToken == "" {
+		return nil
+	}
+	tokens, err := ds.tokenSource(driverCfg)
+	if err != nil {
+		return err
+	}
+	token, rotated, err := tokens.Token(ctx)
+	if err != nil {
+		return err
+	}
+	driverCfg.Passwd = token
+	if rotated {
+		go ds.dropIdleConns() // Not here: the pool may be waiting on this connection
+	}
+	return nil
+}
+
+// dropIdleConns closes the idle connections, opened with stale credentials.
+func (ds *mysqlDataSource) dropIdleConns() {
+	if db := ds.db; db != nil {
+		db.SetMaxIdleConns(0)
+		db.SetMaxIdleConns(ds.maxIdle)
+	}
+}
+
+// RefreshCredentials implements common.CredentialRefresher: new connections use the
+// user and password of cfg (DSN and Password); idle connections are closed. The rest
+// of the DSN (address, database, parameters) is not reapplied.
+func (ds *mysqlDataSource) RefreshCredentials(cfg config.DatabaseConfig) error {
+	if ds.db == nil {
+		return fmt.Errorf("mysql datasource is not connected")
+	}
+	driverCfg, err := mysqldriver.ParseDSN(cfg.DSN)
+	if err != nil {
+		return fmt.Errorf("invalid mysql DSN: %w", err)
+	}
+	ds.setCredentials(driverCfg, cfg.Password)
+	ds.dropIdleConns()
+	return nil
+}
+
+// Failover implements common.PrimaryFailoverer: the connections opened so far are
+// discarded (busy ones when released) and a connection is opened on the first host
+// accepting writes, resolving the host names again. It returns that host's address.
+func (ds *mysqlDataSource) Failover(ctx context.Context) (string, error) {
+	if ds.db == nil {
+		return "", fmt.Errorf("mysql datasource is not connected")
+	}
+	if ds.failover == nil {
+		return "", fmt.Errorf("failover requires database.failover, database.hosts or database.targetSessionAttrs")
+	}
+	ds.failover.failover()
+	ds.dropIdleConns()
+	if err := ds.db.PingContext(ctx); err != nil {
+		return "", fmt.Errorf("reconnecting to a primary: %w", err)
+	}
+	return ds.failover.currentHost(), nil
+}
+
+// IsPrimaryLost implements common.PrimaryFailoverer: the server refused a statement
+// because it is read-only (demoted primary), or the connection to it failed.
+func (ds *mysqlDataSource) IsPrimaryLost(err error) bool {
+	var myErr *mysqldriver.MySQLError
+	if errors.As(err, &myErr) {
+		switch myErr.Number {
+		case 1290, 1792, 1836: // --read-only, read-only transaction, read-only mode
+			return true
+		}
+		return false
+	}
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysqldriver.ErrInvalidConn) || errors.As(err, &netErr)
+}
+
 func (ds *mysqlDataSource) Close() error {
 	if ds.db == nil {
 		return fmt.Errorf("mysql datasource is not connected")
@@ -455,3 +679,148 @@ func (rs *mysqlRowScanner) Scan(dest ...any) error { return rs.row.Scan(dest...)
 type errorRowScanner struct{ err error }
 
 func (ers *errorRowScanner) Scan(dest ...any) error { return ers.err }
+
+// --- Pinned Connections ---
+
+// PinConn reserves a connection of the pool (common.ConnPinner).
+func (ds *mysqlDataSource) PinConn(ctx context.Context) (common.Conn, error) {
+	if ds.db == nil {
+		return nil, fmt.Errorf("mysql datasource is not connected")
+	}
+	conn, err := ds.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mysql connection: %w", err)
+	}
+	return &mysqlConn{conn: conn}, nil
+}
+
+// mysqlConn is a connection reserved by PinConn.
+type mysqlConn struct{ conn *sql.Conn }
+
+func (c *mysqlConn) Exec(ctx context.Context, query string, args ...any) (common.Result, error) {
+	res, err := c.conn.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("mysql exec failed: %w", err)
+	}
+	return &mysqlResult{result: res}, nil
+}
+func (c *mysqlConn) QueryRow(ctx context.Context, query string, args ...any) common.RowScanner {
+	return &mysqlRowScanner{row: c.conn.QueryRowContext(ctx, query, args...)}
+}
+func (c *mysqlConn) Query(ctx context.Context, query string, args ...any) (common.Rows, error) {
+	rows, err := c.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("mysql query failed: %w", err)
+	}
+	return &mysqlRows{rows: rows}, nil
+}
+func (c *mysqlConn) Close() error   { return c.conn.Close() }
+func (c *mysqlConn) Discard() error { return discardConn(c.conn) }
+
+// discardConn closes conn for good: returning driver.ErrBadConn from Raw makes
+// database/sql drop the connection instead of putting it back in the pool.
+func discardConn(conn *sql.Conn) error {
+	if err := conn.Raw(func(any) error { return driver.ErrBadConn }); errors.Is(err, driver.ErrBadConn) {
+		return nil // Closed by Raw
+	}
+	return conn.Close()
+}
+
+// --- Two-Phase Commit (XA) ---
+
+// BeginTwoPhase starts an XA transaction on a dedicated connection.
+func (ds *mysqlDataSource) BeginTwoPhase(ctx context.Context, xid string) (common.PreparableTx, error) {
+	if ds.db == nil {
+		return nil, fmt.Errorf("mysql datasource is not connected")
+	}
+	if err := common.ValidateXID(xid); err != nil {
+		return nil, err
+	}
+	conn, err := ds.db.Conn(ctx) // XA statements must all run on the same session
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mysql connection for XA transaction: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "XA START '"+xid+"'"); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to start XA transaction %s: %w", xid, err)
+	}
+	return &mysqlXATx{conn: conn, xid: xid}, nil
+}
+
+// CommitPrepared commits a prepared XA transaction.
+func (ds *mysqlDataSource) CommitPrepared(ctx context.Context, xid string) error {
+	return ds.resolvePrepared(ctx, "XA COMMIT", xid)
+}
+
+// RollbackPrepared rolls back a prepared XA transaction.
+func (ds *mysqlDataSource) RollbackPrepared(ctx context.Context, xid string) error {
+	return ds.resolvePrepared(ctx, "XA ROLLBACK", xid)
+}
+
+func (ds *mysqlDataSource) resolvePrepared(ctx context.Context, stmt, xid string) error {
+	if ds.db == nil {
+		return fmt.Errorf("mysql datasource is not connected")
+	}
+	if err := common.ValidateXID(xid); err != nil {
+		return err
+	}
+	if _, err := ds.db.ExecContext(ctx, stmt+" '"+xid+"'"); err != nil {
+		return fmt.Errorf("mysql %s failed for %s: %w", stmt, xid, err)
+	}
+	return nil
+}
+
+// mysqlXATx is an XA transaction bound to a dedicated connection.
+type mysqlXATx struct {
+	conn *sql.Conn
+	xid  string
+	done bool
+}
+
+// finish runs the statements ending the XA transaction and releases its connection.
+// When one fails, the transaction may still be open on the session (later statements
+// would fail with XAER_RMFAIL): it is rolled back if possible, and the connection is
+// discarded instead of being returned to the pool.
+func (t *mysqlXATx) finish(ctx context.Context, statements ...string) error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	for _, stmt := range statements {
+		if _, err := t.conn.ExecContext(ctx, stmt); err != nil {
+			cleanup := context.WithoutCancel(ctx)
+			_, _ = t.conn.ExecContext(cleanup, "XA END '"+t.xid+"'") // Fails harmlessly once ended
+			_, _ = t.conn.ExecContext(cleanup, "XA ROLLBACK '"+t.xid+"'")
+			_ = discardConn(t.conn)
+			return fmt.Errorf("mysql %s failed: %w", stmt, err)
+		}
+	}
+	return t.conn.Close()
+}
+
+func (t *mysqlXATx) Prepare(ctx context.Context) error {
+	return t.finish(ctx, "XA END '"+t.xid+"'", "XA PREPARE '"+t.xid+"'")
+}
+func (t *mysqlXATx) Commit() error {
+	return t.finish(context.Background(), "XA END '"+t.xid+"'", "XA COMMIT '"+t.xid+"' ONE PHASE")
+}
+func (t *mysqlXATx) Rollback() error {
+	return t.finish(context.Background(), "XA END '"+t.xid+"'", "XA ROLLBACK '"+t.xid+"'")
+}
+func (t *mysqlXATx) Exec(ctx context.Context, query string, args ...any) (common.Result, error) {
+	res, err := t.conn.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("mysql xa exec failed: %w", err)
+	}
+	return &mysqlResult{result: res}, nil
+}
+func (t *mysqlXATx) QueryRow(ctx context.Context, query string, args ...any) common.RowScanner {
+	return &mysqlRowScanner{row: t.conn.QueryRowContext(ctx, query, args...)}
+}
+func (t *mysqlXATx) Query(ctx context.Context, query string, args ...any) (common.Rows, error) {
+	rows, err := t.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("mysql xa query failed: %w", err)
+	}
+	return &mysqlRows{rows: rows}, nil
+}
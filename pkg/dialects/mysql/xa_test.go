@@ -0,0 +1,75 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// xaConnector returns connections recording their statements; statements starting
+// with failOn fail.
+type xaConnector struct {
+	failOn     string
+	statements []string
+	closed     int
+}
+
+func (c *xaConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &xaConn{connector: c}, nil
+}
+func (c *xaConnector) Driver() driver.Driver { return nil }
+
+type xaConn struct {
+	driver.Conn
+	connector *xaConnector
+}
+
+func (c *xaConn) Close() error { c.connector.closed++; return nil }
+func (c *xaConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.connector.statements = append(c.connector.statements, query)
+	if c.connector.failOn != "" && strings.HasPrefix(query, c.connector.failOn) {
+		return nil, errors.New("XAER_RMFAIL")
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func TestXATx_FailedFinishRollsBackAndDiscardsTheConnection(t *testing.T) {
+	connector := &xaConnector{failOn: "XA PREPARE"}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+	ds := &mysqlDataSource{db: db}
+
+	tx, err := ds.BeginTwoPhase(context.Background(), "order-42")
+	require.NoError(t, err)
+	err = tx.Prepare(context.Background())
+	assert.ErrorContains(t, err, "XA PREPARE 'order-42'")
+	assert.Equal(t, []string{
+		"XA START 'order-42'",
+		"XA END 'order-42'",
+		"XA PREPARE 'order-42'",
+		"XA END 'order-42'",
+		"XA ROLLBACK 'order-42'",
+	}, connector.statements)
+	assert.Equal(t, 1, connector.closed, "the connection is not returned to the pool")
+	assert.Equal(t, 0, db.Stats().OpenConnections)
+	assert.ErrorIs(t, tx.Rollback(), sql.ErrTxDone)
+}
+
+func TestXATx_CommitReleasesTheConnection(t *testing.T) {
+	connector := &xaConnector{}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+	ds := &mysqlDataSource{db: db}
+
+	tx, err := ds.BeginTwoPhase(context.Background(), "order-43")
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	assert.Equal(t, 0, connector.closed)
+	assert.Equal(t, 1, db.Stats().Idle, "the connection is back in the pool")
+}
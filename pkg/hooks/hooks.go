@@ -50,6 +50,13 @@ type AfterDeleter interface {
 
 // --- Find Hooks ---
 
+type AfterScanner interface {
+	// Called right after a row is scanned, before AfterFind, by Find, FindFirst and FindByID.
+	// Use it to populate derived fields (typically tagged `typegorm:"computed"`);
+	// it must not access the database.
+	AfterScan()
+}
+
 type AfterFinder interface {
 	// Called after finding and scanning a single record or each record in a slice.
 	AfterFind(ctx context.Context, db ContextDB) error
@@ -0,0 +1,226 @@
+package migration
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+)
+
+// --- Migration Linting ---
+
+// Lint rules flagging destructive operations.
+const (
+	RuleDropTable        = "drop-table"
+	RuleDropColumn       = "drop-column"
+	RuleTruncate         = "truncate"
+	RuleTypeChange       = "type-change"
+	RuleNotNullNoDefault = "not-null-without-default"
+)
+
+// LintIssue is a destructive operation found in the Up section of a SQL migration.
+type LintIssue struct {
+	Migration  string // Migration file name
+	Statement  string // Offending statement (or ALTER TABLE clause)
+	Rule       string // One of the Rule* constants
+	Message    string // What can go wrong
+	Suggestion string // Safe multi-step alternative
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s: [%s] %s\n    %s\n    Safer: %s", i.Migration, i.Rule, i.Message, i.Statement, i.Suggestion)
+}
+
+// DestructiveError is returned by RunUp when pending migrations contain destructive
+// operations and Migration.AllowDestructive is not set (flag --allow-destructive).
+type DestructiveError struct {
+	Issues []LintIssue
+}
+
+func (e *DestructiveError) Error() string {
+	parts := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		parts[i] = issue.String()
+	}
+	return fmt.Sprintf("%d destructive operation(s) in pending migrations (use --allow-destructive to apply them anyway):\n%s",
+		len(e.Issues), strings.Join(parts, "\n"))
+}
+
+var (
+	lintSpaceRe      = regexp.MustCompile(`\s+`)
+	lintAlterTableRe = regexp.MustCompile(`^ALTER TABLE (?:IF EXISTS |ONLY )*\S+ (.*)$`)
+
+	lintDropColumnRe = regexp.MustCompile(`^DROP (?:COLUMN )?(?:IF EXISTS )?(\S+)`)
+	lintDropOtherRe  = regexp.MustCompile(`^DROP (?:INDEX|KEY|CONSTRAINT|FOREIGN|PRIMARY|CHECK|DEFAULT|PARTITION)\b`)
+	lintTypeChangeRe = regexp.MustCompile(`^(?:MODIFY|CHANGE)\b|^ALTER (?:COLUMN )?\S+ (?:SET DATA )?TYPE\b`)
+	lintMSSQLAlterRe = regexp.MustCompile(`^ALTER COLUMN \S+ [A-Z]`)
+	lintSetNotNullRe = regexp.MustCompile(`^ALTER (?:COLUMN )?\S+ SET NOT NULL\b`)
+	lintAddColumnRe  = regexp.MustCompile(`^ADD (?:COLUMN )?(?:IF NOT EXISTS )?`)
+	lintAddNotNullRe = regexp.MustCompile(`\bNOT NULL\b`)
+	lintHasDefaultRe = regexp.MustCompile(`\bDEFAULT\b|\bGENERATED\b|\bAUTO_INCREMENT\b|\bIDENTITY\b|\bSERIAL\b|\bBIGSERIAL\b`)
+	lintAddOtherRe   = regexp.MustCompile(`^ADD (?:INDEX|KEY|UNIQUE|CONSTRAINT|FOREIGN|PRIMARY|CHECK|FULLTEXT|SPATIAL|PARTITION)\b`)
+)
+
+// LintSQL flags the destructive operations of a SQL script: dropped tables and columns,
+// truncations, column type changes (which may narrow the column) and NOT NULL columns
+// added without a default. migrationName labels the issues.
+func LintSQL(dialect, migrationName, script string) ([]LintIssue, error) {
+	statements, err := common.SplitScript(dialect, script)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", migrationName, err)
+	}
+	var issues []LintIssue
+	add := func(statement, rule, message, suggestion string) {
+		issues = append(issues, LintIssue{Migration: migrationName, Statement: statement, Rule: rule, Message: message, Suggestion: suggestion})
+	}
+	for _, stmt := range statements {
+		normalized := normalizeForLint(dialect, stmt)
+		switch {
+		case strings.HasPrefix(normalized, "DROP TABLE "):
+			add(stmt, RuleDropTable, "drops the table and its data",
+				"rename the table first and drop it in a later migration, once no deployed code reads it")
+		case strings.HasPrefix(normalized, "TRUNCATE "):
+			add(stmt, RuleTruncate, "deletes every row of the table",
+				"delete the rows in batches from a data migration, or keep a backup table")
+		}
+		match := lintAlterTableRe.FindStringSubmatch(normalized)
+		if match == nil {
+			continue
+		}
+		for _, clause := range splitAlterClauses(match[1]) {
+			switch {
+			case lintDropOtherRe.MatchString(clause):
+			case lintDropColumnRe.MatchString(clause):
+				add(clause, RuleDropColumn, "drops the column and its data",
+					"stop reading and writing the column, deploy, then drop it in a later migration")
+			case lintTypeChangeRe.MatchString(clause), dialect == "sqlserver" && lintMSSQLAlterRe.MatchString(clause) && !lintSetNotNullRe.MatchString(clause):
+				add(clause, RuleTypeChange, "changes the column type, which may truncate values or fail on existing rows",
+					"add a column with the new type, backfill it, switch the code to it, then drop the old column")
+			case lintSetNotNullRe.MatchString(clause):
+				add(clause, RuleNotNullNoDefault, "fails if existing rows hold NULL",
+					"backfill the NULL values in a previous migration, then set NOT NULL")
+			case lintAddColumnRe.MatchString(clause) && !lintAddOtherRe.MatchString(clause) &&
+				lintAddNotNullRe.MatchString(clause) && !lintHasDefaultRe.MatchString(clause):
+				add(clause, RuleNotNullNoDefault, "adds a NOT NULL column without a default, which fails on tables with rows",
+					"add the column with a DEFAULT, or as NULL, backfill it, then set NOT NULL")
+			}
+		}
+	}
+	return issues, nil
+}
+
+// normalizeForLint removes comments and string contents, collapses whitespace and
+// uppercases the statement, so that the rules only match SQL keywords. The statement
+// is scanned once, so that quotes in comments and comment markers in strings or quoted
+// identifiers are not mistaken for the start of a string or a comment.
+func normalizeForLint(dialect, stmt string) string {
+	var sb strings.Builder
+	for i := 0; i < len(stmt); {
+		switch c := stmt[i]; {
+		case strings.HasPrefix(stmt[i:], "--"):
+			end := strings.IndexByte(stmt[i:], '\n')
+			if end < 0 {
+				end = len(stmt) - i
+			}
+			sb.WriteByte(' ')
+			i += end
+		case strings.HasPrefix(stmt[i:], "/*"):
+			end := strings.Index(stmt[i+2:], "*/")
+			if end < 0 {
+				end = len(stmt) - i - 4
+			}
+			sb.WriteByte(' ')
+			i += end + 4
+		case c == '\'':
+			sb.WriteString("''")
+			i = skipLintQuoted(stmt, i, '\'', dialect == "mysql")
+		case c == '"' || c == '`':
+			end := skipLintQuoted(stmt, i, c, false)
+			sb.WriteString(stmt[i:end])
+			i = end
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	return strings.ToUpper(strings.TrimSpace(lintSpaceRe.ReplaceAllString(sb.String(), " ")))
+}
+
+// skipLintQuoted returns the index after the quoted text starting at start. A doubled
+// quote, or a backslash when backslashEscapes is set, escapes the next character.
+// Unterminated text runs to the end of the statement.
+func skipLintQuoted(stmt string, start int, quote byte, backslashEscapes bool) int {
+	for i := start + 1; i < len(stmt); i++ {
+		switch stmt[i] {
+		case '\\':
+			if backslashEscapes {
+				i++
+			}
+		case quote:
+			if i+1 < len(stmt) && stmt[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(stmt)
+}
+
+// splitAlterClauses splits the clauses of an ALTER TABLE on the commas outside parentheses.
+func splitAlterClauses(clauses string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range clauses {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(clauses[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(clauses[start:]))
+}
+
+// lintMigrationFiles lints the Up section of the SQL migrations (Go migrations are not linted).
+func lintMigrationFiles(dialect string, files []migrationFile) ([]LintIssue, error) {
+	var issues []LintIssue
+	for _, mf := range files {
+		if mf.Type != "sql" {
+			continue
+		}
+		file, err := os.Open(mf.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open migration file '%s': %w", mf.Path, err)
+		}
+		upSQL, _, err := parseSQLMigration(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse migration file '%s': %w", mf.Path, err)
+		}
+		found, err := LintSQL(dialect, mf.Name, upSQL)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, found...)
+	}
+	return issues, nil
+}
+
+// RunLint lints every migration file of the configured directory without connecting to
+// the database, for CI checks.
+func RunLint(cfg config.Config) ([]LintIssue, error) {
+	files, err := findMigrationFiles(cfg.Migration.Directory)
+	if err != nil {
+		return nil, err
+	}
+	return lintMigrationFiles(cfg.Database.Dialect, files)
+}
@@ -0,0 +1,70 @@
+package migration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintSQL(t *testing.T) {
+	script := `
+CREATE TABLE audits (id INT PRIMARY KEY, note TEXT NOT NULL);
+ALTER TABLE users DROP COLUMN nickname, DROP INDEX idx_users_nick, ADD COLUMN status VARCHAR(10) NOT NULL;
+ALTER TABLE users ADD COLUMN tier INT NOT NULL DEFAULT 0, ADD CONSTRAINT uq_email UNIQUE (email);
+ALTER TABLE orders MODIFY total DECIMAL(8,2);
+ALTER TABLE orders ALTER COLUMN code TYPE VARCHAR(5);
+ALTER TABLE orders ALTER COLUMN paid_at SET NOT NULL;
+UPDATE users SET note = 'DROP TABLE users';
+DROP TABLE legacy_users;
+TRUNCATE TABLE sessions;
+`
+	issues, err := LintSQL("postgres", "0001_changes.sql", script)
+	require.NoError(t, err)
+	rules := make([]string, len(issues))
+	for i, issue := range issues {
+		rules[i] = issue.Rule
+		assert.Equal(t, "0001_changes.sql", issue.Migration)
+		assert.NotEmpty(t, issue.Suggestion)
+	}
+	assert.Equal(t, []string{
+		RuleDropColumn, RuleNotNullNoDefault, // First ALTER: the index drop is fine
+		RuleTypeChange, RuleTypeChange, RuleNotNullNoDefault,
+		RuleDropTable, RuleTruncate,
+	}, rules)
+	assert.Equal(t, "ADD COLUMN STATUS VARCHAR(10) NOT NULL", issues[1].Statement)
+}
+
+func TestLintSQL_SQLServerAlterColumn(t *testing.T) {
+	issues, err := LintSQL("sqlserver", "0002.sql", "ALTER TABLE users ALTER COLUMN name NVARCHAR(20) NOT NULL")
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, RuleTypeChange, issues[0].Rule)
+
+	issues, err = LintSQL("mysql", "0003.sql", "ALTER TABLE users ADD INDEX idx_name (name); ALTER TABLE users RENAME COLUMN e_mail TO email")
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+}
+
+func TestLintSQL_QuotesInComments(t *testing.T) {
+	script := `-- don't keep the old table
+DROP TABLE legacy_users;
+/* it's empty */ TRUNCATE TABLE sessions;
+UPDATE notes SET body = 'it\'s -- DROP TABLE notes' WHERE id = 1;
+ALTER TABLE "odd--name" DROP COLUMN nickname;
+`
+	issues, err := LintSQL("mysql", "0004.sql", script)
+	require.NoError(t, err)
+	rules := make([]string, len(issues))
+	for i, issue := range issues {
+		rules[i] = issue.Rule
+	}
+	assert.Equal(t, []string{RuleDropTable, RuleTruncate, RuleDropColumn}, rules)
+	assert.Equal(t, `ALTER TABLE "ODD--NAME" DROP COLUMN NICKNAME`, normalizeForLint("postgres", `ALTER TABLE "odd--name" DROP COLUMN nickname -- it's fine`))
+}
+
+func TestDestructiveErrorMessage(t *testing.T) {
+	err := &DestructiveError{Issues: []LintIssue{{Migration: "0001.sql", Rule: RuleDropTable, Statement: "DROP TABLE a", Message: "drops", Suggestion: "rename"}}}
+	assert.Contains(t, err.Error(), "--allow-destructive")
+	assert.Contains(t, err.Error(), "0001.sql: [drop-table] drops")
+}
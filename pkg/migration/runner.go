@@ -180,6 +180,10 @@ func getAppliedMigrationsOrdered(ctx context.Context, ds common.DataSource, tabl
 const (
 	markerUp   = "-- +migrate Up"
 	markerDown = "-- +migrate Down"
+	// markerNoTransaction runs the migration's statements outside a transaction, for
+	// statements that refuse one, such as Postgres' CREATE INDEX CONCURRENTLY. A failed
+	// statement leaves the previous ones applied, so keep such migrations to one statement.
+	markerNoTransaction = "-- +migrate NoTransaction"
 )
 
 // parseSQLMigration extracts the 'Up' and 'Down' SQL statements from a reader.
@@ -225,6 +229,66 @@ func parseSQLMigration(r io.Reader) (string, string, error) {
 	return upSQL.String(), downSQL.String(), nil
 }
 
+// isNoTransactionMigration reports whether a SQL migration file has the NoTransaction marker.
+func isNoTransactionMigration(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open migration file '%s': %w", path, err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if strings.HasPrefix(strings.TrimSpace(scanner.Text()), markerNoTransaction) {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("error reading migration file: %w", err)
+	}
+	return false, nil
+}
+
+// sqlExecer is implemented by common.Tx and common.DataSource.
+type sqlExecer interface {
+	Exec(ctx context.Context, query string, args ...any) (common.Result, error)
+}
+
+// execSQLSection parses a SQL migration file and executes its Up or Down section.
+func execSQLSection(ctx context.Context, db sqlExecer, dialect string, path string, up bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open migration file '%s': %w", path, err)
+	}
+	upSQL, downSQL, err := parseSQLMigration(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to parse migration file '%s': %w", path, err)
+	}
+	script := downSQL
+	if up {
+		script = upSQL
+	}
+	if strings.TrimSpace(script) == "" {
+		return nil
+	}
+	return execSQLScript(ctx, db, dialect, strings.TrimSpace(script))
+}
+
+// execSQLScript executes the statements of a SQL migration section one by one, split
+// for the dialect (strings, comments, MySQL DELIMITER blocks, SQL Server GO batches).
+func execSQLScript(ctx context.Context, tx sqlExecer, dialect string, script string) error {
+	statements, err := common.SplitScript(dialect, script)
+	if err != nil {
+		return err
+	}
+	for i, stmt := range statements {
+		if _, err := tx.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("statement %d of %d: %w", i+1, len(statements), err)
+		}
+	}
+	return nil
+}
+
 // --- NEW: Go Migration Template ---
 const goMigrationTemplate = `package migrations
 
@@ -363,11 +427,24 @@ func RunStatus(cfg config.Config) error {
 	}
 	defer ds.Close() // Ensure connection is closed
 
+	for _, schemaName := range migrationSchemas(cfg) {
+		if err := runStatus(ctx, ds, cfg, schemaName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runStatus reports the migrations of one schema ("" for the default schema).
+func runStatus(ctx context.Context, ds common.DataSource, cfg config.Config, schemaName string) error {
 	// 2. Ensure migration table exists
-	migrationTable := cfg.Migration.TableName
-	if migrationTable == "" {
+	if cfg.Migration.TableName == "" {
 		return fmt.Errorf("migration table name is not configured")
 	}
+	migrationTable := historyTable(cfg.Migration.TableName, schemaName)
+	if err := ensureSchema(ctx, ds, schemaName, cfg.Migration.SchemaOwner); err != nil {
+		return err
+	}
 	if err := ensureMigrationsTable(ctx, ds, migrationTable); err != nil {
 		return err // Error already includes context
 	}
@@ -390,6 +467,9 @@ func RunStatus(cfg config.Config) error {
 
 	// 5. Compare and Report Status
 	fmt.Println("\nMigration Status Report:")
+	if schemaName != "" {
+		fmt.Printf("Schema: %s\n", schemaName)
+	}
 	fmt.Println("------------------------")
 	foundPending := false
 	if len(diskMigrations) == 0 {
@@ -446,11 +526,25 @@ func RunUp(cfg config.Config) error {
 		return fmt.Errorf("failed to initialize data source for migrate up: %w", err)
 	}
 	defer ds.Close()
+
+	for _, schemaName := range migrationSchemas(cfg) {
+		if err := runUp(ctx, ds, cfg, schemaName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runUp applies the pending migrations of one schema ("" for the default schema).
+func runUp(ctx context.Context, ds common.DataSource, cfg config.Config, schemaName string) error {
 	dialect := ds.Dialect()
-	migrationTable := cfg.Migration.TableName
-	if migrationTable == "" {
+	if cfg.Migration.TableName == "" {
 		return fmt.Errorf("migration table name is not configured")
 	}
+	migrationTable := historyTable(cfg.Migration.TableName, schemaName)
+	if err := ensureSchema(ctx, ds, schemaName, cfg.Migration.SchemaOwner); err != nil {
+		return err
+	}
 	if err := ensureMigrationsTable(ctx, ds, migrationTable); err != nil {
 		return err
 	}
@@ -467,9 +561,27 @@ func RunUp(cfg config.Config) error {
 		appliedMap[rec.ID] = true
 	}
 
+	// Block destructive operations unless explicitly allowed
+	var pending []migrationFile
+	for _, mf := range diskMigrations {
+		if !appliedMap[mf.ID] {
+			pending = append(pending, mf)
+		}
+	}
+	issues, err := lintMigrationFiles(dialect.Name(), pending)
+	if err != nil {
+		return err
+	}
+	if len(issues) > 0 {
+		if !cfg.Migration.AllowDestructive {
+			return &DestructiveError{Issues: issues}
+		}
+		fmt.Printf("Warning: applying %d destructive operation(s) (allowed by configuration).\n", len(issues))
+	}
+
 	pendingCount := 0
 	appliedCount := 0
-	fmt.Println("Applying pending migrations...")
+	fmt.Printf("Applying pending migrations to the %s...\n", schemaLabel(schemaName))
 	for _, mf := range diskMigrations {
 		if _, applied := appliedMap[mf.ID]; !applied {
 			pendingCount++
@@ -498,6 +610,27 @@ func RunUp(cfg config.Config) error {
 					}
 					// For SQL migrations, we can proceed using ds.BeginTx()
 				}
+				if mf.Type == "go" && schemaName != "" {
+					// Go migrations get the *sql.DB pool, which cannot be pointed at a schema
+					return fmt.Errorf("cannot run Go migration %s in schema '%s': Go migrations run on the default schema", mf.ID, schemaName)
+				}
+
+				noTx := false
+				if mf.Type == "sql" {
+					if noTx, err = isNoTransactionMigration(mf.Path); err != nil {
+						return err
+					}
+				}
+				if noTx {
+					if schemaName != "" {
+						return fmt.Errorf("cannot run NoTransaction migration %s in schema '%s': it runs on the default schema", mf.ID, schemaName)
+					}
+					fmt.Printf("    Executing Up SQL outside a transaction (NoTransaction)...\n")
+					if err := execSQLSection(ctx, ds, dialect.Name(), mf.Path, true); err != nil {
+						return fmt.Errorf("failed to execute 'Up' SQL for migration %s: %w", mf.ID, err)
+					}
+					fmt.Printf("    'Up' SQL executed successfully.\n")
+				}
 
 				// Begin transaction using the common interface
 				txHandle, err := ds.BeginTx(ctx, nil)
@@ -505,10 +638,17 @@ func RunUp(cfg config.Config) error {
 					return fmt.Errorf("failed to begin transaction for migration %s: %w", mf.ID, err)
 				}
 				defer txHandle.Rollback() // Ensure rollback happens if commit isn't reached
+				restoreSchema, err := enterSchema(ctx, txHandle, dialect, schemaName)
+				if err != nil {
+					return fmt.Errorf("migration %s: %w", mf.ID, err)
+				}
 
 				// Execute based on type
 				switch mf.Type {
 				case "sql":
+					if noTx {
+						break // Already executed outside the transaction
+					}
 					file, err := os.Open(mf.Path)
 					if err != nil {
 						return fmt.Errorf("failed to open migration file '%s': %w", mf.Path, err)
@@ -521,8 +661,8 @@ func RunUp(cfg config.Config) error {
 					trimmedUpSQL := strings.TrimSpace(upSQL)
 					if trimmedUpSQL != "" {
 						fmt.Printf("    Executing Up SQL...\n")
-						// Use the transaction handle's Exec
-						if _, err := txHandle.Exec(ctx, trimmedUpSQL); err != nil {
+						// Split into statements: drivers execute one statement per Exec
+						if err := execSQLScript(ctx, txHandle, ds.Dialect().Name(), trimmedUpSQL); err != nil {
 							return fmt.Errorf("failed to execute 'Up' SQL for migration %s: %w", mf.ID, err)
 						}
 						fmt.Printf("    'Up' SQL executed successfully.\n")
@@ -554,6 +694,10 @@ func RunUp(cfg config.Config) error {
 					return fmt.Errorf("unknown migration type '%s' for file %s", mf.Type, mf.Name)
 				}
 
+				if err := restoreSchema(); err != nil {
+					return fmt.Errorf("migration %s: %w", mf.ID, err)
+				}
+
 				// Record migration in history table (always done via the transaction handle)
 				insertSQL := dialect.InsertMigrationSQL(migrationTable)
 				appliedTimestamp := time.Now().UTC()
@@ -600,11 +744,26 @@ func RunDown(cfg config.Config, steps int) error {
 		return fmt.Errorf("failed to initialize data source for migrate down: %w", err)
 	}
 	defer ds.Close()
+
+	// With several schemas, each one reverts its own last migrations
+	for _, schemaName := range migrationSchemas(cfg) {
+		if err := runDown(ctx, ds, cfg, schemaName, steps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runDown reverts the last applied migrations of one schema ("" for the default schema).
+func runDown(ctx context.Context, ds common.DataSource, cfg config.Config, schemaName string, steps int) error {
 	dialect := ds.Dialect()
-	migrationTable := cfg.Migration.TableName
-	if migrationTable == "" {
+	if cfg.Migration.TableName == "" {
 		return fmt.Errorf("migration table name is not configured")
 	}
+	migrationTable := historyTable(cfg.Migration.TableName, schemaName)
+	if err := ensureSchema(ctx, ds, schemaName, cfg.Migration.SchemaOwner); err != nil {
+		return err
+	}
 	if err := ensureMigrationsTable(ctx, ds, migrationTable); err != nil {
 		return err
 	} // Check table exists
@@ -631,7 +790,7 @@ func RunDown(cfg config.Config, steps int) error {
 	}
 
 	revertedCount := 0
-	fmt.Printf("Reverting the last %d applied migration(s)...\n", len(migrationsToRevert))
+	fmt.Printf("Reverting the last %d applied migration(s) of the %s...\n", len(migrationsToRevert), schemaLabel(schemaName))
 	for _, migrationRecord := range migrationsToRevert {
 		fmt.Printf("--> Reverting migration %s...\n", migrationRecord.ID)
 		mf, found := diskFilesMap[migrationRecord.ID]
@@ -650,16 +809,43 @@ func RunDown(cfg config.Config, steps int) error {
 			if mf.Type == "go" && dbHandle == nil {
 				return fmt.Errorf("cannot run Go migration Down() %s: underlying DataSource does not provide *sql.DB access", mf.ID)
 			}
+			if mf.Type == "go" && schemaName != "" {
+				return fmt.Errorf("cannot run Go migration Down() %s in schema '%s': Go migrations run on the default schema", mf.ID, schemaName)
+			}
+
+			noTx := false
+			if mf.Type == "sql" {
+				if noTx, err = isNoTransactionMigration(mf.Path); err != nil {
+					return err
+				}
+			}
+			if noTx {
+				if schemaName != "" {
+					return fmt.Errorf("cannot revert NoTransaction migration %s in schema '%s': it runs on the default schema", mf.ID, schemaName)
+				}
+				fmt.Printf("    Executing Down SQL outside a transaction (NoTransaction)...\n")
+				if err := execSQLSection(ctx, ds, dialect.Name(), mf.Path, false); err != nil {
+					return fmt.Errorf("failed to execute 'Down' SQL for migration %s: %w", migrationRecord.ID, err)
+				}
+				fmt.Printf("    'Down' SQL executed successfully.\n")
+			}
 
 			txHandle, err := ds.BeginTx(ctx, nil)
 			if err != nil {
 				return fmt.Errorf("failed to begin transaction for reverting migration %s: %w", migrationRecord.ID, err)
 			}
 			defer txHandle.Rollback()
+			restoreSchema, err := enterSchema(ctx, txHandle, dialect, schemaName)
+			if err != nil {
+				return fmt.Errorf("migration %s: %w", migrationRecord.ID, err)
+			}
 
 			// Execute Down logic based on type
 			switch mf.Type {
 			case "sql":
+				if noTx {
+					break // Already executed outside the transaction
+				}
 				file, err := os.Open(mf.Path)
 				if err != nil {
 					return fmt.Errorf("failed to open migration file '%s' for revert: %w", mf.Path, err)
@@ -672,7 +858,7 @@ func RunDown(cfg config.Config, steps int) error {
 				trimmedDownSQL := strings.TrimSpace(downSQL)
 				if trimmedDownSQL != "" {
 					fmt.Printf("    Executing Down SQL...\n")
-					if _, err := txHandle.Exec(ctx, trimmedDownSQL); err != nil {
+					if err := execSQLScript(ctx, txHandle, ds.Dialect().Name(), trimmedDownSQL); err != nil {
 						return fmt.Errorf("failed to execute 'Down' SQL for migration %s: %w", migrationRecord.ID, err)
 					}
 					fmt.Printf("    'Down' SQL executed successfully.\n")
@@ -694,6 +880,10 @@ func RunDown(cfg config.Config, steps int) error {
 				return fmt.Errorf("unknown migration type '%s' for file %s", mf.Type, mf.Name)
 			}
 
+			if err := restoreSchema(); err != nil {
+				return fmt.Errorf("migration %s: %w", migrationRecord.ID, err)
+			}
+
 			// Delete record from history table
 			deleteSQL := dialect.DeleteMigrationSQL(migrationTable)
 			if _, err := txHandle.Exec(ctx, deleteSQL, migrationRecord.ID); err != nil {
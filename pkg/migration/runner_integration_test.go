@@ -456,3 +456,43 @@ func TestMigrationRunner_Go_RunUp_Error(t *testing.T) {
 	require.NoError(t, histErr)
 	assert.Empty(t, history, "History should be empty after failed migration")
 }
+
+func TestMigrationRunner_Schemas(t *testing.T) {
+	ctx, cfg, ds := setupMigrationTest(t)
+	dialect := ds.Dialect()
+	schemas := []string{"typegorm_tenant_a", "typegorm_tenant_b"}
+	cfg.Migration.Schemas = schemas
+	t.Cleanup(func() {
+		for _, schemaName := range schemas {
+			_, _ = ds.Exec(context.Background(), "DROP SCHEMA IF EXISTS "+dialect.Quote(schemaName)+" CASCADE")
+			_, _ = ds.Exec(context.Background(), "DROP SCHEMA IF EXISTS "+dialect.Quote(schemaName))
+		}
+	})
+
+	ts1 := time.Now().UTC().Add(-1 * time.Minute).Format("20060102150405")
+	createMigrationFile(t, cfg.Migration.Directory, ts1, "create_widgets",
+		"CREATE TABLE widgets (id INT PRIMARY KEY);",
+		"DROP TABLE widgets;")
+
+	if err := RunUp(cfg); err != nil && strings.Contains(err.Error(), "failed to ensure schema") {
+		t.Skipf("Skipping: the test user cannot create schemas: %v", err)
+	} else {
+		require.NoError(t, err, "RunUp failed")
+	}
+
+	for _, schemaName := range schemas {
+		history, err := getHistoryIDs(ctx, ds, historyTable(cfg.Migration.TableName, schemaName))
+		require.NoError(t, err, "history of %s", schemaName)
+		assert.Equal(t, []string{ts1}, history, "each schema has its own history")
+		_, err = ds.Exec(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %s.%s", dialect.Quote(schemaName), dialect.Quote("widgets")))
+		assert.NoError(t, err, "widgets created in %s", schemaName)
+	}
+	assert.False(t, tableExists(ctx, ds, "widgets"), "the default schema is untouched")
+
+	require.NoError(t, RunDown(cfg, 1), "RunDown failed")
+	for _, schemaName := range schemas {
+		history, err := getHistoryIDs(ctx, ds, historyTable(cfg.Migration.TableName, schemaName))
+		require.NoError(t, err)
+		assert.Empty(t, history, "reverted in %s", schemaName)
+	}
+}
@@ -0,0 +1,37 @@
+package migration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsNoTransactionMigration(t *testing.T) {
+	dir := t.TempDir()
+	online := filepath.Join(dir, "20240101000000_index.sql")
+	require.NoError(t, os.WriteFile(online, []byte("-- +migrate NoTransaction\n-- +migrate Up\nCREATE INDEX CONCURRENTLY idx_users_email ON users (email);\n-- +migrate Down\nDROP INDEX CONCURRENTLY idx_users_email;\n"), 0o644))
+	plain := filepath.Join(dir, "20240101000001_table.sql")
+	require.NoError(t, os.WriteFile(plain, []byte("-- +migrate Up\nCREATE TABLE t (id INT);\n"), 0o644))
+
+	noTx, err := isNoTransactionMigration(online)
+	require.NoError(t, err)
+	assert.True(t, noTx)
+	noTx, err = isNoTransactionMigration(plain)
+	require.NoError(t, err)
+	assert.False(t, noTx)
+
+	up, down, err := parseSQLMigration(mustOpen(t, online))
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE INDEX CONCURRENTLY idx_users_email ON users (email);\n", up)
+	assert.Equal(t, "DROP INDEX CONCURRENTLY idx_users_email;\n", down)
+}
+
+func mustOpen(t *testing.T, path string) *os.File {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { file.Close() })
+	return file
+}
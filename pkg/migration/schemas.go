@@ -0,0 +1,110 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+)
+
+// --- Schema-per-tenant Support ---
+
+// migrationSchemas returns the schemas to migrate, in order; "" is the default schema
+// of the connection (Migration.Schemas not set).
+func migrationSchemas(cfg config.Config) []string {
+	if len(cfg.Migration.Schemas) == 0 {
+		return []string{""}
+	}
+	return cfg.Migration.Schemas
+}
+
+// historyTable qualifies the migration history table by schema ("tenant_a.schema_migrations").
+func historyTable(tableName, schemaName string) string {
+	if schemaName == "" {
+		return tableName
+	}
+	return schemaName + "." + tableName
+}
+
+// schemaLabel names a schema in the runner's output.
+func schemaLabel(schemaName string) string {
+	if schemaName == "" {
+		return "default schema"
+	}
+	return "schema '" + schemaName + "'"
+}
+
+// ensureSchema creates the schema if it does not exist and, on Postgres, gives it to owner.
+func ensureSchema(ctx context.Context, ds common.DataSource, schemaName, owner string) error {
+	if schemaName == "" {
+		return nil
+	}
+	dialect := ds.Dialect()
+	var statements []string
+	switch dialect.Name() {
+	case "postgres":
+		createSQL := "CREATE SCHEMA IF NOT EXISTS " + dialect.Quote(schemaName)
+		if owner != "" {
+			createSQL += " AUTHORIZATION " + dialect.Quote(owner)
+			statements = append(statements, createSQL,
+				fmt.Sprintf("ALTER SCHEMA %s OWNER TO %s", dialect.Quote(schemaName), dialect.Quote(owner)))
+		} else {
+			statements = append(statements, createSQL)
+		}
+	case "mysql":
+		// MySQL schemas are databases: they have no owner, access is granted per user
+		if owner != "" {
+			fmt.Printf("Warning: schema owner '%s' ignored, mysql schemas have no owner.\n", owner)
+		}
+		statements = append(statements, "CREATE SCHEMA IF NOT EXISTS "+dialect.Quote(schemaName))
+	default:
+		return fmt.Errorf("migrating schema '%s': dialect %s does not support multiple schemas", schemaName, dialect.Name())
+	}
+
+	fmt.Printf("Ensuring schema '%s' exists...\n", schemaName)
+	for _, stmt := range statements {
+		if _, err := ds.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to ensure schema '%s': %w", schemaName, err)
+		}
+	}
+	return nil
+}
+
+// enterSchema makes schemaName the default schema of the statements run in tx, so that the
+// unqualified names of a migration resolve to it, and returns the function restoring the
+// previous default before the transaction ends.
+func enterSchema(ctx context.Context, tx common.Tx, dialect common.Dialect, schemaName string) (func() error, error) {
+	noop := func() error { return nil }
+	if schemaName == "" {
+		return noop, nil
+	}
+	switch dialect.Name() {
+	case "postgres":
+		// SET LOCAL lasts until the end of the transaction
+		if _, err := tx.Exec(ctx, "SET LOCAL search_path TO "+dialect.Quote(schemaName)); err != nil {
+			return nil, fmt.Errorf("failed to set search_path to schema '%s': %w", schemaName, err)
+		}
+		return noop, nil
+	case "mysql":
+		// USE outlives the transaction: the pooled connection must get its database back
+		var previous sql.NullString
+		if err := tx.QueryRow(ctx, "SELECT DATABASE()").Scan(&previous); err != nil {
+			return nil, fmt.Errorf("failed to read the current database: %w", err)
+		}
+		if _, err := tx.Exec(ctx, "USE "+dialect.Quote(schemaName)); err != nil {
+			return nil, fmt.Errorf("failed to switch to schema '%s': %w", schemaName, err)
+		}
+		return func() error {
+			if !previous.Valid {
+				return nil
+			}
+			if _, err := tx.Exec(ctx, "USE "+dialect.Quote(previous.String)); err != nil {
+				return fmt.Errorf("failed to switch back to database '%s': %w", previous.String, err)
+			}
+			return nil
+		}, nil
+	}
+	return nil, fmt.Errorf("dialect %s does not support multiple schemas", dialect.Name())
+}
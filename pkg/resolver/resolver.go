@@ -0,0 +1,292 @@
+// Package resolver turns model metadata into filter, sort and pagination argument
+// parsers, so REST or GraphQL handlers can map request arguments onto Find calls
+// with minimal boilerplate:
+//
+//	// GET /users?filter[age][gte]=30&sort=-created_at&page[size]=20&page[number]=2
+//	model, _ := db.GetModel(&User{})
+//	q, err := resolver.ParseQuery(model, r.URL.Query(), resolver.Options{})
+//	if err != nil { /* 400 Bad Request */ }
+//	var users []User
+//	result := db.Find(ctx, &users, q.FindArgs()...)
+package resolver
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+	"github.com/chmenegatti/typegorm/pkg/typegorm"
+)
+
+// Options configures the parsers.
+type Options struct {
+	DefaultPageSize int      // Page size when none is requested (default 20)
+	MaxPageSize     int      // Upper bound for the requested page size (default 100)
+	Columns         []string // Columns allowed in filters and sorting; empty allows all model columns
+	DefaultSort     string   // Sort expression used when none is requested (e.g., "-created_at")
+}
+
+// Query is the parsed, validated form of the request arguments.
+type Query struct {
+	Condition map[string]any // Conditions in the map format accepted by Find ("age >=": 30)
+	OrderBy   string         // ORDER BY clause built from validated column names
+	Page      int            // 1-based page number
+	PageSize  int
+}
+
+// Limit returns the LIMIT for the requested page.
+func (q *Query) Limit() int { return q.PageSize }
+
+// Offset returns the OFFSET for the requested page.
+func (q *Query) Offset() int { return (q.Page - 1) * q.PageSize }
+
+// FindArgs returns the arguments to pass to DB.Find / Tx.Find.
+func (q *Query) FindArgs() []any {
+	args := make([]any, 0, 4)
+	if len(q.Condition) > 0 {
+		args = append(args, q.Condition)
+	}
+	if q.OrderBy != "" {
+		args = append(args, typegorm.Order(q.OrderBy))
+	}
+	args = append(args, typegorm.Limit(q.Limit()), typegorm.Offset(q.Offset()))
+	return args
+}
+
+// Args is the transport-neutral form of the arguments, e.g. decoded GraphQL arguments:
+// Filter maps a column to operator/value pairs ({"age": {"gte": 30}}).
+type Args struct {
+	Filter   map[string]map[string]any
+	Sort     []string // Column names, prefixed with '-' for descending order
+	Page     int
+	PageSize int
+}
+
+// operators maps the public operator names to the condition key suffixes used by Find.
+var operators = map[string]string{
+	"eq":    "=",
+	"ne":    "!=",
+	"gt":    ">",
+	"gte":   ">=",
+	"lt":    "<",
+	"lte":   "<=",
+	"like":  "like",
+	"in":    "in",
+	"nin":   "not in",
+	"null":  "is null",
+	"exact": "=",
+}
+
+// ParseQuery parses URL query parameters in the form
+// filter[column][op]=value, filter[column]=value, sort=-col1,col2, page[number]=N, page[size]=N.
+// String values are converted to the Go type of the target field.
+func ParseQuery(model *schema.Model, values url.Values, opts Options) (*Query, error) {
+	args := Args{Filter: make(map[string]map[string]any)}
+
+	for key, vals := range values {
+		if len(vals) == 0 {
+			continue
+		}
+		value := vals[len(vals)-1]
+		switch {
+		case key == "sort":
+			for _, s := range strings.Split(value, ",") {
+				if s = strings.TrimSpace(s); s != "" {
+					args.Sort = append(args.Sort, s)
+				}
+			}
+		case key == "page[number]":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid page[number] '%s'", value)
+			}
+			args.Page = n
+		case key == "page[size]":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid page[size] '%s'", value)
+			}
+			args.PageSize = n
+		case strings.HasPrefix(key, "filter["):
+			column, op, err := parseFilterKey(key)
+			if err != nil {
+				return nil, err
+			}
+			if args.Filter[column] == nil {
+				args.Filter[column] = make(map[string]any)
+			}
+			args.Filter[column][op] = value
+		}
+	}
+	return Parse(model, args, opts)
+}
+
+// Parse validates transport-neutral arguments against the model.
+func Parse(model *schema.Model, args Args, opts Options) (*Query, error) {
+	if opts.DefaultPageSize <= 0 {
+		opts.DefaultPageSize = 20
+	}
+	if opts.MaxPageSize <= 0 {
+		opts.MaxPageSize = 100
+	}
+
+	q := &Query{Condition: make(map[string]any), Page: args.Page, PageSize: args.PageSize}
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.PageSize <= 0 {
+		q.PageSize = opts.DefaultPageSize
+	}
+	if q.PageSize > opts.MaxPageSize {
+		q.PageSize = opts.MaxPageSize
+	}
+
+	for column, ops := range args.Filter {
+		field, err := allowedField(model, column, opts)
+		if err != nil {
+			return nil, err
+		}
+		for op, raw := range ops {
+			suffix, ok := operators[strings.ToLower(op)]
+			if !ok {
+				return nil, fmt.Errorf("unsupported filter operator '%s' for column '%s'", op, column)
+			}
+			value, err := convertFilterValue(field, suffix, raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for filter[%s][%s]: %w", column, op, err)
+			}
+			if suffix == "is null" {
+				if negate, _ := value.(bool); !negate {
+					suffix = "is not null"
+				}
+				value = true
+			}
+			q.Condition[column+" "+suffix] = value
+		}
+	}
+
+	sort := args.Sort
+	if len(sort) == 0 && opts.DefaultSort != "" {
+		sort = strings.Split(opts.DefaultSort, ",")
+	}
+	clauses := make([]string, 0, len(sort))
+	for _, s := range sort {
+		s = strings.TrimSpace(s)
+		direction := "ASC"
+		if strings.HasPrefix(s, "-") {
+			direction = "DESC"
+			s = s[1:]
+		}
+		field, err := allowedField(model, strings.TrimPrefix(s, "+"), opts)
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, field.DBName+" "+direction)
+	}
+	q.OrderBy = strings.Join(clauses, ", ")
+	return q, nil
+}
+
+// parseFilterKey splits "filter[age][gte]" into ("age", "gte"); "filter[age]" means "eq".
+func parseFilterKey(key string) (string, string, error) {
+	rest := strings.TrimPrefix(key, "filter[")
+	column, rest, ok := strings.Cut(rest, "]")
+	if !ok || column == "" {
+		return "", "", fmt.Errorf("malformed filter parameter '%s'", key)
+	}
+	if rest == "" {
+		return column, "eq", nil
+	}
+	if !strings.HasPrefix(rest, "[") || !strings.HasSuffix(rest, "]") || len(rest) < 3 {
+		return "", "", fmt.Errorf("malformed filter parameter '%s'", key)
+	}
+	return column, rest[1 : len(rest)-1], nil
+}
+
+func allowedField(model *schema.Model, column string, opts Options) (*schema.Field, error) {
+	field, ok := model.GetFieldByDBName(column)
+	if !ok {
+		return nil, fmt.Errorf("unknown column '%s' for %s", column, model.Name)
+	}
+	if len(opts.Columns) > 0 {
+		for _, c := range opts.Columns {
+			if c == column {
+				return field, nil
+			}
+		}
+		return nil, fmt.Errorf("column '%s' cannot be used for filtering or sorting", column)
+	}
+	return field, nil
+}
+
+// convertFilterValue converts raw argument values (strings from URLs, or already typed
+// values from GraphQL) to the type of the field.
+func convertFilterValue(field *schema.Field, operator string, raw any) (any, error) {
+	if operator == "is null" {
+		if s, ok := raw.(string); ok {
+			return strconv.ParseBool(s)
+		}
+		b, ok := raw.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected boolean, got %T", raw)
+		}
+		return b, nil
+	}
+	if operator == "in" || operator == "not in" {
+		var items []any
+		switch v := raw.(type) {
+		case string:
+			for _, s := range strings.Split(v, ",") {
+				items = append(items, strings.TrimSpace(s))
+			}
+		case []any:
+			items = v
+		default:
+			items = []any{v}
+		}
+		converted := make([]any, len(items))
+		for i, item := range items {
+			c, err := convertScalar(field.GoType, item)
+			if err != nil {
+				return nil, err
+			}
+			converted[i] = c
+		}
+		return converted, nil
+	}
+	if operator == "like" {
+		return fmt.Sprint(raw), nil
+	}
+	return convertScalar(field.GoType, raw)
+}
+
+func convertScalar(goType reflect.Type, raw any) (any, error) {
+	s, ok := raw.(string)
+	if !ok {
+		return raw, nil // Already typed (e.g., GraphQL arguments)
+	}
+	for goType.Kind() == reflect.Pointer {
+		goType = goType.Elem()
+	}
+	if goType == reflect.TypeOf(time.Time{}) {
+		return time.Parse(time.RFC3339, s)
+	}
+	switch goType.Kind() {
+	case reflect.String:
+		return s, nil
+	case reflect.Bool:
+		return strconv.ParseBool(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(s, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(s, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(s, 64)
+	default:
+		return s, nil
+	}
+}
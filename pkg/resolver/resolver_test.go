@@ -0,0 +1,85 @@
+// pkg/resolver/resolver_test.go
+package resolver
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+type resolverUser struct {
+	ID        uint `typegorm:"primaryKey;autoIncrement"`
+	Name      string
+	Age       int
+	Active    bool
+	Email     *string
+	CreatedAt time.Time
+}
+
+func parseUserModel(t *testing.T) *schema.Model {
+	model, err := schema.NewParser(nil).Parse(&resolverUser{})
+	require.NoError(t, err)
+	return model
+}
+
+func TestParseQuery(t *testing.T) {
+	values, err := url.ParseQuery("filter[age][gte]=30&filter[name]=Ana&filter[id][in]=1,2,3&filter[email][null]=false&sort=-created_at,name&page[size]=10&page[number]=3")
+	require.NoError(t, err)
+
+	q, err := ParseQuery(parseUserModel(t), values, Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"age >=":            int64(30),
+		"name =":            "Ana",
+		"id in":             []any{uint64(1), uint64(2), uint64(3)},
+		"email is not null": true,
+	}, q.Condition)
+	assert.Equal(t, "created_at DESC, name ASC", q.OrderBy)
+	assert.Equal(t, 10, q.Limit())
+	assert.Equal(t, 20, q.Offset())
+	assert.Len(t, q.FindArgs(), 4)
+}
+
+func TestParseQuery_Defaults(t *testing.T) {
+	q, err := ParseQuery(parseUserModel(t), url.Values{"page[size]": {"5000"}}, Options{DefaultSort: "-id"})
+	require.NoError(t, err)
+	assert.Equal(t, 100, q.PageSize, "page size is capped")
+	assert.Equal(t, 1, q.Page)
+	assert.Equal(t, "id DESC", q.OrderBy)
+	assert.Empty(t, q.Condition)
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	model := parseUserModel(t)
+	tests := map[string]string{
+		"unknown column":   "filter[password]=x",
+		"unknown operator": "filter[age][between]=1",
+		"bad value":        "filter[age][gt]=old",
+		"bad sort":         "sort=secret",
+		"malformed":        "filter[age=1",
+	}
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			values, err := url.ParseQuery(raw)
+			require.NoError(t, err)
+			_, err = ParseQuery(model, values, Options{})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParse_AllowedColumns(t *testing.T) {
+	model := parseUserModel(t)
+	_, err := Parse(model, Args{Sort: []string{"age"}}, Options{Columns: []string{"name"}})
+	assert.Error(t, err)
+
+	q, err := Parse(model, Args{Filter: map[string]map[string]any{"name": {"like": "An%"}}}, Options{Columns: []string{"name"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name like": "An%"}, q.Condition)
+}
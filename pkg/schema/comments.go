@@ -0,0 +1,121 @@
+package schema
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// --- Documentation Comments ---
+
+// typeDocs holds the Go doc comments of a struct and its fields.
+type typeDocs struct {
+	doc    string
+	fields map[string]string
+}
+
+// WithComments makes the parser populate Model.Comment and Field.Comment, which
+// CreateTableSQL turns into table/column COMMENT clauses. Comments come from the
+// `comment:...` tag and, for structs whose source was loaded with LoadDocComments,
+// from their Go doc comments (the tag takes precedence).
+func WithComments() ParserOption {
+	return func(p *Parser) { p.comments = true }
+}
+
+// LoadDocComments reads the Go doc comments of the structs declared in the given
+// package directories (non-recursive, test files excluded), since doc comments are not
+// available through reflection. Call it before parsing the models; without
+// WithComments it has no effect.
+func (p *Parser) LoadDocComments(dirs ...string) error {
+	if p.docs == nil {
+		p.docs = make(map[string]typeDocs)
+	}
+	fset := token.NewFileSet()
+	for _, dir := range dirs {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			return fmt.Errorf("failed to list Go files in %s: %w", dir, err)
+		}
+		if len(paths) == 0 {
+			if _, err := os.Stat(dir); err != nil {
+				return fmt.Errorf("failed to read doc comments: %w", err)
+			}
+		}
+		for _, path := range paths {
+			if strings.HasSuffix(path, "_test.go") {
+				continue
+			}
+			file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			p.collectDocs(file)
+		}
+	}
+	return nil
+}
+
+func (p *Parser) collectDocs(file *ast.File) {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			docGroup := typeSpec.Doc
+			if docGroup == nil && len(genDecl.Specs) == 1 {
+				docGroup = genDecl.Doc // "// User ...\ntype User struct" attaches to the GenDecl
+			}
+			docs := typeDocs{doc: cleanDoc(docGroup), fields: make(map[string]string)}
+			for _, field := range structType.Fields.List {
+				text := cleanDoc(field.Doc)
+				if text == "" {
+					text = cleanDoc(field.Comment) // Trailing "// ..." on the same line
+				}
+				if text == "" {
+					continue
+				}
+				for _, name := range field.Names {
+					docs.fields[name.Name] = text
+				}
+			}
+			p.docs[file.Name.Name+"."+typeSpec.Name.Name] = docs
+		}
+	}
+}
+
+// cleanDoc turns a comment group into a single line.
+func cleanDoc(group *ast.CommentGroup) string {
+	if group == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(group.Text()), " ")
+}
+
+// applyDocComments fills the comments not set by tags from the loaded doc comments.
+func (p *Parser) applyDocComments(model *Model) {
+	if !p.comments || model.Type == nil {
+		return
+	}
+	docs, ok := p.docs[model.Type.String()]
+	if !ok {
+		return
+	}
+	if model.Comment == "" {
+		model.Comment = docs.doc
+	}
+	for _, field := range model.Fields {
+		if field.Comment == "" {
+			field.Comment = docs.fields[field.GoName]
+		}
+	}
+}
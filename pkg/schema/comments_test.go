@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type DocumentedInvoice struct {
+	ID     uint    `typegorm:"primaryKey"`
+	Total  float64 `typegorm:"comment:Total in cents"`
+	Status string
+}
+
+const documentedInvoiceSource = `package schema
+
+// DocumentedInvoice is an invoice issued to a customer.
+type DocumentedInvoice struct {
+	// ID is the invoice number.
+	ID     uint
+	Total  float64 // Overridden by the comment tag
+	Status string  // Payment status: pending, paid or void.
+}
+`
+
+func TestParse_DocComments(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "invoice.go"), []byte(documentedInvoiceSource), 0o644))
+
+	parser := NewParser(nil, WithComments())
+	require.NoError(t, parser.LoadDocComments(dir))
+	model, err := parser.Parse(&DocumentedInvoice{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "DocumentedInvoice is an invoice issued to a customer.", model.Comment)
+	id, _ := model.GetField("ID")
+	assert.Equal(t, "ID is the invoice number.", id.Comment)
+	total, _ := model.GetField("Total")
+	assert.Equal(t, "Total in cents", total.Comment, "the tag takes precedence")
+	status, _ := model.GetField("Status")
+	assert.Equal(t, "Payment status: pending, paid or void.", status.Comment)
+}
+
+func TestParse_CommentsDisabled(t *testing.T) {
+	model, err := NewParser(nil).Parse(&DocumentedInvoice{})
+	require.NoError(t, err)
+
+	total, _ := model.GetField("Total")
+	assert.Empty(t, total.Comment)
+	assert.Empty(t, model.Comment)
+}
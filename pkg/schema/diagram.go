@@ -0,0 +1,146 @@
+// pkg/schema/diagram.go
+package schema
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// --- ER Diagrams ---
+
+// WriteMermaid renders the document as a Mermaid erDiagram. Table names that are not
+// plain identifiers (schema-qualified, with dashes or spaces) are double-quoted, and
+// column names are reduced to the characters Mermaid accepts in attribute names.
+func WriteMermaid(w io.Writer, doc *Document) error {
+	var sb strings.Builder
+	sb.WriteString("erDiagram\n")
+	for _, table := range doc.Tables {
+		fmt.Fprintf(&sb, "    %s {\n", mermaidEntity(table.Name))
+		for _, col := range table.Columns {
+			fmt.Fprintf(&sb, "        %s %s", diagramType(col), mermaidAttribute(col.Name))
+			if keys := columnKeys(table, col); len(keys) > 0 {
+				sb.WriteString(" " + strings.Join(keys, ","))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("    }\n")
+	}
+	for _, table := range doc.Tables {
+		for _, rel := range table.Relations {
+			// Many rows of the referencing table point to exactly one referenced row.
+			cardinality := "}o--||"
+			if columnByName(table, rel.Column).Nullable {
+				cardinality = "}o--o|"
+			}
+			fmt.Fprintf(&sb, "    %s %s %s : %s\n", mermaidEntity(table.Name), cardinality, mermaidEntity(rel.ReferencesTable), mermaidString(rel.Column))
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteDOT renders the document as a Graphviz digraph.
+func WriteDOT(w io.Writer, doc *Document) error {
+	var sb strings.Builder
+	sb.WriteString("digraph schema {\n")
+	sb.WriteString("    rankdir=LR;\n")
+	sb.WriteString("    node [shape=record, fontname=\"Helvetica\"];\n")
+	for _, table := range doc.Tables {
+		rows := make([]string, 0, len(table.Columns))
+		for _, col := range table.Columns {
+			row := fmt.Sprintf("%s : %s", col.Name, diagramType(col))
+			if keys := columnKeys(table, col); len(keys) > 0 {
+				row += " (" + strings.Join(keys, ",") + ")"
+			}
+			rows = append(rows, dotEscape(row)+"\\l")
+		}
+		fmt.Fprintf(&sb, "    %s [label=\"{%s|%s}\"];\n", dotQuote(table.Name), dotEscape(table.Name), strings.Join(rows, ""))
+	}
+	for _, table := range doc.Tables {
+		for _, rel := range table.Relations {
+			fmt.Fprintf(&sb, "    %s -> %s [label=%s];\n", dotQuote(table.Name), dotQuote(rel.ReferencesTable), dotQuote(rel.Column))
+		}
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// diagramType returns a compact type name (e.g., "VARCHAR" from "VARCHAR(100) NOT NULL").
+func diagramType(col ColumnDocument) string {
+	t := col.SQLType
+	if t == "" {
+		t = col.GoType
+	}
+	if i := strings.IndexAny(t, " ("); i > 0 {
+		t = t[:i]
+	}
+	return strings.NewReplacer("*", "", "[]", "", ".", "_").Replace(t)
+}
+
+func columnKeys(table TableDocument, col ColumnDocument) []string {
+	var keys []string
+	if col.PrimaryKey {
+		keys = append(keys, "PK")
+	}
+	for _, rel := range table.Relations {
+		if rel.Column == col.Name {
+			keys = append(keys, "FK")
+			break
+		}
+	}
+	if col.Unique && !col.PrimaryKey {
+		keys = append(keys, "UK")
+	}
+	return keys
+}
+
+func columnByName(table TableDocument, name string) ColumnDocument {
+	for _, col := range table.Columns {
+		if col.Name == name {
+			return col
+		}
+	}
+	return ColumnDocument{}
+}
+
+var (
+	mermaidIdentifierRe    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	mermaidAttributeCharRe = regexp.MustCompile(`[^A-Za-z0-9_]`)
+)
+
+// mermaidEntity returns name as a Mermaid entity name, quoted unless it is a plain
+// identifier ("app.orders", "order items").
+func mermaidEntity(name string) string {
+	if mermaidIdentifierRe.MatchString(name) {
+		return name
+	}
+	return mermaidString(name)
+}
+
+// mermaidString quotes s for Mermaid, which has no escape for double quotes inside
+// quoted text: they become single quotes.
+func mermaidString(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "'") + `"`
+}
+
+// mermaidAttribute replaces the characters Mermaid rejects in attribute names (which
+// cannot be quoted) with underscores.
+func mermaidAttribute(name string) string {
+	name = mermaidAttributeCharRe.ReplaceAllString(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// dotQuote quotes s as a DOT ID.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func dotEscape(s string) string {
+	return strings.NewReplacer(`"`, `\"`, "{", `\{`, "}", `\}`, "|", `\|`, "<", `\<`, ">", `\>`).Replace(s)
+}
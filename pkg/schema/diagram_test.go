@@ -0,0 +1,86 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// diagramDocument has a schema-qualified table, a table name with a space, columns
+// with characters to escape and both a required and a nullable relation.
+func diagramDocument() *Document {
+	return &Document{Version: DocumentVersion, Tables: []TableDocument{
+		{
+			Name: "app.customers",
+			Columns: []ColumnDocument{
+				{Name: "id", SQLType: "BIGINT UNSIGNED AUTO_INCREMENT", PrimaryKey: true},
+				{Name: "e-mail", SQLType: "VARCHAR(100) NOT NULL", Unique: true},
+			},
+		},
+		{
+			Name:    "coupons",
+			Columns: []ColumnDocument{{Name: "id", GoType: "uint", PrimaryKey: true}},
+		},
+		{
+			Name: "order items",
+			Columns: []ColumnDocument{
+				{Name: "id", GoType: "uint", PrimaryKey: true},
+				{Name: "customer_id", GoType: "uint"},
+				{Name: "coupon id", GoType: "*uint", Nullable: true},
+				{Name: `size{"cm"}`, GoType: "int"},
+			},
+			Relations: []RelationDocument{
+				{Column: "customer_id", ReferencesTable: "app.customers", ReferencesColumn: "id"},
+				{Column: "coupon id", ReferencesTable: "coupons", ReferencesColumn: "id"},
+			},
+		},
+	}}
+}
+
+func TestWriteMermaid(t *testing.T) {
+	var sb strings.Builder
+	require.NoError(t, WriteMermaid(&sb, diagramDocument()))
+	assert.Equal(t, `erDiagram
+    "app.customers" {
+        BIGINT id PK
+        VARCHAR e_mail UK
+    }
+    coupons {
+        uint id PK
+    }
+    "order items" {
+        uint id PK
+        uint customer_id FK
+        uint coupon_id FK
+        int size__cm__
+    }
+    "order items" }o--|| "app.customers" : "customer_id"
+    "order items" }o--o| coupons : "coupon id"
+`, sb.String())
+}
+
+func TestWriteDOT(t *testing.T) {
+	var sb strings.Builder
+	require.NoError(t, WriteDOT(&sb, diagramDocument()))
+	assert.Equal(t, `digraph schema {
+    rankdir=LR;
+    node [shape=record, fontname="Helvetica"];
+    "app.customers" [label="{app.customers|id : BIGINT (PK)\le-mail : VARCHAR (UK)\l}"];
+    "coupons" [label="{coupons|id : uint (PK)\l}"];
+    "order items" [label="{order items|id : uint (PK)\lcustomer_id : uint (FK)\lcoupon id : uint (FK)\lsize\{\"cm\"\} : int\l}"];
+    "order items" -> "app.customers" [label="customer_id"];
+    "order items" -> "coupons" [label="coupon id"];
+}
+`, sb.String())
+}
+
+func TestMermaidNames(t *testing.T) {
+	assert.Equal(t, "users", mermaidEntity("users"))
+	assert.Equal(t, `"public.users"`, mermaidEntity("public.users"))
+	assert.Equal(t, `"audit-log"`, mermaidEntity("audit-log"))
+	assert.Equal(t, `"say 'hi'"`, mermaidString(`say "hi"`))
+	assert.Equal(t, "_2fa_code", mermaidAttribute("2fa-code"))
+	assert.Equal(t, `"a\"b\\c"`, dotQuote(`a"b\c`))
+}
@@ -0,0 +1,137 @@
+// pkg/schema/document.go
+package schema
+
+import (
+	"sort"
+	"strings"
+)
+
+// --- Schema Document (machine-readable export) ---
+
+// DocumentVersion is the format version written in Document.Version.
+// Bump it when the document shape changes in a non-additive way.
+const DocumentVersion = 1
+
+// Document is a stable, machine-readable description of a set of models, meant for
+// external tooling (API generators, data catalogs, documentation sites).
+type Document struct {
+	Version int             `json:"version"`
+	Dialect string          `json:"dialect,omitempty"`
+	Tables  []TableDocument `json:"tables"`
+}
+
+// TableDocument describes one table.
+type TableDocument struct {
+	Name       string             `json:"name"`
+	Model      string             `json:"model"`
+	Columns    []ColumnDocument   `json:"columns"`
+	PrimaryKey []string           `json:"primaryKey"`
+	Indexes    []IndexDocument    `json:"indexes"`
+	Relations  []RelationDocument `json:"relations"`
+}
+
+// ColumnDocument describes one column.
+type ColumnDocument struct {
+	Name          string  `json:"name"`
+	Field         string  `json:"field"`
+	GoType        string  `json:"goType"`
+	SQLType       string  `json:"sqlType,omitempty"`
+	Nullable      bool    `json:"nullable"`
+	PrimaryKey    bool    `json:"primaryKey,omitempty"`
+	AutoIncrement bool    `json:"autoIncrement,omitempty"`
+	Unique        bool    `json:"unique,omitempty"`
+	Default       *string `json:"default,omitempty"`
+	Size          int     `json:"size,omitempty"`
+	Precision     int     `json:"precision,omitempty"`
+	Scale         int     `json:"scale,omitempty"`
+}
+
+// IndexDocument describes one index.
+type IndexDocument struct {
+	Name    string   `json:"name"`
+	Unique  bool     `json:"unique"`
+	Columns []string `json:"columns"`
+}
+
+// RelationDocument describes a reference from a column to a column of another table.
+type RelationDocument struct {
+	Column           string `json:"column"`
+	ReferencesTable  string `json:"referencesTable"`
+	ReferencesColumn string `json:"referencesColumn"`
+}
+
+// SQLTypeFunc resolves the SQL type of a field (usually common.Dialect.GetDataType).
+type SQLTypeFunc func(field *Field) (string, error)
+
+// NewDocument builds a Document for the given models. sqlType is optional; when set,
+// it fills ColumnDocument.SQLType (explicit `type:` tags are used otherwise).
+// Tables are sorted by name so the output is stable across runs.
+func NewDocument(sqlType SQLTypeFunc, models ...*Model) (*Document, error) {
+	doc := &Document{Version: DocumentVersion, Tables: make([]TableDocument, 0, len(models))}
+
+	for _, model := range models {
+		table := TableDocument{
+			Name:       model.QualifiedTableName(),
+			Model:      model.Name,
+			Columns:    make([]ColumnDocument, 0, len(model.Fields)),
+			PrimaryKey: make([]string, 0, len(model.PrimaryKeys)),
+			Indexes:    make([]IndexDocument, 0, len(model.Indexes)),
+			Relations:  make([]RelationDocument, 0),
+		}
+
+		for _, field := range model.Fields {
+			column := ColumnDocument{
+				Name:          field.DBName,
+				Field:         field.GoName,
+				GoType:        field.GoType.String(),
+				SQLType:       field.SQLType,
+				Nullable:      field.IsNullable(),
+				PrimaryKey:    field.IsPrimaryKey,
+				AutoIncrement: field.AutoIncrement,
+				Unique:        field.Unique,
+				Default:       field.DefaultValue,
+				Size:          field.Size,
+				Precision:     field.Precision,
+				Scale:         field.Scale,
+			}
+			if sqlType != nil {
+				resolved, err := sqlType(field)
+				if err != nil {
+					return nil, err
+				}
+				column.SQLType = resolved
+			}
+			table.Columns = append(table.Columns, column)
+
+			if field.References != "" {
+				// "table.column" or "schema.table.column": the column is after the last dot
+				i := strings.LastIndex(field.References, ".")
+				refTable, refColumn := field.References, ""
+				if i >= 0 {
+					refTable, refColumn = field.References[:i], field.References[i+1:]
+				}
+				table.Relations = append(table.Relations, RelationDocument{
+					Column:           field.DBName,
+					ReferencesTable:  refTable,
+					ReferencesColumn: refColumn,
+				})
+			}
+		}
+
+		for _, pk := range model.PrimaryKeys {
+			table.PrimaryKey = append(table.PrimaryKey, pk.DBName)
+		}
+		for _, idx := range model.Indexes {
+			columns := make([]string, len(idx.Fields))
+			for i, f := range idx.Fields {
+				columns[i] = f.DBName
+			}
+			table.Indexes = append(table.Indexes, IndexDocument{Name: idx.Name, Unique: idx.IsUnique, Columns: columns})
+		}
+
+		doc.Tables = append(doc.Tables, table)
+	}
+
+	sort.Slice(doc.Tables, func(i, j int) bool { return doc.Tables[i].Name < doc.Tables[j].Name })
+	return doc, nil
+}
@@ -0,0 +1,56 @@
+// pkg/schema/document_test.go
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDocument(t *testing.T) {
+	doc := `
+tables:
+  - name: comments
+    columns:
+      - { name: id, type: uint, primaryKey: true, autoIncrement: true }
+      - { name: post_id, type: uint, references: blog_posts.id, index: true }
+  - name: blog_posts
+    columns:
+      - { name: id, type: uint, primaryKey: true, autoIncrement: true }
+`
+	models, err := NewParser(nil).LoadYAML(strings.NewReader(doc))
+	require.NoError(t, err)
+
+	document, err := NewDocument(nil, models...)
+	require.NoError(t, err)
+	assert.Equal(t, DocumentVersion, document.Version)
+	require.Len(t, document.Tables, 2)
+	assert.Equal(t, "blog_posts", document.Tables[0].Name, "tables are sorted by name")
+
+	comments := document.Tables[1]
+	assert.Equal(t, []string{"id"}, comments.PrimaryKey)
+	require.Len(t, comments.Relations, 1)
+	assert.Equal(t, RelationDocument{Column: "post_id", ReferencesTable: "blog_posts", ReferencesColumn: "id"}, comments.Relations[0])
+	require.Len(t, comments.Indexes, 1)
+	assert.Equal(t, []string{"post_id"}, comments.Indexes[0].Columns)
+}
+
+func TestNewDocument_SchemaQualifiedTables(t *testing.T) {
+	doc := `
+tables:
+  - name: billing.invoices
+    columns:
+      - { name: id, type: uint, primaryKey: true }
+      - { name: customer_id, type: uint, references: crm.customers.id }
+`
+	models, err := NewParser(nil).LoadYAML(strings.NewReader(doc))
+	require.NoError(t, err)
+
+	document, err := NewDocument(nil, models...)
+	require.NoError(t, err)
+	require.Len(t, document.Tables, 1)
+	assert.Equal(t, "billing.invoices", document.Tables[0].Name)
+	assert.Equal(t, []RelationDocument{{Column: "customer_id", ReferencesTable: "crm.customers", ReferencesColumn: "id"}}, document.Tables[0].Relations)
+}
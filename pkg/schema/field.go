@@ -3,6 +3,7 @@ package schema
 
 import (
 	"reflect"
+	"time"
 )
 
 // Field represents metadata about a Go struct field mapped to a database column.
@@ -16,6 +17,7 @@ type Field struct {
 	DBName        string  // Database column name (e.g., "product_id", "stock_keeping_unit")
 	IsPrimaryKey  bool    // Is this field part of the primary key?
 	IsIgnored     bool    // Should this field be ignored by the ORM (tag "-")?
+	IsComputed    bool    // Derived in memory after scanning, never persisted (tag "computed")
 	IsRequired    bool    // Does this field have a NOT NULL constraint (tag "not null")?
 	Nullable      bool    // Can the DB column be NULL? (Inferred from pointer/sql.Null*, adjusted by "not null" tag)
 	Unique        bool    // Does this field have a column-level UNIQUE constraint (tag "unique")?
@@ -25,6 +27,32 @@ type Field struct {
 	Precision     int     // Precision for decimal types - parsed from precision tag
 	Scale         int     // Scale for decimal types - parsed from scale tag
 	SQLType       string  // Explicit SQL data type override from tag (e.g., "VARCHAR(150)")
+	Anonymize     string  // Anonymization rule applied when exporting data (tag "anonymize:email")
+	References    string  // Referenced "table.column" for foreign keys (tag "references:users.id")
+	Comment       string  // Column comment (tag "comment:..." or Go doc comment; see WithComments)
+
+	AutoCreateTime bool // Set on insert (tag "autoCreateTime" or "createdAt"; implied for CreatedAt)
+	AutoUpdateTime bool // Set on insert and update (tag "autoUpdateTime" or "updatedAt"; implied for UpdatedAt)
+	ManualTime     bool // Opts CreatedAt/UpdatedAt out of the conventions (tag "autoCreateTime:false")
+
+	Retention time.Duration // Rows older than this (by this timestamp column) expire (tag "retention:90d")
+
+	Sensitive bool // Bound values are shown as [REDACTED] in SQL logs (tag "sensitive")
+
+	SoftDelete bool // Deletion timestamp: Delete sets it instead of removing the row (tag "softDelete"; implied for a nullable DeletedAt)
+
+	// CounterCache is the column of the referenced parent row that counts the rows
+	// pointing at it (tag "counterCache:posts_count", used together with "references").
+	CounterCache string
+
+	// RenamedFrom lists the previous column names, most recent first (tag
+	// "renamedFrom:e_mail,mail"), so that migrations rename the column instead of
+	// dropping it and adding a new one.
+	RenamedFrom []string
+
+	// SlugSource is the Go field a URL slug is generated from on Create when this field
+	// is empty (tag "uniqueSlug:Title"). The column is unique; conflicts get a suffix.
+	SlugSource string
 
 	// --- Indexing ---
 	// Note: A field can potentially be part of multiple indexes. Storing the names here.
@@ -36,18 +64,36 @@ type Field struct {
 	IndexNames       []string // Names of non-unique indexes this field belongs to
 	UniqueIndexNames []string // Names of unique indexes this field belongs to
 
-	// --- Relationships (Future) ---
-	// Relation *Relation // Details about the relationship if this field represents one
+	// --- Relationships ---
+	Relation *Relation // Related records held by this field (tag "relation"); such fields are not columns
 
 	// --- Internal ---
 	Tags map[string]string // Optional: Store raw parsed key-value tags if needed later
 }
 
+// DataTyper is implemented by struct value types stored in a single column (through
+// driver.Valuer/sql.Scanner) to choose that column's type per dialect, e.g. "JSON".
+type DataTyper interface {
+	DataType(dialect string) string
+}
+
 // HasSQLTypeOverride checks if an explicit SQL type was set via tags.
 func (f *Field) HasSQLTypeOverride() bool {
 	return f.SQLType != ""
 }
 
+// IsAutoCreateTime reports whether the column is a creation timestamp, filled by the
+// ORM on insert (and defaulted by the database).
+func (f *Field) IsAutoCreateTime() bool {
+	return f.AutoCreateTime || (f.GoName == "CreatedAt" && !f.ManualTime)
+}
+
+// IsAutoUpdateTime reports whether the column is an update timestamp, filled by the
+// ORM on insert and on every update.
+func (f *Field) IsAutoUpdateTime() bool {
+	return f.AutoUpdateTime || (f.GoName == "UpdatedAt" && !f.ManualTime)
+}
+
 // IsNullable checks if the field allows NULL values in the database.
 // Considers both the Go type and the "not null" tag.
 func (f *Field) IsNullable() bool {
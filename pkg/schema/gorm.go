@@ -0,0 +1,73 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// --- GORM Tag Compatibility ---
+
+// WithGormTags enables the GORM compatibility mode: fields without a `typegorm` tag use
+// their `gorm` tag, translated to the equivalent typegorm options. This eases migrating
+// large model packages incrementally; a `typegorm` tag always takes precedence.
+//
+// Supported: column, type, size, precision, scale, primaryKey, autoIncrement, unique,
+// index, uniqueIndex, default, not null, autoCreateTime, autoUpdateTime, comment and "-".
+// Relationship and permission options (foreignKey, constraint, "<-", ...) are skipped
+// with a warning.
+func WithGormTags() ParserOption {
+	return func(p *Parser) { p.gormTags = true }
+}
+
+// translateGormTag converts a `gorm` tag into `typegorm` tag syntax.
+func translateGormTag(fieldName, gormTag string) string {
+	var parts []string
+	for _, part := range strings.Split(gormTag, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, ":", 2)
+		key := strings.TrimSpace(kv[0])
+		value := ""
+		if len(kv) == 2 {
+			value = strings.TrimSpace(kv[1])
+		}
+
+		switch strings.ToLower(key) {
+		case "-":
+			return "-" // "-", "-:all" and "-:migration" all mean "not a column" here
+		case "column", "type", "size", "precision", "scale", "default":
+			parts = append(parts, strings.ToLower(key)+":"+value)
+		case "primarykey", "primary_key":
+			parts = append(parts, "primaryKey")
+		case "autoincrement":
+			if !strings.EqualFold(value, "false") {
+				parts = append(parts, "autoIncrement")
+			}
+		case "unique":
+			parts = append(parts, "unique")
+		case "not null", "notnull":
+			parts = append(parts, "notNull")
+		case "index", "uniqueindex":
+			name := strings.TrimSpace(strings.SplitN(value, ",", 2)[0]) // Drop options like "sort:desc"
+			option := "index"
+			if strings.EqualFold(key, "uniqueIndex") {
+				option = "uniqueIndex"
+			}
+			if name != "" {
+				option += ":" + name
+			}
+			parts = append(parts, option)
+		case "autocreatetime":
+			parts = append(parts, "autoCreateTime")
+		case "autoupdatetime":
+			parts = append(parts, "autoUpdateTime")
+		case "comment":
+			parts = append(parts, "comment:"+value)
+		default:
+			fmt.Printf("Warning: gorm tag option '%s' on %s has no typegorm equivalent, skipping\n", part, fieldName)
+		}
+	}
+	return strings.Join(parts, ";")
+}
@@ -0,0 +1,65 @@
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type GormProduct struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	Code      string    `gorm:"column:sku;size:64;uniqueIndex:uix_sku"`
+	Price     float64   `gorm:"type:decimal(10,2);not null;default:0"`
+	Category  string    `gorm:"index:idx_category,sort:desc"`
+	OwnerID   uint      `gorm:"foreignKey:OwnerRefer"`
+	Secret    string    `gorm:"-"`
+	Label     string    `gorm:"size:10" typegorm:"column:display_label"` // typegorm wins
+	Published time.Time `gorm:"autoCreateTime"`
+}
+
+func TestParse_GormTags(t *testing.T) {
+	model, err := NewParser(nil, WithGormTags()).Parse(&GormProduct{})
+	require.NoError(t, err)
+
+	require.Len(t, model.PrimaryKeys, 1)
+	assert.True(t, model.PrimaryKeys[0].AutoIncrement)
+
+	code, ok := model.GetField("Code")
+	require.True(t, ok)
+	assert.Equal(t, "sku", code.DBName)
+	assert.Equal(t, 64, code.Size)
+	assert.Equal(t, []string{"uix_sku"}, code.UniqueIndexNames)
+
+	price, _ := model.GetField("Price")
+	assert.Equal(t, "decimal(10,2)", price.SQLType)
+	assert.True(t, price.IsRequired)
+	require.NotNil(t, price.DefaultValue)
+	assert.Equal(t, "0", *price.DefaultValue)
+
+	category, _ := model.GetField("Category")
+	assert.Equal(t, []string{"idx_category"}, category.IndexNames)
+
+	_, ok = model.GetField("Secret")
+	assert.False(t, ok, "gorm:\"-\" is ignored")
+
+	owner, ok := model.GetField("OwnerID")
+	require.True(t, ok, "unsupported options are skipped, the column stays")
+	assert.Equal(t, "owner_id", owner.DBName)
+
+	label, _ := model.GetField("Label")
+	assert.Equal(t, "display_label", label.DBName)
+	assert.Zero(t, label.Size)
+
+	published, _ := model.GetField("Published")
+	assert.True(t, published.IsAutoCreateTime())
+}
+
+func TestParse_GormTagsOptIn(t *testing.T) {
+	model, err := NewParser(nil).Parse(&GormProduct{})
+	require.NoError(t, err)
+
+	code, _ := model.GetField("Code")
+	assert.Equal(t, "code", code.DBName, "gorm tags are ignored unless enabled")
+}
@@ -50,6 +50,37 @@ func (ns DefaultNamingStrategy) ColumnName(fieldName string) string {
 	return strings.ToLower(string(output))
 }
 
+// PreserveCaseNamingStrategy keeps the exact case of Go names: struct Order maps to
+// table "Orders" and field CreatedAt to column "CreatedAt". Since the ORM quotes every
+// identifier, the names stay case-sensitive on dialects that fold unquoted identifiers
+// (Postgres). Select it with config schema.preserveCase.
+type PreserveCaseNamingStrategy struct{}
+
+func (ns PreserveCaseNamingStrategy) TableName(structName string) string {
+	return structName + "s"
+}
+
+func (ns PreserveCaseNamingStrategy) ColumnName(fieldName string) string {
+	return fieldName
+}
+
+// QualifiedTableName returns the table name qualified by its schema ("accounting.invoices"),
+// or the bare table name when the model has no schema. Quote each part separately.
+func (m *Model) QualifiedTableName() string {
+	if m.Schema == "" {
+		return m.TableName
+	}
+	return m.Schema + "." + m.TableName
+}
+
+// SplitQualifiedName splits "schema.table" into its parts; schema is "" for a bare name.
+func SplitQualifiedName(name string) (schemaName, table string) {
+	if before, after, ok := strings.Cut(name, "."); ok {
+		return before, after
+	}
+	return "", name
+}
+
 // --- Index Representation ---
 
 // Index represents a database index definition.
@@ -63,17 +94,24 @@ type Index struct {
 
 // Model represents the parsed schema of a Go struct for ORM mapping.
 type Model struct {
-	Name           string            // Name of the Go struct (e.g., "Product")
-	Type           reflect.Type      // reflect.Type of the struct
-	TableName      string            // Database table name (e.g., "products")
-	Fields         []*Field          // Slice of all mapped fields (ordered as in struct)
-	FieldsByName   map[string]*Field // Quick lookup by Go field name ("ProductID")
-	FieldsByDBName map[string]*Field // Quick lookup by DB column name ("product_id")
-	PrimaryKeys    []*Field          // Slice of primary key fields (usually one, but could be composite)
-	Indexes        []*Index          // Slice of all defined indexes (unique and non-unique)
-
-	// --- Relationships (Future) ---
-	// Relations      []*Relation
+	Name            string            // Name of the Go struct (e.g., "Product")
+	Type            reflect.Type      // reflect.Type of the struct
+	TableName       string            // Database table name (e.g., "products")
+	Schema          string            // Schema (database on MySQL) qualifying the table, "" for the connection's default
+	Fields          []*Field          // Slice of all mapped fields (ordered as in struct)
+	FieldsByName    map[string]*Field // Quick lookup by Go field name ("ProductID")
+	FieldsByDBName  map[string]*Field // Quick lookup by DB column name ("product_id")
+	PrimaryKeys     []*Field          // Slice of primary key fields (usually one, but could be composite)
+	Indexes         []*Index          // Slice of all defined indexes (unique and non-unique)
+	ComputedFields  []*Field          // Fields populated by the AfterScan hook (tag "computed"), not mapped to columns
+	Comment         string            // Table comment (Go doc comment of the struct; see WithComments)
+	RetentionField  *Field            // Timestamp column with a retention period (tag "retention"), nil when none
+	CounterCaches   []*Field          // Foreign keys maintaining a counter on the parent row (tag "counterCache")
+	SlugField       *Field            // Slug generated on Create (tag "uniqueSlug"), nil when none
+	SoftDeleteField *Field            // Deletion timestamp of soft deletes (tag "softDelete" or DeletedAt), nil when none
+	RenamedFrom     []string          // Previous table names, most recent first (model tag "renamedFrom")
+
+	Relations []*Relation // Fields holding related records (tag "relation"), in struct order
 
 	// These flags indicate if the model implements the corresponding hook interface.
 	// Checked during parsing.
@@ -84,6 +122,7 @@ type Model struct {
 	HasBeforeDelete bool
 	HasAfterDelete  bool
 	HasAfterFind    bool
+	HasAfterScan    bool
 	// --- End Hook Flags ---
 
 	// --- Internal ---
@@ -2,7 +2,7 @@
 package schema
 
 import (
-	// Need this for sql.Null* types check
+	"database/sql" // Need this for sql.Null* types check
 	"fmt"
 	"reflect"
 	"sort"
@@ -21,17 +21,43 @@ import (
 type Parser struct {
 	cache          sync.Map // Cache[reflect.Type]*Model
 	namingStrategy NamingStrategy
+	strict         bool                // Reject unknown tag options instead of warning
+	defaultSchema  string              // Schema of the tables without an explicit one (see WithDefaultSchema)
+	gormTags       bool                // Fall back to translated `gorm` tags when no `typegorm` tag is present
+	comments       bool                // Populate Model/Field comments from tags and loaded doc comments
+	docs           map[string]typeDocs // Go doc comments by qualified type name ("pkg.Type")
+}
+
+// ParserOption defines a function type that configures a Parser.
+type ParserOption func(*Parser)
+
+// WithStrictTags makes Parse fail with a *TagError when a struct uses unknown tag
+// options (typos like "primaryKy"), instead of only printing a warning.
+func WithStrictTags() ParserOption {
+	return func(p *Parser) { p.strict = true }
+}
+
+// WithDefaultSchema qualifies the tables of models without an explicit schema (see the
+// model tag "table") with schemaName, e.g. "accounting" on Postgres or SQL Server.
+func WithDefaultSchema(schemaName string) ParserOption {
+	return func(p *Parser) { p.defaultSchema = schemaName }
 }
 
 // NewParser creates a new schema parser with the given naming strategy.
 // If namingStrategy is nil, DefaultNamingStrategy (snake_case) is used.
-func NewParser(namingStrategy NamingStrategy) *Parser {
+func NewParser(namingStrategy NamingStrategy, opts ...ParserOption) *Parser {
 	if namingStrategy == nil {
 		namingStrategy = defaultNamingStrategy
 	}
-	return &Parser{
+	p := &Parser{
 		namingStrategy: namingStrategy,
 	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(p)
+		}
+	}
+	return p
 }
 
 // Parse analyzes a struct value or type and returns its ORM schema representation (Model).
@@ -65,26 +91,16 @@ func (p *Parser) Parse(value any) (*Model, error) {
 	}
 	// fmt.Printf("Cache miss for %s, parsing...\n", structType.Name()) // Debug cache
 
-	// Not in cache, parse it
-	model := &Model{
-		Name:            structType.Name(),
-		Type:            structType,
-		Fields:          make([]*Field, 0, structType.NumField()),
-		FieldsByName:    make(map[string]*Field),
-		FieldsByDBName:  make(map[string]*Field),
-		PrimaryKeys:     make([]*Field, 0),
-		Indexes:         make([]*Index, 0),
-		instance:        reflect.New(structType).Interface(),
-		NamingStrategy:  p.namingStrategy,
-		HasBeforeCreate: false,
-		HasAfterCreate:  false,
-		HasBeforeUpdate: false,
-		HasAfterUpdate:  false,
-		HasBeforeDelete: false,
-		HasAfterDelete:  false,
-		HasAfterFind:    false,
+	if p.strict {
+		if err := p.CheckTags(structType); err != nil {
+			return nil, err
+		}
 	}
+
+	// Not in cache, parse it
+	model := newModel(structType.Name(), structType, p.namingStrategy)
 	model.TableName = p.namingStrategy.TableName(model.Name)
+	model.Schema = p.defaultSchema
 
 	// --- Check Hook Interface Implementations ---
 	// *** Use types from the 'hooks' package ***
@@ -95,6 +111,7 @@ func (p *Parser) Parse(value any) (*Model, error) {
 	var beforeDeleterType = reflect.TypeOf((*hooks.BeforeDeleter)(nil)).Elem()
 	var afterDeleterType = reflect.TypeOf((*hooks.AfterDeleter)(nil)).Elem()
 	var afterFinderType = reflect.TypeOf((*hooks.AfterFinder)(nil)).Elem()
+	var afterScannerType = reflect.TypeOf((*hooks.AfterScanner)(nil)).Elem()
 
 	pointerType := reflect.PointerTo(structType)
 
@@ -106,16 +123,21 @@ func (p *Parser) Parse(value any) (*Model, error) {
 	model.HasBeforeDelete = structType.Implements(beforeDeleterType) || pointerType.Implements(beforeDeleterType)
 	model.HasAfterDelete = structType.Implements(afterDeleterType) || pointerType.Implements(afterDeleterType)
 	model.HasAfterFind = structType.Implements(afterFinderType) || pointerType.Implements(afterFinderType)
+	model.HasAfterScan = structType.Implements(afterScannerType) || pointerType.Implements(afterScannerType)
 	// --- End Hook Check ---
 
-	// Temporary maps to build indexes before creating Index structs
-	indexesByName := make(map[string][]*Field)       // map[index_name][]Field
-	uniqueIndexesByName := make(map[string][]*Field) // map[unique_index_name][]Field
-
 	// Iterate through struct fields using NumField() and Field() from reflect.Type
 	for i := 0; i < structType.NumField(); i++ {
 		structField := structType.Field(i)
 
+		// Model options live on a blank field: _ struct{} `typegorm:"table:accounting.invoices"`
+		if structField.Name == "_" {
+			if err := p.parseModelTag(model, structField.Tag.Get("typegorm")); err != nil {
+				return nil, fmt.Errorf("error parsing model tag for %s: %w", model.Name, err)
+			}
+			continue
+		}
+
 		// Skip unexported fields (like fields starting with lowercase letter)
 		if !structField.IsExported() {
 			continue
@@ -125,94 +147,209 @@ func (p *Parser) Parse(value any) (*Model, error) {
 		// This requires recursive parsing or flattening fields.
 		// if structField.Anonymous { ... }
 
-		field := &Field{
-			StructField: structField,
-			GoName:      structField.Name,
-			GoType:      structField.Type,
-			Tags:        make(map[string]string), // Initialize tag map
-		}
-
-		// Initial Nullability Check based on Go Type
-		kind := field.GoType.Kind()
-		field.Nullable = (kind == reflect.Pointer || kind == reflect.Interface || kind == reflect.Map || kind == reflect.Slice)
-		// Check for sql.Null* types
-		if field.GoType.PkgPath() == "database/sql" && strings.HasPrefix(field.GoType.Name(), "Null") {
-			field.Nullable = true
-		}
-		// Check for time.Time (common case, usually not nullable by default)
-		if field.GoType == reflect.TypeOf(time.Time{}) || field.GoType == reflect.TypeOf((*time.Time)(nil)).Elem() {
-			// Nullability depends on whether it's *time.Time (pointer) or time.Time (value)
-			field.Nullable = (kind == reflect.Pointer)
-		}
+		field := newField(structField.Name, structField.Type)
+		field.StructField = structField
 
 		// Parse the 'typegorm' tag
 		tag := structField.Tag.Get("typegorm")
+		if tag == "" && p.gormTags {
+			if gormTag, ok := structField.Tag.Lookup("gorm"); ok {
+				tag = translateGormTag(model.Name+"."+field.GoName, gormTag)
+			}
+		}
 		if err := p.parseTag(field, tag); err != nil {
 			return nil, fmt.Errorf("error parsing tag for field %s.%s: %w", model.Name, field.GoName, err)
 		}
 
-		// Skip ignored fields after tag parsing
+		if _, ok := field.Tags["relation"]; ok {
+			rel, err := parseRelation(field)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing relation %s.%s: %w", model.Name, field.GoName, err)
+			}
+			field.Relation = rel
+			model.Relations = append(model.Relations, rel)
+		} else if tagValue(field.Tags, relationTagKeys...) != "" {
+			return nil, fmt.Errorf("field %s.%s: foreignKey, joinTable, joinForeignKey, joinReferences and mappedBy require a relation tag", model.Name, field.GoName)
+		}
+
+		// Skip ignored fields after tag parsing (computed fields are kept apart)
+		if field.IsComputed {
+			model.ComputedFields = append(model.ComputedFields, field)
+		}
 		if field.IsIgnored {
 			continue
 		}
 
-		// Determine final DB column name
-		if field.DBName == "" { // If not overridden by tag "column:..."
-			field.DBName = p.namingStrategy.ColumnName(field.GoName)
+		if err := p.addField(model, field); err != nil {
+			return nil, err
 		}
+	} // End field loop
 
-		// Finalize Nullability: "not null" tag forces non-nullable.
-		if field.IsRequired { // IsRequired comes from "not null" tag
-			field.Nullable = false
+	if model.SlugField != nil {
+		source, ok := model.FieldsByName[model.SlugField.SlugSource]
+		if !ok {
+			source, ok = model.FieldsByDBName[model.SlugField.SlugSource]
 		}
-
-		// Add field to model collections
-		model.Fields = append(model.Fields, field)
-		if _, exists := model.FieldsByName[field.GoName]; exists {
-			return nil, fmt.Errorf("duplicate Go field name detected: %s in struct %s", field.GoName, model.Name)
+		if !ok || source.GoType.Kind() != reflect.String {
+			return nil, fmt.Errorf("uniqueSlug on %s.%s: source field '%s' must be a string field of the model", model.Name, model.SlugField.GoName, model.SlugField.SlugSource)
 		}
-		model.FieldsByName[field.GoName] = field
+		model.SlugField.SlugSource = source.GoName
+	}
 
-		// Check for DB name collision *before* adding
-		if existingField, exists := model.FieldsByDBName[field.DBName]; exists {
-			return nil, fmt.Errorf("duplicate DB column name '%s' detected (from fields %s and %s) in struct %s",
-				field.DBName, existingField.GoName, field.GoName, model.Name)
+	for _, rel := range model.Relations {
+		if err := resolveRelation(model, rel, p.namingStrategy); err != nil {
+			return nil, err
 		}
-		model.FieldsByDBName[field.DBName] = field
+	}
 
-		// Collect primary keys
-		if field.IsPrimaryKey {
-			field.IsRequired = true
-			field.Nullable = false // Ensure Nullable is false for PKs
-			model.PrimaryKeys = append(model.PrimaryKeys, field)
-		} else {
-			// 2. For non-PK fields, respect the "not null" tag first.
-			if field.IsRequired { // Was set by "not null" tag
-				field.Nullable = false
-			}
-			// 3. Then, respect the "null" tag (explicitly allowing null).
-			if field.Nullable { // Set by "null" tag OR inferred from pointer type
-				field.IsRequired = false // Explicit "null" overrides any default required status
-			}
-			// 4. Default for non-PK, non-pointer/nullable-type fields without tags:
-			// If field.Nullable is still false (e.g., int, string, bool, time.Time)
-			// and field.IsRequired is false (no "not null" tag), we imply NOT NULL.
-			if !field.Nullable && !field.IsRequired {
-				field.IsRequired = true // Default basic value types to NOT NULL
-			}
-		}
+	p.applyDocComments(model)
 
-		// Collect index information temporarily
-		for _, indexName := range field.IndexNames {
-			indexesByName[indexName] = append(indexesByName[indexName], field)
+	if err := p.buildIndexes(model); err != nil {
+		return nil, err
+	}
+
+	if len(model.ComputedFields) > 0 && !model.HasAfterScan {
+		fmt.Printf("Warning: Model %s has computed fields but does not implement AfterScan()\n", model.Name)
+	}
+
+	// Validate primary keys...
+	if len(model.PrimaryKeys) == 0 {
+		fmt.Printf("Warning: No primary key specified via tags for model %s\n", model.Name)
+	}
+
+	// Store in cache
+	p.cache.Store(structType, model)
+	return model, nil
+}
+
+// newModel creates an empty Model ready to receive fields.
+func newModel(name string, structType reflect.Type, namingStrategy NamingStrategy) *Model {
+	model := &Model{
+		Name:           name,
+		Type:           structType,
+		Fields:         make([]*Field, 0),
+		FieldsByName:   make(map[string]*Field),
+		FieldsByDBName: make(map[string]*Field),
+		PrimaryKeys:    make([]*Field, 0),
+		Indexes:        make([]*Index, 0),
+		NamingStrategy: namingStrategy,
+	}
+	if structType != nil {
+		model.instance = reflect.New(structType).Interface()
+	}
+	return model
+}
+
+// newField creates a Field for the given Go name and type, inferring nullability from the type.
+func newField(goName string, goType reflect.Type) *Field {
+	field := &Field{
+		GoName: goName,
+		GoType: goType,
+		Tags:   make(map[string]string), // Initialize tag map
+	}
+
+	// Initial Nullability Check based on Go Type
+	kind := field.GoType.Kind()
+	field.Nullable = (kind == reflect.Pointer || kind == reflect.Interface || kind == reflect.Map || kind == reflect.Slice)
+	// Check for sql.Null* types
+	if field.GoType.PkgPath() == "database/sql" && strings.HasPrefix(field.GoType.Name(), "Null") {
+		field.Nullable = true
+	}
+	// Check for time.Time (common case, usually not nullable by default)
+	if field.GoType == reflect.TypeOf(time.Time{}) || field.GoType == reflect.TypeOf((*time.Time)(nil)).Elem() {
+		// Nullability depends on whether it's *time.Time (pointer) or time.Time (value)
+		field.Nullable = (kind == reflect.Pointer)
+	}
+	return field
+}
+
+// addField finalizes a tag-parsed field and registers it in the model collections.
+func (p *Parser) addField(model *Model, field *Field) error {
+	// Determine final DB column name
+	if field.DBName == "" { // If not overridden by tag "column:..."
+		field.DBName = p.namingStrategy.ColumnName(field.GoName)
+	}
+
+	// Finalize Nullability: "not null" tag forces non-nullable.
+	if field.IsRequired { // IsRequired comes from "not null" tag
+		field.Nullable = false
+	}
+
+	// Add field to model collections
+	if _, exists := model.FieldsByName[field.GoName]; exists {
+		return fmt.Errorf("duplicate Go field name detected: %s in struct %s", field.GoName, model.Name)
+	}
+	// Check for DB name collision *before* adding
+	if existingField, exists := model.FieldsByDBName[field.DBName]; exists {
+		return fmt.Errorf("duplicate DB column name '%s' detected (from fields %s and %s) in struct %s",
+			field.DBName, existingField.GoName, field.GoName, model.Name)
+	}
+	if field.CounterCache != "" && field.References == "" {
+		return fmt.Errorf("counterCache on %s.%s requires a references tag naming the parent table", model.Name, field.GoName)
+	}
+	if field.SlugSource != "" {
+		if field.GoType.Kind() != reflect.String {
+			return fmt.Errorf("uniqueSlug on %s.%s requires a string field, got %s", model.Name, field.GoName, field.GoType)
 		}
-		for _, uniqueIndexName := range field.UniqueIndexNames {
-			uniqueIndexesByName[uniqueIndexName] = append(uniqueIndexesByName[uniqueIndexName], field)
+		if model.SlugField != nil {
+			return fmt.Errorf("model %s declares uniqueSlug on both %s and %s", model.Name, model.SlugField.GoName, field.GoName)
 		}
-	} // End field loop
-
-	// --- Post-processing ---
+		model.SlugField = field
+	}
+	if field.Retention > 0 {
+		if !isTimeType(field.GoType) {
+			return fmt.Errorf("retention on %s.%s requires a time column, got %s", model.Name, field.GoName, field.GoType)
+		}
+		if model.RetentionField != nil {
+			return fmt.Errorf("model %s declares retention on both %s and %s", model.Name, model.RetentionField.GoName, field.GoName)
+		}
+		model.RetentionField = field
+	}
+	if field.GoName == "DeletedAt" && isTimeType(field.GoType) && field.IsNullable() {
+		field.SoftDelete = true // Conventional soft delete column
+	}
+	if field.SoftDelete {
+		if !isTimeType(field.GoType) || !field.IsNullable() {
+			return fmt.Errorf("softDelete on %s.%s requires a nullable time column (*time.Time or sql.NullTime), got %s", model.Name, field.GoName, field.GoType)
+		}
+		if model.SoftDeleteField != nil {
+			return fmt.Errorf("model %s declares softDelete on both %s and %s", model.Name, model.SoftDeleteField.GoName, field.GoName)
+		}
+		model.SoftDeleteField = field
+	}
+	if field.CounterCache != "" {
+		model.CounterCaches = append(model.CounterCaches, field)
+	}
+	model.Fields = append(model.Fields, field)
+	model.FieldsByName[field.GoName] = field
+	model.FieldsByDBName[field.DBName] = field
+
+	// Collect primary keys
+	if field.IsPrimaryKey {
+		field.IsRequired = true
+		field.Nullable = false // Ensure Nullable is false for PKs
+		model.PrimaryKeys = append(model.PrimaryKeys, field)
+	} else {
+		// 2. For non-PK fields, respect the "not null" tag first.
+		if field.IsRequired { // Was set by "not null" tag
+			field.Nullable = false
+		}
+		// 3. Then, respect the "null" tag (explicitly allowing null).
+		if field.Nullable { // Set by "null" tag OR inferred from pointer type
+			field.IsRequired = false // Explicit "null" overrides any default required status
+		}
+		// 4. Default for non-PK, non-pointer/nullable-type fields without tags:
+		// If field.Nullable is still false (e.g., int, string, bool, time.Time)
+		// and field.IsRequired is false (no "not null" tag), we imply NOT NULL.
+		if !field.Nullable && !field.IsRequired {
+			field.IsRequired = true // Default basic value types to NOT NULL
+		}
+	}
+	return nil
+}
 
+// buildIndexes turns the index tags collected on the model fields into Index definitions.
+func (p *Parser) buildIndexes(model *Model) error {
 	indexesMap := make(map[string]*Index) // Temporary map: map[index_name]*Index
 
 	for _, field := range model.Fields {
@@ -220,20 +357,19 @@ func (p *Parser) Parse(value any) (*Model, error) {
 		for _, indexName := range field.IndexNames {
 			if idx, ok := indexesMap[indexName]; ok {
 				if idx.IsUnique {
-					return nil, fmt.Errorf("index name '%s' used for both unique and non-unique indexes", indexName)
+					return fmt.Errorf("index name '%s' used for both unique and non-unique indexes", indexName)
 				}
 				idx.Fields = append(idx.Fields, field)
 			} else {
 				indexesMap[indexName] = &Index{Name: indexName, IsUnique: false, Fields: []*Field{field}}
 			}
-			indexesByName[indexName] = append(indexesByName[indexName], field)
 		}
 		// Process NAMED unique indexes
 		for _, uniqueIndexName := range field.UniqueIndexNames {
 			field.Unique = true // Ensure column-level unique is also true
 			if idx, ok := indexesMap[uniqueIndexName]; ok {
 				if !idx.IsUnique {
-					return nil, fmt.Errorf("index name '%s' used for both unique and non-unique indexes", uniqueIndexName)
+					return fmt.Errorf("index name '%s' used for both unique and non-unique indexes", uniqueIndexName)
 				}
 				idx.Fields = append(idx.Fields, field)
 			} else {
@@ -249,7 +385,7 @@ func (p *Parser) Parse(value any) (*Model, error) {
 				indexesMap[defaultUniqueName] = &Index{Name: defaultUniqueName, IsUnique: true, Fields: []*Field{field}}
 			} else {
 				if !idx.IsUnique {
-					return nil, fmt.Errorf("index name '%s' used for both unique and non-unique indexes", defaultUniqueName)
+					return fmt.Errorf("index name '%s' used for both unique and non-unique indexes", defaultUniqueName)
 				}
 				idx.Fields = append(idx.Fields, field)
 			}
@@ -263,7 +399,7 @@ func (p *Parser) Parse(value any) (*Model, error) {
 				indexesMap[defaultIndexName] = &Index{Name: defaultIndexName, IsUnique: false, Fields: []*Field{field}}
 			} else {
 				if idx.IsUnique {
-					return nil, fmt.Errorf("index name '%s' used for both unique and non-unique indexes", defaultIndexName)
+					return fmt.Errorf("index name '%s' used for both unique and non-unique indexes", defaultIndexName)
 				}
 				idx.Fields = append(idx.Fields, field)
 			}
@@ -278,15 +414,63 @@ func (p *Parser) Parse(value any) (*Model, error) {
 	}
 	// Sort the final list of indexes by name
 	sort.Slice(model.Indexes, func(i, j int) bool { return model.Indexes[i].Name < model.Indexes[j].Name })
+	return nil
+}
 
-	// Validate primary keys...
-	if len(model.PrimaryKeys) == 0 {
-		fmt.Printf("Warning: No primary key specified via tags for model %s\n", model.Name)
+// parseModelTag processes the model options of the `typegorm` tag of a blank (_) field:
+// "table:name" or "table:schema.name" sets the table, "schema:name" only the schema and
+// "renamedFrom:old_name" lists the previous table names.
+func (p *Parser) parseModelTag(model *Model, tag string) error {
+	for _, part := range strings.Split(tag, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(part, ":")
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		switch key {
+		case "table":
+			schemaName, table := SplitQualifiedName(value)
+			if table == "" || strings.Contains(table, ".") {
+				return fmt.Errorf("tag 'table' expects 'name' or 'schema.name', got '%s'", value)
+			}
+			model.TableName = table
+			if schemaName != "" {
+				model.Schema = schemaName
+			}
+		case "schema":
+			if value == "" {
+				return fmt.Errorf("tag 'schema' requires a value")
+			}
+			model.Schema = value
+		case "renamedfrom":
+			names, err := parseNameList("renamedFrom", value)
+			if err != nil {
+				return err
+			}
+			model.RenamedFrom = names
+		default:
+			if p.strict {
+				return fmt.Errorf("unknown model tag key '%s'", key)
+			}
+			fmt.Printf("Warning: Unknown model tag key '%s' for %s\n", key, model.Name)
+		}
 	}
+	return nil
+}
 
-	// Store in cache
-	p.cache.Store(structType, model)
-	return model, nil
+// parseNameList splits the comma-separated names of a tag option ("e_mail,mail").
+func parseNameList(key, value string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("tag '%s' requires at least one name", key)
+	}
+	return names, nil
 }
 
 // parseTag processes the content of the `typegorm` tag string.
@@ -372,6 +556,61 @@ func (p *Parser) parseTag(field *Field, tag string) error {
 			if value != "" {
 				field.UniqueIndexNames = append(field.UniqueIndexNames, value)
 			} // Store explicit name
+		case "anonymize":
+			if value == "" {
+				return fmt.Errorf("tag '%s' requires a value", key)
+			}
+			field.Anonymize = strings.ToLower(value)
+		case "references":
+			if !strings.Contains(value, ".") {
+				return fmt.Errorf("tag '%s' expects 'table.column', got '%s'", key, value)
+			}
+			field.References = value
+		case "autocreatetime", "createdat", "created_at":
+			field.AutoCreateTime = !strings.EqualFold(value, "false")
+			field.ManualTime = !field.AutoCreateTime
+		case "autoupdatetime", "updatedat", "updated_at":
+			field.AutoUpdateTime = !strings.EqualFold(value, "false")
+			field.ManualTime = !field.AutoUpdateTime
+		case "comment":
+			if p.comments {
+				field.Comment = value
+			}
+		case "countercache", "counter_cache":
+			if value == "" {
+				return fmt.Errorf("tag '%s' requires the parent column, e.g. counterCache:posts_count", key)
+			}
+			field.CounterCache = value
+		case "uniqueslug", "unique_slug":
+			if value == "" {
+				return fmt.Errorf("tag '%s' requires the source field, e.g. uniqueSlug:Title", key)
+			}
+			field.SlugSource = value
+			field.Unique = true
+		case "retention":
+			period, err := parseRetention(value)
+			if err != nil {
+				return fmt.Errorf("invalid retention value '%s' for tag '%s': %w", value, key, err)
+			}
+			field.Retention = period
+		case "sensitive":
+			field.Sensitive = true
+		case "softdelete", "soft_delete":
+			field.SoftDelete = true
+		case "renamedfrom":
+			names, err := parseNameList(key, value)
+			if err != nil {
+				return err
+			}
+			field.RenamedFrom = names
+		case "relation":
+			field.IsIgnored = true // Holds related records, not a column (see parseRelation)
+		case "foreignkey", "foreign_key", "jointable", "join_table", "mappedby", "mapped_by",
+			"joinforeignkey", "join_foreign_key", "joinreferences", "join_references":
+			// Read with "relation" (see parseRelation)
+		case "computed":
+			field.IsComputed = true
+			field.IsIgnored = true // Not a column: never selected, inserted or updated
 		case "-":
 			field.IsIgnored = true
 			return nil
@@ -410,3 +649,35 @@ var globalParser = NewParser(nil)
 func Parse(value any) (*Model, error) {
 	return globalParser.Parse(value)
 }
+
+// parseRetention parses a retention period: a Go duration ("720h") or a number of
+// days, weeks or years ("90d", "2w", "1y").
+func parseRetention(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, fmt.Errorf("a period is required, e.g. 90d")
+	}
+	units := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour, 'y': 365 * 24 * time.Hour}
+	if unit, ok := units[value[len(value)-1]]; ok {
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("expected a positive number of %c", value[len(value)-1])
+		}
+		return time.Duration(n) * unit, nil
+	}
+	period, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if period <= 0 {
+		return 0, fmt.Errorf("period must be positive")
+	}
+	return period, nil
+}
+
+// isTimeType reports whether t holds a timestamp (time.Time, *time.Time or sql.NullTime).
+func isTimeType(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t == reflect.TypeOf(time.Time{}) || t == reflect.TypeOf(sql.NullTime{})
+}
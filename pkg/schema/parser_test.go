@@ -2,7 +2,9 @@
 package schema
 
 import (
+	"database/sql"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -232,3 +234,243 @@ func TestParse_Cache(t *testing.T) {
 // - Embedded structs
 // - Tag parsing errors (e.g., invalid size)
 // - Custom naming strategy
+
+type TypoModel struct {
+	ID    uint   `typegorm:"primarykey;autoIncrement"`
+	Email string `typegorm:"uniqe;column:email"`
+	Note  string `typegorm:"frobnicate"`
+}
+
+func TestParse_StrictTags(t *testing.T) {
+	// Lenient by default: unknown options are only logged
+	_, err := NewParser(nil).Parse(&TypoModel{})
+	require.NoError(t, err)
+
+	_, err = NewParser(nil, WithStrictTags()).Parse(&TypoModel{})
+	var tagErr *TagError
+	require.ErrorAs(t, err, &tagErr)
+	assert.Equal(t, []UnknownTag{
+		{Field: "Email", Key: "uniqe", Suggestion: "unique"},
+		{Field: "Note", Key: "frobnicate"},
+	}, tagErr.Unknown)
+
+	assert.NoError(t, NewParser(nil, WithStrictTags()).CheckTags(reflect.TypeOf(TaggedModel{})))
+}
+
+type RetentionModel struct {
+	ID        uint      `typegorm:"primaryKey"`
+	CreatedAt time.Time `typegorm:"retention:90d"`
+}
+
+func TestParse_Retention(t *testing.T) {
+	parser := NewParser(nil)
+	model, err := parser.Parse(&RetentionModel{})
+	require.NoError(t, err)
+	require.NotNil(t, model.RetentionField)
+	assert.Equal(t, "created_at", model.RetentionField.DBName)
+	assert.Equal(t, 90*24*time.Hour, model.RetentionField.Retention)
+
+	type badType struct {
+		ID   uint   `typegorm:"primaryKey"`
+		Name string `typegorm:"retention:30d"`
+	}
+	_, err = parser.Parse(&badType{})
+	assert.ErrorContains(t, err, "requires a time column")
+
+	type badPeriod struct {
+		ID        uint      `typegorm:"primaryKey"`
+		CreatedAt time.Time `typegorm:"retention:soon"`
+	}
+	_, err = parser.Parse(&badPeriod{})
+	assert.ErrorContains(t, err, "invalid retention value")
+
+	for value, want := range map[string]time.Duration{"2w": 14 * 24 * time.Hour, "1y": 365 * 24 * time.Hour, "36h": 36 * time.Hour} {
+		got, err := parseRetention(value)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, value)
+	}
+}
+
+func TestParse_UniqueSlug(t *testing.T) {
+	type article struct {
+		ID    uint   `typegorm:"primaryKey"`
+		Title string `typegorm:"size:100"`
+		Slug  string `typegorm:"uniqueSlug:title"`
+	}
+	model, err := NewParser(nil).Parse(&article{})
+	require.NoError(t, err)
+	require.NotNil(t, model.SlugField)
+	assert.Equal(t, "Title", model.SlugField.SlugSource)
+	assert.True(t, model.SlugField.Unique)
+
+	type missingSource struct {
+		ID   uint   `typegorm:"primaryKey"`
+		Slug string `typegorm:"uniqueSlug:Name"`
+	}
+	_, err = NewParser(nil).Parse(&missingSource{})
+	assert.ErrorContains(t, err, "source field 'Name'")
+}
+
+func TestParse_SoftDelete(t *testing.T) {
+	type note struct {
+		ID        uint `typegorm:"primaryKey"`
+		DeletedAt *time.Time
+	}
+	model, err := NewParser(nil).Parse(&note{})
+	require.NoError(t, err)
+	require.NotNil(t, model.SoftDeleteField)
+	assert.Equal(t, "deleted_at", model.SoftDeleteField.DBName)
+
+	type archived struct {
+		ID         uint         `typegorm:"primaryKey"`
+		ArchivedAt sql.NullTime `typegorm:"softDelete"`
+		DeletedAt  time.Time    // Not nullable: a plain column
+	}
+	model, err = NewParser(nil).Parse(&archived{})
+	require.NoError(t, err)
+	require.NotNil(t, model.SoftDeleteField)
+	assert.Equal(t, "archived_at", model.SoftDeleteField.DBName)
+
+	type badType struct {
+		ID      uint      `typegorm:"primaryKey"`
+		Removed time.Time `typegorm:"softDelete"`
+	}
+	_, err = NewParser(nil).Parse(&badType{})
+	assert.ErrorContains(t, err, "requires a nullable time column")
+}
+
+func TestParse_Sensitive(t *testing.T) {
+	type account struct {
+		ID       uint `typegorm:"primaryKey"`
+		Email    string
+		Password string `typegorm:"sensitive;size:100"`
+	}
+	model, err := NewParser(nil).Parse(&account{})
+	require.NoError(t, err)
+	password, ok := model.GetField("Password")
+	require.True(t, ok)
+	assert.True(t, password.Sensitive)
+	email, _ := model.GetField("Email")
+	assert.False(t, email.Sensitive)
+}
+
+func TestParse_QualifiedTable(t *testing.T) {
+	type invoice struct {
+		_  struct{} `typegorm:"table:accounting.invoices"`
+		ID uint     `typegorm:"primaryKey"`
+	}
+	model, err := NewParser(nil).Parse(&invoice{})
+	require.NoError(t, err)
+	assert.Equal(t, "accounting", model.Schema)
+	assert.Equal(t, "invoices", model.TableName)
+	assert.Equal(t, "accounting.invoices", model.QualifiedTableName())
+	assert.Len(t, model.Fields, 1, "the blank field is not a column")
+
+	type ledger struct {
+		ID uint `typegorm:"primaryKey"`
+	}
+	model, err = NewParser(nil, WithDefaultSchema("finance")).Parse(&ledger{})
+	require.NoError(t, err)
+	assert.Equal(t, "finance.ledgers", model.QualifiedTableName())
+
+	type badTable struct {
+		_  struct{} `typegorm:"table:a.b.c"`
+		ID uint     `typegorm:"primaryKey"`
+	}
+	_, err = NewParser(nil).Parse(&badTable{})
+	assert.ErrorContains(t, err, "schema.name")
+}
+
+func TestParse_RenamedFrom(t *testing.T) {
+	type contact struct {
+		_     struct{} `typegorm:"table:contacts;renamedFrom:people"`
+		ID    uint     `typegorm:"primaryKey"`
+		Email string   `typegorm:"column:email;renamedFrom:e_mail, mail"`
+	}
+	model, err := NewParser(nil).Parse(&contact{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"people"}, model.RenamedFrom)
+	field, ok := model.GetFieldByDBName("email")
+	require.True(t, ok)
+	assert.Equal(t, []string{"e_mail", "mail"}, field.RenamedFrom)
+
+	yamlModels, err := NewParser(nil).LoadYAML(strings.NewReader(`
+tables:
+  - name: contacts
+    renamedFrom: [people]
+    columns:
+      - { name: id, type: uint, primaryKey: true }
+      - { name: email, type: string, renamedFrom: [e_mail] }
+`))
+	require.NoError(t, err)
+	require.Len(t, yamlModels, 1)
+	assert.Equal(t, []string{"people"}, yamlModels[0].RenamedFrom)
+	field, ok = yamlModels[0].GetFieldByDBName("email")
+	require.True(t, ok)
+	assert.Equal(t, []string{"e_mail"}, field.RenamedFrom)
+
+	type empty struct {
+		Email string `typegorm:"renamedFrom:"`
+	}
+	_, err = NewParser(nil).Parse(&empty{})
+	assert.ErrorContains(t, err, "at least one name")
+}
+
+func TestParse_Relations(t *testing.T) {
+	type autor struct {
+		ID uint `typegorm:"primaryKey"`
+	}
+	type perfil struct {
+		ID uint `typegorm:"primaryKey"`
+	}
+	type post struct {
+		ID         uint    `typegorm:"primaryKey"`
+		Autor      *autor  `typegorm:"relation:many-to-one"`
+		AutorID    uint    // Declared after the relation
+		Perfil     perfil  `typegorm:"relation:one-to-one"` // No PerfilID: has-one
+		Revisor    *autor  `typegorm:"relation:one-to-one;foreignKey:RevisorRef"`
+		Tags       []autor `typegorm:"relation:many-to-many;joinTable:post_tags"`
+		Respostas  []*post `typegorm:"relation:one-to-many;mappedBy:Pai"`
+		RevisorRef uint
+	}
+	model, err := NewParser(nil).Parse(&post{})
+	require.NoError(t, err)
+	require.Len(t, model.Relations, 5)
+	_, isColumn := model.GetField("Autor")
+	assert.False(t, isColumn, "Relation fields are not columns")
+
+	rel, ok := model.GetRelation("Autor")
+	require.True(t, ok)
+	assert.Equal(t, RelationManyToOne, rel.Kind)
+	assert.Equal(t, reflect.TypeOf(autor{}), rel.Type)
+	assert.Equal(t, "AutorID", rel.ForeignKey)
+	assert.True(t, rel.BelongsTo)
+
+	rel, _ = model.GetRelation("Perfil")
+	assert.False(t, rel.BelongsTo)
+	assert.Equal(t, "postID", rel.ForeignKey)
+	rel, _ = model.GetRelation("Revisor")
+	assert.True(t, rel.BelongsTo)
+	rel, _ = model.GetRelation("Tags")
+	assert.Equal(t, "post_tags", rel.JoinTable)
+	assert.Equal(t, "post_id", rel.JoinForeignKey)
+	assert.Equal(t, "autor_id", rel.JoinReferences)
+	rel, _ = model.GetRelation("Respostas")
+	assert.Equal(t, "Pai", rel.MappedBy)
+	assert.Empty(t, rel.ForeignKey, "Read from the mappedBy relation when loaded")
+	assert.Equal(t, reflect.TypeOf(post{}), rel.Type)
+
+	type missingKey struct {
+		ID    uint   `typegorm:"primaryKey"`
+		Autor *autor `typegorm:"relation:many-to-one;foreignKey:Dono"`
+	}
+	_, err = NewParser(nil).Parse(&missingKey{})
+	assert.ErrorContains(t, err, "foreign key field 'Dono' not found")
+
+	type badKind struct {
+		ID    uint   `typegorm:"primaryKey"`
+		Autor *autor `typegorm:"relation:belongs-to"`
+	}
+	_, err = NewParser(nil).Parse(&badKind{})
+	assert.ErrorContains(t, err, "invalid relation 'belongs-to'")
+}
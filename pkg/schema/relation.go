@@ -0,0 +1,145 @@
+// pkg/schema/relation.go
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RelationKind is the cardinality of a relation field (tag "relation:many-to-one").
+type RelationKind string
+
+const (
+	RelationManyToOne  RelationKind = "many-to-one"  // Belongs-to: the foreign key is a field of this model
+	RelationOneToOne   RelationKind = "one-to-one"   // Has-one, or belongs-to when the foreign key is a field of this model
+	RelationOneToMany  RelationKind = "one-to-many"  // Has-many: the foreign key is a field of the related model
+	RelationManyToMany RelationKind = "many-to-many" // Through a join table (tag "joinTable")
+)
+
+// Relation describes a field holding related records instead of a column:
+//
+//	type Post struct {
+//		ID      uint `typegorm:"primaryKey"`
+//		AutorID uint
+//		Autor   *User `typegorm:"relation:many-to-one"`              // Foreign key AutorID (default: field name + "ID")
+//		Capa    *Cover `typegorm:"relation:one-to-one;foreignKey:PostID"` // Cover.PostID references Post.ID
+//	}
+type Relation struct {
+	Name       string       // Go field holding the related value(s)
+	Kind       RelationKind // Cardinality
+	Type       reflect.Type // Related struct type
+	ForeignKey string       // Go field holding the key: of this model when BelongsTo, of the related model otherwise (tag "foreignKey")
+	BelongsTo  bool         // The foreign key is a field of this model, referencing the related primary key
+	JoinTable  string       // Join table of many-to-many relations (tag "joinTable")
+	MappedBy   string       // Relation field of the related model on the other side (tag "mappedBy")
+
+	JoinForeignKey string // Join table column referencing this model (tag "joinForeignKey", default <model>_id)
+	JoinReferences string // Join table column referencing the related model (tag "joinReferences", default <related>_id)
+}
+
+// relationTagKeys are the options read together with "relation".
+var relationTagKeys = []string{"foreignkey", "foreign_key", "jointable", "join_table", "mappedby", "mapped_by",
+	"joinforeignkey", "join_foreign_key", "joinreferences", "join_references"}
+
+// parseRelation validates the "relation" tag of a field and returns its relation; the
+// foreign key is resolved by resolveRelation once every field of the model is known.
+func parseRelation(field *Field) (*Relation, error) {
+	value := field.Tags["relation"]
+	kind := RelationKind(strings.ToLower(value))
+	switch kind {
+	case RelationManyToOne, RelationOneToOne, RelationOneToMany, RelationManyToMany:
+	default:
+		return nil, fmt.Errorf("invalid relation '%s' (expected many-to-one, one-to-one, one-to-many or many-to-many)", value)
+	}
+
+	relatedType := field.GoType
+	if kind == RelationOneToMany || kind == RelationManyToMany {
+		if relatedType.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("%s relation requires a slice field, got %s", kind, relatedType)
+		}
+		relatedType = relatedType.Elem()
+	}
+	for relatedType.Kind() == reflect.Pointer {
+		relatedType = relatedType.Elem()
+	}
+	if relatedType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s relation requires a struct type, got %s", kind, field.GoType)
+	}
+
+	rel := &Relation{Name: field.GoName, Kind: kind, Type: relatedType}
+	rel.ForeignKey = tagValue(field.Tags, "foreignkey", "foreign_key")
+	rel.JoinTable = tagValue(field.Tags, "jointable", "join_table")
+	rel.MappedBy = tagValue(field.Tags, "mappedby", "mapped_by")
+	rel.JoinForeignKey = tagValue(field.Tags, "joinforeignkey", "join_foreign_key")
+	rel.JoinReferences = tagValue(field.Tags, "joinreferences", "join_references")
+	if (rel.JoinTable != "" || rel.JoinForeignKey != "" || rel.JoinReferences != "") && kind != RelationManyToMany {
+		return nil, fmt.Errorf("joinTable, joinForeignKey and joinReferences apply to many-to-many relations, not %s", kind)
+	}
+	return rel, nil
+}
+
+// resolveRelation decides which side holds the foreign key:
+//   - many-to-one: this model, in ForeignKey or <Field>ID;
+//   - one-to-one: this model when it has that field, the related model otherwise
+//     (ForeignKey or <Model>ID);
+//   - one-to-many: the related model, in ForeignKey, in the foreign key of its MappedBy
+//     relation, or <Model>ID;
+//   - many-to-many: the join table, in the JoinForeignKey and JoinReferences columns
+//     named after both models.
+//
+// Fields of the related model are checked when the relation is loaded.
+func resolveRelation(model *Model, rel *Relation, naming NamingStrategy) error {
+	switch rel.Kind {
+	case RelationManyToOne:
+		if rel.ForeignKey == "" {
+			rel.ForeignKey = rel.Name + "ID"
+		}
+		if _, ok := model.FieldsByName[rel.ForeignKey]; !ok {
+			return fmt.Errorf("relation %s.%s: foreign key field '%s' not found", model.Name, rel.Name, rel.ForeignKey)
+		}
+		rel.BelongsTo = true
+	case RelationOneToOne:
+		key := rel.ForeignKey
+		if key == "" {
+			key = rel.Name + "ID"
+		}
+		if _, ok := model.FieldsByName[key]; ok {
+			rel.ForeignKey, rel.BelongsTo = key, true
+		} else if rel.ForeignKey == "" {
+			rel.ForeignKey = model.Name + "ID"
+		}
+	case RelationOneToMany:
+		if rel.ForeignKey == "" && rel.MappedBy == "" { // Otherwise read from the related model when loaded
+			rel.ForeignKey = model.Name + "ID"
+		}
+	case RelationManyToMany:
+		if rel.JoinForeignKey == "" {
+			rel.JoinForeignKey = naming.ColumnName(model.Name + "ID")
+		}
+		if rel.JoinReferences == "" {
+			rel.JoinReferences = naming.ColumnName(rel.Type.Name() + "ID")
+		}
+	}
+	return nil
+}
+
+// tagValue returns the value of the first of keys present in tags.
+func tagValue(tags map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := tags[key]; ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// GetRelation retrieves a relation by the name of its Go field.
+func (m *Model) GetRelation(name string) (*Relation, bool) {
+	for _, rel := range m.Relations {
+		if rel.Name == name {
+			return rel, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,142 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// --- Tag Validation ---
+
+// knownTagKeys lists the options understood by parseTag, in their documented spelling.
+// Matching is case-insensitive; keep this list in sync with the switch in parseTag.
+var knownTagKeys = []string{
+	"primaryKey", "primary_key", "pk", "autoIncrement", "auto_increment",
+	"column", "name", "type", "size", "precision", "scale",
+	"notNull", "not null", "required", "null", "unique", "default",
+	"index", "uniqueIndex", "unique_index", "anonymize", "references",
+	"autoCreateTime", "autoUpdateTime", "createdAt", "created_at", "updatedAt", "updated_at", "comment", "computed", "retention",
+	"counterCache", "counter_cache", "uniqueSlug", "unique_slug", "sensitive", "renamedFrom",
+	"softDelete", "soft_delete", "relation", "foreignKey", "foreign_key", "joinTable", "join_table",
+	"mappedBy", "mapped_by", "joinForeignKey", "join_foreign_key", "joinReferences", "join_references", "-",
+}
+
+// UnknownTag describes an unrecognized option found in a `typegorm` tag.
+type UnknownTag struct {
+	Field      string // Go field name
+	Key        string // Option as written in the tag
+	Suggestion string // Closest known option, empty when nothing is close
+}
+
+// TagError is returned by CheckTags (and Parse in strict mode) listing every
+// unrecognized tag option of a model.
+type TagError struct {
+	Model   string
+	Unknown []UnknownTag
+}
+
+func (e *TagError) Error() string {
+	parts := make([]string, len(e.Unknown))
+	for i, u := range e.Unknown {
+		parts[i] = fmt.Sprintf("%s: '%s'", u.Field, u.Key)
+		if u.Suggestion != "" {
+			parts[i] += fmt.Sprintf(" (did you mean '%s'?)", u.Suggestion)
+		}
+	}
+	return fmt.Sprintf("model %s has unknown tag options: %s", e.Model, strings.Join(parts, "; "))
+}
+
+// CheckTags validates the `typegorm` tags of a struct (value, pointer or reflect.Type)
+// and returns a *TagError listing every unknown option with a suggestion, or nil.
+// It does not parse or cache the model.
+func (p *Parser) CheckTags(value any) error {
+	structType, ok := value.(reflect.Type)
+	if !ok {
+		structType = reflect.TypeOf(value)
+	}
+	for structType != nil && structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType == nil || structType.Kind() != reflect.Struct {
+		return fmt.Errorf("input must be a struct instance or pointer to struct, got %T", value)
+	}
+
+	tagErr := &TagError{Model: structType.Name()}
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+		tag := structField.Tag.Get("typegorm")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		for _, part := range strings.Split(tag, ";") {
+			key := strings.TrimSpace(strings.SplitN(part, ":", 2)[0])
+			if key == "" || isKnownTagKey(key) {
+				continue
+			}
+			tagErr.Unknown = append(tagErr.Unknown, UnknownTag{
+				Field:      structField.Name,
+				Key:        key,
+				Suggestion: suggestTagKey(key),
+			})
+		}
+	}
+	if len(tagErr.Unknown) > 0 {
+		return tagErr
+	}
+	return nil
+}
+
+func isKnownTagKey(key string) bool {
+	for _, known := range knownTagKeys {
+		if strings.EqualFold(key, known) {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestTagKey returns the known option closest to key (edit distance of at most 2,
+// 1 for short keys; ignoring case, '_' and spaces), or "".
+func suggestTagKey(key string) string {
+	normalize := func(s string) string {
+		return strings.NewReplacer("_", "", " ", "").Replace(strings.ToLower(s))
+	}
+	target := normalize(key)
+	best, bestDistance := "", 3
+	if len(target) < 5 {
+		bestDistance = 2
+	}
+	for _, known := range knownTagKeys {
+		if strings.ContainsAny(known, "_ ") || known == "-" {
+			continue // Suggest the canonical spelling only
+		}
+		if d := editDistance(target, normalize(known)); d < bestDistance {
+			best, bestDistance = known, d
+		}
+	}
+	return best
+}
+
+// editDistance computes the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,265 @@
+// pkg/schema/yaml.go
+package schema
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// --- Declarative Schema Files ---
+
+// SchemaFile is the root of a declarative schema document, an alternative to Go
+// structs for teams that keep their schema in YAML:
+//
+//	tables:
+//	  - name: users
+//	    columns:
+//	      - { name: id, type: uint, primaryKey: true, autoIncrement: true }
+//	      - { name: email, type: string, size: 255, unique: true }
+//	      - { name: bio, type: string, nullable: true }
+//	    indexes:
+//	      - { name: idx_users_email_bio, columns: [email, bio] }
+type SchemaFile struct {
+	Tables []TableDefinition `yaml:"tables"`
+}
+
+// TableDefinition declares one table.
+type TableDefinition struct {
+	Name        string             `yaml:"name"`
+	Comment     string             `yaml:"comment"`
+	RenamedFrom []string           `yaml:"renamedFrom"` // Previous table names, most recent first
+	Columns     []ColumnDefinition `yaml:"columns"`
+	Indexes     []IndexDefinition  `yaml:"indexes"`
+}
+
+// ColumnDefinition declares one column. Type is a portable type name
+// (string, int, int64, uint, uint64, bool, float32, float64, time, bytes);
+// SQLType overrides the generated SQL type entirely.
+type ColumnDefinition struct {
+	Name          string   `yaml:"name"`
+	Type          string   `yaml:"type"`
+	SQLType       string   `yaml:"sqlType"`
+	Size          int      `yaml:"size"`
+	Precision     int      `yaml:"precision"`
+	Scale         int      `yaml:"scale"`
+	PrimaryKey    bool     `yaml:"primaryKey"`
+	AutoIncrement bool     `yaml:"autoIncrement"`
+	Nullable      bool     `yaml:"nullable"`
+	Unique        bool     `yaml:"unique"`
+	Index         bool     `yaml:"index"`
+	Default       *string  `yaml:"default"`
+	Anonymize     string   `yaml:"anonymize"`
+	References    string   `yaml:"references"` // "table.column"
+	Comment       string   `yaml:"comment"`
+	Retention     string   `yaml:"retention"`   // e.g. "90d"; only for time columns
+	RenamedFrom   []string `yaml:"renamedFrom"` // Previous column names, most recent first
+}
+
+// IndexDefinition declares a (possibly composite) index on a table.
+type IndexDefinition struct {
+	Name    string   `yaml:"name"`
+	Columns []string `yaml:"columns"`
+	Unique  bool     `yaml:"unique"`
+}
+
+// yamlColumnTypes maps portable type names to the Go types the dialects understand.
+var yamlColumnTypes = map[string]reflect.Type{
+	"string":  reflect.TypeOf(""),
+	"text":    reflect.TypeOf(""),
+	"int":     reflect.TypeOf(int(0)),
+	"int32":   reflect.TypeOf(int32(0)),
+	"int64":   reflect.TypeOf(int64(0)),
+	"uint":    reflect.TypeOf(uint(0)),
+	"uint32":  reflect.TypeOf(uint32(0)),
+	"uint64":  reflect.TypeOf(uint64(0)),
+	"bool":    reflect.TypeOf(false),
+	"float32": reflect.TypeOf(float32(0)),
+	"float64": reflect.TypeOf(float64(0)),
+	"time":    reflect.TypeOf(time.Time{}),
+	"bytes":   reflect.TypeOf([]byte(nil)),
+}
+
+// LoadYAMLFile reads a declarative schema file and returns its models.
+func LoadYAMLFile(path string) ([]*Model, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open schema file %s: %w", path, err)
+	}
+	defer f.Close()
+	return LoadYAML(f)
+}
+
+// LoadYAML decodes a declarative schema document using the global parser.
+func LoadYAML(r io.Reader) ([]*Model, error) {
+	return globalParser.LoadYAML(r)
+}
+
+// LoadYAML decodes a declarative schema document into models. The resulting models
+// go through the same validation as struct-based ones, but have no Go type
+// (Model.Type is nil), so they can be used for DDL generation and migration, not CRUD.
+func (p *Parser) LoadYAML(r io.Reader) ([]*Model, error) {
+	var file SchemaFile
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true) // Typos in keys are errors, not silently ignored
+	if err := decoder.Decode(&file); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to decode schema file: %w", err)
+	}
+	return p.ParseDefinitions(file)
+}
+
+// ParseDefinitions converts declarative table definitions into models.
+func (p *Parser) ParseDefinitions(file SchemaFile) ([]*Model, error) {
+	models := make([]*Model, 0, len(file.Tables))
+	seen := make(map[string]bool)
+	for _, table := range file.Tables {
+		if table.Name == "" {
+			return nil, fmt.Errorf("schema file: table without name")
+		}
+		if seen[table.Name] {
+			return nil, fmt.Errorf("schema file: duplicate table '%s'", table.Name)
+		}
+		seen[table.Name] = true
+
+		model, err := p.parseTableDefinition(table)
+		if err != nil {
+			return nil, fmt.Errorf("schema file: table '%s': %w", table.Name, err)
+		}
+		models = append(models, model)
+	}
+	return models, nil
+}
+
+func (p *Parser) parseTableDefinition(table TableDefinition) (*Model, error) {
+	model := newModel(goNameFromColumn(table.Name), nil, p.namingStrategy)
+	model.Schema, model.TableName = SplitQualifiedName(table.Name)
+	if model.Schema == "" {
+		model.Schema = p.defaultSchema
+	}
+	if p.comments {
+		model.Comment = table.Comment
+	}
+	model.RenamedFrom = table.RenamedFrom
+
+	for _, col := range table.Columns {
+		if col.Name == "" {
+			return nil, fmt.Errorf("column without name")
+		}
+		goType, ok := yamlColumnTypes[strings.ToLower(col.Type)]
+		if !ok {
+			if col.SQLType == "" {
+				return nil, fmt.Errorf("column '%s': unknown type '%s'", col.Name, col.Type)
+			}
+			goType = reflect.TypeOf("") // Only used for nullability; SQLType wins
+		}
+		if col.Nullable && goType.Kind() != reflect.Slice {
+			goType = reflect.PointerTo(goType)
+		}
+
+		field := newField(goNameFromColumn(col.Name), goType)
+		if err := p.parseTag(field, col.tag()); err != nil {
+			return nil, fmt.Errorf("column '%s': %w", col.Name, err)
+		}
+		if p.comments {
+			field.Comment = col.Comment // Set directly: comments may contain ';'
+		}
+		if err := p.addField(model, field); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, idx := range table.Indexes {
+		if idx.Name == "" || len(idx.Columns) == 0 {
+			return nil, fmt.Errorf("index definitions need a name and at least one column")
+		}
+		for _, colName := range idx.Columns {
+			field, ok := model.FieldsByDBName[colName]
+			if !ok {
+				return nil, fmt.Errorf("index '%s' references unknown column '%s'", idx.Name, colName)
+			}
+			if idx.Unique {
+				field.IsUniqueIndex = true
+				field.UniqueIndexNames = append(field.UniqueIndexNames, idx.Name)
+			} else {
+				field.IsIndex = true
+				field.IndexNames = append(field.IndexNames, idx.Name)
+			}
+		}
+	}
+
+	if err := p.buildIndexes(model); err != nil {
+		return nil, err
+	}
+	if len(model.PrimaryKeys) == 0 {
+		fmt.Printf("Warning: No primary key declared for table %s\n", model.TableName)
+	}
+	return model, nil
+}
+
+// tag renders the column definition as an equivalent `typegorm` struct tag,
+// so declarative columns follow exactly the same rules as tagged struct fields.
+func (c ColumnDefinition) tag() string {
+	parts := []string{"column:" + c.Name}
+	if c.SQLType != "" {
+		parts = append(parts, "type:"+c.SQLType)
+	}
+	if c.Size > 0 {
+		parts = append(parts, "size:"+strconv.Itoa(c.Size))
+	}
+	if c.Precision > 0 {
+		parts = append(parts, "precision:"+strconv.Itoa(c.Precision))
+	}
+	if c.Scale > 0 {
+		parts = append(parts, "scale:"+strconv.Itoa(c.Scale))
+	}
+	if c.PrimaryKey {
+		parts = append(parts, "primaryKey")
+	}
+	if c.AutoIncrement {
+		parts = append(parts, "autoIncrement")
+	}
+	if c.Nullable {
+		parts = append(parts, "null")
+	}
+	if c.Unique {
+		parts = append(parts, "unique")
+	}
+	if c.Index {
+		parts = append(parts, "index")
+	}
+	if c.Default != nil {
+		parts = append(parts, "default:"+*c.Default)
+	}
+	if c.Anonymize != "" {
+		parts = append(parts, "anonymize:"+c.Anonymize)
+	}
+	if c.References != "" {
+		parts = append(parts, "references:"+c.References)
+	}
+	if c.Retention != "" {
+		parts = append(parts, "retention:"+c.Retention)
+	}
+	if len(c.RenamedFrom) > 0 {
+		parts = append(parts, "renamedFrom:"+strings.Join(c.RenamedFrom, ","))
+	}
+	return strings.Join(parts, ";")
+}
+
+// goNameFromColumn converts snake_case names to CamelCase Go-style names.
+func goNameFromColumn(name string) string {
+	var sb strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		sb.WriteString(strings.ToUpper(part[:1]))
+		sb.WriteString(part[1:])
+	}
+	return sb.String()
+}
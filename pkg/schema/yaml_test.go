@@ -0,0 +1,78 @@
+// pkg/schema/yaml_test.go
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchemaYAML = `
+tables:
+  - name: blog_posts
+    columns:
+      - { name: id, type: uint, primaryKey: true, autoIncrement: true }
+      - { name: title, type: string, size: 200 }
+      - { name: slug, type: string, size: 200, unique: true }
+      - { name: summary, type: string, nullable: true }
+      - { name: author_id, type: uint64 }
+      - { name: status, type: string, sqlType: "VARCHAR(20)", default: "'draft'" }
+    indexes:
+      - { name: idx_posts_author_status, columns: [author_id, status] }
+`
+
+func TestLoadYAML(t *testing.T) {
+	models, err := NewParser(nil).LoadYAML(strings.NewReader(testSchemaYAML))
+	require.NoError(t, err)
+	require.Len(t, models, 1)
+
+	model := models[0]
+	assert.Equal(t, "blog_posts", model.TableName)
+	assert.Equal(t, "BlogPosts", model.Name)
+	assert.Nil(t, model.Type, "declarative models have no Go type")
+	require.Len(t, model.Fields, 6)
+	require.Len(t, model.PrimaryKeys, 1)
+	assert.True(t, model.PrimaryKeys[0].AutoIncrement)
+
+	title, ok := model.GetFieldByDBName("title")
+	require.True(t, ok)
+	assert.Equal(t, 200, title.Size)
+	assert.True(t, title.IsRequired)
+
+	summary, ok := model.GetFieldByDBName("summary")
+	require.True(t, ok)
+	assert.True(t, summary.IsNullable())
+	assert.Equal(t, reflect.Pointer, summary.GoType.Kind())
+
+	status, ok := model.GetFieldByDBName("status")
+	require.True(t, ok)
+	assert.Equal(t, "VARCHAR(20)", status.SQLType)
+	require.NotNil(t, status.DefaultValue)
+	assert.Equal(t, "'draft'", *status.DefaultValue)
+
+	indexNames := make([]string, 0, len(model.Indexes))
+	for _, idx := range model.Indexes {
+		indexNames = append(indexNames, idx.Name)
+	}
+	assert.Equal(t, []string{"idx_posts_author_status", "uix_blog_posts_slug"}, indexNames)
+	assert.Len(t, model.Indexes[0].Fields, 2)
+}
+
+func TestLoadYAML_Errors(t *testing.T) {
+	tests := map[string]string{
+		"unknown type":   "tables:\n  - name: t\n    columns:\n      - { name: a, type: money }\n",
+		"unknown key":    "tables:\n  - name: t\n    colums: []\n",
+		"bad index":      "tables:\n  - name: t\n    columns:\n      - { name: a, type: int }\n    indexes:\n      - { name: i, columns: [b] }\n",
+		"duplicate":      "tables:\n  - name: t\n  - name: t\n",
+		"duplicate cols": "tables:\n  - name: t\n    columns:\n      - { name: a, type: int }\n      - { name: a, type: int }\n",
+	}
+	for name, doc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewParser(nil).LoadYAML(strings.NewReader(doc))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+)
+
+// --- After-commit Callbacks ---
+
+// AfterCommit registers fn to run once the transaction has committed successfully,
+// e.g. to send emails or invalidate caches only for changes that were persisted.
+// Functions run in registration order, in the goroutine calling Commit; they are
+// dropped if the transaction rolls back.
+func (tx *Tx) AfterCommit(fn func()) {
+	if fn != nil {
+		tx.afterCommit = append(tx.afterCommit, fn)
+	}
+}
+
+// AfterRollback registers fn to run once the transaction has been rolled back,
+// including when Commit fails or the watchdog rolled it back.
+func (tx *Tx) AfterRollback(fn func()) {
+	if fn != nil {
+		tx.afterRollback = append(tx.afterRollback, fn)
+	}
+}
+
+// finish runs the functions registered for the outcome of the transaction and
+// discards the others. A panicking function does not prevent the next ones from running.
+func (tx *Tx) finish(committed bool) {
+	pending := tx.afterRollback
+	if committed {
+		pending = tx.afterCommit
+	}
+	tx.afterCommit, tx.afterRollback = nil, nil
+	for _, fn := range pending {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					fmt.Printf("Warning: after-commit/rollback function panicked: %v\n", r)
+				}
+			}()
+			fn()
+		}()
+	}
+}
+
+// OnCommit makes an After* callback that fires inside a transaction wait until the
+// transaction commits; it is skipped if the transaction rolls back. Outside of a
+// transaction it runs inline as usual. Set hooks.deferInTransaction to apply this to
+// all After* callbacks, including the model's own hook methods.
+//
+// A deferred callback gets the DB (not the finished Tx) as HookContext.DB, and its
+// errors are logged since the operation has already completed.
+func OnCommit() CallbackOption {
+	return func(e *callbackEntry) { e.deferred = true }
+}
+
+// deferToCommit registers the callback entry to run for hc once tx commits.
+func deferToCommit(ctx context.Context, registry *CallbackRegistry, entry *callbackEntry, hc *HookContext, tx *Tx) {
+	snapshot := *hc
+	if registry.db != nil {
+		snapshot.DB = registry.db
+	}
+	detached := context.WithoutCancel(ctx) // The request may be gone by the time Commit runs
+
+	tx.AfterCommit(func() {
+		var err error
+		if entry.fn == nil {
+			err = callModelHook(detached, &snapshot)
+		} else {
+			err = entry.fn(detached, &snapshot)
+		}
+		if err != nil {
+			fmt.Printf("Warning: deferred %s callback '%s' failed: %v\n", snapshot.Event, entry.name, err)
+		}
+	})
+}
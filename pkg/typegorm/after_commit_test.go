@@ -0,0 +1,91 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/hooks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTx_AfterCommitAndAfterRollback(t *testing.T) {
+	db, _ := newMockDB()
+	ctx := context.Background()
+	var events []string
+
+	tx, err := db.Begin(ctx, nil)
+	require.NoError(t, err)
+	tx.AfterCommit(func() { events = append(events, "commit 1") })
+	tx.AfterCommit(func() { panic("boom") })
+	tx.AfterCommit(func() { events = append(events, "commit 2") })
+	tx.AfterRollback(func() { events = append(events, "rollback A") })
+	assert.Empty(t, events, "nothing runs before the transaction ends")
+	require.NoError(t, tx.Commit())
+	require.NoError(t, tx.Rollback()) // Deferred Rollback after Commit must not fire anything
+	assert.Equal(t, []string{"commit 1", "commit 2"}, events)
+
+	events = nil
+	tx, err = db.Begin(ctx, nil)
+	require.NoError(t, err)
+	tx.AfterCommit(func() { events = append(events, "commit B") })
+	tx.AfterRollback(func() { events = append(events, "rollback B") })
+	require.NoError(t, tx.Rollback())
+	assert.Equal(t, []string{"rollback B"}, events)
+}
+
+func TestOnCommitCallbacks_DeferredInTransaction(t *testing.T) {
+	db, _ := newMockDB()
+	ctx := context.Background()
+
+	var notified []string
+	require.NoError(t, db.Callbacks().Register(EventAfterUpdate, "notify", func(ctx context.Context, hc *HookContext) error {
+		notified = append(notified, hc.Value.(*maskUser).Name)
+		assert.Same(t, db, hc.DB, "deferred callbacks get the DB, not the finished Tx")
+		return nil
+	}, OnCommit()))
+
+	tx, err := db.Begin(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.Updates(ctx, &maskUser{ID: 1, Name: "Ana"}, map[string]any{"name": "Ana"}).Error)
+	assert.Empty(t, notified, "not run before commit")
+	require.NoError(t, tx.Commit())
+	assert.Equal(t, []string{"Ana"}, notified)
+
+	tx, err = db.Begin(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.Updates(ctx, &maskUser{ID: 2, Name: "Bia"}, map[string]any{"name": "Bia"}).Error)
+	require.NoError(t, tx.Rollback())
+
+	require.NoError(t, db.Updates(ctx, &maskUser{ID: 3, Name: "Eva"}, map[string]any{"name": "Eva"}).Error)
+	assert.Equal(t, []string{"Ana", "Eva"}, notified, "runs inline outside of transactions, skipped on rollback")
+
+	noop := func(ctx context.Context, hc *HookContext) error { return nil }
+	assert.ErrorContains(t, db.Callbacks().Register(EventBeforeUpdate, "n", noop, OnCommit()), "only After* callbacks")
+}
+
+type commitHookUser struct {
+	ID   uint `typegorm:"primaryKey;autoIncrement"`
+	Name string
+	log  *[]string
+}
+
+func (u *commitHookUser) AfterCreate(ctx context.Context, db hooks.ContextDB) error {
+	*u.log = append(*u.log, "model:"+u.Name)
+	return nil
+}
+
+func TestDeferInTransaction_DefersModelHooks(t *testing.T) {
+	db, _ := newMockDB()
+	db.callbacks.deferAll = true
+	ctx := context.Background()
+	var log []string
+
+	tx, err := db.Begin(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.Create(ctx, &commitHookUser{Name: "Ana", log: &log}).Error)
+	assert.Empty(t, log)
+	require.NoError(t, tx.Commit())
+	assert.Equal(t, []string{"model:Ana"}, log)
+}
@@ -0,0 +1,338 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Cascading Create ---
+
+// WithAssociations makes Create also insert the records held by the relation fields
+// (tag "relation") of the value, in one transaction (a savepoint inside a Tx), wiring
+// the foreign keys:
+//
+//	user := User{Nome: "Ana", Perfil: &Perfil{Bio: "..."}, Posts: []Post{{Titulo: "Oi"}}}
+//	db.Create(ctx, &user, typegorm.WithAssociations())
+//	// INSERT INTO users ...; INSERT INTO perfis (..., user_id) ...; INSERT INTO posts (..., user_id) ...
+//
+// Many-to-one and belongs-to records are inserted before the value, the others after
+// it; many-to-many records are linked through rows of the join table. Associated
+// records are cascaded as well. Records that already exist are not inserted (only
+// linked, for many-to-many): those with an auto-increment primary key set, and those
+// with a preset key (UUIDs, natural keys) found in their table, which costs one SELECT
+// per such record. names restricts the cascade to the
+// given relations of the value; without names every populated relation is created.
+// The other options apply to the value only.
+func WithAssociations(names ...string) CreateOption {
+	return func(opts *createOptions) {
+		opts.associations = true
+		opts.associationNames = append(opts.associationNames, names...)
+	}
+}
+
+// withoutAssociations appends an option disabling the cascade to opts.
+func withoutAssociations(opts []CreateOption) []CreateOption {
+	return append(opts[:len(opts):len(opts)], func(o *createOptions) {
+		o.associations, o.associationNames = false, nil
+	})
+}
+
+// createWithAssociations creates value and its associations through tx.
+func createWithAssociations(ctx context.Context, tx *Tx, value any, opts []CreateOption, names []string) *Result {
+	record := reflect.ValueOf(value)
+	if record.Kind() != reflect.Pointer || record.IsNil() || record.Elem().Kind() != reflect.Struct {
+		return &Result{Error: fmt.Errorf("input value must be a non-nil pointer to a struct, got %T", value)}
+	}
+	model, err := tx.parser.Parse(value)
+	if err != nil {
+		return &Result{Error: fmt.Errorf("failed to parse schema for type %T: %w", value, err)}
+	}
+	relations := model.Relations
+	if len(names) > 0 {
+		relations = nil
+		for _, name := range names {
+			rel, ok := model.GetRelation(name)
+			if !ok {
+				return &Result{Error: fmt.Errorf("with associations: %s has no relation named '%s'", model.Name, name)}
+			}
+			relations = append(relations, rel)
+		}
+	}
+	c := &cascade{tx: tx, visited: make(map[cascadeKey]bool)}
+	return c.create(ctx, model, record, relations, func(ctx context.Context) *Result {
+		return tx.Create(ctx, value, withoutAssociations(opts)...)
+	})
+}
+
+// cascade inserts a graph of records; visited holds the records already handled, so
+// that back references (a Post pointing to its Autor) do not loop.
+type cascade struct {
+	tx      *Tx
+	visited map[cascadeKey]bool
+}
+
+// cascadeKey identifies a record by address and type (a struct shares the address of
+// its first field).
+type cascadeKey struct {
+	typ  reflect.Type
+	addr uintptr
+}
+
+func (c *cascade) seen(record reflect.Value) bool {
+	return c.visited[cascadeKey{record.Type(), record.Pointer()}]
+}
+
+// create inserts record (a pointer to a struct of model) with insert, the records of
+// relations before or after it.
+func (c *cascade) create(ctx context.Context, model *schema.Model, record reflect.Value, relations []*schema.Relation, insert func(context.Context) *Result) *Result {
+	c.visited[cascadeKey{record.Type(), record.Pointer()}] = true
+	elem := record.Elem()
+
+	// Belongs-to: the value holds the foreign key of the related record
+	for _, rel := range relations {
+		if !rel.BelongsTo {
+			continue
+		}
+		related := relatedRecords(elem.FieldByName(rel.Name))
+		if len(related) == 0 {
+			continue
+		}
+		relatedModel, err := c.tx.parser.Parse(related[0].Interface())
+		if err != nil {
+			return &Result{Error: fmt.Errorf("create %s.%s: %w", model.Name, rel.Name, err)}
+		}
+		if err := c.createRecord(ctx, relatedModel, related[0]); err != nil {
+			return &Result{Error: fmt.Errorf("create %s.%s: %w", model.Name, rel.Name, err)}
+		}
+		if len(relatedModel.PrimaryKeys) != 1 {
+			return &Result{Error: fmt.Errorf("create %s.%s: %s must have a single-column primary key", model.Name, rel.Name, relatedModel.Name)}
+		}
+		key := related[0].Elem().FieldByName(relatedModel.PrimaryKeys[0].GoName)
+		if err := setRelationKey(elem.FieldByName(rel.ForeignKey), key); err != nil {
+			return &Result{Error: fmt.Errorf("create %s.%s: %w", model.Name, rel.Name, err)}
+		}
+	}
+
+	result := insert(ctx)
+	if result.Error != nil {
+		return result
+	}
+
+	for _, rel := range relations {
+		if rel.BelongsTo {
+			continue
+		}
+		related := relatedRecords(elem.FieldByName(rel.Name))
+		if len(related) == 0 {
+			continue
+		}
+		var err error
+		if rel.Kind == schema.RelationManyToMany {
+			err = c.link(ctx, model, elem, rel, related)
+		} else {
+			err = c.createChildren(ctx, model, elem, rel, related)
+		}
+		if err != nil {
+			return &Result{Error: fmt.Errorf("create %s.%s: %w", model.Name, rel.Name, err)}
+		}
+	}
+	return result
+}
+
+// createRecord inserts an associated record and its own associations, unless it
+// exists (see exists) or was already handled.
+func (c *cascade) createRecord(ctx context.Context, model *schema.Model, record reflect.Value) error {
+	if c.seen(record) {
+		return nil
+	}
+	if exists, err := c.exists(ctx, model, record); err != nil || exists {
+		return err
+	}
+	return c.insert(ctx, model, record)
+}
+
+// insert inserts an associated record known to be new, and its own associations.
+func (c *cascade) insert(ctx context.Context, model *schema.Model, record reflect.Value) error {
+	return c.create(ctx, model, record, model.Relations, func(ctx context.Context) *Result {
+		return c.tx.Create(ctx, record.Interface())
+	}).Error
+}
+
+// createChildren sets the foreign key of the has-one/has-many records to the primary
+// key of the parent, then inserts them.
+func (c *cascade) createChildren(ctx context.Context, model *schema.Model, parent reflect.Value, rel *schema.Relation, children []reflect.Value) error {
+	if len(model.PrimaryKeys) != 1 {
+		return fmt.Errorf("%s must have a single-column primary key", model.Name)
+	}
+	relatedModel, err := c.tx.parser.Parse(children[0].Interface())
+	if err != nil {
+		return err
+	}
+	foreignKey, err := relationForeignKey(relatedModel, rel)
+	if err != nil {
+		return err
+	}
+	key := parent.FieldByName(model.PrimaryKeys[0].GoName)
+	for _, child := range children {
+		if c.seen(child) {
+			continue
+		}
+		if exists, err := c.exists(ctx, relatedModel, child); err != nil {
+			return err
+		} else if exists {
+			continue
+		}
+		if err := setRelationKey(child.Elem().FieldByName(foreignKey.GoName), key); err != nil {
+			return err
+		}
+		if err := c.insert(ctx, relatedModel, child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// link inserts the many-to-many records that do not exist yet, then the rows of the
+// join table pairing them with the parent.
+func (c *cascade) link(ctx context.Context, model *schema.Model, parent reflect.Value, rel *schema.Relation, related []reflect.Value) error {
+	relatedModel, err := c.tx.parser.Parse(related[0].Interface())
+	if err != nil {
+		return err
+	}
+	if len(model.PrimaryKeys) != 1 || len(relatedModel.PrimaryKeys) != 1 {
+		return fmt.Errorf("%s and %s must have a single-column primary key", model.Name, relatedModel.Name)
+	}
+	joinTable, err := relationJoinTable(relatedModel, rel)
+	if err != nil {
+		return err
+	}
+
+	ownKey, ok := virtualKey(parent.FieldByName(model.PrimaryKeys[0].GoName))
+	if !ok {
+		return fmt.Errorf("%s has no primary key value after insert", model.Name)
+	}
+	dialect := c.tx.dialect
+	var tuples []string
+	var args []any
+	for _, record := range related {
+		if err := c.createRecord(ctx, relatedModel, record); err != nil {
+			return err
+		}
+		refKey, ok := virtualKey(record.Elem().FieldByName(relatedModel.PrimaryKeys[0].GoName))
+		if !ok {
+			return fmt.Errorf("%s has no primary key value after insert", relatedModel.Name)
+		}
+		tuples = append(tuples, fmt.Sprintf("(%s, %s)", dialect.BindVar(len(args)+1), dialect.BindVar(len(args)+2)))
+		args = append(args, ownKey, refKey)
+	}
+	stmt := fmt.Sprintf("INSERT INTO %s (%s, %s) VALUES %s", quoteQualified(dialect, joinTable),
+		dialect.Quote(rel.JoinForeignKey), dialect.Quote(rel.JoinReferences), strings.Join(tuples, ", "))
+	logArgs := redactArgs(model, args, parent.Addr().Interface())
+	for _, record := range related {
+		logArgs = redactArgs(relatedModel, logArgs, record.Interface())
+	}
+	logSQL("Executing SQL: %s | Args: %v\n", stmt, logArgs)
+	if _, err := c.tx.source.Exec(ctx, stmt, args...); err != nil {
+		return fmt.Errorf("linking through %s: %w", joinTable, err)
+	}
+	return nil
+}
+
+// exists reports whether record is already stored: a record with a zero primary key is
+// new, one with an auto-increment key set exists, and one with a preset key (UUIDs,
+// natural keys) is looked up in its table.
+func (c *cascade) exists(ctx context.Context, model *schema.Model, record reflect.Value) (bool, error) {
+	if missingPrimaryKey(model, record.Elem()) {
+		return false, nil
+	}
+	dialect := c.tx.dialect
+	where := make([]string, len(model.PrimaryKeys))
+	args := make([]any, len(model.PrimaryKeys))
+	autoIncrement := true
+	for i, pk := range model.PrimaryKeys {
+		autoIncrement = autoIncrement && pk.AutoIncrement
+		where[i] = assignment(dialect, pk.DBName, i+1)
+		args[i] = record.Elem().FieldByName(pk.GoName).Interface()
+	}
+	if autoIncrement {
+		return true, nil
+	}
+	query := fmt.Sprintf("SELECT 1 FROM %s WHERE %s", quoteTable(dialect, model), strings.Join(where, " AND "))
+	logSQL("Executing SQL: %s | Args: %v\n", query, redactArgs(model, args, record.Interface()))
+	var found int
+	if err := c.tx.source.QueryRow(ctx, query, args...).Scan(&found); errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("checking whether %s exists: %w", model.Name, err)
+	}
+	return true, nil
+}
+
+// relatedRecords returns pointers to the records held by a relation field: the
+// non-nil pointer or non-zero struct of a to-one field, the elements of a slice.
+func relatedRecords(field reflect.Value) []reflect.Value {
+	switch field.Kind() {
+	case reflect.Pointer:
+		if field.IsNil() {
+			return nil
+		}
+		return []reflect.Value{field}
+	case reflect.Struct:
+		if field.IsZero() {
+			return nil
+		}
+		return []reflect.Value{field.Addr()}
+	case reflect.Slice:
+		records := make([]reflect.Value, 0, field.Len())
+		for i := 0; i < field.Len(); i++ {
+			item := field.Index(i)
+			if item.Kind() != reflect.Pointer {
+				item = item.Addr()
+			} else if item.IsNil() {
+				continue
+			}
+			records = append(records, item)
+		}
+		return records
+	}
+	return nil
+}
+
+// missingPrimaryKey reports whether a primary key of record is zero (not inserted yet).
+func missingPrimaryKey(model *schema.Model, record reflect.Value) bool {
+	for _, pk := range model.PrimaryKeys {
+		if _, ok := virtualKey(record.FieldByName(pk.GoName)); !ok {
+			return true
+		}
+	}
+	return len(model.PrimaryKeys) == 0
+}
+
+// setRelationKey copies a key into a foreign key field, converting between integer
+// types and filling pointer fields.
+func setRelationKey(field, key reflect.Value) error {
+	for key.Kind() == reflect.Pointer {
+		if key.IsNil() {
+			return nil
+		}
+		key = key.Elem()
+	}
+	target := field
+	if field.Kind() == reflect.Pointer {
+		target = reflect.New(field.Type().Elem()).Elem()
+	}
+	if !key.Type().ConvertibleTo(target.Type()) {
+		return fmt.Errorf("cannot assign key of type %s to foreign key of type %s", key.Type(), field.Type())
+	}
+	target.Set(key.Convert(target.Type()))
+	if field.Kind() == reflect.Pointer {
+		field.Set(target.Addr())
+	}
+	return nil
+}
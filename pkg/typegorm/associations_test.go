@@ -0,0 +1,149 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type assocEmpresa struct {
+	ID   uint `typegorm:"primaryKey;autoIncrement"`
+	Nome string
+}
+
+type assocPerfil struct {
+	ID        uint `typegorm:"primaryKey;autoIncrement"`
+	UsuarioID uint
+	Bio       string
+}
+
+type assocTag struct {
+	ID   uint `typegorm:"primaryKey;autoIncrement"`
+	Nome string
+}
+
+type assocPost struct {
+	ID        uint `typegorm:"primaryKey;autoIncrement"`
+	UsuarioID uint
+	Titulo    string
+	Autor     *assocUsuario `typegorm:"relation:many-to-one;foreignKey:UsuarioID"`
+	Tags      []*assocTag   `typegorm:"relation:many-to-many;joinTable:post_tags;joinForeignKey:post_id;joinReferences:tag_id"`
+}
+
+type assocUsuario struct {
+	ID        uint `typegorm:"primaryKey;autoIncrement"`
+	Nome      string
+	EmpresaID uint
+	Empresa   *assocEmpresa `typegorm:"relation:many-to-one"`
+	Perfil    *assocPerfil  `typegorm:"relation:one-to-one;foreignKey:UsuarioID"`
+	Posts     []assocPost   `typegorm:"relation:one-to-many;mappedBy:Autor"`
+}
+
+// insertsInto returns the recorded INSERT statements, by table.
+func insertsInto(source *mockSource) map[string]mockStatement {
+	inserts := make(map[string]mockStatement)
+	for _, stmt := range source.Statements() {
+		if rest, ok := strings.CutPrefix(stmt.SQL, "INSERT INTO `"); ok {
+			inserts[rest[:strings.Index(rest, "`")]] = stmt
+		}
+	}
+	return inserts
+}
+
+func TestCreate_WithAssociations(t *testing.T) {
+	db, source := newMockDB()
+	source.autoID = true
+
+	usuario := assocUsuario{
+		Nome:    "Ana",
+		Empresa: &assocEmpresa{Nome: "ACME"},
+		Perfil:  &assocPerfil{Bio: "dev"},
+	}
+	usuario.Posts = []assocPost{{
+		Titulo: "Oi",
+		Autor:  &usuario, // Back reference: not inserted twice
+		Tags:   []*assocTag{{Nome: "go"}, {ID: 50, Nome: "sql"}},
+	}}
+	result := db.Create(context.Background(), &usuario, WithAssociations())
+	require.NoError(t, result.Error)
+
+	assert.Equal(t, uint(1), usuario.Empresa.ID)
+	assert.Equal(t, uint(1), usuario.EmpresaID, "Belongs-to inserted first, foreign key wired")
+	assert.Equal(t, uint(2), usuario.ID)
+	assert.Equal(t, uint(2), usuario.Perfil.UsuarioID)
+	assert.Equal(t, uint(3), usuario.Perfil.ID)
+	assert.Equal(t, uint(2), usuario.Posts[0].UsuarioID, "Foreign key of mappedBy")
+	assert.Equal(t, uint(4), usuario.Posts[0].ID)
+	assert.Equal(t, uint(5), usuario.Posts[0].Tags[0].ID)
+
+	statements := source.Statements()
+	assert.Equal(t, "BEGIN", statements[0].SQL)
+	assert.Equal(t, "COMMIT", source.lastStatement().SQL)
+	inserts := insertsInto(source)
+	assert.Len(t, inserts, 6, "One INSERT per record, none for the existing tag")
+	assert.Contains(t, inserts["assoc_usuarios"].Args, uint(1))
+	link := inserts["post_tags"]
+	assert.Equal(t, "INSERT INTO `post_tags` (`post_id`, `tag_id`) VALUES (?, ?), (?, ?)", link.SQL)
+	assert.Equal(t, []any{uint(4), uint(5), uint(4), uint(50)}, link.Args)
+}
+
+func TestCreate_WithAssociationsNamedAndRollback(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	source.autoID = true
+
+	usuario := assocUsuario{Nome: "Ana", Empresa: &assocEmpresa{Nome: "ACME"}, Perfil: &assocPerfil{Bio: "dev"}}
+	require.NoError(t, db.Create(ctx, &usuario, WithAssociations("Perfil")).Error)
+	inserts := insertsInto(source)
+	assert.Len(t, inserts, 2)
+	assert.NotContains(t, inserts, "assoc_empresas", "Only the named relations")
+
+	err := db.Create(ctx, &assocUsuario{}, WithAssociations("Comentarios")).Error
+	assert.ErrorContains(t, err, "no relation named 'Comentarios'")
+
+	source.execErrs = []error{nil, errors.New("perfil rejected")}
+	err = db.Create(ctx, &assocUsuario{Nome: "Bia", Perfil: &assocPerfil{Bio: "x"}}, WithAssociations()).Error
+	assert.ErrorContains(t, err, "perfil rejected")
+	assert.Equal(t, "ROLLBACK", source.lastStatement().SQL, "All or nothing")
+}
+
+type assocNota struct {
+	ID        string `typegorm:"primaryKey;size:36"`
+	UsuarioID uint
+	Texto     string
+}
+
+type assocCaderno struct {
+	ID    uint `typegorm:"primaryKey;autoIncrement"`
+	Nome  string
+	Notas []assocNota `typegorm:"relation:one-to-many;foreignKey:UsuarioID"`
+}
+
+func TestCreate_WithAssociationsPresetKeys(t *testing.T) {
+	db, source := newMockDB()
+	source.autoID = true
+
+	caderno := assocCaderno{Nome: "Ana", Notas: []assocNota{{ID: "n-old", Texto: "a"}, {ID: "n-new", Texto: "b"}}}
+	source.queueRows([]string{"1"}, []any{1}) // n-old is stored already
+	require.NoError(t, db.Create(context.Background(), &caderno, WithAssociations()).Error)
+
+	var checks []mockStatement
+	for _, stmt := range source.Statements() {
+		if strings.HasPrefix(stmt.SQL, "SELECT 1 FROM") {
+			checks = append(checks, stmt)
+		}
+	}
+	require.Len(t, checks, 2, "children with a preset key are looked up")
+	assert.Equal(t, "SELECT 1 FROM `assoc_notas` WHERE `id` = ?", checks[0].SQL)
+	assert.Equal(t, []any{"n-old"}, checks[0].Args)
+
+	insert, ok := insertsInto(source)["assoc_notas"]
+	require.True(t, ok, "a child with a preset key that is not stored is inserted")
+	assert.Contains(t, insert.Args, "n-new")
+	assert.Equal(t, caderno.ID, caderno.Notas[1].UsuarioID, "and wired to its parent")
+	assert.Zero(t, caderno.Notas[0].UsuarioID, "the stored child is left alone")
+}
@@ -0,0 +1,192 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+)
+
+// --- Asynchronous Callbacks ---
+
+// Async makes an After* callback run on the DB's bounded worker pool instead of inline,
+// for non-critical side effects such as notifications. Inside a transaction it is only
+// dispatched once the transaction commits (and dropped on rollback). The callback gets
+// a shallow copy of the record and the DB (not the finished Tx) as HookContext.DB.
+//
+// Delivery is best-effort: when the queue stays full for hooks.asyncQueueTimeout, and
+// after Close, callbacks are dropped with a warning; callbacks pending when the process
+// exits are lost. The pool size is configured with hooks.asyncWorkers and
+// hooks.asyncQueueSize.
+func Async() CallbackOption {
+	return func(e *callbackEntry) { e.async = true }
+}
+
+// asyncDispatcher runs asynchronous callbacks on a bounded pool of workers, started
+// on first use.
+type asyncDispatcher struct {
+	db           *DB
+	workers      int
+	queueSize    int
+	queueTimeout time.Duration
+	start        sync.Once
+	jobs         chan func()
+
+	mu      sync.Mutex
+	idle    *sync.Cond // Broadcast when pending drops to zero
+	pending int        // Jobs being queued, queued or running
+	closed  bool
+}
+
+func newAsyncDispatcher(db *DB, cfg config.HooksConfig) *asyncDispatcher {
+	d := &asyncDispatcher{db: db, workers: cfg.AsyncWorkers, queueSize: cfg.AsyncQueueSize, queueTimeout: cfg.AsyncQueueTimeout}
+	if d.workers <= 0 {
+		d.workers = 4
+	}
+	if d.queueSize <= 0 {
+		d.queueSize = 1024
+	}
+	if d.queueTimeout <= 0 {
+		d.queueTimeout = time.Second
+	}
+	d.idle = sync.NewCond(&d.mu)
+	return d
+}
+
+func (d *asyncDispatcher) submit(job func()) {
+	d.start.Do(func() {
+		d.jobs = make(chan func(), d.queueSize)
+		for i := 0; i < d.workers; i++ {
+			go func() {
+				for job := range d.jobs {
+					job()
+					d.done()
+				}
+			}()
+		}
+	})
+
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		fmt.Println("Warning: DB closed, dropping async callback.")
+		return
+	}
+	d.pending++ // close waits for it, so d.jobs stays open until the job is queued
+	d.mu.Unlock()
+
+	select {
+	case d.jobs <- job:
+		return
+	default:
+	}
+	timer := time.NewTimer(d.queueTimeout)
+	defer timer.Stop()
+	select {
+	case d.jobs <- job:
+	case <-timer.C:
+		d.done()
+		fmt.Printf("Warning: async callback queue full for %s, dropping callback.\n", d.queueTimeout)
+	}
+}
+
+// done marks a job as finished (or dropped).
+func (d *asyncDispatcher) done() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.pending--; d.pending == 0 {
+		d.idle.Broadcast()
+	}
+}
+
+// wait blocks until no job is pending.
+func (d *asyncDispatcher) wait() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for d.pending > 0 {
+		d.idle.Wait()
+	}
+}
+
+// close waits for the queued callbacks and stops the workers; later callbacks are
+// dropped.
+func (d *asyncDispatcher) close() {
+	d.start.Do(func() {}) // Wait for a concurrent start, and prevent later ones
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return
+	}
+	d.closed = true
+	for d.pending > 0 {
+		d.idle.Wait()
+	}
+	d.mu.Unlock()
+	if d.jobs != nil {
+		close(d.jobs)
+	}
+}
+
+// WaitAsyncCallbacks blocks until all asynchronous callbacks dispatched so far have
+// finished, e.g. before shutting down or in tests.
+func (db *DB) WaitAsyncCallbacks() {
+	db.callbacks.async.wait()
+}
+
+// dispatchAsync schedules an asynchronous callback for the record in hc.
+func dispatchAsync(ctx context.Context, registry *CallbackRegistry, entry *callbackEntry, hc *HookContext) {
+	dispatcher := registry.async
+	if dispatcher == nil {
+		fmt.Printf("Warning: no async dispatcher, running callback '%s' inline.\n", entry.name)
+		if err := entry.fn(ctx, hc); err != nil {
+			fmt.Printf("Warning: %s callback '%s' failed: %v\n", hc.Event, entry.name, err)
+		}
+		return
+	}
+
+	snapshot := *hc
+	snapshot.DB = dispatcher.db
+	if rv := reflect.ValueOf(hc.Value); rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		clone := reflect.New(rv.Elem().Type())
+		clone.Elem().Set(rv.Elem()) // Shallow copy: the caller may keep modifying the original
+		snapshot.Value = clone.Interface()
+	}
+	detached := context.WithoutCancel(ctx) // Keep values, outlive the request
+
+	job := func() {
+		defer func() {
+			if r := recover(); r != nil {
+				fmt.Printf("Warning: async %s callback '%s' panicked: %v\n", snapshot.Event, entry.name, r)
+			}
+		}()
+		if err := entry.fn(detached, &snapshot); err != nil {
+			fmt.Printf("Warning: async %s callback '%s' failed: %v\n", snapshot.Event, entry.name, err)
+		}
+	}
+
+	if tx, ok := hc.DB.(*Tx); ok {
+		tx.AfterCommit(func() { dispatcher.submit(job) })
+		return
+	}
+	dispatcher.submit(job)
+}
+
+// validateAsync rejects Async and OnCommit on Before* events, whose errors must abort
+// the operation.
+func validateAsync(event HookEvent, entry *callbackEntry) error {
+	if entry.async && !isAfterEvent(event) {
+		return fmt.Errorf("callbacks: '%s' cannot be async: only After* callbacks can", entry.name)
+	}
+	if entry.deferred && !isAfterEvent(event) {
+		return fmt.Errorf("callbacks: '%s' cannot run on commit: only After* callbacks can", entry.name)
+	}
+	return nil
+}
+
+func isAfterEvent(event HookEvent) bool {
+	return strings.HasPrefix(string(event), "After")
+}
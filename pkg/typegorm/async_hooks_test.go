@@ -0,0 +1,82 @@
+package typegorm
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsyncCallbacks_DispatchedAfterCommit(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var notified []string
+	require.NoError(t, db.Callbacks().Register(EventAfterUpdate, "notify", func(ctx context.Context, hc *HookContext) error {
+		mu.Lock()
+		defer mu.Unlock()
+		notified = append(notified, hc.Value.(*maskUser).Name)
+		assert.Same(t, db, hc.DB, "async callbacks get the DB, not the finished Tx")
+		return nil
+	}, Async()))
+
+	tx, err := db.Begin(ctx, nil)
+	require.NoError(t, err)
+	user := &maskUser{ID: 1, Name: "Ana"}
+	require.NoError(t, tx.Updates(ctx, user, map[string]any{"name": "Ana"}).Error)
+	user.Name = "changed after the update" // The callback works on a snapshot
+	db.WaitAsyncCallbacks()
+	mu.Lock()
+	assert.Empty(t, notified, "not dispatched before commit")
+	mu.Unlock()
+	require.NoError(t, tx.Commit())
+
+	tx, err = db.Begin(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.Updates(ctx, &maskUser{ID: 2, Name: "Bia"}, map[string]any{"name": "Bia"}).Error)
+	require.NoError(t, tx.Rollback())
+
+	require.NoError(t, db.Updates(ctx, &maskUser{ID: 3, Name: "Eva"}, map[string]any{"name": "Eva"}).Error)
+	db.WaitAsyncCallbacks()
+	require.NoError(t, db.Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.ElementsMatch(t, []string{"Ana", "Eva"}, notified, "rolled back changes are not notified")
+	assert.True(t, source.containsStatement("UPDATE `mask_users`"))
+}
+
+func TestAsyncCallbacks_OnlyAfterEvents(t *testing.T) {
+	db, _ := newMockDB()
+	noop := func(ctx context.Context, hc *HookContext) error { return nil }
+
+	assert.ErrorContains(t, db.Callbacks().Register(EventBeforeCreate, "n", noop, Async()), "only After* callbacks")
+	assert.NoError(t, db.Callbacks().Register(EventAfterCreate, "n", noop, Async()))
+}
+
+func TestAsyncDispatcher_DropsWhenFullOrClosed(t *testing.T) {
+	d := newAsyncDispatcher(nil, config.HooksConfig{AsyncWorkers: 1, AsyncQueueSize: 1, AsyncQueueTimeout: 10 * time.Millisecond})
+	release := make(chan struct{})
+	var ran atomic.Int32
+	blocking := func() { <-release; ran.Add(1) }
+
+	d.submit(blocking) // Taken by the worker
+	require.Eventually(t, func() bool { return len(d.jobs) == 0 }, time.Second, time.Millisecond)
+	d.submit(blocking) // Queued
+	d.submit(func() { t.Error("a callback of a full queue must not run inline") })
+
+	close(release)
+	d.wait()
+	assert.Equal(t, int32(2), ran.Load())
+
+	d.close()
+	d.submit(func() { t.Error("a callback submitted after close must not run") })
+	d.wait()
+}
@@ -0,0 +1,310 @@
+package typegorm
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// --- Attachments ---
+
+// Attachment is the metadata of a file stored outside the database, kept in a single
+// JSON column of the model:
+//
+//	type Document struct {
+//		ID   uint `typegorm:"primaryKey;autoIncrement"`
+//		File typegorm.Attachment
+//	}
+//
+//	doc.File, err = typegorm.NewAttachment(ctx, store, header.Filename, upload)
+//	db.Create(ctx, &doc)
+//
+// The blob itself lives in an AttachmentStore; with UseAttachments the blobs of deleted
+// rows are removed once the deletion is committed.
+type Attachment struct {
+	Name        string `json:"name"`         // Original file name
+	Size        int64  `json:"size"`         // Size in bytes
+	ContentType string `json:"content_type"` // MIME type, from the file extension
+	Hash        string `json:"hash"`         // Hex SHA-256 of the content
+	Key         string `json:"key"`          // Key of the blob in the store
+}
+
+// IsZero reports whether no file is attached.
+func (a Attachment) IsZero() bool {
+	return a.Key == ""
+}
+
+// Value stores the attachment as JSON (NULL when empty).
+func (a Attachment) Value() (driver.Value, error) {
+	if a.IsZero() {
+		return nil, nil
+	}
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan reads an attachment stored by Value.
+func (a *Attachment) Scan(src any) error {
+	*a = Attachment{}
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	}
+	return fmt.Errorf("attachment: cannot scan %T", src)
+}
+
+// DataType implements schema.DataTyper.
+func (Attachment) DataType(dialect string) string {
+	switch dialect {
+	case "mysql":
+		return "JSON"
+	case "postgres":
+		return "JSONB"
+	}
+	return "TEXT"
+}
+
+// Open returns a reader for the attached file.
+func (a Attachment) Open(ctx context.Context, store AttachmentStore) (io.ReadCloser, error) {
+	if a.IsZero() {
+		return nil, fmt.Errorf("attachment: no file attached")
+	}
+	return store.Get(ctx, a.Key)
+}
+
+// AttachmentStore is a blob storage backend. FileStore stores blobs on the local
+// filesystem; object storages such as S3 are plugged in by implementing this interface
+// around their client (key -> object key).
+type AttachmentStore interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes a blob. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// NewAttachment streams r into the store under a new unique key and returns its
+// metadata (size and SHA-256 computed on the way).
+func NewAttachment(ctx context.Context, store AttachmentStore, name string, r io.Reader) (Attachment, error) {
+	base := path.Base(filepath.ToSlash(name))
+	ext := strings.ToLower(path.Ext(base))
+	slug := Slugify(strings.TrimSuffix(base, path.Ext(base)))
+	if slug == "" {
+		slug = "file"
+	}
+	key := fmt.Sprintf("%s/%s-%s%s", time.Now().UTC().Format("2006/01/02"), strings.ToLower(UniqueToken(12)), slug, ext)
+
+	hash := sha256.New()
+	counter := &countingReader{r: io.TeeReader(r, hash)}
+	if err := store.Put(ctx, key, counter); err != nil {
+		return Attachment{}, fmt.Errorf("attachment: failed to store %s: %w", name, err)
+	}
+	contentType := mime.TypeByExtension(ext)
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return Attachment{
+		Name:        base,
+		Size:        counter.n,
+		ContentType: contentType,
+		Hash:        hex.EncodeToString(hash.Sum(nil)),
+		Key:         key,
+	}, nil
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// FileStore stores blobs as files under Dir.
+type FileStore struct {
+	Dir string
+}
+
+func (s FileStore) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" {
+		return "", fmt.Errorf("attachment: invalid key %q", key)
+	}
+	return filepath.Join(s.Dir, filepath.FromSlash(clean)), nil
+}
+
+func (s FileStore) Put(ctx context.Context, key string, r io.Reader) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(target)
+		return err
+	}
+	return f.Close()
+}
+
+func (s FileStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(target)
+}
+
+func (s FileStore) Delete(ctx context.Context, key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// --- Orphan cleanup ---
+
+var attachmentType = reflect.TypeOf(Attachment{})
+
+// UseAttachments removes the blobs of deleted rows from store: Delete loads the
+// attachment columns of the row if the struct does not carry them, and once the
+// deletion is committed (immediately outside a transaction) the blobs are deleted.
+// Soft deletes keep the blobs, since the row can still be restored; they are removed
+// when the row is deleted for good (Delete in a WithUnscoped context).
+// Failures to delete a blob are logged; the row deletion is not affected.
+func (db *DB) UseAttachments(store AttachmentStore) error {
+	if store == nil {
+		return fmt.Errorf("attachments: store is nil")
+	}
+	if err := db.callbacks.Register(EventBeforeDelete, "attachments:load", loadAttachments); err != nil {
+		return err
+	}
+	return db.callbacks.Register(EventAfterDelete, "attachments:cleanup", func(ctx context.Context, hc *HookContext) error {
+		if hc.SoftDelete {
+			return nil
+		}
+		var errs []error
+		for _, key := range attachmentKeys(reflect.ValueOf(hc.Value)) {
+			fmt.Printf("Deleting orphaned attachment %s\n", key)
+			if err := store.Delete(ctx, key); err != nil {
+				errs = append(errs, fmt.Errorf("attachment %s: %w", key, err))
+			}
+		}
+		return errors.Join(errs...)
+	}, OnCommit())
+}
+
+// loadAttachments fills empty attachment fields of a record about to be deleted from
+// the database, so their blobs can be cleaned up afterwards.
+func loadAttachments(ctx context.Context, hc *HookContext) error {
+	if hc.SoftDelete {
+		return nil
+	}
+	value := reflect.ValueOf(hc.Value)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	record := value.Elem()
+	var missing []int
+	for _, i := range attachmentFields(record.Type()) {
+		if attachmentOf(record.Field(i)).IsZero() {
+			missing = append(missing, i)
+		}
+	}
+	if len(missing) == 0 || len(hc.Model.PrimaryKeys) != 1 {
+		return nil
+	}
+
+	stored := reflect.New(record.Type())
+	pk := record.FieldByName(hc.Model.PrimaryKeys[0].GoName).Interface()
+	var result *Result
+	switch conn := hc.DB.(type) {
+	case *Tx:
+		result = conn.FindByID(ctx, stored.Interface(), pk)
+	case *DB:
+		result = conn.FindByID(WithPrimary(ctx), stored.Interface(), pk)
+	default:
+		return nil
+	}
+	if result.Error != nil {
+		return nil // The row may not exist; nothing to clean up
+	}
+	for _, i := range missing {
+		record.Field(i).Set(stored.Elem().Field(i))
+	}
+	return nil
+}
+
+// attachmentFields returns the indexes of the Attachment and *Attachment fields of a struct type.
+func attachmentFields(structType reflect.Type) []int {
+	var fields []int
+	for i := 0; i < structType.NumField(); i++ {
+		sf := structType.Field(i)
+		if sf.IsExported() && (sf.Type == attachmentType || sf.Type == reflect.PointerTo(attachmentType)) {
+			fields = append(fields, i)
+		}
+	}
+	return fields
+}
+
+func attachmentOf(field reflect.Value) Attachment {
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			return Attachment{}
+		}
+		field = field.Elem()
+	}
+	return field.Interface().(Attachment)
+}
+
+// attachmentKeys returns the storage keys of the attachments of a record.
+func attachmentKeys(value reflect.Value) []string {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	var keys []string
+	for _, i := range attachmentFields(value.Type()) {
+		if a := attachmentOf(value.Field(i)); !a.IsZero() {
+			keys = append(keys, a.Key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,133 @@
+package typegorm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type attachedDoc struct {
+	ID   int64 `typegorm:"primaryKey;autoIncrement"`
+	File Attachment
+}
+
+func newStoredAttachment(t *testing.T, store FileStore, content string) Attachment {
+	t.Helper()
+	att, err := NewAttachment(context.Background(), store, "Relatório Final.pdf", strings.NewReader(content))
+	require.NoError(t, err)
+	return att
+}
+
+func blobExists(store FileStore, key string) bool {
+	_, err := os.Stat(filepath.Join(store.Dir, filepath.FromSlash(key)))
+	return err == nil
+}
+
+func TestNewAttachment_StoresAndDescribesFile(t *testing.T) {
+	store := FileStore{Dir: t.TempDir()}
+	att := newStoredAttachment(t, store, "hello")
+
+	sum := sha256.Sum256([]byte("hello"))
+	assert.Equal(t, "Relatório Final.pdf", att.Name)
+	assert.Equal(t, int64(5), att.Size)
+	assert.Equal(t, "application/pdf", att.ContentType)
+	assert.Equal(t, hex.EncodeToString(sum[:]), att.Hash)
+	assert.True(t, strings.HasSuffix(att.Key, "-relatorio-final.pdf"), att.Key)
+
+	r, err := att.Open(context.Background(), store)
+	require.NoError(t, err)
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+}
+
+func TestAttachment_ValueScan(t *testing.T) {
+	att := Attachment{Name: "a.txt", Size: 1, ContentType: "text/plain", Hash: "ab", Key: "k/a.txt"}
+	v, err := att.Value()
+	require.NoError(t, err)
+
+	var scanned Attachment
+	require.NoError(t, scanned.Scan([]byte(v.(string))))
+	assert.Equal(t, att, scanned)
+
+	v, err = Attachment{}.Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+	assert.Equal(t, "JSON", Attachment{}.DataType("mysql"))
+}
+
+func TestUseAttachments_DeletesBlobAfterDelete(t *testing.T) {
+	store := FileStore{Dir: t.TempDir()}
+	db, _ := newMockDB()
+	require.NoError(t, db.UseAttachments(store))
+
+	doc := &attachedDoc{ID: 1, File: newStoredAttachment(t, store, "x")}
+	require.NoError(t, db.Delete(context.Background(), doc).Error)
+	assert.False(t, blobExists(store, doc.File.Key))
+}
+
+func TestUseAttachments_LoadsMissingMetadata(t *testing.T) {
+	store := FileStore{Dir: t.TempDir()}
+	db, source := newMockDB()
+	require.NoError(t, db.UseAttachments(store))
+
+	att := newStoredAttachment(t, store, "x")
+	stored, err := att.Value()
+	require.NoError(t, err)
+	source.queueRows([]string{"id", "file"}, []any{int64(1), stored})
+
+	require.NoError(t, db.Delete(context.Background(), &attachedDoc{ID: 1}).Error)
+	assert.False(t, blobExists(store, att.Key))
+}
+
+func TestUseAttachments_WaitsForCommit(t *testing.T) {
+	store := FileStore{Dir: t.TempDir()}
+	db, _ := newMockDB()
+	require.NoError(t, db.UseAttachments(store))
+	ctx := context.Background()
+
+	kept := &attachedDoc{ID: 1, File: newStoredAttachment(t, store, "x")}
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Delete(ctx, kept).Error)
+	require.NoError(t, tx.Rollback())
+	assert.True(t, blobExists(store, kept.File.Key))
+
+	removed := &attachedDoc{ID: 2, File: newStoredAttachment(t, store, "y")}
+	tx, err = db.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Delete(ctx, removed).Error)
+	assert.True(t, blobExists(store, removed.File.Key))
+	require.NoError(t, tx.Commit())
+	assert.False(t, blobExists(store, removed.File.Key))
+}
+
+type softAttachedDoc struct {
+	ID        int64 `typegorm:"primaryKey;autoIncrement"`
+	File      Attachment
+	DeletedAt *time.Time
+}
+
+func TestUseAttachments_KeepsBlobOfSoftDeletedRow(t *testing.T) {
+	store := FileStore{Dir: t.TempDir()}
+	db, _ := newMockDB()
+	require.NoError(t, db.UseAttachments(store))
+	ctx := context.Background()
+
+	doc := &softAttachedDoc{ID: 1, File: newStoredAttachment(t, store, "x")}
+	require.NoError(t, db.Delete(ctx, doc).Error)
+	assert.True(t, blobExists(store, doc.File.Key), "a soft-deleted row can be restored")
+
+	require.NoError(t, db.Delete(WithUnscoped(ctx), doc).Error)
+	assert.False(t, blobExists(store, doc.File.Key))
+}
@@ -0,0 +1,319 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Set-Based Writes ---
+
+// DeleteWhere deletes every row of the model matching conds, in one statement, and
+// reports the count in Result.RowsAffected:
+//
+//	db.DeleteWhere(ctx, &Session{}, typegorm.Lt("expires_at", time.Now()))
+//	db.DeleteWhere(ctx, &User{}, "last_login < ? AND active = ?", cutoff, false)
+//
+// conds take the forms of Find (struct pointer, map with operators, Expr), ANDed, or
+// SQL followed by its arguments. Without conditions it fails with
+// ErrMissingWhereClause; delete every row with Model(...).AllRows().Delete. As for the
+// other writes of a Chain, the model's DefaultScope does not apply; soft-deleted models
+// get their deletion timestamp set (see Chain.Unscoped).
+func (db *DB) DeleteWhere(ctx context.Context, model any, conds ...any) *Result {
+	return whereConds(db.Model(model), conds).Delete(ctx)
+}
+
+// DeleteWhere deletes the matching rows within the transaction. See DB.DeleteWhere.
+func (tx *Tx) DeleteWhere(ctx context.Context, model any, conds ...any) *Result {
+	return whereConds(tx.Model(model), conds).Delete(ctx)
+}
+
+// batchOptions holds the optional behaviors of DeleteWhereInBatches and
+// UpdateWhereInBatches.
+type batchOptions struct {
+	pause time.Duration // Sleep between batches
+}
+
+// BatchOption defines a function type that modifies batchOptions.
+type BatchOption func(*batchOptions)
+
+// BatchPause sets how long DeleteWhereInBatches and UpdateWhereInBatches sleep between
+// batches (default 100ms), letting other transactions and the replicas catch up.
+func BatchPause(d time.Duration) BatchOption {
+	return func(opts *batchOptions) { opts.pause = d }
+}
+
+// DeleteProgress reports the state of DeleteWhereInBatches after a batch.
+type DeleteProgress struct {
+	Table   string
+	Batch   int   // Batches completed so far
+	Deleted int64 // Rows deleted so far
+}
+
+// UpdateProgress reports the state of UpdateWhereInBatches after a batch.
+type UpdateProgress struct {
+	Table   string
+	Batch   int   // Batches completed so far
+	Updated int64 // Rows updated so far
+}
+
+// DeleteWhereInBatches deletes the rows of the model matching conds (the forms of
+// DeleteWhere) batchSize rows at a time, each batch in its own statement, so that a
+// large purge never holds locks on many rows at once:
+//
+//	res := db.DeleteWhereInBatches(ctx, &Event{}, []any{typegorm.Lt("created_at", cutoff)}, 5000,
+//		func(p typegorm.DeleteProgress) { log.Printf("%s: %d rows deleted", p.Table, p.Deleted) })
+//
+// On MySQL a batch is DELETE ... ORDER BY <primary key> LIMIT n, ordered so that
+// statement-based replication deletes the same rows on the replicas; elsewhere it is
+// the range of the next n primary keys (the model needs a single-column primary key).
+// progress, if not nil, is called after every batch, and the loop sleeps between
+// batches (see BatchPause). Result.RowsAffected is the total; on error it counts the
+// batches completed. Soft deletes apply as in DeleteWhere.
+func (db *DB) DeleteWhereInBatches(ctx context.Context, model any, conds []any, batchSize int, progress func(DeleteProgress), opts ...BatchOption) (result *Result) {
+	defer recoverResult(&result, "DeleteWhereInBatches", model)
+	return deleteInBatches(ctx, whereConds(db.Model(model), conds), batchSize, progress, opts)
+}
+
+// DeleteWhereInBatches deletes the matching rows in batches within the transaction:
+// each statement stays small, but the deleted rows are locked until the transaction
+// ends. See DB.DeleteWhereInBatches.
+func (tx *Tx) DeleteWhereInBatches(ctx context.Context, model any, conds []any, batchSize int, progress func(DeleteProgress), opts ...BatchOption) (result *Result) {
+	defer recoverResult(&result, "DeleteWhereInBatches", model)
+	return deleteInBatches(ctx, whereConds(tx.Model(model), conds), batchSize, progress, opts)
+}
+
+func deleteInBatches(ctx context.Context, chain *Chain, batchSize int, progress func(DeleteProgress), opts []BatchOption) *Result {
+	model, dialect, err := chain.resolve()
+	if err != nil {
+		return &Result{Error: err}
+	}
+	where, args, err := chain.where(ctx, "Delete", dialect, model, false)
+	if err != nil {
+		return &Result{Error: err}
+	}
+	table := quoteTable(dialect, model)
+	write := batchWrite{operation: "Delete", statement: "DELETE FROM " + table, where: where, args: args, limit: true}
+	if softDelete := chain.softDeleteField(ctx, model); softDelete != nil {
+		write.statement = "UPDATE " + table + " SET " + assignment(dialect, softDelete.DBName, 1)
+		write.stmtArgs = []any{clock(ctx, chain.now())}
+	}
+	return write.run(ctx, chain, model, dialect, batchSize, opts, func(batch int, total int64) {
+		if progress != nil {
+			progress(DeleteProgress{Table: model.TableName, Batch: batch, Deleted: total})
+		}
+	})
+}
+
+// UpdateWhereInBatches sets data (as UpdateWhere) on the rows of the model matching
+// conds, batchSize rows at a time, each batch in its own statement:
+//
+//	res := db.UpdateWhereInBatches(ctx, &User{}, map[string]any{"active": false},
+//		[]any{typegorm.Lt("last_login", cutoff)}, 5000, nil)
+//
+// A batch is the range of the next n primary keys on every dialect (the model needs a
+// single-column primary key), as the updated rows may still match conds. progress,
+// pauses and Result.RowsAffected are those of DeleteWhereInBatches.
+func (db *DB) UpdateWhereInBatches(ctx context.Context, model any, data map[string]any, conds []any, batchSize int, progress func(UpdateProgress), opts ...BatchOption) (result *Result) {
+	defer recoverResult(&result, "UpdateWhereInBatches", model)
+	return updateInBatches(ctx, whereConds(db.Model(model), conds), data, batchSize, progress, opts)
+}
+
+// UpdateWhereInBatches updates the matching rows in batches within the transaction:
+// each statement stays small, but the updated rows are locked until the transaction
+// ends. See DB.UpdateWhereInBatches.
+func (tx *Tx) UpdateWhereInBatches(ctx context.Context, model any, data map[string]any, conds []any, batchSize int, progress func(UpdateProgress), opts ...BatchOption) (result *Result) {
+	defer recoverResult(&result, "UpdateWhereInBatches", model)
+	return updateInBatches(ctx, whereConds(tx.Model(model), conds), data, batchSize, progress, opts)
+}
+
+func updateInBatches(ctx context.Context, chain *Chain, data map[string]any, batchSize int, progress func(UpdateProgress), opts []BatchOption) *Result {
+	model, dialect, err := chain.resolve()
+	if err != nil {
+		return &Result{Error: err}
+	}
+	statement, stmtArgs, columns, err := chain.updateStatement(ctx, model, dialect, data)
+	if err != nil {
+		return &Result{Error: err}
+	}
+	where, args, err := chain.where(ctx, "Update", dialect, model, false)
+	if err != nil {
+		return &Result{Error: err}
+	}
+	write := batchWrite{operation: "Updates", statement: statement, stmtArgs: stmtArgs, where: where, args: args, data: columns}
+	return write.run(ctx, chain, model, dialect, batchSize, opts, func(batch int, total int64) {
+		if progress != nil {
+			progress(UpdateProgress{Table: model.TableName, Batch: batch, Updated: total})
+		}
+	})
+}
+
+// batchWrite is a write of DeleteWhereInBatches or UpdateWhereInBatches: statement
+// (with the arguments of its SET clause) restricted to a batch of the rows of where.
+type batchWrite struct {
+	operation string
+	statement string
+	stmtArgs  []any
+	where     string // " WHERE ..." clause of the conditions
+	args      []any
+	data      map[string]any // SET values, to redact the log
+	limit     bool           // Batches of the MySQL statement ... ORDER BY ... LIMIT n
+}
+
+// run runs the batches until no matching row is left; report is called after each.
+func (w batchWrite) run(ctx context.Context, chain *Chain, model *schema.Model, dialect common.Dialect, batchSize int, opts []BatchOption, report func(batch int, total int64)) *Result {
+	result := &Result{}
+	name := strings.ToLower(w.operation)
+	options := batchOptions{pause: 100 * time.Millisecond}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	if batchSize <= 0 {
+		result.Error = fmt.Errorf("%s in batches: batch size must be positive, got %d", name, batchSize)
+		return result
+	}
+
+	// nextBatch returns the statement of the next batch ("" when no rows are left) and
+	// whether it is known to be the last one.
+	var nextBatch func() (string, []any, bool, error)
+	limit := w.limit && dialect.Name() == "mysql"
+	if limit {
+		if len(model.PrimaryKeys) == 0 {
+			result.Error = fmt.Errorf("%s in batches: %s needs a primary key to order the batches", name, model.TableName)
+			return result
+		}
+		keys := make([]string, len(model.PrimaryKeys))
+		for i, pk := range model.PrimaryKeys {
+			keys[i] = dialect.Quote(pk.DBName)
+		}
+		query := fmt.Sprintf("%s%s ORDER BY %s LIMIT %d", w.statement, w.where, strings.Join(keys, ", "), batchSize)
+		queryArgs := append(append([]any(nil), w.stmtArgs...), w.args...)
+		nextBatch = func() (string, []any, bool, error) { return query, queryArgs, false, nil }
+	} else {
+		if len(model.PrimaryKeys) != 1 {
+			result.Error = fmt.Errorf("%s in batches: %s needs a single-column primary key", name, model.TableName)
+			return result
+		}
+		nextBatch = w.primaryKeyBatches(ctx, chain, dialect, model, batchSize)
+	}
+
+	fmt.Printf("Running %s on %s in batches of %d\n", name, model.TableName, batchSize)
+	for batch := 1; ; batch++ {
+		if err := ctx.Err(); err != nil {
+			result.Error = fmt.Errorf("%s in batches: %w", name, err)
+			return result
+		}
+		query, queryArgs, last, err := nextBatch()
+		if err != nil {
+			result.Error = fmt.Errorf("%s in batches: selecting batch %d of %s: %w", name, batch, model.TableName, err)
+			return result
+		}
+		if query == "" {
+			break
+		}
+		res := chain.exec(ctx, w.operation, model, renumberBindVars(dialect, query), queryArgs, w.data)
+		if res.Error != nil {
+			result.Error = fmt.Errorf("%s in batches: batch %d of %s: %w", name, batch, model.TableName, res.Error)
+			return result
+		}
+		result.RowsAffected += res.RowsAffected
+		report(batch, result.RowsAffected)
+		if last || (limit && res.RowsAffected < int64(batchSize)) {
+			break
+		}
+		if options.pause > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(options.pause):
+			}
+		}
+	}
+	fmt.Printf("Ran %s on %d row(s) of %s in batches.\n", name, result.RowsAffected, model.TableName)
+	return result
+}
+
+// primaryKeyBatches returns the batches of the dialects without DELETE ... LIMIT, and
+// of the updates: each covers the primary keys after the previous batch up to the
+// batchSize-th matching one, with the conditions applied again.
+func (w batchWrite) primaryKeyBatches(ctx context.Context, chain *Chain, dialect common.Dialect, model *schema.Model, batchSize int) func() (string, []any, bool, error) {
+	pk := dialect.Quote(model.PrimaryKeys[0].DBName)
+	table := quoteTable(dialect, model)
+	var previous any // Highest key of the previous batch
+	return func() (string, []any, bool, error) {
+		bounded, boundedArgs := w.where, w.args
+		if previous != nil {
+			bounded = andClause(w.where, pk+" > "+dialect.BindVar(1))
+			boundedArgs = append(append([]any(nil), w.args...), previous)
+		}
+		selectSQL := renumberBindVars(dialect, fmt.Sprintf("SELECT %s FROM %s%s ORDER BY %s LIMIT %d", pk, table, bounded, pk, batchSize))
+		logSQL("Executing SQL: %s | Args: %v\n", selectSQL, redactArgs(model, boundedArgs, chainWhere(chain.conds)))
+		var rd reader
+		if chain.tx != nil {
+			rd = chain.tx.source
+		} else {
+			rd = chain.db.conn(ctx) // The primary: the keys are about to be written
+		}
+		rows, err := rd.Query(ctx, selectSQL, boundedArgs...)
+		if err != nil {
+			return "", nil, false, err
+		}
+		defer rows.Close()
+		keys := 0
+		var highest any
+		for rows.Next() {
+			if err := rows.Scan(&highest); err != nil {
+				return "", nil, false, err
+			}
+			keys++
+		}
+		if err := rows.Err(); err != nil || keys == 0 {
+			return "", nil, false, err
+		}
+		previous = highest
+		query := w.statement + andClause(bounded, pk+" <= "+dialect.BindVar(1))
+		queryArgs := append(append(append([]any(nil), w.stmtArgs...), boundedArgs...), highest)
+		return query, queryArgs, keys < batchSize, nil
+	}
+}
+
+// andClause adds clause to a " WHERE ..." clause, possibly empty.
+func andClause(where, clause string) string {
+	if where == "" {
+		return " WHERE " + clause
+	}
+	return where + " AND " + clause
+}
+
+// UpdateWhere sets data (keys are column or Go field names) on every row of the model
+// matching conds, in one statement, and reports the count in Result.RowsAffected:
+//
+//	db.UpdateWhere(ctx, &User{}, map[string]any{"active": false}, typegorm.Lt("last_login", cutoff))
+//
+// conds are those of DeleteWhere. Hooks are not called, as no struct is loaded.
+func (db *DB) UpdateWhere(ctx context.Context, model any, data map[string]any, conds ...any) *Result {
+	return whereConds(db.Model(model), conds).Updates(ctx, data)
+}
+
+// UpdateWhere updates the matching rows within the transaction. See DB.UpdateWhere.
+func (tx *Tx) UpdateWhere(ctx context.Context, model any, data map[string]any, conds ...any) *Result {
+	return whereConds(tx.Model(model), conds).Updates(ctx, data)
+}
+
+// whereConds adds conds to the chain: SQL with its arguments, or conditions ANDed.
+func whereConds(chain *Chain, conds []any) *Chain {
+	if len(conds) > 0 {
+		if _, ok := conds[0].(string); ok {
+			return chain.Where(conds[0], conds[1:]...)
+		}
+	}
+	for _, cond := range conds {
+		chain = chain.Where(cond)
+	}
+	return chain
+}
@@ -0,0 +1,110 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteWhere(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	source.affected = 3
+
+	res := db.DeleteWhere(ctx, &maskUser{}, map[string]any{"age <": 18}, Like("email", "%@test"))
+	require.NoError(t, res.Error)
+	assert.Equal(t, int64(3), res.RowsAffected)
+	assert.Equal(t, "DELETE FROM `mask_users` WHERE `age` < ? AND `email` LIKE ?", source.lastStatement().SQL)
+	assert.Equal(t, []any{18, "%@test"}, source.lastStatement().Args)
+
+	require.NoError(t, db.DeleteWhere(ctx, &maskUser{}, "age > ? AND name = ?", 90, "x").Error)
+	assert.Equal(t, "DELETE FROM `mask_users` WHERE (age > ? AND name = ?)", source.lastStatement().SQL)
+
+	assert.ErrorIs(t, db.DeleteWhere(ctx, &maskUser{}).Error, ErrMissingWhereClause)
+}
+
+func TestUpdateWhere(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	res := tx.UpdateWhere(ctx, &maskUser{}, map[string]any{"Name": "anon", "email": ""}, &maskUser{Age: 30})
+	require.NoError(t, res.Error)
+	require.NoError(t, tx.Commit())
+	assert.True(t, source.containsStatement("UPDATE `mask_users` SET `email` = ?, `name` = ? WHERE `age` = ?"))
+
+	assert.ErrorIs(t, db.UpdateWhere(ctx, &maskUser{}, map[string]any{"name": "x"}).Error, ErrMissingWhereClause)
+}
+
+func TestDeleteWhereInBatches_Limit(t *testing.T) {
+	db, source := newMockDBWithDialect("mysql")
+	ctx := context.Background()
+	source.affected = 2
+
+	var progress []DeleteProgress
+	res := db.DeleteWhereInBatches(ctx, &maskUser{}, []any{map[string]any{"age <": 18}}, 2, func(p DeleteProgress) {
+		progress = append(progress, p)
+		source.mu.Lock()
+		source.affected = 1 // The next batch is the last one
+		source.mu.Unlock()
+	}, BatchPause(0))
+	require.NoError(t, res.Error)
+	assert.Equal(t, int64(3), res.RowsAffected)
+	assert.Equal(t, []DeleteProgress{{Table: "mask_users", Batch: 1, Deleted: 2}, {Table: "mask_users", Batch: 2, Deleted: 3}}, progress)
+	stmts := source.Statements()
+	require.Len(t, stmts, 2)
+	assert.Equal(t, "DELETE FROM `mask_users` WHERE `age` < ? ORDER BY `id` LIMIT 2", stmts[1].SQL)
+	assert.Equal(t, []any{18}, stmts[1].Args)
+
+	assert.ErrorIs(t, db.DeleteWhereInBatches(ctx, &maskUser{}, nil, 2, nil).Error, ErrMissingWhereClause)
+}
+
+func TestDeleteWhereInBatches_PrimaryKeyRanges(t *testing.T) {
+	db, source := newMockDB()
+	source.dialect = &numberedDialect{mockDialect{name: "postgres"}}
+	ctx := context.Background()
+	source.affected = 2
+	source.queueRows([]string{"id"}, []any{int64(4)}, []any{int64(7)})
+	source.queueRows([]string{"id"}, []any{int64(9)})
+
+	res := db.DeleteWhereInBatches(ctx, &maskUser{}, []any{"age < ?", 18}, 2, nil, BatchPause(time.Millisecond))
+	require.NoError(t, res.Error)
+	assert.Equal(t, int64(4), res.RowsAffected)
+	stmts := source.Statements()
+	require.Len(t, stmts, 4, "the short second batch is the last one")
+	assert.Equal(t, "SELECT `id` FROM `mask_users` WHERE (age < $1) ORDER BY `id` LIMIT 2", stmts[0].SQL)
+	assert.Equal(t, "DELETE FROM `mask_users` WHERE (age < $1) AND `id` <= $2", stmts[1].SQL)
+	assert.Equal(t, []any{18, int64(7)}, stmts[1].Args)
+	assert.Equal(t, "SELECT `id` FROM `mask_users` WHERE (age < $1) AND `id` > $2 ORDER BY `id` LIMIT 2", stmts[2].SQL)
+	assert.Equal(t, "DELETE FROM `mask_users` WHERE (age < $1) AND `id` > $2 AND `id` <= $3", stmts[3].SQL)
+	assert.Equal(t, []any{18, int64(7), int64(9)}, stmts[3].Args)
+}
+
+func TestUpdateWhereInBatches_PrimaryKeyRangesInTx(t *testing.T) {
+	db, source := newMockDBWithDialect("mysql")
+	ctx := context.Background()
+	source.affected = 2
+	source.queueRows([]string{"id"}, []any{int64(4)}, []any{int64(7)})
+
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	var progress []UpdateProgress
+	res := tx.UpdateWhereInBatches(ctx, &maskUser{}, map[string]any{"Name": "anon"}, []any{Lt("age", 18)}, 2,
+		func(p UpdateProgress) { progress = append(progress, p) }, BatchPause(0))
+	require.NoError(t, res.Error)
+	require.NoError(t, tx.Commit())
+	assert.Equal(t, int64(2), res.RowsAffected)
+	assert.Equal(t, []UpdateProgress{{Table: "mask_users", Batch: 1, Updated: 2}}, progress)
+	stmts := source.Statements()
+	require.Len(t, stmts, 5, "BEGIN, two batches of keys (the second is empty), one UPDATE, COMMIT")
+	assert.Equal(t, "SELECT `id` FROM `mask_users` WHERE `age` < ? ORDER BY `id` LIMIT 2", stmts[1].SQL)
+	assert.Equal(t, "UPDATE `mask_users` SET `name` = ? WHERE `age` < ? AND `id` <= ?", stmts[2].SQL, "No LIMIT: updated rows may still match")
+	assert.Equal(t, []any{"anon", 18, int64(7)}, stmts[2].Args)
+	assert.Equal(t, "SELECT `id` FROM `mask_users` WHERE `age` < ? AND `id` > ? ORDER BY `id` LIMIT 2", stmts[3].SQL)
+
+	assert.ErrorIs(t, db.UpdateWhereInBatches(ctx, &maskUser{}, map[string]any{"name": "x"}, nil, 2, nil).Error, ErrMissingWhereClause)
+}
@@ -0,0 +1,296 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/chmenegatti/typegorm/pkg/hooks"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Lifecycle Callbacks ---
+
+// HookEvent identifies a model lifecycle event.
+type HookEvent string
+
+const (
+	EventBeforeCreate HookEvent = "BeforeCreate"
+	EventAfterCreate  HookEvent = "AfterCreate"
+	EventBeforeUpdate HookEvent = "BeforeUpdate"
+	EventAfterUpdate  HookEvent = "AfterUpdate"
+	EventBeforeDelete HookEvent = "BeforeDelete"
+	EventAfterDelete  HookEvent = "AfterDelete"
+	EventAfterFind    HookEvent = "AfterFind"
+)
+
+// ModelHook is the name of the built-in callback that calls the model's own hook
+// method (BeforeCreate, AfterFind, ...). Use it to order plugin callbacks around it:
+//
+//	db.Callbacks().Register(typegorm.EventBeforeCreate, "audit", fn, typegorm.Before(typegorm.ModelHook))
+const ModelHook = "model"
+
+// HookContext describes the record a callback runs for.
+type HookContext struct {
+	Event      HookEvent
+	Model      *schema.Model
+	Value      any             // Pointer to the struct being processed
+	Data       map[string]any  // Columns being written (BeforeUpdate only; may be modified)
+	DB         hooks.ContextDB // *DB or *Tx running the operation
+	SoftDelete bool            // The delete only sets the deletion timestamp (Delete events only)
+}
+
+// CallbackFunc is a callback registered for a lifecycle event. Errors returned from
+// Before* callbacks abort the operation; errors from After* callbacks are logged.
+type CallbackFunc func(ctx context.Context, hc *HookContext) error
+
+// CallbackOption configures the position of a callback.
+type CallbackOption func(*callbackEntry)
+
+// Before runs the callback before the named callbacks of the same event.
+func Before(names ...string) CallbackOption {
+	return func(e *callbackEntry) { e.before = append(e.before, names...) }
+}
+
+// After runs the callback after the named callbacks of the same event.
+func After(names ...string) CallbackOption {
+	return func(e *callbackEntry) { e.after = append(e.after, names...) }
+}
+
+type callbackEntry struct {
+	name     string
+	fn       CallbackFunc // nil for ModelHook
+	before   []string
+	after    []string
+	async    bool // Dispatched on the worker pool (see Async)
+	deferred bool // Inside a transaction, run once it commits (see OnCommit)
+}
+
+// CallbackRegistry holds the callbacks of each event in a deterministic order: the
+// Before/After constraints are honored, and otherwise callbacks run in registration
+// order. Callbacks not declared Before another one run after the model's own hook method.
+type CallbackRegistry struct {
+	mu       sync.RWMutex
+	entries  map[HookEvent][]*callbackEntry // Registration order
+	ordered  map[HookEvent][]*callbackEntry // Execution order
+	async    *asyncDispatcher               // Worker pool for Async callbacks
+	db       *DB                            // Owning DB, passed to callbacks run after commit
+	deferAll bool                           // All After* callbacks in a Tx wait for the commit (hooks.deferInTransaction)
+}
+
+func newCallbackRegistry() *CallbackRegistry {
+	return &CallbackRegistry{
+		entries: make(map[HookEvent][]*callbackEntry),
+		ordered: make(map[HookEvent][]*callbackEntry),
+	}
+}
+
+// Callbacks returns the callback registry shared by the DB and its transactions.
+func (db *DB) Callbacks() *CallbackRegistry {
+	return db.callbacks
+}
+
+// Register adds a named callback for an event. Names must be unique per event;
+// Before/After referencing callbacks that are not registered (yet) are ignored until
+// they are. It fails if the constraints form a cycle.
+func (r *CallbackRegistry) Register(event HookEvent, name string, fn CallbackFunc, opts ...CallbackOption) error {
+	if name == "" || name == ModelHook {
+		return fmt.Errorf("callbacks: invalid callback name '%s'", name)
+	}
+	if fn == nil {
+		return fmt.Errorf("callbacks: callback '%s' is nil", name)
+	}
+	entry := &callbackEntry{name: name, fn: fn}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(entry)
+		}
+	}
+	if err := validateAsync(event, entry); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current := r.entriesFor(event)
+	for _, existing := range current {
+		if existing.name == name {
+			return fmt.Errorf("callbacks: '%s' is already registered for %s", name, event)
+		}
+	}
+	updated := append(append([]*callbackEntry{}, current...), entry)
+	ordered, err := sortCallbacks(updated)
+	if err != nil {
+		return fmt.Errorf("callbacks: cannot register '%s' for %s: %w", name, event, err)
+	}
+	r.entries[event] = updated
+	r.ordered[event] = ordered
+	return nil
+}
+
+// Remove unregisters a callback. It reports whether it was registered.
+func (r *CallbackRegistry) Remove(event HookEvent, name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current := r.entriesFor(event)
+	for i, entry := range current {
+		if entry.name == name && entry.fn != nil {
+			updated := append(append([]*callbackEntry{}, current[:i]...), current[i+1:]...)
+			ordered, _ := sortCallbacks(updated) // Removing a node cannot create a cycle
+			r.entries[event] = updated
+			r.ordered[event] = ordered
+			return true
+		}
+	}
+	return false
+}
+
+// Order returns the callback names of an event in execution order (including ModelHook).
+func (r *CallbackRegistry) Order(event HookEvent) []string {
+	entries := r.execution(event)
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.name
+	}
+	return names
+}
+
+// entriesFor returns the registered entries of an event, starting with ModelHook.
+// Callers must hold the lock.
+func (r *CallbackRegistry) entriesFor(event HookEvent) []*callbackEntry {
+	if current, ok := r.entries[event]; ok {
+		return current
+	}
+	return []*callbackEntry{{name: ModelHook}}
+}
+
+func (r *CallbackRegistry) execution(event HookEvent) []*callbackEntry {
+	if r == nil {
+		return []*callbackEntry{{name: ModelHook}}
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if ordered, ok := r.ordered[event]; ok {
+		return ordered
+	}
+	return []*callbackEntry{{name: ModelHook}}
+}
+
+// sortCallbacks orders entries topologically by their Before/After constraints,
+// breaking ties by registration order. Callbacks without Before constraints implicitly
+// run after ModelHook.
+func sortCallbacks(entries []*callbackEntry) ([]*callbackEntry, error) {
+	index := make(map[string]int, len(entries))
+	for i, entry := range entries {
+		index[entry.name] = i
+	}
+	successors := make([][]int, len(entries))
+	inDegree := make([]int, len(entries))
+	addEdge := func(from, to int) {
+		successors[from] = append(successors[from], to)
+		inDegree[to]++
+	}
+	for i, entry := range entries {
+		if entry.fn != nil && len(entry.before) == 0 {
+			addEdge(index[ModelHook], i) // Unless placed before something, run after the model hook
+		}
+		for _, name := range entry.before {
+			if j, ok := index[name]; ok {
+				addEdge(i, j)
+			}
+		}
+		for _, name := range entry.after {
+			if j, ok := index[name]; ok {
+				addEdge(j, i)
+			}
+		}
+	}
+
+	var ready []int
+	for i := range entries {
+		if inDegree[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	ordered := make([]*callbackEntry, 0, len(entries))
+	for len(ready) > 0 {
+		sort.Ints(ready) // Deterministic: earliest registration first
+		next := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, entries[next])
+		for _, successor := range successors[next] {
+			inDegree[successor]--
+			if inDegree[successor] == 0 {
+				ready = append(ready, successor)
+			}
+		}
+	}
+	if len(ordered) != len(entries) {
+		return nil, fmt.Errorf("before/after constraints form a cycle")
+	}
+	return ordered, nil
+}
+
+// runCallbacks runs the callbacks of hc.Event in order, stopping at the first error.
+func runCallbacks(ctx context.Context, registry *CallbackRegistry, hc *HookContext) error {
+	for _, entry := range registry.execution(hc.Event) {
+		if entry.fn == nil {
+			if tx, ok := hc.DB.(*Tx); ok && registry != nil && registry.deferAll && isAfterEvent(hc.Event) {
+				deferToCommit(ctx, registry, entry, hc, tx)
+				continue
+			}
+			if err := callModelHook(ctx, hc); err != nil {
+				return err
+			}
+			continue
+		}
+		if entry.async {
+			dispatchAsync(ctx, registry, entry, hc)
+			continue
+		}
+		if tx, ok := hc.DB.(*Tx); ok && isAfterEvent(hc.Event) && (entry.deferred || registry.deferAll) {
+			deferToCommit(ctx, registry, entry, hc, tx)
+			continue
+		}
+		if err := entry.fn(ctx, hc); err != nil {
+			return fmt.Errorf("callback '%s': %w", entry.name, err)
+		}
+	}
+	return nil
+}
+
+// callModelHook calls the hook method implemented by the model itself, if any.
+func callModelHook(ctx context.Context, hc *HookContext) error {
+	switch hc.Event {
+	case EventBeforeCreate:
+		if h, ok := hc.Value.(hooks.BeforeCreator); ok {
+			return h.BeforeCreate(ctx, hc.DB)
+		}
+	case EventAfterCreate:
+		if h, ok := hc.Value.(hooks.AfterCreator); ok {
+			return h.AfterCreate(ctx, hc.DB)
+		}
+	case EventBeforeUpdate:
+		if h, ok := hc.Value.(hooks.BeforeUpdater); ok {
+			return h.BeforeUpdate(ctx, hc.DB, hc.Data)
+		}
+	case EventAfterUpdate:
+		if h, ok := hc.Value.(hooks.AfterUpdater); ok {
+			return h.AfterUpdate(ctx, hc.DB)
+		}
+	case EventBeforeDelete:
+		if h, ok := hc.Value.(hooks.BeforeDeleter); ok {
+			return h.BeforeDelete(ctx, hc.DB)
+		}
+	case EventAfterDelete:
+		if h, ok := hc.Value.(hooks.AfterDeleter); ok {
+			return h.AfterDelete(ctx, hc.DB)
+		}
+	case EventAfterFind:
+		if h, ok := hc.Value.(hooks.AfterFinder); ok {
+			return h.AfterFind(ctx, hc.DB)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/hooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type hookedPost struct {
+	ID    uint `typegorm:"primaryKey;autoIncrement"`
+	Title string
+	trace *[]string
+}
+
+func (p *hookedPost) BeforeCreate(ctx context.Context, db hooks.ContextDB) error {
+	*p.trace = append(*p.trace, ModelHook)
+	return nil
+}
+
+func (p *hookedPost) BeforeUpdate(ctx context.Context, db hooks.ContextDB, data map[string]any) error {
+	data["title"] = "Normalized"
+	return nil
+}
+
+func tracer(trace *[]string, name string) CallbackFunc {
+	return func(ctx context.Context, hc *HookContext) error {
+		*trace = append(*trace, name)
+		return nil
+	}
+}
+
+func TestCallbacks_OrderAroundModelHook(t *testing.T) {
+	db, source := newMockDB()
+	source.queueRows([]string{"id", "title"}, []any{uint(1), "Hello"}) // Re-fetch after create
+	var trace []string
+	callbacks := db.Callbacks()
+
+	require.NoError(t, callbacks.Register(EventBeforeCreate, "audit", tracer(&trace, "audit")))
+	require.NoError(t, callbacks.Register(EventBeforeCreate, "validate", tracer(&trace, "validate"), Before(ModelHook)))
+	require.NoError(t, callbacks.Register(EventBeforeCreate, "tenant", tracer(&trace, "tenant"), Before("validate")))
+	assert.Equal(t, []string{"tenant", "validate", ModelHook, "audit"}, callbacks.Order(EventBeforeCreate))
+
+	require.NoError(t, db.Create(context.Background(), &hookedPost{Title: "Hello", trace: &trace}).Error)
+	assert.Equal(t, []string{"tenant", "validate", ModelHook, "audit"}, trace)
+
+	assert.True(t, callbacks.Remove(EventBeforeCreate, "tenant"))
+	assert.False(t, callbacks.Remove(EventBeforeCreate, ModelHook), "the model hook cannot be removed")
+	assert.Equal(t, []string{"validate", ModelHook, "audit"}, callbacks.Order(EventBeforeCreate))
+}
+
+func TestCallbacks_RegisterErrors(t *testing.T) {
+	db, _ := newMockDB()
+	callbacks := db.Callbacks()
+	noop := func(ctx context.Context, hc *HookContext) error { return nil }
+
+	require.NoError(t, callbacks.Register(EventAfterFind, "a", noop))
+	assert.Error(t, callbacks.Register(EventAfterFind, "a", noop), "duplicate name")
+	assert.Error(t, callbacks.Register(EventAfterFind, ModelHook, noop), "reserved name")
+	require.NoError(t, callbacks.Register(EventAfterFind, "b", noop, After("a")))
+	assert.ErrorContains(t, callbacks.Register(EventAfterFind, "c", noop, Before("a"), After("b")), "cycle")
+	assert.Equal(t, []string{ModelHook, "a", "b"}, callbacks.Order(EventAfterFind))
+}
+
+func TestCallbacks_AbortAndData(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	var trace []string
+
+	require.NoError(t, db.Callbacks().Register(EventBeforeDelete, "guard", func(ctx context.Context, hc *HookContext) error {
+		return errors.New("protected")
+	}))
+	result := db.Delete(ctx, &hookedPost{ID: 1, trace: &trace})
+	assert.ErrorContains(t, result.Error, "callback 'guard': protected")
+	assert.Empty(t, source.Statements(), "Before* errors abort the operation")
+
+	require.NoError(t, db.Updates(ctx, &hookedPost{ID: 1, trace: &trace}, map[string]any{"title": "raw"}).Error)
+	assert.Equal(t, []any{"Normalized", uint(1)}, source.lastStatement().Args, "model hook modified the data")
+}
+
+func TestCallbacks_AfterFindOnSliceElements(t *testing.T) {
+	db, source := newMockDB()
+	require.NoError(t, db.Callbacks().Register(EventAfterFind, "upper", func(ctx context.Context, hc *HookContext) error {
+		hc.Value.(*maskUser).Name += "!"
+		return nil
+	}))
+
+	source.queueRows([]string{"id", "name", "email", "age"}, []any{uint(1), "Ana", "", 30}, []any{uint(2), "Bia", "", 20})
+	var users []maskUser
+	require.NoError(t, db.Find(context.Background(), &users).Error)
+	assert.Equal(t, "Ana!", users[0].Name)
+	assert.Equal(t, "Bia!", users[1].Name)
+}
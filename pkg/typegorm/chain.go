@@ -0,0 +1,662 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Chainable Model/Table Entry Points ---
+
+// Chain targets a table once — from a model value or by name — and accumulates
+// conditions, so that queries can be composed programmatically and bulk Update,
+// Updates, Delete and Count calls don't need a struct value to identify it:
+//
+//	err := db.Model(&User{}).Where("age > ?", 30).Or(map[string]any{"role": "admin"}).
+//		Order("name").Limit(10).Find(ctx, &users).Error
+//	n, err := db.Model(&User{}).Where(map[string]any{"active": false}).Count(ctx)
+//	res := db.Model(&User{}).Where(map[string]any{"last_login <": cutoff}).Update(ctx, "active", false)
+//	res = db.Table("sessions").Where(map[string]any{"expires_at <": time.Now()}).Delete(ctx)
+//
+// Conditions use the syntax of Find (struct pointer or map with operators) or are SQL
+// with "?" placeholders (a slice argument expands to a list, for IN (?)) or named
+// parameters (":name" with sql.Named or typegorm.Named). Where and Not
+// calls are combined with AND; Or makes the conditions so far one alternative and its
+// condition another. With Table, map keys are plain column names. Chain writes act on
+// all matching rows in one statement: hooks and counter caches, which work on a single
+// record, are not run, and setting a state machine field is rejected. Chains are
+// immutable; every method returns a new Chain.
+type Chain struct {
+	db    *DB
+	tx    *Tx
+	value any    // Model value (Model), nil for Table
+	table string // Table name (Table)
+	conds []chainCond
+	opts  []FindOption // Order, Limit, Offset (Find and First)
+	all   bool         // AllRows: Update/Delete without conditions is intended
+
+	unscoped bool // Unscoped: soft-deleted rows included, Delete removes rows
+}
+
+// chainCond is a condition added by Where, Or or Not.
+type chainCond struct {
+	cond any   // Struct pointer, map or SQL string
+	args []any // Arguments of a SQL string
+	or   bool  // Or: alternative to the conditions before it
+	not  bool  // Not: negated
+}
+
+// Model starts a Chain on the table of the model value (e.g., &User{}).
+func (db *DB) Model(value any) *Chain { return &Chain{db: db, value: value} }
+
+// Table starts a Chain on a table by name, without a model.
+func (db *DB) Table(name string) *Chain { return &Chain{db: db, table: name} }
+
+// Model starts a Chain on the model's table within the transaction. See DB.Model.
+func (tx *Tx) Model(value any) *Chain { return &Chain{tx: tx, value: value} }
+
+// Table starts a Chain on a table by name within the transaction. See DB.Table.
+func (tx *Tx) Table(name string) *Chain { return &Chain{tx: tx, table: name} }
+
+// Where adds a condition (struct pointer, map, or SQL with "?" placeholders and its
+// arguments), combined with the others with AND.
+func (c *Chain) Where(cond any, args ...any) *Chain {
+	return c.with(chainCond{cond: cond, args: args})
+}
+
+// Or makes the conditions added so far one alternative, and cond another:
+// Where(a).Where(b).Or(c) matches (a AND b) OR c.
+func (c *Chain) Or(cond any, args ...any) *Chain {
+	return c.with(chainCond{cond: cond, args: args, or: true})
+}
+
+// Not adds a negated condition, combined with the others with AND.
+func (c *Chain) Not(cond any, args ...any) *Chain {
+	return c.with(chainCond{cond: cond, args: args, not: true})
+}
+
+func (c *Chain) with(cond chainCond) *Chain {
+	next := *c
+	next.conds = append(append([]chainCond{}, c.conds...), cond)
+	return &next
+}
+
+// Order sets the ORDER BY clause of Find and First (see the Order find option).
+func (c *Chain) Order(orderBy string) *Chain { return c.withOption(Order(orderBy)) }
+
+// Limit sets the maximum number of rows returned by Find.
+func (c *Chain) Limit(limit int) *Chain { return c.withOption(Limit(limit)) }
+
+// Offset sets the number of rows skipped by Find and First.
+func (c *Chain) Offset(offset int) *Chain { return c.withOption(Offset(offset)) }
+
+// Joins joins another table to Find, First and Count (see the Joins find option).
+func (c *Chain) Joins(join any, args ...any) *Chain { return c.withOption(Joins(join, args...)) }
+
+// Distinct removes duplicate rows from Find and makes Count count the distinct rows.
+func (c *Chain) Distinct() *Chain { return c.withOption(Distinct()) }
+
+// Group adds columns to the GROUP BY clause of Find; Count counts the groups.
+func (c *Chain) Group(columns string) *Chain { return c.withOption(Group(columns)) }
+
+// Having filters the groups of Group (see the Having find option).
+func (c *Chain) Having(cond any, args ...any) *Chain { return c.withOption(Having(cond, args...)) }
+
+// Columns restricts the columns read by Find (see the Columns find option); with
+// Distinct, Count counts the distinct values of these columns.
+func (c *Chain) Columns(columns ...string) *Chain { return c.withOption(Columns(columns...)) }
+
+func (c *Chain) withOption(opt FindOption) *Chain {
+	next := *c
+	next.opts = append(append([]FindOption{}, c.opts...), opt)
+	return &next
+}
+
+// Find scans the matching rows into dest (pointer to a slice of the model), like
+// DB.Find with the chain's conditions and options. The model is the element type of
+// dest; with Table, the table name is not used.
+func (c *Chain) Find(ctx context.Context, dest any) *Result {
+	args := make([]any, 0, len(c.opts)+1)
+	if len(c.conds) > 0 {
+		args = append(args, chainWhere(c.conds))
+	}
+	for _, opt := range c.opts {
+		args = append(args, opt)
+	}
+	if c.tx != nil {
+		return c.tx.Find(ctx, dest, args...)
+	}
+	return c.db.Find(ctx, dest, args...)
+}
+
+// First scans the first matching row into dest (pointer to a struct). Result.Error is
+// sql.ErrNoRows when no row matches.
+func (c *Chain) First(ctx context.Context, dest any) *Result {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Pointer || destValue.IsNil() || destValue.Elem().Kind() != reflect.Struct {
+		return &Result{Error: fmt.Errorf("destination must be a non-nil pointer to a struct, got %T", dest)}
+	}
+	rows := reflect.New(reflect.SliceOf(destValue.Elem().Type()))
+	result := c.Limit(1).Find(ctx, rows.Interface())
+	if result.Error != nil {
+		return result
+	}
+	if rows.Elem().Len() == 0 {
+		return &Result{Error: sql.ErrNoRows}
+	}
+	destValue.Elem().Set(rows.Elem().Index(0))
+	return result
+}
+
+// AllRows allows Update, Updates and Delete to run without conditions.
+func (c *Chain) AllRows() *Chain {
+	next := *c
+	next.all = true
+	return &next
+}
+
+// Unscoped makes the chain see soft-deleted rows, Delete remove rows for good, and
+// reads skip the model's DefaultScope and DefaultOrder.
+func (c *Chain) Unscoped() *Chain {
+	next := c.withOption(Unscoped())
+	next.unscoped = true
+	return next
+}
+
+// Count returns the number of matching rows (the model's DefaultScope applies), of
+// joined rows with Joins, or of the groups or distinct rows with Group and Distinct.
+func (c *Chain) Count(ctx context.Context) (int64, error) {
+	model, dialect, err := c.resolve()
+	if err != nil {
+		return 0, err
+	}
+	var options queryOptions
+	for _, opt := range c.opts {
+		opt(&options)
+	}
+	rewriter, policies := c.rules()
+	joins, err := planJoins(&queryBuild{dialect: dialect, parser: c.parser(), ctx: ctx, rewriter: rewriter, policies: policies}, model, options)
+	if err == nil && joins != nil {
+		err = checkMaskedUse("Count", model, joins.masked, chainWhere(c.conds), options)
+	}
+	if err != nil {
+		return 0, err
+	}
+	where, args, err := c.where(ctx, "Count", joins.columns(dialect), model, true)
+	if err != nil {
+		return 0, err
+	}
+	args = joins.whereArgs(args)
+	source := quoteTable(dialect, model) + joins.from() + where
+	if options.grouped() {
+		groupBy, havingArgs, err := options.groupClause(joins.columns(dialect), model)
+		if err != nil {
+			return 0, err
+		}
+		selectList := "1"
+		if options.distinct && len(model.Fields) == 0 {
+			selectList = "*" // Table: no known columns
+		} else if options.distinct && len(options.columns) > 0 {
+			columns, _, err := options.selectedColumns(joins.columns(dialect), model)
+			if err != nil {
+				return 0, err
+			}
+			selectList = joins.withJoined(columns)
+		} else if options.distinct {
+			columns, fields := selectColumns(dialect, model)
+			selectList = joins.selectList(dialect, columns, fields)
+		}
+		source = "(" + options.selectKeyword() + selectList + " FROM " + source + groupBy + ") " + dialect.Quote("typegorm_count")
+		args = append(args, havingArgs...)
+	}
+	sqlQuery := renumberBindVars(dialect, "SELECT COUNT(*) FROM "+source)
+
+	var rd reader
+	if c.tx != nil {
+		var leave func()
+		if ctx, leave, err = c.tx.enter(ctx, "Count"); err != nil {
+			return 0, err
+		}
+		defer leave()
+		rd = c.tx.source
+	} else {
+		rd = c.db.reader(ctx)
+	}
+	logSQL("Executing SQL: %s | Args: %v\n", sqlQuery, redactArgs(model, args, chainWhere(c.conds)))
+	var count int64
+	if err := rd.QueryRow(ctx, sqlQuery, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count %s: %w", model.TableName, err)
+	}
+	return count, nil
+}
+
+// Update sets one column (Go field or column name) on all matching rows.
+func (c *Chain) Update(ctx context.Context, column string, value any) *Result {
+	return c.Updates(ctx, map[string]any{column: value})
+}
+
+// Updates sets the columns of data (Go field or column names) on all matching rows,
+// and their UpdatedAt. Primary keys cannot be changed this way.
+func (c *Chain) Updates(ctx context.Context, data map[string]any) (result *Result) {
+	var sqlQuery string
+	defer wrapOpError(&result, c.parser(), "Updates", c.value, &sqlQuery)
+	defer recoverResult(&result, "Updates", c.value)
+	result = &Result{}
+
+	model, dialect, err := c.resolve()
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	statement, args, columns, err := c.updateStatement(ctx, model, dialect, data)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	where, whereArgs, err := c.where(ctx, "Update", dialect, model, false)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	sqlQuery = renumberBindVars(dialect, statement+where)
+	return c.exec(ctx, "Updates", model, sqlQuery, append(args, whereArgs...), columns)
+}
+
+// updateStatement renders "UPDATE <table> SET ..." for data (Go field or column names)
+// and the UpdatedAt of the model, with the SET arguments and the columns set.
+func (c *Chain) updateStatement(ctx context.Context, model *schema.Model, dialect common.Dialect, data map[string]any) (string, []any, map[string]any, error) {
+	if len(data) == 0 {
+		return "", nil, nil, fmt.Errorf("no fields provided for update")
+	}
+	columns := make(map[string]any, len(data))
+	for key, value := range data {
+		column, err := chainColumn(model, key)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		if columns[column], err = interceptValue(ctx, model, column, value); err != nil {
+			return "", nil, nil, err
+		}
+	}
+	if err := rejectBulkTransition(c.machines(), model, columns); err != nil {
+		return "", nil, nil, err
+	}
+	columns = touchUpdatedAt(clock(ctx, c.now()), model, reflect.Value{}, columns)
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	setClauses := make([]string, len(names))
+	args := make([]any, 0, len(names))
+	for i, name := range names {
+		setClauses[i] = assignment(dialect, name, i+1)
+		args = append(args, columns[name])
+	}
+	return "UPDATE " + quoteTable(dialect, model) + " SET " + strings.Join(setClauses, ", "), args, columns, nil
+}
+
+// Delete deletes all matching rows; rows of soft-deleted models get their deletion
+// timestamp set instead (see Unscoped).
+func (c *Chain) Delete(ctx context.Context) (result *Result) {
+	var sqlQuery string
+	defer wrapOpError(&result, c.parser(), "Delete", c.value, &sqlQuery)
+	defer recoverResult(&result, "Delete", c.value)
+	result = &Result{}
+
+	model, dialect, err := c.resolve()
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	where, args, err := c.where(ctx, "Delete", dialect, model, false)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	if softDelete := c.softDeleteField(ctx, model); softDelete != nil {
+		sqlQuery = renumberBindVars(dialect, "UPDATE "+quoteTable(dialect, model)+" SET "+assignment(dialect, softDelete.DBName, 1)+where)
+		return c.exec(ctx, "Delete", model, sqlQuery, append([]any{clock(ctx, c.now())}, args...), nil)
+	}
+	sqlQuery = renumberBindVars(dialect, "DELETE FROM "+quoteTable(dialect, model)+where)
+	return c.exec(ctx, "Delete", model, sqlQuery, args, nil)
+}
+
+// exec runs a write statement. data (the SET values of Updates) and the conditions
+// are used to redact sensitive values in the log.
+func (c *Chain) exec(ctx context.Context, operation string, model *schema.Model, sqlQuery string, args []any, data map[string]any) *Result {
+	var exec execer
+	if c.tx != nil {
+		err := c.tx.checkWritable(operation)
+		if err == nil {
+			var leave func()
+			if ctx, leave, err = c.tx.enter(ctx, operation); err == nil {
+				defer leave()
+			}
+		}
+		if err != nil {
+			return &Result{Error: err}
+		}
+		exec = c.tx.source
+	} else {
+		exec = c.db.conn(ctx)
+	}
+
+	logSQL("Executing SQL: %s | Args: %v\n", sqlQuery, redactArgs(model, args, data, chainWhere(c.conds)))
+	res, err := exec.Exec(ctx, sqlQuery, args...)
+	if err != nil {
+		return &Result{Error: fmt.Errorf("failed to execute %s: %w", strings.ToLower(operation), err)}
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		fmt.Printf("Warning: could not get RowsAffected after %s: %v\n", strings.ToLower(operation), err)
+	}
+	c.invalidateCache(ctx, model)
+	return &Result{RowsAffected: affected}
+}
+
+// invalidateCache drops the cached rows of the model (see CachedEntity), which a bulk
+// write may have changed; in a transaction, again after commit.
+func (c *Chain) invalidateCache(ctx context.Context, model *schema.Model) {
+	if c.tx == nil {
+		invalidateEntityTable(ctx, c.db.cache, model)
+		return
+	}
+	cache := c.tx.cache
+	invalidateEntityTable(ctx, cache, model)
+	if _, ok := entityCacheTTL(cache, model); ok {
+		c.tx.AfterCommit(func() { invalidateEntityTable(context.Background(), cache, model) })
+	}
+}
+
+// rules returns the condition rewriter and the policies of the chain's DB or Tx.
+func (c *Chain) rules() (ConditionRewriter, []PolicyFunc) {
+	if c.tx != nil {
+		return c.tx.rewriter, c.tx.policies
+	}
+	return c.db.rewriter, c.db.policies
+}
+
+// where renders the " WHERE ..." clause of the chain's conditions, after the condition
+// rewriter and policies. Writes without conditions require AllRows; reads apply the model's DefaultScope.
+// Soft-deleted rows are excluded unless the chain is unscoped.
+func (c *Chain) where(ctx context.Context, op string, dialect common.Dialect, model *schema.Model, read bool) (string, []any, error) {
+	rewriter, policies := c.rules()
+	cond, err := rewriteCondition(ctx, rewriter, policies, op, model, chainWhere(c.conds))
+	if err != nil {
+		return "", nil, err
+	}
+	clauses, args, err := buildWhereClause(dialect, model, cond)
+	if err != nil {
+		return "", nil, err
+	}
+	if read {
+		options := queryOptions{unscoped: c.unscoped || isUnscoped(ctx)}
+		if clauses, args, err = applyDefaultScope(dialect, model, clauses, args, &options); err != nil {
+			return "", nil, err
+		}
+	} else if len(clauses) == 0 && !c.all {
+		return "", nil, ErrMissingWhereClause
+	} else if softDelete := c.softDeleteField(ctx, model); softDelete != nil {
+		clauses = append(clauses, dialect.Quote(softDelete.DBName)+" IS NULL")
+	}
+	if len(clauses) == 0 {
+		return "", nil, nil
+	}
+	return " WHERE " + strings.Join(clauses, " AND "), args, nil
+}
+
+// softDeleteField returns the deletion timestamp of a soft-deleted model, nil when the
+// model has none or the chain is unscoped.
+func (c *Chain) softDeleteField(ctx context.Context, model *schema.Model) *schema.Field {
+	if c.unscoped {
+		return nil
+	}
+	return softDeleteField(ctx, model)
+}
+
+// now returns the clock of the chain's DB or transaction.
+func (c *Chain) machines() *stateMachines {
+	if c.tx != nil {
+		return c.tx.machines
+	}
+	return c.db.machines
+}
+
+func (c *Chain) now() NowFunc {
+	if c.tx != nil {
+		return c.tx.now
+	}
+	return c.db.now
+}
+
+// resolve returns the target model (a column-less model for Table) and the dialect.
+func (c *Chain) resolve() (*schema.Model, common.Dialect, error) {
+	dialect := c.dialect()
+	if c.value != nil {
+		model, err := c.parser().Parse(c.value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse schema for type %T: %w", c.value, err)
+		}
+		return model, dialect, nil
+	}
+	if c.table == "" {
+		return nil, nil, fmt.Errorf("chain: Model or Table is required")
+	}
+	schemaName, table := schema.SplitQualifiedName(c.table)
+	return &schema.Model{Name: c.table, TableName: table, Schema: schemaName}, dialect, nil
+}
+
+func (c *Chain) parser() *schema.Parser {
+	if c.tx != nil {
+		return c.tx.parser
+	}
+	return c.db.parser
+}
+
+func (c *Chain) dialect() common.Dialect {
+	if c.tx != nil {
+		return c.tx.dialect
+	}
+	return c.db.source.Dialect()
+}
+
+// chainColumn resolves an update key (Go field or column name) to a column.
+func chainColumn(model *schema.Model, key string) (string, error) {
+	if model.Type == nil {
+		if !bareIdentifierRe.MatchString(key) || strings.Contains(key, ".") {
+			return "", fmt.Errorf("invalid column name '%s' for table %s", key, model.TableName)
+		}
+		return key, nil
+	}
+	field, ok := model.GetFieldByDBName(key)
+	if !ok {
+		field, ok = model.GetField(key)
+	}
+	if !ok || field.IsIgnored {
+		return "", fmt.Errorf("invalid column name '%s' provided in update data for model %s", key, model.Name)
+	}
+	if field.IsPrimaryKey {
+		return "", fmt.Errorf("primary key '%s' cannot be updated with Chain.Updates", field.DBName)
+	}
+	return field.DBName, nil
+}
+
+// chainWhere is the condition of a Chain, accepted by buildWhereClause.
+type chainWhere []chainCond
+
+// build renders the conditions: the clauses of each alternative joined with AND, and
+// the alternatives (if Or was used) joined with OR in a single clause.
+func (w chainWhere) build(dialect common.Dialect, model *schema.Model) ([]string, []any, error) {
+	var alternatives [][]string
+	var current []string
+	var args []any
+	for i, cond := range w {
+		if cond.or && i > 0 {
+			alternatives = append(alternatives, current)
+			current = nil
+		}
+		var condClauses []string
+		var condArgs []any
+		var err error
+		if raw, ok := cond.cond.(string); ok {
+			var clause string
+			clause, condArgs, err = rawCondition(dialect, raw, cond.args)
+			condClauses = []string{clause}
+		} else if len(cond.args) > 0 {
+			err = fmt.Errorf("arguments are only allowed with SQL conditions, got %T", cond.cond)
+		} else {
+			condClauses, condArgs, err = buildWhereClause(dialect, model, cond.cond)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if cond.not && len(condClauses) > 0 {
+			condClauses = []string{"NOT (" + strings.Join(condClauses, " AND ") + ")"}
+		}
+		current = append(current, condClauses...)
+		args = append(args, condArgs...)
+	}
+	if len(alternatives) == 0 {
+		return current, args, nil
+	}
+	alternatives = append(alternatives, current)
+	parts := make([]string, 0, len(alternatives))
+	for _, clauses := range alternatives {
+		if len(clauses) > 0 {
+			parts = append(parts, "("+strings.Join(clauses, " AND ")+")")
+		}
+	}
+	return []string{"(" + strings.Join(parts, " OR ") + ")"}, args, nil
+}
+
+// rawCondition replaces the "?" placeholders of a SQL condition (see bindPlaceholders).
+// The condition is always parenthesized, so that it keeps its precedence once combined
+// with other conditions whatever operators it uses (OR on a new line, "OR(", ...).
+func rawCondition(dialect common.Dialect, raw string, args []any) (string, []any, error) {
+	clause, expanded, err := bindPlaceholders(dialect, raw, args)
+	if err != nil {
+		return "", nil, err
+	}
+	return "(" + clause + ")", expanded, nil
+}
+
+// bindPlaceholders replaces the "?" placeholders of a SQL fragment (outside string
+// literals) with the dialect's bind variables (numbered later by renumberBindVars),
+// expanding slice arguments into lists. Named parameters — ":name", or "@name" for a
+// name that has an argument — take their values from the sql.NamedArg arguments
+// (sql.Named) and NamedArgs arguments (Named), the other arguments fill the "?" in order:
+//
+//	"status = :status AND created_at > :since", sql.Named("status", "paid"), sql.Named("since", t)
+func bindPlaceholders(dialect common.Dialect, raw string, args []any) (string, []any, error) {
+	named, args := splitNamedArgs(args)
+	var b strings.Builder
+	var expanded []any
+	bind := func(value any) {
+		arg := reflect.ValueOf(value)
+		if arg.Kind() == reflect.Slice && arg.Type().Elem().Kind() != reflect.Uint8 {
+			if arg.Len() == 0 {
+				b.WriteString("NULL") // IN (NULL) matches nothing
+				return
+			}
+			for j := 0; j < arg.Len(); j++ {
+				if j > 0 {
+					b.WriteString(", ")
+				}
+				b.WriteString(dialect.BindVar(1))
+				expanded = append(expanded, arg.Index(j).Interface())
+			}
+			return
+		}
+		b.WriteString(dialect.BindVar(1))
+		expanded = append(expanded, value)
+	}
+
+	n := 0
+	inString := false
+	for i := 0; i < len(raw); i++ {
+		ch := raw[i]
+		if ch == '\'' {
+			inString = !inString
+		}
+		if inString {
+			b.WriteByte(ch)
+			continue
+		}
+		if name := namedParameter(raw, i); name != "" && named != nil {
+			value, ok := named[name]
+			if !ok && ch == ':' {
+				return "", nil, fmt.Errorf("condition %q has no argument for the parameter :%s", raw, name)
+			}
+			if ok {
+				bind(value)
+				i += len(name)
+				continue
+			}
+		}
+		if ch != '?' {
+			b.WriteByte(ch)
+			continue
+		}
+		if n >= len(args) {
+			return "", nil, fmt.Errorf("condition %q has more placeholders than arguments (%d)", raw, len(args))
+		}
+		bind(args[n])
+		n++
+	}
+	if n != len(args) {
+		return "", nil, fmt.Errorf("condition %q has %d placeholder(s) for %d argument(s)", raw, n, len(args))
+	}
+	return strings.TrimSpace(b.String()), expanded, nil
+}
+
+// splitNamedArgs separates the named arguments (sql.NamedArg, NamedArgs) from the
+// positional ones; named is nil without named arguments. A plain map stays positional.
+func splitNamedArgs(args []any) (named map[string]any, positional []any) {
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case sql.NamedArg:
+			if named == nil {
+				named = make(map[string]any)
+			}
+			named[v.Name] = v.Value
+		case NamedArgs:
+			if named == nil {
+				named = make(map[string]any, len(v))
+			}
+			for name, value := range v {
+				named[name] = value
+			}
+		default:
+			positional = append(positional, arg)
+		}
+	}
+	return named, positional
+}
+
+// namedParameter returns the name of the parameter starting at raw[i] (":name" or
+// "@name"), or "". Casts ("x::int") and system variables ("@@version") are not
+// parameters.
+func namedParameter(raw string, i int) string {
+	if (raw[i] != ':' && raw[i] != '@') || (i > 0 && (raw[i-1] == raw[i] || isNameByte(raw[i-1]))) {
+		return ""
+	}
+	end := i + 1
+	for end < len(raw) && isNameByte(raw[end]) {
+		end++
+	}
+	if end == i+1 || (raw[i+1] >= '0' && raw[i+1] <= '9') {
+		return ""
+	}
+	return raw[i+1 : end]
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
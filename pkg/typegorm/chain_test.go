@@ -0,0 +1,206 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain_UpdateAndDelete(t *testing.T) {
+	db, source := newMockDB()
+	source.affected = 3
+	ctx := context.Background()
+
+	users := db.Model(&maskUser{}).Where(map[string]any{"age <": 18})
+	result := users.Updates(ctx, map[string]any{"Name": "minor", "email": ""})
+	require.NoError(t, result.Error)
+	assert.Equal(t, int64(3), result.RowsAffected)
+	last := source.lastStatement()
+	assert.Equal(t, "UPDATE `mask_users` SET `email` = ?, `name` = ? WHERE `age` < ?", last.SQL)
+	assert.Equal(t, []any{"", "minor", 18}, last.Args)
+
+	require.NoError(t, users.Where(map[string]any{"name": "x"}).Delete(ctx).Error)
+	assert.Equal(t, "DELETE FROM `mask_users` WHERE `age` < ? AND `name` = ?", source.lastStatement().SQL)
+}
+
+func TestChain_RequiresConditionsForWrites(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	assert.ErrorIs(t, db.Table("sessions").Delete(ctx).Error, ErrMissingWhereClause)
+	assert.Empty(t, source.Statements())
+
+	require.NoError(t, db.Table("sessions").AllRows().Delete(ctx).Error)
+	assert.Equal(t, "DELETE FROM `sessions`", source.lastStatement().SQL)
+
+	assert.Error(t, db.Model(&maskUser{}).AllRows().Update(ctx, "id", 1).Error, "primary keys are not updatable")
+	assert.Error(t, db.Table("sessions").AllRows().Update(ctx, "bad column", 1).Error)
+}
+
+func TestChain_TableConditionsAndCount(t *testing.T) {
+	db, source := newMockDB()
+	source.queueRows([]string{"count"}, []any{int64(7)})
+
+	n, err := db.Table("sessions").Where(map[string]any{"user_id in": []int{1, 2}}).Count(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), n)
+	last := source.lastStatement()
+	assert.Equal(t, "SELECT COUNT(*) FROM `sessions` WHERE `user_id` IN (?, ?)", last.SQL)
+	assert.Equal(t, []any{1, 2}, last.Args)
+
+	_, err = db.Table("sessions").Where(map[string]any{"x; DROP": 1}).Count(context.Background())
+	assert.Error(t, err)
+}
+
+func TestChain_InReadOnlyTransaction(t *testing.T) {
+	db, _ := newMockDB()
+	tx, err := db.BeginReadOnly(context.Background())
+	require.NoError(t, err)
+	result := tx.Model(&maskUser{}).AllRows().Delete(context.Background())
+	assert.ErrorIs(t, result.Error, ErrReadOnlyTransaction)
+}
+
+func TestChain_FindComposesConditions(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	source.queueRows([]string{"id", "name", "email", "age"}, []any{uint(1), "Ana", "ana@example.com", 31})
+
+	var users []maskUser
+	result := db.Model(&maskUser{}).Where("age > ?", 30).Where(map[string]any{"name like": "A%"}).
+		Or("email IN (?)", []string{"a@x", "b@x"}).Not(map[string]any{"id": 7}).
+		Order("name DESC").Limit(10).Find(ctx, &users)
+	require.NoError(t, result.Error)
+	require.Len(t, users, 1)
+	last := source.lastStatement()
+	assert.Equal(t, "SELECT `id`, `name`, `email`, `age` FROM `mask_users` WHERE "+
+		"(((age > ?) AND `name` LIKE ?) OR ((email IN (?, ?)) AND NOT (`id` = ?))) ORDER BY `name` DESC LIMIT 10", last.SQL)
+	assert.Equal(t, []any{30, "A%", "a@x", "b@x", 7}, last.Args)
+
+	// Chains are immutable; conditions also apply to Count
+	base := db.Model(&maskUser{}).Where("name = 'who?' OR age = ?", 5)
+	_, _ = base.Where("id > ?", 1).Count(ctx)
+	assert.Equal(t, "SELECT COUNT(*) FROM `mask_users` WHERE (name = 'who?' OR age = ?) AND (id > ?)", source.lastStatement().SQL)
+	_, _ = base.Count(ctx)
+	assert.Equal(t, []any{5}, source.lastStatement().Args)
+}
+
+func TestChain_First(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	source.queueRows([]string{"id", "name", "email", "age"}, []any{uint(2), "Bia", "bia@example.com", 20})
+
+	var user maskUser
+	require.NoError(t, db.Model(&maskUser{}).Where("age >= ?", 18).Order("age").First(ctx, &user).Error)
+	assert.Equal(t, "Bia", user.Name)
+	assert.Contains(t, source.lastStatement().SQL, "WHERE (age >= ?) ORDER BY `age` LIMIT 1")
+
+	assert.ErrorIs(t, db.Model(&maskUser{}).Where("age > ?", 99).First(ctx, &user).Error, sql.ErrNoRows)
+	assert.Error(t, db.Model(&maskUser{}).Where("age > ? AND id = ?", 1).Find(ctx, &[]maskUser{}).Error)
+	assert.Error(t, db.Model(&maskUser{}).Where(map[string]any{"age": 1}, 2).Find(ctx, &[]maskUser{}).Error)
+}
+
+// ageGroup is a report struct mapping the grouped column of mask_users.
+type ageGroup struct {
+	_   struct{} `typegorm:"table:mask_users"`
+	Age int
+}
+
+func TestFind_DistinctGroupHaving(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	source.queueRows([]string{"age"}, []any{30}, []any{40})
+
+	var groups []ageGroup
+	require.NoError(t, db.Find(ctx, &groups, map[string]any{"age >": 18},
+		Group("age"), Having("COUNT(*) > ?", 1), Order("age")).Error)
+	assert.Equal(t, []ageGroup{{Age: 30}, {Age: 40}}, groups)
+	last := source.lastStatement()
+	assert.Equal(t, "SELECT `age` FROM `mask_users` WHERE `age` > ? GROUP BY `age` HAVING (COUNT(*) > ?) ORDER BY `age`", last.SQL)
+	assert.Equal(t, []any{18, 1}, last.Args)
+
+	require.NoError(t, db.Find(ctx, &groups, Distinct()).Error)
+	assert.Equal(t, "SELECT DISTINCT `age` FROM `mask_users`", source.lastStatement().SQL)
+
+	assert.Error(t, db.Find(ctx, &groups, Group("age"), Having(map[string]any{"x; DROP": 1})).Error)
+}
+
+// ageTotal is a report struct of mask_users with an aggregate.
+type ageTotal struct {
+	_     struct{} `typegorm:"table:mask_users"`
+	Age   int
+	Total int64
+}
+
+func TestFind_ColumnsSelectGroupedColumnsAndAggregates(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	source.queueRows([]string{"age", "total"}, []any{30, int64(2)})
+
+	var totals []ageTotal
+	require.NoError(t, db.Find(ctx, &totals, Group("age"), Columns("age", "COUNT(*) AS total")).Error)
+	assert.Equal(t, []ageTotal{{Age: 30, Total: 2}}, totals)
+	assert.Equal(t, "SELECT `age`, COUNT(*) AS `total` FROM `mask_users` GROUP BY `age`", source.lastStatement().SQL)
+
+	var users []maskUser
+	require.NoError(t, db.Model(&maskUser{}).Group("age").Columns("Age").Find(ctx, &users).Error)
+	assert.Equal(t, "SELECT `age` FROM `mask_users` GROUP BY `age`", source.lastStatement().SQL)
+	assert.ErrorContains(t, db.Find(ctx, &users, Columns("SUM(age) AS sum")).Error, "is not a field of maskUser")
+
+	// FindFirst honors Distinct, Group and Having
+	source.queueRows([]string{"age", "total"}, []any{40, int64(3)})
+	var first ageTotal
+	require.NoError(t, db.FindFirst(ctx, &first, Group("age"), Having("COUNT(*) > ?", 2), Columns("age", "COUNT(*) AS total"), Order("total DESC")).Error)
+	assert.Equal(t, ageTotal{Age: 40, Total: 3}, first)
+	last := source.lastStatement()
+	assert.Equal(t, "SELECT `age`, COUNT(*) AS `total` FROM `mask_users` GROUP BY `age` HAVING (COUNT(*) > ?) ORDER BY `total` DESC LIMIT 1", last.SQL)
+	assert.Equal(t, []any{2}, last.Args)
+	var group ageGroup
+	_ = db.FindFirst(ctx, &group, Distinct())
+	assert.Equal(t, "SELECT DISTINCT `age` FROM `mask_users` LIMIT 1", source.lastStatement().SQL)
+}
+
+func TestChain_CountGroupsAndDistinctRows(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	source.queueRows([]string{"count"}, []any{int64(3)})
+
+	n, err := db.Model(&maskUser{}).Where("age > ?", 18).Group("age").Having(map[string]any{"age <": 60}).Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	last := source.lastStatement()
+	assert.Equal(t, "SELECT COUNT(*) FROM (SELECT 1 FROM `mask_users` WHERE (age > ?) GROUP BY `age` HAVING `age` < ?) `typegorm_count`", last.SQL)
+	assert.Equal(t, []any{18, 60}, last.Args)
+
+	_, _ = db.Model(&maskUser{}).Distinct().Count(ctx)
+	assert.Equal(t, "SELECT COUNT(*) FROM (SELECT DISTINCT `id`, `name`, `email`, `age` FROM `mask_users`) `typegorm_count`", source.lastStatement().SQL)
+	_, _ = db.Model(&maskUser{}).Distinct().Columns("age").Count(ctx)
+	assert.Equal(t, "SELECT COUNT(*) FROM (SELECT DISTINCT `age` FROM `mask_users`) `typegorm_count`", source.lastStatement().SQL)
+}
+
+func TestChain_RawConditionsKeepTheirPrecedence(t *testing.T) {
+	ctx := context.Background()
+	for _, raw := range []string{"name = 'a'\nOR name = 'b'", "name = 'a'\tOR name = 'b'", "name = 'a' OR(name = 'b')"} {
+		db, source := newMockDB()
+
+		require.NoError(t, db.Model(&maskUser{}).Where(raw).Where(map[string]any{"age": 7}).Delete(ctx).Error)
+		assert.Equal(t, "DELETE FROM `mask_users` WHERE ("+raw+") AND `age` = ?", source.lastStatement().SQL)
+
+		require.NoError(t, db.Model(&maskUser{}).Where(map[string]any{"age": 7}).Not(raw).Update(ctx, "name", "x").Error)
+		assert.Equal(t, "UPDATE `mask_users` SET `name` = ? WHERE `age` = ? AND NOT (("+raw+"))", source.lastStatement().SQL)
+
+		var users []maskUser
+		require.NoError(t, db.Find(ctx, &users, And(SQL(raw), Eq("age", 7))).Error)
+		assert.Equal(t, "SELECT `id`, `name`, `email`, `age` FROM `mask_users` WHERE (("+raw+") AND `age` = ?)", source.lastStatement().SQL)
+
+		db.UseConditionRewriter(func(ctx context.Context, op string, model *schema.Model, cond Expr) (Expr, error) {
+			return And(cond, Eq("age", 42)), nil // Tenant-style filter
+		})
+		require.NoError(t, db.Model(&maskUser{}).Where(raw).Delete(ctx).Error)
+		assert.Equal(t, "DELETE FROM `mask_users` WHERE (("+raw+") AND `age` = ?)", source.lastStatement().SQL, "the filter applies to every row")
+		assert.Equal(t, []any{42}, source.lastStatement().Args)
+	}
+}
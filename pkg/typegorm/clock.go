@@ -0,0 +1,172 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Clock and ID Generation ---
+
+// NowFunc returns the current time. Create fills zero CreatedAt/UpdatedAt fields (and
+// those tagged autoCreateTime/autoUpdateTime) with the clock's time, and every update
+// sets UpdatedAt; set it with DB.UseNowFunc (or Session.WithNowFunc) so that tests can
+// freeze time:
+//
+//	frozen := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+//	db.UseNowFunc(func() time.Time { return frozen })
+type NowFunc func() time.Time
+
+// IDGenerator returns the primary key of a new row. Once set with DB.UseIDGenerator
+// (or Session.WithIDGenerator), Create calls it for models with a single primary key
+// that is not auto-incremented and left zero; the value must be assignable (or
+// convertible) to the key field.
+type IDGenerator func(ctx context.Context, model *schema.Model) (any, error)
+
+type nowFuncKey struct{}
+type idGeneratorKey struct{}
+
+// UseNowFunc sets the clock of timestamps, soft deletes, RunRetention and state
+// machine history (nil restores the default, time.Now).
+// Call it before the DB is shared between goroutines.
+func (db *DB) UseNowFunc(now NowFunc) {
+	db.now = now
+}
+
+// UseIDGenerator sets the generator of primary keys (nil disables it). Call it before
+// the DB is shared between goroutines.
+func (db *DB) UseIDGenerator(gen IDGenerator) {
+	db.ids = gen
+}
+
+// WithNowFunc returns a copy of the session whose operations (also in its
+// transactions) use now instead of the DB's clock.
+func (s *Session) WithNowFunc(now NowFunc) *Session {
+	return &Session{db: s.db, ctx: context.WithValue(s.ctx, nowFuncKey{}, now)}
+}
+
+// WithIDGenerator returns a copy of the session whose operations (also in its
+// transactions) use gen instead of the DB's ID generator.
+func (s *Session) WithIDGenerator(gen IDGenerator) *Session {
+	return &Session{db: s.db, ctx: context.WithValue(s.ctx, idGeneratorKey{}, gen)}
+}
+
+// nowFunc returns the clock set on ctx (by a Session), else fallback; nil when none is set.
+func nowFunc(ctx context.Context, fallback NowFunc) NowFunc {
+	if now, ok := ctx.Value(nowFuncKey{}).(NowFunc); ok && now != nil {
+		return now
+	}
+	return fallback
+}
+
+// clock returns the time of nowFunc, defaulting to time.Now.
+func clock(ctx context.Context, fallback NowFunc) time.Time {
+	if now := nowFunc(ctx, fallback); now != nil {
+		return now()
+	}
+	return time.Now()
+}
+
+func idGenerator(ctx context.Context, fallback IDGenerator) IDGenerator {
+	if gen, ok := ctx.Value(idGeneratorKey{}).(IDGenerator); ok && gen != nil {
+		return gen
+	}
+	return fallback
+}
+
+// applyCreateDefaults sets the zero timestamps of the struct about to be inserted, and
+// its primary key when a generator is set. For an upsert, which may update an existing
+// row, the update timestamps are set to the clock even when the struct has one (e.g.
+// the time it was loaded), as Updates does.
+func applyCreateDefaults(ctx context.Context, now NowFunc, gen IDGenerator, model *schema.Model, structValue reflect.Value, upsert bool) error {
+	if gen != nil && len(model.PrimaryKeys) == 1 && !model.PrimaryKeys[0].AutoIncrement {
+		pk := structValue.FieldByName(model.PrimaryKeys[0].GoName)
+		if pk.IsValid() && pk.CanSet() && pk.IsZero() {
+			id, err := gen(ctx, model)
+			if err != nil {
+				return fmt.Errorf("failed to generate primary key for %s: %w", model.Name, err)
+			}
+			value := reflect.ValueOf(id)
+			switch {
+			case !value.IsValid():
+				return fmt.Errorf("ID generator returned nil for %s", model.Name)
+			case value.Type().AssignableTo(pk.Type()):
+				pk.Set(value)
+			case value.CanConvert(pk.Type()):
+				pk.Set(value.Convert(pk.Type()))
+			default:
+				return fmt.Errorf("ID generator returned %T for %s.%s (%s)", id, model.Name, model.PrimaryKeys[0].GoName, pk.Type())
+			}
+		}
+	}
+	t := clock(ctx, now)
+	for _, field := range model.Fields {
+		if field.IsIgnored || !(field.IsAutoCreateTime() || field.IsAutoUpdateTime()) {
+			continue
+		}
+		if fieldValue := structValue.FieldByName(field.GoName); upsert && field.IsAutoUpdateTime() && fieldValue.CanSet() {
+			setTime(fieldValue, t)
+		} else {
+			setZeroTime(fieldValue, t)
+		}
+	}
+	return nil
+}
+
+// touchUpdatedAt returns data with the update timestamps set to t (on the struct too,
+// when valid), unless data sets them already.
+func touchUpdatedAt(t time.Time, model *schema.Model, structValue reflect.Value, data map[string]any) map[string]any {
+	var touched map[string]any
+	for _, field := range model.Fields {
+		if field.IsIgnored || !field.IsAutoUpdateTime() {
+			continue
+		}
+		if _, ok := data[field.DBName]; ok {
+			continue
+		}
+		if touched == nil {
+			touched = make(map[string]any, len(data)+1)
+			for key, value := range data {
+				touched[key] = value
+			}
+		}
+		touched[field.DBName] = t
+		if !structValue.IsValid() {
+			continue
+		}
+		if fieldValue := structValue.FieldByName(field.GoName); fieldValue.IsValid() && fieldValue.CanSet() {
+			setTime(fieldValue, t)
+		}
+	}
+	if touched == nil {
+		return data
+	}
+	return touched
+}
+
+var timeValueType = reflect.TypeOf(time.Time{})
+
+// setZeroTime sets a zero time.Time or nil *time.Time field to t.
+func setZeroTime(field reflect.Value, t time.Time) {
+	if !field.IsValid() || !field.CanSet() {
+		return
+	}
+	switch {
+	case field.Type() == timeValueType && field.Interface().(time.Time).IsZero():
+		setTime(field, t)
+	case field.Kind() == reflect.Pointer && field.Type().Elem() == timeValueType && (field.IsNil() || field.Elem().Interface().(time.Time).IsZero()):
+		setTime(field, t)
+	}
+}
+
+func setTime(field reflect.Value, t time.Time) {
+	switch {
+	case field.Type() == timeValueType:
+		field.Set(reflect.ValueOf(t))
+	case field.Kind() == reflect.Pointer && field.Type().Elem() == timeValueType:
+		field.Set(reflect.ValueOf(&t))
+	}
+}
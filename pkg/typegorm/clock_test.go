@@ -0,0 +1,120 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clockedNote struct {
+	ID        string `typegorm:"primaryKey;size:36"`
+	Body      string
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+}
+
+func TestUseNowFunc_FreezesTimestamps(t *testing.T) {
+	db, source := newMockDB()
+	frozen := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	db.UseNowFunc(func() time.Time { return frozen })
+	ids := 0
+	db.UseIDGenerator(func(ctx context.Context, model *schema.Model) (any, error) {
+		ids++
+		return fmt.Sprintf("%s-%d", model.TableName, ids), nil
+	})
+	ctx := context.Background()
+
+	note := &clockedNote{Body: "hello"}
+	require.NoError(t, db.Create(ctx, note).Error)
+	assert.Equal(t, "clocked_notes-1", note.ID)
+	assert.Equal(t, frozen, note.CreatedAt)
+	require.NotNil(t, note.UpdatedAt)
+	assert.Equal(t, frozen, *note.UpdatedAt)
+	insert := source.Statements()[0]
+	assert.Equal(t, "INSERT INTO `clocked_notes` (`id`, `body`, `created_at`, `updated_at`) VALUES (?, ?, ?, ?)", insert.SQL)
+	assert.Equal(t, []any{"clocked_notes-1", "hello", frozen, &frozen}, insert.Args)
+
+	later := frozen.Add(time.Hour)
+	session := db.WithContext(ctx).WithNowFunc(func() time.Time { return later })
+	require.NoError(t, session.Updates(note, map[string]any{"body": "edited"}).Error)
+	assert.Equal(t, later, *note.UpdatedAt)
+	update := source.lastStatement()
+	assert.Contains(t, update.SQL, "`updated_at` = ?")
+	assert.Contains(t, update.Args, later)
+}
+
+func TestCreate_WithoutNowFuncUsesTimeNow(t *testing.T) {
+	db, source := newMockDB()
+	before := time.Now()
+	note := &clockedNote{ID: "n1", Body: "hello"}
+	require.NoError(t, db.Create(context.Background(), note).Error)
+	assert.Equal(t, "INSERT INTO `clocked_notes` (`id`, `body`, `created_at`, `updated_at`) VALUES (?, ?, ?, ?)", source.Statements()[0].SQL)
+	assert.False(t, note.CreatedAt.Before(before))
+	require.NotNil(t, note.UpdatedAt)
+	assert.Equal(t, note.CreatedAt, *note.UpdatedAt)
+}
+
+func TestUpdates_TouchUpdatedAtOnEveryPath(t *testing.T) {
+	db, source := newMockDB()
+	frozen := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	db.UseNowFunc(func() time.Time { return frozen })
+	ctx := context.Background()
+
+	note := &clockedNote{ID: "n1", Body: "hello"}
+	require.NoError(t, db.UpdateIf(ctx, note, map[string]any{"body": "edited"}, map[string]any{"body": "hello"}).Error)
+	assert.Contains(t, source.lastStatement().SQL, "`updated_at` = ?")
+	assert.Equal(t, frozen, *note.UpdatedAt)
+
+	require.NoError(t, db.Model(&clockedNote{}).Where(map[string]any{"body": "x"}).Update(ctx, "body", "y").Error)
+	assert.Equal(t, "UPDATE `clocked_notes` SET `body` = ?, `updated_at` = ? WHERE `body` = ?", source.lastStatement().SQL)
+	assert.Equal(t, []any{"y", frozen, "x"}, source.lastStatement().Args)
+
+	require.NoError(t, db.Update(ctx, &clockedNote{ID: "n1", Body: "z"}).Error)
+	assert.Contains(t, source.lastStatement().Args, frozen)
+
+	lastInsert := func() mockStatement {
+		statements := source.Statements()
+		for i := len(statements) - 1; i >= 0; i-- {
+			if strings.HasPrefix(statements[i].SQL, "INSERT") {
+				return statements[i]
+			}
+		}
+		return mockStatement{}
+	}
+	loaded := frozen.Add(-time.Hour) // Stale timestamps of a loaded struct are not written back
+	note = &clockedNote{ID: "n1", Body: "saved", CreatedAt: loaded, UpdatedAt: &loaded}
+	require.NoError(t, db.Save(ctx, note).Error)
+	assert.Equal(t, frozen, *note.UpdatedAt)
+	assert.Equal(t, loaded, note.CreatedAt)
+	assert.Contains(t, lastInsert().SQL, "`updated_at` = ")
+	assert.Contains(t, lastInsert().Args, &frozen)
+
+	note = &clockedNote{ID: "n1", Body: "upserted", UpdatedAt: &loaded}
+	require.NoError(t, db.Create(ctx, note, OnConflict(Conflict{DoUpdates: []string{"Body"}})).Error)
+	assert.Contains(t, lastInsert().SQL, "`body` = ")
+	assert.Contains(t, lastInsert().SQL, "`updated_at` = ", "requested updates also touch updated_at")
+	assert.Equal(t, frozen, *note.UpdatedAt)
+}
+
+func TestManualTimestamps_OptOutOfConventions(t *testing.T) {
+	type stampedNote struct {
+		ID        string    `typegorm:"primaryKey;size:36"`
+		CreatedAt time.Time `typegorm:"autoCreateTime:false"`
+		EditedAt  time.Time `typegorm:"updatedAt"`
+	}
+	db, source := newMockDB()
+	frozen := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	db.UseNowFunc(func() time.Time { return frozen })
+
+	note := &stampedNote{ID: "n1"}
+	require.NoError(t, db.Create(context.Background(), note).Error)
+	assert.True(t, note.CreatedAt.IsZero(), "CreatedAt is a plain column")
+	assert.Equal(t, frozen, note.EditedAt)
+	assert.Equal(t, []any{"n1", time.Time{}, frozen}, source.Statements()[0].Args)
+}
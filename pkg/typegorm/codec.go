@@ -0,0 +1,64 @@
+package typegorm
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+)
+
+// --- Payload Codecs ---
+
+// Codec serializes the payloads the ORM stores outside the database, such as the rows
+// of the entity cache. The default is JSON; set another one with DB.UseCodec, e.g. a
+// msgpack or protobuf codec, or a wrapper adding a version header to the payloads:
+//
+//	type msgpackCodec struct{}
+//
+//	func (msgpackCodec) Marshal(v any) ([]byte, error)      { return msgpack.Marshal(v) }
+//	func (msgpackCodec) Unmarshal(data []byte, v any) error { return msgpack.Unmarshal(data, v) }
+//
+// Payloads are structs whose fields carry `json` and `msgpack` tags; Unmarshal receives
+// a pointer to such a struct. Changing the codec makes existing payloads unreadable:
+// the entity cache then drops them and reloads the rows. The entity cache decodes every
+// payload it writes and compares it with the row: rows the codec cannot reproduce
+// (with JSON, e.g. interface fields or types without JSON support) are not cached.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONCodec encodes payloads with encoding/json (the default).
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (JSONCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// GobCodec encodes payloads with encoding/gob, which is more compact than JSON and
+// keeps Go types exactly (e.g. the location of time.Time values).
+type GobCodec struct{}
+
+func (GobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// UseCodec sets the codec of the payloads (nil restores JSON). Call it before the DB
+// is shared between goroutines.
+func (db *DB) UseCodec(codec Codec) {
+	db.codec = codec
+}
+
+// payloadCodec returns the configured codec or the JSON default.
+func (db *DB) payloadCodec() Codec {
+	if db.codec == nil {
+		return JSONCodec{}
+	}
+	return db.codec
+}
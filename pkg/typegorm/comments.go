@@ -0,0 +1,49 @@
+package typegorm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Table/Column Comments ---
+
+// quoteComment renders a comment as an SQL string literal of the dialect. MySQL also
+// treats backslashes as escapes in string literals, so they are doubled there.
+func quoteComment(dialect common.Dialect, comment string) string {
+	if dialect.Name() == "mysql" {
+		comment = strings.ReplaceAll(comment, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(comment, "'", "''") + "'"
+}
+
+// inlineComments reports whether the dialect declares comments inside CREATE TABLE
+// (MySQL) instead of with separate COMMENT ON statements.
+func inlineComments(dialect common.Dialect) bool {
+	return dialect.Name() == "mysql"
+}
+
+// CommentStatements returns the COMMENT ON statements documenting a model's table and
+// columns, for dialects that do not declare comments inline in CREATE TABLE (Postgres).
+// It returns nil for MySQL (handled by CreateTableSQL) and SQLite (no comment support).
+func CommentStatements(dialect common.Dialect, model *schema.Model) []string {
+	switch dialect.Name() {
+	case "mysql", "sqlite", "sqlite3":
+		return nil
+	}
+	var statements []string
+	table := quoteTable(dialect, model)
+	if model.Comment != "" {
+		statements = append(statements, fmt.Sprintf("COMMENT ON TABLE %s IS %s;", table, quoteComment(dialect, model.Comment)))
+	}
+	for _, field := range model.Fields {
+		if field.IsIgnored || field.Comment == "" {
+			continue
+		}
+		statements = append(statements, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;",
+			table, dialect.Quote(field.DBName), quoteComment(dialect, field.Comment)))
+	}
+	return statements
+}
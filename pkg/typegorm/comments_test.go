@@ -0,0 +1,47 @@
+package typegorm
+
+import (
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type commentedNote struct {
+	ID   uint   `typegorm:"primaryKey"`
+	Body string `typegorm:"comment:Markdown body, it's rendered"`
+}
+
+func commentedModel(t *testing.T) *schema.Model {
+	model, err := schema.NewParser(nil, schema.WithComments()).Parse(&commentedNote{})
+	require.NoError(t, err)
+	model.Comment = "User notes"
+	return model
+}
+
+func TestCreateTableSQL_InlineCommentsMySQL(t *testing.T) {
+	stmt, err := CreateTableSQL(&mockDialect{name: "mysql"}, commentedModel(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, "CREATE TABLE IF NOT EXISTS `commented_notes` (`id` INT, `body` TEXT COMMENT 'Markdown body, it''s rendered') COMMENT='User notes';", stmt)
+	assert.Empty(t, CommentStatements(&mockDialect{name: "mysql"}, commentedModel(t)))
+}
+
+func TestCommentStatements_Postgres(t *testing.T) {
+	dialect := &mockDialect{name: "postgres"}
+	stmt, err := CreateTableSQL(dialect, commentedModel(t))
+	require.NoError(t, err)
+	assert.NotContains(t, stmt, "COMMENT")
+
+	assert.Equal(t, []string{
+		"COMMENT ON TABLE `commented_notes` IS 'User notes';",
+		"COMMENT ON COLUMN `commented_notes`.`body` IS 'Markdown body, it''s rendered';",
+	}, CommentStatements(dialect, commentedModel(t)))
+}
+
+func TestQuoteComment_EscapesBackslashesForMySQL(t *testing.T) {
+	comment := `Path C:\temp\ it's`
+	assert.Equal(t, `'Path C:\\temp\\ it''s'`, quoteComment(&mockDialect{name: "mysql"}, comment))
+	assert.Equal(t, `'Path C:\temp\ it''s'`, quoteComment(&mockDialect{name: "postgres"}, comment))
+}
@@ -0,0 +1,61 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type computedPerson struct {
+	ID        uint `typegorm:"primaryKey;autoIncrement"`
+	FirstName string
+	LastName  string
+	FullName  string `typegorm:"computed"`
+}
+
+func (p *computedPerson) AfterScan() {
+	p.FullName = p.FirstName + " " + p.LastName
+}
+
+func TestAfterScan_ComputedFields(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	model, err := db.GetModel(&computedPerson{})
+	require.NoError(t, err)
+	assert.True(t, model.HasAfterScan)
+	require.Len(t, model.ComputedFields, 1)
+	_, mapped := model.GetFieldByDBName("full_name")
+	assert.False(t, mapped, "computed fields are not columns")
+
+	columns := []string{"id", "first_name", "last_name"}
+
+	source.queueRows(columns, []any{uint(1), "Ada", "Lovelace"}, []any{uint(2), "Alan", "Turing"})
+	var people []computedPerson
+	require.NoError(t, db.Find(ctx, &people).Error)
+	require.Len(t, people, 2)
+	assert.Equal(t, "Ada Lovelace", people[0].FullName)
+	assert.Equal(t, "Alan Turing", people[1].FullName)
+	assert.NotContains(t, source.lastStatement().SQL, "full_name")
+
+	source.queueRows(columns, []any{uint(3), "Grace", "Hopper"})
+	var first computedPerson
+	require.NoError(t, db.FindFirst(ctx, &first).Error)
+	assert.Equal(t, "Grace Hopper", first.FullName)
+
+	source.queueRows(columns, []any{uint(4), "Edsger", "Dijkstra"})
+	var byID computedPerson
+	require.NoError(t, db.FindByID(ctx, &byID, 4).Error)
+	assert.Equal(t, "Edsger Dijkstra", byID.FullName)
+
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	source.queueRows(columns, []any{uint(5), "Barbara", "Liskov"})
+	var inTx []*computedPerson
+	require.NoError(t, tx.Find(ctx, &inTx).Error)
+	require.Len(t, inTx, 1)
+	assert.Equal(t, "Barbara Liskov", inTx[0].FullName)
+	require.NoError(t, tx.Rollback())
+}
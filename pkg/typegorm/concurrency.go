@@ -0,0 +1,34 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+)
+
+// --- Concurrent Tx Use Detection ---
+
+// txOpKey marks the context of a running Tx operation, so calls made by its hooks
+// (which receive that context) are recognized as nested rather than concurrent.
+type txOpKey struct{ tx *Tx }
+
+// concurrentTx is implemented by transactions whose driver supports concurrent
+// statements on the same transaction; the ORM then skips the detection.
+type concurrentTx interface {
+	SupportsConcurrentUse() bool
+}
+
+// enter marks an ORM operation as running on the transaction. It fails with
+// ErrConcurrentTxUse when another goroutine is in the middle of an operation. The
+// returned context must be used for the operation; call leave when it is done.
+func (tx *Tx) enter(ctx context.Context, operation string) (context.Context, func(), error) {
+	if ctx.Value(txOpKey{tx}) != nil {
+		return ctx, func() {}, nil // Nested call from a hook of the running operation
+	}
+	if c, ok := tx.source.(concurrentTx); ok && c.SupportsConcurrentUse() {
+		return ctx, func() {}, nil
+	}
+	if !tx.busy.CompareAndSwap(false, true) {
+		return ctx, nil, fmt.Errorf("tx: %s called while another operation is running on the same transaction: %w", operation, ErrConcurrentTxUse)
+	}
+	return context.WithValue(ctx, txOpKey{tx}, true), func() { tx.busy.Store(false) }, nil
+}
@@ -0,0 +1,92 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Run with -race: the shared *DB must not race across goroutines.
+func TestDB_ConcurrentCreateFindUpdates(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	require.NoError(t, db.Callbacks().Register(EventAfterCreate, "noop", func(ctx context.Context, hc *HookContext) error { return nil }))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 150)
+	for i := 0; i < 50; i++ {
+		wg.Add(3)
+		go func(i int) {
+			defer wg.Done()
+			errs <- db.Create(ctx, &maskUser{Name: fmt.Sprintf("user-%d", i)}).Error
+		}(i)
+		go func() {
+			defer wg.Done()
+			var users []maskUser
+			errs <- db.Find(ctx, &users, map[string]any{"age >": 18}, Order("name"), Limit(10)).Error
+		}()
+		go func(i int) {
+			defer wg.Done()
+			errs <- db.Updates(ctx, &maskUser{ID: uint(i + 1)}, map[string]any{"age": i}).Error
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Len(t, source.Statements(), 150+50) // Create also re-fetches the inserted row
+}
+
+func TestTx_ConcurrentUseDetected(t *testing.T) {
+	db, _ := newMockDB()
+	ctx := context.Background()
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	require.NoError(t, db.Callbacks().Register(EventBeforeCreate, "block", func(ctx context.Context, hc *HookContext) error {
+		close(entered)
+		<-release
+		return nil
+	}))
+
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+
+	done := make(chan error)
+	go func() { done <- tx.Create(ctx, &maskUser{Name: "Ana"}).Error }()
+	<-entered
+
+	var users []maskUser
+	res := tx.Find(ctx, &users)
+	assert.ErrorIs(t, res.Error, ErrConcurrentTxUse)
+	assert.ErrorIs(t, tx.UpdateIf(ctx, &maskUser{ID: 1}, map[string]any{"name": "x"}, nil).Error, ErrConcurrentTxUse)
+	assert.ErrorIs(t, tx.Commit(), ErrConcurrentTxUse)
+
+	close(release)
+	require.NoError(t, <-done)
+	require.NoError(t, tx.Find(ctx, &users).Error, "usable again once the operation finished")
+	require.NoError(t, tx.Commit())
+}
+
+func TestTx_NestedCallsFromHooksAllowed(t *testing.T) {
+	db, _ := newMockDB()
+	ctx := context.Background()
+
+	var nestedErr error
+	require.NoError(t, db.Callbacks().Register(EventAfterCreate, "lookup", func(ctx context.Context, hc *HookContext) error {
+		var users []maskUser
+		nestedErr = hc.DB.(*Tx).Find(ctx, &users).Error
+		return nil
+	}))
+
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Create(ctx, &maskUser{Name: "Ana"}).Error)
+	assert.NoError(t, nestedErr)
+	require.NoError(t, tx.Commit())
+}
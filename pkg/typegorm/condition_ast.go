@@ -0,0 +1,289 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Condition AST ---
+
+// ParseCondition returns the condition of a query as an Expr tree, whatever its form:
+// struct pointer, map (with operator suffixes), Expr or the conditions of a Chain. In
+// the result, groups only hold comparisons, groups and SQL nodes (see SQL), columns
+// are database column names, and map entries are sorted by key. A nil condition gives
+// an empty And group.
+func ParseCondition(model *schema.Model, condition any) (Expr, error) {
+	switch cond := condition.(type) {
+	case nil:
+		return And(), nil
+	case Expr:
+		return normalizeExpr(model, cond)
+	case chainWhere:
+		return parseChainWhere(model, cond)
+	}
+
+	value := reflect.ValueOf(condition)
+	switch {
+	case value.Kind() == reflect.Pointer && !value.IsNil() && value.Elem().Kind() == reflect.Struct:
+		structValue := value.Elem()
+		var exprs []Expr
+		for i := 0; i < structValue.NumField(); i++ {
+			fieldValue := structValue.Field(i)
+			if !fieldValue.IsValid() || fieldValue.IsZero() {
+				continue
+			}
+			field, ok := model.GetField(structValue.Type().Field(i).Name)
+			if !ok || field.IsIgnored {
+				continue
+			}
+			exprs = append(exprs, Expr{op: "=", column: field.DBName, values: []any{fieldValue.Interface()}})
+		}
+		return Expr{op: "and", exprs: exprs}, nil
+	case value.Kind() == reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return Expr{}, fmt.Errorf("map condition keys must be strings (column [operator]), got %s", value.Type().Key().Kind())
+		}
+		keys := make([]string, 0, value.Len())
+		for _, key := range value.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		exprs := make([]Expr, 0, len(keys))
+		for _, key := range keys {
+			column, operator, err := parseConditionKey(key)
+			if err != nil {
+				return Expr{}, err
+			}
+			field, err := exprField(model, column)
+			if err != nil {
+				return Expr{}, err
+			}
+			expr := Expr{op: operator, column: field.DBName}
+			if operator != "is null" && operator != "is not null" {
+				expr.values = []any{value.MapIndex(reflect.ValueOf(key).Convert(value.Type().Key())).Interface()}
+			}
+			exprs = append(exprs, expr)
+		}
+		return Expr{op: "and", exprs: exprs}, nil
+	}
+	return Expr{}, fmt.Errorf("unsupported condition type: %T. Expecting struct pointer, map[string]any or Expr", condition)
+}
+
+// parseChainWhere mirrors chainWhere.build: the conditions of each alternative ANDed,
+// the alternatives ORed.
+func parseChainWhere(model *schema.Model, where chainWhere) (Expr, error) {
+	var alternatives []Expr
+	current := Expr{op: "and"}
+	for i, cond := range where {
+		if cond.or && i > 0 {
+			alternatives = append(alternatives, current)
+			current = Expr{op: "and"}
+		}
+		var expr Expr
+		var err error
+		if raw, ok := cond.cond.(string); ok {
+			expr = SQL(raw, cond.args...)
+		} else if len(cond.args) > 0 {
+			err = fmt.Errorf("arguments are only allowed with SQL conditions, got %T", cond.cond)
+		} else {
+			expr, err = ParseCondition(model, cond.cond)
+		}
+		if err != nil {
+			return Expr{}, err
+		}
+		if cond.not {
+			expr = Not(expr)
+		}
+		current.exprs = append(current.exprs, expr)
+	}
+	if len(alternatives) == 0 {
+		return current, nil
+	}
+	return Expr{op: "or", exprs: append(alternatives, current)}, nil
+}
+
+// normalizeExpr resolves the columns of an Expr and parses its nested conditions.
+func normalizeExpr(model *schema.Model, expr Expr) (Expr, error) {
+	switch expr.op {
+	case "cond":
+		return ParseCondition(model, expr.values[0])
+	case "sql":
+		return expr, nil
+	case "":
+		return Expr{}, fmt.Errorf("empty condition expression (use the constructors, e.g. typegorm.Eq)")
+	}
+	if len(expr.exprs) > 0 {
+		exprs := make([]Expr, len(expr.exprs))
+		for i, sub := range expr.exprs {
+			var err error
+			if exprs[i], err = normalizeExpr(model, sub); err != nil {
+				return Expr{}, err
+			}
+		}
+		expr.exprs = exprs
+	}
+	if expr.column != "" {
+		field, err := exprField(model, expr.column)
+		if err != nil {
+			return Expr{}, err
+		}
+		expr.column = field.DBName
+	}
+	return expr, nil
+}
+
+// SQL is a condition written in SQL, with "?" placeholders for args (slices expand
+// to lists), as accepted by Chain.Where.
+func SQL(raw string, args ...any) Expr {
+	return Expr{op: "sql", values: append([]any{raw}, args...)}
+}
+
+// Op returns the operator of the node: a comparison ("=", "<>", ">", ">=", "<", "<=",
+// "like", "in", "not in", "between", "is null", "is not null"), a group ("and", "or",
+// "not") or "sql".
+func (e Expr) Op() string { return e.op }
+
+// Column returns the column of a comparison ("" for other nodes).
+func (e Expr) Column() string { return e.column }
+
+// Values returns the compared values of a comparison (the slice for In and NotIn),
+// or the SQL followed by its arguments for a SQL node.
+func (e Expr) Values() []any { return e.values }
+
+// Children returns the conditions of a group.
+func (e Expr) Children() []Expr { return e.exprs }
+
+// Walk calls fn for the node and, while fn returns true, its children (depth first).
+func (e Expr) Walk(fn func(Expr) bool) {
+	if !fn(e) {
+		return
+	}
+	for _, sub := range e.exprs {
+		sub.Walk(fn)
+	}
+}
+
+// Rewrite returns the tree with every node replaced by fn(node), children first.
+func (e Expr) Rewrite(fn func(Expr) Expr) Expr {
+	if len(e.exprs) > 0 {
+		exprs := make([]Expr, len(e.exprs))
+		for i, sub := range e.exprs {
+			exprs[i] = sub.Rewrite(fn)
+		}
+		e.exprs = exprs
+	}
+	return fn(e)
+}
+
+// String renders the condition for humans, with values inlined:
+// (age >= 18 AND email LIKE '%@example.com').
+func (e Expr) String() string {
+	switch e.op {
+	case "and", "or":
+		parts := make([]string, 0, len(e.exprs))
+		for _, sub := range e.exprs {
+			if part := sub.String(); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		if len(parts) <= 1 {
+			return strings.Join(parts, "")
+		}
+		return "(" + strings.Join(parts, " "+strings.ToUpper(e.op)+" ") + ")"
+	case "not":
+		inner := e.exprs[0].String()
+		if inner == "" {
+			return ""
+		}
+		if strings.HasPrefix(inner, "(") && strings.HasSuffix(inner, ")") {
+			return "NOT " + inner
+		}
+		return "NOT (" + inner + ")"
+	case "cond":
+		return fmt.Sprintf("%v", e.values[0])
+	case "sql":
+		raw := e.values[0].(string)
+		for _, arg := range e.values[1:] {
+			raw = strings.Replace(raw, "?", formatExprValue(arg), 1)
+		}
+		return raw
+	case "is null", "is not null":
+		return e.column + " " + strings.ToUpper(e.op)
+	case "between":
+		return fmt.Sprintf("%s BETWEEN %s AND %s", e.column, formatExprValue(e.values[0]), formatExprValue(e.values[1]))
+	case "in", "not in":
+		list := reflect.ValueOf(e.values[0])
+		items := make([]string, 0)
+		if list.Kind() == reflect.Slice {
+			for i := 0; i < list.Len(); i++ {
+				items = append(items, formatExprValue(list.Index(i).Interface()))
+			}
+		}
+		return fmt.Sprintf("%s %s (%s)", e.column, strings.ToUpper(e.op), strings.Join(items, ", "))
+	case "":
+		return ""
+	}
+	return fmt.Sprintf("%s %s %s", e.column, strings.ToUpper(e.op), formatExprValue(e.values[0]))
+}
+
+func formatExprValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case []byte:
+		return "'" + strings.ReplaceAll(string(v), "'", "''") + "'"
+	case time.Time:
+		return "'" + v.Format(time.RFC3339Nano) + "'"
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// --- Condition Rewriters ---
+
+// ConditionRewriter inspects the condition of a query before it runs, and returns the
+// condition to run instead (or an error to refuse the query). op is "Find",
+// "FindFirst", "Count", "Update" or "Delete". It sees the ParseCondition tree, so a
+// tenant filter can be enforced with:
+//
+//	db.UseConditionRewriter(func(ctx context.Context, op string, model *schema.Model, cond typegorm.Expr) (typegorm.Expr, error) {
+//		if _, ok := model.GetFieldByDBName("tenant_id"); !ok {
+//			return cond, nil
+//		}
+//		return typegorm.And(cond, typegorm.Eq("tenant_id", tenantFrom(ctx))), nil
+//	})
+type ConditionRewriter func(ctx context.Context, op string, model *schema.Model, cond Expr) (Expr, error)
+
+// UseConditionRewriter sets the rewriter of query conditions (nil removes it). It
+// applies to Find, FindFirst, the SELECTs of FindQuery and FindUnion (as "Find"), the
+// extra conditions of UpdateIf (as "Update"), the join table reads of many-to-many
+// preloads and the Count, Update and Delete of chains, also in transactions started
+// afterwards. Call it before the DB is shared between goroutines.
+func (db *DB) UseConditionRewriter(rewriter ConditionRewriter) {
+	db.rewriter = rewriter
+}
+
+// rewriteCondition runs the rewriter, then the policies, on a condition; without
+// either, the condition is returned unchanged.
+func rewriteCondition(ctx context.Context, rewriter ConditionRewriter, policies []PolicyFunc, op string, model *schema.Model, condition any) (any, error) {
+	if rewriter == nil && len(policies) == 0 {
+		return condition, nil
+	}
+	expr, err := ParseCondition(model, condition)
+	if err != nil {
+		return nil, err
+	}
+	if rewriter != nil {
+		if expr, err = rewriter(ctx, op, model, expr); err != nil {
+			return nil, fmt.Errorf("%s on %s refused by the condition rewriter: %w", op, model.Name, err)
+		}
+	}
+	return applyPolicies(ctx, policies, op, model, expr)
+}
@@ -0,0 +1,120 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCondition(t *testing.T) {
+	db, _ := newMockDB()
+	model, err := db.GetModel(&maskUser{})
+	require.NoError(t, err)
+
+	expr, err := ParseCondition(model, map[string]any{"name like": "A%", "age >=": 18, "email is null": true})
+	require.NoError(t, err)
+	assert.Equal(t, "(age >= 18 AND email IS NULL AND name LIKE 'A%')", expr.String())
+
+	expr, err = ParseCondition(model, &maskUser{Name: "O'Hara", Age: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "(name = 'O''Hara' AND age = 3)", expr.String())
+
+	expr, err = ParseCondition(model, Or(Eq("Email", "a@x"), Not(In("id", []int{1, 2})), map[string]any{"age <": 5}))
+	require.NoError(t, err)
+	assert.Equal(t, "(email = 'a@x' OR NOT (id IN (1, 2)) OR age < 5)", expr.String())
+
+	where := db.Model(&maskUser{}).Where("age > ?", 30).Or(map[string]any{"name": "Bia"}).Not(Between("age", 1, 9)).conds
+	expr, err = ParseCondition(model, chainWhere(where))
+	require.NoError(t, err)
+	assert.Equal(t, "(age > 30 OR (name = 'Bia' AND NOT (age BETWEEN 1 AND 9)))", expr.String())
+
+	expr, err = ParseCondition(model, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "and", expr.Op())
+	assert.Empty(t, expr.String())
+
+	_, err = ParseCondition(model, map[string]any{"nope": 1})
+	assert.Error(t, err)
+	_, err = ParseCondition(model, 42)
+	assert.Error(t, err)
+}
+
+func TestExpr_WalkAndRewrite(t *testing.T) {
+	cond := And(Eq("age", 1), Or(Eq("name", "Ana"), IsNull("email")))
+
+	var columns []string
+	cond.Walk(func(e Expr) bool {
+		if e.Column() != "" {
+			columns = append(columns, e.Column())
+		}
+		return e.Op() != "or" // Do not descend into OR groups
+	})
+	assert.Equal(t, []string{"age"}, columns)
+	assert.Len(t, cond.Children(), 2)
+	assert.Equal(t, []any{1}, cond.Children()[0].Values())
+
+	renamed := cond.Rewrite(func(e Expr) Expr {
+		if e.Column() == "name" {
+			return Like("name", "A%")
+		}
+		return e
+	})
+	assert.Equal(t, "(age = 1 AND (name LIKE 'A%' OR email IS NULL))", renamed.String())
+	assert.Equal(t, "(age = 1 AND (name = 'Ana' OR email IS NULL))", cond.String(), "Rewrite leaves the tree unchanged")
+	assert.Equal(t, "age > 3 AND name = 'x'", SQL("age > ? AND name = ?", 3, "x").String())
+}
+
+func TestUseConditionRewriter_EnforcesTenantFilter(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	var ops []string
+	db.UseConditionRewriter(func(ctx context.Context, op string, model *schema.Model, cond Expr) (Expr, error) {
+		ops = append(ops, op)
+		refused := false
+		cond.Walk(func(e Expr) bool {
+			refused = refused || e.Op() == "sql"
+			return true
+		})
+		if refused {
+			return cond, errors.New("raw SQL conditions are not allowed")
+		}
+		return And(cond, Eq("age", 42)), nil
+	})
+
+	var users []maskUser
+	require.NoError(t, db.Find(ctx, &users, map[string]any{"name": "Ana"}).Error)
+	assert.Equal(t, "SELECT `id`, `name`, `email`, `age` FROM `mask_users` WHERE (`name` = ? AND `age` = ?)", source.lastStatement().SQL)
+	assert.Equal(t, []any{"Ana", 42}, source.lastStatement().Args)
+
+	require.NoError(t, db.Find(ctx, &users).Error)
+	assert.Equal(t, "SELECT `id`, `name`, `email`, `age` FROM `mask_users` WHERE `age` = ?", source.lastStatement().SQL)
+
+	var user maskUser
+	_ = db.FindFirst(ctx, &user, &maskUser{Name: "Ana"})
+	assert.Contains(t, source.lastStatement().SQL, "WHERE (`name` = ? AND `age` = ?) LIMIT 1")
+
+	require.NoError(t, db.Model(&maskUser{}).AllRows().Delete(ctx).Error)
+	assert.Equal(t, "DELETE FROM `mask_users` WHERE `age` = ?", source.lastStatement().SQL)
+
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Model(&maskUser{}).Where(Gt("id", 1)).Update(ctx, "name", "x").Error)
+	assert.Equal(t, "UPDATE `mask_users` SET `name` = ? WHERE (`id` > ? AND `age` = ?)", source.lastStatement().SQL)
+	require.NoError(t, tx.Rollback())
+
+	require.NoError(t, db.FindUnion(ctx, &users, Union(From(&maskUser{}, Lt("id", 10)), From(&maskUser{}))).Error)
+	assert.Equal(t, "SELECT `id`, `name`, `email`, `age` FROM `mask_users` WHERE (`id` < ? AND `age` = ?) UNION SELECT `id`, `name`, `email`, `age` FROM `mask_users` WHERE `age` = ?", source.lastStatement().SQL)
+
+	require.NoError(t, db.UpdateIf(ctx, &maskUser{ID: 3}, map[string]any{"name": "Bia"}, nil).Error)
+	assert.Equal(t, "UPDATE `mask_users` SET `name` = ? WHERE `id` = ? AND `age` = ?", source.lastStatement().SQL)
+
+	err = db.Model(&maskUser{}).Where("age > ?", 1).Find(ctx, &users).Error
+	assert.ErrorContains(t, err, "Find on maskUser refused by the condition rewriter: raw SQL conditions are not allowed")
+	err = db.UpdateIf(ctx, &maskUser{ID: 3}, map[string]any{"name": "Bia"}, SQL("age > ?", 1)).Error
+	assert.ErrorContains(t, err, "Update on maskUser refused by the condition rewriter")
+	assert.Equal(t, []string{"Find", "Find", "FindFirst", "Delete", "Update", "Find", "Find", "Update", "Find", "Update"}, ops)
+}
@@ -0,0 +1,134 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Counter Caches ---
+
+// A counter cache keeps a count of child rows on the parent row, declared on the
+// child's foreign key:
+//
+//	type Post struct {
+//		ID     uint `typegorm:"primaryKey;autoIncrement"`
+//		UserID uint `typegorm:"references:users.id;counterCache:posts_count"`
+//	}
+//
+// Create and Delete of a Post increment/decrement users.posts_count with a single
+// UPDATE (posts_count = posts_count + 1), in the same transaction when run through a Tx.
+// Outside a Tx the write and the counter UPDATE are separate autocommit statements: when
+// the counter UPDATE fails, Result.Error reports it although the row is already written
+// (RowsAffected, LastInsertID and the struct's ID are set), and the counter is off by
+// one. RecountAll recomputes the counters from scratch, counting only the rows not soft
+// deleted.
+
+// counterKey is the parent row a counter cache of a child record points to.
+type counterKey struct {
+	field *schema.Field
+	value any
+}
+
+// counterExecer is the part of a DataSource or Tx used to maintain counter caches.
+type counterExecer interface {
+	Exec(ctx context.Context, query string, args ...any) (common.Result, error)
+	QueryRow(ctx context.Context, query string, args ...any) common.RowScanner
+}
+
+// counterKeys returns the foreign keys of the counter caches of a record. Keys missing
+// from the struct (e.g. Delete called with only the primary key set) are read from
+// the row itself, using the primary key WHERE clause; call it before deleting.
+func counterKeys(ctx context.Context, conn counterExecer, dialect common.Dialect, model *schema.Model, structValue reflect.Value, pkWhere []string, pkArgs []any) ([]counterKey, error) {
+	if len(model.CounterCaches) == 0 {
+		return nil, nil
+	}
+	keys := make([]counterKey, 0, len(model.CounterCaches))
+	var missing []*schema.Field
+	for _, field := range model.CounterCaches {
+		value := structValue.FieldByName(field.GoName)
+		for value.Kind() == reflect.Pointer && !value.IsNil() {
+			value = value.Elem()
+		}
+		if !value.IsValid() || value.IsZero() || value.Kind() == reflect.Pointer {
+			missing = append(missing, field)
+			continue
+		}
+		keys = append(keys, counterKey{field: field, value: value.Interface()})
+	}
+	if len(missing) == 0 || pkWhere == nil {
+		return keys, nil
+	}
+
+	columns := make([]string, len(missing))
+	dest := make([]any, len(missing))
+	for i, field := range missing {
+		columns[i] = dialect.Quote(field.DBName)
+		dest[i] = new(any)
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s", strings.Join(columns, ", "), quoteTable(dialect, model), strings.Join(pkWhere, " AND "))
+	logSQL("Executing SQL: %s | Args: %v\n", query, redactArgs(model, pkArgs, structValue.Interface()))
+	if err := conn.QueryRow(ctx, query, pkArgs...).Scan(dest...); err != nil {
+		return nil, fmt.Errorf("counter cache: failed to read foreign keys of %s: %w", model.Name, err)
+	}
+	for i, field := range missing {
+		if value := *(dest[i].(*any)); value != nil {
+			keys = append(keys, counterKey{field: field, value: value})
+		}
+	}
+	return keys, nil
+}
+
+// applyCounterCaches adds delta (+1 on create, -1 on delete) to the parent counters.
+func applyCounterCaches(ctx context.Context, conn counterExecer, dialect common.Dialect, keys []counterKey, delta int) error {
+	for _, key := range keys {
+		parentTable, parentColumn, _ := strings.Cut(key.field.References, ".")
+		counter := dialect.Quote(key.field.CounterCache)
+		op := "+"
+		if delta < 0 {
+			op = "-"
+		}
+		query := fmt.Sprintf("UPDATE %s SET %s = %s %s 1 WHERE %s = %s",
+			dialect.Quote(parentTable), counter, counter, op, dialect.Quote(parentColumn), dialect.BindVar(1))
+		logSQL("Executing SQL: %s | Args: [%v]\n", query, key.value)
+		if _, err := conn.Exec(ctx, query, key.value); err != nil {
+			return fmt.Errorf("counter cache: failed to update %s.%s: %w", parentTable, key.field.CounterCache, err)
+		}
+	}
+	return nil
+}
+
+// RecountAll recomputes every counter cache declared by the given child models from
+// the actual row counts, e.g. after bulk imports or raw SQL deletes:
+//
+//	err := db.RecountAll(ctx, &Post{}, &Comment{})
+func (db *DB) RecountAll(ctx context.Context, values ...any) error {
+	dialect := db.source.Dialect()
+	for _, value := range values {
+		model, err := db.GetModel(value)
+		if err != nil {
+			return fmt.Errorf("recount: failed to parse schema for type %T: %w", value, err)
+		}
+		for _, field := range model.CounterCaches {
+			parentTable, parentColumn, _ := strings.Cut(field.References, ".")
+			where := fmt.Sprintf("%s.%s = %s.%s",
+				quoteTable(dialect, model), dialect.Quote(field.DBName),
+				dialect.Quote(parentTable), dialect.Quote(parentColumn))
+			if model.SoftDeleteField != nil { // Delete only decremented the counter
+				where += fmt.Sprintf(" AND %s.%s IS NULL", quoteTable(dialect, model), dialect.Quote(model.SoftDeleteField.DBName))
+			}
+			query := fmt.Sprintf("UPDATE %s SET %s = (SELECT COUNT(*) FROM %s WHERE %s)",
+				dialect.Quote(parentTable), dialect.Quote(field.CounterCache),
+				quoteTable(dialect, model), where)
+			logSQL("Executing SQL: %s\n", query)
+			if _, err := db.conn(ctx).Exec(ctx, query); err != nil {
+				return fmt.Errorf("recount: failed to recount %s.%s: %w", parentTable, field.CounterCache, err)
+			}
+		}
+	}
+	return nil
+}
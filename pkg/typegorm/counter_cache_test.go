@@ -0,0 +1,94 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type counterPost struct {
+	ID     int64  `typegorm:"primaryKey;autoIncrement"`
+	UserID int64  `typegorm:"references:users.id;counterCache:posts_count"`
+	Title  string `typegorm:"size:100"`
+}
+
+func TestCounterCache_CreateIncrements(t *testing.T) {
+	db, source := newMockDB()
+	source.lastID = 10
+
+	require.NoError(t, db.Create(context.Background(), &counterPost{UserID: 3, Title: "hi"}).Error)
+	stmts := source.Statements()
+	require.Len(t, stmts, 3) // INSERT, re-fetch, counter
+	assert.Equal(t, "UPDATE `users` SET `posts_count` = `posts_count` + 1 WHERE `id` = ?", stmts[2].SQL)
+	assert.Equal(t, []any{int64(3)}, stmts[2].Args)
+}
+
+func TestCounterCache_DeleteDecrementsReadingForeignKey(t *testing.T) {
+	db, source := newMockDB()
+	source.queueRows([]string{"user_id"}, []any{int64(5)})
+
+	require.NoError(t, db.Delete(context.Background(), &counterPost{ID: 1}).Error)
+	stmts := source.Statements()
+	require.Len(t, stmts, 3)
+	assert.Equal(t, "SELECT `user_id` FROM `counter_posts` WHERE `id` = ?", stmts[0].SQL)
+	assert.Contains(t, stmts[1].SQL, "DELETE FROM `counter_posts`")
+	assert.Equal(t, "UPDATE `users` SET `posts_count` = `posts_count` - 1 WHERE `id` = ?", stmts[2].SQL)
+	assert.Equal(t, []any{int64(5)}, stmts[2].Args)
+}
+
+func TestCounterCache_TxDeleteUsesStructKey(t *testing.T) {
+	db, source := newMockDB()
+	tx, err := db.Begin(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, tx.Delete(context.Background(), &counterPost{ID: 1, UserID: 8}).Error)
+	require.NoError(t, tx.Commit())
+
+	assert.False(t, source.containsStatement("SELECT `user_id`"))
+	assert.True(t, source.containsStatement("`posts_count` - 1"))
+}
+
+func TestRecountAll(t *testing.T) {
+	db, source := newMockDB()
+	require.NoError(t, db.RecountAll(context.Background(), &counterPost{}))
+	assert.Equal(t, "UPDATE `users` SET `posts_count` = (SELECT COUNT(*) FROM `counter_posts` WHERE `counter_posts`.`user_id` = `users`.`id`)", source.lastStatement().SQL)
+}
+
+func TestCounterCache_CreateReportsCounterFailure(t *testing.T) {
+	db, source := newMockDB()
+	source.lastID = 10
+	source.execErrs = []error{nil, errors.New("lock wait timeout")}
+
+	post := &counterPost{UserID: 3, Title: "hi"}
+	result := db.Create(context.Background(), post)
+	assert.ErrorContains(t, result.Error, "counter cache: failed to update users.posts_count")
+	assert.ErrorContains(t, result.Error, "lock wait timeout")
+	// The row is stored: the result and the struct report it.
+	assert.EqualValues(t, 1, result.RowsAffected)
+	assert.EqualValues(t, 10, result.LastInsertID)
+	assert.EqualValues(t, 10, post.ID)
+}
+
+func TestRecountAll_SkipsSoftDeletedRows(t *testing.T) {
+	type softCounterPost struct {
+		ID        int64      `typegorm:"primaryKey;autoIncrement"`
+		UserID    int64      `typegorm:"references:users.id;counterCache:posts_count"`
+		DeletedAt *time.Time `typegorm:"softDelete"`
+	}
+	db, source := newMockDB()
+	require.NoError(t, db.RecountAll(context.Background(), &softCounterPost{}))
+	assert.Equal(t, "UPDATE `users` SET `posts_count` = (SELECT COUNT(*) FROM `soft_counter_posts` WHERE `soft_counter_posts`.`user_id` = `users`.`id` AND `soft_counter_posts`.`deleted_at` IS NULL)", source.lastStatement().SQL)
+}
+
+func TestCounterCache_RequiresReferences(t *testing.T) {
+	type badCounter struct {
+		ID     int64 `typegorm:"primaryKey"`
+		UserID int64 `typegorm:"counterCache:posts_count"`
+	}
+	db, _ := newMockDB()
+	_, err := db.GetModel(&badCounter{})
+	assert.ErrorContains(t, err, "requires a references tag")
+}
@@ -0,0 +1,242 @@
+package typegorm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// createOptions holds the optional behaviors of a Create call.
+type createOptions struct {
+	onConflictDoNothing bool      // Skip the insert silently when it would violate a unique constraint
+	onConflictUpdate    bool      // Update the conflicting row instead (upsert)
+	conflictColumns     []string  // Columns identifying a conflict (default: primary keys)
+	conflictUpdates     []string  // Columns updated on conflict (Go or column names; default: every inserted column)
+	selected            []string  // Only these fields are inserted (Go or column names)
+	omitted             []string  // These fields are never inserted (Go or column names)
+	associations        bool      // Also create the records of the relation fields (see WithAssociations)
+	associationNames    []string  // Relations cascaded by WithAssociations (empty: all)
+	saved               *savedRow // Row read by Save before its upsert, nil outside Save
+}
+
+// CreateOption defines a function type that modifies createOptions.
+type CreateOption func(*createOptions)
+
+// OnConflictDoNothing makes Create skip the row instead of failing when it conflicts
+// with an existing one: a no-op ON DUPLICATE KEY UPDATE on MySQL (unlike INSERT IGNORE,
+// it still fails on invalid values and foreign keys), ON CONFLICT DO NOTHING on
+// Postgres and SQLite, INSERT ... WHERE NOT EXISTS on SQL Server.
+// columns optionally names the conflict target (DB column names); it defaults to the
+// primary keys and is required by SQL Server when the primary key is auto-generated.
+// Result.RowsAffected is 0 when the row was skipped, in which case the ID is not set
+// and AfterCreate is not called.
+func OnConflictDoNothing(columns ...string) CreateOption {
+	return func(opts *createOptions) {
+		opts.onConflictDoNothing = true
+		opts.conflictColumns = append(opts.conflictColumns, columns...)
+	}
+}
+
+// Conflict describes what Create does with a row conflicting with an existing one
+// (see OnConflict).
+type Conflict struct {
+	Columns   []string // Conflict target (column names; default: primary keys). MySQL checks every unique key
+	DoNothing bool     // Skip the row, as OnConflictDoNothing
+	DoUpdates []string // Columns (Go or column names) set from the new row; empty: every inserted column but the target and creation timestamps
+}
+
+// OnConflict makes Create an upsert: a row conflicting with an existing one updates it
+// (ON DUPLICATE KEY UPDATE on MySQL, ON CONFLICT ... DO UPDATE on Postgres and SQLite):
+//
+//	db.Create(ctx, &user, typegorm.OnConflict(typegorm.Conflict{Columns: []string{"email"}, DoUpdates: []string{"Name"}}))
+//
+// DoUpdates columns must be inserted. Result.RowsAffected follows the database (MySQL
+// reports 2 for an updated row), and when the row was updated the generated ID is not
+// reported, so the primary key is only set back when the row was inserted or given.
+// SQL Server has no upsert syntax and returns ErrUnsupportedDialect.
+func OnConflict(conflict Conflict) CreateOption {
+	return func(opts *createOptions) {
+		opts.conflictColumns = append(opts.conflictColumns, conflict.Columns...)
+		if conflict.DoNothing {
+			opts.onConflictDoNothing = true
+			return
+		}
+		opts.onConflictUpdate = true
+		opts.conflictUpdates = append(opts.conflictUpdates, conflict.DoUpdates...)
+	}
+}
+
+// Select restricts the INSERT to the given fields (Go field or column names); the other
+// columns get their database defaults:
+//
+//	db.Create(ctx, &user, typegorm.Select("Name", "Email"))
+func Select(fields ...string) CreateOption {
+	return func(opts *createOptions) { opts.selected = append(opts.selected, fields...) }
+}
+
+// Omit excludes the given fields (Go field or column names) from the INSERT, e.g. for
+// sensitive or database-managed columns, without a permanent tag:
+//
+//	db.Create(ctx, &user, typegorm.Omit("Password"))
+func Omit(fields ...string) CreateOption {
+	return func(opts *createOptions) { opts.omitted = append(opts.omitted, fields...) }
+}
+
+// validateFields checks that the names given to Select/Omit exist in the model.
+func (opts createOptions) validateFields(model *schema.Model) error {
+	for _, name := range append(append([]string{}, opts.selected...), opts.omitted...) {
+		if _, ok := model.GetField(name); ok {
+			continue
+		}
+		if _, ok := model.GetFieldByDBName(name); !ok {
+			return fmt.Errorf("select/omit: unknown field '%s' for model %s", name, model.Name)
+		}
+	}
+	return nil
+}
+
+// updatesOnConflict reports whether the INSERT is an upsert updating the conflicting row.
+func (opts createOptions) updatesOnConflict() bool {
+	return opts.onConflictUpdate && !opts.onConflictDoNothing
+}
+
+// includes reports whether a field takes part in the INSERT according to Select/Omit.
+func (opts createOptions) includes(field *schema.Field) bool {
+	matches := func(names []string) bool {
+		for _, name := range names {
+			if name == field.GoName || name == field.DBName {
+				return true
+			}
+		}
+		return false
+	}
+	if len(opts.selected) > 0 && !matches(opts.selected) {
+		return false
+	}
+	return !matches(opts.omitted)
+}
+
+func applyCreateOptions(opts []CreateOption) createOptions {
+	var options createOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	return options
+}
+
+// buildInsertSQL renders the INSERT statement for the given (unquoted) columns and
+// their arguments, honoring the conflict options. It may return extra arguments.
+func buildInsertSQL(dialect common.Dialect, model *schema.Model, columns []string, args []any, opts createOptions) (string, []any, error) {
+	var conflict []string
+	if opts.onConflictDoNothing || opts.onConflictUpdate {
+		conflict = opts.conflictColumns
+		if len(conflict) == 0 {
+			for _, pk := range model.PrimaryKeys {
+				conflict = append(conflict, pk.DBName)
+			}
+		}
+		for _, col := range conflict {
+			if _, ok := model.GetFieldByDBName(col); !ok {
+				return "", nil, fmt.Errorf("invalid conflict column '%s' for model %s", col, model.Name)
+			}
+		}
+	}
+
+	if opts.updatesOnConflict() {
+		updates, err := conflictUpdateColumns(model, columns, conflict, opts.conflictUpdates)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(updates) > 0 {
+			return buildUpsertSQL(dialect, model, columns, args, conflict, updates)
+		}
+		opts.onConflictDoNothing = true // Nothing to update: the existing row is kept as is
+	}
+
+	if !opts.onConflictDoNothing || dialect.Name() == "mysql" {
+		buf := getStmtBuffer(statementSize(model))
+		defer putStmtBuffer(buf)
+		buf.WriteString("INSERT INTO ")
+		writeInsertColumns(buf, dialect, model.QualifiedTableName(), columns)
+		if opts.onConflictDoNothing {
+			// Assigning a column to itself leaves the row unchanged (RowsAffected is 0)
+			keep := columns[0]
+			if len(conflict) > 0 {
+				keep = conflict[0]
+			}
+			buf.WriteString(" ON DUPLICATE KEY UPDATE ")
+			buf.WriteQuoted(dialect, keep)
+			buf.WriteString(" = ")
+			buf.WriteQuoted(dialect, keep)
+		}
+		return buf.String(), args, nil
+	}
+
+	quotedTable := quoteTable(dialect, model)
+	quotedColumns := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	for i, col := range columns {
+		quotedColumns[i] = dialect.Quote(col)
+		placeholders[i] = dialect.BindVar(i + 1)
+	}
+	columnList := strings.Join(quotedColumns, ", ")
+	valueList := strings.Join(placeholders, ", ")
+
+	switch dialect.Name() {
+	case "sqlserver", "mssql":
+		// No native syntax: guard the insert with a NOT EXISTS on the conflict columns.
+		argIndex := make(map[string]int, len(columns))
+		for i, col := range columns {
+			argIndex[col] = i
+		}
+		conds := make([]string, 0, len(conflict))
+		allArgs := append([]any{}, args...)
+		for _, col := range conflict {
+			i, ok := argIndex[col]
+			if !ok {
+				return "", nil, fmt.Errorf("conflict column '%s' has no value to compare (auto-generated or skipped)", col)
+			}
+			allArgs = append(allArgs, args[i])
+			conds = append(conds, assignment(dialect, col, len(allArgs)))
+		}
+		if len(conds) == 0 {
+			return "", nil, fmt.Errorf("OnConflictDoNothing on %s requires conflict columns", dialect.Name())
+		}
+		return fmt.Sprintf("INSERT INTO %s (%s) SELECT %s WHERE NOT EXISTS (SELECT 1 FROM %s WHERE %s)",
+			quotedTable, columnList, valueList, quotedTable, strings.Join(conds, " AND ")), allArgs, nil
+	default: // postgres, sqlite and other ON CONFLICT-capable dialects
+		target := ""
+		if len(opts.conflictColumns) > 0 {
+			quoted := make([]string, len(opts.conflictColumns))
+			for i, col := range opts.conflictColumns {
+				quoted[i] = dialect.Quote(col)
+			}
+			target = " (" + strings.Join(quoted, ", ") + ")"
+		}
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT%s DO NOTHING", quotedTable, columnList, valueList, target), args, nil
+	}
+}
+
+// writeInsertColumns writes `table (col, ...) VALUES (?, ...)` for the given columns.
+func writeInsertColumns(buf *stmtBuffer, dialect common.Dialect, table string, columns []string) {
+	buf.WriteString(quoteQualified(dialect, table))
+	buf.WriteString(" (")
+	for i, col := range columns {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteQuoted(dialect, col)
+	}
+	buf.WriteString(") VALUES (")
+	for i := range columns {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteBindVar(dialect, i+1)
+	}
+	_ = buf.WriteByte(')')
+}
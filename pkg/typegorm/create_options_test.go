@@ -0,0 +1,190 @@
+package typegorm
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type conflictTag struct {
+	ID   uint   `typegorm:"primaryKey;autoIncrement"`
+	Slug string `typegorm:"unique"`
+}
+
+func TestBuildInsertSQL_OnConflictDoNothing(t *testing.T) {
+	tests := []struct {
+		dialect string
+		opts    []CreateOption
+		want    string
+		args    []any
+	}{
+		{"mysql", nil, "INSERT INTO `conflict_tags` (`slug`) VALUES (?)", []any{"go"}},
+		{"mysql", []CreateOption{OnConflictDoNothing()}, "INSERT INTO `conflict_tags` (`slug`) VALUES (?) ON DUPLICATE KEY UPDATE `id` = `id`", []any{"go"}},
+		{"mysql", []CreateOption{OnConflictDoNothing("slug")}, "INSERT INTO `conflict_tags` (`slug`) VALUES (?) ON DUPLICATE KEY UPDATE `slug` = `slug`", []any{"go"}},
+		{"postgres", []CreateOption{OnConflictDoNothing()}, "INSERT INTO `conflict_tags` (`slug`) VALUES (?) ON CONFLICT DO NOTHING", []any{"go"}},
+		{"postgres", []CreateOption{OnConflictDoNothing("slug")}, "INSERT INTO `conflict_tags` (`slug`) VALUES (?) ON CONFLICT (`slug`) DO NOTHING", []any{"go"}},
+		{"sqlserver", []CreateOption{OnConflictDoNothing("slug")}, "INSERT INTO `conflict_tags` (`slug`) SELECT ? WHERE NOT EXISTS (SELECT 1 FROM `conflict_tags` WHERE `slug` = ?)", []any{"go", "go"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			db, _ := newMockDBWithDialect(tt.dialect)
+			model, err := db.GetModel(&conflictTag{})
+			require.NoError(t, err)
+			sql, args, err := buildInsertSQL(db.source.Dialect(), model, []string{"slug"}, []any{"go"}, applyCreateOptions(tt.opts))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, sql)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+
+	db, _ := newMockDBWithDialect("sqlserver")
+	model, _ := db.GetModel(&conflictTag{})
+	_, _, err := buildInsertSQL(db.source.Dialect(), model, []string{"slug"}, []any{"go"}, applyCreateOptions([]CreateOption{OnConflictDoNothing()}))
+	assert.Error(t, err, "auto-increment primary key cannot be compared")
+}
+
+func TestCreate_OnConflictDoNothingSkipped(t *testing.T) {
+	db, source := newMockDBWithDialect("mysql")
+	source.affected = 0
+
+	tag := &conflictTag{Slug: "go"}
+	result := db.Create(context.Background(), tag, OnConflictDoNothing())
+	require.NoError(t, result.Error)
+	assert.Zero(t, result.RowsAffected, "skipped rows report zero rows affected")
+	assert.Zero(t, tag.ID)
+	assert.Len(t, source.Statements(), 1, "no re-fetch after a skipped insert")
+}
+
+func TestCreate_SelectAndOmit(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	require.NoError(t, db.Create(ctx, &maskUser{Name: "Ana", Email: "ana@example.com", Age: 30}, Omit("Email")).Error)
+	assert.Equal(t, "INSERT INTO `mask_users` (`name`, `age`) VALUES (?, ?)", source.Statements()[0].SQL)
+
+	tx, err := db.Begin(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.Create(ctx, &maskUser{Name: "Bia", Age: 20}, Select("Name", "email")).Error)
+	require.NoError(t, tx.Commit())
+	assert.True(t, source.containsStatement("INSERT INTO `mask_users` (`name`, `email`) VALUES (?, ?)"))
+
+	result := db.Create(ctx, &maskUser{Name: "Eva"}, Omit("Passwd"))
+	assert.ErrorContains(t, result.Error, "unknown field 'Passwd'")
+}
+
+// upsertDialect is a mock dialect with its own upsert clause, like MySQL's.
+type upsertDialect struct{ mockDialect }
+
+func (d *upsertDialect) UpsertClause(conflictColumns, updateColumns []string) string {
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(updateColumns, ", ")
+}
+
+type upsertUser struct {
+	ID        uint   `typegorm:"primaryKey;autoIncrement"`
+	Email     string `typegorm:"unique"`
+	Name      string
+	CreatedAt time.Time
+}
+
+func TestBuildInsertSQL_OnConflictUpdate(t *testing.T) {
+	db, source := newMockDBWithDialect("postgres")
+	model, err := db.GetModel(&upsertUser{})
+	require.NoError(t, err)
+	columns := []string{"email", "name", "created_at"}
+	build := func(opts ...CreateOption) (string, error) {
+		sql, _, err := buildInsertSQL(db.source.Dialect(), model, columns, []any{"a@x", "Ana", time.Time{}}, applyCreateOptions(opts))
+		return sql, err
+	}
+
+	sql, err := build(OnConflict(Conflict{Columns: []string{"email"}}))
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `upsert_users` (`email`, `name`, `created_at`) VALUES (?, ?, ?) ON CONFLICT (`email`) DO UPDATE SET `name` = EXCLUDED.`name`", sql)
+
+	sql, err = build(OnConflict(Conflict{Columns: []string{"email"}, DoUpdates: []string{"Name", "created_at"}}))
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `upsert_users` (`email`, `name`, `created_at`) VALUES (?, ?, ?) ON CONFLICT (`email`) DO UPDATE SET `name` = EXCLUDED.`name`, `created_at` = EXCLUDED.`created_at`", sql)
+
+	sql, err = build(OnConflict(Conflict{Columns: []string{"email"}, DoNothing: true}))
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `upsert_users` (`email`, `name`, `created_at`) VALUES (?, ?, ?) ON CONFLICT (`email`) DO NOTHING", sql)
+
+	_, err = build(OnConflict(Conflict{DoUpdates: []string{"ID"}}))
+	assert.ErrorContains(t, err, "is not inserted")
+
+	source.dialect = &upsertDialect{mockDialect{name: "mysql"}}
+	sql, err = build(OnConflict(Conflict{}))
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `upsert_users` (`email`, `name`, `created_at`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE email, name", sql)
+
+	source.dialect = &mockDialect{name: "sqlserver"}
+	_, err = build(OnConflict(Conflict{}))
+	assert.ErrorIs(t, err, ErrUnsupportedDialect)
+}
+
+func TestSave_InsertsOrUpserts(t *testing.T) {
+	db, source := newMockDBWithDialect("postgres")
+	ctx := context.Background()
+
+	require.NoError(t, db.Save(ctx, &upsertUser{Email: "a@x", Name: "Ana"}).Error)
+	assert.Equal(t, "INSERT INTO `upsert_users` (`email`, `name`, `created_at`) VALUES (?, ?, ?)", source.Statements()[0].SQL)
+
+	user := &upsertUser{ID: 42, Email: "b@x", Name: "Bia"}
+	require.NoError(t, db.Save(ctx, user).Error)
+	assert.True(t, source.containsStatement("INSERT INTO `upsert_users` (`id`, `email`, `name`, `created_at`) VALUES (?, ?, ?, ?) ON CONFLICT (`id`) DO UPDATE SET `email` = EXCLUDED.`email`, `name` = EXCLUDED.`name`"))
+	assert.Equal(t, uint(42), user.ID, "the key is kept when the upsert reports no ID")
+}
+
+func TestSave_MovesCounterCachesOfAnUpdatedRow(t *testing.T) {
+	db, source := newMockDBWithDialect("mysql")
+	ctx := context.Background()
+
+	// MySQL reports 2 affected rows for an updated row: the post moves from user 3 to 4
+	source.queueRows([]string{"user_id"}, []any{int64(3)})
+	source.affected = 2
+	require.NoError(t, db.Save(ctx, &counterPost{ID: 5, UserID: 4, Title: "moved"}).Error)
+	stmts := source.Statements()
+	assert.Equal(t, "SELECT `user_id` FROM `counter_posts` WHERE `id` = ?", stmts[0].SQL)
+	assert.Equal(t, []mockStatement{
+		{SQL: "UPDATE `users` SET `posts_count` = `posts_count` - 1 WHERE `id` = ?", Args: []any{int64(3)}},
+		{SQL: "UPDATE `users` SET `posts_count` = `posts_count` + 1 WHERE `id` = ?", Args: []any{int64(4)}},
+	}, stmts[len(stmts)-2:])
+
+	// Same parent: the counters are left alone
+	source.statements = nil
+	source.queueRows([]string{"user_id"}, []any{int64(4)})
+	require.NoError(t, db.Save(ctx, &counterPost{ID: 5, UserID: 4, Title: "edited"}).Error)
+	assert.False(t, source.containsStatement("UPDATE `users` SET `posts_count` = `posts_count` + 1 WHERE `id` = ?"))
+
+	// 1 affected row is an insert, even if the row existed when it was read
+	source.statements = nil
+	source.queueRows([]string{"user_id"}, []any{int64(3)})
+	source.affected = 1
+	require.NoError(t, db.Save(ctx, &counterPost{ID: 6, UserID: 4, Title: "new"}).Error)
+	stmts = source.Statements()
+	assert.Equal(t, mockStatement{SQL: "UPDATE `users` SET `posts_count` = `posts_count` + 1 WHERE `id` = ?", Args: []any{int64(4)}}, stmts[len(stmts)-1])
+	assert.False(t, source.containsStatement("UPDATE `users` SET `posts_count` = `posts_count` - 1 WHERE `id` = ?"))
+}
+
+func TestSave_ChecksStateTransitions(t *testing.T) {
+	db, source := newStateMachineDB(t, true)
+	ctx := context.Background()
+
+	source.queueRows([]string{"status"}, []any{"pending"})
+	err := db.Save(ctx, &smOrder{ID: 1, Status: "shipped"}).Error
+	require.ErrorIs(t, err, ErrInvalidTransition)
+	assert.Len(t, source.Statements(), 1, "no upsert")
+
+	source.statements = nil
+	source.queueRows([]string{"status"}, []any{"pending"})
+	require.NoError(t, db.Save(ctx, &smOrder{ID: 1, Status: "paid"}).Error)
+	assert.True(t, source.containsStatement("INSERT INTO `state_transitions` (`model`, `record_id`, `field`, `from_state`, `to_state`, `created_at`) VALUES (?, ?, ?, ?, ?, ?)"), sqlOf(source.Statements()))
+
+	// A new row has no transition to check or record
+	source.statements = nil
+	require.NoError(t, db.Save(ctx, &smOrder{ID: 2, Status: "shipped"}).Error)
+	assert.False(t, source.containsStatement("INSERT INTO `state_transitions` (`model`, `record_id`, `field`, `from_state`, `to_state`, `created_at`) VALUES (?, ?, ?, ?, ?, ?)"))
+}
@@ -0,0 +1,96 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+)
+
+// --- Credential Rotation ---
+
+// credentialRefresher re-resolves the secret references of the database config
+// (database.dsnFrom, database.passwordFrom) every database.secretRefresh, and hands
+// rotated credentials to the connection pools (common.CredentialRefresher).
+type credentialRefresher struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// startCredentialRefresh starts the rotation of db's credentials; nil when the config
+// has no secret references or no refresh interval.
+func startCredentialRefresh(db *DB, cfg config.DatabaseConfig) *credentialRefresher {
+	if cfg.SecretRefresh <= 0 || (cfg.DSNFrom == "" && cfg.PasswordFrom == "") {
+		return nil
+	}
+	if _, ok := db.source.(common.CredentialRefresher); !ok {
+		fmt.Printf("Warning: dialect '%s' cannot refresh credentials; database.secretRefresh is ignored\n", cfg.Dialect)
+		return nil
+	}
+	r := &credentialRefresher{stop: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(cfg.SecretRefresh)
+		defer ticker.Stop()
+		current := cfg
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), cfg.SecretRefresh)
+			next, err := current.ResolveCredentials(ctx)
+			cancel()
+			if err != nil {
+				fmt.Printf("Warning: refreshing database credentials: %v\n", err)
+				continue
+			}
+			if next.DSN == current.DSN && next.Password == current.Password {
+				continue
+			}
+			db.refreshCredentials(next)
+			current = next
+		}
+	}()
+	return r
+}
+
+// close stops the rotation.
+func (r *credentialRefresher) close() {
+	if r == nil {
+		return
+	}
+	close(r.stop)
+	<-r.done
+}
+
+// refreshCredentials passes rotated credentials to the primary, the named pools (same
+// DSN) and the replicas (their own DSN, the new password).
+func (db *DB) refreshCredentials(cfg config.DatabaseConfig) {
+	refresh := func(name string, ds common.DataSource, cfg config.DatabaseConfig) {
+		refresher, ok := ds.(common.CredentialRefresher)
+		if !ok {
+			return
+		}
+		if err := refresher.RefreshCredentials(cfg); err != nil {
+			fmt.Printf("Warning: refreshing credentials of %s: %v\n", name, err)
+		}
+	}
+	refresh("the primary", db.source, cfg)
+	for name, ds := range db.pools {
+		refresh("pool "+name, ds, cfg)
+	}
+	if db.replicas != nil {
+		for i, ds := range db.replicas.sources {
+			replicaCfg := cfg
+			if i < len(cfg.Replicas) {
+				replicaCfg.DSN = cfg.Replicas[i]
+			}
+			refresh(fmt.Sprintf("replica %d", i+1), ds, replicaCfg)
+		}
+	}
+	fmt.Println("Database credentials rotated.")
+}
@@ -0,0 +1,60 @@
+package typegorm
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// refreshingSource is a mock DataSource recording rotated credentials.
+type refreshingSource struct {
+	*mockSource
+	mu        sync.Mutex
+	passwords []string
+}
+
+func (s *refreshingSource) RefreshCredentials(cfg config.DatabaseConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.passwords = append(s.passwords, cfg.Password)
+	return nil
+}
+
+func (s *refreshingSource) refreshed() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.passwords...)
+}
+
+func TestCredentialRefresh_RotatesChangedSecrets(t *testing.T) {
+	var mu sync.Mutex
+	password := "first"
+	config.RegisterSecretProvider("rotating", config.SecretProviderFunc(func(ctx context.Context, ref string) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return password, nil
+	}))
+
+	source := &refreshingSource{mockSource: newMockSource()}
+	db := NewDB(source, nil, config.Config{})
+	cfg := config.DatabaseConfig{Dialect: "mock", PasswordFrom: "rotating:db", Password: "first", SecretRefresh: 5 * time.Millisecond}
+	db.secrets = startCredentialRefresh(db, cfg)
+	require.NotNil(t, db.secrets)
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, source.refreshed(), "unchanged secrets are not pushed")
+
+	mu.Lock()
+	password = "second"
+	mu.Unlock()
+	require.Eventually(t, func() bool { return len(source.refreshed()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []string{"second"}, source.refreshed())
+
+	require.NoError(t, db.Close())
+	assert.Nil(t, startCredentialRefresh(db, config.DatabaseConfig{PasswordFrom: "rotating:db"}), "no interval, no rotation")
+}
@@ -0,0 +1,52 @@
+package typegorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronSchedule_Next(t *testing.T) {
+	at := func(month time.Month, day, hour, minute, second int) time.Time {
+		return time.Date(2026, month, day, hour, minute, second, 0, time.UTC)
+	}
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"*/15 * * * *", at(1, 1, 10, 7, 0), at(1, 1, 10, 15, 0)},
+		{"*/15 * * * *", at(1, 1, 10, 15, 0), at(1, 1, 10, 30, 0)},
+		{"0 9 * * mon-fri", at(1, 3, 10, 0, 0), at(1, 5, 9, 0, 0)}, // Saturday -> Monday
+		{"30 2 1,15 * *", at(1, 15, 3, 0, 0), at(2, 1, 2, 30, 0)},
+		{"0 0 13 * 5", at(1, 1, 0, 0, 0), at(1, 2, 0, 0, 0)}, // Day 13 or Friday
+		{"0 0 * * 7", at(1, 1, 0, 0, 0), at(1, 4, 0, 0, 0)},  // 7 is Sunday
+		{"0 12 * JUN *", at(1, 1, 0, 0, 0), at(6, 1, 12, 0, 0)},
+		{"@hourly", at(1, 1, 10, 59, 30), at(1, 1, 11, 0, 0)},
+		{"@every 90s", at(1, 1, 10, 0, 0).Add(500 * time.Millisecond), at(1, 1, 10, 1, 30)},
+		{"0 0 29 2 *", at(3, 1, 0, 0, 0), time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", at(1, 1, 0, 0, 0), time.Time{}},
+	}
+	for _, tt := range tests {
+		schedule, err := ParseCron(tt.expr)
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.want, schedule.Next(tt.from), "%s from %s", tt.expr, tt.from)
+	}
+}
+
+func TestParseCron_Errors(t *testing.T) {
+	for expr, message := range map[string]string{
+		"* * *":         "expected 5 fields",
+		"60 * * * *":    `invalid value "60" in minute field`,
+		"5-1 * * * *":   "invalid range",
+		"*/0 * * * *":   "invalid step",
+		"* * * foo *":   "month field",
+		"@every 10ms":   "at least 1s",
+		"@every banana": "at least 1s",
+	} {
+		_, err := ParseCron(expr)
+		assert.ErrorContains(t, err, message, expr)
+	}
+}
@@ -0,0 +1,121 @@
+package typegorm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Common Table Expressions (WITH) ---
+
+// cte is a named subquery attached to a query with With or WithRecursive.
+type cte struct {
+	name      string
+	query     Subquery
+	recursive bool
+}
+
+// With adds a common table expression the query can read with Table:
+//
+//	recent := typegorm.From(&Order{}, map[string]any{"created_at >": since})
+//	big := typegorm.From(&Order{}, map[string]any{"total >": 1000}).
+//		With("recent", recent).Table("recent")
+func (q *SelectQuery) With(name string, query Subquery) *SelectQuery {
+	q.ctes = append(q.ctes, cte{name: name, query: query})
+	return q
+}
+
+// WithRecursive adds a recursive common table expression, typically a UnionAll of an
+// anchor query and a Raw query joining the CTE to itself, to walk hierarchies:
+//
+//	tree := typegorm.UnionAll(
+//		typegorm.From(&Category{}, map[string]any{"id": rootID}),
+//		typegorm.Raw("SELECT c.id, c.parent_id, c.name FROM categories c JOIN tree t ON c.parent_id = t.id"),
+//	)
+//	db.FindQuery(ctx, &categories, typegorm.From(&Category{}).WithRecursive("tree", tree).Table("tree"))
+func (q *SelectQuery) WithRecursive(name string, query Subquery) *SelectQuery {
+	q.ctes = append(q.ctes, cte{name: name, query: query, recursive: true})
+	return q
+}
+
+// Table makes the query read from the named relation (a CTE or another table with the
+// model's columns) instead of the model's table. Conditions still use the model's columns.
+func (q *SelectQuery) Table(name string) *SelectQuery {
+	q.table = name
+	return q
+}
+
+// With adds a common table expression to the compound query. See SelectQuery.With.
+func (u *UnionQuery) With(name string, query Subquery) *UnionQuery {
+	u.ctes = append(u.ctes, cte{name: name, query: query})
+	return u
+}
+
+// WithRecursive adds a recursive common table expression to the compound query.
+// See SelectQuery.WithRecursive.
+func (u *UnionQuery) WithRecursive(name string, query Subquery) *UnionQuery {
+	u.ctes = append(u.ctes, cte{name: name, query: query, recursive: true})
+	return u
+}
+
+// RawQuery is a hand-written SELECT used as a Subquery, e.g. the recursive member of a
+// recursive CTE. It must select the same columns, in the same order, as the other
+// operands; placeholders use the dialect's syntax, each argument once and in order.
+type RawQuery struct {
+	sql  string
+	args []any
+}
+
+// Raw builds a RawQuery. WARNING: the SQL is used verbatim; pass values as arguments.
+func Raw(sql string, args ...any) *RawQuery {
+	return &RawQuery{sql: sql, args: args}
+}
+
+func (r *RawQuery) subquerySQL(qb *queryBuild, columns *schema.Model) (string, []any, bool, error) {
+	if strings.TrimSpace(r.sql) == "" {
+		return "", nil, false, fmt.Errorf("query: Raw requires SQL")
+	}
+	return r.sql, r.args, false, nil
+}
+
+// renderCTEs renders the WITH clause (with a trailing space), or "" without CTEs.
+// RECURSIVE is a property of the whole clause, so it is set when any CTE is recursive.
+func renderCTEs(qb *queryBuild, ctes []cte) (string, []any, error) {
+	if len(ctes) == 0 {
+		return "", nil, nil
+	}
+	var (
+		b         strings.Builder
+		args      []any
+		recursive bool
+	)
+	seen := make(map[string]bool, len(ctes))
+	for i, c := range ctes {
+		if c.name == "" {
+			return "", nil, fmt.Errorf("with: CTE %d has no name", i+1)
+		}
+		if seen[c.name] {
+			return "", nil, fmt.Errorf("with: CTE %q is defined twice", c.name)
+		}
+		seen[c.name] = true
+		if c.query == nil {
+			return "", nil, fmt.Errorf("with: CTE %q has no query", c.name)
+		}
+		body, bodyArgs, _, err := c.query.subquerySQL(qb, nil)
+		if err != nil {
+			return "", nil, fmt.Errorf("with: CTE %q: %w", c.name, err)
+		}
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(qb.dialect.Quote(c.name) + " AS (" + body + ")")
+		args = append(args, bodyArgs...)
+		recursive = recursive || c.recursive
+	}
+	prefix := "WITH "
+	if recursive {
+		prefix = "WITH RECURSIVE "
+	}
+	return prefix + b.String() + " ", args, nil
+}
@@ -0,0 +1,59 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cteCategory struct {
+	ID       uint `typegorm:"primaryKey;autoIncrement"`
+	ParentID uint
+	Name     string
+}
+
+func TestFindQuery_With(t *testing.T) {
+	db, source := newMockDB()
+	queueMaskUsers(source, 1)
+
+	adults := From(&maskUser{}, map[string]any{"age >=": 18})
+	query := From(&maskUser{}, map[string]any{"name": "ana"}).With("adults", adults).Table("adults")
+
+	var users []maskUser
+	require.NoError(t, db.FindQuery(context.Background(), &users, query).Error)
+	last := source.lastStatement()
+	assert.Equal(t, "WITH `adults` AS (SELECT `id`, `name`, `email`, `age` FROM `mask_users` WHERE `age` >= ?) "+
+		"SELECT `id`, `name`, `email`, `age` FROM `adults` WHERE `name` = ?", last.SQL)
+	assert.Equal(t, []any{18, "ana"}, last.Args)
+}
+
+func TestFindQuery_WithRecursive(t *testing.T) {
+	db, source := newMockDB()
+	source.queueRows([]string{"id", "parent_id", "name"}, []any{int64(1), int64(0), "root"}, []any{int64(2), int64(1), "child"})
+
+	tree := UnionAll(
+		From(&cteCategory{}, map[string]any{"id": 1}),
+		Raw("SELECT c.id, c.parent_id, c.name FROM cte_categorys c JOIN tree t ON c.parent_id = t.id"),
+	)
+	var categories []cteCategory
+	result := db.FindQuery(context.Background(), &categories, From(&cteCategory{}).WithRecursive("tree", tree).Table("tree"), Order("name"))
+	require.NoError(t, result.Error)
+	require.Len(t, categories, 2)
+	assert.Equal(t, "child", categories[1].Name)
+
+	assert.Equal(t, "WITH RECURSIVE `tree` AS (SELECT `id`, `parent_id`, `name` FROM `cte_categorys` WHERE `id` = ?"+
+		" UNION ALL SELECT c.id, c.parent_id, c.name FROM cte_categorys c JOIN tree t ON c.parent_id = t.id)"+
+		" SELECT `id`, `parent_id`, `name` FROM `tree` ORDER BY `name`", source.lastStatement().SQL)
+}
+
+func TestRenderCTEs_Validation(t *testing.T) {
+	db, _ := newMockDB()
+	qb := &queryBuild{dialect: db.source.Dialect(), parser: db.parser}
+
+	_, _, err := renderCTEs(qb, []cte{{name: "a", query: Raw("SELECT 1")}, {name: "a", query: Raw("SELECT 2")}})
+	assert.ErrorContains(t, err, "defined twice")
+	_, _, err = renderCTEs(qb, []cte{{name: "a"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,254 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Mock DataSource used by the unit tests of this package ---
+
+type mockDialect struct{ name string }
+
+func (d *mockDialect) Name() string {
+	if d.name == "" {
+		return "mock"
+	}
+	return d.name
+}
+func (d *mockDialect) Quote(identifier string) string { return "`" + identifier + "`" }
+func (d *mockDialect) BindVar(i int) string           { return "?" }
+func (d *mockDialect) GetDataType(field *schema.Field) (string, error) {
+	switch field.GoType.Kind() {
+	case reflect.String:
+		return "TEXT", nil
+	default:
+		return "INT", nil
+	}
+}
+func (d *mockDialect) CreateSchemaMigrationsTableSQL(tableName string) string { return "" }
+func (d *mockDialect) GetAppliedMigrationsSQL(tableName string) string        { return "" }
+func (d *mockDialect) InsertMigrationSQL(tableName string) string             { return "" }
+func (d *mockDialect) DeleteMigrationSQL(tableName string) string             { return "" }
+
+// mockStatement records one statement sent to the mock.
+type mockStatement struct {
+	SQL  string
+	Args []any
+}
+
+// mockSource records every statement and answers queries with the queued results.
+type mockSource struct {
+	mu         sync.Mutex
+	dialect    common.Dialect
+	statements []mockStatement
+	results    []*mockRows // Returned in order by Query/QueryRow
+	execErr    error
+	execErrs   []error // Returned by the next Exec calls, in order, before execErr
+	queryErr   error   // Returned by Query (e.g., to simulate a dead connection)
+	affected   int64
+	lastID     int64
+	autoID     bool // Each Exec returns the next lastID
+}
+
+func newMockSource() *mockSource {
+	return &mockSource{dialect: &mockDialect{}, affected: 1}
+}
+
+// queueRows queues the rows returned by the next Query/QueryRow call.
+func (m *mockSource) queueRows(columns []string, values ...[]any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results = append(m.results, &mockRows{columns: columns, values: values, pos: -1})
+}
+
+func (m *mockSource) record(query string, args []any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statements = append(m.statements, mockStatement{SQL: query, Args: args})
+}
+
+func (m *mockSource) nextRows() *mockRows {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.results) == 0 {
+		return &mockRows{pos: -1}
+	}
+	r := m.results[0]
+	m.results = m.results[1:]
+	return r
+}
+
+// Statements returns a copy of all recorded statements.
+func (m *mockSource) Statements() []mockStatement {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]mockStatement(nil), m.statements...)
+}
+
+func (m *mockSource) Close() error                            { return nil }
+func (m *mockSource) Connect(cfg config.DatabaseConfig) error { return nil }
+func (m *mockSource) Ping(ctx context.Context) error          { return nil }
+func (m *mockSource) Dialect() common.Dialect                 { return m.dialect }
+func (m *mockSource) BeginTx(ctx context.Context, opts any) (common.Tx, error) {
+	m.record("BEGIN", nil)
+	return &mockTx{source: m}, nil
+}
+func (m *mockSource) Exec(ctx context.Context, query string, args ...any) (common.Result, error) {
+	m.record(query, args)
+	m.mu.Lock()
+	if m.autoID {
+		m.lastID++
+	}
+	if len(m.execErrs) > 0 {
+		err := m.execErrs[0]
+		m.execErrs = m.execErrs[1:]
+		m.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		return &mockResult{affected: m.affected, lastID: m.lastID}, nil
+	}
+	m.mu.Unlock()
+	if m.execErr != nil {
+		return nil, m.execErr
+	}
+	return &mockResult{affected: m.affected, lastID: m.lastID}, nil
+}
+func (m *mockSource) QueryRow(ctx context.Context, query string, args ...any) common.RowScanner {
+	m.record(query, args)
+	return &mockRowScanner{rows: m.nextRows()}
+}
+func (m *mockSource) Query(ctx context.Context, query string, args ...any) (common.Rows, error) {
+	m.record(query, args)
+	if m.queryErr != nil {
+		return nil, m.queryErr
+	}
+	return m.nextRows(), nil
+}
+
+func (m *mockSource) PinConn(ctx context.Context) (common.Conn, error) {
+	m.record("PIN", nil)
+	return &mockConn{source: m}, nil
+}
+
+// mockConn records its release as "RELEASE" (Close) or "DISCARD" (Discard).
+type mockConn struct{ source *mockSource }
+
+func (c *mockConn) Close() error   { c.source.record("RELEASE", nil); return nil }
+func (c *mockConn) Discard() error { c.source.record("DISCARD", nil); return nil }
+func (c *mockConn) Exec(ctx context.Context, query string, args ...any) (common.Result, error) {
+	return c.source.Exec(ctx, query, args...)
+}
+func (c *mockConn) QueryRow(ctx context.Context, query string, args ...any) common.RowScanner {
+	return c.source.QueryRow(ctx, query, args...)
+}
+func (c *mockConn) Query(ctx context.Context, query string, args ...any) (common.Rows, error) {
+	return c.source.Query(ctx, query, args...)
+}
+
+type mockTx struct{ source *mockSource }
+
+func (t *mockTx) Commit() error   { t.source.record("COMMIT", nil); return nil }
+func (t *mockTx) Rollback() error { t.source.record("ROLLBACK", nil); return nil }
+func (t *mockTx) Exec(ctx context.Context, query string, args ...any) (common.Result, error) {
+	return t.source.Exec(ctx, query, args...)
+}
+func (t *mockTx) QueryRow(ctx context.Context, query string, args ...any) common.RowScanner {
+	return t.source.QueryRow(ctx, query, args...)
+}
+func (t *mockTx) Query(ctx context.Context, query string, args ...any) (common.Rows, error) {
+	return t.source.Query(ctx, query, args...)
+}
+
+type mockResult struct{ affected, lastID int64 }
+
+func (r *mockResult) LastInsertId() (int64, error) { return r.lastID, nil }
+func (r *mockResult) RowsAffected() (int64, error) { return r.affected, nil }
+
+type mockRows struct {
+	columns []string
+	values  [][]any
+	pos     int
+}
+
+func (r *mockRows) Close() error               { return nil }
+func (r *mockRows) Next() bool                 { r.pos++; return r.pos < len(r.values) }
+func (r *mockRows) Columns() ([]string, error) { return r.columns, nil }
+func (r *mockRows) Err() error                 { return nil }
+func (r *mockRows) Scan(dest ...any) error {
+	row := r.values[r.pos]
+	if len(dest) != len(row) {
+		return fmt.Errorf("mock: expected %d scan destinations, got %d", len(row), len(dest))
+	}
+	for i, d := range dest {
+		if scanner, ok := d.(sql.Scanner); ok {
+			if err := scanner.Scan(row[i]); err != nil {
+				return err
+			}
+			continue
+		}
+		target := reflect.ValueOf(d).Elem()
+		if row[i] == nil {
+			target.Set(reflect.Zero(target.Type()))
+			continue
+		}
+		v := reflect.ValueOf(row[i])
+		if target.Kind() == reflect.Pointer {
+			ptr := reflect.New(target.Type().Elem())
+			ptr.Elem().Set(v.Convert(target.Type().Elem()))
+			target.Set(ptr)
+			continue
+		}
+		target.Set(v.Convert(target.Type()))
+	}
+	return nil
+}
+
+type mockRowScanner struct{ rows *mockRows }
+
+func (s *mockRowScanner) Scan(dest ...any) error {
+	if !s.rows.Next() {
+		return sql.ErrNoRows
+	}
+	return s.rows.Scan(dest...)
+}
+
+// newMockDB builds a DB backed by a fresh mockSource.
+func newMockDB() (*DB, *mockSource) {
+	source := newMockSource()
+	return NewDB(source, schema.NewParser(nil), config.NewDefaultConfig()), source
+}
+
+// newMockDBWithDialect builds a mock DB that reports the given dialect name.
+func newMockDBWithDialect(name string) (*DB, *mockSource) {
+	db, source := newMockDB()
+	source.dialect = &mockDialect{name: name}
+	return db, source
+}
+
+// lastStatement returns the SQL of the most recent recorded statement.
+func (m *mockSource) lastStatement() mockStatement {
+	stmts := m.Statements()
+	if len(stmts) == 0 {
+		return mockStatement{}
+	}
+	return stmts[len(stmts)-1]
+}
+
+// containsStatement reports whether any recorded statement contains the fragment.
+func (m *mockSource) containsStatement(fragment string) bool {
+	for _, s := range m.Statements() {
+		if strings.Contains(s.SQL, fragment) {
+			return true
+		}
+	}
+	return false
+}
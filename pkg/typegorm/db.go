@@ -8,8 +8,8 @@ import (
 	"fmt"
 	"math"
 	"reflect"
-	"strconv"
 	"strings" // For SQL builder
+	"sync/atomic"
 	"time"
 
 	"github.com/chmenegatti/typegorm/pkg/config" // Needed if Open stays here
@@ -18,10 +18,30 @@ import (
 )
 
 // DB represents the main ORM database handle. Provides ORM methods.
+// A DB is safe for concurrent use by multiple goroutines; share one per database.
+// Transactions started from it (Tx) are not.
 type DB struct {
-	source common.DataSource // The underlying connected DataSource (MySQL, Postgres, etc.)
-	parser *schema.Parser
-	config config.Config // Store original config for potential use
+	source    common.DataSource // The underlying connected DataSource (MySQL, Postgres, etc.)
+	parser    *schema.Parser
+	config    config.Config                // Store original config for potential use
+	relations *virtualRelations            // Virtual relations resolved by user-provided loaders
+	leaks     *leakTracker                 // Rows/Tx leak detection (nil when disabled)
+	callbacks *CallbackRegistry            // Lifecycle callbacks run around the model hooks
+	replicas  *replicaSet                  // Read replicas (nil when none are configured)
+	pools     map[string]common.DataSource // Named pools selected with WithPool
+	machines  *stateMachines               // State machines validating Updates
+	cache     EntityCache                  // FindByID cache of CachedEntity models (nil when disabled)
+	codec     Codec                        // Payload serialization (nil: JSON)
+	now       NowFunc                      // Clock of timestamps set by the ORM (nil: time.Now)
+	ids       IDGenerator                  // Primary keys of new rows (nil: none generated)
+	rewriter  ConditionRewriter            // Rewrites query conditions before they run (nil: none)
+	policies  []PolicyFunc                 // Data-access policies checked before statements run
+	osc       OnlineSchemaChanger          // Tool running MySQL ALTER TABLE online (nil: direct)
+	secrets   *credentialRefresher         // Rotation of secret credentials (nil when disabled)
+	failover  *primaryFailover             // Reconnection to a new primary (nil when disabled)
+	throttle  *writeThrottle               // Rate limits of the writes (see ThrottleWrites)
+	metrics   MetricsCollector             // Receives the ORM's metrics (nil: none)
+	lockTable atomic.Bool                  // The table of LockRecord exists
 	// TODO: Add logger, context, etc.
 }
 
@@ -34,11 +54,24 @@ func NewDB(source common.DataSource, parser *schema.Parser, cfg config.Config) *
 	if parser == nil {
 		parser = schema.NewParser(nil) // Use default parser if none provided
 	}
-	return &DB{
-		source: source,
-		parser: parser,
-		config: cfg,
-	}
+	db := &DB{
+		source:    source,
+		parser:    parser,
+		config:    cfg,
+		relations: newVirtualRelations(),
+		machines:  newStateMachines(),
+		callbacks: newCallbackRegistry(),
+		throttle:  newWriteThrottle(),
+	}
+	db.callbacks.async = newAsyncDispatcher(db, cfg.Hooks)
+	db.callbacks.db = db
+	db.callbacks.deferAll = cfg.Hooks.DeferInTransaction
+	setLogLevel(cfg.Logging.Level)
+	if cfg.Logging.LeakDetection || strings.EqualFold(cfg.Logging.Level, "debug") {
+		fmt.Println("Leak detection enabled for rows and transactions.")
+		db.leaks = newLeakTracker()
+	}
+	return db
 }
 
 // Close closes the underlying database connection pool.
@@ -46,6 +79,16 @@ func (db *DB) Close() error {
 	if db.source == nil {
 		return fmt.Errorf("db source is nil, cannot close")
 	}
+	db.callbacks.async.close() // Let queued async callbacks finish first
+	db.secrets.close()
+	if err := closePools(db.pools); err != nil {
+		fmt.Printf("Warning: closing connection pools: %v\n", err)
+	}
+	if db.replicas != nil {
+		if err := db.replicas.close(); err != nil {
+			fmt.Printf("Warning: closing replicas: %v\n", err)
+		}
+	}
 	return db.source.Close()
 }
 
@@ -59,7 +102,12 @@ func (db *DB) Ping(ctx context.Context) error {
 
 // GetDataSource returns the underlying common.DataSource.
 // Useful for executing raw SQL or accessing dialect-specific features if needed.
+// With leak detection enabled, the returned DataSource tracks the Rows and
+// transactions opened through it (call Unwrap for the original one).
 func (db *DB) GetDataSource() common.DataSource {
+	if db.leaks != nil {
+		return &trackingSource{DataSource: db.source, tracker: db.leaks}
+	}
 	return db.source
 }
 
@@ -79,62 +127,62 @@ func (db *DB) AutoMigrate(ctx context.Context, values ...any) error {
 	dialect := db.source.Dialect()
 
 	for _, value := range values {
-		model, err := db.parser.Parse(value)
-		if err != nil {
-			return fmt.Errorf("automigrate: failed to parse schema for type %T: %w", value, err)
-		}
-
-		tableName := dialect.Quote(model.TableName)
-		fmt.Printf("AutoMigrate: Ensuring table %s exists for model %s...\n", tableName, model.Name)
-
-		var columnDefs []string
-		var primaryKeyNames []string
-
-		for _, field := range model.Fields {
-			if field.IsIgnored {
-				continue
-			}
-
-			// Get column type definition using the dialect's refined GetDataType
-			colType, err := dialect.GetDataType(field)
+		// Declarative models (e.g., loaded from a YAML schema file) are used as-is
+		model, ok := value.(*schema.Model)
+		if !ok {
+			var err error
+			model, err = db.parser.Parse(value)
 			if err != nil {
-				return fmt.Errorf("automigrate: failed to get data type for field %s.%s: %w", model.Name, field.GoName, err)
+				return fmt.Errorf("automigrate: failed to parse schema for type %T: %w", value, err)
 			}
+		}
 
-			columnDefs = append(columnDefs, fmt.Sprintf("%s %s", dialect.Quote(field.DBName), colType))
+		tableName := quoteTable(dialect, model)
+		fmt.Printf("AutoMigrate: Ensuring table %s exists for model %s...\n", tableName, model.Name)
 
-			if field.IsPrimaryKey {
-				primaryKeyNames = append(primaryKeyNames, dialect.Quote(field.DBName))
-			}
-			// TODO: Handle UNIQUE constraints defined directly via GetDataType? Or add separately?
+		// Renamed tables/columns keep their data instead of getting an empty new table
+		if err := db.Migrator().ApplyRenames(ctx, model); err != nil {
+			return fmt.Errorf("automigrate: %w", err)
 		}
 
-		if len(columnDefs) == 0 {
+		createTableSQL, err := CreateTableSQL(dialect, model)
+		if err != nil {
+			return fmt.Errorf("automigrate: %w", err)
+		}
+		if createTableSQL == "" {
 			fmt.Printf("AutoMigrate: Skipping model %s, no migratable fields found.\n", model.Name)
 			continue
 		}
 
-		// Add composite primary key constraint if multiple PKs defined
-		if len(primaryKeyNames) > 1 {
-			// If more than one field is marked as PK, add a separate composite key constraint.
-			// Assumes GetDataType does NOT add PRIMARY KEY inline in this composite case
-			// (or we would need to modify GetDataType too). Let's assume GetDataType only adds PK inline for single PKs.
-			pkConstraint := fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(primaryKeyNames, ", "))
-			columnDefs = append(columnDefs, pkConstraint)
-			fmt.Printf("AutoMigrate: Adding composite primary key constraint for %s.\n", model.Name)
+		if stmt := createSchemaSQL(dialect, model); stmt != "" {
+			fmt.Printf("AutoMigrate: Executing: %s\n", stmt)
+			if _, err := db.conn(ctx).Exec(ctx, stmt); err != nil {
+				return fmt.Errorf("automigrate: failed to create schema %s: %w", model.Schema, err)
+			}
 		}
-		// Assemble CREATE TABLE statement
-		createTableSQL := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s);",
-			tableName,
-			strings.Join(columnDefs, ", "),
-		)
 
 		// Execute CREATE TABLE statement
 		fmt.Printf("AutoMigrate: Executing: %s\n", createTableSQL) // Log the SQL
-		_, err = db.source.Exec(ctx, createTableSQL)
+		_, err = db.conn(ctx).Exec(ctx, createTableSQL)
 		if err != nil {
 			return fmt.Errorf("automigrate: failed to create/ensure table %s for model %s: %w", tableName, model.Name, err)
 		}
+		for _, stmt := range CommentStatements(dialect, model) {
+			fmt.Printf("AutoMigrate: Executing: %s\n", stmt)
+			if _, err := db.conn(ctx).Exec(ctx, stmt); err != nil {
+				return fmt.Errorf("automigrate: failed to comment table %s: %w", tableName, err)
+			}
+		}
+		temporalSQL, err := TemporalSQL(dialect, model)
+		if err != nil {
+			return fmt.Errorf("automigrate: %w", err)
+		}
+		for _, stmt := range temporalSQL {
+			fmt.Printf("AutoMigrate: Executing: %s\n", stmt)
+			if _, err := db.conn(ctx).Exec(ctx, stmt); err != nil {
+				return fmt.Errorf("automigrate: failed to version table %s: %w", tableName, err)
+			}
+		}
 
 		// TODO: Index Creation - requires iterating model.Indexes and generating CREATE INDEX SQL
 		// for _, index := range model.Indexes {
@@ -148,9 +196,102 @@ func (db *DB) AutoMigrate(ctx context.Context, values ...any) error {
 	return nil
 }
 
+// createSchemaSQL builds the CREATE SCHEMA IF NOT EXISTS statement for a schema-qualified
+// model, on the dialects supporting it (Postgres, MySQL).
+func createSchemaSQL(dialect common.Dialect, model *schema.Model) string {
+	switch dialect.Name() {
+	case "postgres", "mysql":
+		if model.Schema != "" {
+			return "CREATE SCHEMA IF NOT EXISTS " + dialect.Quote(model.Schema)
+		}
+	}
+	return ""
+}
+
+// CreateTableSQL builds the CREATE TABLE IF NOT EXISTS statement for a model.
+// Returns an empty string if the model has no migratable fields.
+func CreateTableSQL(dialect common.Dialect, model *schema.Model) (string, error) {
+	var columnDefs []string
+	var primaryKeyNames []string
+
+	for _, field := range model.Fields {
+		if field.IsIgnored {
+			continue
+		}
+
+		// Get column type definition using the dialect's refined GetDataType
+		colType, err := dialect.GetDataType(field)
+		if err != nil {
+			return "", fmt.Errorf("failed to get data type for field %s.%s: %w", model.Name, field.GoName, err)
+		}
+
+		if field.Comment != "" && inlineComments(dialect) {
+			colType += " COMMENT " + quoteComment(dialect, field.Comment)
+		}
+		columnDefs = append(columnDefs, fmt.Sprintf("%s %s", dialect.Quote(field.DBName), colType))
+
+		if field.IsPrimaryKey {
+			primaryKeyNames = append(primaryKeyNames, dialect.Quote(field.DBName))
+		}
+		// TODO: Handle UNIQUE constraints defined directly via GetDataType? Or add separately?
+	}
+
+	if len(columnDefs) == 0 {
+		return "", nil
+	}
+
+	// Add composite primary key constraint if multiple PKs defined
+	if len(primaryKeyNames) > 1 {
+		// If more than one field is marked as PK, add a separate composite key constraint.
+		// Assumes GetDataType does NOT add PRIMARY KEY inline in this composite case
+		// (or we would need to modify GetDataType too). Let's assume GetDataType only adds PK inline for single PKs.
+		pkConstraint := fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(primaryKeyNames, ", "))
+		columnDefs = append(columnDefs, pkConstraint)
+		fmt.Printf("AutoMigrate: Adding composite primary key constraint for %s.\n", model.Name)
+	}
+	tableOptions := ""
+	if model.Comment != "" && inlineComments(dialect) {
+		tableOptions = " COMMENT=" + quoteComment(dialect, model.Comment)
+	}
+	// Assemble CREATE TABLE statement
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)%s;",
+		quoteTable(dialect, model),
+		strings.Join(columnDefs, ", "),
+		tableOptions,
+	), nil
+}
+
 // *** IMPLEMENT Create Method ***
-func (db *DB) Create(ctx context.Context, value any) *Result {
-	result := &Result{}
+// Create inserts value. An empty `uniqueSlug` field is generated from its source field,
+// retrying with a suffix while the slug is already taken. The counter caches of the
+// referenced parents are updated by a separate statement after the INSERT, with no
+// atomicity outside a Tx: a counter cache error can be returned with the row stored
+// (see Counter Caches). Use Tx.Create when the counters must never drift.
+func (db *DB) Create(ctx context.Context, value any, opts ...CreateOption) *Result {
+	if options := applyCreateOptions(opts); options.associations {
+		var result *Result
+		err := db.Transaction(ctx, func(ctx context.Context, tx *Tx) error {
+			result = createWithAssociations(ctx, tx, value, opts, options.associationNames)
+			return result.Error
+		})
+		if result == nil || (result.Error == nil && err != nil) {
+			result = &Result{Error: err} // Begin or Commit failed
+		}
+		return result
+	}
+	defer invalidateEntity(ctx, db.cache, db.parser, value)
+	if model, err := db.GetModel(value); err == nil && model.SlugField != nil {
+		return createWithSlug(value, model, func() *Result { return db.create(ctx, value, opts...) })
+	}
+	return db.create(ctx, value, opts...)
+}
+
+func (db *DB) create(ctx context.Context, value any, opts ...CreateOption) (result *Result) {
+	var sqlQuery string
+	defer wrapOpError(&result, db.parser, "Create", value, &sqlQuery)
+	defer recoverResult(&result, "Create", value)
+	result = &Result{}
+	options := applyCreateOptions(opts)
 
 	// 1. Validate input & Get Reflect Value/Type
 	reflectValue := reflect.ValueOf(value)
@@ -173,27 +314,36 @@ func (db *DB) Create(ctx context.Context, value any) *Result {
 	}
 
 	// --- Call BeforeCreate Hook ---
-	if model.HasBeforeCreate {
-		hookMethod := reflectValue.MethodByName("BeforeCreate")            // Get method on pointer value
-		if err := callHook(ctx, db, hookMethod, structValue); err != nil { // Pass DB as ContextDB
-			result.Error = fmt.Errorf("BeforeCreate hook failed: %w", err)
-			return result
-		}
+	if err := runCallbacks(ctx, db.callbacks, &HookContext{Event: EventBeforeCreate, Model: model, Value: value, DB: db}); err != nil {
+		result.Error = fmt.Errorf("BeforeCreate hook failed: %w", err)
+		return result
 	}
 	// --- End Hook Call ---
 
+	if err := checkCreatePolicies(ctx, db.policies, model, value); err != nil {
+		result.Error = err
+		return result
+	}
+	if err := options.validateFields(model); err != nil {
+		result.Error = err
+		return result
+	}
+	if err := applyCreateDefaults(ctx, nowFunc(ctx, db.now), idGenerator(ctx, db.ids), model, structValue, options.updatesOnConflict()); err != nil {
+		result.Error = err
+		return result
+	}
+
 	// 3. Build INSERT statement parts
 	var columns []string
-	var placeholders []string
 	var args []any
-	tableName := model.TableName
+	var sensitive []any // Values of sensitive fields, redacted in the log
 	dialect := db.source.Dialect()
 
 	// Iterate through parsed fields to build the INSERT
 	for _, field := range model.Fields {
-		if field.IsIgnored {
+		if field.IsIgnored || !options.includes(field) {
 			continue
-		} // Skip ignored fields
+		} // Skip ignored fields and fields excluded by Select/Omit
 
 		fieldValue := structValue.FieldByName(field.GoName)
 		if !fieldValue.IsValid() {
@@ -207,7 +357,7 @@ func (db *DB) Create(ctx context.Context, value any) *Result {
 			continue
 		}
 		// b) Skip conventional timestamp fields if zero/nil to allow DB defaults
-		if field.GoName == "CreatedAt" || field.GoName == "UpdatedAt" {
+		if field.IsAutoCreateTime() || field.IsAutoUpdateTime() {
 			isZeroTime := false
 			if fieldValue.Kind() == reflect.Struct && fieldValue.Type() == reflect.TypeOf(time.Time{}) {
 				isZeroTime = fieldValue.Interface().(time.Time).IsZero()
@@ -227,10 +377,17 @@ func (db *DB) Create(ctx context.Context, value any) *Result {
 		}
 		// --- End skipping columns ---
 
-		// Add column, placeholder, and the actual value from the struct
-		columns = append(columns, dialect.Quote(field.DBName))
-		placeholders = append(placeholders, dialect.BindVar(len(args)+1))
-		args = append(args, fieldValue.Interface())
+		// Add column and the actual value from the struct
+		value, err := interceptField(ctx, model, field, fieldValue)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		columns = append(columns, field.DBName)
+		args = append(args, value)
+		if field.Sensitive {
+			sensitive = append(sensitive, value)
+		}
 	}
 
 	if len(columns) == 0 {
@@ -238,16 +395,16 @@ func (db *DB) Create(ctx context.Context, value any) *Result {
 		return result
 	}
 
-	// Construct the SQL query string
-	sqlQuery := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
-		dialect.Quote(tableName),
-		strings.Join(columns, ", "),
-		strings.Join(placeholders, ", "),
-	)
+	// Construct the SQL query string (honoring conflict options)
+	sqlQuery, args, err = buildInsertSQL(dialect, model, columns, args, options)
+	if err != nil {
+		result.Error = err
+		return result
+	}
 
 	// 4. Execute SQL
-	fmt.Printf("Executing SQL: %s | Args: %v\n", sqlQuery, args) // Debug log
-	sqlResult, err := db.source.Exec(ctx, sqlQuery, args...)
+	logSQL("Executing SQL: %s | Args: %v\n", sqlQuery, redactArgs(model, args, sensitive)) // Debug log
+	sqlResult, err := db.conn(ctx).Exec(ctx, sqlQuery, args...)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to execute insert for %s: %w", structType.Name(), err)
 		return result
@@ -259,12 +416,16 @@ func (db *DB) Create(ctx context.Context, value any) *Result {
 	} else {
 		fmt.Printf("Warning: could not get RowsAffected after insert: %v\n", errAff)
 	}
+	if options.onConflictDoNothing && result.RowsAffected == 0 {
+		fmt.Printf("Insert skipped for %s: conflicting row already exists.\n", structType.Name())
+		return result
+	}
 
 	// Handle setting AutoIncrement ID back onto the input struct
 	var pkField *schema.Field = nil
 	if len(model.PrimaryKeys) == 1 && model.PrimaryKeys[0].AutoIncrement {
-		pkField = model.PrimaryKeys[0] // Get the single auto-inc PK field
-		if lastID, errID := sqlResult.LastInsertId(); errID == nil {
+		pkField = model.PrimaryKeys[0]                                                                             // Get the single auto-inc PK field
+		if lastID, errID := sqlResult.LastInsertId(); errID == nil && (lastID != 0 || !options.onConflictUpdate) { // An upsert that updated reports no ID
 			result.LastInsertID = lastID
 			pkValueField := structValue.FieldByName(pkField.GoName)
 			if pkValueField.IsValid() && pkValueField.CanSet() {
@@ -281,7 +442,7 @@ func (db *DB) Create(ctx context.Context, value any) *Result {
 			} else {
 				fmt.Printf("Warning: Cannot set auto-increment ID back on PK field %s (invalid or not settable)\n", pkField.GoName)
 			}
-		} else {
+		} else if errID != nil {
 			fmt.Printf("Warning: could not get LastInsertId after insert (driver/DB may not support it): %v\n", errID)
 		}
 	}
@@ -304,7 +465,7 @@ func (db *DB) Create(ctx context.Context, value any) *Result {
 			canRefetch = false
 			break
 		}
-		pkWhereClauses = append(pkWhereClauses, fmt.Sprintf("%s = %s", dialect.Quote(pk.DBName), dialect.BindVar(i+1)))
+		pkWhereClauses = append(pkWhereClauses, assignment(dialect, pk.DBName, i+1))
 		pkValueArgs = append(pkValueArgs, pkValue.Interface())
 	}
 
@@ -334,13 +495,13 @@ func (db *DB) Create(ctx context.Context, value any) *Result {
 		if len(selectCols) > 0 {
 			selectQuery := fmt.Sprintf("SELECT %s FROM %s WHERE %s",
 				strings.Join(selectCols, ", "),
-				dialect.Quote(tableName),
+				quoteTable(dialect, model),
 				strings.Join(pkWhereClauses, " AND "),
 			)
 
 			// Execute SELECT query using QueryRow
-			fmt.Printf("Re-fetching record with query: %s | Args: %v\n", selectQuery, pkValueArgs)
-			rowScanner := db.source.QueryRow(ctx, selectQuery, pkValueArgs...)
+			logSQL("Re-fetching record with query: %s | Args: %v\n", selectQuery, redactArgs(model, pkValueArgs, value))
+			rowScanner := db.conn(ctx).QueryRow(ctx, selectQuery, pkValueArgs...)
 
 			// Scan the result directly back into the fields of the original struct
 			if scanErr := rowScanner.Scan(scanDest...); scanErr != nil {
@@ -358,12 +519,21 @@ func (db *DB) Create(ctx context.Context, value any) *Result {
 		fmt.Println("Warning: Cannot re-fetch record after create without primary key information.")
 	}
 
+	// --- Counter caches of referenced parents ---
+	// The INSERT is already committed: a failed counter UPDATE leaves the counter behind
+	// (RecountAll fixes it). Create through a Tx to make both atomic.
+	keys, err := counterKeys(ctx, db.conn(ctx), dialect, model, structValue, nil, nil)
+	if err == nil {
+		err = options.saved.applyCounterCaches(ctx, db.conn(ctx), dialect, keys, result.RowsAffected)
+	}
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
 	// --- Call AfterCreate Hook ---
-	if model.HasAfterCreate {
-		hookMethod := reflectValue.MethodByName("AfterCreate")
-		if err := callHook(ctx, db, hookMethod, structValue); err != nil {
-			fmt.Printf("Warning: AfterCreate hook failed: %v\n", err)
-		}
+	if err := runCallbacks(ctx, db.callbacks, &HookContext{Event: EventAfterCreate, Model: model, Value: value, DB: db}); err != nil {
+		fmt.Printf("Warning: AfterCreate hook failed: %v\n", err)
 	}
 	// --- End Hook Call ---
 
@@ -374,8 +544,11 @@ func (db *DB) Create(ctx context.Context, value any) *Result {
 // 'dest' must be a pointer to a struct.
 // 'id' is the primary key value to search for. Assumes a single primary key column for now.
 // Returns a Result object. Result.Error will be sql.ErrNoRows if the record is not found.
-func (db *DB) FindByID(ctx context.Context, dest any, id any) *Result {
-	result := &Result{}
+func (db *DB) FindByID(ctx context.Context, dest any, id any) (result *Result) {
+	var sqlQuery string
+	defer wrapOpError(&result, db.parser, "FindByID", dest, &sqlQuery)
+	defer recoverResult(&result, "FindByID", dest)
+	result = &Result{}
 
 	// 1. Validate dest input
 	destValue := reflect.ValueOf(dest)
@@ -404,36 +577,49 @@ func (db *DB) FindByID(ctx context.Context, dest any, id any) *Result {
 	}
 	pkField := model.PrimaryKeys[0]
 
-	// 4. Build SELECT SQL
 	dialect := db.source.Dialect()
-	selectCols := []string{}
-	scanFields := []*schema.Field{} // Keep track of fields to scan into
+	tableNameQuoted := quoteTable(dialect, model)
 
-	for _, field := range model.Fields {
-		if !field.IsIgnored {
-			selectCols = append(selectCols, dialect.Quote(field.DBName))
-			scanFields = append(scanFields, field)
-		}
+	// --- Check the data-access policies ---
+	policyCtx, mask := withColumnMask(ctx, db.policies)
+	policyWhere, policyArgs, err := policyClause(policyCtx, db.policies, dialect, "FindByID", model, []any{id})
+	if err != nil {
+		result.Error = err
+		return result
 	}
+	masked := mask.maskedFields(model)
+
+	// 4. Build SELECT SQL
+	selectList, scanFields := maskedSelectColumns(dialect, model, masked)
 
-	if len(selectCols) == 0 {
+	if len(scanFields) == 0 {
 		result.Error = fmt.Errorf("no selectable columns found for model %s", model.Name)
 		return result
 	}
 
-	tableNameQuoted := dialect.Quote(model.TableName)
-	pkColNameQuoted := dialect.Quote(pkField.DBName)
 	// Use LIMIT 1 for safety, although QueryRow should handle it
-	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = %s LIMIT 1",
-		strings.Join(selectCols, ", "),
-		tableNameQuoted,
-		pkColNameQuoted,
-		dialect.BindVar(1), // Placeholder for the ID arg
-	)
+	// A row not matching the policies is not found
+	sqlQuery = selectByPKSQL(dialect, model, selectList, pkField, softDeleteField(ctx, model), policyWhere)
+	args := append([]any{id}, policyArgs...)
+	if policyWhere != "" {
+		sqlQuery = renumberBindVars(dialect, sqlQuery)
+	}
+
+	// --- Serve from the entity cache (see CachedEntity), which holds whole rows ---
+	cacheable := len(masked) == 0 && policyWhere == "" && !isUnscoped(ctx)
+	if cacheable && loadCachedEntity(ctx, db.cache, db.payloadCodec(), model, scanFields, id, destElem) {
+		result.RowsAffected = 1
+		fmt.Printf("Found record for ID %v of %s in the entity cache\n", id, destType.Name())
+		callAfterScan(model, destValue)
+		if err := runCallbacks(ctx, db.callbacks, &HookContext{Event: EventAfterFind, Model: model, Value: dest, DB: db}); err != nil {
+			fmt.Printf("Warning: AfterFind hook failed for ID %v: %v\n", id, err)
+		}
+		return result
+	}
 
 	// 5. Execute Query using QueryRow
-	fmt.Printf("Executing SQL: %s | Args: [%v]\n", query, id) // Debug log
-	rowScanner := db.source.QueryRow(ctx, query, id)
+	logSQL("Executing SQL: %s | Args: %v\n", sqlQuery, redactArgs(model, args)) // Debug log
+	rowScanner := db.reader(ctx).QueryRow(ctx, sqlQuery, args...)
 
 	// 6. Prepare Scan Destinations
 	scanDest := make([]any, len(scanFields))
@@ -468,13 +654,17 @@ func (db *DB) FindByID(ctx context.Context, dest any, id any) *Result {
 	// If scan succeeded, error is nil
 	result.RowsAffected = 1 // QueryRow affects 1 row if found
 	fmt.Printf("Successfully found and scanned record for ID %v into %s\n", id, destType.Name())
+	if cacheable {
+		storeCachedEntity(ctx, db.cache, db.payloadCodec(), model, scanFields, id, destElem)
+	}
+	zeroMaskedFields(destElem, masked)
+
+	// --- Populate computed fields ---
+	callAfterScan(model, destValue)
 
 	// --- Call AfterFind Hook ---
-	if model.HasAfterFind {
-		hookMethod := destValue.MethodByName("AfterFind")
-		if err := callHook(ctx, db, hookMethod, destElem); err != nil {
-			fmt.Printf("Warning: AfterFind hook failed for ID %v: %v\n", id, err)
-		}
+	if err := runCallbacks(ctx, db.callbacks, &HookContext{Event: EventAfterFind, Model: model, Value: dest, DB: db}); err != nil {
+		fmt.Printf("Warning: AfterFind hook failed for ID %v: %v\n", id, err)
 	}
 	// --- End Hook Call ---
 	return result
@@ -483,9 +673,15 @@ func (db *DB) FindByID(ctx context.Context, dest any, id any) *Result {
 // Delete deletes a record based on the primary key found in the provided value.
 // 'value' must be a pointer to a struct instance containing the primary key value(s).
 // Returns a Result object; check Result.Error for issues and Result.RowsAffected
-// (RowsAffected == 0 indicates the record was not found or not deleted).
-func (db *DB) Delete(ctx context.Context, value any) *Result {
-	result := &Result{}
+// (RowsAffected == 0 indicates the record was not found or not deleted). As with Create,
+// a counter cache error can be returned after the row is deleted; use Tx.Delete to make
+// both atomic.
+func (db *DB) Delete(ctx context.Context, value any) (result *Result) {
+	var sqlQuery string
+	defer wrapOpError(&result, db.parser, "Delete", value, &sqlQuery)
+	defer recoverResult(&result, "Delete", value)
+	result = &Result{}
+	defer invalidateEntity(ctx, db.cache, db.parser, value)
 
 	// 1. Validate input & Get Reflect Value/Type
 	reflectValue := reflect.ValueOf(value)
@@ -508,12 +704,10 @@ func (db *DB) Delete(ctx context.Context, value any) *Result {
 	}
 
 	// --- Call BeforeDelete Hook ---
-	if model.HasBeforeDelete {
-		hookMethod := reflectValue.MethodByName("BeforeDelete")
-		if err := callHook(ctx, db, hookMethod, structValue); err != nil {
-			result.Error = fmt.Errorf("BeforeDelete hook failed: %w", err)
-			return result
-		}
+	softDelete := softDeleteField(ctx, model)
+	if err := runCallbacks(ctx, db.callbacks, &HookContext{Event: EventBeforeDelete, Model: model, Value: value, DB: db, SoftDelete: softDelete != nil}); err != nil {
+		result.Error = fmt.Errorf("BeforeDelete hook failed: %w", err)
+		return result
 	}
 	// --- End Hook Call ---
 
@@ -539,19 +733,34 @@ func (db *DB) Delete(ctx context.Context, value any) *Result {
 			return result
 		}
 		pkArgs = append(pkArgs, pkValueField.Interface())
-		pkWhereClauses = append(pkWhereClauses, fmt.Sprintf("%s = %s", dialect.Quote(pkField.DBName), dialect.BindVar(i+1)))
+		pkWhereClauses = append(pkWhereClauses, assignment(dialect, pkField.DBName, i+1))
 	}
 
-	// 4. Build DELETE SQL
-	tableNameQuoted := dialect.Quote(model.TableName)
-	sqlQuery := fmt.Sprintf("DELETE FROM %s WHERE %s",
-		tableNameQuoted,
-		strings.Join(pkWhereClauses, " AND "),
-	)
+	// --- Check the data-access policies ---
+	policyWhere, policyArgs, err := policyClause(ctx, db.policies, dialect, "Delete", model, pkArgs)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	// Foreign keys of counter caches, read before the row is gone
+	keys, err := counterKeys(ctx, db.conn(ctx), dialect, model, structValue, pkWhereClauses, pkArgs)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	// 4. Build DELETE SQL (an UPDATE of the deletion timestamp for soft deletes)
+	if softDelete != nil {
+		sqlQuery, pkArgs = softDeleteSQL(dialect, model, softDelete, clock(ctx, db.now), structValue, pkArgs)
+	} else {
+		sqlQuery = deleteSQL(dialect, model, pkWhereClauses)
+	}
+	sqlQuery, pkArgs = withPolicyClause(dialect, sqlQuery, pkArgs, policyWhere, policyArgs)
 
 	// 5. Execute SQL
-	fmt.Printf("Executing SQL: %s | Args: %v\n", sqlQuery, pkArgs) // Debug log
-	sqlResult, err := db.source.Exec(ctx, sqlQuery, pkArgs...)
+	logSQL("Executing SQL: %s | Args: %v\n", sqlQuery, redactArgs(model, pkArgs, value)) // Debug log
+	sqlResult, err := db.conn(ctx).Exec(ctx, sqlQuery, pkArgs...)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to execute delete for %s: %w", model.Name, err)
 		return result
@@ -573,10 +782,16 @@ func (db *DB) Delete(ctx context.Context, value any) *Result {
 		fmt.Printf("Successfully deleted %d record(s) for %s.\n", affected, model.Name)
 	}
 
+	if affected > 0 {
+		if err := applyCounterCaches(ctx, db.conn(ctx), dialect, keys, -1); err != nil {
+			result.Error = err
+			return result
+		}
+	}
+
 	// --- Call AfterDelete Hook ---
-	if model.HasAfterDelete && affected > 0 {
-		hookMethod := reflectValue.MethodByName("AfterDelete")
-		if err := callHook(ctx, db, hookMethod, structValue); err != nil {
+	if affected > 0 {
+		if err := runCallbacks(ctx, db.callbacks, &HookContext{Event: EventAfterDelete, Model: model, Value: value, DB: db, SoftDelete: softDelete != nil}); err != nil {
 			fmt.Printf("Warning: AfterDelete hook failed: %v\n", err)
 		}
 	}
@@ -592,11 +807,17 @@ func (db *DB) Delete(ctx context.Context, value any) *Result {
 // 'conds' can be:
 //   - A pointer to a struct (query-by-example, uses non-zero fields).
 //   - A map[string]any (keys are DB column names).
+//   - An Expr (typed condition, e.g. typegorm.Or(typegorm.Eq("name", "Ana"), typegorm.Gt("age", 30))).
 //   - TODO: A string followed by args (raw WHERE clause).
 //
+// FindOptions (Order, Unscoped, Distinct, Group, Having, Columns) may be mixed with the
+// condition; Joins is rejected.
 // Returns a Result object. Result.Error will be sql.ErrNoRows if no record is found.
-func (db *DB) FindFirst(ctx context.Context, dest any, conds ...any) *Result {
-	result := &Result{}
+func (db *DB) FindFirst(ctx context.Context, dest any, conds ...any) (result *Result) {
+	var sqlQuery string
+	defer wrapOpError(&result, db.parser, "FindFirst", dest, &sqlQuery)
+	defer recoverResult(&result, "FindFirst", dest)
+	result = &Result{}
 
 	// 1. Validate dest input
 	destValue := reflect.ValueOf(dest)
@@ -623,12 +844,36 @@ func (db *DB) FindFirst(ctx context.Context, dest any, conds ...any) *Result {
 	whereClauses := []string{}
 	whereArgs := []any{}
 
-	if len(conds) > 0 {
-		// Simple condition handling for now: assumes first arg is struct ptr or map
-		queryCond := conds[0]
+	condition, options, err := processFindArgs(conds...)
+	if err == nil && len(options.joins) > 0 {
+		err = errors.New("FindFirst does not support Joins: use Find with Limit(1)")
+	}
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	policyCtx, mask := withColumnMask(ctx, db.policies)
+	userCondition := condition
+	if condition, err = rewriteCondition(policyCtx, db.rewriter, db.policies, "FindFirst", model, condition); err != nil {
+		result.Error = err
+		return result
+	}
+	if err = checkMaskedUse("FindFirst", model, mask.maskedFields(model), userCondition, options); err != nil {
+		result.Error = err
+		return result
+	}
+	if condition != nil {
+		// Simple condition handling for now: condition is struct ptr or map
+		queryCond := condition
 		queryValue := reflect.ValueOf(queryCond)
 
-		if queryValue.Kind() == reflect.Pointer && queryValue.Elem().Kind() == reflect.Struct {
+		if _, ok := queryCond.(Expr); ok {
+			// Typed condition (see Expr)
+			if whereClauses, whereArgs, err = buildWhereClause(dialect, model, queryCond); err != nil {
+				result.Error = err
+				return result
+			}
+		} else if queryValue.Kind() == reflect.Pointer && queryValue.Elem().Kind() == reflect.Struct {
 			// Query-by-example (struct pointer)
 			queryStruct := queryValue.Elem()
 			for i := 0; i < queryStruct.NumField(); i++ {
@@ -680,40 +925,63 @@ func (db *DB) FindFirst(ctx context.Context, dest any, conds ...any) *Result {
 			result.Error = fmt.Errorf("unsupported condition type: %T. Expecting struct pointer or map[string]any", queryCond)
 			return result
 		}
-	} // End if len(conds) > 0
+	} // End if condition != nil
+
+	options.unscoped = options.unscoped || isUnscoped(ctx) // Session.Unscoped / WithUnscoped
+	whereClauses, whereArgs, err = applyDefaultScope(dialect, model, whereClauses, whereArgs, &options)
+	if err != nil {
+		result.Error = err
+		return result
+	}
 
 	// 4. Build SELECT SQL
-	selectCols := []string{}
-	scanFields := []*schema.Field{}
-	for _, field := range model.Fields {
-		if !field.IsIgnored {
-			selectCols = append(selectCols, dialect.Quote(field.DBName))
-			scanFields = append(scanFields, field)
+	masked := mask.maskedFields(model)
+	selectList, scanFields := maskedSelectColumns(dialect, model, masked)
+	if len(options.columns) > 0 {
+		if selectList, scanFields, err = options.selectedColumns(dialect, model); err != nil {
+			result.Error = err
+			return result
 		}
 	}
-	if len(selectCols) == 0 {
+	if len(scanFields) == 0 {
 		result.Error = fmt.Errorf("no selectable columns found for model %s", model.Name)
 		return result
 	}
 
-	tableNameQuoted := dialect.Quote(model.TableName)
-	queryBuilder := strings.Builder{}
-	queryBuilder.WriteString("SELECT ")
-	queryBuilder.WriteString(strings.Join(selectCols, ", "))
+	tableNameQuoted, whereClauses, whereArgs, err := asOfSource(dialect, model, options, whereClauses, whereArgs)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	groupBy, havingArgs, err := options.groupClause(dialect, model)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	queryBuilder := getStmtBuffer(statementSize(model))
+	defer putStmtBuffer(queryBuilder)
+	queryBuilder.WriteString(options.selectKeyword())
+	queryBuilder.WriteString(selectList)
 	queryBuilder.WriteString(" FROM ")
 	queryBuilder.WriteString(tableNameQuoted)
 	if len(whereClauses) > 0 {
 		queryBuilder.WriteString(" WHERE ")
-		queryBuilder.WriteString(strings.Join(whereClauses, " AND "))
+		queryBuilder.WriteJoined(whereClauses, " AND ")
+	}
+	queryBuilder.WriteString(groupBy)
+	if options.orderBy != "" {
+		queryBuilder.WriteString(" ORDER BY ")
+		queryBuilder.WriteString(quoteOrderBy(dialect, model, options.orderBy))
 	}
 	// LIMIT 1 for FindFirst
 	queryBuilder.WriteString(" LIMIT 1") // Add LIMIT clause
 
-	sqlQuery := queryBuilder.String()
+	sqlQuery = renumberBindVars(dialect, queryBuilder.String())
+	whereArgs = append(whereArgs, havingArgs...)
 
 	// 5. Execute Query using QueryRow
-	fmt.Printf("Executing SQL: %s | Args: %v\n", sqlQuery, whereArgs) // Debug log
-	rowScanner := db.source.QueryRow(ctx, sqlQuery, whereArgs...)
+	logSQL("Executing SQL: %s | Args: %v\n", sqlQuery, redactArgs(model, whereArgs, conds...)) // Debug log
+	rowScanner := db.reader(ctx).QueryRow(ctx, sqlQuery, whereArgs...)
 
 	// 6. Prepare Scan Destinations
 	scanDest := make([]any, len(scanFields))
@@ -745,14 +1013,20 @@ func (db *DB) FindFirst(ctx context.Context, dest any, conds ...any) *Result {
 	result.RowsAffected = 1 // Found and scanned one row
 	fmt.Printf("Successfully found and scanned first record into %s\n", destType.Name())
 
+	// --- Populate computed fields ---
+	zeroMaskedFields(destElem, masked)
+	callAfterScan(model, destValue)
+
 	// --- Call AfterFind Hook ---
-	if model.HasAfterFind {
-		hookMethod := destValue.MethodByName("AfterFind")
-		if err := callHook(ctx, db, hookMethod, destElem); err != nil {
-			fmt.Printf("Warning: AfterFind hook failed for FindFirst: %v\n", err)
-		}
+	if err := runCallbacks(ctx, db.callbacks, &HookContext{Event: EventAfterFind, Model: model, Value: dest, DB: db}); err != nil {
+		fmt.Printf("Warning: AfterFind hook failed for FindFirst: %v\n", err)
 	}
 	// --- End Hook Call ---
+
+	// --- Load the relations requested with Preload ---
+	if err := preload(ctx, db.relationSource(), db.parser, db.relations, destValue, options.preload, options.preloadConds); err != nil {
+		result.Error = err
+	}
 	return result
 }
 
@@ -764,8 +1038,12 @@ func (db *DB) FindFirst(ctx context.Context, dest any, conds ...any) *Result {
 // It only updates columns provided in the 'data' map.
 // Returns a Result object. Check Result.Error and Result.RowsAffected.
 // RowsAffected == 0 typically means the record was not found with the given PK.
-func (db *DB) Updates(ctx context.Context, modelWithValue any, data map[string]any) *Result {
-	result := &Result{}
+func (db *DB) Updates(ctx context.Context, modelWithValue any, data map[string]any) (result *Result) {
+	var sqlQuery string
+	defer wrapOpError(&result, db.parser, "Updates", modelWithValue, &sqlQuery)
+	defer recoverResult(&result, "Updates", modelWithValue)
+	result = &Result{}
+	defer invalidateEntity(ctx, db.cache, db.parser, modelWithValue)
 
 	// 1. Validate input model & Get Reflect Value/Type
 	reflectValue := reflect.ValueOf(modelWithValue)
@@ -788,14 +1066,12 @@ func (db *DB) Updates(ctx context.Context, modelWithValue any, data map[string]a
 	}
 
 	// --- Call BeforeUpdate Hook ---
-	if model.HasBeforeUpdate {
-		hookMethod := reflectValue.MethodByName("BeforeUpdate")
-		if err := callHookWithData(ctx, db, hookMethod, structValue, data); err != nil {
-			result.Error = fmt.Errorf("BeforeUpdate hook failed: %w", err)
-			return result
-		}
+	if err := runCallbacks(ctx, db.callbacks, &HookContext{Event: EventBeforeUpdate, Model: model, Value: modelWithValue, DB: db, Data: data}); err != nil {
+		result.Error = fmt.Errorf("BeforeUpdate hook failed: %w", err)
+		return result
 	}
 	// --- End Hook Call ---
+	data = touchUpdatedAt(clock(ctx, db.now), model, structValue, data)
 
 	// 3. Extract Primary Key values for WHERE clause
 	if len(model.PrimaryKeys) == 0 {
@@ -816,12 +1092,28 @@ func (db *DB) Updates(ctx context.Context, modelWithValue any, data map[string]a
 			return result
 		}
 		pkArgs = append(pkArgs, pkValueField.Interface())
-		pkWhereClauses = append(pkWhereClauses, fmt.Sprintf("%s = %s", dialect.Quote(pkField.DBName), dialect.BindVar(i+1))) // Placeholders start at 1 for WHERE
+		pkWhereClauses = append(pkWhereClauses, assignment(dialect, pkField.DBName, i+1)) // Placeholders start at 1 for WHERE
+	}
+
+	// --- Check the data-access policies ---
+	policyWhere, policyArgs, err := policyClause(ctx, db.policies, dialect, "Update", model, pkArgs)
+	if err != nil {
+		result.Error = err
+		return result
 	}
 
+	// --- Validate state machine transitions ---
+	transition, err := checkTransition(ctx, db.conn(ctx), db.machines, dialect, model, data, pkWhereClauses, pkArgs)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	pkWhereClauses, pkArgs = transition.guard(dialect, pkWhereClauses, pkArgs)
+
 	// 4. Build SET clause and collect arguments
 	setClauses := []string{}
 	setArgs := []any{}
+	var sensitive []any
 	placeholderOffset := len(pkArgs) // Placeholders for SET start after PK args
 
 	for dbColName, value := range data {
@@ -837,8 +1129,16 @@ func (db *DB) Updates(ctx context.Context, modelWithValue any, data map[string]a
 		}
 		// TODO: Add check for read-only fields (like CreatedAt) if needed
 
-		setClauses = append(setClauses, fmt.Sprintf("%s = %s", dialect.Quote(dbColName), dialect.BindVar(placeholderOffset+len(setArgs)+1)))
+		value, err := interceptValue(ctx, model, dbColName, value)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		setClauses = append(setClauses, assignment(dialect, dbColName, placeholderOffset+len(setArgs)+1))
 		setArgs = append(setArgs, value)
+		if field.Sensitive {
+			sensitive = append(sensitive, value)
+		}
 	}
 
 	// Check if there's anything to update
@@ -849,19 +1149,15 @@ func (db *DB) Updates(ctx context.Context, modelWithValue any, data map[string]a
 	}
 
 	// 5. Build Full UPDATE SQL
-	tableNameQuoted := dialect.Quote(model.TableName)
-	sqlQuery := fmt.Sprintf("UPDATE %s SET %s WHERE %s",
-		tableNameQuoted,
-		strings.Join(setClauses, ", "),
-		strings.Join(pkWhereClauses, " AND "),
-	)
+	sqlQuery = updateSQL(dialect, model, setClauses, pkWhereClauses)
 
 	// Combine SET arguments and WHERE arguments
 	allArgs := append(setArgs, pkArgs...)
+	sqlQuery, allArgs = withPolicyClause(dialect, sqlQuery, allArgs, policyWhere, policyArgs)
 
 	// 6. Execute SQL
-	fmt.Printf("Executing SQL: %s | Args: %v\n", sqlQuery, allArgs) // Debug log
-	sqlResult, err := db.source.Exec(ctx, sqlQuery, allArgs...)
+	logSQL("Executing SQL: %s | Args: %v\n", sqlQuery, redactArgs(model, allArgs, sensitive)) // Debug log
+	sqlResult, err := db.conn(ctx).Exec(ctx, sqlQuery, allArgs...)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to execute update for %s: %w", model.Name, err)
 		return result
@@ -882,10 +1178,16 @@ func (db *DB) Updates(ctx context.Context, modelWithValue any, data map[string]a
 		// Similar logic to the re-fetch in Create.
 	}
 
+	if history := transition.history(model, pkArgs, clock(ctx, db.now)); history != nil && affected > 0 {
+		if err := db.create(ctx, history).Error; err != nil {
+			result.Error = fmt.Errorf("state machine: failed to record transition: %w", err)
+			return result
+		}
+	}
+
 	// --- Call AfterUpdate Hook ---
-	if model.HasAfterUpdate && affected > 0 {
-		hookMethod := reflectValue.MethodByName("AfterUpdate")
-		if err := callHook(ctx, db, hookMethod, structValue); err != nil {
+	if affected > 0 {
+		if err := runCallbacks(ctx, db.callbacks, &HookContext{Event: EventAfterUpdate, Model: model, Value: modelWithValue, DB: db}); err != nil {
 			fmt.Printf("Warning: AfterUpdate hook failed: %v\n", err)
 		}
 	}
@@ -897,10 +1199,13 @@ func (db *DB) Updates(ctx context.Context, modelWithValue any, data map[string]a
 
 // Find retrieves a slice of records matching the given conditions and scans them into dest.
 // 'dest' must be a pointer to a slice of structs (e.g., &[]User{}).
-// 'conds' are the query conditions (struct pointer or map[string]any).
+// 'conds' are the query conditions (struct pointer, map[string]any or Expr).
 // Returns a Result object. Result.Error contains database/scan errors, but NOT sql.ErrNoRows.
-func (db *DB) Find(ctx context.Context, dest any, condsAndOpts ...any) *Result {
-	result := &Result{}
+func (db *DB) Find(ctx context.Context, dest any, condsAndOpts ...any) (result *Result) {
+	var sqlQuery string
+	defer wrapOpError(&result, db.parser, "Find", dest, &sqlQuery)
+	defer recoverResult(&result, "Find", dest)
+	result = &Result{}
 
 	// 1. Validate dest input
 	destValue := reflect.ValueOf(dest)
@@ -937,48 +1242,86 @@ func (db *DB) Find(ctx context.Context, dest any, condsAndOpts ...any) *Result {
 		result.Error = err
 		return result
 	}
+	policyCtx, mask := withColumnMask(ctx, db.policies)
+	userCondition := condition
+	if condition, err = rewriteCondition(policyCtx, db.rewriter, db.policies, "Find", model, condition); err != nil {
+		result.Error = err
+		return result
+	}
+	if err = checkMaskedUse("Find", model, mask.maskedFields(model), userCondition, options); err != nil {
+		result.Error = err
+		return result
+	}
 
 	// 3. Build WHERE clause and arguments
 	dialect := db.source.Dialect()
-	whereClauses, whereArgs, err := buildWhereClause(dialect, model, condition) // Pass only the condition
+	joins, err := planJoins(&queryBuild{dialect: dialect, parser: db.parser, ctx: ctx, rewriter: db.rewriter, policies: db.policies}, model, options)
+	if err == nil {
+		err = joins.check("Find", model, userCondition, options)
+	}
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	whereClauses, whereArgs, err := buildWhereClause(joins.columns(dialect), model, condition) // Pass only the condition
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	options.unscoped = options.unscoped || isUnscoped(ctx) // Session.Unscoped / WithUnscoped
+	whereClauses, whereArgs, err = applyDefaultScope(joins.columns(dialect), model, whereClauses, whereArgs, &options)
 	if err != nil {
 		result.Error = err
 		return result
 	}
 
 	// 4. Build SELECT SQL (including ORDER BY, LIMIT, OFFSET)
-	selectCols := []string{}
-	scanFields := []*schema.Field{}
-	for _, field := range model.Fields {
-		if !field.IsIgnored {
-			selectCols = append(selectCols, dialect.Quote(field.DBName))
-			scanFields = append(scanFields, field)
+	masked := mask.maskedFields(model)
+	selectList, scanFields := maskedSelectColumns(dialect, model, masked)
+	selectList = joins.selectList(dialect, selectList, scanFields)
+	if len(options.columns) > 0 {
+		if selectList, scanFields, err = options.selectedColumns(joins.columns(dialect), model); err != nil {
+			result.Error = err
+			return result
 		}
+		selectList = joins.withJoined(selectList)
 	}
-	if len(selectCols) == 0 {
+	if len(scanFields) == 0 {
 		result.Error = fmt.Errorf("no selectable columns found for model %s", model.Name)
 		return result
 	}
 
-	tableNameQuoted := dialect.Quote(model.TableName)
-	queryBuilder := strings.Builder{}
-	queryBuilder.WriteString("SELECT ")
-	queryBuilder.WriteString(strings.Join(selectCols, ", "))
+	tableNameQuoted, whereClauses, whereArgs, err := asOfSource(dialect, model, options, whereClauses, whereArgs)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	groupBy, havingArgs, err := options.groupClause(joins.columns(dialect), model)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	queryBuilder := getStmtBuffer(statementSize(model))
+	defer putStmtBuffer(queryBuilder)
+	queryBuilder.WriteString(options.selectKeyword())
+	queryBuilder.WriteString(selectList)
 	queryBuilder.WriteString(" FROM ")
 	queryBuilder.WriteString(tableNameQuoted)
+	queryBuilder.WriteString(joins.from())
 	if len(whereClauses) > 0 {
 		queryBuilder.WriteString(" WHERE ")
-		queryBuilder.WriteString(strings.Join(whereClauses, " AND "))
+		queryBuilder.WriteJoined(whereClauses, " AND ")
 	}
+	queryBuilder.WriteString(groupBy)
 
 	// *** NEW: Append optional clauses ***
 	if options.orderBy != "" {
-		// WARNING: Direct use of orderBy string. Ensure it's safe.
 		queryBuilder.WriteString(" ORDER BY ")
-		queryBuilder.WriteString(options.orderBy)
+		queryBuilder.WriteString(quoteOrderBy(joins.columns(dialect), model, options.orderBy))
 	}
-	effectiveLimit := options.limit
-	if options.offset > 0 && options.limit <= 0 {
+	maxRows := resolveMaxRows(options, db.config.Query.MaxRows)
+	effectiveLimit := guardedLimit(options.limit, maxRows)
+	if options.offset > 0 && effectiveLimit <= 0 {
 		// Set a large default limit if offset is used without limit
 		// Use math.MaxInt64 which is suitable for most DB limits
 		effectiveLimit = math.MaxInt64
@@ -986,113 +1329,72 @@ func (db *DB) Find(ctx context.Context, dest any, condsAndOpts ...any) *Result {
 	}
 	if effectiveLimit > 0 { // Append LIMIT if it's positive (either user-set or default)
 		queryBuilder.WriteString(" LIMIT ")
-		queryBuilder.WriteString(strconv.FormatInt(int64(effectiveLimit), 10)) // Use FormatInt for safety with large numbers
+		queryBuilder.WriteInt(int64(effectiveLimit)) // Use FormatInt for safety with large numbers
 	}
 	if options.offset > 0 { // Append OFFSET if it's positive
 		queryBuilder.WriteString(" OFFSET ")
-		queryBuilder.WriteString(strconv.Itoa(options.offset))
+		queryBuilder.WriteInt(int64(options.offset))
 	}
 	// *** End Append optional clauses ***
 
-	sqlQuery := queryBuilder.String()
+	sqlQuery = renumberBindVars(dialect, queryBuilder.String()) // Conditions are built with BindVar(1)
+	whereArgs = append(joins.whereArgs(whereArgs), havingArgs...)
 
 	// 5. Execute Query using Query()
-	fmt.Printf("Executing SQL: %s | Args: %v\n", sqlQuery, whereArgs)
-	rows, err := db.source.Query(ctx, sqlQuery, whereArgs...)
+	logSQL("Executing SQL: %s | Args: %v\n", sqlQuery, redactArgs(model, whereArgs, condsAndOpts...))
+	rows, err := db.reader(ctx).Query(ctx, sqlQuery, whereArgs...)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to execute find query for %s: %w", model.Name, err)
 		return result
 	}
 	defer rows.Close()
 
-	// 6. Iterate and Scan Rows into Slice (remains the same logic)
-	sliceValue.Set(reflect.MakeSlice(sliceValue.Type(), 0, 0))
-
-	var addedElements []reflect.Value // Store elements for AfterFind hooks
-
-	rowCount := 0
-	for rows.Next() {
-		rowCount++
-		newElemInstance := reflect.New(schemaType).Elem()
-		scanDest := make([]any, len(scanFields))
-		for i, field := range scanFields {
-			fieldValue := newElemInstance.FieldByName(field.GoName)
-			if !fieldValue.IsValid() {
-				result.Error = fmt.Errorf("internal error: struct field %s not found in new element", field.GoName)
-				return result
-			}
-			if !fieldValue.CanAddr() {
-				result.Error = fmt.Errorf("internal error: struct field %s is not addressable", field.GoName)
-				return result
-			}
-			scanDest[i] = fieldValue.Addr().Interface()
-		}
-		if err := rows.Scan(scanDest...); err != nil {
-			result.Error = fmt.Errorf("failed to scan row for model %s: %w", model.Name, err)
-			return result
-		}
-		if elementIsPointer {
-			elemPtr := newElemInstance.Addr()
-			sliceValue.Set(reflect.Append(sliceValue, elemPtr))
-			addedElements = append(addedElements, elemPtr)
-		} else {
-			sliceValue.Set(reflect.Append(sliceValue, newElemInstance))
-			addedElements = append(addedElements, newElemInstance)
-		}
+	// 6. Iterate and Scan Rows into Slice (pooled destinations, see scanPlan)
+	plan, err := getScanPlan(schemaType, scanFields)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	rowCount, err := joins.scan(rows, sliceValue, schemaType, elementIsPointer, plan, scanCapacity(options.limit))
+	if err != nil {
+		result.Error = fmt.Errorf("failed to scan row for model %s: %w", model.Name, err)
+		return result
 	}
 	if err := rows.Err(); err != nil {
 		result.Error = fmt.Errorf("error iterating query results for %s: %w", model.Name, err)
 		return result
 	}
+	if err := checkMaxRows(rowCount, maxRows, sliceValue, model.Name); err != nil {
+		result.Error = err
+		return result
+	}
 	result.RowsAffected = int64(rowCount)
 	fmt.Printf("Successfully found and scanned %d record(s) into slice of %s\n", rowCount, elementType.Name())
 
-	// --- Call AfterFind Hook for each found element ---
-	if model.HasAfterFind && rowCount > 0 {
-		fmt.Printf("Calling AfterFind hook for %d elements...\n", len(addedElements))
-		for _, elemValue := range addedElements {
-			instanceValue := elemValue
-			hookMethod := instanceValue.MethodByName("AfterFind")
-			if hookMethod.IsValid() {
-				structValForHook := instanceValue
-				if instanceValue.Kind() == reflect.Pointer {
-					structValForHook = instanceValue.Elem()
-				}
-				if err := callHook(ctx, db, hookMethod, structValForHook); err != nil {
-					fmt.Printf("Warning: AfterFind hook failed for element: %v\n", err)
-				}
-			} else {
-				// This might happen if the hook is defined on the value receiver but the slice holds pointers,
-				// or vice-versa. The callHook helper tries both, but MethodByName needs the right receiver.
-				// Let's try getting the method on the pointer/value explicitly based on elemValue kind.
-				var method reflect.Value
-				if elemValue.Kind() == reflect.Pointer {
-					method = elemValue.MethodByName("AfterFind") // Check pointer first
-					if !method.IsValid() && elemValue.Elem().IsValid() {
-						method = elemValue.Elem().MethodByName("AfterFind") // Check value if pointer failed
-					}
-				} else { // elemValue is struct value
-					method = elemValue.MethodByName("AfterFind") // Check value first
-					if !method.IsValid() && elemValue.CanAddr() {
-						method = elemValue.Addr().MethodByName("AfterFind") // Check pointer if value failed
-					}
-				}
+	// --- Populate computed fields ---
+	for i := 0; i < sliceValue.Len(); i++ {
+		callAfterScan(model, sliceValue.Index(i)) // Slice elements, not the pre-append copies
+	}
 
-				if method.IsValid() {
-					structValForHook := elemValue
-					if elemValue.Kind() == reflect.Pointer {
-						structValForHook = elemValue.Elem()
-					}
-					if err := callHook(ctx, db, method, structValForHook); err != nil {
-						fmt.Printf("Warning: AfterFind hook failed for element (fallback check): %v\n", err)
-					}
-				} else {
-					fmt.Printf("Warning: Could not find AfterFind method via reflection for element type %s\n", elemValue.Type())
-				}
-			}
+	// --- Call AfterFind Hook for each found element ---
+	for i := 0; i < sliceValue.Len(); i++ {
+		elem := sliceValue.Index(i) // Slice elements, not the pre-append copies
+		if elem.Kind() != reflect.Pointer {
+			elem = elem.Addr()
+		}
+		if err := runCallbacks(ctx, db.callbacks, &HookContext{Event: EventAfterFind, Model: model, Value: elem.Interface(), DB: db}); err != nil {
+			fmt.Printf("Warning: AfterFind hook failed for element: %v\n", err)
 		}
 	}
 	// --- End Hook Call ---
+
+	// --- Load the relations requested with Preload ---
+	if len(options.preload) > 0 && rowCount > 0 {
+		if err := preload(ctx, db.relationSource(), db.parser, db.relations, destValue, options.preload, options.preloadConds); err != nil {
+			result.Error = err
+			return result
+		}
+	}
 	return result
 }
 
@@ -1112,10 +1414,11 @@ func (db *DB) Begin(ctx context.Context, opts ...*sql.TxOptions) (*Tx, error) {
 	if len(opts) > 0 && opts[0] != nil {
 		txOpt = *opts[0] // Use provided options if not nil
 	}
+	txOpt.ReadOnly = txOpt.ReadOnly || db.config.Safety.ReadOnly // Writes rejected by the ORM too
 
 	fmt.Println("Beginning transaction...")
 	// Call the underlying DataSource's BeginTx method
-	commonTx, err := db.source.BeginTx(ctx, txOpt) // Pass options as 'any'
+	commonTx, err := db.conn(ctx).BeginTx(ctx, txOpt) // Pass options as 'any'
 	if err != nil {
 		fmt.Printf("Failed to begin transaction: %v\n", err)
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
@@ -1124,9 +1427,28 @@ func (db *DB) Begin(ctx context.Context, opts ...*sql.TxOptions) (*Tx, error) {
 
 	// Wrap the common.Tx in our typegorm.Tx struct
 	tx := &Tx{
-		source:  commonTx,
-		parser:  db.parser,           // Share the parser
-		dialect: db.source.Dialect(), // Get dialect from the source
+		source:    guardTx(ctx, commonTx), // Count statements against the context's StatementGuard
+		parser:    db.parser,              // Share the parser
+		dialect:   db.source.Dialect(),    // Get dialect from the source
+		relations: db.relations,           // Share virtual relations
+		machines:  db.machines,            // Share state machines
+		callbacks: db.callbacks,           // Share lifecycle callbacks
+		cache:     db.cache,               // Share the entity cache (invalidation only)
+		now:       db.now,                 // Share the clock and ID generator
+		ids:       db.ids,
+		rewriter:  db.rewriter,
+		policies:  db.policies,
+		readOnly:  txOpt.ReadOnly,
+		maxRows:   db.config.Query.MaxRows,
+	}
+	tx.source = db.throttleTx(tx.source) // Throttle its writes (see ThrottleWrites)
+	db.startWatchdog(tx)
+	db.trackTx(tx)
+
+	// Apply per-transaction session settings (e.g., RLS tenant/user) carried by the context
+	if err := applySessionSettings(ctx, tx); err != nil {
+		_ = tx.Rollback()
+		return nil, err
 	}
 	return tx, nil
 }
@@ -1137,13 +1459,24 @@ func (db *DB) Begin(ctx context.Context, opts ...*sql.TxOptions) (*Tx, error) {
 
 // buildWhereClause constructs the WHERE clause parts based on conditions.
 // Supports struct pointer (query-by-example) or map[string]any (with operator suffixes).
-func buildWhereClause(dialect common.Dialect, model *schema.Model, condition any) ([]string, []any, error) {
+func buildWhereClause(dialect common.Dialect, model *schema.Model, condition any) (clauses []string, args []any, err error) {
+	defer recoverError(&err, "condition building", condition)
 	whereClauses := []string{}
 	whereArgs := []any{}
 
 	if condition == nil {
 		return whereClauses, whereArgs, nil // No conditions to build
 	}
+	if where, ok := condition.(chainWhere); ok {
+		return where.build(dialect, model) // Chain conditions (see Chain.Where)
+	}
+	if expr, ok := condition.(Expr); ok {
+		clause, args, err := expr.build(dialect, model)
+		if err != nil || clause == "" {
+			return whereClauses, whereArgs, err
+		}
+		return []string{clause}, args, nil
+	}
 
 	queryValue := reflect.ValueOf(condition)
 
@@ -1192,6 +1525,9 @@ func buildWhereClause(dialect common.Dialect, model *schema.Model, condition any
 			}
 
 			schemaField, ok := model.GetFieldByDBName(columnName)
+			if !ok && model.Type == nil && bareIdentifierRe.MatchString(columnName) && !strings.Contains(columnName, ".") {
+				schemaField, ok = &schema.Field{DBName: columnName}, true // Table without a model (see DB.Table)
+			}
 			if !ok {
 				return nil, nil, fmt.Errorf("invalid column name '%s' in map condition for model %s", columnName, model.Name)
 			}
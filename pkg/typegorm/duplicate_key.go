@@ -0,0 +1,151 @@
+package typegorm
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Duplicate Key Errors ---
+
+// DuplicateKeyError is the error of an insert or update violating a unique constraint.
+// It matches ErrDuplicateKey with errors.Is, and unwraps to the driver error:
+//
+//	var dup *typegorm.DuplicateKeyError
+//	if errors.As(res.Error, &dup) && slices.Contains(dup.Columns, "email") {
+//		return "email already taken"
+//	}
+type DuplicateKeyError struct {
+	Constraint string   // Constraint/index name reported by the database (may be empty on SQLite)
+	Columns    []string // Offending columns, from the error or resolved through the model
+	Err        error    // Driver error
+}
+
+func (e *DuplicateKeyError) Error() string {
+	if e.Constraint == "" {
+		return fmt.Sprintf("duplicate key on (%s): %v", strings.Join(e.Columns, ", "), e.Err)
+	}
+	return fmt.Sprintf("duplicate key for %s (%s): %v", e.Constraint, strings.Join(e.Columns, ", "), e.Err)
+}
+
+// Is reports whether target is ErrDuplicateKey.
+func (e *DuplicateKeyError) Is(target error) bool {
+	return target == ErrDuplicateKey
+}
+
+// Unwrap returns the driver error.
+func (e *DuplicateKeyError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	// MySQL: Error 1062 (23000): Duplicate entry 'a@b.c' for key 'users.idx_email'
+	mysqlDuplicateRe = regexp.MustCompile(`Duplicate entry '.*' for key '([^']+)'`)
+	// Postgres: duplicate key value violates unique constraint "users_email_key"
+	pgDuplicateRe = regexp.MustCompile(`duplicate key value violates unique constraint "([^"]+)"`)
+	// Postgres detail: Key (email, tenant_id)=(a@b.c, 1) already exists.
+	pgDetailRe = regexp.MustCompile(`Key \(([^)]+)\)=`)
+	// SQLite: UNIQUE constraint failed: users.email, users.tenant_id
+	sqliteDuplicateRe = regexp.MustCompile(`UNIQUE constraint failed: ([\w., ]+)`)
+)
+
+// translateDuplicateKey turns a unique violation reported by the driver into a
+// DuplicateKeyError. Other errors are returned unchanged.
+func translateDuplicateKey(err error, model *schema.Model) error {
+	if err == nil || errors.Is(err, ErrDuplicateKey) {
+		return err
+	}
+	msg := err.Error()
+	dup := &DuplicateKeyError{Err: err}
+	switch {
+	case mysqlDuplicateRe.MatchString(msg):
+		key := mysqlDuplicateRe.FindStringSubmatch(msg)[1]
+		if i := strings.LastIndex(key, "."); i >= 0 {
+			key = key[i+1:] // MySQL 8 reports "table.index"
+		}
+		dup.Constraint = key
+	case pgDuplicateRe.MatchString(msg) || driverField(err, "Code") == "23505":
+		if m := pgDuplicateRe.FindStringSubmatch(msg); m != nil {
+			dup.Constraint = m[1]
+		}
+		if dup.Constraint == "" {
+			dup.Constraint = driverField(err, "Constraint", "ConstraintName")
+		}
+		detail := driverField(err, "Detail")
+		if detail == "" {
+			detail = msg
+		}
+		if m := pgDetailRe.FindStringSubmatch(detail); m != nil {
+			for _, column := range strings.Split(m[1], ",") {
+				dup.Columns = append(dup.Columns, strings.TrimSpace(column))
+			}
+		}
+	case sqliteDuplicateRe.MatchString(msg):
+		for _, column := range strings.Split(sqliteDuplicateRe.FindStringSubmatch(msg)[1], ",") {
+			column = strings.TrimSpace(column)
+			if i := strings.LastIndex(column, "."); i >= 0 {
+				column = column[i+1:]
+			}
+			dup.Columns = append(dup.Columns, column)
+		}
+	default:
+		return err
+	}
+	if len(dup.Columns) == 0 {
+		dup.Columns = constraintColumns(model, dup.Constraint)
+	}
+	return dup
+}
+
+// constraintColumns resolves the columns of a unique constraint through the model:
+// its indexes, column-level UNIQUE (named after the column on MySQL, "<table>_<column>_key"
+// on Postgres) and the primary key.
+func constraintColumns(model *schema.Model, constraint string) []string {
+	if model == nil || constraint == "" {
+		return nil
+	}
+	var columns []string
+	for _, idx := range model.Indexes {
+		if idx.Name == constraint {
+			for _, field := range idx.Fields {
+				columns = append(columns, field.DBName)
+			}
+			return columns
+		}
+	}
+	if constraint == "PRIMARY" || constraint == model.TableName+"_pkey" {
+		for _, field := range model.PrimaryKeys {
+			columns = append(columns, field.DBName)
+		}
+		return columns
+	}
+	name := strings.TrimSuffix(strings.TrimPrefix(constraint, model.TableName+"_"), "_key")
+	if _, ok := model.FieldsByDBName[name]; ok {
+		return []string{name}
+	}
+	return nil
+}
+
+// driverField reads the first non-empty string field with one of the given names from
+// a driver error struct (lib/pq *pq.Error, pgx *pgconn.PgError), without importing them.
+func driverField(err error, names ...string) string {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		rv := reflect.ValueOf(e)
+		for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+			rv = rv.Elem()
+		}
+		if rv.Kind() != reflect.Struct {
+			continue
+		}
+		for _, name := range names {
+			if field := rv.FieldByName(name); field.IsValid() && field.Kind() == reflect.String && field.String() != "" {
+				return field.String()
+			}
+		}
+	}
+	return ""
+}
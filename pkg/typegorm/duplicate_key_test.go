@@ -0,0 +1,72 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dupAccount struct {
+	ID       uint   `typegorm:"primaryKey;autoIncrement"`
+	Email    string `typegorm:"unique"`
+	TenantID int    `typegorm:"uniqueIndex:idx_tenant_login"`
+	Login    string `typegorm:"uniqueIndex:idx_tenant_login"`
+}
+
+// pqError mimics the fields of lib/pq's *pq.Error.
+type pqError struct {
+	Code       string
+	Message    string
+	Detail     string
+	Constraint string
+}
+
+func (e *pqError) Error() string { return "pq: " + e.Message }
+
+func TestTranslateDuplicateKey(t *testing.T) {
+	db, _ := newMockDB()
+	model, err := db.GetModel(&dupAccount{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		err        error
+		constraint string
+		columns    []string
+	}{
+		{"mysql 8 index", errors.New("Error 1062 (23000): Duplicate entry '1-ana' for key 'dup_accounts.idx_tenant_login'"), "idx_tenant_login", []string{"login", "tenant_id"}},
+		{"mysql column unique", errors.New("Error 1062 (23000): Duplicate entry 'a@b.c' for key 'email'"), "email", []string{"email"}},
+		{"mysql primary", errors.New("Error 1062 (23000): Duplicate entry '7' for key 'PRIMARY'"), "PRIMARY", []string{"id"}},
+		{"postgres message", errors.New(`ERROR: duplicate key value violates unique constraint "dup_accounts_email_key" (SQLSTATE 23505)`), "dup_accounts_email_key", []string{"email"}},
+		{"postgres detail", &pqError{Code: "23505", Message: `duplicate key value violates unique constraint "idx_tenant_login"`, Detail: "Key (tenant_id, login)=(1, ana) already exists.", Constraint: "idx_tenant_login"}, "idx_tenant_login", []string{"tenant_id", "login"}},
+		{"sqlite", errors.New("UNIQUE constraint failed: dup_accounts.tenant_id, dup_accounts.login"), "", []string{"tenant_id", "login"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			translated := translateDuplicateKey(tt.err, model)
+			var dup *DuplicateKeyError
+			require.ErrorAs(t, translated, &dup)
+			assert.Equal(t, tt.constraint, dup.Constraint)
+			assert.Equal(t, tt.columns, dup.Columns)
+			assert.ErrorIs(t, translated, ErrDuplicateKey)
+			assert.ErrorIs(t, translated, tt.err)
+		})
+	}
+
+	other := errors.New("Error 1213 (40001): Deadlock found")
+	assert.Same(t, other, translateDuplicateKey(other, model))
+}
+
+func TestCreate_DuplicateKeyError(t *testing.T) {
+	db, source := newMockDB()
+	source.execErr = errors.New("Error 1062 (23000): Duplicate entry 'a@b.c' for key 'dup_accounts.email'")
+
+	res := db.Create(context.Background(), &dupAccount{Email: "a@b.c"})
+	require.ErrorIs(t, res.Error, ErrDuplicateKey)
+	var dup *DuplicateKeyError
+	require.ErrorAs(t, res.Error, &dup)
+	assert.Equal(t, []string{"email"}, dup.Columns)
+}
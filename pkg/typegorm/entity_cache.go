@@ -0,0 +1,378 @@
+package typegorm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Entity Cache ---
+
+// EntityCache stores serialized rows by key for FindByID (encoded with the DB's Codec,
+// JSON by default). Implementations must be safe for concurrent use; see
+// NewMemoryEntityCache and NewRedisEntityCache.
+type EntityCache interface {
+	// Get returns the value stored under key and whether it was found.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key; ttl <= 0 means no expiration.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes key (a missing key is not an error).
+	Delete(ctx context.Context, key string) error
+}
+
+// ConditionalSetter is implemented by caches that can store a value only under a key
+// holding none, atomically (SET NX). FindByID uses it to write rows back, so that a row
+// read before a concurrent write cannot replace the write's invalidation marker; with
+// other caches the check and the write are separate calls.
+type ConditionalSetter interface {
+	SetIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+}
+
+// PrefixDeleter is implemented by caches that can drop every key with a prefix. Bulk
+// writes (Chain.Updates/Delete) use it to invalidate a whole table; with other caches
+// the entries they change expire with their TTL.
+type PrefixDeleter interface {
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// CachedEntity is implemented by models whose rows FindByID caches (by primary key)
+// once a cache is set with DB.UseEntityCache:
+//
+//	func (Product) EntityCacheTTL() time.Duration { return 10 * time.Minute }
+//
+// Create, Updates, UpdateIf, UpdatesMask and Delete invalidate the cached row (in a
+// transaction, again after commit) by replacing it with a marker kept for
+// entityTombstoneTTL, during which FindByID does not cache the row: a read that
+// started before the write cannot store the old row back. Writes with raw SQL or other
+// processes are not seen, so the TTL bounds how stale a row can be. Only models with a
+// single primary key are cached.
+type CachedEntity interface {
+	EntityCacheTTL() time.Duration
+}
+
+// UseEntityCache enables the entity cache of CachedEntity models (nil disables it).
+// Call it before the DB is shared between goroutines.
+func (db *DB) UseEntityCache(cache EntityCache) {
+	db.cache = cache
+}
+
+// entityCacheTTL reports whether the model is cached, and for how long.
+func entityCacheTTL(cache EntityCache, model *schema.Model) (time.Duration, bool) {
+	if cache == nil || model == nil || model.Type == nil || len(model.PrimaryKeys) != 1 {
+		return 0, false
+	}
+	cached, ok := reflect.New(model.Type).Interface().(CachedEntity)
+	if !ok {
+		return 0, false
+	}
+	return cached.EntityCacheTTL(), true
+}
+
+func entityCachePrefix(model *schema.Model) string {
+	return "typegorm:" + model.QualifiedTableName() + ":"
+}
+
+func entityCacheKey(model *schema.Model, id any) string {
+	return entityCachePrefix(model) + fmt.Sprint(reflect.Indirect(reflect.ValueOf(id)).Interface())
+}
+
+// loadCachedEntity fills the column fields of dest (a struct value) from the cache.
+// Unreadable entries, e.g. written before a schema change, are dropped.
+func loadCachedEntity(ctx context.Context, cache EntityCache, codec Codec, model *schema.Model, scanFields []*schema.Field, id any, dest reflect.Value) bool {
+	if _, ok := entityCacheTTL(cache, model); !ok {
+		return false
+	}
+	key := entityCacheKey(model, id)
+	data, found, err := cache.Get(ctx, key)
+	if err != nil {
+		fmt.Printf("Warning: entity cache read failed for %s: %v\n", key, err)
+		return false
+	}
+	if !found || isEntityTombstone(data) {
+		return false
+	}
+	if err := decodeEntity(codec, model, scanFields, data, dest); err != nil {
+		fmt.Printf("Warning: dropping unreadable entity cache entry %s: %v\n", key, err)
+		_ = cache.Delete(ctx, key)
+		return false
+	}
+	return true
+}
+
+// storeCachedEntity serializes the column fields of dest into the cache.
+func storeCachedEntity(ctx context.Context, cache EntityCache, codec Codec, model *schema.Model, scanFields []*schema.Field, id any, dest reflect.Value) {
+	ttl, ok := entityCacheTTL(cache, model)
+	if !ok {
+		return
+	}
+	key := entityCacheKey(model, id)
+	data, err := encodeEntity(codec, model, scanFields, dest)
+	if err == nil {
+		err = checkEntityRoundTrip(codec, model, scanFields, data, dest)
+	}
+	if err != nil {
+		fmt.Printf("Warning: model %s cannot be cached: %v\n", model.Name, err)
+		return
+	}
+	if err := setEntityIfAbsent(ctx, cache, key, data, ttl); err != nil {
+		fmt.Printf("Warning: entity cache write failed for %s: %v\n", key, err)
+	}
+}
+
+// entityTombstone marks an invalidated row; entityTombstoneTTL bounds how long a read
+// may take and still be prevented from caching the row it read before the write.
+var entityTombstone = []byte("\x00typegorm:invalidated")
+
+const entityTombstoneTTL = 5 * time.Second
+
+func isEntityTombstone(data []byte) bool {
+	return bytes.Equal(data, entityTombstone)
+}
+
+// setEntityIfAbsent stores a row unless the key holds a value, e.g. a tombstone.
+func setEntityIfAbsent(ctx context.Context, cache EntityCache, key string, data []byte, ttl time.Duration) error {
+	if setter, ok := cache.(ConditionalSetter); ok {
+		_, err := setter.SetIfAbsent(ctx, key, data, ttl)
+		return err
+	}
+	current, found, err := cache.Get(ctx, key)
+	if err != nil || (found && isEntityTombstone(current)) {
+		return err
+	}
+	return cache.Set(ctx, key, data, ttl)
+}
+
+// tombstoneEntity replaces the cached row under key with a tombstone.
+func tombstoneEntity(ctx context.Context, cache EntityCache, key string) {
+	if err := cache.Set(ctx, key, entityTombstone, entityTombstoneTTL); err != nil {
+		fmt.Printf("Warning: entity cache invalidation failed for %s: %v\n", key, err)
+	}
+}
+
+// invalidateEntity drops the cached row of value (a struct pointer) and returns its key,
+// or "" when nothing is cached for it.
+func invalidateEntity(ctx context.Context, cache EntityCache, parser *schema.Parser, value any) string {
+	if cache == nil {
+		return ""
+	}
+	model, err := parser.Parse(value)
+	if err != nil {
+		return ""
+	}
+	if _, ok := entityCacheTTL(cache, model); !ok {
+		return ""
+	}
+	pk := reflect.Indirect(reflect.ValueOf(value)).FieldByName(model.PrimaryKeys[0].GoName)
+	if !pk.IsValid() || pk.IsZero() {
+		return ""
+	}
+	key := entityCacheKey(model, pk.Interface())
+	tombstoneEntity(ctx, cache, key)
+	return key
+}
+
+// invalidateEntityTable drops every cached row of the model when the cache supports it.
+func invalidateEntityTable(ctx context.Context, cache EntityCache, model *schema.Model) {
+	if _, ok := entityCacheTTL(cache, model); !ok {
+		return
+	}
+	if deleter, ok := cache.(PrefixDeleter); ok {
+		if err := deleter.DeletePrefix(ctx, entityCachePrefix(model)); err != nil {
+			fmt.Printf("Warning: entity cache invalidation failed for %s: %v\n", model.TableName, err)
+		}
+	}
+}
+
+// invalidateEntity drops the cached row of value now and again once the transaction
+// commits, so that a concurrent FindByID cannot cache the row as it was before.
+func (tx *Tx) invalidateEntity(ctx context.Context, value any) {
+	if key := invalidateEntity(ctx, tx.cache, tx.parser, value); key != "" {
+		cache := tx.cache
+		tx.AfterCommit(func() { tombstoneEntity(context.Background(), cache, key) })
+	}
+}
+
+// entityPayload returns the payload struct type of a model's cached rows: the column
+// names (checked when reading, to skip entries written before a schema change) and one
+// field per column, tagged with the column name.
+func entityPayload(model *schema.Model, scanFields []*schema.Field) reflect.Type {
+	if cached, ok := entityPayloads.Load(model); ok {
+		return cached.(reflect.Type)
+	}
+	fields := make([]reflect.StructField, 0, len(scanFields)+1)
+	fields = append(fields, reflect.StructField{
+		Name: "Columns",
+		Type: reflect.TypeOf([]string(nil)),
+		Tag:  `json:"_columns" msgpack:"_columns"`,
+	})
+	for i, field := range scanFields {
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("F%d", i),
+			Type: field.GoType,
+			Tag:  reflect.StructTag(fmt.Sprintf(`json:"%s" msgpack:"%s"`, field.DBName, field.DBName)),
+		})
+	}
+	payload := reflect.StructOf(fields)
+	entityPayloads.Store(model, payload)
+	return payload
+}
+
+var entityPayloads sync.Map // *schema.Model -> reflect.Type
+
+// encodeEntity serializes the column values of dest with codec.
+func encodeEntity(codec Codec, model *schema.Model, scanFields []*schema.Field, dest reflect.Value) ([]byte, error) {
+	payload := reflect.New(entityPayload(model, scanFields)).Elem()
+	columns := make([]string, len(scanFields))
+	for i, field := range scanFields {
+		columns[i] = field.DBName
+		payload.Field(i + 1).Set(dest.FieldByName(field.GoName))
+	}
+	payload.Field(0).Set(reflect.ValueOf(columns))
+	return codec.Marshal(payload.Addr().Interface())
+}
+
+// decodeEntity sets the column fields of dest from a payload written by encodeEntity.
+func decodeEntity(codec Codec, model *schema.Model, scanFields []*schema.Field, data []byte, dest reflect.Value) error {
+	payload := reflect.New(entityPayload(model, scanFields))
+	if err := codec.Unmarshal(data, payload.Interface()); err != nil {
+		return err
+	}
+	payload = payload.Elem()
+	columns, _ := payload.Field(0).Interface().([]string)
+	if len(columns) != len(scanFields) {
+		return fmt.Errorf("cached columns differ from the model")
+	}
+	for i, field := range scanFields {
+		if columns[i] != field.DBName {
+			return fmt.Errorf("cached columns differ from the model")
+		}
+	}
+	for i, field := range scanFields {
+		dest.FieldByName(field.GoName).Set(payload.Field(i + 1))
+	}
+	return nil
+}
+
+// checkEntityRoundTrip decodes a payload written by encodeEntity and compares it with
+// the row it was made from, so that a codec losing data (e.g. JSON with interface
+// fields, or types without JSON support) never serves altered rows.
+func checkEntityRoundTrip(codec Codec, model *schema.Model, scanFields []*schema.Field, data []byte, dest reflect.Value) error {
+	decoded := reflect.New(model.Type).Elem()
+	if err := decodeEntity(codec, model, scanFields, data, decoded); err != nil {
+		return fmt.Errorf("%T cannot read its own payload: %w", codec, err)
+	}
+	for _, field := range scanFields {
+		want, got := dest.FieldByName(field.GoName), decoded.FieldByName(field.GoName)
+		if !sameEntityValue(want, got) {
+			return fmt.Errorf("field %s does not survive a %T round trip", field.GoName, codec)
+		}
+	}
+	return nil
+}
+
+// sameEntityValue compares two field values; times are compared as instants, as
+// codecs may not keep their location.
+func sameEntityValue(a, b reflect.Value) bool {
+	if t, ok := a.Interface().(time.Time); ok {
+		return t.Equal(b.Interface().(time.Time))
+	}
+	if t, ok := a.Interface().(*time.Time); ok && t != nil {
+		u, _ := b.Interface().(*time.Time)
+		return u != nil && t.Equal(*u)
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// --- In-memory Entity Cache ---
+
+// MemoryEntityCache is an EntityCache local to the process. Expired entries are
+// removed when read.
+type MemoryEntityCache struct {
+	mu      sync.RWMutex
+	entries map[string]memoryCacheEntry
+}
+
+type memoryCacheEntry struct {
+	data    []byte
+	expires time.Time // Zero: no expiration
+}
+
+// NewMemoryEntityCache creates an empty in-memory cache.
+func NewMemoryEntityCache() *MemoryEntityCache {
+	return &MemoryEntityCache{entries: make(map[string]memoryCacheEntry)}
+}
+
+func (c *MemoryEntityCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, false, nil
+	}
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.mu.Lock()
+		delete(c.entries, key)
+		c.mu.Unlock()
+		return nil, false, nil
+	}
+	return entry.data, true, nil
+}
+
+func (c *MemoryEntityCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := memoryCacheEntry{data: append([]byte(nil), value...)}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	c.entries[key] = entry
+	c.mu.Unlock()
+	return nil
+}
+
+// SetIfAbsent stores value unless key holds an unexpired value.
+func (c *MemoryEntityCache) SetIfAbsent(_ context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	entry := memoryCacheEntry{data: append([]byte(nil), value...)}
+	now := time.Now()
+	if ttl > 0 {
+		entry.expires = now.Add(ttl)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if current, ok := c.entries[key]; ok && (current.expires.IsZero() || !now.After(current.expires)) {
+		return false, nil
+	}
+	c.entries[key] = entry
+	return true, nil
+}
+
+func (c *MemoryEntityCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+	return nil
+}
+
+// DeletePrefix removes every key starting with prefix.
+func (c *MemoryEntityCache) DeletePrefix(_ context.Context, prefix string) error {
+	c.mu.Lock()
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// Len returns the number of entries, including expired ones not yet removed.
+func (c *MemoryEntityCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
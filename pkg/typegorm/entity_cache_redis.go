@@ -0,0 +1,267 @@
+package typegorm
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- Redis Entity Cache ---
+
+// RedisEntityCache is an EntityCache on a Redis server, shared by all the processes
+// using it. It speaks the Redis protocol (RESP) over a single connection, opened on
+// first use and reopened after network errors; commands are serialized.
+type RedisEntityCache struct {
+	addr     string
+	password string
+	db       int
+	timeout  time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+// RedisOption configures a RedisEntityCache.
+type RedisOption func(*RedisEntityCache)
+
+// RedisPassword authenticates with AUTH password.
+func RedisPassword(password string) RedisOption {
+	return func(c *RedisEntityCache) { c.password = password }
+}
+
+// RedisDB selects the logical database (SELECT n).
+func RedisDB(n int) RedisOption {
+	return func(c *RedisEntityCache) { c.db = n }
+}
+
+// RedisTimeout bounds dialing and each command when the context has no deadline
+// (default 2s).
+func RedisTimeout(d time.Duration) RedisOption {
+	return func(c *RedisEntityCache) { c.timeout = d }
+}
+
+// NewRedisEntityCache returns a cache on the Redis server at addr ("host:port"). The
+// connection is opened on first use.
+func NewRedisEntityCache(addr string, opts ...RedisOption) *RedisEntityCache {
+	c := &RedisEntityCache{addr: addr, timeout: 2 * time.Second}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
+	return c
+}
+
+func (c *RedisEntityCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := c.do(ctx, "GET", key)
+	if err != nil || reply == nil {
+		return nil, false, err
+	}
+	data, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("redis: unexpected GET reply %T", reply)
+	}
+	return data, true, nil
+}
+
+func (c *RedisEntityCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []string{"SET", key, string(value)}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(max(ttl.Milliseconds(), 1), 10))
+	}
+	_, err := c.do(ctx, args...)
+	return err
+}
+
+// SetIfAbsent stores value unless key exists (SET NX).
+func (c *RedisEntityCache) SetIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	args := []string{"SET", key, string(value), "NX"}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(max(ttl.Milliseconds(), 1), 10))
+	}
+	reply, err := c.do(ctx, args...)
+	return reply != nil, err
+}
+
+func (c *RedisEntityCache) Delete(ctx context.Context, key string) error {
+	_, err := c.do(ctx, "DEL", key)
+	return err
+}
+
+// DeletePrefix removes every key starting with prefix, walking the keyspace with SCAN.
+func (c *RedisEntityCache) DeletePrefix(ctx context.Context, prefix string) error {
+	pattern := redisGlobEscaper.Replace(prefix) + "*"
+	cursor := "0"
+	for {
+		reply, err := c.do(ctx, "SCAN", cursor, "MATCH", pattern, "COUNT", "500")
+		if err != nil {
+			return err
+		}
+		page, ok := reply.([]any)
+		if !ok || len(page) != 2 {
+			return fmt.Errorf("redis: unexpected SCAN reply %v", reply)
+		}
+		next, _ := page[0].([]byte)
+		keys, _ := page[1].([]any)
+		if len(keys) > 0 {
+			args := make([]string, 0, len(keys)+1)
+			args = append(args, "DEL")
+			for _, key := range keys {
+				if k, ok := key.([]byte); ok {
+					args = append(args, string(k))
+				}
+			}
+			if _, err := c.do(ctx, args...); err != nil {
+				return err
+			}
+		}
+		cursor = string(next)
+		if cursor == "0" || cursor == "" {
+			return nil
+		}
+	}
+}
+
+// Close closes the connection.
+func (c *RedisEntityCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeConn()
+}
+
+var redisGlobEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// redisError is an error reply of the server; the connection stays usable.
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+// do sends one command and reads its reply: nil, string, int64, []byte or []any.
+func (c *RedisEntityCache) do(ctx context.Context, args ...string) (any, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.connect(ctx); err != nil {
+		return nil, err
+	}
+	reply, err := c.roundTrip(ctx, args)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		_ = c.closeConn() // Unknown connection state: reconnect on the next command
+	}
+	return reply, err
+}
+
+func (c *RedisEntityCache) connect(ctx context.Context) error {
+	if c.conn != nil {
+		return nil
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	conn, err := (&net.Dialer{}).DialContext(dialCtx, "tcp", c.addr)
+	if err != nil {
+		return fmt.Errorf("redis: failed to connect to %s: %w", c.addr, err)
+	}
+	c.conn, c.rd = conn, bufio.NewReader(conn)
+	if c.password != "" {
+		if _, err := c.roundTrip(ctx, []string{"AUTH", c.password}); err != nil {
+			_ = c.closeConn()
+			return err
+		}
+	}
+	if c.db != 0 {
+		if _, err := c.roundTrip(ctx, []string{"SELECT", strconv.Itoa(c.db)}); err != nil {
+			_ = c.closeConn()
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *RedisEntityCache) closeConn() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn, c.rd = nil, nil
+	return err
+}
+
+func (c *RedisEntityCache) roundTrip(ctx context.Context, args []string) (any, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(c.timeout)
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.conn, b.String()); err != nil {
+		return nil, fmt.Errorf("redis: %s failed: %w", args[0], err)
+	}
+	return readRedisReply(c.rd)
+}
+
+func readRedisReply(rd *bufio.Reader) (any, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("redis: failed to read reply: %w", err)
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err // $-1: nil reply
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(rd, data); err != nil {
+			return nil, fmt.Errorf("redis: failed to read reply: %w", err)
+		}
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		// Read every element, even after an error element, to keep the connection in
+		// sync; the first error element is returned.
+		items := make([]any, n)
+		var replyErr error
+		for i := range items {
+			items[i], err = readRedisReply(rd)
+			var elemErr redisError
+			if errors.As(err, &elemErr) {
+				replyErr = cmp.Or(replyErr, err)
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		if replyErr != nil {
+			return nil, replyErr
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("redis: unexpected reply %q", line)
+}
@@ -0,0 +1,320 @@
+package typegorm
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cachedProduct struct {
+	ID    uint `typegorm:"primaryKey;autoIncrement"`
+	Name  string
+	Note  *string
+	Price int
+}
+
+func (cachedProduct) EntityCacheTTL() time.Duration { return time.Minute }
+
+func queueProduct(source *mockSource, id int64, name string) {
+	source.queueRows([]string{"id", "name", "note", "price"}, []any{id, name, "fragile", int64(30)})
+}
+
+func TestEntityCache_FindByID(t *testing.T) {
+	db, source := newMockDB()
+	cache := NewMemoryEntityCache()
+	db.UseEntityCache(cache)
+	ctx := context.Background()
+
+	queueProduct(source, 1, "Lamp")
+	var first cachedProduct
+	require.NoError(t, db.FindByID(ctx, &first, 1).Error)
+	assert.Equal(t, 1, cache.Len())
+	queries := len(source.Statements())
+
+	second := cachedProduct{Price: 99}
+	res := db.FindByID(ctx, &second, 1)
+	require.NoError(t, res.Error)
+	assert.EqualValues(t, 1, res.RowsAffected)
+	assert.Len(t, source.Statements(), queries, "served from the cache")
+	assert.Equal(t, first, second)
+	require.NotNil(t, second.Note)
+	assert.Equal(t, "fragile", *second.Note)
+
+	var uncached maskUser
+	queueMaskUsers(source, 1)
+	require.NoError(t, db.FindByID(ctx, &uncached, 1).Error)
+	assert.Equal(t, 1, cache.Len(), "models without EntityCacheTTL are not cached")
+}
+
+func TestEntityCache_InvalidatedByWrites(t *testing.T) {
+	db, source := newMockDB()
+	cache := NewMemoryEntityCache()
+	db.UseEntityCache(cache)
+	ctx := context.Background()
+	const key = "typegorm:cached_products:1"
+	cached := func() bool {
+		data, found, _ := cache.Get(ctx, key)
+		return found && !isEntityTombstone(data)
+	}
+	load := func() {
+		require.NoError(t, cache.Delete(ctx, key)) // As when the tombstone expires
+		queueProduct(source, 1, "Lamp")
+		require.NoError(t, db.FindByID(ctx, &cachedProduct{}, 1).Error)
+		require.True(t, cached())
+	}
+
+	load()
+	require.NoError(t, db.Updates(ctx, &cachedProduct{ID: 1}, map[string]any{"price": 40}).Error)
+	assert.False(t, cached(), "Updates")
+
+	load()
+	require.NoError(t, db.UpdateIf(ctx, &cachedProduct{ID: 1}, map[string]any{"price": 41}, map[string]any{"price": 40}).Error)
+	assert.False(t, cached(), "UpdateIf")
+
+	load()
+	require.NoError(t, db.Model(&cachedProduct{}).Where(map[string]any{"price >": 10}).Update(ctx, "price", 5).Error)
+	assert.Equal(t, 0, cache.Len(), "Chain bulk update")
+
+	load()
+	require.NoError(t, db.Delete(ctx, &cachedProduct{ID: 1}).Error)
+	assert.False(t, cached(), "Delete")
+
+	load()
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Updates(ctx, &cachedProduct{ID: 1}, map[string]any{"name": "Desk lamp"}).Error)
+	assert.False(t, cached(), "invalidated in the transaction")
+	require.NoError(t, cache.Set(ctx, key, []byte("stale"), 0))
+	require.NoError(t, tx.Commit())
+	assert.False(t, cached(), "invalidated again after commit")
+}
+
+func TestEntityCache_ReadBeforeAWriteIsNotStoredBack(t *testing.T) {
+	db, source := newMockDB()
+	cache := NewMemoryEntityCache()
+	db.UseEntityCache(cache)
+	ctx := context.Background()
+
+	// The write lands while the read is running: the read cannot cache its old row.
+	require.NoError(t, db.Updates(ctx, &cachedProduct{ID: 1}, map[string]any{"name": "Desk lamp"}).Error)
+	queueProduct(source, 1, "Lamp")
+	var product cachedProduct
+	require.NoError(t, db.FindByID(ctx, &product, 1).Error)
+	assert.Equal(t, "Lamp", product.Name)
+	data, found, _ := cache.Get(ctx, "typegorm:cached_products:1")
+	require.True(t, found)
+	assert.True(t, isEntityTombstone(data), "the tombstone is kept")
+
+	queueProduct(source, 1, "Desk lamp")
+	require.NoError(t, db.FindByID(ctx, &product, 1).Error)
+	assert.Equal(t, "Desk lamp", product.Name, "tombstones are cache misses")
+}
+
+func TestEntityCache_UnreadableEntryIsDropped(t *testing.T) {
+	db, source := newMockDB()
+	cache := NewMemoryEntityCache()
+	db.UseEntityCache(cache)
+	ctx := context.Background()
+	require.NoError(t, cache.Set(ctx, "typegorm:cached_products:1", []byte("garbage"), 0))
+
+	queueProduct(source, 1, "Lamp")
+	var product cachedProduct
+	require.NoError(t, db.FindByID(ctx, &product, 1).Error)
+	assert.Equal(t, "Lamp", product.Name, "read from the database")
+	data, found, _ := cache.Get(ctx, "typegorm:cached_products:1")
+	require.True(t, found)
+	assert.NotEqual(t, "garbage", string(data), "replaced with the fresh row")
+}
+
+func TestMemoryEntityCache_TTLAndPrefix(t *testing.T) {
+	cache := NewMemoryEntityCache()
+	ctx := context.Background()
+	require.NoError(t, cache.Set(ctx, "a:1", []byte("x"), time.Nanosecond))
+	require.NoError(t, cache.Set(ctx, "a:2", []byte("y"), 0))
+	require.NoError(t, cache.Set(ctx, "b:1", []byte("z"), 0))
+	time.Sleep(time.Millisecond)
+
+	_, found, _ := cache.Get(ctx, "a:1")
+	assert.False(t, found, "expired")
+	require.NoError(t, cache.DeletePrefix(ctx, "a:"))
+	assert.Equal(t, 1, cache.Len())
+}
+
+// fakeRedis is a minimal in-memory RESP server supporting the commands of RedisEntityCache.
+type fakeRedis struct {
+	mu       sync.Mutex
+	data     map[string]string
+	commands []string
+}
+
+func startFakeRedis(t *testing.T) (*fakeRedis, string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	srv := &fakeRedis{data: map[string]string{}}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go srv.serve(conn)
+		}
+	}()
+	return srv, ln.Addr().String()
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	rd := bufio.NewReader(conn)
+	for {
+		reply, err := readRedisReply(rd)
+		if err != nil {
+			return
+		}
+		items := reply.([]any)
+		args := make([]string, len(items))
+		for i, item := range items {
+			args[i] = string(item.([]byte))
+		}
+		s.mu.Lock()
+		s.commands = append(s.commands, args[0])
+		var out string
+		switch strings.ToUpper(args[0]) {
+		case "AUTH", "SELECT":
+			out = "+OK\r\n"
+		case "SET":
+			out = "+OK\r\n"
+			if _, exists := s.data[args[1]]; exists && len(args) > 3 && args[3] == "NX" {
+				out = "$-1\r\n"
+				break
+			}
+			s.data[args[1]] = args[2]
+		case "GET":
+			if v, ok := s.data[args[1]]; ok {
+				out = fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+			} else {
+				out = "$-1\r\n"
+			}
+		case "DEL":
+			for _, key := range args[1:] {
+				delete(s.data, key)
+			}
+			out = ":" + strconv.Itoa(len(args)-1) + "\r\n"
+		case "SCAN":
+			prefix := strings.TrimSuffix(strings.ReplaceAll(args[3], `\`, ""), "*")
+			var keys []string
+			for key := range s.data {
+				if strings.HasPrefix(key, prefix) {
+					keys = append(keys, fmt.Sprintf("$%d\r\n%s\r\n", len(key), key))
+				}
+			}
+			out = fmt.Sprintf("*2\r\n$1\r\n0\r\n*%d\r\n%s", len(keys), strings.Join(keys, ""))
+		default:
+			out = "-ERR unknown command\r\n"
+		}
+		s.mu.Unlock()
+		if _, err := conn.Write([]byte(out)); err != nil {
+			return
+		}
+	}
+}
+
+func TestRedisEntityCache(t *testing.T) {
+	srv, addr := startFakeRedis(t)
+	cache := NewRedisEntityCache(addr, RedisPassword("secret"), RedisDB(2))
+	defer cache.Close()
+	ctx := context.Background()
+
+	_, found, err := cache.Get(ctx, "typegorm:products:1")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, cache.Set(ctx, "typegorm:products:1", []byte("row\r\n1"), time.Minute))
+	require.NoError(t, cache.Set(ctx, "typegorm:products:2", []byte("row2"), 0))
+	require.NoError(t, cache.Set(ctx, "typegorm:orders:1", []byte("order"), 0))
+	data, found, err := cache.Get(ctx, "typegorm:products:1")
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, "row\r\n1", string(data))
+
+	require.NoError(t, cache.DeletePrefix(ctx, "typegorm:products:"))
+	_, found, _ = cache.Get(ctx, "typegorm:products:2")
+	assert.False(t, found)
+	_, found, _ = cache.Get(ctx, "typegorm:orders:1")
+	assert.True(t, found)
+
+	require.NoError(t, cache.Delete(ctx, "typegorm:orders:1"))
+	_, found, _ = cache.Get(ctx, "typegorm:orders:1")
+	assert.False(t, found)
+
+	srv.mu.Lock()
+	assert.Equal(t, []string{"AUTH", "SELECT"}, srv.commands[:2], "authenticates once per connection")
+	srv.mu.Unlock()
+}
+
+func TestEntityCache_Codecs(t *testing.T) {
+	for name, codec := range map[string]Codec{"json": nil, "gob": GobCodec{}} {
+		t.Run(name, func(t *testing.T) {
+			db, source := newMockDB()
+			cache := NewMemoryEntityCache()
+			db.UseEntityCache(cache)
+			db.UseCodec(codec)
+			ctx := context.Background()
+
+			source.queueRows([]string{"id", "name", "note", "price"}, []any{int64(2), "Chair", nil, int64(15)})
+			var first, second cachedProduct
+			require.NoError(t, db.FindByID(ctx, &first, 2).Error)
+			data, found, _ := cache.Get(ctx, "typegorm:cached_products:2")
+			require.True(t, found)
+			if codec == nil {
+				assert.JSONEq(t, `{"_columns":["id","name","note","price"],"id":2,"name":"Chair","note":null,"price":15}`, string(data))
+			}
+
+			second.Note = new(string)
+			require.NoError(t, db.FindByID(ctx, &second, 2).Error)
+			assert.Equal(t, first, second, "nil pointers are restored")
+		})
+	}
+}
+
+// lossyCodec loses part of the payloads, as JSON does for interface fields.
+type lossyCodec struct{ JSONCodec }
+
+func (c lossyCodec) Unmarshal(data []byte, v any) error {
+	return c.JSONCodec.Unmarshal([]byte(strings.ReplaceAll(string(data), `"Chair"`, `""`)), v)
+}
+
+func TestEntityCache_SkipsRowsTheCodecCannotRestore(t *testing.T) {
+	db, source := newMockDB()
+	cache := NewMemoryEntityCache()
+	db.UseEntityCache(cache)
+	db.UseCodec(lossyCodec{})
+	ctx := context.Background()
+
+	source.queueRows([]string{"id", "name", "note", "price"}, []any{int64(2), "Chair", nil, int64(15)})
+	var product cachedProduct
+	require.NoError(t, db.FindByID(ctx, &product, 2).Error)
+	assert.Equal(t, "Chair", product.Name)
+	_, found, _ := cache.Get(ctx, "typegorm:cached_products:2")
+	assert.False(t, found, "a row that does not survive the round trip is not cached")
+}
+
+func TestReadRedisReply_DrainsArraysWithErrorElements(t *testing.T) {
+	rd := bufio.NewReader(strings.NewReader("*3\r\n+OK\r\n-ERR first\r\n$2\r\nhi\r\n:7\r\n"))
+	_, err := readRedisReply(rd)
+	assert.EqualError(t, err, "redis: ERR first")
+
+	next, err := readRedisReply(rd)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), next, "the whole array was read")
+}
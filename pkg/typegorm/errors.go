@@ -0,0 +1,131 @@
+package typegorm
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// ErrUnsupportedDialect is returned when a feature is not available for the
+// dialect of the current connection.
+var ErrUnsupportedDialect = errors.New("typegorm: operation not supported by dialect")
+
+// ErrReadOnlyTransaction is returned by write operations (Create, Updates, Delete, ...)
+// called on a transaction started with BeginReadOnly.
+var ErrReadOnlyTransaction = errors.New("typegorm: write operation in read-only transaction")
+
+// ErrReadOnlyDatabase is returned by the writes of a DB whose config sets
+// safety.readOnly (the default of the production profile).
+var ErrReadOnlyDatabase = errors.New("typegorm: write operation on a read-only database")
+
+// ErrTransactionTimeout is returned by Commit when the transaction watchdog already
+// rolled the transaction back for exceeding transaction.watchdogThreshold.
+var ErrTransactionTimeout = errors.New("typegorm: transaction rolled back by watchdog")
+
+// ErrConcurrentTxUse is returned when a Tx is used by several goroutines at the same
+// time (a transaction runs on a single connection, which drivers cannot interleave).
+var ErrConcurrentTxUse = errors.New("typegorm: transaction used concurrently")
+
+// ErrDuplicateKey matches (errors.Is) the *DuplicateKeyError returned when an insert or
+// update violates a unique constraint.
+var ErrDuplicateKey = errors.New("typegorm: duplicate key")
+
+// ErrTooManyRows matches (errors.Is) the *MaxRowsError returned when a Find exceeds
+// its MaxRows guard.
+var ErrTooManyRows = errors.New("typegorm: too many rows")
+
+// ErrInvalidTransition matches (errors.Is) the *TransitionError returned when Updates
+// changes a state machine field to a state not allowed from the current one.
+var ErrInvalidTransition = errors.New("typegorm: invalid state transition")
+
+// ErrStatementQuotaExceeded is returned by statements executed beyond the limit of a
+// StatementGuard created with FailOnExceed.
+var ErrStatementQuotaExceeded = errors.New("typegorm: statement quota exceeded")
+
+// ErrMissingWhereClause is returned by Chain.Update, Updates and Delete without
+// conditions, which would change every row; call AllRows to confirm that intent.
+var ErrMissingWhereClause = errors.New("typegorm: update or delete without conditions")
+
+// ErrPolicyDenied is returned when a data-access policy (see DB.UsePolicy) rejects a
+// statement; the policy's error is wrapped as well.
+var ErrPolicyDenied = errors.New("typegorm: denied by policy")
+
+// ErrWriteThrottled is returned by writes whose context ends, or would end, before the
+// write throttle (see DB.ThrottleWrites) lets them run.
+var ErrWriteThrottled = errors.New("typegorm: write throttled")
+
+// OpError describes the ORM operation a returned error comes from. Operations such as
+// Create, Find or Updates wrap their errors in it; errors.Is/As still reach the
+// underlying error (sentinels, driver errors, *PanicError):
+//
+//	var opErr *typegorm.OpError
+//	if errors.As(res.Error, &opErr) {
+//		log.Printf("%s on %s failed (SQL: %s)", opErr.Op, opErr.Table, opErr.SQL)
+//	}
+type OpError struct {
+	Op    string // Operation, e.g. "Create", "FindByID", "UpdateIf"
+	Model string // Go type of the model, e.g. "User"
+	Table string // Table name (empty when the model could not be parsed)
+	SQL   string // Statement of the operation, if it was built before the error
+	Err   error
+}
+
+func (e *OpError) Error() string {
+	if e.Model == "" {
+		return fmt.Sprintf("typegorm: %s: %v", e.Op, e.Err)
+	}
+	return fmt.Sprintf("typegorm: %s %s: %v", e.Op, e.Model, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *OpError) Unwrap() error {
+	return e.Err
+}
+
+// wrapOpError wraps the error of an operation returning *Result in an OpError, turning
+// unique violations into a DuplicateKeyError. It must be deferred before recoverResult
+// so that recovered panics are wrapped as well:
+//
+//	var sqlQuery string
+//	defer wrapOpError(&result, db.parser, "Create", value, &sqlQuery)
+//	defer recoverResult(&result, "Create", value)
+func wrapOpError(result **Result, parser *schema.Parser, op string, value any, sqlQuery *string) {
+	if *result == nil || (*result).Error == nil {
+		return
+	}
+	var existing *OpError
+	if errors.As((*result).Error, &existing) {
+		return // Already wrapped by a nested operation
+	}
+	opErr := &OpError{Op: op, SQL: *sqlQuery, Err: (*result).Error}
+	var model *schema.Model
+	if t := modelType(value); t != nil {
+		opErr.Model = t.Name()
+		if parser != nil {
+			if parsed, err := parser.Parse(reflect.New(t).Interface()); err == nil {
+				model = parsed
+				opErr.Table = model.TableName
+			}
+		}
+	}
+	opErr.Err = translateDuplicateKey(opErr.Err, model)
+	(*result).Error = opErr
+}
+
+// modelType returns the struct type behind value (struct, pointer, slice or pointer
+// to a slice of structs or struct pointers), or nil.
+func modelType(value any) reflect.Type {
+	if value == nil {
+		return nil
+	}
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
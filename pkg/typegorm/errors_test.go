@@ -0,0 +1,49 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpError_WrapsOperationErrors(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	driverErr := errors.New("connection reset")
+	source.execErr = driverErr
+
+	res := db.Updates(ctx, &maskUser{ID: 1}, map[string]any{"name": "Ana"})
+	var opErr *OpError
+	require.ErrorAs(t, res.Error, &opErr)
+	assert.Equal(t, "Updates", opErr.Op)
+	assert.Equal(t, "maskUser", opErr.Model)
+	assert.Equal(t, "mask_users", opErr.Table)
+	assert.Contains(t, opErr.SQL, "UPDATE `mask_users`")
+	assert.ErrorIs(t, res.Error, driverErr, "the driver error stays reachable")
+	assert.Contains(t, res.Error.Error(), "typegorm: Updates maskUser:")
+
+	// Sentinel errors from the database layer are still matched
+	res = db.FindByID(ctx, &maskUser{}, 42)
+	require.ErrorAs(t, res.Error, &opErr)
+	assert.Equal(t, "FindByID", opErr.Op)
+	assert.ErrorIs(t, res.Error, sql.ErrNoRows)
+
+	// Errors before any SQL is built carry no statement
+	res = db.Find(ctx, &[]maskUser{}, map[string]any{"nope": 1})
+	require.ErrorAs(t, res.Error, &opErr)
+	assert.Equal(t, "Find", opErr.Op)
+	assert.Equal(t, "maskUser", opErr.Model)
+	assert.Empty(t, opErr.SQL)
+
+	tx, err := db.BeginReadOnly(ctx)
+	require.NoError(t, err)
+	res = tx.UpdateIf(ctx, &maskUser{ID: 1}, map[string]any{"name": "Bia"}, nil)
+	require.ErrorAs(t, res.Error, &opErr)
+	assert.Equal(t, "UpdateIf", opErr.Op)
+	assert.ErrorIs(t, res.Error, ErrReadOnlyTransaction)
+	require.NoError(t, tx.Rollback())
+}
@@ -0,0 +1,153 @@
+package typegorm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type exportCustomer struct {
+	ID    uint   `typegorm:"primaryKey;autoIncrement"`
+	Name  string `typegorm:"anonymize:name"`
+	Email string `typegorm:"anonymize:email"`
+	Phone string
+	Plan  string
+}
+
+func TestExport_AnonymizesTaggedAndConfiguredColumns(t *testing.T) {
+	db, source := newMockDB()
+	db.config.Export.Anonymize = map[string]map[string]string{
+		"export_customers": {"phone": "phone"},
+	}
+	source.queueRows([]string{"id", "name", "email", "phone", "plan"},
+		[]any{1, "Alice Smith", "alice@corp.com", "555-1234", "pro"},
+		[]any{2, "Bob Jones", "bob@corp.com", "555-9876", "free"},
+	)
+
+	var buf bytes.Buffer
+	count, err := db.Export(context.Background(), &buf, &exportCustomer{}, ExportOptions{Anonymize: true})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var first map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.NotEqual(t, "alice@corp.com", first["email"])
+	assert.True(t, strings.HasSuffix(first["email"].(string), "@example.com"))
+	assert.NotEqual(t, "Alice Smith", first["name"])
+	assert.NotEqual(t, "555-1234", first["phone"], "config rule should anonymize phone")
+	assert.Equal(t, "pro", first["plan"], "columns without rules are exported as-is")
+
+	// Deterministic: the same input gives the same anonymized value.
+	assert.Equal(t, anonymizeEmail("alice@corp.com"), first["email"])
+}
+
+func TestExport_OptionRulesOverrideTags(t *testing.T) {
+	db, source := newMockDB()
+	source.queueRows([]string{"id", "name", "email", "phone", "plan"},
+		[]any{1, "Alice Smith", "alice@corp.com", "555-1234", "pro"},
+	)
+
+	var buf bytes.Buffer
+	_, err := db.Export(context.Background(), &buf, &exportCustomer{}, ExportOptions{
+		Anonymize: true,
+		Rules:     map[string]string{"email": "null", "plan": "redact"},
+	})
+	require.NoError(t, err)
+
+	var row map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &row))
+	assert.Nil(t, row["email"])
+	assert.Equal(t, "[REDACTED]", row["plan"])
+}
+
+func TestExport_HashRuleIsKeyed(t *testing.T) {
+	db, source := newMockDB()
+	_, err := db.Export(context.Background(), &bytes.Buffer{}, &exportCustomer{}, ExportOptions{
+		Anonymize: true,
+		Rules:     map[string]string{"plan": "hash"},
+	})
+	assert.ErrorContains(t, err, "needs a key (export.anonymizeKey)")
+
+	db.config.Export.AnonymizeKey = "s3cret"
+	source.queueRows([]string{"id", "name", "email", "phone", "plan"},
+		[]any{1, "Alice Smith", "alice@corp.com", "555-1234", "pro"},
+	)
+	var buf bytes.Buffer
+	_, err = db.Export(context.Background(), &buf, &exportCustomer{}, ExportOptions{
+		Anonymize: true,
+		Rules:     map[string]string{"plan": "hash"},
+	})
+	require.NoError(t, err)
+	var row map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &row))
+	assert.Equal(t, "1369e36b168f0a5acbf03be541484ba00756a5a9a0c2dbb97a14b358c97edf05", row["plan"])
+	assert.NotEqual(t, anonymizeDigest("pro"), row["plan"], "not a plain SHA-256")
+}
+
+func TestExport_UnknownRule(t *testing.T) {
+	db, _ := newMockDB()
+	_, err := db.Export(context.Background(), &bytes.Buffer{}, &exportCustomer{}, ExportOptions{
+		Anonymize: true,
+		Rules:     map[string]string{"plan": "scramble"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown anonymization rule 'scramble'")
+}
+
+func TestExport_WithoutAnonymize(t *testing.T) {
+	db, source := newMockDB()
+	source.queueRows([]string{"id", "name", "email", "phone", "plan"},
+		[]any{1, "Alice Smith", "alice@corp.com", "555-1234", "pro"},
+	)
+
+	var buf bytes.Buffer
+	_, err := db.Export(context.Background(), &buf, &exportCustomer{}, ExportOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "alice@corp.com")
+}
+
+func TestExport_SkipsSoftDeletedRowsUnlessUnscoped(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	_, err := db.Export(ctx, &bytes.Buffer{}, &softNote{}, ExportOptions{Condition: map[string]any{"title": "draft"}})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT `id`, `title`, `deleted_at` FROM `soft_notes` WHERE `title` = ? AND `deleted_at` IS NULL", source.lastStatement().SQL)
+
+	_, err = db.Export(ctx, &bytes.Buffer{}, &softNote{}, ExportOptions{Unscoped: true})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT `id`, `title`, `deleted_at` FROM `soft_notes`", source.lastStatement().SQL)
+}
+
+func TestExport_AppliesPolicies(t *testing.T) {
+	db, source := newMockDB()
+	var ops []string
+	db.UsePolicy(func(ctx context.Context, op string, model *schema.Model, cond *Expr) error {
+		ops = append(ops, op)
+		*cond = And(*cond, Eq("plan", "free"))
+		MaskColumns(ctx, "email")
+		return nil
+	})
+	source.queueRows([]string{"id", "name", "phone", "plan"}, []any{1, "Bob Jones", "555-9876", "free"})
+
+	var buf bytes.Buffer
+	count, err := db.Export(context.Background(), &buf, &exportCustomer{}, ExportOptions{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	assert.Equal(t, []string{"Export"}, ops)
+	assert.Equal(t, "SELECT `id`, `name`, `phone`, `plan` FROM `export_customers` WHERE `plan` = ?", source.lastStatement().SQL)
+
+	var row map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &row))
+	assert.Nil(t, row["email"], "masked columns are exported as null")
+	assert.Equal(t, "Bob Jones", row["name"])
+}
@@ -0,0 +1,216 @@
+package typegorm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Typed Conditions ---
+
+// Expr is a typed condition, accepted wherever a condition is (Find, FindFirst,
+// Chain.Where, Chain.Or, ...). Unlike map keys with an operator suffix ("age >="),
+// operators cannot be misspelled, and groups can be nested:
+//
+//	db.Find(ctx, &users, typegorm.Or(
+//		typegorm.And(typegorm.Gte("age", 18), typegorm.Like("email", "%@example.com")),
+//		typegorm.In("role", []string{"admin", "owner"}),
+//	))
+//	db.Model(&User{}).Where(typegorm.Between("age", 18, 30)).Delete(ctx)
+//
+// Groups also take map and struct pointer conditions, whose entries are ANDed:
+//
+//	db.Find(ctx, &users, typegorm.Or(map[string]any{"age <": 18}, map[string]any{"age >": 65}))
+//
+// Columns are given by column or Go field name.
+type Expr struct {
+	op     string // Operator of a comparison, "and", "or", "not" for groups, "sql" or "cond"
+	column string
+	values []any // Compared values ("sql": the SQL and its arguments; "cond": the map or struct pointer condition)
+	exprs  []Expr
+}
+
+// Eq matches rows whose column equals value (IS NULL for nil).
+func Eq(column string, value any) Expr {
+	if value == nil {
+		return IsNull(column)
+	}
+	return Expr{op: "=", column: column, values: []any{value}}
+}
+
+// Neq matches rows whose column differs from value (IS NOT NULL for nil).
+func Neq(column string, value any) Expr {
+	if value == nil {
+		return IsNotNull(column)
+	}
+	return Expr{op: "<>", column: column, values: []any{value}}
+}
+
+// Gt matches rows whose column is greater than value.
+func Gt(column string, value any) Expr { return Expr{op: ">", column: column, values: []any{value}} }
+
+// Gte matches rows whose column is greater than or equal to value.
+func Gte(column string, value any) Expr { return Expr{op: ">=", column: column, values: []any{value}} }
+
+// Lt matches rows whose column is less than value.
+func Lt(column string, value any) Expr { return Expr{op: "<", column: column, values: []any{value}} }
+
+// Lte matches rows whose column is less than or equal to value.
+func Lte(column string, value any) Expr { return Expr{op: "<=", column: column, values: []any{value}} }
+
+// Like matches rows whose column matches the LIKE pattern.
+func Like(column string, pattern string) Expr {
+	return Expr{op: "like", column: column, values: []any{pattern}}
+}
+
+// In matches rows whose column is one of values (a slice); an empty slice matches nothing.
+func In(column string, values any) Expr {
+	return Expr{op: "in", column: column, values: []any{values}}
+}
+
+// NotIn matches rows whose column is none of values (a slice); an empty slice matches every row.
+func NotIn(column string, values any) Expr {
+	return Expr{op: "not in", column: column, values: []any{values}}
+}
+
+// Between matches rows whose column is between low and high, inclusive.
+func Between(column string, low, high any) Expr {
+	return Expr{op: "between", column: column, values: []any{low, high}}
+}
+
+// IsNull matches rows whose column is NULL.
+func IsNull(column string) Expr { return Expr{op: "is null", column: column} }
+
+// IsNotNull matches rows whose column is not NULL.
+func IsNotNull(column string) Expr { return Expr{op: "is not null", column: column} }
+
+// And matches rows matching every condition (an Expr, map or struct pointer; without
+// any, it adds no condition).
+func And(conds ...any) Expr { return Expr{op: "and", exprs: toExprs(conds)} }
+
+// Or matches rows matching at least one condition (an Expr, map or struct pointer;
+// without any, it adds no condition).
+func Or(conds ...any) Expr { return Expr{op: "or", exprs: toExprs(conds)} }
+
+// Not matches rows not matching cond (an Expr, map or struct pointer).
+func Not(cond any) Expr { return Expr{op: "not", exprs: toExprs([]any{cond})} }
+
+func toExprs(conds []any) []Expr {
+	exprs := make([]Expr, len(conds))
+	for i, cond := range conds {
+		if expr, ok := cond.(Expr); ok {
+			exprs[i] = expr
+		} else {
+			exprs[i] = Expr{op: "cond", values: []any{cond}}
+		}
+	}
+	return exprs
+}
+
+// build renders the expression with BindVar(1) placeholders (numbered later by
+// renumberBindVars); the clause is empty for an empty group.
+func (e Expr) build(dialect common.Dialect, model *schema.Model) (string, []any, error) {
+	switch e.op {
+	case "and", "or":
+		clauses := make([]string, 0, len(e.exprs))
+		var args []any
+		for _, expr := range e.exprs {
+			clause, exprArgs, err := expr.build(dialect, model)
+			if err != nil {
+				return "", nil, err
+			}
+			if clause != "" {
+				clauses = append(clauses, clause)
+				args = append(args, exprArgs...)
+			}
+		}
+		if len(clauses) <= 1 {
+			return strings.Join(clauses, ""), args, nil
+		}
+		return "(" + strings.Join(clauses, " "+strings.ToUpper(e.op)+" ") + ")", args, nil
+	case "not":
+		clause, args, err := e.exprs[0].build(dialect, model)
+		if err != nil || clause == "" {
+			return clause, args, err
+		}
+		return "NOT (" + clause + ")", args, nil
+	case "sql":
+		return rawCondition(dialect, e.values[0].(string), e.values[1:])
+	case "cond":
+		clauses, args, err := buildWhereClause(dialect, model, e.values[0])
+		if err != nil || len(clauses) <= 1 {
+			return strings.Join(clauses, ""), args, err
+		}
+		return "(" + strings.Join(clauses, " AND ") + ")", args, nil
+	case "":
+		return "", nil, fmt.Errorf("empty condition expression (use the constructors, e.g. typegorm.Eq)")
+	}
+
+	field, err := exprField(model, e.column)
+	if err != nil {
+		return "", nil, err
+	}
+	quotedColumn := dialect.Quote(field.DBName)
+	if e.op == "between" {
+		return fmt.Sprintf("%s BETWEEN %s AND %s", quotedColumn, dialect.BindVar(1), dialect.BindVar(1)), e.values, nil
+	}
+	var value reflect.Value
+	if len(e.values) > 0 {
+		value = reflect.ValueOf(e.values[0])
+	}
+	if (e.op == "in" || e.op == "not in") && value.Kind() != reflect.Slice {
+		return "", nil, fmt.Errorf("value for '%s' operator on '%s' must be a slice, got %T", e.op, e.column, e.values[0])
+	}
+	clause, argCount, err := buildOperatorClause(dialect, quotedColumn, e.op, value)
+	if err != nil {
+		return "", nil, fmt.Errorf("error building clause for '%s': %w", e.column, err)
+	}
+	switch {
+	case argCount == 0:
+		return clause, nil, nil
+	case e.op == "in" || e.op == "not in":
+		args := make([]any, value.Len())
+		for i := range args {
+			args[i] = value.Index(i).Interface()
+		}
+		return clause, args, nil
+	}
+	return clause, e.values, nil
+}
+
+// exprField resolves the column of an expression by column or Go field name.
+func exprField(model *schema.Model, column string) (*schema.Field, error) {
+	field, ok := model.GetFieldByDBName(column)
+	if !ok {
+		field, ok = model.GetField(column)
+	}
+	if !ok && model.Type == nil && bareIdentifierRe.MatchString(column) && !strings.Contains(column, ".") {
+		return &schema.Field{DBName: column}, nil // Table without a model (see DB.Table)
+	}
+	if !ok || field.IsIgnored {
+		return nil, fmt.Errorf("invalid column name '%s' in condition for model %s", column, model.Name)
+	}
+	return field, nil
+}
+
+// appendExprSecrets appends the values compared with sensitive columns.
+func appendExprSecrets(secrets []any, model *schema.Model, expr Expr) []any {
+	for _, sub := range expr.exprs {
+		secrets = appendExprSecrets(secrets, model, sub)
+	}
+	if expr.op == "cond" {
+		return appendSensitiveValues(secrets, model, expr.values[0])
+	}
+	if expr.column == "" {
+		return secrets
+	}
+	if field, err := exprField(model, expr.column); err == nil && field.Sensitive {
+		for _, value := range expr.values {
+			secrets = appendSecret(secrets, reflect.ValueOf(value))
+		}
+	}
+	return secrets
+}
@@ -0,0 +1,121 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpr_FindNestedGroups(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	var users []maskUser
+	require.NoError(t, db.Find(ctx, &users, Or(
+		And(Gte("age", 18), Like("Email", "%@example.com")),
+		In("name", []string{"Ana", "Bia"}),
+		Not(Between("age", 60, 70)),
+	), Limit(5)).Error)
+	last := source.lastStatement()
+	assert.Equal(t, "SELECT `id`, `name`, `email`, `age` FROM `mask_users` WHERE "+
+		"((`age` >= ? AND `email` LIKE ?) OR `name` IN (?, ?) OR NOT (`age` BETWEEN ? AND ?)) LIMIT 5", last.SQL)
+	assert.Equal(t, []any{18, "%@example.com", "Ana", "Bia", 60, 70}, last.Args)
+}
+
+func TestExpr_Operators(t *testing.T) {
+	db, _ := newMockDB()
+	model, err := db.GetModel(&maskUser{})
+	require.NoError(t, err)
+	dialect := db.GetDataSource().Dialect()
+
+	cases := []struct {
+		expr Expr
+		sql  string
+		args []any
+	}{
+		{Eq("name", "Ana"), "`name` = ?", []any{"Ana"}},
+		{Eq("name", nil), "`name` IS NULL", nil},
+		{Neq("age", 3), "`age` <> ?", []any{3}},
+		{Neq("name", nil), "`name` IS NOT NULL", nil},
+		{Gt("age", 1), "`age` > ?", []any{1}},
+		{Lt("age", 1), "`age` < ?", []any{1}},
+		{Lte("age", 1), "`age` <= ?", []any{1}},
+		{In("id", []uint{}), "1 = 0", nil},
+		{NotIn("id", []uint{4}), "`id` NOT IN (?)", []any{uint(4)}},
+		{And(), "", nil},
+		{And(Eq("age", 1)), "`age` = ?", []any{1}},
+		{Not(Or()), "", nil},
+	}
+	for _, tc := range cases {
+		sql, args, err := tc.expr.build(dialect, model)
+		require.NoError(t, err, tc.sql)
+		assert.Equal(t, tc.sql, sql)
+		assert.Equal(t, tc.args, args, tc.sql)
+	}
+
+	_, _, err = Eq("nope", 1).build(dialect, model)
+	assert.ErrorContains(t, err, "invalid column name 'nope'")
+	_, _, err = In("id", 4).build(dialect, model)
+	assert.ErrorContains(t, err, "must be a slice")
+	_, _, err = Expr{}.build(dialect, model)
+	assert.Error(t, err)
+}
+
+func TestExpr_FindFirstAndChain(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	source.queueRows([]string{"id", "name", "email", "age"}, []any{uint(1), "Ana", "ana@example.com", 31})
+
+	var user maskUser
+	require.NoError(t, db.FindFirst(ctx, &user, Or(Eq("name", "Ana"), Gt("age", 30))).Error)
+	assert.Contains(t, source.lastStatement().SQL, "WHERE (`name` = ? OR `age` > ?) LIMIT 1")
+
+	require.NoError(t, db.Model(&maskUser{}).Where(Eq("age", 1)).Or(In("id", []int{2, 3})).Delete(ctx).Error)
+	last := source.lastStatement()
+	assert.Equal(t, "DELETE FROM `mask_users` WHERE ((`age` = ?) OR (`id` IN (?, ?)))", last.SQL)
+	assert.Equal(t, []any{1, 2, 3}, last.Args)
+}
+
+func TestExpr_RedactsSensitiveValues(t *testing.T) {
+	db, _ := newMockDB()
+	model, err := db.GetModel(&secretAccount{})
+	require.NoError(t, err)
+
+	cond := Or(Eq("email", "a@x.io"), In("Token", []string{"t1", "t2"}))
+	assert.Equal(t, []any{"a@x.io", redactedValue, redactedValue}, redactArgs(model, []any{"a@x.io", "t1", "t2"}, cond))
+}
+
+func TestExpr_GroupsOfMapConditions(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	var users []maskUser
+	require.NoError(t, db.Find(ctx, &users, Or(map[string]any{"age <": 18}, map[string]any{"age >": 65})).Error)
+	last := source.lastStatement()
+	assert.Equal(t, "SELECT `id`, `name`, `email`, `age` FROM `mask_users` WHERE (`age` < ? OR `age` > ?)", last.SQL)
+	assert.Equal(t, []any{18, 65}, last.Args)
+
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Find(ctx, &users, And(
+		&maskUser{Name: "Ana", Age: 30},
+		Or(map[string]any{"email like": "%@x.io"}, Not(map[string]any{"id in": []int{1, 2}})),
+	)).Error)
+	require.NoError(t, tx.Commit())
+	assert.Contains(t, sqlOf(source.Statements()), "SELECT `id`, `name`, `email`, `age` FROM `mask_users` WHERE "+
+		"((`name` = ? AND `age` = ?) AND (`email` LIKE ? OR NOT (`id` IN (?, ?))))")
+
+	assert.Error(t, db.Find(ctx, &users, Or(map[string]any{"nope": 1}, Eq("age", 1))).Error)
+	assert.Error(t, db.Find(ctx, &users, Or("age > 1")).Error, "SQL strings are only accepted by Chain.Where")
+}
+
+func TestExpr_RedactsSensitiveMapConditions(t *testing.T) {
+	db, _ := newMockDB()
+	model, err := db.GetModel(&secretAccount{})
+	require.NoError(t, err)
+
+	cond := Or(map[string]any{"password": "hunter2"}, Eq("email", "a@x.io"))
+	assert.Equal(t, []any{redactedValue, "a@x.io"}, redactArgs(model, []any{"hunter2", "a@x.io"}, cond))
+}
@@ -0,0 +1,199 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+)
+
+// --- Primary Failover ---
+
+// With database.failover, a statement failing because the primary is gone or was
+// demoted (read-only) makes the DB reconnect to the new primary among the candidates
+// (database.hosts, or a DNS name resolved again), once for all the statements failing
+// at the same time. Failed reads (SELECT) are run again on the new primary; writes
+// return their error, as they may have been applied.
+
+// FailoverEventKind is the step of a failover reported to the FailoverListener.
+type FailoverEventKind string
+
+const (
+	FailoverStarted   FailoverEventKind = "started"   // A statement failed: the primary is lost
+	FailoverCompleted FailoverEventKind = "completed" // Reconnected to a new primary
+	FailoverFailed    FailoverEventKind = "failed"    // No primary could be reached
+	FailoverReplayed  FailoverEventKind = "replayed"  // A failed read ran again on the new primary
+)
+
+// FailoverEvent describes a step of a failover.
+type FailoverEvent struct {
+	Kind     FailoverEventKind
+	Cause    error         // Error of the statement that detected the loss
+	Primary  string        // Completed: address of the new primary
+	Duration time.Duration // Completed, Failed: time spent reconnecting
+	Query    string        // Replayed: the read run again
+	Err      error         // Failed: why; Replayed: the error of the replay, if any
+}
+
+// FailoverListener receives the failover events, e.g. to export metrics or alert.
+// It runs synchronously on the goroutine of the failing statement.
+type FailoverListener func(FailoverEvent)
+
+// UseFailoverListener sets the listener of failover events (nil removes it).
+// Call it before the DB is shared between goroutines.
+func (db *DB) UseFailoverListener(listener FailoverListener) {
+	if db.failover != nil {
+		db.failover.listener = listener
+	}
+}
+
+// PrimaryFailovers returns how many times the DB reconnected to a new primary.
+func (db *DB) PrimaryFailovers() int64 {
+	if db.failover == nil {
+		return 0
+	}
+	return db.failover.count.Load()
+}
+
+// primaryFailover coordinates the failovers of the primary DataSource.
+type primaryFailover struct {
+	source   common.PrimaryFailoverer
+	listener FailoverListener
+
+	mu    sync.Mutex
+	last  time.Time // End of the last failover
+	count atomic.Int64
+}
+
+// newPrimaryFailover returns the failover of ds; nil when database.failover is off or
+// the dialect cannot fail over.
+func newPrimaryFailover(ds common.DataSource, cfg config.DatabaseConfig) *primaryFailover {
+	if !cfg.Failover {
+		return nil
+	}
+	failoverer, ok := ds.(common.PrimaryFailoverer)
+	if !ok {
+		fmt.Printf("Warning: dialect '%s' cannot fail over; database.failover is ignored\n", cfg.Dialect)
+		return nil
+	}
+	return &primaryFailover{source: failoverer}
+}
+
+func (f *primaryFailover) emit(event FailoverEvent) {
+	if f.listener != nil {
+		f.listener(event)
+	}
+}
+
+// recover reconnects to a new primary after cause, reported by a statement started at
+// started. A failover completed since then is reused. It reports whether a primary is
+// available.
+func (f *primaryFailover) recover(ctx context.Context, cause error, started time.Time) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.last.After(started) {
+		return true // Another statement already failed over
+	}
+	fmt.Printf("Warning: primary lost (%v), failing over...\n", cause)
+	f.emit(FailoverEvent{Kind: FailoverStarted, Cause: cause})
+	begin := time.Now()
+	primary, err := f.source.Failover(ctx)
+	if err != nil {
+		fmt.Printf("Warning: failover failed: %v\n", err)
+		f.emit(FailoverEvent{Kind: FailoverFailed, Cause: cause, Duration: time.Since(begin), Err: err})
+		return false
+	}
+	f.last = time.Now()
+	f.count.Add(1)
+	fmt.Printf("Failed over to the primary at %s.\n", primary)
+	f.emit(FailoverEvent{Kind: FailoverCompleted, Cause: cause, Primary: primary, Duration: f.last.Sub(begin)})
+	return true
+}
+
+// primarySource returns the primary DataSource, failing over when the primary is lost.
+func (db *DB) primarySource() common.DataSource {
+	if db.failover == nil {
+		return db.source
+	}
+	return &failoverSource{DataSource: db.source, failover: db.failover}
+}
+
+// failoverSource runs the statements of the primary, failing over when they report
+// that the primary is lost.
+type failoverSource struct {
+	common.DataSource
+	failover *primaryFailover
+}
+
+// lost fails over when err means the primary is lost; it reports whether the
+// statement can be retried on a new primary.
+func (s *failoverSource) lost(ctx context.Context, err error, started time.Time) bool {
+	if err == nil || ctx.Err() != nil || !s.failover.source.IsPrimaryLost(err) {
+		return false
+	}
+	return s.failover.recover(ctx, err, started)
+}
+
+// Exec runs a write; it is not replayed after a failover.
+func (s *failoverSource) Exec(ctx context.Context, query string, args ...any) (common.Result, error) {
+	started := time.Now()
+	result, err := s.DataSource.Exec(ctx, query, args...)
+	if err != nil {
+		s.lost(ctx, err, started)
+	}
+	return result, err
+}
+
+// Query runs a query, replayed once on the new primary when it is a read.
+func (s *failoverSource) Query(ctx context.Context, query string, args ...any) (common.Rows, error) {
+	started := time.Now()
+	rows, err := s.DataSource.Query(ctx, query, args...)
+	if err == nil || !s.lost(ctx, err, started) || !isReadStatement(query) {
+		return rows, err
+	}
+	rows, err = s.DataSource.Query(ctx, query, args...)
+	s.failover.emit(FailoverEvent{Kind: FailoverReplayed, Query: query, Err: err})
+	return rows, err
+}
+
+// QueryRow runs through Query so that the loss surfaces before the row is scanned.
+func (s *failoverSource) QueryRow(ctx context.Context, query string, args ...any) common.RowScanner {
+	rows, err := s.Query(ctx, query, args...)
+	return &firstRowScanner{rows: rows, err: err}
+}
+
+// BeginTx starts a transaction, again on the new primary after a failover.
+func (s *failoverSource) BeginTx(ctx context.Context, opts any) (common.Tx, error) {
+	started := time.Now()
+	tx, err := s.DataSource.BeginTx(ctx, opts)
+	if err == nil || !s.lost(ctx, err, started) {
+		return tx, err
+	}
+	return s.DataSource.BeginTx(ctx, opts)
+}
+
+// isReadStatement reports whether query only reads, so that it can run twice.
+func isReadStatement(query string) bool {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return false
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "SELECT", "SHOW", "EXPLAIN", "DESCRIBE":
+		return true
+	case "WITH": // Unless the CTE feeds a write
+		for _, field := range fields[1:] {
+			switch strings.ToUpper(field) {
+			case "INSERT", "UPDATE", "DELETE", "REPLACE":
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
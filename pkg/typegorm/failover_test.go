@@ -0,0 +1,71 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+var errReadOnly = errors.New("Error 1290: The MySQL server is running with the --read-only option")
+
+// failoverMockSource is a mock primary that loses its role until Failover is called.
+type failoverMockSource struct {
+	*mockSource
+	failovers int
+}
+
+func (s *failoverMockSource) IsPrimaryLost(err error) bool { return errors.Is(err, errReadOnly) }
+func (s *failoverMockSource) Failover(ctx context.Context) (string, error) {
+	s.failovers++
+	s.queryErr, s.execErr = nil, nil
+	return "db2:3306", nil
+}
+
+func TestFailover_ReplaysReadsNotWrites(t *testing.T) {
+	source := &failoverMockSource{mockSource: newMockSource()}
+	cfg := config.NewDefaultConfig()
+	cfg.Database.Failover = true
+	db := NewDB(source, schema.NewParser(nil), cfg)
+	db.failover = newPrimaryFailover(source, cfg.Database)
+	var events []FailoverEvent
+	db.UseFailoverListener(func(event FailoverEvent) { events = append(events, event) })
+	ctx := context.Background()
+
+	source.queryErr = errReadOnly
+	source.queueRows([]string{"id", "nome"}, []any{uint(7), "Ana"})
+	var autores []preloadAutor
+	require.NoError(t, db.Find(ctx, &autores).Error, "The read is replayed on the new primary")
+	require.Len(t, autores, 1)
+	assert.Equal(t, 1, source.failovers)
+	assert.Equal(t, int64(1), db.PrimaryFailovers())
+	kinds := make([]FailoverEventKind, len(events))
+	for i, event := range events {
+		kinds[i] = event.Kind
+	}
+	assert.Equal(t, []FailoverEventKind{FailoverStarted, FailoverCompleted, FailoverReplayed}, kinds)
+	assert.Equal(t, "db2:3306", events[1].Primary)
+	assert.ErrorIs(t, events[0].Cause, errReadOnly)
+
+	source.execErr = errReadOnly
+	err := db.Create(ctx, &preloadAutor{ID: 8, Nome: "Bia"}).Error
+	assert.ErrorIs(t, err, errReadOnly, "Writes are not replayed")
+	assert.Equal(t, 2, source.failovers, "But the DB fails over for the next ones")
+	require.NoError(t, db.Create(ctx, &preloadAutor{ID: 9, Nome: "Cris"}).Error)
+
+	source.queryErr = errors.New("syntax error")
+	assert.Error(t, db.Find(ctx, &autores).Error)
+	assert.Equal(t, 2, source.failovers, "Other errors do not fail over")
+}
+
+func TestIsReadStatement(t *testing.T) {
+	assert.True(t, isReadStatement("SELECT * FROM t"))
+	assert.True(t, isReadStatement("  with x AS (SELECT 1) SELECT * FROM x"))
+	assert.False(t, isReadStatement("WITH x AS (SELECT 1) DELETE FROM t"))
+	assert.False(t, isReadStatement("UPDATE t SET a = 1"))
+}
@@ -0,0 +1,124 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Field Interceptors ---
+
+// FieldInterceptor transforms the value of one column whenever it is written by an
+// insert or an update, e.g. to hash a password or normalize an email. It receives the
+// value as given (struct field or update map entry) and returns the value to store.
+type FieldInterceptor func(ctx context.Context, value any) (any, error)
+
+// FieldInterceptorProvider is implemented by models with per-field interceptors, keyed
+// by Go field or column name:
+//
+//	func (User) FieldInterceptors() map[string]typegorm.FieldInterceptor {
+//		return map[string]typegorm.FieldInterceptor{
+//			"Email":    func(_ context.Context, v any) (any, error) { return strings.ToLower(v.(string)), nil },
+//			"Password": hashPassword,
+//		}
+//	}
+//
+// Interceptors run while the statement is built, so Create, Updates, UpdateIf,
+// UpdatesMask and Chain.Updates all apply them and map-based updates cannot bypass
+// them. On Create the struct field is set to the stored value; interceptors should
+// therefore be idempotent (e.g. leave an already hashed password unchanged).
+type FieldInterceptorProvider interface {
+	FieldInterceptors() map[string]FieldInterceptor
+}
+
+// fieldInterceptorSet is the resolved column -> interceptor map of a model.
+type fieldInterceptorSet struct {
+	byColumn map[string]FieldInterceptor
+	err      error
+}
+
+var fieldInterceptorCache sync.Map // *schema.Model -> *fieldInterceptorSet
+
+// fieldInterceptors returns the model's interceptors by column, nil when it has none.
+func fieldInterceptors(model *schema.Model) (map[string]FieldInterceptor, error) {
+	if model == nil || model.Type == nil {
+		return nil, nil
+	}
+	if cached, ok := fieldInterceptorCache.Load(model); ok {
+		set := cached.(*fieldInterceptorSet)
+		return set.byColumn, set.err
+	}
+
+	set := &fieldInterceptorSet{}
+	if provider, ok := reflect.New(model.Type).Interface().(FieldInterceptorProvider); ok {
+		interceptors := provider.FieldInterceptors()
+		set.byColumn = make(map[string]FieldInterceptor, len(interceptors))
+		for name, interceptor := range interceptors {
+			field, ok := model.GetField(name)
+			if !ok {
+				field, ok = model.GetFieldByDBName(name)
+			}
+			if !ok || field.IsIgnored {
+				set.err = fmt.Errorf("field interceptor: unknown field '%s' for model %s", name, model.Name)
+				break
+			}
+			if interceptor != nil {
+				set.byColumn[field.DBName] = interceptor
+			}
+		}
+		if set.err != nil {
+			set.byColumn = nil
+		}
+	}
+	fieldInterceptorCache.Store(model, set)
+	return set.byColumn, set.err
+}
+
+// interceptValue runs the interceptor registered for column, if any, on value.
+func interceptValue(ctx context.Context, model *schema.Model, column string, value any) (any, error) {
+	interceptors, err := fieldInterceptors(model)
+	if err != nil {
+		return nil, err
+	}
+	interceptor, ok := interceptors[column]
+	if !ok {
+		return value, nil
+	}
+	out, err := interceptor(ctx, value)
+	if err != nil {
+		return nil, fmt.Errorf("field interceptor for %s.%s failed: %w", model.Name, column, err)
+	}
+	return out, nil
+}
+
+// interceptField runs the interceptor of field on the struct field's value and, when the
+// result fits the field, stores it back so the struct matches the inserted row.
+func interceptField(ctx context.Context, model *schema.Model, field *schema.Field, fieldValue reflect.Value) (any, error) {
+	value := fieldValue.Interface()
+	out, err := interceptValue(ctx, model, field.DBName, value)
+	if err != nil {
+		return nil, err
+	}
+	if fieldValue.CanSet() {
+		switch {
+		case out == nil:
+			if isNillable(fieldValue.Kind()) {
+				fieldValue.Set(reflect.Zero(fieldValue.Type()))
+			}
+		case reflect.TypeOf(out).AssignableTo(fieldValue.Type()):
+			fieldValue.Set(reflect.ValueOf(out))
+		}
+	}
+	return out, nil
+}
+
+func isNillable(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return true
+	}
+	return false
+}
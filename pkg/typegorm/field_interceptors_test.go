@@ -0,0 +1,96 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type interceptedUser struct {
+	ID       uint `typegorm:"primaryKey;autoIncrement"`
+	Email    string
+	Password string
+}
+
+func (interceptedUser) FieldInterceptors() map[string]FieldInterceptor {
+	return map[string]FieldInterceptor{
+		"Email": func(_ context.Context, v any) (any, error) {
+			s, ok := v.(string)
+			if !ok {
+				return nil, errors.New("email must be a string")
+			}
+			return strings.ToLower(s), nil
+		},
+		"password": func(_ context.Context, v any) (any, error) {
+			s, _ := v.(string)
+			if strings.HasPrefix(s, "hashed:") {
+				return s, nil
+			}
+			return "hashed:" + s, nil
+		},
+	}
+}
+
+type badInterceptorUser struct {
+	ID   uint `typegorm:"primaryKey;autoIncrement"`
+	Name string
+}
+
+func (badInterceptorUser) FieldInterceptors() map[string]FieldInterceptor {
+	return map[string]FieldInterceptor{"Nickname": func(_ context.Context, v any) (any, error) { return v, nil }}
+}
+
+func TestFieldInterceptorsOnCreate(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	user := &interceptedUser{Email: "Ana@Example.COM", Password: "secret"}
+
+	require.NoError(t, db.Create(ctx, user).Error)
+	insert := source.Statements()[0]
+	assert.Contains(t, insert.SQL, "INSERT INTO `intercepted_users`")
+	assert.Equal(t, []any{"ana@example.com", "hashed:secret"}, insert.Args)
+	assert.Equal(t, "ana@example.com", user.Email, "struct reflects the stored value")
+	assert.Equal(t, "hashed:secret", user.Password)
+}
+
+func TestFieldInterceptorsOnUpdates(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	user := &interceptedUser{ID: 7}
+
+	require.NoError(t, db.Updates(ctx, user, map[string]any{"email": "BOB@X.IO", "password": "pw"}).Error)
+	assert.ElementsMatch(t, []any{"bob@x.io", "hashed:pw", uint(7)}, source.lastStatement().Args)
+
+	require.NoError(t, db.UpdateIf(ctx, user, map[string]any{"password": "pw2"}, map[string]any{"email": "bob@x.io"}).Error)
+	assert.Equal(t, []any{"hashed:pw2", uint(7), "bob@x.io"}, source.lastStatement().Args, "conditions are not intercepted")
+
+	require.NoError(t, db.Model(&interceptedUser{}).AllRows().Update(ctx, "Email", "ALL@X.IO").Error)
+	assert.Equal(t, []any{"all@x.io"}, source.lastStatement().Args)
+
+	user.Email = "Mask@X.IO"
+	require.NoError(t, db.UpdatesMask(ctx, user, []string{"Email"}).Error)
+	assert.Equal(t, []any{"mask@x.io", uint(7)}, source.lastStatement().Args)
+
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Updates(ctx, user, map[string]any{"email": "TX@X.IO"}).Error)
+	assert.Equal(t, []any{"tx@x.io", uint(7)}, source.lastStatement().Args)
+	require.NoError(t, tx.Commit())
+}
+
+func TestFieldInterceptorsErrors(t *testing.T) {
+	db, _ := newMockDB()
+	ctx := context.Background()
+
+	res := db.Updates(ctx, &interceptedUser{ID: 1}, map[string]any{"email": 42})
+	require.Error(t, res.Error)
+	assert.Contains(t, res.Error.Error(), "email must be a string")
+
+	res = db.Create(ctx, &badInterceptorUser{Name: "x"})
+	require.Error(t, res.Error)
+	assert.Contains(t, res.Error.Error(), "unknown field 'Nickname'")
+}
@@ -0,0 +1,168 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+)
+
+// --- Permission management ---
+
+// Privilege is a SQL privilege name used in GRANT/REVOKE statements.
+type Privilege string
+
+const (
+	PrivilegeSelect     Privilege = "SELECT"
+	PrivilegeInsert     Privilege = "INSERT"
+	PrivilegeUpdate     Privilege = "UPDATE"
+	PrivilegeDelete     Privilege = "DELETE"
+	PrivilegeTruncate   Privilege = "TRUNCATE"
+	PrivilegeReferences Privilege = "REFERENCES"
+	PrivilegeUsage      Privilege = "USAGE"
+	PrivilegeAll        Privilege = "ALL PRIVILEGES"
+)
+
+// grantPrivileges is the allow-list of the privileges accepted by GrantSQL/RevokeSQL,
+// which are written verbatim in the statement.
+var grantPrivileges = map[Privilege]bool{
+	PrivilegeSelect: true, PrivilegeInsert: true, PrivilegeUpdate: true, PrivilegeDelete: true,
+	PrivilegeTruncate: true, PrivilegeReferences: true, PrivilegeUsage: true, PrivilegeAll: true,
+}
+
+// Grant describes the privileges given to (or taken from) roles on a table or sequence.
+// Exactly one of Model, Table or Sequence must be set.
+type Grant struct {
+	Privileges      []Privilege
+	Model           any      // Model value (e.g., &User{}); its table is used
+	Table           string   // Raw table name, alternative to Model
+	Sequence        string   // Sequence name (Postgres only)
+	Roles           []string // Grantees; on MySQL, user@host accounts are quoted as 'user'@'host'
+	WithGrantOption bool     // Only used by GRANT
+}
+
+// Grant executes a GRANT statement so permission changes can live in migrations
+// next to the schema they protect.
+func (m *Migrator) Grant(ctx context.Context, g Grant) error {
+	if err := m.resolveGrantTable(&g); err != nil {
+		return err
+	}
+	stmt, err := GrantSQL(m.db.source.Dialect(), g)
+	if err != nil {
+		return err
+	}
+	return m.exec(ctx, stmt)
+}
+
+// Revoke executes the REVOKE statement matching the given grant.
+func (m *Migrator) Revoke(ctx context.Context, g Grant) error {
+	if err := m.resolveGrantTable(&g); err != nil {
+		return err
+	}
+	stmt, err := RevokeSQL(m.db.source.Dialect(), g)
+	if err != nil {
+		return err
+	}
+	return m.exec(ctx, stmt)
+}
+
+func (m *Migrator) resolveGrantTable(g *Grant) error {
+	if g.Model == nil {
+		return nil
+	}
+	if g.Table != "" || g.Sequence != "" {
+		return fmt.Errorf("grant: set only one of a model, table or sequence")
+	}
+	parsed, err := m.db.GetModel(g.Model)
+	if err != nil {
+		return fmt.Errorf("migrator: failed to parse schema for type %T: %w", g.Model, err)
+	}
+	g.Table = parsed.QualifiedTableName()
+	return nil
+}
+
+// GrantSQL builds a GRANT statement for the dialect. Useful inside Go migrations,
+// which only receive a *sql.DB.
+func GrantSQL(dialect common.Dialect, g Grant) (string, error) {
+	object, privileges, roles, err := grantParts(dialect, g)
+	if err != nil {
+		return "", err
+	}
+	stmt := fmt.Sprintf("GRANT %s ON %s TO %s", privileges, object, roles)
+	if g.WithGrantOption {
+		stmt += " WITH GRANT OPTION"
+	}
+	return stmt, nil
+}
+
+// RevokeSQL builds a REVOKE statement for the dialect.
+func RevokeSQL(dialect common.Dialect, g Grant) (string, error) {
+	object, privileges, roles, err := grantParts(dialect, g)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("REVOKE %s ON %s FROM %s", privileges, object, roles), nil
+}
+
+func grantParts(dialect common.Dialect, g Grant) (object, privileges, roles string, err error) {
+	if len(g.Privileges) == 0 {
+		return "", "", "", fmt.Errorf("grant: at least one privilege is required")
+	}
+	if len(g.Roles) == 0 {
+		return "", "", "", fmt.Errorf("grant: at least one role is required")
+	}
+
+	switch {
+	case g.Table != "" && g.Sequence != "":
+		return "", "", "", fmt.Errorf("grant: set either a table or a sequence, not both")
+	case g.Table != "":
+		object = "TABLE " + quoteQualified(dialect, g.Table)
+	case g.Sequence != "":
+		if dialect.Name() != "postgres" {
+			return "", "", "", fmt.Errorf("grant: sequences are not supported by %s: %w", dialect.Name(), ErrUnsupportedDialect)
+		}
+		object = "SEQUENCE " + dialect.Quote(g.Sequence)
+	default:
+		return "", "", "", fmt.Errorf("grant: a model, table or sequence is required")
+	}
+
+	privs := make([]string, len(g.Privileges))
+	for i, p := range g.Privileges {
+		privilege := Privilege(strings.ToUpper(strings.TrimSpace(string(p))))
+		if !grantPrivileges[privilege] {
+			return "", "", "", fmt.Errorf("grant: unknown privilege %q", string(p))
+		}
+		privs[i] = string(privilege)
+	}
+
+	grantees := make([]string, len(g.Roles))
+	for i, role := range g.Roles {
+		switch {
+		case role == "":
+			return "", "", "", fmt.Errorf("grant: empty role name")
+		case strings.EqualFold(role, "PUBLIC"):
+			grantees[i] = "PUBLIC"
+		case dialect.Name() == "mysql" && strings.Contains(role, "@"):
+			grantees[i] = quoteMySQLAccount(role)
+		default:
+			grantees[i] = dialect.Quote(role)
+		}
+	}
+	return object, strings.Join(privs, ", "), strings.Join(grantees, ", "), nil
+}
+
+// quoteMySQLAccount quotes the user and host parts of a MySQL user@host account as
+// string literals, dropping the quotes they may already have ('app'@'%', `app`@localhost).
+// The host is after the last '@', since user names may contain one.
+func quoteMySQLAccount(account string) string {
+	at := strings.LastIndex(account, "@")
+	return quoteAccountPart(account[:at]) + "@" + quoteAccountPart(account[at+1:])
+}
+
+func quoteAccountPart(part string) string {
+	if len(part) >= 2 && strings.ContainsRune("'`\"", rune(part[0])) && part[len(part)-1] == part[0] {
+		part = part[1 : len(part)-1]
+	}
+	return "'" + strings.ReplaceAll(part, "'", "''") + "'"
+}
@@ -0,0 +1,99 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrantSQLAndRevokeSQL(t *testing.T) {
+	mysql := &mockDialect{name: "mysql"}
+	postgres := &mockDialect{name: "postgres"}
+	tests := []struct {
+		name      string
+		dialect   common.Dialect
+		grant     Grant
+		grantSQL  string
+		revokeSQL string
+		err       string
+	}{
+		{
+			name:      "mysql table with user@host accounts",
+			dialect:   mysql,
+			grant:     Grant{Privileges: []Privilege{PrivilegeSelect, "insert"}, Table: "app.orders", Roles: []string{"report@%", "'etl'@'10.0.0.%'", "`o'brien`@localhost"}},
+			grantSQL:  "GRANT SELECT, INSERT ON TABLE `app`.`orders` TO 'report'@'%', 'etl'@'10.0.0.%', 'o''brien'@'localhost'",
+			revokeSQL: "REVOKE SELECT, INSERT ON TABLE `app`.`orders` FROM 'report'@'%', 'etl'@'10.0.0.%', 'o''brien'@'localhost'",
+		},
+		{
+			name:      "mysql user containing @",
+			dialect:   mysql,
+			grant:     Grant{Privileges: []Privilege{PrivilegeAll}, Table: "orders", Roles: []string{"ops@corp.example@db1"}, WithGrantOption: true},
+			grantSQL:  "GRANT ALL PRIVILEGES ON TABLE `orders` TO 'ops@corp.example'@'db1' WITH GRANT OPTION",
+			revokeSQL: "REVOKE ALL PRIVILEGES ON TABLE `orders` FROM 'ops@corp.example'@'db1'",
+		},
+		{
+			name:      "postgres roles and public",
+			dialect:   postgres,
+			grant:     Grant{Privileges: []Privilege{PrivilegeUpdate, PrivilegeDelete}, Table: "orders", Roles: []string{"app_rw", "public", "a@b"}},
+			grantSQL:  "GRANT UPDATE, DELETE ON TABLE `orders` TO `app_rw`, PUBLIC, `a@b`",
+			revokeSQL: "REVOKE UPDATE, DELETE ON TABLE `orders` FROM `app_rw`, PUBLIC, `a@b`",
+		},
+		{
+			name:      "postgres sequence",
+			dialect:   postgres,
+			grant:     Grant{Privileges: []Privilege{PrivilegeUsage}, Sequence: "orders_id_seq", Roles: []string{"app_rw"}},
+			grantSQL:  "GRANT USAGE ON SEQUENCE `orders_id_seq` TO `app_rw`",
+			revokeSQL: "REVOKE USAGE ON SEQUENCE `orders_id_seq` FROM `app_rw`",
+		},
+		{name: "sequence on mysql", dialect: mysql, grant: Grant{Privileges: []Privilege{PrivilegeUsage}, Sequence: "s", Roles: []string{"app"}}, err: "sequences are not supported"},
+		{name: "no privilege", dialect: postgres, grant: Grant{Table: "orders", Roles: []string{"app"}}, err: "at least one privilege"},
+		{name: "no role", dialect: postgres, grant: Grant{Privileges: []Privilege{PrivilegeSelect}, Table: "orders"}, err: "at least one role"},
+		{name: "empty role", dialect: postgres, grant: Grant{Privileges: []Privilege{PrivilegeSelect}, Table: "orders", Roles: []string{""}}, err: "empty role"},
+		{name: "no object", dialect: postgres, grant: Grant{Privileges: []Privilege{PrivilegeSelect}, Roles: []string{"app"}}, err: "a model, table or sequence is required"},
+		{name: "table and sequence", dialect: postgres, grant: Grant{Privileges: []Privilege{PrivilegeSelect}, Table: "t", Sequence: "s", Roles: []string{"app"}}, err: "not both"},
+		{name: "unknown privilege", dialect: mysql, grant: Grant{Privileges: []Privilege{"SELECT ON *.* TO evil; --"}, Table: "t", Roles: []string{"app"}}, err: "unknown privilege"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grantSQL, err := GrantSQL(tt.dialect, tt.grant)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				_, err = RevokeSQL(tt.dialect, tt.grant)
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.grantSQL, grantSQL)
+			revokeSQL, err := RevokeSQL(tt.dialect, tt.grant)
+			require.NoError(t, err)
+			assert.Equal(t, tt.revokeSQL, revokeSQL)
+		})
+	}
+}
+
+func TestMigratorGrantAndRevoke(t *testing.T) {
+	db, source := newMockDBWithDialect("postgres")
+	ctx := context.Background()
+	grant := Grant{Privileges: []Privilege{PrivilegeSelect}, Model: &maskUser{}, Roles: []string{"reader"}}
+
+	require.NoError(t, db.Migrator().Grant(ctx, grant))
+	require.NoError(t, db.Migrator().Revoke(ctx, grant))
+	stmts := source.Statements()
+	require.Len(t, stmts, 2)
+	assert.Equal(t, "GRANT SELECT ON TABLE `mask_users` TO `reader`", stmts[0].SQL)
+	assert.Equal(t, "REVOKE SELECT ON TABLE `mask_users` FROM `reader`", stmts[1].SQL)
+
+	grant.Table = "other"
+	assert.ErrorContains(t, db.Migrator().Grant(ctx, grant), "only one of a model, table or sequence")
+	grant.Table, grant.Sequence = "", "mask_users_id_seq"
+	assert.ErrorContains(t, db.Migrator().Revoke(ctx, grant), "only one of a model, table or sequence")
+	assert.ErrorContains(t, db.Migrator().Grant(ctx, Grant{Privileges: []Privilege{PrivilegeSelect}, Model: 42, Roles: []string{"reader"}}), "failed to parse schema")
+
+	source.execErr = errors.New("permission denied")
+	assert.ErrorContains(t, db.Migrator().Grant(ctx, Grant{Privileges: []Privilege{PrivilegeSelect}, Table: "orders", Roles: []string{"reader"}}), "permission denied")
+	assert.Len(t, source.Statements(), 3, "invalid grants are not executed")
+}
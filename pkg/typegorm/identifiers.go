@@ -0,0 +1,79 @@
+package typegorm
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Identifier Quoting ---
+
+// bareIdentifierRe matches an unquoted (optionally table-qualified) identifier.
+var bareIdentifierRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*(\.[A-Za-z_][A-Za-z0-9_$]*)?$`)
+
+// quoteTable quotes the table of model, qualified by its schema when it has one:
+// "accounting"."invoices".
+func quoteTable(dialect common.Dialect, model *schema.Model) string {
+	if model.Schema == "" {
+		return dialect.Quote(model.TableName)
+	}
+	return dialect.Quote(model.Schema) + "." + dialect.Quote(model.TableName)
+}
+
+// quoteQualified quotes a table name given as "table" or "schema.table", each part
+// separately. Other dotted names (e.g. CTE names with dots) are quoted as one identifier.
+func quoteQualified(dialect common.Dialect, name string) string {
+	if !strings.Contains(name, ".") || !bareIdentifierRe.MatchString(name) {
+		return dialect.Quote(name)
+	}
+	schemaName, table := schema.SplitQualifiedName(name)
+	return dialect.Quote(schemaName) + "." + dialect.Quote(table)
+}
+
+// quoteOrderBy quotes the column references of an Order/DefaultOrder clause, so that
+// columns named after reserved words ("group", "order") and case-sensitive names work:
+// model fields (Go or column name) become the quoted column, other reserved words are
+// quoted as-is. Clauses with expressions (function calls, ...) are left untouched.
+//
+//	"group DESC, CreatedAt" -> "`group` DESC, `created_at`"
+func quoteOrderBy(dialect common.Dialect, model *schema.Model, clause string) string {
+	if clause == "" || strings.ContainsAny(clause, "()`\"'[") {
+		return clause
+	}
+	items := strings.Split(clause, ",")
+	for i, item := range items {
+		tokens := strings.Fields(item)
+		if len(tokens) == 0 {
+			continue
+		}
+		tokens[0] = quoteIdentifier(dialect, model, tokens[0])
+		items[i] = strings.Join(tokens, " ")
+	}
+	return strings.Join(items, ", ")
+}
+
+// quoteIdentifier quotes a bare column reference when it names a model field or is a
+// reserved word of the dialect; anything else is returned unchanged.
+func quoteIdentifier(dialect common.Dialect, model *schema.Model, name string) string {
+	if !bareIdentifierRe.MatchString(name) {
+		return name
+	}
+	if table, column, qualified := strings.Cut(name, "."); qualified {
+		dialect = unqualified(dialect) // Already qualified (see Joins)
+		return quoteIdentifier(dialect, nil, table) + "." + quoteIdentifier(dialect, model, column)
+	}
+	if model != nil {
+		if field, ok := model.GetFieldByDBName(name); ok {
+			return dialect.Quote(field.DBName)
+		}
+		if field, ok := model.GetField(name); ok && !field.IsIgnored {
+			return dialect.Quote(field.DBName)
+		}
+	}
+	if common.IsReservedWord(dialect.Name(), name) {
+		return dialect.Quote(name)
+	}
+	return name
+}
@@ -0,0 +1,94 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reservedOrder struct {
+	ID        uint   `typegorm:"primaryKey;autoIncrement"`
+	Group     string `typegorm:"column:group"`
+	CreatedAt int64
+}
+
+func TestQuoteOrderBy(t *testing.T) {
+	db, _ := newMockDBWithDialect("mysql")
+	model, err := db.GetModel(&reservedOrder{})
+	require.NoError(t, err)
+	dialect := db.source.Dialect()
+
+	tests := map[string]string{
+		"group DESC, CreatedAt": "`group` DESC, `created_at`",
+		"created_at asc":        "`created_at` asc",
+		"key":                   "`key`",                   // Reserved word, not a model column
+		"reserved_orders.group": "reserved_orders.`group`", // Table-qualified
+		"score DESC":            "score DESC",              // Unknown and not reserved
+		"FIELD(id, 3, 1, 2)":    "FIELD(id, 3, 1, 2)",      // Expressions are left untouched
+		"`group` DESC":          "`group` DESC",
+		"":                      "",
+	}
+	for clause, expected := range tests {
+		assert.Equal(t, expected, quoteOrderBy(dialect, model, clause), clause)
+	}
+}
+
+func TestFind_OrderByReservedColumn(t *testing.T) {
+	db, source := newMockDB()
+	var rows []reservedOrder
+	require.NoError(t, db.Find(context.Background(), &rows, Order("group")).Error)
+	assert.Contains(t, source.lastStatement().SQL, "ORDER BY `group`")
+}
+
+func TestPreserveCaseNamingStrategy(t *testing.T) {
+	parser := schema.NewParser(schema.PreserveCaseNamingStrategy{})
+	model, err := parser.Parse(&reservedOrder{})
+	require.NoError(t, err)
+	assert.Equal(t, "reservedOrders", model.TableName)
+	_, ok := model.GetFieldByDBName("CreatedAt")
+	assert.True(t, ok)
+	_, ok = model.GetFieldByDBName("group")
+	assert.True(t, ok, "explicit column tags are kept")
+}
+
+type qualifiedInvoice struct {
+	_      struct{} `typegorm:"table:accounting.invoices"`
+	ID     uint     `typegorm:"primaryKey;autoIncrement"`
+	Number string
+}
+
+func TestQualifiedTable_Statements(t *testing.T) {
+	db, source := newMockDBWithDialect("postgres")
+	ctx := context.Background()
+
+	require.NoError(t, db.AutoMigrate(ctx, &qualifiedInvoice{}))
+	stmts := source.Statements()
+	require.Len(t, stmts, 2)
+	assert.Equal(t, "CREATE SCHEMA IF NOT EXISTS `accounting`", stmts[0].SQL)
+	assert.Contains(t, stmts[1].SQL, "CREATE TABLE IF NOT EXISTS `accounting`.`invoices`")
+
+	require.NoError(t, db.Updates(ctx, &qualifiedInvoice{ID: 1}, map[string]any{"number": "A-1"}).Error)
+	assert.Contains(t, source.lastStatement().SQL, "UPDATE `accounting`.`invoices` SET")
+
+	var invoices []qualifiedInvoice
+	require.NoError(t, db.Find(ctx, &invoices).Error)
+	assert.Contains(t, source.lastStatement().SQL, "FROM `accounting`.`invoices`")
+
+	require.NoError(t, db.Table("accounting.invoices").Where(map[string]any{"number": "A-1"}).Delete(ctx).Error)
+	assert.Contains(t, source.lastStatement().SQL, "DELETE FROM `accounting`.`invoices`")
+}
+
+func TestQualifiedTable_DefaultSchema(t *testing.T) {
+	db, source := newMockDB()
+	db.parser = schema.NewParser(nil, schema.WithDefaultSchema("sales"))
+
+	require.NoError(t, db.Delete(context.Background(), &maskUser{ID: 1}).Error)
+	assert.Contains(t, source.lastStatement().SQL, "`sales`.`mask_users`")
+
+	model, err := db.GetModel(&qualifiedInvoice{})
+	require.NoError(t, err)
+	assert.Equal(t, "accounting", model.Schema, "the model tag wins over the default schema")
+}
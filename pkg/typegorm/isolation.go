@@ -0,0 +1,180 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// --- Isolation Levels ---
+
+// Isolation levels, re-exported from database/sql for convenience.
+//
+// How the dialects treat them:
+//   - MySQL (InnoDB): all four levels are native; the default is REPEATABLE READ.
+//   - Postgres: READ UNCOMMITTED behaves as READ COMMITTED (the default); REPEATABLE READ
+//     is snapshot isolation; SERIALIZABLE is true serializable (SSI) and may abort
+//     transactions with SQLSTATE 40001, which RunInTransaction can retry.
+//   - SQLite: transactions are always SERIALIZABLE; other levels are accepted but not
+//     enforced.
+const (
+	LevelDefault         = sql.LevelDefault
+	LevelReadUncommitted = sql.LevelReadUncommitted
+	LevelReadCommitted   = sql.LevelReadCommitted
+	LevelRepeatableRead  = sql.LevelRepeatableRead
+	LevelSerializable    = sql.LevelSerializable
+)
+
+// txConfig holds the options of BeginWith and RunInTransaction.
+type txConfig struct {
+	options     sql.TxOptions
+	maxAttempts int           // Attempts for RunInTransaction (1 = no retry)
+	backoff     time.Duration // Base delay between attempts, doubled each time
+}
+
+// TxOption defines a function type that modifies txConfig.
+type TxOption func(*txConfig)
+
+// WithIsolation sets the isolation level of the transaction.
+func WithIsolation(level sql.IsolationLevel) TxOption {
+	return func(c *txConfig) { c.options.Isolation = level }
+}
+
+// WithReadOnly starts the transaction in read-only mode (see BeginReadOnly).
+func WithReadOnly() TxOption {
+	return func(c *txConfig) { c.options.ReadOnly = true }
+}
+
+// WithRetry makes RunInTransaction retry the whole function up to maxAttempts times
+// when the database aborts it with a serialization failure or deadlock.
+// Retrying is enabled by default (3 attempts) for Serializable transactions.
+func WithRetry(maxAttempts int, backoff time.Duration) TxOption {
+	return func(c *txConfig) {
+		c.maxAttempts = maxAttempts
+		c.backoff = backoff
+	}
+}
+
+func newTxConfig(opts []TxOption) txConfig {
+	cfg := txConfig{backoff: 10 * time.Millisecond}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	if cfg.maxAttempts <= 0 {
+		cfg.maxAttempts = 1
+		if cfg.options.Isolation == sql.LevelSerializable {
+			cfg.maxAttempts = 3
+		}
+	}
+	return cfg
+}
+
+// BeginWith starts a transaction configured with TxOptions:
+//
+//	tx, err := db.BeginWith(ctx, typegorm.WithIsolation(typegorm.LevelRepeatableRead))
+func (db *DB) BeginWith(ctx context.Context, opts ...TxOption) (*Tx, error) {
+	cfg := newTxConfig(opts)
+	warnIsolation(db.source.Dialect().Name(), cfg.options.Isolation)
+	return db.Begin(ctx, &cfg.options)
+}
+
+// BeginSerializable starts a SERIALIZABLE transaction. Prefer RunInTransaction with
+// WithIsolation(LevelSerializable) to get automatic retries on serialization failures.
+func (db *DB) BeginSerializable(ctx context.Context) (*Tx, error) {
+	return db.BeginWith(ctx, WithIsolation(LevelSerializable))
+}
+
+// RunInTransaction runs fn in a transaction, committing when it returns nil and rolling
+// back when it returns an error or panics (the panic is then re-raised). With WithRetry
+// (or Serializable isolation, which retries 3 times by default) the whole function is
+// re-run when the database reports a serialization failure or deadlock, so fn must be
+// safe to repeat:
+//
+//	err := db.RunInTransaction(ctx, func(tx *typegorm.Tx) error {
+//		...
+//	}, typegorm.WithIsolation(typegorm.LevelSerializable))
+//
+// When ctx carries a transaction (see Transaction and ContextWithTx), fn runs in a
+// savepoint of that transaction instead, without retries.
+func (db *DB) RunInTransaction(ctx context.Context, fn func(tx *Tx) error, opts ...TxOption) error {
+	if outer, ok := TxFromContext(ctx); ok {
+		return outer.Transaction(ctx, func(_ context.Context, tx *Tx) error { return fn(tx) })
+	}
+	cfg := newTxConfig(opts)
+	warnIsolation(db.source.Dialect().Name(), cfg.options.Isolation)
+
+	var err error
+	delay := cfg.backoff
+	for attempt := 1; attempt <= cfg.maxAttempts; attempt++ {
+		err = db.runOnce(ctx, fn, cfg.options)
+		if err == nil || !IsSerializationFailure(err) || attempt == cfg.maxAttempts {
+			break
+		}
+		fmt.Printf("Transaction aborted by serialization failure (attempt %d/%d), retrying: %v\n", attempt, cfg.maxAttempts, err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		delay *= 2
+	}
+	return err
+}
+
+func (db *DB) runOnce(ctx context.Context, fn func(tx *Tx) error, options sql.TxOptions) error {
+	tx, err := db.Begin(ctx, &options)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			_ = tx.Rollback()
+			panic(r)
+		}
+	}()
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// IsSerializationFailure reports whether err is a transient concurrency failure worth
+// retrying: SQLSTATE 40001 (serialization failure) or 40P01 (Postgres deadlock), or
+// MySQL errors 1213 (deadlock) and 1205 (lock wait timeout).
+func IsSerializationFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var stateErr interface{ SQLState() string } // lib/pq and pgx errors
+	if errors.As(err, &stateErr) {
+		switch stateErr.SQLState() {
+		case "40001", "40P01":
+			return true
+		}
+	}
+	// go-sql-driver/mysql formats errors as "Error 1213 (40001): Deadlock found ..."
+	msg := err.Error()
+	for _, marker := range []string{"Error 1213", "Error 1205", "(40001)", "SQLSTATE 40001", "SQLSTATE 40P01", "could not serialize access"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// warnIsolation logs when the dialect does not enforce the requested level as such.
+func warnIsolation(dialectName string, level sql.IsolationLevel) {
+	switch {
+	case level == sql.LevelDefault:
+	case dialectName == "postgres" && level == sql.LevelReadUncommitted:
+		fmt.Println("Note: Postgres runs READ UNCOMMITTED as READ COMMITTED")
+	case (dialectName == "sqlite" || dialectName == "sqlite3") && level != sql.LevelSerializable:
+		fmt.Printf("Note: SQLite transactions are always SERIALIZABLE (requested %s)\n", level)
+	}
+}
@@ -0,0 +1,69 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sqlStateError struct{ state string }
+
+func (e *sqlStateError) Error() string    { return "pq: error " + e.state }
+func (e *sqlStateError) SQLState() string { return e.state }
+
+func TestIsSerializationFailure(t *testing.T) {
+	assert.True(t, IsSerializationFailure(fmt.Errorf("wrapped: %w", &sqlStateError{state: "40001"})))
+	assert.True(t, IsSerializationFailure(errors.New("Error 1213 (40001): Deadlock found when trying to get lock")))
+	assert.False(t, IsSerializationFailure(&sqlStateError{state: "23505"}))
+	assert.False(t, IsSerializationFailure(errors.New("Error 1062 (23000): Duplicate entry")))
+	assert.False(t, IsSerializationFailure(nil))
+}
+
+func TestRunInTransaction_RetriesSerializable(t *testing.T) {
+	db, source := newMockDBWithDialect("postgres")
+
+	attempts := 0
+	err := db.RunInTransaction(context.Background(), func(tx *Tx) error {
+		attempts++
+		if attempts < 3 {
+			return &sqlStateError{state: "40001"}
+		}
+		return nil
+	}, WithIsolation(LevelSerializable), WithRetry(3, time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, []string{"BEGIN", "ROLLBACK", "BEGIN", "ROLLBACK", "BEGIN", "COMMIT"}, sqlOf(source.Statements()))
+
+	// Other errors are not retried
+	attempts = 0
+	boom := errors.New("boom")
+	err = db.RunInTransaction(context.Background(), func(tx *Tx) error { attempts++; return boom }, WithIsolation(LevelSerializable))
+	assert.ErrorIs(t, err, boom)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestRunInTransaction_RollsBackOnPanic(t *testing.T) {
+	db, source := newMockDB()
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = db.RunInTransaction(context.Background(), func(tx *Tx) error {
+			tx.Create(context.Background(), &maskUser{Name: "Ana"})
+			panic("boom")
+		})
+	})
+	stmts := sqlOf(source.Statements())
+	assert.Equal(t, "BEGIN", stmts[0])
+	assert.Equal(t, "ROLLBACK", stmts[len(stmts)-1])
+	assert.NotContains(t, stmts, "COMMIT")
+
+	// Transaction goes through the same path
+	assert.Panics(t, func() {
+		_ = db.Transaction(context.Background(), func(ctx context.Context, tx *Tx) error { panic("boom") })
+	})
+	assert.Equal(t, "ROLLBACK", source.lastStatement().SQL)
+}
@@ -0,0 +1,392 @@
+package typegorm
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Joins ---
+
+// joinOption is a table joined with Joins: a SQL clause, a relation name or a model.
+type joinOption struct {
+	join any
+	args []any
+}
+
+// Joins joins another table to the query of Find, to filter or order the rows by its
+// columns. join is a SQL clause with "?" placeholders for args, used as-is:
+//
+//	db.Find(ctx, &users, typegorm.Joins("LEFT JOIN profiles ON profiles.user_id = users.id"),
+//		typegorm.SQL("profiles.bio LIKE ?", "%go%"))
+//
+// or a model value (or the name of a relation field of the model), joined with LEFT
+// JOIN on an optional condition followed by its arguments:
+//
+//	db.Find(ctx, &users, typegorm.Joins(&Profile{}, "profiles.user_id = users.id AND profiles.public = ?", true))
+//	db.Find(ctx, &users, typegorm.Joins("Profile")) // ON the keys of the User.Profile relation
+//
+// Without a condition, the keys of the relation of the model with the joined model
+// are used (many-to-many relations need a condition). The table.column references of
+// the condition are quoted for the dialect and soft-deleted rows of the joined model
+// are left out. When the joined model is held by a to-one relation field of the model,
+// its columns are also selected and scanned into that field (left nil or zero when no
+// row matched), e.g. user.Profile.Bio. Once tables are joined, the columns of the model
+// are qualified with its table in the SELECT list, the conditions and the ordering;
+// conditions on the joined tables are written in SQL (typegorm.SQL, Chain.Where).
+//
+// The joined rows of a model (or relation) go through the condition rewriter and the
+// policies of that model, as a "Find": the condition they add joins the ON clause, the
+// columns they mask are neither selected nor usable in the conditions and ordering.
+// SQL clauses are used as-is, without policies. Joining a one-to-many or many-to-many
+// relation returns each row of the model once per related row, so Find requires
+// Distinct with it; Count counts the joined rows. FindFirst does not support Joins.
+func Joins(join any, args ...any) FindOption {
+	return func(opts *queryOptions) {
+		opts.joins = append(opts.joins, joinOption{join: join, args: args})
+	}
+}
+
+// joinPlan is the part of a Find query added by its Joins.
+type joinPlan struct {
+	table   string   // Quoted table of the model, qualifying its columns
+	clauses []string // JOIN clauses
+	args    []any    // Arguments of the clauses, before those of the WHERE clause
+	joined  []string // Quoted columns of the joined models scanned into the model
+	targets []joinTarget
+	masked  []*schema.Field // Columns of the joined models masked by their policies
+	toMany  []string        // Joined one-to-many and many-to-many relations
+}
+
+// joinTarget is a to-one relation field of the model receiving the columns of a join.
+type joinTarget struct {
+	index  []int           // Relation field in the model struct
+	typ    reflect.Type    // Joined struct type
+	fields []*schema.Field // Scanned fields of the joined struct, in SELECT order
+}
+
+// planJoins resolves the Joins of options; nil when there are none. The joined models
+// go through the condition rewriter and the policies of qb.
+func planJoins(qb *queryBuild, model *schema.Model, options queryOptions) (*joinPlan, error) {
+	dialect := qb.dialect
+	if len(options.joins) == 0 {
+		return nil, nil
+	}
+	if options.asOf != nil {
+		return nil, fmt.Errorf("Joins cannot be combined with AsOf: the joined tables are not versioned")
+	}
+	if model.Type == nil {
+		return nil, fmt.Errorf("Joins requires a model, not a table name")
+	}
+	plan := &joinPlan{table: quoteTable(dialect, model)}
+	for _, join := range options.joins {
+		if raw, ok := join.join.(string); ok && !bareIdentifierRe.MatchString(strings.TrimSpace(raw)) {
+			clause, args, err := bindPlaceholders(dialect, raw, join.args)
+			if err != nil {
+				return nil, fmt.Errorf("Joins: %w", err)
+			}
+			plan.clauses = append(plan.clauses, clause)
+			plan.args = append(plan.args, args...)
+			continue
+		}
+		if err := plan.addModel(qb, model, join, options.unscoped); err != nil {
+			return nil, fmt.Errorf("Joins: %w", err)
+		}
+	}
+	return plan, nil
+}
+
+// addModel adds a LEFT JOIN of the model (or relation) of join, restricted by the
+// policies of the joined model.
+func (p *joinPlan) addModel(qb *queryBuild, model *schema.Model, join joinOption, unscoped bool) error {
+	dialect := qb.dialect
+	rel, related, err := joinRelation(qb.parser, model, join.join)
+	if err != nil {
+		return err
+	}
+	if related.QualifiedTableName() == model.QualifiedTableName() {
+		return fmt.Errorf("%s joins its own table: use a SQL clause with a table alias", model.Name)
+	}
+	table := quoteTable(dialect, related)
+
+	var on string
+	var args []any
+	if len(join.args) > 0 {
+		raw, ok := join.args[0].(string)
+		if !ok {
+			return fmt.Errorf("the condition of the join with %s must be a SQL string, got %T", related.Name, join.args[0])
+		}
+		if on, args, err = rawCondition(dialect, quoteQualifiedColumns(dialect, raw), join.args[1:]); err != nil {
+			return err
+		}
+	} else if on, err = joinCondition(dialect, model, related, rel, p.table, table); err != nil {
+		return err
+	}
+	if softDelete := related.SoftDeleteField; softDelete != nil && !unscoped {
+		on += " AND " + table + "." + dialect.Quote(softDelete.DBName) + " IS NULL"
+	}
+	policyCtx, mask := withColumnMask(qb.ctx, qb.policies)
+	cond, err := rewriteCondition(policyCtx, qb.rewriter, qb.policies, "Find", related, nil)
+	if err != nil {
+		return err
+	}
+	policyWhere, policyArgs, err := buildWhereClause(qualifyingDialect{Dialect: dialect, table: table}, related, cond)
+	if err != nil {
+		return fmt.Errorf("invalid condition from policy on %s: %w", related.Name, err)
+	}
+	if len(policyWhere) > 0 {
+		on = "(" + on + ") AND " + strings.Join(policyWhere, " AND ")
+		args = append(args, policyArgs...)
+	}
+	masked := mask.maskedFields(related)
+	p.masked = append(p.masked, masked...)
+	p.clauses = append(p.clauses, "LEFT JOIN "+table+" ON "+on)
+	p.args = append(p.args, args...)
+
+	if rel != nil && (rel.Kind == schema.RelationOneToMany || rel.Kind == schema.RelationManyToMany) {
+		p.toMany = append(p.toMany, rel.Name)
+	}
+	if rel == nil || rel.Kind == schema.RelationOneToMany || rel.Kind == schema.RelationManyToMany {
+		return nil // Used to filter only: no field receives the columns
+	}
+	structField, ok := model.Type.FieldByName(rel.Name)
+	if !ok {
+		return fmt.Errorf("relation field %s not found in %s", rel.Name, model.Name)
+	}
+	target := joinTarget{index: structField.Index, typ: rel.Type}
+	for _, field := range related.Fields {
+		if !field.IsIgnored && !slices.Contains(masked, field) {
+			p.joined = append(p.joined, table+"."+dialect.Quote(field.DBName))
+			target.fields = append(target.fields, field)
+		}
+	}
+	p.targets = append(p.targets, target)
+	return nil
+}
+
+// check rejects what the joins make unsafe in a Find: a condition, ordering or grouping
+// on a masked column of a joined model, and a to-many join without Distinct.
+func (p *joinPlan) check(op string, model *schema.Model, cond any, options queryOptions) error {
+	if p == nil {
+		return nil
+	}
+	if err := checkMaskedUse(op, model, p.masked, cond, options); err != nil {
+		return err
+	}
+	if len(p.toMany) > 0 && !options.distinct {
+		return fmt.Errorf("Joins: %s returns each %s once per related row: add Distinct, or use Preload to load the related rows",
+			strings.Join(p.toMany, ", "), model.Name)
+	}
+	return nil
+}
+
+// joinRelation resolves the joined model of a relation name or model value, and the
+// relation of model with it (nil for a model value without one).
+func joinRelation(parser *schema.Parser, model *schema.Model, join any) (*schema.Relation, *schema.Model, error) {
+	if name, ok := join.(string); ok {
+		rel, ok := model.GetRelation(strings.TrimSpace(name))
+		if !ok {
+			return nil, nil, fmt.Errorf("%s has no relation named '%s'", model.Name, name)
+		}
+		related, err := parser.Parse(reflect.New(rel.Type).Interface())
+		if err != nil {
+			return nil, nil, fmt.Errorf("relation %s: %w", rel.Name, err)
+		}
+		return rel, related, nil
+	}
+	related, err := parser.Parse(join)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse schema for type %T: %w", join, err)
+	}
+	var found *schema.Relation
+	for _, rel := range model.Relations {
+		if rel.Type != related.Type {
+			continue
+		}
+		if found != nil {
+			return nil, nil, fmt.Errorf("%s has several relations to %s (%s, %s): join the relation by name", model.Name, related.Name, found.Name, rel.Name)
+		}
+		found = rel
+	}
+	return found, related, nil
+}
+
+// joinCondition builds the ON condition of a relation from its keys.
+func joinCondition(dialect common.Dialect, model, related *schema.Model, rel *schema.Relation, table, relatedTable string) (string, error) {
+	if rel == nil {
+		return "", fmt.Errorf("%s has no relation to %s: give the ON condition", model.Name, related.Name)
+	}
+	if rel.Kind == schema.RelationManyToMany {
+		return "", fmt.Errorf("many-to-many relation %s needs an ON condition (join the join table with a SQL clause)", rel.Name)
+	}
+	if rel.BelongsTo {
+		foreignKey, ok := model.FieldsByName[rel.ForeignKey]
+		if !ok {
+			return "", fmt.Errorf("foreign key field '%s' not found in %s", rel.ForeignKey, model.Name)
+		}
+		if len(related.PrimaryKeys) != 1 {
+			return "", fmt.Errorf("%s must have a single-column primary key", related.Name)
+		}
+		return fmt.Sprintf("%s.%s = %s.%s", relatedTable, dialect.Quote(related.PrimaryKeys[0].DBName),
+			table, dialect.Quote(foreignKey.DBName)), nil
+	}
+	foreignKey, err := relationForeignKey(related, rel)
+	if err != nil {
+		return "", err
+	}
+	if len(model.PrimaryKeys) != 1 {
+		return "", fmt.Errorf("%s must have a single-column primary key", model.Name)
+	}
+	return fmt.Sprintf("%s.%s = %s.%s", relatedTable, dialect.Quote(foreignKey.DBName),
+		table, dialect.Quote(model.PrimaryKeys[0].DBName)), nil
+}
+
+// qualifiedColumnRe matches the table.column references of a condition.
+var qualifiedColumnRe = regexp.MustCompile(`\b[A-Za-z_][A-Za-z0-9_$]*\.[A-Za-z_][A-Za-z0-9_$]*\b`)
+
+// quoteQualifiedColumns quotes the table.column references of a join condition.
+// Conditions with quotes or string literals are used as-is.
+//
+//	"profiles.user_id = users.id" -> "`profiles`.`user_id` = `users`.`id`"
+func quoteQualifiedColumns(dialect common.Dialect, condition string) string {
+	if strings.ContainsAny(condition, "`\"'[") {
+		return condition
+	}
+	return qualifiedColumnRe.ReplaceAllStringFunc(condition, func(name string) string {
+		table, column, _ := strings.Cut(name, ".")
+		return dialect.Quote(table) + "." + dialect.Quote(column)
+	})
+}
+
+// columns returns the dialect quoting the columns of the model in conditions and
+// ordering: qualified with its table once tables are joined.
+func (p *joinPlan) columns(dialect common.Dialect) common.Dialect {
+	if p == nil {
+		return dialect
+	}
+	return qualifyingDialect{Dialect: dialect, table: p.table}
+}
+
+// selectList qualifies the columns of the model and adds those of the joined models.
+func (p *joinPlan) selectList(dialect common.Dialect, selectList string, fields []*schema.Field) string {
+	if p == nil {
+		return selectList
+	}
+	columns := make([]string, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, p.table+"."+dialect.Quote(field.DBName))
+	}
+	return p.withJoined(strings.Join(columns, ", "))
+}
+
+// withJoined appends the columns of the joined models to a SELECT list of the model.
+func (p *joinPlan) withJoined(selectList string) string {
+	if p == nil || len(p.joined) == 0 {
+		return selectList
+	}
+	return selectList + ", " + strings.Join(p.joined, ", ")
+}
+
+// from returns the JOIN clauses following the FROM table.
+func (p *joinPlan) from() string {
+	if p == nil {
+		return ""
+	}
+	return " " + strings.Join(p.clauses, " ")
+}
+
+// whereArgs prepends the arguments of the JOIN clauses to those of the WHERE clause.
+func (p *joinPlan) whereArgs(args []any) []any {
+	if p == nil || len(p.args) == 0 {
+		return args
+	}
+	return append(append([]any{}, p.args...), args...)
+}
+
+// qualifyingDialect qualifies the quoted names with a table: the condition and
+// ordering builders only quote column names.
+type qualifyingDialect struct {
+	common.Dialect
+	table string
+}
+
+func (d qualifyingDialect) Quote(name string) string {
+	return d.table + "." + d.Dialect.Quote(name)
+}
+
+// unqualified returns the dialect under a qualifyingDialect.
+func unqualified(dialect common.Dialect) common.Dialect {
+	if d, ok := dialect.(qualifyingDialect); ok {
+		return d.Dialect
+	}
+	return dialect
+}
+
+// scan scans all rows into sliceValue like scanInto, the columns of the joins into the
+// relation fields of the targets.
+func (p *joinPlan) scan(rows interface {
+	Next() bool
+	Scan(dest ...any) error
+}, sliceValue reflect.Value, structType reflect.Type, elementIsPointer bool, plan *scanPlan, capacity int) (int, error) {
+	if p == nil || len(p.targets) == 0 {
+		return scanInto(rows, sliceValue, structType, elementIsPointer, plan, capacity)
+	}
+	dest := make([]any, len(plan.indexes), len(plan.indexes)+len(p.joined))
+	scanned := make([]reflect.Value, 0, len(p.joined)) // *T per joined column: NULL when no row matched
+	for _, target := range p.targets {
+		for _, field := range target.fields {
+			column := reflect.New(reflect.PointerTo(field.GoType))
+			scanned = append(scanned, column)
+			dest = append(dest, column.Interface())
+		}
+	}
+
+	sliceValue.Set(reflect.MakeSlice(sliceValue.Type(), 0, capacity))
+	rowCount := 0
+	for rows.Next() {
+		rowCount++
+		elem := reflect.New(structType).Elem()
+		plan.bind(elem, dest[:len(plan.indexes)])
+		if err := rows.Scan(dest...); err != nil {
+			return rowCount, err
+		}
+		offset := 0
+		for _, target := range p.targets {
+			assignJoined(elem.FieldByIndex(target.index), target, scanned[offset:offset+len(target.fields)])
+			offset += len(target.fields)
+		}
+		if elementIsPointer {
+			sliceValue.Set(reflect.Append(sliceValue, elem.Addr()))
+		} else {
+			sliceValue.Set(reflect.Append(sliceValue, elem))
+		}
+	}
+	return rowCount, nil
+}
+
+// assignJoined sets a relation field from the scanned columns of its join; all NULL
+// means no row matched and the field is left unset.
+func assignJoined(field reflect.Value, target joinTarget, columns []reflect.Value) {
+	record := reflect.New(target.typ).Elem()
+	matched := false
+	for i, column := range columns {
+		if value := column.Elem(); !value.IsNil() {
+			record.FieldByName(target.fields[i].GoName).Set(value.Elem())
+			matched = true
+		}
+		column.Elem().SetZero() // Reused for the next row
+	}
+	if !matched {
+		return
+	}
+	if field.Kind() == reflect.Pointer {
+		field.Set(record.Addr())
+	} else {
+		field.Set(record)
+	}
+}
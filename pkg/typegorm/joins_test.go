@@ -0,0 +1,106 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoins_ScansRelationIntoNestedStruct(t *testing.T) {
+	db, source := newMockDB()
+	source.queueRows([]string{"id", "titulo", "autor_id", "id", "nome"},
+		[]any{uint(1), "a", uint(7), uint(7), "Ana"}, []any{uint(2), "a", uint(0), nil, nil})
+
+	var posts []preloadPost
+	result := db.Find(context.Background(), &posts, map[string]any{"titulo": "a"}, Joins(&preloadAutor{}), Order("titulo DESC"))
+	require.NoError(t, result.Error)
+
+	assert.Equal(t, "SELECT `preload_posts`.`id`, `preload_posts`.`titulo`, `preload_posts`.`autor_id`, `preload_autors`.`id`, `preload_autors`.`nome` "+
+		"FROM `preload_posts` LEFT JOIN `preload_autors` ON `preload_autors`.`id` = `preload_posts`.`autor_id` "+
+		"WHERE `preload_posts`.`titulo` = ? ORDER BY `preload_posts`.`titulo` DESC", source.lastStatement().SQL)
+	require.Len(t, posts, 2)
+	require.NotNil(t, posts[0].Autor)
+	assert.Equal(t, preloadAutor{ID: 7, Nome: "Ana"}, *posts[0].Autor)
+	assert.Nil(t, posts[1].Autor, "No joined row")
+}
+
+func TestJoins_ClausesAndConditions(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	source.queueRows([]string{"id", "titulo", "autor_id", "id", "post_id", "url"},
+		[]any{uint(1), "a", uint(7), uint(70), uint64(1), "/1.png"})
+
+	var posts []*preloadPost
+	result := db.Find(ctx, &posts, SQL("preload_capas.url <> ?", ""),
+		Joins("INNER JOIN tags ON tags.post_id = preload_posts.id AND tags.nome = ?", "go"), Joins("Capa"))
+	require.NoError(t, result.Error)
+	statement := source.lastStatement()
+	assert.Contains(t, statement.SQL, "FROM `preload_posts` INNER JOIN tags ON tags.post_id = preload_posts.id AND tags.nome = ? "+
+		"LEFT JOIN `preload_capas` ON `preload_capas`.`post_id` = `preload_posts`.`id` WHERE (preload_capas.url <> ?)")
+	assert.Equal(t, []any{"go", ""}, statement.Args, "Join arguments come first")
+	require.Len(t, posts, 1)
+	assert.Equal(t, "/1.png", posts[0].Capa.URL)
+
+	source.queueRows([]string{"count"}, []any{int64(3)})
+	count, err := db.Model(&preloadPost{}).Where(map[string]any{"titulo": "a"}).
+		Joins(&preloadAutor{}, "preload_autors.id = preload_posts.autor_id OR preload_autors.nome = ?", "Ana").Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.Equal(t, "SELECT COUNT(*) FROM `preload_posts` LEFT JOIN `preload_autors` "+
+		"ON (`preload_autors`.`id` = `preload_posts`.`autor_id` OR `preload_autors`.`nome` = ?) WHERE `preload_posts`.`titulo` = ?",
+		source.lastStatement().SQL)
+	assert.Equal(t, []any{"Ana", "a"}, source.lastStatement().Args)
+}
+
+func TestJoins_Errors(t *testing.T) {
+	db, _ := newMockDB()
+	ctx := context.Background()
+	var posts []preloadPost
+
+	err := db.Find(ctx, &posts, Joins("Comentarios")).Error
+	assert.ErrorContains(t, err, "has no relation named 'Comentarios'")
+	err = db.Find(ctx, &posts, Joins(&preloadPost{})).Error
+	assert.ErrorContains(t, err, "joins its own table")
+	err = db.Find(ctx, &posts, Joins(&softNote{})).Error
+	assert.ErrorContains(t, err, "give the ON condition")
+}
+
+func TestJoins_PoliciesMasksAndToManyRelations(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	db.UsePolicy(func(ctx context.Context, op string, model *schema.Model, cond *Expr) error {
+		if model.Name == "preloadAutor" {
+			MaskColumns(ctx, "nome")
+			*cond = And(*cond, Gt("id", 5))
+		}
+		return nil
+	})
+
+	source.queueRows([]string{"id", "titulo", "autor_id", "id"}, []any{uint(1), "a", uint(7), uint(7)})
+	var posts []preloadPost
+	result := db.Find(ctx, &posts, Joins("Autor"))
+	require.NoError(t, result.Error)
+	assert.Equal(t, "SELECT `preload_posts`.`id`, `preload_posts`.`titulo`, `preload_posts`.`autor_id`, `preload_autors`.`id` "+
+		"FROM `preload_posts` LEFT JOIN `preload_autors` ON (`preload_autors`.`id` = `preload_posts`.`autor_id`) AND `preload_autors`.`id` > ?",
+		source.lastStatement().SQL, "The policy narrows the join and the masked column is not read")
+	assert.Equal(t, []any{5}, source.lastStatement().Args)
+	require.Len(t, posts, 1)
+	assert.Equal(t, preloadAutor{ID: 7}, *posts[0].Autor)
+
+	err := db.Find(ctx, &posts, SQL("preload_autors.nome = ?", "Ana"), Joins("Autor")).Error
+	assert.ErrorIs(t, err, ErrPolicyDenied, "Filtering on a masked joined column")
+
+	var post preloadPost
+	err = db.FindFirst(ctx, &post, Joins("Autor")).Error
+	assert.ErrorContains(t, err, "FindFirst does not support Joins")
+
+	var escritores []preloadEscritor
+	err = db.Find(ctx, &escritores, Joins("Artigos")).Error
+	assert.ErrorContains(t, err, "add Distinct")
+	source.queueRows([]string{"id", "nome"}, []any{uint(1), "Ana"})
+	require.NoError(t, db.Find(ctx, &escritores, Joins("Artigos"), Distinct(), SQL("preload_artigos.publicado = ?", true)).Error)
+	assert.Contains(t, source.lastStatement().SQL, "SELECT DISTINCT")
+}
@@ -0,0 +1,201 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+)
+
+// --- Connection Leak Detection ---
+
+// OpenResource describes a tracked Rows or transaction that has not been released yet.
+type OpenResource struct {
+	Kind   string // "rows" or "tx"
+	Opened time.Time
+	Stack  string // Stack trace captured when the resource was opened
+}
+
+// leakTracker records open Rows/transactions and reports those garbage collected
+// without Close/Commit/Rollback. Enabled with logging.leakDetection (or level "debug").
+type leakTracker struct {
+	mu     sync.Mutex
+	nextID uint64
+	open   map[uint64]OpenResource
+	leaked atomic.Int64
+}
+
+func newLeakTracker() *leakTracker {
+	return &leakTracker{open: make(map[uint64]OpenResource)}
+}
+
+func (t *leakTracker) acquire(kind string) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextID++
+	t.open[t.nextID] = OpenResource{Kind: kind, Opened: time.Now(), Stack: string(debug.Stack())}
+	return t.nextID
+}
+
+// release marks a resource as properly closed. It reports whether it was still open.
+func (t *leakTracker) release(id uint64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, ok := t.open[id]
+	delete(t.open, id)
+	return ok
+}
+
+// leak is called from finalizers for resources that were never released.
+func (t *leakTracker) leak(id uint64) bool {
+	t.mu.Lock()
+	res, ok := t.open[id]
+	delete(t.open, id)
+	t.mu.Unlock()
+	if !ok {
+		return false
+	}
+	t.leaked.Add(1)
+	hint := "rows.Close()"
+	if res.Kind == "tx" {
+		hint = "Commit() or Rollback()"
+	}
+	fmt.Printf("Warning: leaked %s detected: %s was never called (opened %s ago). Opened at:\n%s\n",
+		res.Kind, hint, time.Since(res.Opened).Round(time.Millisecond), res.Stack)
+	return true
+}
+
+// OpenResources returns the tracked Rows and transactions that are still open, oldest
+// first. It is empty when leak detection is disabled.
+func (db *DB) OpenResources() []OpenResource {
+	if db.leaks == nil {
+		return nil
+	}
+	db.leaks.mu.Lock()
+	defer db.leaks.mu.Unlock()
+	out := make([]OpenResource, 0, len(db.leaks.open))
+	for _, res := range db.leaks.open {
+		out = append(out, res)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Opened.Before(out[j].Opened) })
+	return out
+}
+
+// LeakCount returns how many Rows/transactions were garbage collected without being
+// closed, committed or rolled back.
+func (db *DB) LeakCount() int64 {
+	if db.leaks == nil {
+		return 0
+	}
+	return db.leaks.leaked.Load()
+}
+
+// trackTx registers a new transaction; a finalizer rolls it back and logs it if it
+// becomes unreachable while still open.
+func (db *DB) trackTx(tx *Tx) {
+	if db.leaks == nil {
+		return
+	}
+	tracker := db.leaks
+	id := tracker.acquire("tx")
+	tx.release = func() { tracker.release(id) }
+	runtime.SetFinalizer(tx, func(tx *Tx) {
+		if tracker.leak(id) {
+			_ = tx.source.Rollback() // Return the connection to the pool
+		}
+	})
+}
+
+// --- Tracking DataSource (raw access through GetDataSource) ---
+
+// trackingSource wraps the DataSource returned by GetDataSource when leak detection is
+// enabled, so Rows and transactions opened with raw SQL are tracked as well.
+type trackingSource struct {
+	common.DataSource
+	tracker *leakTracker
+}
+
+// Unwrap returns the underlying DataSource (e.g., to check optional capabilities).
+func (s *trackingSource) Unwrap() common.DataSource { return s.DataSource }
+
+// GetSQLDB forwards to the underlying DataSource when it exposes its *sql.DB.
+func (s *trackingSource) GetSQLDB() *sql.DB {
+	if getter, ok := s.DataSource.(interface{ GetSQLDB() *sql.DB }); ok {
+		return getter.GetSQLDB()
+	}
+	return nil
+}
+
+func (s *trackingSource) Query(ctx context.Context, query string, args ...any) (common.Rows, error) {
+	rows, err := s.DataSource.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return newTrackedRows(rows, s.tracker), nil
+}
+
+func (s *trackingSource) BeginTx(ctx context.Context, opts any) (common.Tx, error) {
+	tx, err := s.DataSource.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	tracked := &trackedTx{Tx: tx, tracker: s.tracker, id: s.tracker.acquire("tx")}
+	runtime.SetFinalizer(tracked, func(t *trackedTx) {
+		if t.tracker.leak(t.id) {
+			_ = t.Tx.Rollback()
+		}
+	})
+	return tracked, nil
+}
+
+type trackedRows struct {
+	common.Rows
+	tracker *leakTracker
+	id      uint64
+}
+
+func newTrackedRows(rows common.Rows, tracker *leakTracker) *trackedRows {
+	tracked := &trackedRows{Rows: rows, tracker: tracker, id: tracker.acquire("rows")}
+	runtime.SetFinalizer(tracked, func(r *trackedRows) {
+		if r.tracker.leak(r.id) {
+			_ = r.Rows.Close() // Return the connection to the pool
+		}
+	})
+	return tracked
+}
+
+func (r *trackedRows) Close() error {
+	r.tracker.release(r.id)
+	return r.Rows.Close()
+}
+
+type trackedTx struct {
+	common.Tx
+	tracker *leakTracker
+	id      uint64
+}
+
+func (t *trackedTx) Commit() error {
+	t.tracker.release(t.id)
+	return t.Tx.Commit()
+}
+
+func (t *trackedTx) Rollback() error {
+	t.tracker.release(t.id)
+	return t.Tx.Rollback()
+}
+
+func (t *trackedTx) Query(ctx context.Context, query string, args ...any) (common.Rows, error) {
+	rows, err := t.Tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return newTrackedRows(rows, t.tracker), nil
+}
@@ -0,0 +1,60 @@
+package typegorm
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+)
+
+func newLeakDetectingDB() (*DB, *mockSource) {
+	source := newMockSource()
+	cfg := config.Config{Logging: config.LoggingConfig{LeakDetection: true}}
+	return NewDB(source, nil, cfg), source
+}
+
+func leakRows(db *DB) {
+	rows, _ := db.GetDataSource().Query(context.Background(), "SELECT 1")
+	_ = rows // Never closed
+}
+
+func leakTx(db *DB) {
+	_, _ = db.Begin(context.Background()) // Never committed or rolled back
+}
+
+func TestLeakDetection_ReportsUnclosedResources(t *testing.T) {
+	db, source := newLeakDetectingDB()
+	leakRows(db)
+	leakTx(db)
+	require.Len(t, db.OpenResources(), 2)
+
+	require.Eventually(t, func() bool {
+		runtime.GC()
+		return db.LeakCount() == 2
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Empty(t, db.OpenResources())
+	assert.True(t, source.containsStatement("ROLLBACK"), "leaked transactions are rolled back")
+}
+
+func TestLeakDetection_ClosedResourcesAreReleased(t *testing.T) {
+	db, _ := newLeakDetectingDB()
+	ctx := context.Background()
+
+	rows, err := db.GetDataSource().Query(ctx, "SELECT 1")
+	require.NoError(t, err)
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	assert.Len(t, db.OpenResources(), 2)
+
+	require.NoError(t, rows.Close())
+	require.NoError(t, tx.Commit())
+	assert.Empty(t, db.OpenResources())
+
+	runtime.GC()
+	assert.Zero(t, db.LeakCount())
+}
@@ -0,0 +1,288 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// --- DataLoader-style batching ---
+
+// LoaderOptions configures a Loader.
+type LoaderOptions struct {
+	Wait     time.Duration // Time window used to collect keys before querying (default 2ms)
+	MaxBatch int           // Dispatch immediately once this many keys are pending (default 100)
+}
+
+// Loader coalesces primary-key lookups issued within a short window into a single
+// "pk IN (...)" query and caches the results. It is meant to be request-scoped, e.g.
+// one Loader per GraphQL request, so concurrent resolvers share queries:
+//
+//	users := typegorm.NewLoader[User](db, typegorm.LoaderOptions{})
+//	user, err := users.Load(ctx, order.UserID) // Batched with other Loads in the window
+//
+// Missing records are reported as sql.ErrNoRows, like FindByID.
+type Loader[T any] struct {
+	db       *DB
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	cache   map[any]*loaderCall[T]
+	pending *loaderBatch[T]
+}
+
+type loaderCall[T any] struct {
+	done  chan struct{}
+	value *T
+	err   error
+}
+
+type loaderBatch[T any] struct {
+	ctx   context.Context
+	keys  []any
+	calls map[any]*loaderCall[T]
+	timer *time.Timer
+}
+
+// NewLoader creates a Loader for model type T (a struct with a single primary key).
+func NewLoader[T any](db *DB, opts LoaderOptions) *Loader[T] {
+	if opts.Wait <= 0 {
+		opts.Wait = 2 * time.Millisecond
+	}
+	if opts.MaxBatch <= 0 {
+		opts.MaxBatch = 100
+	}
+	return &Loader[T]{db: db, wait: opts.Wait, maxBatch: opts.MaxBatch, cache: make(map[any]*loaderCall[T])}
+}
+
+// Load returns the record with the given primary key, batching the lookup with
+// other Load calls issued within the wait window.
+func (l *Loader[T]) Load(ctx context.Context, key any) (*T, error) {
+	call, err := l.enqueue(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// LoadMany returns the records for the given keys in the same order. Missing records
+// are returned as nil entries without an error.
+func (l *Loader[T]) LoadMany(ctx context.Context, keys []any) ([]*T, error) {
+	calls := make([]*loaderCall[T], len(keys))
+	for i, key := range keys {
+		call, err := l.enqueue(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		calls[i] = call
+	}
+	values := make([]*T, len(keys))
+	for i, call := range calls {
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if call.err != nil && call.err != sql.ErrNoRows {
+			return nil, call.err
+		}
+		values[i] = call.value
+	}
+	return values, nil
+}
+
+// Prime stores a record in the cache, e.g. after creating it.
+func (l *Loader[T]) Prime(key any, value *T) error {
+	normalized, err := l.normalizeKey(key)
+	if err != nil {
+		return err
+	}
+	call := &loaderCall[T]{done: make(chan struct{}), value: value}
+	close(call.done)
+	l.mu.Lock()
+	l.cache[normalized] = call
+	l.mu.Unlock()
+	return nil
+}
+
+// Clear removes a key from the cache so the next Load queries it again.
+func (l *Loader[T]) Clear(key any) {
+	normalized, err := l.normalizeKey(key)
+	if err != nil {
+		return
+	}
+	l.mu.Lock()
+	delete(l.cache, normalized)
+	l.mu.Unlock()
+}
+
+// ClearAll empties the cache.
+func (l *Loader[T]) ClearAll() {
+	l.mu.Lock()
+	l.cache = make(map[any]*loaderCall[T])
+	l.mu.Unlock()
+}
+
+// VirtualLoader adapts the Loader so it can back a VirtualRelation, letting Preload
+// share the same batching and cache.
+func (l *Loader[T]) VirtualLoader() VirtualLoader {
+	return func(ctx context.Context, keys []any) (map[any]any, error) {
+		values, err := l.LoadMany(ctx, keys)
+		if err != nil {
+			return nil, err
+		}
+		loaded := make(map[any]any, len(keys))
+		for i, value := range values {
+			if value != nil {
+				loaded[keys[i]] = value
+			}
+		}
+		return loaded, nil
+	}
+}
+
+// enqueue returns the cached call for key or adds the key to the pending batch.
+func (l *Loader[T]) enqueue(ctx context.Context, key any) (*loaderCall[T], error) {
+	normalized, err := l.normalizeKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if call, ok := l.cache[normalized]; ok {
+		return call, nil
+	}
+
+	call := &loaderCall[T]{done: make(chan struct{})}
+	l.cache[normalized] = call
+
+	if l.pending == nil {
+		// The batch outlives the first caller's cancellation; each caller still
+		// honors its own context while waiting.
+		batch := &loaderBatch[T]{ctx: context.WithoutCancel(ctx), calls: make(map[any]*loaderCall[T])}
+		batch.timer = time.AfterFunc(l.wait, func() { l.dispatch(batch) })
+		l.pending = batch
+	}
+	batch := l.pending
+	batch.keys = append(batch.keys, normalized)
+	batch.calls[normalized] = call
+	if len(batch.keys) >= l.maxBatch {
+		batch.timer.Stop()
+		l.pending = nil
+		go l.fetch(batch)
+	}
+	return call, nil
+}
+
+// dispatch runs a batch whose wait window expired (unless it was already dispatched).
+func (l *Loader[T]) dispatch(batch *loaderBatch[T]) {
+	l.mu.Lock()
+	if l.pending != batch {
+		l.mu.Unlock()
+		return
+	}
+	l.pending = nil
+	l.mu.Unlock()
+	l.fetch(batch)
+}
+
+// fetch loads all keys of a batch with one query and resolves the waiting calls.
+func (l *Loader[T]) fetch(batch *loaderBatch[T]) {
+	model, err := l.db.GetModel(new(T))
+	if err == nil && len(model.PrimaryKeys) != 1 {
+		err = fmt.Errorf("loader requires model %s to have exactly one primary key", model.Name)
+	}
+	var records []*T
+	if err == nil {
+		pk := model.PrimaryKeys[0]
+		fmt.Printf("Loader: fetching %d %s record(s) in one query\n", len(batch.keys), model.Name)
+		result := l.db.Find(batch.ctx, &records, map[string]any{pk.DBName + " in": batch.keys})
+		err = result.Error
+
+		for _, record := range records {
+			key := reflect.ValueOf(record).Elem().FieldByName(pk.GoName).Interface()
+			if call, ok := batch.calls[key]; ok {
+				call.value = record
+			}
+		}
+	}
+
+	for _, call := range batch.calls {
+		switch {
+		case err != nil:
+			call.err = err
+		case call.value == nil:
+			call.err = sql.ErrNoRows
+		}
+		close(call.done)
+	}
+
+	if err != nil {
+		// Do not cache failures; the next Load retries.
+		l.mu.Lock()
+		for key, call := range batch.calls {
+			if l.cache[key] == call {
+				delete(l.cache, key)
+			}
+		}
+		l.mu.Unlock()
+	}
+}
+
+// normalizeKey converts a key to the Go type of the primary key so that, e.g., an
+// int literal matches a uint ID in the cache and in the results.
+func (l *Loader[T]) normalizeKey(key any) (any, error) {
+	model, err := l.db.GetModel(new(T))
+	if err != nil {
+		return nil, err
+	}
+	if len(model.PrimaryKeys) != 1 {
+		return nil, fmt.Errorf("loader requires model %s to have exactly one primary key", model.Name)
+	}
+	pkType := model.PrimaryKeys[0].GoType
+	value := reflect.ValueOf(key)
+	if !value.IsValid() {
+		return nil, fmt.Errorf("loader key cannot be nil")
+	}
+	if value.Type() == pkType {
+		return key, nil
+	}
+	if pkType.Kind() == reflect.String { // Convert would turn an integer into a rune
+		switch {
+		case value.CanInt():
+			return reflect.ValueOf(strconv.FormatInt(value.Int(), 10)).Convert(pkType).Interface(), nil
+		case value.CanUint():
+			return reflect.ValueOf(strconv.FormatUint(value.Uint(), 10)).Convert(pkType).Interface(), nil
+		}
+	}
+	if !value.Type().ConvertibleTo(pkType) {
+		return nil, fmt.Errorf("loader key of type %T cannot be converted to %s", key, pkType)
+	}
+	converted := value.Convert(pkType)
+	if isNumber(value) && isNumber(converted) {
+		// Non-integral floats, negative keys of unsigned IDs and overflows would match
+		// another row.
+		if (value.CanInt() && value.Int() < 0 && converted.CanUint()) ||
+			(value.CanFloat() && value.Float() < 0 && converted.CanUint()) ||
+			!converted.Convert(value.Type()).Equal(value) {
+			return nil, fmt.Errorf("loader key %v cannot be represented as %s", key, pkType)
+		}
+	}
+	return converted.Interface(), nil
+}
+
+// isNumber reports whether v is an integer or a float.
+func isNumber(v reflect.Value) bool {
+	return v.CanInt() || v.CanUint() || v.CanFloat()
+}
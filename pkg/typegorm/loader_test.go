@@ -0,0 +1,88 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type loaderUser struct {
+	ID   uint `typegorm:"primaryKey;autoIncrement"`
+	Name string
+}
+
+func TestLoader_BatchesConcurrentLoads(t *testing.T) {
+	db, source := newMockDB()
+	source.queueRows([]string{"id", "name"}, []any{uint(1), "ana"}, []any{uint(2), "bia"})
+
+	loader := NewLoader[loaderUser](db, LoaderOptions{Wait: 20 * time.Millisecond})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	results := make([]*loaderUser, 3)
+	errs := make([]error, 3)
+	for i, key := range []any{1, uint(2), 3} {
+		wg.Add(1)
+		go func(i int, key any) {
+			defer wg.Done()
+			results[i], errs[i] = loader.Load(ctx, key)
+		}(i, key)
+	}
+	wg.Wait()
+
+	require.Len(t, source.Statements(), 1, "all keys are fetched with one query")
+	assert.Contains(t, source.lastStatement().SQL, "`id` IN (?, ?, ?)")
+
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+	assert.Equal(t, "ana", results[0].Name)
+	assert.Equal(t, "bia", results[1].Name)
+	assert.ErrorIs(t, errs[2], sql.ErrNoRows)
+
+	// Cached: no new query
+	user, err := loader.Load(ctx, uint(1))
+	require.NoError(t, err)
+	assert.Equal(t, "ana", user.Name)
+	assert.Len(t, source.Statements(), 1)
+}
+
+func TestLoader_LoadManyAndPrime(t *testing.T) {
+	db, source := newMockDB()
+	source.queueRows([]string{"id", "name"}, []any{uint(5), "eva"})
+
+	loader := NewLoader[loaderUser](db, LoaderOptions{Wait: time.Millisecond})
+	require.NoError(t, loader.Prime(4, &loaderUser{ID: 4, Name: "primed"}))
+
+	users, err := loader.LoadMany(context.Background(), []any{4, 5, 6})
+	require.NoError(t, err)
+	require.Len(t, users, 3)
+	assert.Equal(t, "primed", users[0].Name)
+	assert.Equal(t, "eva", users[1].Name)
+	assert.Nil(t, users[2])
+	assert.Equal(t, []any{uint(5), uint(6)}, source.lastStatement().Args, "primed keys are not queried")
+}
+
+type loaderTag struct {
+	Code string `typegorm:"primaryKey"`
+}
+
+func TestLoader_NormalizeKey(t *testing.T) {
+	db, _ := newMockDB()
+	users := NewLoader[loaderUser](db, LoaderOptions{})
+	key, err := users.normalizeKey(float64(7))
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), key)
+	for _, bad := range []any{1.5, -1, int64(-3), -2.0} {
+		_, err := users.normalizeKey(bad)
+		assert.ErrorContains(t, err, "cannot be represented as uint", bad)
+	}
+
+	key, err = NewLoader[loaderTag](db, LoaderOptions{}).normalizeKey(65)
+	require.NoError(t, err)
+	assert.Equal(t, "65", key, "not the rune 'A'")
+}
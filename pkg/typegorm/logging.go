@@ -0,0 +1,35 @@
+package typegorm
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// --- SQL Logging ---
+
+// sqlLogging turns the "Executing SQL" lines on or off. They go to standard output,
+// shared by every DB of the process, so the level of the last DB created applies (see
+// setLogLevel).
+var sqlLogging atomic.Bool
+
+func init() { sqlLogging.Store(true) }
+
+// setLogLevel applies the Logging.Level of a config: the executed statements are
+// printed at "debug" and "info" (the default, also when the level is empty), not at
+// "warn" or "error".
+func setLogLevel(level string) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "warn", "warning", "error":
+		sqlLogging.Store(false)
+	default:
+		sqlLogging.Store(true)
+	}
+}
+
+// logSQL prints an executed statement when the log level allows it.
+func logSQL(format string, args ...any) {
+	if sqlLogging.Load() {
+		fmt.Printf(format, args...)
+	}
+}
@@ -0,0 +1,66 @@
+package typegorm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// --- Max Rows Guard ---
+
+// MaxRows aborts the Find with a *MaxRowsError when the query would return more than n
+// rows, instead of loading them all (e.g. after forgetting a WHERE). It overrides the
+// global default (config: query.maxRows); MaxRows(0) disables the guard for the call.
+func MaxRows(n int) FindOption {
+	return func(opts *queryOptions) {
+		if n <= 0 {
+			n = -1
+		}
+		opts.maxRows = n
+	}
+}
+
+// MaxRowsError is returned by Find when the result exceeds the MaxRows guard. It matches
+// ErrTooManyRows with errors.Is.
+type MaxRowsError struct {
+	Model string // Model queried
+	Limit int    // Maximum number of rows allowed
+}
+
+func (e *MaxRowsError) Error() string {
+	return fmt.Sprintf("query on %s returned more than %d rows (add conditions, Limit, or raise MaxRows)", e.Model, e.Limit)
+}
+
+func (e *MaxRowsError) Is(target error) bool {
+	return target == ErrTooManyRows
+}
+
+// resolveMaxRows returns the guard for a query: the MaxRows option, else the global
+// default. Zero means no guard.
+func resolveMaxRows(options queryOptions, defaultMax int) int {
+	switch {
+	case options.maxRows < 0:
+		return 0
+	case options.maxRows > 0:
+		return options.maxRows
+	}
+	return defaultMax
+}
+
+// guardedLimit returns the LIMIT to send: with a guard, one row more than allowed is
+// fetched so that exceeding it can be detected without loading the whole table.
+func guardedLimit(limit, maxRows int) int {
+	if maxRows > 0 && (limit <= 0 || limit > maxRows) {
+		return maxRows + 1
+	}
+	return limit
+}
+
+// checkMaxRows discards the scanned rows and returns a *MaxRowsError when the guard was exceeded.
+func checkMaxRows(rowCount, maxRows int, sliceValue reflect.Value, model string) error {
+	if maxRows <= 0 || rowCount <= maxRows {
+		return nil
+	}
+	sliceValue.Set(reflect.Zero(sliceValue.Type()))
+	fmt.Printf("Warning: Find on %s aborted: more than %d rows\n", model, maxRows)
+	return &MaxRowsError{Model: model, Limit: maxRows}
+}
@@ -0,0 +1,69 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func queueMaskUsers(source *mockSource, n int) {
+	values := make([][]any, n)
+	for i := range values {
+		values[i] = []any{int64(i + 1), "user", "u@x.io", int64(20)}
+	}
+	source.queueRows([]string{"id", "name", "email", "age"}, values...)
+}
+
+func TestMaxRows_AbortsFind(t *testing.T) {
+	db, source := newMockDB()
+	queueMaskUsers(source, 3)
+
+	var users []maskUser
+	result := db.Find(context.Background(), &users, MaxRows(2))
+	require.ErrorIs(t, result.Error, ErrTooManyRows)
+	var maxErr *MaxRowsError
+	require.True(t, errors.As(result.Error, &maxErr))
+	assert.Equal(t, 2, maxErr.Limit)
+	assert.Empty(t, users)
+	assert.Contains(t, source.lastStatement().SQL, "LIMIT 3")
+}
+
+func TestMaxRows_AllowsResultsWithinGuard(t *testing.T) {
+	db, source := newMockDB()
+	queueMaskUsers(source, 2)
+
+	var users []maskUser
+	require.NoError(t, db.Find(context.Background(), &users, MaxRows(2)).Error)
+	assert.Len(t, users, 2)
+}
+
+func TestMaxRows_SmallerLimitIsKept(t *testing.T) {
+	db, source := newMockDB()
+
+	var users []maskUser
+	require.NoError(t, db.Find(context.Background(), &users, Limit(5), MaxRows(10)).Error)
+	assert.Contains(t, source.lastStatement().SQL, "LIMIT 5")
+}
+
+func TestMaxRows_GlobalDefaultAndOverride(t *testing.T) {
+	db, source := newMockDB()
+	db.config.Query.MaxRows = 1
+
+	queueMaskUsers(source, 2)
+	var users []maskUser
+	require.ErrorIs(t, db.Find(context.Background(), &users).Error, ErrTooManyRows)
+
+	queueMaskUsers(source, 2)
+	require.NoError(t, db.Find(context.Background(), &users, MaxRows(0)).Error)
+	assert.Len(t, users, 2)
+	assert.NotContains(t, source.lastStatement().SQL, "LIMIT")
+
+	tx, err := db.Begin(context.Background())
+	require.NoError(t, err)
+	queueMaskUsers(source, 2)
+	require.ErrorIs(t, tx.Find(context.Background(), &users).Error, ErrTooManyRows)
+	require.NoError(t, tx.Rollback())
+}
@@ -0,0 +1,33 @@
+package typegorm
+
+import "time"
+
+// --- Metrics ---
+
+// MetricsCollector receives the measurements of the ORM (see the Metric* constants),
+// e.g. to export them to Prometheus or StatsD. Its methods are called synchronously
+// from concurrent goroutines, so they must be safe for concurrent use and fast.
+type MetricsCollector interface {
+	// IncCounter adds delta to the counter name.
+	IncCounter(name string, labels map[string]string, delta float64)
+	// ObserveDuration records d in the distribution (histogram, summary) name.
+	ObserveDuration(name string, labels map[string]string, d time.Duration)
+}
+
+// UseMetricsCollector sets the collector of the ORM's metrics (nil removes it).
+// Call it before the DB is shared between goroutines.
+func (db *DB) UseMetricsCollector(collector MetricsCollector) {
+	db.metrics = collector
+}
+
+func incCounter(collector MetricsCollector, name string, labels map[string]string, delta float64) {
+	if collector != nil {
+		collector.IncCounter(name, labels, delta)
+	}
+}
+
+func observeDuration(collector MetricsCollector, name string, labels map[string]string, d time.Duration) {
+	if collector != nil {
+		collector.ObserveDuration(name, labels, d)
+	}
+}
@@ -0,0 +1,44 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// Migrator groups schema management helpers that go beyond AutoMigrate
+// (security policies, grants, ...). Obtain one with DB.Migrator().
+type Migrator struct {
+	db *DB
+}
+
+// Migrator returns the schema management helper bound to this DB.
+func (db *DB) Migrator() *Migrator {
+	return &Migrator{db: db}
+}
+
+// dialectName returns the name of the dialect of the underlying DataSource.
+func (m *Migrator) dialectName() string {
+	return m.db.source.Dialect().Name()
+}
+
+// tableFor resolves the quoted table name for a model value (e.g., &User{}).
+func (m *Migrator) tableFor(model any) (*schema.Model, string, error) {
+	parsed, err := m.db.GetModel(model)
+	if err != nil {
+		return nil, "", fmt.Errorf("migrator: failed to parse schema for type %T: %w", model, err)
+	}
+	return parsed, quoteTable(m.db.source.Dialect(), parsed), nil
+}
+
+// exec runs the given statements in order, stopping at the first failure.
+func (m *Migrator) exec(ctx context.Context, statements ...string) error {
+	for _, stmt := range statements {
+		fmt.Printf("Migrator: Executing: %s\n", stmt)
+		if _, err := m.db.conn(ctx).Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("migrator: failed to execute %q: %w", stmt, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,454 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Online (Zero-Downtime) Schema Changes ---
+
+// OnlineIndex describes an index built without blocking writes.
+type OnlineIndex struct {
+	Name    string
+	Columns []string // Column (or Go field) names, in order
+	Unique  bool
+}
+
+// CreateIndexOnlineSQL builds the statement creating an index without blocking writes:
+// CONCURRENTLY on Postgres (it cannot run inside a transaction; in SQL migrations, mark
+// the file with "-- +migrate NoTransaction"), ALGORITHM=INPLACE LOCK=NONE on MySQL and
+// ONLINE = ON on SQL Server.
+func CreateIndexOnlineSQL(dialect common.Dialect, model *schema.Model, index OnlineIndex) (string, error) {
+	if index.Name == "" || len(index.Columns) == 0 {
+		return "", fmt.Errorf("online index: a name and at least one column are required")
+	}
+	columns := make([]string, len(index.Columns))
+	for i, name := range index.Columns {
+		field, err := onlineColumn(model, name)
+		if err != nil {
+			return "", err
+		}
+		columns[i] = dialect.Quote(field.DBName)
+	}
+	unique := ""
+	if index.Unique {
+		unique = "UNIQUE "
+	}
+	table, name, cols := quoteTable(dialect, model), dialect.Quote(index.Name), strings.Join(columns, ", ")
+	switch dialect.Name() {
+	case "postgres":
+		return fmt.Sprintf("CREATE %sINDEX CONCURRENTLY IF NOT EXISTS %s ON %s (%s)", unique, name, table, cols), nil
+	case "mysql":
+		return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s) ALGORITHM=INPLACE LOCK=NONE", unique, name, table, cols), nil
+	case "sqlserver":
+		return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s) WITH (ONLINE = ON)", unique, name, table, cols), nil
+	}
+	return fmt.Sprintf("CREATE %sINDEX IF NOT EXISTS %s ON %s (%s)", unique, name, table, cols), nil
+}
+
+// CreateIndexOnline creates the index on the model's table without blocking writes
+// (see CreateIndexOnlineSQL).
+func (m *Migrator) CreateIndexOnline(ctx context.Context, model any, index OnlineIndex) error {
+	parsed, _, err := m.tableFor(model)
+	if err != nil {
+		return err
+	}
+	stmt, err := CreateIndexOnlineSQL(m.db.source.Dialect(), parsed, index)
+	if err != nil {
+		return err
+	}
+	return m.exec(ctx, stmt)
+}
+
+func onlineColumn(model *schema.Model, name string) (*schema.Field, error) {
+	if field, ok := model.GetFieldByDBName(name); ok {
+		return field, nil
+	}
+	if field, ok := model.GetField(name); ok && !field.IsIgnored {
+		return field, nil
+	}
+	return nil, fmt.Errorf("unknown column '%s' for table %s", name, model.TableName)
+}
+
+// OnlineSchemaChanger runs an ALTER TABLE through an external tool copying the table in
+// the background (pt-online-schema-change, gh-ost), for MySQL tables too large to be
+// altered in place. alter is the ALTER TABLE clause list, without "ALTER TABLE <table>".
+type OnlineSchemaChanger interface {
+	AlterTable(ctx context.Context, schemaName, table, alter string) error
+}
+
+// UseOnlineSchemaChanger sets the tool used by Migrator.AlterTableOnline on MySQL (nil
+// runs the ALTER TABLE directly). Call it before the DB is shared between goroutines.
+func (db *DB) UseOnlineSchemaChanger(changer OnlineSchemaChanger) {
+	db.osc = changer
+}
+
+// AlterTableOnline alters the model's table, e.g. AlterTableOnline(ctx, &User{},
+// "ADD COLUMN tier INT NULL"). On MySQL, a tool set with DB.UseOnlineSchemaChanger does
+// the change; otherwise the ALTER TABLE runs directly.
+func (m *Migrator) AlterTableOnline(ctx context.Context, model any, alter string) error {
+	parsed, table, err := m.tableFor(model)
+	if err != nil {
+		return err
+	}
+	if m.db.osc != nil && m.dialectName() == "mysql" {
+		fmt.Printf("Migrator: Altering %s online: %s\n", table, alter)
+		if err := m.db.osc.AlterTable(ctx, parsed.Schema, parsed.TableName, alter); err != nil {
+			return fmt.Errorf("migrator: online alter of %s failed: %w", parsed.TableName, err)
+		}
+		return nil
+	}
+	return m.exec(ctx, fmt.Sprintf("ALTER TABLE %s %s", table, alter))
+}
+
+// PtOnlineSchemaChange runs Percona's pt-online-schema-change. The password (Password,
+// or p= in DSN) is written to a temporary option file given in the DSN (F=), not on the
+// command line where other users could read it.
+type PtOnlineSchemaChange struct {
+	Path     string   // Binary, default "pt-online-schema-change"
+	DSN      string   // Connection in pt DSN syntax, e.g. "h=db1,u=migrator"
+	Password string   // Password of the DSN user
+	Database string   // Database of tables without a schema
+	Args     []string // Extra flags, e.g. "--max-load=Threads_running=25"
+	DryRun   bool     // --dry-run instead of --execute
+}
+
+func (p PtOnlineSchemaChange) AlterTable(ctx context.Context, schemaName, table, alter string) error {
+	var password string
+	p.DSN, password = p.splitPassword()
+	if password != "" {
+		file, err := writeOptionFile(password)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(file)
+		p.DSN = strings.TrimPrefix(p.DSN+",F="+file, ",")
+	}
+	path, args := p.command(schemaName, table, alter)
+	return runSchemaTool(ctx, path, args)
+}
+
+// splitPassword returns the DSN without its password (p=), and the password: Password,
+// else the one of the DSN.
+func (p PtOnlineSchemaChange) splitPassword() (string, string) {
+	var parts []string
+	password := p.Password
+	for _, part := range strings.Split(p.DSN, ",") {
+		if value, ok := strings.CutPrefix(part, "p="); ok {
+			if password == "" {
+				password = value
+			}
+			continue
+		}
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ","), password
+}
+
+func (p PtOnlineSchemaChange) command(schemaName, table, alter string) (string, []string) {
+	path := p.Path
+	if path == "" {
+		path = "pt-online-schema-change"
+	}
+	if schemaName == "" {
+		schemaName = p.Database
+	}
+	dsn := "D=" + schemaName + ",t=" + table
+	if p.DSN != "" {
+		dsn = p.DSN + "," + dsn
+	}
+	mode := "--execute"
+	if p.DryRun {
+		mode = "--dry-run"
+	}
+	args := append([]string{"--alter", alter, mode}, p.Args...)
+	return path, append(args, dsn)
+}
+
+// GhOst runs GitHub's gh-ost. The password is written to a temporary config file
+// (--conf), not on the command line where other users could read it.
+type GhOst struct {
+	Path     string // Binary, default "gh-ost"
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Database string   // Database of tables without a schema
+	Args     []string // Extra flags, e.g. "--allow-on-master", "--max-load=Threads_running=25"
+	DryRun   bool     // Without --execute gh-ost only checks the migration
+}
+
+func (g GhOst) AlterTable(ctx context.Context, schemaName, table, alter string) error {
+	conf := ""
+	if g.Password != "" {
+		file, err := writeOptionFile(g.Password)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(file)
+		conf = file
+	}
+	path, args := g.command(schemaName, table, alter, conf)
+	return runSchemaTool(ctx, path, args)
+}
+
+// command returns the gh-ost invocation; conf is the config file with the password.
+func (g GhOst) command(schemaName, table, alter, conf string) (string, []string) {
+	path := g.Path
+	if path == "" {
+		path = "gh-ost"
+	}
+	if schemaName == "" {
+		schemaName = g.Database
+	}
+	var args []string
+	if g.Host != "" {
+		args = append(args, "--host="+g.Host)
+	}
+	if g.Port != 0 {
+		args = append(args, fmt.Sprintf("--port=%d", g.Port))
+	}
+	if g.User != "" {
+		args = append(args, "--user="+g.User)
+	}
+	if conf != "" {
+		args = append(args, "--conf="+conf)
+	}
+	args = append(args, "--database="+schemaName, "--table="+table, "--alter="+alter)
+	args = append(args, g.Args...)
+	if !g.DryRun {
+		args = append(args, "--execute")
+	}
+	return path, args
+}
+
+// writeOptionFile writes a MySQL option file holding password in its [client] section,
+// readable by its owner only; remove it once the tool exits.
+func writeOptionFile(password string) (string, error) {
+	file, err := os.CreateTemp("", "typegorm-osc-*.cnf") // Mode 0600
+	if err != nil {
+		return "", fmt.Errorf("online schema change: writing the option file: %w", err)
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(password)
+	_, err = fmt.Fprintf(file, "[client]\npassword=\"%s\"\n", escaped)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("online schema change: writing the option file: %w", err)
+	}
+	return file.Name(), nil
+}
+
+// runSchemaTool runs an external tool, streaming its output.
+func runSchemaTool(ctx context.Context, path string, args []string) error {
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// --- Batched Backfills ---
+
+// BackfillColumn backfills a column of the model's table from a migration, next to
+// CreateIndexOnline and AlterTableOnline. See DB.BackfillColumn.
+func (m *Migrator) BackfillColumn(ctx context.Context, model any, column, expr string, opts ...BackfillOption) (BackfillReport, error) {
+	return m.db.BackfillColumn(ctx, model, column, expr, opts...)
+}
+
+// backfillOptions holds the optional behaviors of BackfillColumn.
+type backfillOptions struct {
+	batchSize int
+	rate      int                    // Rows per second, 0 = unlimited
+	all       bool                   // Also rewrite rows where the column is not NULL
+	progress  func(BackfillProgress) // Called after every batch
+}
+
+// BackfillOption defines a function type that modifies backfillOptions.
+type BackfillOption func(*backfillOptions)
+
+// BackfillBatchSize sets how many rows are updated per statement (default 1000).
+func BackfillBatchSize(n int) BackfillOption {
+	return func(opts *backfillOptions) { opts.batchSize = n }
+}
+
+// BackfillRate limits the backfill to about n rows per second, pausing between
+// batches, so that replicas and other traffic keep up.
+func BackfillRate(n int) BackfillOption {
+	return func(opts *backfillOptions) { opts.rate = n }
+}
+
+// BackfillAll rewrites every row; by default only rows where the column is NULL are.
+func BackfillAll() BackfillOption {
+	return func(opts *backfillOptions) { opts.all = true }
+}
+
+// OnBackfillProgress registers a function called after every batch.
+func OnBackfillProgress(fn func(BackfillProgress)) BackfillOption {
+	return func(opts *backfillOptions) { opts.progress = fn }
+}
+
+// BackfillProgress reports the state of a backfill after a batch.
+type BackfillProgress struct {
+	Table   string
+	Column  string
+	Batch   int   // Batches completed so far
+	Updated int64 // Rows updated so far
+}
+
+// BackfillReport summarizes a backfill.
+type BackfillReport struct {
+	Table   string
+	Column  string
+	Updated int64
+	Batches int
+}
+
+// BackfillColumn sets column to the SQL expression expr on every row where it is NULL,
+// walking the table by primary key in small batches, each its own statement:
+//
+//	report, err := db.BackfillColumn(ctx, &User{}, "email_lower", "LOWER(email)", typegorm.BackfillRate(5000))
+//
+// It is the data step of a zero-downtime column change: add the column as NULL, deploy
+// code writing it, backfill, then make it NOT NULL. expr is raw SQL; never build it
+// from user input.
+func (db *DB) BackfillColumn(ctx context.Context, model any, column, expr string, opts ...BackfillOption) (BackfillReport, error) {
+	options := backfillOptions{batchSize: 1000}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	if options.batchSize <= 0 {
+		options.batchSize = 1000
+	}
+	parsed, err := db.GetModel(model)
+	if err != nil {
+		return BackfillReport{}, fmt.Errorf("backfill: failed to parse schema for type %T: %w", model, err)
+	}
+	report := BackfillReport{Table: parsed.TableName}
+	field, err := onlineColumn(parsed, column)
+	if err != nil {
+		return report, fmt.Errorf("backfill: %w", err)
+	}
+	report.Column = field.DBName
+	if len(parsed.PrimaryKeys) != 1 {
+		return report, fmt.Errorf("backfill: %s needs a single-column primary key", parsed.TableName)
+	}
+
+	dialect := db.source.Dialect()
+	table, pk, col := quoteTable(dialect, parsed), dialect.Quote(parsed.PrimaryKeys[0].DBName), dialect.Quote(field.DBName)
+	selectSQL := func(after bool) string {
+		var conds []string
+		if after {
+			conds = append(conds, pk+" > "+dialect.BindVar(1))
+		}
+		if !options.all {
+			conds = append(conds, col+" IS NULL")
+		}
+		where := ""
+		if len(conds) > 0 {
+			where = " WHERE " + strings.Join(conds, " AND ")
+		}
+		return fmt.Sprintf("SELECT %s FROM %s%s ORDER BY %s LIMIT %d", pk, table, where, pk, options.batchSize)
+	}
+	fmt.Printf("Backfill: setting %s.%s = %s\n", parsed.TableName, field.DBName, expr)
+
+	var last any
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		started := time.Now()
+		var ids []any
+		if last == nil {
+			ids, err = db.selectIDs(ctx, selectSQL(false))
+		} else {
+			ids, err = db.selectIDs(ctx, selectSQL(true), last)
+		}
+		if err != nil {
+			return report, fmt.Errorf("backfill: failed to select rows of %s: %w", parsed.TableName, err)
+		}
+		if len(ids) == 0 {
+			break
+		}
+		updated, err := db.backfillBatch(ctx, table, pk, col, expr, ids)
+		if err != nil {
+			return report, fmt.Errorf("backfill: batch %d of %s failed: %w", report.Batches+1, parsed.TableName, err)
+		}
+		last = ids[len(ids)-1]
+		report.Batches++
+		report.Updated += updated
+		fmt.Printf("Backfill: %s batch %d updated %d row(s) (%d total)\n", parsed.TableName, report.Batches, updated, report.Updated)
+		if options.progress != nil {
+			options.progress(BackfillProgress{Table: report.Table, Column: report.Column, Batch: report.Batches, Updated: report.Updated})
+		}
+		if len(ids) < options.batchSize {
+			break
+		}
+		if options.rate > 0 {
+			budget := time.Duration(len(ids)) * time.Second / time.Duration(options.rate)
+			if wait := budget - time.Since(started); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return report, ctx.Err()
+				case <-time.After(wait):
+				}
+			}
+		}
+	}
+	return report, nil
+}
+
+// selectIDs returns the primary keys selected by query.
+func (db *DB) selectIDs(ctx context.Context, query string, args ...any) ([]any, error) {
+	logSQL("Executing SQL: %s | Args: %v\n", query, redactArgs(nil, args))
+	rows, err := db.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []any
+	for rows.Next() {
+		var id any
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// backfillBatch updates the rows of one batch.
+func (db *DB) backfillBatch(ctx context.Context, table, pk, col, expr string, ids []any) (int64, error) {
+	dialect := db.source.Dialect()
+	buf := getStmtBuffer(64 + len(expr) + 4*len(ids))
+	defer putStmtBuffer(buf)
+	buf.WriteString("UPDATE " + table + " SET " + col + " = " + expr + " WHERE " + pk + " IN (")
+	for i := range ids {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteBindVar(dialect, i+1)
+	}
+	buf.WriteByte(')')
+	updateSQL := buf.String()
+	logSQL("Executing SQL: %s | Args: %v\n", updateSQL, redactArgs(nil, ids))
+	res, err := db.conn(ctx).Exec(ctx, updateSQL, ids...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
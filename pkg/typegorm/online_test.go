@@ -0,0 +1,117 @@
+package typegorm
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateIndexOnlineSQL(t *testing.T) {
+	db, _ := newMockDB()
+	model, err := db.GetModel(&auditLog{})
+	require.NoError(t, err)
+	index := OnlineIndex{Name: "idx_audit_action", Columns: []string{"Action", "created_at"}}
+
+	tests := map[string]string{
+		"postgres":  "CREATE INDEX CONCURRENTLY IF NOT EXISTS `idx_audit_action` ON `audit_logs` (`action`, `created_at`)",
+		"mysql":     "CREATE INDEX `idx_audit_action` ON `audit_logs` (`action`, `created_at`) ALGORITHM=INPLACE LOCK=NONE",
+		"sqlserver": "CREATE INDEX `idx_audit_action` ON `audit_logs` (`action`, `created_at`) WITH (ONLINE = ON)",
+	}
+	for name, want := range tests {
+		got, err := CreateIndexOnlineSQL(&mockDialect{name: name}, model, index)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, name)
+	}
+
+	_, err = CreateIndexOnlineSQL(&mockDialect{name: "postgres"}, model, OnlineIndex{Name: "idx", Columns: []string{"missing"}})
+	assert.Error(t, err)
+}
+
+type recordingChanger struct {
+	schema, table, alter string
+}
+
+func (r *recordingChanger) AlterTable(_ context.Context, schemaName, table, alter string) error {
+	r.schema, r.table, r.alter = schemaName, table, alter
+	return nil
+}
+
+func TestAlterTableOnline(t *testing.T) {
+	db, source := newMockDBWithDialect("mysql")
+	changer := &recordingChanger{}
+	db.UseOnlineSchemaChanger(changer)
+
+	require.NoError(t, db.Migrator().AlterTableOnline(context.Background(), &auditLog{}, "ADD COLUMN tier INT NULL"))
+	assert.Equal(t, "audit_logs", changer.table)
+	assert.Equal(t, "ADD COLUMN tier INT NULL", changer.alter)
+	assert.Empty(t, source.Statements())
+
+	db, source = newMockDBWithDialect("postgres")
+	db.UseOnlineSchemaChanger(changer)
+	require.NoError(t, db.Migrator().AlterTableOnline(context.Background(), &auditLog{}, "ADD COLUMN tier INT NULL"))
+	assert.Equal(t, "ALTER TABLE `audit_logs` ADD COLUMN tier INT NULL", source.lastStatement().SQL)
+}
+
+func TestOnlineSchemaChangeCommands(t *testing.T) {
+	path, args := PtOnlineSchemaChange{DSN: "h=db1,u=migrator", Args: []string{"--max-load=Threads_running=25"}}.
+		command("shop", "orders", "ADD COLUMN note TEXT")
+	assert.Equal(t, "pt-online-schema-change", path)
+	assert.Equal(t, []string{"--alter", "ADD COLUMN note TEXT", "--execute", "--max-load=Threads_running=25", "h=db1,u=migrator,D=shop,t=orders"}, args)
+
+	path, args = GhOst{Host: "db1", Port: 3306, User: "migrator", Password: "secret", Database: "shop", DryRun: true}.
+		command("", "orders", "ADD COLUMN note TEXT", "/tmp/osc.cnf")
+	assert.Equal(t, "gh-ost", path)
+	assert.Equal(t, []string{"--host=db1", "--port=3306", "--user=migrator", "--conf=/tmp/osc.cnf", "--database=shop", "--table=orders", "--alter=ADD COLUMN note TEXT"}, args,
+		"The password is not on the command line")
+
+	dsn, password := PtOnlineSchemaChange{DSN: "h=db1,p=secret,u=migrator"}.splitPassword()
+	assert.Equal(t, "h=db1,u=migrator", dsn)
+	assert.Equal(t, "secret", password)
+}
+
+func TestWriteOptionFile(t *testing.T) {
+	file, err := writeOptionFile(`se"c\ret`)
+	require.NoError(t, err)
+	defer os.Remove(file)
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "[client]\npassword=\"se\\\"c\\\\ret\"\n", string(content))
+}
+
+func TestMigrator_BackfillColumn(t *testing.T) {
+	db, source := newMockDB()
+	source.queueRows([]string{"id"})
+	report, err := db.Migrator().BackfillColumn(context.Background(), &auditLog{}, "Action", "UPPER(`action`)")
+	require.NoError(t, err)
+	assert.Equal(t, BackfillReport{Table: "audit_logs", Column: "action"}, report)
+}
+
+func TestBackfillColumn(t *testing.T) {
+	db, source := newMockDB()
+	source.queueRows([]string{"id"}, []any{int64(1)}, []any{int64(2)})
+	source.queueRows([]string{"id"}, []any{int64(3)})
+
+	var batches []int
+	report, err := db.BackfillColumn(context.Background(), &auditLog{}, "Action", "UPPER(`action`)",
+		BackfillBatchSize(2), BackfillRate(1000), OnBackfillProgress(func(p BackfillProgress) { batches = append(batches, p.Batch) }))
+	require.NoError(t, err)
+	assert.Equal(t, BackfillReport{Table: "audit_logs", Column: "action", Updated: 2, Batches: 2}, report) // 1 affected row per mock UPDATE
+	assert.Equal(t, []int{1, 2}, batches)
+
+	stmts := source.Statements()
+	require.Len(t, stmts, 4)
+	assert.Equal(t, "SELECT `id` FROM `audit_logs` WHERE `action` IS NULL ORDER BY `id` LIMIT 2", stmts[0].SQL)
+	assert.Equal(t, "UPDATE `audit_logs` SET `action` = UPPER(`action`) WHERE `id` IN (?, ?)", stmts[1].SQL)
+	assert.Equal(t, "SELECT `id` FROM `audit_logs` WHERE `id` > ? AND `action` IS NULL ORDER BY `id` LIMIT 2", stmts[2].SQL)
+	assert.Equal(t, []any{int64(2)}, stmts[2].Args)
+	assert.Equal(t, []any{int64(3)}, stmts[3].Args)
+
+	_, err = db.BackfillColumn(context.Background(), &auditLog{}, "missing", "1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,64 @@
+package typegorm
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/debug"
+)
+
+// --- Panic Recovery ---
+
+// PanicError is returned instead of crashing when a reflective path (hooks, scanning,
+// condition building) panics, e.g. on an unexpected struct shape.
+type PanicError struct {
+	Operation string // e.g. "Create", "FindByID", "hook BeforeCreate"
+	Model     string // Go type of the value being processed
+	Value     any    // Value passed to panic
+	Stack     []byte // Stack trace captured at the panic
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("typegorm: panic in %s for %s: %v", e.Operation, e.Model, e.Value)
+}
+
+// Unwrap exposes the panic value when it was an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+func newPanicError(operation string, value any, recovered any) *PanicError {
+	model := "<nil>"
+	if rv, ok := value.(reflect.Value); ok {
+		if rv.IsValid() {
+			model = rv.Type().String()
+		}
+	} else if value != nil {
+		model = reflect.TypeOf(value).String()
+	}
+	err := &PanicError{Operation: operation, Model: model, Value: recovered, Stack: debug.Stack()}
+	fmt.Printf("Recovered from %v\n%s\n", err, err.Stack)
+	return err
+}
+
+// recoverResult must be deferred by operations returning *Result (named result):
+//
+//	func (db *DB) Create(ctx context.Context, value any) (result *Result) {
+//		defer recoverResult(&result, "Create", value)
+func recoverResult(result **Result, operation string, value any) {
+	if r := recover(); r != nil {
+		if *result == nil {
+			*result = &Result{}
+		}
+		(*result).Error = newPanicError(operation, value, r)
+	}
+}
+
+// recoverError is the equivalent of recoverResult for functions returning an error.
+func recoverError(err *error, operation string, value any) {
+	if r := recover(); r != nil {
+		*err = newPanicError(operation, value, r)
+	}
+}
@@ -0,0 +1,53 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type panicScanUser struct {
+	ID    uint `typegorm:"primaryKey;autoIncrement"`
+	Name  string
+	Label string `typegorm:"computed"`
+}
+
+func (u *panicScanUser) AfterScan() {
+	panic(errors.New("unexpected shape"))
+}
+
+func TestPanicInFind_ReturnsError(t *testing.T) {
+	db, source := newMockDB()
+
+	source.queueRows([]string{"id", "name"}, []any{uint(1), "Ana"})
+	var users []panicScanUser
+	result := db.Find(context.Background(), &users)
+
+	var panicErr *PanicError
+	require.ErrorAs(t, result.Error, &panicErr)
+	assert.Equal(t, "Find", panicErr.Operation)
+	assert.Equal(t, "*[]typegorm.panicScanUser", panicErr.Model)
+	assert.NotEmpty(t, panicErr.Stack)
+	assert.EqualError(t, errors.Unwrap(panicErr), "unexpected shape")
+}
+
+func TestPanicInTxFindByID_ReturnsError(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	tx, err := db.Begin(ctx, nil)
+	require.NoError(t, err)
+	source.queueRows([]string{"id", "name"}, []any{uint(1), "Ana"})
+	var user panicScanUser
+	result := tx.FindByID(ctx, &user, 1)
+	require.NoError(t, tx.Rollback(), "the transaction stays usable")
+
+	var panicErr *PanicError
+	require.ErrorAs(t, result.Error, &panicErr)
+	assert.Equal(t, "FindByID", panicErr.Operation)
+	assert.Equal(t, "*typegorm.panicScanUser", panicErr.Model)
+	assert.Contains(t, result.Error.Error(), "typegorm: panic in FindByID for *typegorm.panicScanUser: unexpected shape")
+}
@@ -0,0 +1,262 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Data-Access Policies ---
+
+// PolicyFunc centralizes data-access rules: it runs before a statement executes, and
+// rejects it by returning an error, or narrows it by replacing *cond. op is "Create",
+// "Find", "FindFirst", "FindByID", "Count", "Export", "Update" or "Delete"; cond is the
+// ParseCondition tree of the statement (for Create, the non-zero fields of the new
+// row, and changes are ignored); policies of reads can also hide columns (see
+// MaskColumns). For the primary key operations (FindByID, Updates, Delete, Restore)
+// cond is the primary key: when a policy narrows it, writes add the new condition to
+// their WHERE, leaving a row that does not match untouched (RowsAffected is 0), and
+// FindByID treats such a row as missing. The SELECTs of FindQuery and FindUnion run as
+// "Find", the extra conditions of UpdateIf as "Update", the rows joined by Joins as a
+// "Find" on the joined model (narrowing its ON clause), and the join table reads of
+// many-to-many preloads as a "Find" on a column-less model named after the table.
+//
+//	db.UsePolicy(func(ctx context.Context, op string, model *schema.Model, cond *typegorm.Expr) error {
+//		if op == "Delete" && model.Name == "AuditLog" {
+//			return errors.New("audit logs are append-only")
+//		}
+//		if _, ok := model.GetFieldByDBName("org_id"); ok {
+//			*cond = typegorm.And(*cond, typegorm.Eq("org_id", orgFrom(ctx)))
+//		}
+//		return nil
+//	})
+type PolicyFunc func(ctx context.Context, op string, model *schema.Model, cond *Expr) error
+
+// UsePolicy adds a data-access policy; policies run in the order they were added,
+// after the condition rewriter, also in transactions started afterwards. Call it
+// before the DB is shared between goroutines.
+func (db *DB) UsePolicy(policy PolicyFunc) {
+	db.policies = append(db.policies, policy)
+}
+
+// applyPolicies runs the policies on a condition and returns the condition to run.
+// Rejections wrap ErrPolicyDenied.
+func applyPolicies(ctx context.Context, policies []PolicyFunc, op string, model *schema.Model, cond Expr) (Expr, error) {
+	for _, policy := range policies {
+		if err := policy(ctx, op, model, &cond); err != nil {
+			return Expr{}, fmt.Errorf("%w: %s on %s: %w", ErrPolicyDenied, op, model.Name, err)
+		}
+	}
+	return cond, nil
+}
+
+// checkCreatePolicies runs the policies on a row about to be inserted.
+func checkCreatePolicies(ctx context.Context, policies []PolicyFunc, model *schema.Model, value any) error {
+	if len(policies) == 0 {
+		return nil
+	}
+	cond, err := ParseCondition(model, value)
+	if err != nil {
+		return err
+	}
+	_, err = applyPolicies(ctx, policies, "Create", model, cond)
+	return err
+}
+
+// policyClause runs the policies on the primary key condition of a FindByID, Updates,
+// Delete or Restore. When they narrow it, it returns the narrowed condition as a clause
+// (with BindVar(1) placeholders) and its arguments; empty when they leave it unchanged.
+func policyClause(ctx context.Context, policies []PolicyFunc, dialect common.Dialect, op string, model *schema.Model, pkValues []any) (string, []any, error) {
+	if len(policies) == 0 {
+		return "", nil, nil
+	}
+	key := make([]any, len(model.PrimaryKeys))
+	for i, pk := range model.PrimaryKeys {
+		key[i] = Eq(pk.DBName, pkValues[i])
+	}
+	cond := And(key...)
+	narrowed, err := applyPolicies(ctx, policies, op, model, cond)
+	if err != nil {
+		return "", nil, err
+	}
+	if reflect.DeepEqual(narrowed, cond) {
+		return "", nil, nil
+	}
+	clause, args, err := narrowed.build(dialect, model)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid condition from policy on %s: %w", model.Name, err)
+	}
+	return clause, args, nil
+}
+
+// withPolicyClause ANDs the clause of policyClause to the WHERE ending a write
+// statement, so that a row not matching the policies is left untouched (RowsAffected
+// is 0) in the same statement, without a separate check that could race with it.
+func withPolicyClause(dialect common.Dialect, sqlQuery string, args []any, clause string, clauseArgs []any) (string, []any) {
+	if clause == "" {
+		return sqlQuery, args
+	}
+	return renumberBindVars(dialect, sqlQuery+" AND "+clause), append(args, clauseArgs...)
+}
+
+// --- Column Masking ---
+
+type columnMaskKey struct{}
+
+// columnMask collects the columns masked by the policies of a read.
+type columnMask struct{ names []string }
+
+// MaskColumns hides columns (Go or column names) from the read a policy is checking:
+// they are left out of the SELECT and returned as zero values (nil pointers). Call it
+// from a PolicyFunc on "Find", "FindFirst" or "FindByID"; the SELECTs of FindQuery and
+// FindUnion (which run as "Find") still read the masked columns, so that the operands
+// of a union stay aligned, and clear them in the rows returned. Names the model does
+// not have, and primary keys, are ignored, so one policy can mask a column on every
+// model that has it. A read whose condition, Order, Group or Having uses a masked
+// column is rejected with ErrPolicyDenied, as filtering or sorting on it reveals its
+// values; so is a many-to-many preload when a policy masks a key of the join table:
+//
+//	db.UsePolicy(func(ctx context.Context, op string, model *schema.Model, cond *typegorm.Expr) error {
+//		if !isAdmin(ctx) {
+//			typegorm.MaskColumns(ctx, "salary")
+//		}
+//		return nil
+//	})
+func MaskColumns(ctx context.Context, columns ...string) {
+	if mask, ok := ctx.Value(columnMaskKey{}).(*columnMask); ok {
+		mask.names = append(mask.names, columns...)
+	}
+}
+
+// withColumnMask returns the context given to the policies of a read, collecting the
+// columns they mask (nil without policies).
+func withColumnMask(ctx context.Context, policies []PolicyFunc) (context.Context, *columnMask) {
+	if len(policies) == 0 {
+		return ctx, nil
+	}
+	mask := &columnMask{}
+	return context.WithValue(ctx, columnMaskKey{}, mask), mask
+}
+
+// maskedFields resolves the masked columns of the model.
+func (m *columnMask) maskedFields(model *schema.Model) []*schema.Field {
+	if m == nil {
+		return nil
+	}
+	var fields []*schema.Field
+	for _, name := range m.names {
+		field, ok := model.GetFieldByDBName(name)
+		if !ok {
+			field, ok = model.GetField(name)
+		}
+		if ok && !field.IsIgnored && !field.IsPrimaryKey && !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// maskedSelectColumns is selectColumns without the masked fields.
+func maskedSelectColumns(dialect common.Dialect, model *schema.Model, masked []*schema.Field) (string, []*schema.Field) {
+	selectList, scanFields := selectColumns(dialect, model)
+	if len(masked) == 0 {
+		return selectList, scanFields
+	}
+	quoted := make([]string, 0, len(scanFields))
+	fields := make([]*schema.Field, 0, len(scanFields))
+	for _, field := range scanFields {
+		if !slices.Contains(masked, field) {
+			quoted = append(quoted, dialect.Quote(field.DBName))
+			fields = append(fields, field)
+		}
+	}
+	return strings.Join(quoted, ", "), fields
+}
+
+// zeroMaskedFields clears the masked fields of a loaded struct.
+func zeroMaskedFields(structValue reflect.Value, masked []*schema.Field) {
+	for _, field := range masked {
+		if fieldValue := structValue.FieldByName(field.GoName); fieldValue.IsValid() && fieldValue.CanSet() {
+			fieldValue.SetZero()
+		}
+	}
+}
+
+// checkMaskedUse rejects a read that filters, orders, groups on or selects (see Columns)
+// a masked column. cond
+// is the condition given by the caller, before the rewriter and the policies (which
+// may use any column); SQL fragments are matched word by word, so a masked name in
+// them is rejected even when it is not a column reference.
+func checkMaskedUse(op string, model *schema.Model, masked []*schema.Field, cond any, options queryOptions) error {
+	if len(masked) == 0 {
+		return nil
+	}
+	used := func(texts ...string) *schema.Field {
+		for _, text := range texts {
+			if field := mentionedField(text, masked); field != nil {
+				return field
+			}
+		}
+		return nil
+	}
+	var field *schema.Field
+	for _, condition := range []any{cond, chainWhere(options.having)} {
+		expr, err := ParseCondition(model, condition)
+		if err != nil {
+			return fmt.Errorf("%w: %s on %s: cannot check the condition against the masked columns: %w", ErrPolicyDenied, op, model.Name, err)
+		}
+		expr.Walk(func(e Expr) bool {
+			if field == nil {
+				field = used(e.column)
+				if raw, ok := firstString(e.values); ok && e.op == "sql" && field == nil {
+					field = used(raw)
+				}
+			}
+			return field == nil
+		})
+		if field != nil {
+			break
+		}
+	}
+	if field == nil {
+		field = used(append([]string{options.orderBy}, options.groupBy...)...)
+	}
+	if field != nil {
+		return fmt.Errorf("%w: %s on %s: column %s is masked and cannot be used to filter, order or group the rows", ErrPolicyDenied, op, model.Name, field.DBName)
+	}
+	if field = used(options.columns...); field != nil {
+		return fmt.Errorf("%w: %s on %s: column %s is masked and cannot be selected", ErrPolicyDenied, op, model.Name, field.DBName)
+	}
+	return nil
+}
+
+// mentionedField returns the masked field that text names (column or Go name, in any
+// case, possibly qualified or quoted), nil when there is none.
+func mentionedField(text string, masked []*schema.Field) *schema.Field {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		for _, field := range masked {
+			if strings.EqualFold(word, field.DBName) || strings.EqualFold(word, field.GoName) {
+				return field
+			}
+		}
+	}
+	return nil
+}
+
+// firstString returns the first value when it is a string (the SQL of a SQL node).
+func firstString(values []any) (string, bool) {
+	if len(values) == 0 {
+		return "", false
+	}
+	s, ok := values[0].(string)
+	return s, ok
+}
@@ -0,0 +1,222 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ageScope restricts maskUser statements to age 42, like a tenant filter would.
+func ageScope(ctx context.Context, op string, model *schema.Model, cond *Expr) error {
+	if op == "Create" {
+		return nil
+	}
+	*cond = And(*cond, Eq("age", 42))
+	return nil
+}
+
+func TestUsePolicy_RejectsStatements(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	var ops []string
+	db.UsePolicy(func(ctx context.Context, op string, model *schema.Model, cond *Expr) error {
+		ops = append(ops, op)
+		if op == "Delete" && model.Name == "maskUser" {
+			return errors.New("users are never deleted")
+		}
+		return nil
+	})
+
+	res := db.Delete(ctx, &maskUser{ID: 1})
+	assert.ErrorIs(t, res.Error, ErrPolicyDenied)
+	assert.ErrorContains(t, res.Error, "Delete on maskUser: users are never deleted")
+	assert.Empty(t, source.Statements(), "nothing runs once a policy rejects the statement")
+
+	err := db.Model(&maskUser{}).Where(Gt("age", 1)).Delete(ctx).Error
+	assert.ErrorIs(t, err, ErrPolicyDenied)
+
+	require.NoError(t, db.Create(ctx, &maskUser{Name: "Ana"}).Error)
+	assert.Equal(t, []string{"Delete", "Delete", "Create"}, ops)
+}
+
+func TestUsePolicy_NarrowsConditions(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	db.UsePolicy(ageScope)
+
+	var users []maskUser
+	require.NoError(t, db.Find(ctx, &users, map[string]any{"name": "Ana"}).Error)
+	assert.Equal(t, "SELECT `id`, `name`, `email`, `age` FROM `mask_users` WHERE (`name` = ? AND `age` = ?)", source.lastStatement().SQL)
+	assert.Equal(t, []any{"Ana", 42}, source.lastStatement().Args)
+
+	source.queueRows([]string{"count"}, []any{int64(3)})
+	count, err := db.Model(&maskUser{}).Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.Equal(t, "SELECT COUNT(*) FROM `mask_users` WHERE `age` = ?", source.lastStatement().SQL)
+}
+
+func TestUsePolicy_PrimaryKeyOperations(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	db.UsePolicy(ageScope)
+
+	// The policy narrows the write itself: a row outside its scope is not deleted
+	source.affected = 0
+	res := db.Delete(ctx, &maskUser{ID: 7})
+	require.NoError(t, res.Error)
+	assert.Zero(t, res.RowsAffected)
+	assert.Equal(t, []mockStatement{{SQL: "DELETE FROM `mask_users` WHERE `id` = ? AND (`id` = ? AND `age` = ?)", Args: []any{uint(7), uint(7), 42}}}, source.Statements())
+
+	// FindByID reads the row with the policy in the same SELECT
+	var user maskUser
+	assert.ErrorIs(t, db.FindByID(ctx, &user, uint(7)).Error, sql.ErrNoRows)
+	assert.Equal(t, "SELECT `id`, `name`, `email`, `age` FROM `mask_users` WHERE `id` = ? AND (`id` = ? AND `age` = ?) LIMIT 1", source.lastStatement().SQL)
+	assert.Equal(t, []any{uint(7), uint(7), 42}, source.lastStatement().Args)
+
+	// Inside the scope: the update runs, in one statement
+	source.affected = 1
+	before := len(source.Statements())
+	res = db.Updates(ctx, &maskUser{ID: 7}, map[string]any{"name": "Bia"})
+	require.NoError(t, res.Error)
+	assert.Equal(t, int64(1), res.RowsAffected)
+	require.Len(t, source.Statements(), before+1)
+	assert.Equal(t, "UPDATE `mask_users` SET `name` = ? WHERE `id` = ? AND (`id` = ? AND `age` = ?)", source.lastStatement().SQL)
+	assert.Equal(t, []any{"Bia", uint(7), uint(7), 42}, source.lastStatement().Args)
+
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Delete(ctx, &maskUser{ID: 8}).Error)
+	assert.Equal(t, "DELETE FROM `mask_users` WHERE `id` = ? AND (`id` = ? AND `age` = ?)", source.lastStatement().SQL)
+	require.NoError(t, tx.Updates(ctx, &maskUser{ID: 8}, map[string]any{"name": "Eva"}).Error)
+	assert.Contains(t, source.lastStatement().SQL, "WHERE `id` = ? AND (`id` = ? AND `age` = ?)")
+	require.NoError(t, tx.Rollback())
+}
+
+func TestUsePolicy_QueriesConditionalUpdatesAndJoinTables(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	var models []string
+	db.UsePolicy(func(ctx context.Context, op string, model *schema.Model, cond *Expr) error {
+		models = append(models, op+" "+model.Name)
+		if model.Name == "maskUser" {
+			return ageScope(ctx, op, model, cond)
+		}
+		if model.Name == "artigo_categorias" {
+			*cond = And(*cond, Eq("visible", true))
+		}
+		return nil
+	})
+
+	var users []maskUser
+	require.NoError(t, db.FindQuery(ctx, &users, UnionAll(From(&maskUser{}, map[string]any{"name": "Ana"}), From(&maskUser{}))).Error)
+	assert.Equal(t, "SELECT `id`, `name`, `email`, `age` FROM `mask_users` WHERE (`name` = ? AND `age` = ?) UNION ALL SELECT `id`, `name`, `email`, `age` FROM `mask_users` WHERE `age` = ?", source.lastStatement().SQL)
+	assert.Equal(t, []any{"Ana", 42, 42}, source.lastStatement().Args)
+
+	require.NoError(t, db.UpdateIf(ctx, &maskUser{ID: 3}, map[string]any{"name": "Bia"}, map[string]any{"name": "Ana"}).Error)
+	assert.Equal(t, "UPDATE `mask_users` SET `name` = ? WHERE `id` = ? AND (`name` = ? AND `age` = ?)", source.lastStatement().SQL)
+	assert.Equal(t, []any{"Bia", uint(3), "Ana", 42}, source.lastStatement().Args)
+
+	source.queueRows([]string{"id", "titulo", "publicado", "autor_id"}, []any{uint(10), "a", true, uint(1)})
+	var artigos []preloadArtigo
+	require.NoError(t, db.Find(ctx, &artigos, Preload("Categorias")).Error)
+	assert.Equal(t, "SELECT `artigo_id`, `categoria_id` FROM `artigo_categorias` WHERE (`artigo_id` IN (?) AND `visible` = ?)", source.lastStatement().SQL)
+	assert.Equal(t, []any{uint(10), true}, source.lastStatement().Args)
+	assert.Equal(t, []string{"Find maskUser", "Find maskUser", "Update maskUser", "Find preloadArtigo", "Find artigo_categorias"}, models)
+}
+
+func TestUsePolicy_SoftDeleteAndRestoreKeepTheirFilters(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	db.UsePolicy(func(ctx context.Context, op string, model *schema.Model, cond *Expr) error {
+		*cond = And(*cond, Neq("title", "locked"))
+		return nil
+	})
+
+	require.NoError(t, db.Delete(ctx, &softNote{ID: 3}).Error)
+	stmt := source.lastStatement()
+	assert.Equal(t, "UPDATE `soft_notes` SET `deleted_at` = ? WHERE `id` = ? AND `deleted_at` IS NULL AND (`id` = ? AND `title` <> ?)", stmt.SQL)
+	assert.Equal(t, []any{uint(3), uint(3), "locked"}, stmt.Args[1:])
+
+	require.NoError(t, db.Restore(ctx, &softNote{ID: 3}).Error)
+	stmt = source.lastStatement()
+	assert.Equal(t, "UPDATE `soft_notes` SET `deleted_at` = NULL WHERE `id` = ? AND `deleted_at` IS NOT NULL AND (`id` = ? AND `title` <> ?)", stmt.SQL)
+	assert.Equal(t, []any{uint(3), uint(3), "locked"}, stmt.Args)
+	assert.Len(t, source.Statements(), 2, "no separate policy check")
+}
+
+func TestMaskColumns_HidesColumnsFromReads(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	db.UsePolicy(func(ctx context.Context, op string, model *schema.Model, cond *Expr) error {
+		MaskColumns(ctx, "email", "Age", "salary", "id") // Unknown columns and keys are ignored
+		return nil
+	})
+
+	source.queueRows([]string{"id", "name"}, []any{uint(1), "Ana"})
+	var users []maskUser
+	require.NoError(t, db.Find(ctx, &users).Error)
+	assert.Equal(t, "SELECT `id`, `name` FROM `mask_users`", source.lastStatement().SQL)
+	assert.Equal(t, []maskUser{{ID: 1, Name: "Ana"}}, users)
+
+	source.queueRows([]string{"id", "name"}, []any{uint(2), "Bia"})
+	user := maskUser{Email: "stale@example.com", Age: 9}
+	require.NoError(t, db.FindByID(ctx, &user, uint(2)).Error)
+	assert.Equal(t, "SELECT `id`, `name` FROM `mask_users` WHERE `id` = ? LIMIT 1", source.lastStatement().SQL)
+	assert.Equal(t, maskUser{ID: 2, Name: "Bia"}, user, "masked fields are returned as zero values")
+
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	source.queueRows([]string{"id", "name"}, []any{uint(3), "Eva"})
+	user = maskUser{Age: 9}
+	require.NoError(t, tx.FindFirst(ctx, &user, map[string]any{"name": "Eva"}).Error)
+	assert.Contains(t, source.lastStatement().SQL, "SELECT `id`, `name` FROM `mask_users` WHERE `name` = ?")
+	assert.Equal(t, maskUser{ID: 3, Name: "Eva"}, user)
+	require.NoError(t, tx.Rollback())
+
+	MaskColumns(ctx, "email") // Outside a policy: no effect
+}
+
+func TestMaskColumns_RejectsFilteringAndOrderingOnMaskedColumns(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	db.UsePolicy(func(ctx context.Context, op string, model *schema.Model, cond *Expr) error {
+		MaskColumns(ctx, "age")
+		*cond = And(*cond, Gt("age", 0)) // The policies themselves may use masked columns
+		return nil
+	})
+
+	var users []maskUser
+	for _, args := range [][]any{
+		{Gt("age", 30)},
+		{map[string]any{"Age": 30}},
+		{SQL("AGE between ? and ?", 30, 40)},
+		{Order("`age` DESC")},
+		{Group("age")},
+		{Group("name"), Having("max(age) > ?", 30)},
+		{Group("name"), Columns("name", "MAX(age) AS email")},
+	} {
+		err := db.Find(ctx, &users, args...).Error
+		require.ErrorIs(t, err, ErrPolicyDenied, "%v", args)
+		assert.Contains(t, err.Error(), "column age is masked")
+	}
+	var user maskUser
+	require.ErrorIs(t, db.FindFirst(ctx, &user, Order("age")).Error, ErrPolicyDenied)
+	assert.Empty(t, source.Statements(), "nothing is read")
+
+	source.queueRows([]string{"id", "name", "email"}, []any{uint(1), "Ana", "ana@example.com"})
+	require.NoError(t, db.Find(ctx, &users, Eq("name", "Ana"), Order("name")).Error)
+	assert.Equal(t, "SELECT `id`, `name`, `email` FROM `mask_users` WHERE (`name` = ? AND `age` > ?) ORDER BY `name`", source.lastStatement().SQL)
+
+	// FindQuery and FindUnion read the masked columns and clear them
+	source.queueRows([]string{"id", "name", "email", "age"}, []any{uint(2), "Bia", "bia@example.com", 35})
+	require.NoError(t, db.FindUnion(ctx, &users, UnionAll(From(&maskUser{}), From(&maskUser{}, Eq("name", "Bia")))).Error)
+	assert.Equal(t, []maskUser{{ID: 2, Name: "Bia", Email: "bia@example.com"}}, users)
+	require.ErrorIs(t, db.FindUnion(ctx, &users, Union(From(&maskUser{}), From(&maskUser{})), Order("age")).Error, ErrPolicyDenied)
+	require.ErrorIs(t, db.FindQuery(ctx, &users, From(&maskUser{}, Lt("age", 40))).Error, ErrPolicyDenied)
+}
@@ -0,0 +1,159 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+	"github.com/chmenegatti/typegorm/pkg/dialects"
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+)
+
+// --- Named Connection Pools ---
+
+// Named pools (config: database.pools) are extra connection pools on the primary DSN,
+// each with its own limits, so e.g. long batch/analytics queries run on a small "batch"
+// pool and cannot starve the transactional traffic of the default pool:
+//
+//	database:
+//	  pools:
+//	    batch: { maxOpenConns: 2 }
+//
+//	err := db.Find(typegorm.WithPool(ctx, "batch"), &report).Error
+
+type poolKey struct{}
+
+// WithPool routes the operations (and transactions begun) with ctx to the named pool.
+// Unknown names fall back to the default pool with a warning.
+func WithPool(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, poolKey{}, name)
+}
+
+// PoolNames returns the names of the configured secondary pools, sorted.
+func (db *DB) PoolNames() []string {
+	names := make([]string, 0, len(db.pools))
+	for name := range db.pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// namedPool returns the pool selected by WithPool, or nil for the default pool.
+func (db *DB) namedPool(ctx context.Context) common.DataSource {
+	name, ok := ctx.Value(poolKey{}).(string)
+	if !ok || name == "" || name == "default" {
+		return nil
+	}
+	if ds, ok := db.pools[name]; ok {
+		return ds
+	}
+	fmt.Printf("Warning: unknown connection pool %q, using the default pool\n", name)
+	return nil
+}
+
+// conn returns the DataSource the statements of ctx run on.
+func (db *DB) conn(ctx context.Context) common.DataSource {
+	if ds := db.namedPool(ctx); ds != nil {
+		return db.writeGuard(guardSource(ctx, db.throttleSource(ds)))
+	}
+	return db.writeGuard(guardSource(ctx, db.throttleSource(db.primarySource())))
+}
+
+// poolSource returns the unwrapped DataSource of the pool of ctx, for the capabilities
+// (common.ConnPinner, common.TwoPhaseCommitter) the wrappers of conn do not expose.
+func (db *DB) poolSource(ctx context.Context) common.DataSource {
+	if ds := db.namedPool(ctx); ds != nil {
+		return ds
+	}
+	return db.source
+}
+
+// pinnedConn is a connection reserved for statements sharing session state. Its
+// statements go through the throttle and the StatementGuard of the context it was
+// pinned with; conn runs them directly (e.g. to restore the session when ctx is done).
+type pinnedConn struct {
+	common.DataSource // Only Exec, Query and QueryRow
+	conn              common.Conn
+}
+
+// pinConn reserves a connection of the pool of ctx; release it with conn.Close (or
+// conn.Discard when its session could not be restored). Data sources that do not
+// implement common.ConnPinner fail with ErrUnsupportedDialect.
+func (db *DB) pinConn(ctx context.Context) (*pinnedConn, error) {
+	ds := db.poolSource(ctx)
+	pinner, ok := ds.(common.ConnPinner)
+	if !ok {
+		return nil, fmt.Errorf("%s data source cannot reserve a connection: %w", ds.Dialect().Name(), ErrUnsupportedDialect)
+	}
+	conn, err := pinner.PinConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pinnedConn{DataSource: db.writeGuard(guardSource(ctx, db.throttleSource(connSource{conn: conn}))), conn: conn}, nil
+}
+
+// connSource runs the statements of a reserved connection, so that the DataSource
+// wrappers (throttle, StatementGuard) apply to them; only Exec, Query and QueryRow
+// can be called.
+type connSource struct {
+	common.DataSource
+	conn common.Conn
+}
+
+func (s connSource) Exec(ctx context.Context, query string, args ...any) (common.Result, error) {
+	return s.conn.Exec(ctx, query, args...)
+}
+
+func (s connSource) Query(ctx context.Context, query string, args ...any) (common.Rows, error) {
+	return s.conn.Query(ctx, query, args...)
+}
+
+func (s connSource) QueryRow(ctx context.Context, query string, args ...any) common.RowScanner {
+	return s.conn.QueryRow(ctx, query, args...)
+}
+
+// openPools connects one DataSource per named pool, sharing the primary DSN.
+func openPools(cfg config.DatabaseConfig) (map[string]common.DataSource, error) {
+	if len(cfg.Pools) == 0 {
+		return nil, nil
+	}
+	pools := make(map[string]common.DataSource, len(cfg.Pools))
+	for name, poolCfg := range cfg.Pools {
+		poolDBCfg := cfg
+		poolDBCfg.Pool = poolCfg
+		ds, err := connectSource(poolDBCfg)
+		if err != nil {
+			_ = closePools(pools)
+			return nil, fmt.Errorf("failed to open connection pool %q: %w", name, err)
+		}
+		pools[name] = ds
+	}
+	fmt.Printf("Opened %d named connection pool(s).\n", len(pools))
+	return pools, nil
+}
+
+func closePools(pools map[string]common.DataSource) error {
+	var errs []error
+	for name, ds := range pools {
+		if err := ds.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("pool %q: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// connectSource opens a DataSource of the configured dialect.
+func connectSource(cfg config.DatabaseConfig) (common.DataSource, error) {
+	factory := dialects.Get(cfg.Dialect)
+	if factory == nil {
+		return nil, fmt.Errorf("unsupported dialect: %s", cfg.Dialect)
+	}
+	ds := factory()
+	if err := ds.Connect(cfg); err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
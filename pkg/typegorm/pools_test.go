@@ -0,0 +1,78 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+)
+
+func newMockDBWithPool(name string) (*DB, *mockSource, *mockSource) {
+	db, primary := newMockDB()
+	pool := newMockSource()
+	db.pools = map[string]common.DataSource{name: pool}
+	return db, primary, pool
+}
+
+func TestWithPool_RoutesOperations(t *testing.T) {
+	db, primary, batch := newMockDBWithPool("batch")
+	ctx := WithPool(context.Background(), "batch")
+
+	var users []maskUser
+	require.NoError(t, db.Find(ctx, &users).Error)
+	require.NoError(t, db.Delete(ctx, &maskUser{ID: 1}).Error)
+
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	assert.Empty(t, primary.Statements())
+	assert.True(t, batch.containsStatement("SELECT"))
+	assert.True(t, batch.containsStatement("DELETE"))
+	assert.True(t, batch.containsStatement("BEGIN"))
+}
+
+func TestWithPool_DefaultAndUnknownUsePrimary(t *testing.T) {
+	db, primary, batch := newMockDBWithPool("batch")
+
+	var users []maskUser
+	require.NoError(t, db.Find(context.Background(), &users).Error)
+	require.NoError(t, db.Find(WithPool(context.Background(), "reports"), &users).Error)
+
+	assert.Len(t, primary.Statements(), 2)
+	assert.Empty(t, batch.Statements())
+	assert.Equal(t, []string{"batch"}, db.PoolNames())
+}
+
+func TestWithPool_BypassesReplicas(t *testing.T) {
+	db, primary, batch := newMockDBWithPool("batch")
+	replica := newMockSource()
+	db.replicas = &replicaSet{sources: []common.DataSource{replica}}
+
+	var users []maskUser
+	require.NoError(t, db.Find(WithPool(context.Background(), "batch"), &users).Error)
+	assert.Len(t, batch.Statements(), 1)
+	assert.Empty(t, replica.Statements())
+	assert.Empty(t, primary.Statements())
+}
+
+func TestWithPool_RoutesMigratorAndTwoPhaseCommit(t *testing.T) {
+	db, primary, batch := newMockDBWithPool("batch")
+	primary.dialect = &mockDialect{name: "postgres"}
+	batch.dialect = &mockDialect{name: "postgres"}
+	ctx := WithPool(context.Background(), "batch")
+
+	require.NoError(t, db.Migrator().Grant(ctx, Grant{Privileges: []Privilege{PrivilegeSelect}, Table: "users", Roles: []string{"reporting"}}))
+	tx, err := db.BeginTwoPhase(ctx, "order-42")
+	require.NoError(t, err)
+	require.NoError(t, tx.Prepare(ctx))
+	require.NoError(t, db.CommitPrepared(ctx, "order-42"))
+
+	assert.Empty(t, primary.Statements())
+	assert.True(t, batch.containsStatement("GRANT"))
+	assert.True(t, batch.containsStatement("BEGIN"))
+	assert.True(t, batch.containsStatement("COMMIT PREPARED"))
+}
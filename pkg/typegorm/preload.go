@@ -0,0 +1,403 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Relation Preloading ---
+
+// Preload eagerly loads the relations of the found records (fields tagged "relation")
+// with one IN query per relation, whatever the number of rows, and virtual relations
+// (see VirtualRelation) with their loader:
+//
+//	type Post struct {
+//		ID      uint   `typegorm:"primaryKey"`
+//		AutorID uint
+//		Autor   *Autor `typegorm:"relation:many-to-one"`
+//	}
+//	db.Find(ctx, &posts, typegorm.Preload("Autor"))
+//	// SELECT ... FROM posts
+//	// SELECT ... FROM autores WHERE id IN (?, ?, ?)
+//
+// Many-to-many relations first read the join table, then the related rows by primary
+// key. A dotted path ("Artigos.Categorias") loads each level for the records loaded by
+// the previous one, and PreloadWhere filters or orders the rows of one level.
+//
+// Related rows are read with the Find of the same DB or transaction, so they follow
+// its scopes (soft deletes, DefaultScope, policies). Records without a related row keep
+// their field untouched.
+
+// relationSource is where the related rows are read: the Find of the DB or Tx
+// preloading the relations, and its reader, condition rewriter and policies for the
+// join tables.
+type relationSource struct {
+	find     func(ctx context.Context, dest any, condsAndOpts ...any) *Result
+	reader   func(ctx context.Context) reader
+	dialect  common.Dialect
+	rewriter ConditionRewriter
+	policies []PolicyFunc
+}
+
+// relationSource returns where the relations preloaded by db are read.
+func (db *DB) relationSource() relationSource {
+	return relationSource{find: db.Find, reader: db.reader, dialect: db.source.Dialect(), rewriter: db.rewriter, policies: db.policies}
+}
+
+// relationSource returns where the relations preloaded by tx are read.
+func (tx *Tx) relationSource() relationSource {
+	return relationSource{
+		find:     tx.Find,
+		reader:   func(context.Context) reader { return tx.source },
+		dialect:  tx.dialect,
+		rewriter: tx.rewriter,
+		policies: tx.policies,
+	}
+}
+
+// relationLoad is one relation to preload, with what applies to its rows.
+type relationLoad struct {
+	rel    *schema.Relation
+	conds  []any            // Conditions and FindOptions of PreloadWhere
+	nested []string         // Paths to preload on the related rows
+	subs   map[string][]any // PreloadWhere arguments of the nested paths
+}
+
+// preload resolves the relations named in names for target (a pointer to a struct or
+// to a slice of structs): schema relations through src, the others as virtual
+// relations. conds holds the PreloadWhere arguments by path.
+func preload(ctx context.Context, src relationSource, parser *schema.Parser, registry *virtualRelations, target reflect.Value, names []string, conds map[string][]any) error {
+	if len(names) == 0 {
+		return nil
+	}
+	elems, err := preloadElems("preload", target)
+	if err != nil || len(elems) == 0 {
+		return err
+	}
+	model, err := parser.Parse(reflect.New(elems[0].Type()).Interface())
+	if err != nil {
+		return fmt.Errorf("preload: %w", err)
+	}
+
+	// "Artigos" and "Artigos.Categorias" load Artigos once
+	var order []string
+	nested := make(map[string][]string)
+	for _, name := range names {
+		first, rest, _ := strings.Cut(name, ".")
+		if _, ok := nested[first]; !ok {
+			order = append(order, first)
+			nested[first] = nil
+		}
+		if rest != "" {
+			nested[first] = append(nested[first], rest)
+		}
+	}
+
+	var virtual []string
+	for _, name := range order {
+		rel, ok := model.GetRelation(name)
+		if !ok {
+			if len(nested[name]) > 0 || conds[name] != nil {
+				return fmt.Errorf("preload %s.%s: nested preloads and PreloadWhere require a relation field", model.Name, name)
+			}
+			virtual = append(virtual, name)
+			continue
+		}
+		load := relationLoad{rel: rel, conds: conds[name], nested: nested[name], subs: subPaths(conds, name)}
+		if err := loadRelation(ctx, src, parser, registry, model, load, elems); err != nil {
+			return err
+		}
+	}
+	return loadVirtualRelations(ctx, registry, target, virtual)
+}
+
+// subPaths returns the entries of conds below name, relative to it.
+func subPaths(conds map[string][]any, name string) map[string][]any {
+	var subs map[string][]any
+	for path, args := range conds {
+		if rest, ok := strings.CutPrefix(path, name+"."); ok {
+			if subs == nil {
+				subs = make(map[string][]any)
+			}
+			subs[rest] = args
+		}
+	}
+	return subs
+}
+
+// loadRelation loads a relation for elems and assigns the related records.
+func loadRelation(ctx context.Context, src relationSource, parser *schema.Parser, registry *virtualRelations, model *schema.Model, load relationLoad, elems []reflect.Value) error {
+	rel := load.rel
+	related, err := parser.Parse(reflect.New(rel.Type).Interface())
+	if err != nil {
+		return fmt.Errorf("preload %s.%s: %w", model.Name, rel.Name, err)
+	}
+	if rel.Kind == schema.RelationManyToMany {
+		return loadManyToMany(ctx, src, parser, registry, model, related, load, elems)
+	}
+
+	// ownKey (of the records) matches relatedKey (of the related rows)
+	var ownKey, relatedKey *schema.Field
+	if rel.BelongsTo {
+		ownKey = model.FieldsByName[rel.ForeignKey]
+		if len(related.PrimaryKeys) != 1 {
+			return fmt.Errorf("preload %s.%s: %s must have a single-column primary key", model.Name, rel.Name, related.Name)
+		}
+		relatedKey = related.PrimaryKeys[0]
+	} else {
+		if len(model.PrimaryKeys) != 1 {
+			return fmt.Errorf("preload %s.%s: %s must have a single-column primary key", model.Name, rel.Name, model.Name)
+		}
+		ownKey = model.PrimaryKeys[0]
+		if relatedKey, err = relationForeignKey(related, rel); err != nil {
+			return fmt.Errorf("preload %s.%s: %w", model.Name, rel.Name, err)
+		}
+	}
+
+	keys := relationKeys(elems, ownKey)
+	if len(keys) == 0 {
+		return nil
+	}
+	rows, err := findRelated(ctx, src, parser, registry, model, load, relatedKey, keys)
+	if err != nil {
+		return err
+	}
+	byKey := make(map[string][]reflect.Value, rows.Len())
+	for i := 0; i < rows.Len(); i++ {
+		row := rows.Index(i)
+		if key, ok := virtualKey(row.FieldByName(relatedKey.GoName)); ok {
+			byKey[relationKey(key)] = append(byKey[relationKey(key)], row)
+		}
+	}
+
+	for _, elem := range elems {
+		key, ok := virtualKey(elem.FieldByName(ownKey.GoName))
+		if !ok {
+			continue
+		}
+		matches := byKey[relationKey(key)]
+		if len(matches) == 0 {
+			continue
+		}
+		if err := assignRelated(elem.FieldByName(rel.Name), rel, matches); err != nil {
+			return fmt.Errorf("preload %s.%s: %w", model.Name, rel.Name, err)
+		}
+	}
+	return nil
+}
+
+// loadManyToMany reads the pairs of the join table for elems, then the related rows by
+// primary key.
+func loadManyToMany(ctx context.Context, src relationSource, parser *schema.Parser, registry *virtualRelations, model, related *schema.Model, load relationLoad, elems []reflect.Value) error {
+	rel := load.rel
+	if len(model.PrimaryKeys) != 1 || len(related.PrimaryKeys) != 1 {
+		return fmt.Errorf("preload %s.%s: %s and %s must have a single-column primary key", model.Name, rel.Name, model.Name, related.Name)
+	}
+	joinTable, err := relationJoinTable(related, rel)
+	if err != nil {
+		return fmt.Errorf("preload %s.%s: %w", model.Name, rel.Name, err)
+	}
+	ownKey, relatedKey := model.PrimaryKeys[0], related.PrimaryKeys[0]
+
+	keys := relationKeys(elems, ownKey)
+	if len(keys) == 0 {
+		return nil
+	}
+	// The join table has no model: the condition rewriter and the policies see a
+	// column-less one, as for Table
+	dialect := src.dialect
+	schemaName, table := schema.SplitQualifiedName(joinTable)
+	joinModel := &schema.Model{Name: joinTable, TableName: table, Schema: schemaName}
+	policyCtx, mask := withColumnMask(ctx, src.policies)
+	cond, err := rewriteCondition(policyCtx, src.rewriter, src.policies, "Find", joinModel, In(rel.JoinForeignKey, keys))
+	if err != nil {
+		return fmt.Errorf("preload %s.%s: %w", model.Name, rel.Name, err)
+	}
+	if mask != nil { // The pairs cannot be read without both keys
+		for _, name := range mask.names {
+			if strings.EqualFold(name, rel.JoinForeignKey) || strings.EqualFold(name, rel.JoinReferences) {
+				return fmt.Errorf("preload %s.%s: %w: Find on %s: column %s is masked", model.Name, rel.Name, ErrPolicyDenied, joinTable, name)
+			}
+		}
+	}
+	whereClauses, args, err := buildWhereClause(dialect, joinModel, cond)
+	if err != nil {
+		return fmt.Errorf("preload %s.%s: %w", model.Name, rel.Name, err)
+	}
+	query := renumberBindVars(dialect, fmt.Sprintf("SELECT %s, %s FROM %s WHERE %s",
+		dialect.Quote(rel.JoinForeignKey), dialect.Quote(rel.JoinReferences), quoteTable(dialect, joinModel),
+		strings.Join(whereClauses, " AND ")))
+	logSQL("Executing SQL: %s | Args: %v\n", query, redactArgs(joinModel, args))
+	joinRows, err := src.reader(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("preload %s.%s: reading join table %s: %w", model.Name, rel.Name, joinTable, err)
+	}
+	defer joinRows.Close()
+
+	pairs := make(map[string][]string)
+	var refs []any
+	seen := make(map[string]bool)
+	for joinRows.Next() {
+		var own, ref any
+		if err := joinRows.Scan(&own, &ref); err != nil {
+			return fmt.Errorf("preload %s.%s: reading join table %s: %w", model.Name, rel.Name, joinTable, err)
+		}
+		own, ref = joinKey(own), joinKey(ref)
+		pairs[relationKey(own)] = append(pairs[relationKey(own)], relationKey(ref))
+		if !seen[relationKey(ref)] {
+			seen[relationKey(ref)] = true
+			refs = append(refs, ref)
+		}
+	}
+	if err := joinRows.Err(); err != nil {
+		return fmt.Errorf("preload %s.%s: reading join table %s: %w", model.Name, rel.Name, joinTable, err)
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	rows, err := findRelated(ctx, src, parser, registry, model, load, relatedKey, refs)
+	if err != nil {
+		return err
+	}
+	byKey := make(map[string]reflect.Value, rows.Len())
+	for i := 0; i < rows.Len(); i++ {
+		row := rows.Index(i)
+		if key, ok := virtualKey(row.FieldByName(relatedKey.GoName)); ok {
+			byKey[relationKey(key)] = row
+		}
+	}
+
+	for _, elem := range elems {
+		key, ok := virtualKey(elem.FieldByName(ownKey.GoName))
+		if !ok {
+			continue
+		}
+		var matches []reflect.Value
+		for _, ref := range pairs[relationKey(key)] {
+			if row, found := byKey[ref]; found { // Filtered out by PreloadWhere or scopes otherwise
+				matches = append(matches, row)
+			}
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		if err := assignRelated(elem.FieldByName(rel.Name), rel, matches); err != nil {
+			return fmt.Errorf("preload %s.%s: %w", model.Name, rel.Name, err)
+		}
+	}
+	return nil
+}
+
+// findRelated reads the related rows whose column key is one of keys, with the
+// PreloadWhere arguments of the relation, and preloads their nested paths. It returns
+// the slice of rows.
+func findRelated(ctx context.Context, src relationSource, parser *schema.Parser, registry *virtualRelations, model *schema.Model, load relationLoad, key *schema.Field, keys []any) (reflect.Value, error) {
+	rel := load.rel
+	conds := []any{In(key.DBName, keys)}
+	var opts []any
+	for _, arg := range load.conds {
+		if _, ok := arg.(FindOption); ok {
+			opts = append(opts, arg)
+		} else {
+			conds = append(conds, arg)
+		}
+	}
+	var cond any = conds[0]
+	if len(conds) > 1 {
+		cond = And(conds...)
+	}
+
+	fmt.Printf("Preloading %s.%s for %d key(s)\n", model.Name, rel.Name, len(keys))
+	rows := reflect.New(reflect.SliceOf(rel.Type))
+	if result := src.find(ctx, rows.Interface(), append([]any{cond}, opts...)...); result.Error != nil {
+		return reflect.Value{}, fmt.Errorf("preload %s.%s: %w", model.Name, rel.Name, result.Error)
+	}
+	// Nested relations are loaded before the rows are copied into the records
+	if err := preload(ctx, src, parser, registry, rows, load.nested, load.subs); err != nil {
+		return reflect.Value{}, err
+	}
+	return rows.Elem(), nil
+}
+
+// relationKeys returns the distinct non-zero values of field across elems.
+func relationKeys(elems []reflect.Value, field *schema.Field) []any {
+	keys := make([]any, 0, len(elems))
+	seen := make(map[string]bool, len(elems))
+	for _, elem := range elems {
+		key, ok := virtualKey(elem.FieldByName(field.GoName))
+		if !ok || seen[relationKey(key)] {
+			continue
+		}
+		seen[relationKey(key)] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// assignRelated stores the related rows of a record: the first one in a to-one field,
+// all of them in a to-many slice of values or pointers.
+func assignRelated(field reflect.Value, rel *schema.Relation, rows []reflect.Value) error {
+	if rel.Kind == schema.RelationManyToOne || rel.Kind == schema.RelationOneToOne {
+		return assignVirtual(field, rows[0].Addr().Interface()) // One-to-one: the first row wins
+	}
+	slice := reflect.MakeSlice(field.Type(), 0, len(rows))
+	for _, row := range rows {
+		if field.Type().Elem().Kind() == reflect.Pointer {
+			row = row.Addr()
+		}
+		slice = reflect.Append(slice, row)
+	}
+	field.Set(slice)
+	return nil
+}
+
+// relationForeignKey returns the foreign key field of the related model of a has-one
+// or has-many relation: ForeignKey, or the foreign key of the MappedBy relation.
+func relationForeignKey(related *schema.Model, rel *schema.Relation) (*schema.Field, error) {
+	foreignKey := rel.ForeignKey
+	if foreignKey == "" {
+		inverse, ok := related.GetRelation(rel.MappedBy)
+		if !ok || !inverse.BelongsTo {
+			return nil, fmt.Errorf("mappedBy '%s' is not a many-to-one relation of %s", rel.MappedBy, related.Name)
+		}
+		foreignKey = inverse.ForeignKey
+	}
+	field, ok := related.FieldsByName[foreignKey]
+	if !ok {
+		return nil, fmt.Errorf("foreign key field '%s' not found in %s", foreignKey, related.Name)
+	}
+	return field, nil
+}
+
+// relationJoinTable returns the join table of a many-to-many relation: JoinTable, or
+// the one declared by the MappedBy relation (the owning side).
+func relationJoinTable(related *schema.Model, rel *schema.Relation) (string, error) {
+	if rel.JoinTable != "" {
+		return rel.JoinTable, nil
+	}
+	if inverse, ok := related.GetRelation(rel.MappedBy); ok && inverse.JoinTable != "" {
+		return inverse.JoinTable, nil
+	}
+	return "", fmt.Errorf("many-to-many relation requires a joinTable")
+}
+
+// relationKey matches key values of different Go types (e.g., a uint64 foreign key and
+// a uint primary key).
+func relationKey(key any) string {
+	return fmt.Sprint(key)
+}
+
+// joinKey normalizes a key scanned from a join table: drivers return text columns and
+// some integers as []byte.
+func joinKey(value any) any {
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+	return value
+}
@@ -0,0 +1,149 @@
+package typegorm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type preloadAutor struct {
+	ID   uint `typegorm:"primaryKey"`
+	Nome string
+}
+
+type preloadCapa struct {
+	ID     uint `typegorm:"primaryKey"`
+	PostID uint64
+	URL    string
+}
+
+type preloadPost struct {
+	ID      uint `typegorm:"primaryKey"`
+	Titulo  string
+	AutorID uint
+	Autor   *preloadAutor `typegorm:"relation:many-to-one"`
+	Capa    preloadCapa   `typegorm:"relation:one-to-one;foreignKey:PostID"`
+}
+
+func TestPreload_ToOneRelations(t *testing.T) {
+	db, source := newMockDB()
+	source.queueRows([]string{"id", "titulo", "autor_id"},
+		[]any{uint(1), "a", uint(7)}, []any{uint(2), "b", uint(9)}, []any{uint(3), "c", uint(7)})
+	source.queueRows([]string{"id", "nome"}, []any{uint(7), "Ana"}, []any{uint(9), "Bia"})
+	source.queueRows([]string{"id", "post_id", "url"}, []any{uint(70), uint64(1), "/1.png"}, []any{uint(71), uint64(3), "/3.png"})
+
+	var posts []preloadPost
+	result := db.Find(context.Background(), &posts, Preload("Autor", "Capa"))
+	require.NoError(t, result.Error)
+	require.Len(t, posts, 3)
+
+	statements := source.Statements()
+	require.Len(t, statements, 3, "One query per relation")
+	assert.Equal(t, "SELECT `id`, `nome` FROM `preload_autors` WHERE `id` IN (?, ?)", statements[1].SQL)
+	assert.Equal(t, []any{uint(7), uint(9)}, statements[1].Args)
+	assert.Contains(t, statements[2].SQL, "FROM `preload_capas` WHERE `post_id` IN (?, ?, ?)")
+
+	assert.Equal(t, "Ana", posts[0].Autor.Nome)
+	assert.Equal(t, "Bia", posts[1].Autor.Nome)
+	assert.Same(t, posts[0].Autor, posts[2].Autor, "Records sharing a key share the related row")
+	assert.Equal(t, "/1.png", posts[0].Capa.URL, "Has-one matched by PostID, across key types")
+	assert.Empty(t, posts[1].Capa.URL, "No related row: field untouched")
+	assert.Equal(t, "/3.png", posts[2].Capa.URL)
+}
+
+func TestPreload_FindFirstAndErrors(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	source.queueRows([]string{"id", "titulo", "autor_id"}, []any{uint(1), "a", uint(7)})
+	source.queueRows([]string{"id", "nome"}, []any{uint(7), "Ana"})
+
+	var post preloadPost
+	require.NoError(t, db.FindFirst(ctx, &post, Preload("Autor")).Error)
+	require.NotNil(t, post.Autor)
+	assert.Equal(t, "Ana", post.Autor.Nome)
+
+	source.queueRows([]string{"id", "titulo", "autor_id"}, []any{uint(1), "a", uint(7)})
+	var posts []preloadPost
+	err := db.Find(ctx, &posts, Preload("Comentarios")).Error
+	assert.ErrorContains(t, err, "no virtual relation named 'Comentarios'")
+}
+
+type preloadCategoria struct {
+	ID   uint `typegorm:"primaryKey"`
+	Nome string
+}
+
+type preloadArtigo struct {
+	ID         uint `typegorm:"primaryKey"`
+	Titulo     string
+	Publicado  bool
+	AutorID    uint
+	Autor      *preloadEscritor    `typegorm:"relation:many-to-one"`
+	Categorias []*preloadCategoria `typegorm:"relation:many-to-many;joinTable:artigo_categorias;joinForeignKey:artigo_id;joinReferences:categoria_id"`
+}
+
+type preloadEscritor struct {
+	ID      uint `typegorm:"primaryKey"`
+	Nome    string
+	Artigos []preloadArtigo `typegorm:"relation:one-to-many;mappedBy:Autor"`
+}
+
+func TestPreload_ToManyNestedAndConditions(t *testing.T) {
+	db, source := newMockDB()
+	source.queueRows([]string{"id", "nome"}, []any{uint(1), "Ana"}, []any{uint(2), "Bia"}, []any{uint(3), "Caio"})
+	source.queueRows([]string{"id", "titulo", "publicado", "autor_id"},
+		[]any{uint(10), "a", true, uint(1)}, []any{uint(11), "b", true, uint(2)}, []any{uint(12), "c", true, uint(1)})
+	source.queueRows([]string{"artigo_id", "categoria_id"},
+		[]any{int64(10), int64(100)}, []any{int64(12), int64(100)}, []any{int64(12), []byte("101")})
+	source.queueRows([]string{"id", "nome"}, []any{uint(100), "Go"}, []any{uint(101), "SQL"})
+
+	var escritores []preloadEscritor
+	result := db.Find(context.Background(), &escritores,
+		Preload("Artigos.Categorias"), PreloadWhere("Artigos", map[string]any{"publicado": true}, Order("titulo")))
+	require.NoError(t, result.Error)
+
+	statements := source.Statements()
+	require.Len(t, statements, 4, "One query per level, plus the join table")
+	assert.Contains(t, statements[1].SQL, "FROM `preload_artigos` WHERE")
+	assert.Contains(t, statements[1].SQL, "`autor_id` IN (?, ?, ?)")
+	assert.Contains(t, statements[1].SQL, "`publicado` = ?")
+	assert.True(t, strings.HasSuffix(statements[1].SQL, "ORDER BY `titulo`"), statements[1].SQL)
+	assert.Equal(t, []any{uint(1), uint(2), uint(3), true}, statements[1].Args)
+	assert.Equal(t, "SELECT `artigo_id`, `categoria_id` FROM `artigo_categorias` WHERE `artigo_id` IN (?, ?, ?)", statements[2].SQL)
+	assert.Contains(t, statements[3].SQL, "FROM `preload_categorias` WHERE `id` IN (?, ?)")
+
+	require.Len(t, escritores[0].Artigos, 2, "Has-many keyed by the foreign key of mappedBy")
+	assert.Equal(t, "a", escritores[0].Artigos[0].Titulo)
+	assert.Equal(t, "c", escritores[0].Artigos[1].Titulo)
+	require.Len(t, escritores[1].Artigos, 1)
+	assert.Empty(t, escritores[1].Artigos[0].Categorias, "No pair in the join table")
+	assert.Nil(t, escritores[2].Artigos, "No related row: field untouched")
+
+	require.Len(t, escritores[0].Artigos[0].Categorias, 1, "Nested relations are loaded before the rows are copied")
+	assert.Equal(t, "Go", escritores[0].Artigos[0].Categorias[0].Nome)
+	require.Len(t, escritores[0].Artigos[1].Categorias, 2)
+	assert.Same(t, escritores[0].Artigos[0].Categorias[0], escritores[0].Artigos[1].Categorias[0], "Shared related rows")
+	assert.Equal(t, "SQL", escritores[0].Artigos[1].Categorias[1].Nome, "Join keys scanned as []byte")
+}
+
+func TestPreload_ToManyErrors(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	source.queueRows([]string{"id", "titulo", "autor_id"}, []any{uint(1), "a", uint(7)})
+	var posts []preloadPost
+	err := db.Find(ctx, &posts, Preload("Comentarios.Autor")).Error
+	assert.ErrorContains(t, err, "nested preloads and PreloadWhere require a relation field")
+
+	type semTabela struct {
+		ID    uint           `typegorm:"primaryKey"`
+		Itens []preloadAutor `typegorm:"relation:many-to-many"`
+	}
+	source.queueRows([]string{"id"}, []any{uint(1)})
+	var rows []semTabela
+	err = db.Find(ctx, &rows, Preload("Itens")).Error
+	assert.ErrorContains(t, err, "many-to-many relation requires a joinTable")
+}
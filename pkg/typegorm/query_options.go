@@ -2,14 +2,30 @@ package typegorm
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
 )
 
 // queryOptions holds the optional clauses for a Find query.
 type queryOptions struct {
-	limit   int    // SQL LIMIT clause
-	offset  int    // SQL OFFSET clause
-	orderBy string // SQL ORDER BY clause (raw string)
+	limit        int              // SQL LIMIT clause
+	offset       int              // SQL OFFSET clause
+	orderBy      string           // SQL ORDER BY clause (raw string)
+	preload      []string         // Relations to resolve after scanning
+	preloadConds map[string][]any // PreloadWhere arguments by relation path
+	unscoped     bool             // Skip the model's DefaultScope/DefaultOrder
+	maxRows      int              // Row guard: 0 = global default, -1 = disabled (see MaxRows)
+	asOf         *time.Time       // Read past row versions of a TemporalModel (see AsOf)
+	joins        []joinOption     // Joined tables (see Joins)
+	distinct     bool             // SELECT DISTINCT
+	groupBy      []string         // SQL GROUP BY columns (raw strings)
+	having       []chainCond      // SQL HAVING conditions, combined with AND
+	columns      []string         // Selected columns and aliased expressions (see Columns)
 }
 
 // FindOption defines a function type that modifies queryOptions.
@@ -33,9 +49,9 @@ func Offset(offset int) FindOption {
 
 // Order specifies the ordering clause for the query.
 // Example: Order("user_name ASC, created_at DESC")
-// WARNING: The clause is used directly. Ensure column names are correct
-// and beware of SQL injection if constructing this from user input.
-// Consider adding validation or quoting helpers later.
+// Bare column references (column or Go field names, reserved words such as "group")
+// are quoted for the dialect; clauses with expressions are used as-is.
+// WARNING: Beware of SQL injection if constructing this from user input.
 func Order(clause string) FindOption {
 	return func(opts *queryOptions) {
 		// Basic validation: prevent obviously malicious content?
@@ -48,6 +64,159 @@ func Order(clause string) FindOption {
 	}
 }
 
+// Distinct removes the duplicate rows from the result (SELECT DISTINCT). With Count, the
+// distinct rows are counted.
+func Distinct() FindOption {
+	return func(opts *queryOptions) {
+		opts.distinct = true
+	}
+}
+
+// Group adds columns to the GROUP BY clause; calls accumulate.
+// Example: Group("status"), Group("country, city")
+// Bare column references are quoted for the dialect like with Order. The selected
+// columns must be grouped or aggregated: select them with Columns, or use a report
+// struct mapping only the grouped columns. With Count, the groups are counted.
+// WARNING: Beware of SQL injection if constructing this from user input.
+func Group(columns string) FindOption {
+	return func(opts *queryOptions) {
+		if trimmed := strings.TrimSpace(columns); trimmed != "" {
+			opts.groupBy = append(opts.groupBy, trimmed)
+		}
+	}
+}
+
+// Having filters the groups of Group: SQL with "?" placeholders or named parameters, or
+// conditions of the forms of Find (map with operators, struct pointer). Calls are
+// combined with AND.
+// Example: Group("customer_id"), Having("SUM(total) > ?", 1000)
+func Having(cond any, args ...any) FindOption {
+	return func(opts *queryOptions) {
+		opts.having = append(opts.having, chainCond{cond: cond, args: args})
+	}
+}
+
+// Columns restricts the columns read by Find and FindFirst to the given ones: column or
+// Go field names of the model, or SQL expressions aliased to one of them with "AS";
+// the other fields are left zero. With Group, it selects the grouped columns and the
+// aggregates only, as MySQL requires under ONLY_FULL_GROUP_BY:
+//
+//	type StatusTotal struct {
+//		_      struct{} `typegorm:"table:orders"`
+//		Status string
+//		Total  float64
+//	}
+//	db.Find(ctx, &totals, Group("status"), Columns("status", "SUM(amount) AS total"))
+//
+// WARNING: Beware of SQL injection if constructing this from user input.
+func Columns(columns ...string) FindOption {
+	return func(opts *queryOptions) {
+		for _, column := range columns {
+			if trimmed := strings.TrimSpace(column); trimmed != "" {
+				opts.columns = append(opts.columns, trimmed)
+			}
+		}
+	}
+}
+
+// Preload requests the named relations to be loaded after the rows are scanned:
+// relation fields of the model or virtual relations. Keys are collected across the
+// whole result and each relation is loaded once (see preload.go); a dotted path also
+// loads the relations of the related rows.
+// Example: Preload("Customer", "Artigos.Categorias")
+func Preload(names ...string) FindOption {
+	return func(opts *queryOptions) {
+		opts.preload = append(opts.preload, names...)
+	}
+}
+
+// PreloadWhere preloads the relation at path like Preload, keeping the related rows
+// matching the conditions; FindOptions such as Order apply to the query of the
+// relation (a Limit caps the rows of all the records together, not of each one).
+// Example: PreloadWhere("Artigos", map[string]any{"publicado": true}, Order("titulo"))
+func PreloadWhere(path string, condsAndOpts ...any) FindOption {
+	return func(opts *queryOptions) {
+		opts.preload = append(opts.preload, path)
+		if opts.preloadConds == nil {
+			opts.preloadConds = make(map[string][]any)
+		}
+		opts.preloadConds[path] = condsAndOpts
+	}
+}
+
+// selectKeyword returns the start of the SELECT of options.
+func (opts queryOptions) selectKeyword() string {
+	if opts.distinct {
+		return "SELECT DISTINCT "
+	}
+	return "SELECT "
+}
+
+// columnAliasRe splits "<expression> AS <alias>" (see Columns).
+var columnAliasRe = regexp.MustCompile(`(?is)^(.+)\s+AS\s+(\w+)$`)
+
+// selectedColumns renders the SELECT list of the Columns of options, with the fields
+// receiving them in order.
+func (opts queryOptions) selectedColumns(dialect common.Dialect, model *schema.Model) (string, []*schema.Field, error) {
+	quoted := make([]string, 0, len(opts.columns))
+	fields := make([]*schema.Field, 0, len(opts.columns))
+	for _, column := range opts.columns {
+		expr, alias := "", column
+		if match := columnAliasRe.FindStringSubmatch(column); match != nil {
+			expr, alias = strings.TrimSpace(match[1]), match[2]
+		}
+		field, ok := model.GetFieldByDBName(alias)
+		if !ok {
+			field, ok = model.GetField(alias)
+		}
+		if !ok || field.IsIgnored {
+			return "", nil, fmt.Errorf("column %q is not a field of %s (alias expressions with AS <field>)", column, model.Name)
+		}
+		if slices.Contains(fields, field) {
+			return "", nil, fmt.Errorf("column %s of %s is selected twice", field.DBName, model.Name)
+		}
+		if expr == "" {
+			quoted = append(quoted, dialect.Quote(field.DBName))
+		} else {
+			quoted = append(quoted, expr+" AS "+unqualified(dialect).Quote(field.DBName))
+		}
+		fields = append(fields, field)
+	}
+	return strings.Join(quoted, ", "), fields, nil
+}
+
+// grouped reports whether the rows of the query are groups or distinct rows, which
+// Count counts through a derived table.
+func (opts queryOptions) grouped() bool {
+	return opts.distinct || len(opts.groupBy) > 0 || len(opts.having) > 0
+}
+
+// groupClause renders the GROUP BY and HAVING clauses of options, with the arguments
+// of the HAVING conditions.
+func (opts queryOptions) groupClause(dialect common.Dialect, model *schema.Model) (string, []any, error) {
+	var b strings.Builder
+	for i, columns := range opts.groupBy {
+		if i == 0 {
+			b.WriteString(" GROUP BY ")
+		} else {
+			b.WriteString(", ")
+		}
+		b.WriteString(quoteOrderBy(dialect, model, columns))
+	}
+	if len(opts.having) == 0 {
+		return b.String(), nil, nil
+	}
+	clauses, args, err := chainWhere(opts.having).build(dialect, model)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid having condition: %w", err)
+	}
+	if len(clauses) > 0 {
+		b.WriteString(" HAVING ")
+		b.WriteString(strings.Join(clauses, " AND "))
+	}
+	return b.String(), args, nil
+}
+
 // processFindArgs separates conditions from FindOption functions.
 // Returns the condition (if any), the applied options, and an error.
 func processFindArgs(args ...any) (any, queryOptions, error) {
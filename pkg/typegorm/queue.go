@@ -0,0 +1,450 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Job Queue ---
+
+// The job queue stores jobs in the table typegorm_jobs (create it with
+// db.AutoMigrate(ctx, &typegorm.Job{})). Enqueue inserts a job in the caller's
+// transaction, so that it only becomes visible if the transaction commits; a Worker
+// claims the due jobs with SELECT ... FOR UPDATE SKIP LOCKED (MySQL 8, Postgres) or an
+// optimistic UPDATE elsewhere, runs them, retries failures with a backoff and moves
+// jobs out of attempts to the dead letters (status "dead").
+
+// JobStatus is the state of a job.
+type JobStatus string
+
+const (
+	JobPending JobStatus = "pending" // Waiting for RunAt
+	JobRunning JobStatus = "running" // Claimed by a worker until LockedUntil
+	JobDead    JobStatus = "dead"    // Out of attempts: kept for inspection (see DeadJobs, RetryJob)
+)
+
+// DefaultJobAttempts is the MaxAttempts of jobs enqueued without one.
+const DefaultJobAttempts = 5
+
+// Job is a row of the job queue. Successful jobs are deleted.
+type Job struct {
+	_           struct{}   `typegorm:"table:typegorm_jobs"`
+	ID          int64      `typegorm:"primaryKey;autoIncrement"`
+	Queue       string     `typegorm:"size:64;not null;index:idx_typegorm_jobs_claim"`
+	Status      JobStatus  `typegorm:"size:16;not null;index:idx_typegorm_jobs_claim"`
+	RunAt       time.Time  `typegorm:"not null;index:idx_typegorm_jobs_claim"` // Not run before
+	Payload     []byte     `typegorm:"sensitive"`                              // Handler input, JSON with NewJob
+	Attempts    int        `typegorm:"not null"`                               // Runs started so far
+	MaxAttempts int        `typegorm:"not null"`
+	LastError   string     `typegorm:"size:1024;sensitive"`
+	LockedBy    string     `typegorm:"size:128"` // Claim of the worker running the job
+	LockedUntil *time.Time // Running jobs not finished by then are claimed again
+	CreatedAt   time.Time
+}
+
+// NewJob returns a job of queue with payload encoded as JSON.
+func NewJob(queue string, payload any) (*Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encoding job payload: %w", err)
+	}
+	return &Job{Queue: queue, Payload: data}, nil
+}
+
+// Decode decodes the JSON payload of the job into v.
+func (j *Job) Decode(v any) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+// Enqueue inserts job, in the transaction of ctx when there is one (see
+// ContextWithTx). Zero fields get defaults: queue "default", RunAt now, MaxAttempts
+// DefaultJobAttempts.
+func (db *DB) Enqueue(ctx context.Context, job *Job) error {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.Enqueue(ctx, job)
+	}
+	prepareJob(job, clock(ctx, db.now))
+	if result := db.Create(ctx, job); result.Error != nil {
+		return fmt.Errorf("enqueue %s: %w", job.Queue, result.Error)
+	}
+	return nil
+}
+
+// Enqueue inserts job in the transaction: it is only run if the transaction commits.
+func (tx *Tx) Enqueue(ctx context.Context, job *Job) error {
+	prepareJob(job, clock(ctx, tx.now))
+	if result := tx.Create(ctx, job); result.Error != nil {
+		return fmt.Errorf("enqueue %s: %w", job.Queue, result.Error)
+	}
+	return nil
+}
+
+func prepareJob(job *Job, now time.Time) {
+	if job.Queue == "" {
+		job.Queue = "default"
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = now
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = DefaultJobAttempts
+	}
+	job.Status = JobPending
+}
+
+// DeadJobs returns the jobs of queue that ran out of attempts.
+func (db *DB) DeadJobs(ctx context.Context, queue string) ([]Job, error) {
+	var jobs []Job
+	if result := db.Find(ctx, &jobs, map[string]any{"queue": queue, "status": string(JobDead)}, Order("id")); result.Error != nil {
+		return nil, fmt.Errorf("dead jobs of %s: %w", queue, result.Error)
+	}
+	return jobs, nil
+}
+
+// RetryJob puts a dead job back in its queue with its attempts reset.
+func (db *DB) RetryJob(ctx context.Context, id int64) error {
+	q := newJobSQL(db)
+	stmt := fmt.Sprintf("UPDATE %s SET %s = %s, %s = 0, %s = %s, %s = '' WHERE %s = %s AND %s = %s", q.table,
+		q.col("Status"), q.bind(1), q.col("Attempts"), q.col("RunAt"), q.bind(2), q.col("LastError"),
+		q.col("ID"), q.bind(3), q.col("Status"), q.bind(4))
+	args := []any{string(JobPending), clock(ctx, db.now), id, string(JobDead)}
+	logSQL("Executing SQL: %s | Args: %v\n", stmt, redactArgs(q.model, args))
+	result, err := db.conn(ctx).Exec(ctx, stmt, args...)
+	if err != nil {
+		return fmt.Errorf("retry job %d: %w", id, err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("retry job %d: no dead job with this ID", id)
+	}
+	return nil
+}
+
+// JobHandler runs a job. A returned error (or a panic) makes the job retried later, or
+// dead once it ran MaxAttempts times; so does a run not finished within the visibility
+// timeout (see WorkerVisibilityTimeout).
+type JobHandler func(ctx context.Context, job *Job) error
+
+// workerOptions holds the optional behaviors of a Worker.
+type workerOptions struct {
+	concurrency int
+	poll        time.Duration
+	visibility  time.Duration
+	backoff     func(attempt int) time.Duration
+	onDead      func(job *Job, err error)
+}
+
+// WorkerOption defines a function type that modifies workerOptions.
+type WorkerOption func(*workerOptions)
+
+// WorkerConcurrency sets how many jobs the worker runs at the same time (default 1).
+func WorkerConcurrency(n int) WorkerOption {
+	return func(opts *workerOptions) { opts.concurrency = n }
+}
+
+// WorkerPollInterval sets how long the worker waits when its queue is empty (default 1s).
+func WorkerPollInterval(d time.Duration) WorkerOption {
+	return func(opts *workerOptions) { opts.poll = d }
+}
+
+// WorkerVisibilityTimeout sets how long a job may run (default 5m): its context is
+// canceled then, and other workers may claim it again, e.g. after a crash.
+func WorkerVisibilityTimeout(d time.Duration) WorkerOption {
+	return func(opts *workerOptions) { opts.visibility = d }
+}
+
+// WorkerBackoff sets the delay before retrying a job that failed its attempt-th run
+// (default: 1s doubled at each attempt, at most 1h).
+func WorkerBackoff(backoff func(attempt int) time.Duration) WorkerOption {
+	return func(opts *workerOptions) { opts.backoff = backoff }
+}
+
+// OnDeadJob registers a function called when a job runs out of attempts, e.g. to
+// alert.
+func OnDeadJob(fn func(job *Job, err error)) WorkerOption {
+	return func(opts *workerOptions) { opts.onDead = fn }
+}
+
+func defaultJobBackoff(attempt int) time.Duration {
+	if attempt > 12 {
+		return time.Hour
+	}
+	return min(time.Second<<max(attempt-1, 0), time.Hour)
+}
+
+// Worker runs the jobs of a queue.
+type Worker struct {
+	db      *DB
+	queue   string
+	handler JobHandler
+	owner   string
+	claims  atomic.Int64 // Numbers the claims of owner
+	opts    workerOptions
+}
+
+// NewWorker returns a worker running the jobs of queue with handler; start it with Run.
+func (db *DB) NewWorker(queue string, handler JobHandler, opts ...WorkerOption) *Worker {
+	options := workerOptions{concurrency: 1, poll: time.Second, visibility: 5 * time.Minute, backoff: defaultJobBackoff}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	if options.concurrency < 1 {
+		options.concurrency = 1
+	}
+	return &Worker{db: db, queue: queue, handler: handler, owner: lockOwner(), opts: options}
+}
+
+// Run processes jobs until ctx is canceled, then waits for the running jobs.
+func (w *Worker) Run(ctx context.Context) error {
+	fmt.Printf("Worker %s started on queue %s (concurrency %d).\n", w.owner, w.queue, w.opts.concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < w.opts.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				ran, err := w.RunOnce(ctx)
+				if err != nil && ctx.Err() == nil {
+					fmt.Printf("Warning: worker on queue %s: %v\n", w.queue, err)
+				}
+				if ran && err == nil {
+					continue // More jobs may be due
+				}
+				select {
+				case <-ctx.Done():
+				case <-time.After(w.opts.poll):
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	fmt.Printf("Worker %s stopped.\n", w.owner)
+	return nil
+}
+
+// RunOnce claims one due job and runs it; it reports whether there was one. Errors
+// are those of the queue table, not of the handler.
+func (w *Worker) RunOnce(ctx context.Context) (bool, error) {
+	job, err := w.claim(ctx)
+	if err != nil || job == nil {
+		return false, err
+	}
+	if job.Status == JobDead { // Out of attempts when reclaimed: not run again
+		if w.opts.onDead != nil {
+			w.opts.onDead(job, errors.New(job.LastError))
+		}
+		return true, nil
+	}
+	return true, w.run(ctx, job)
+}
+
+// claim takes the next due job: pending and past RunAt, or running past LockedUntil
+// (its worker died or overran the visibility timeout). Such a job that already used
+// its MaxAttempts is moved to the dead letters instead, and returned with status
+// JobDead.
+func (w *Worker) claim(ctx context.Context) (*Job, error) {
+	q := newJobSQL(w.db)
+	now := clock(ctx, w.db.now)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = %s AND ((%s = %s AND %s <= %s) OR (%s = %s AND %s < %s)) ORDER BY %s, %s LIMIT 1",
+		q.columns(), q.table, q.col("Queue"), q.bind(1),
+		q.col("Status"), q.bind(2), q.col("RunAt"), q.bind(3),
+		q.col("Status"), q.bind(4), q.col("LockedUntil"), q.bind(5),
+		q.col("RunAt"), q.col("ID"))
+	args := []any{w.queue, string(JobPending), now, string(JobRunning), now}
+
+	if !supportsSkipLocked(w.db.source.Dialect().Name()) {
+		// Optimistic claim: the UPDATE only matches if no other worker changed the job
+		job, err := q.scan(w.db.conn(ctx).QueryRow(ctx, query, args...))
+		if job == nil || err != nil {
+			return nil, err
+		}
+		claimed, err := w.markRunning(ctx, w.db.conn(ctx), q, job, now)
+		if err != nil || !claimed {
+			return nil, err
+		}
+		return job, nil
+	}
+
+	var job *Job
+	err := w.db.RunInTransaction(ctx, func(tx *Tx) error {
+		var err error
+		job, err = q.scan(tx.source.QueryRow(ctx, query+" FOR UPDATE SKIP LOCKED", args...))
+		if job == nil || err != nil {
+			return err
+		}
+		_, err = w.markRunning(ctx, tx.source, q, job, now)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// markRunning records the claim of job under a token of its own, so that the
+// concurrent runs of the worker only complete their own claims; it reports false when
+// another worker changed the job since it was read.
+func (w *Worker) markRunning(ctx context.Context, exec execer, q jobSQL, job *Job, now time.Time) (bool, error) {
+	if job.Status == JobRunning && job.Attempts >= job.MaxAttempts {
+		return w.bury(ctx, exec, q, job)
+	}
+	until := now.Add(w.opts.visibility)
+	token := fmt.Sprintf("%s#%d", w.owner, w.claims.Add(1))
+	stmt := fmt.Sprintf("UPDATE %s SET %s = %s, %s = %s, %s = %s, %s = %s WHERE %s = %s AND %s = %s AND %s = %s", q.table,
+		q.col("Status"), q.bind(1), q.col("Attempts"), q.bind(2), q.col("LockedBy"), q.bind(3), q.col("LockedUntil"), q.bind(4),
+		q.col("ID"), q.bind(5), q.col("Status"), q.bind(6), q.col("Attempts"), q.bind(7))
+	args := []any{string(JobRunning), job.Attempts + 1, token, until, job.ID, string(job.Status), job.Attempts}
+	logSQL("Executing SQL: %s | Args: %v\n", stmt, redactArgs(q.model, args))
+	result, err := exec.Exec(ctx, stmt, args...)
+	if err != nil {
+		return false, fmt.Errorf("claiming job %d: %w", job.ID, err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return false, nil
+	}
+	job.Status, job.Attempts, job.LockedBy, job.LockedUntil = JobRunning, job.Attempts+1, token, &until
+	return true, nil
+}
+
+// bury moves a running job past LockedUntil that already used its MaxAttempts to the
+// dead letters; it reports false when another worker changed the job since it was read.
+func (w *Worker) bury(ctx context.Context, exec execer, q jobSQL, job *Job) (bool, error) {
+	message := fmt.Sprintf("attempt %d did not finish within the visibility timeout", job.Attempts)
+	stmt := fmt.Sprintf("UPDATE %s SET %s = %s, %s = %s, %s = '', %s = NULL WHERE %s = %s AND %s = %s AND %s = %s", q.table,
+		q.col("Status"), q.bind(1), q.col("LastError"), q.bind(2), q.col("LockedBy"), q.col("LockedUntil"),
+		q.col("ID"), q.bind(3), q.col("Status"), q.bind(4), q.col("Attempts"), q.bind(5))
+	args := []any{string(JobDead), message, job.ID, string(JobRunning), job.Attempts}
+	logSQL("Executing SQL: %s | Args: %v\n", stmt, redactArgs(q.model, args, []any{message}))
+	result, err := exec.Exec(ctx, stmt, args...)
+	if err != nil {
+		return false, fmt.Errorf("burying job %d: %w", job.ID, err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return false, nil
+	}
+	fmt.Printf("Warning: job %d of queue %s is dead after %d attempt(s): %s\n", job.ID, job.Queue, job.Attempts, redactedValue)
+	job.Status, job.LastError = JobDead, message
+	return true, nil
+}
+
+// run runs the handler, then deletes the job or schedules its retry.
+func (w *Worker) run(ctx context.Context, job *Job) error {
+	jobCtx, cancel := context.WithTimeout(ctx, w.opts.visibility)
+	err := w.handle(jobCtx, job)
+	cancel()
+
+	ctx = context.WithoutCancel(ctx) // Record the outcome even when the worker is stopping
+	q := newJobSQL(w.db)
+	conn := w.db.conn(ctx)
+	if err == nil {
+		stmt := fmt.Sprintf("DELETE FROM %s WHERE %s = %s AND %s = %s", q.table, q.col("ID"), q.bind(1), q.col("LockedBy"), q.bind(2))
+		logSQL("Executing SQL: %s | Args: %v\n", stmt, redactArgs(q.model, []any{job.ID, job.LockedBy}))
+		if _, err := conn.Exec(ctx, stmt, job.ID, job.LockedBy); err != nil {
+			return fmt.Errorf("completing job %d: %w", job.ID, err)
+		}
+		return nil
+	}
+
+	message := err.Error()
+	if len(message) > 1024 {
+		message = message[:1024]
+	}
+	status, runAt := JobPending, clock(ctx, w.db.now).Add(w.opts.backoff(job.Attempts))
+	if job.Attempts >= job.MaxAttempts {
+		status, runAt = JobDead, job.RunAt
+		fmt.Printf("Warning: job %d of queue %s is dead after %d attempt(s): %s\n", job.ID, job.Queue, job.Attempts, redactedValue)
+	} else {
+		fmt.Printf("Warning: job %d of queue %s failed (attempt %d/%d), retrying at %s: %s\n",
+			job.ID, job.Queue, job.Attempts, job.MaxAttempts, runAt.Format(time.RFC3339), redactedValue)
+	}
+	stmt := fmt.Sprintf("UPDATE %s SET %s = %s, %s = %s, %s = %s, %s = '', %s = NULL WHERE %s = %s AND %s = %s", q.table,
+		q.col("Status"), q.bind(1), q.col("RunAt"), q.bind(2), q.col("LastError"), q.bind(3), q.col("LockedBy"), q.col("LockedUntil"),
+		q.col("ID"), q.bind(4), q.col("LockedBy"), q.bind(5))
+	args := []any{string(status), runAt, message, job.ID, job.LockedBy}
+	logSQL("Executing SQL: %s | Args: %v\n", stmt, redactArgs(q.model, args, []any{message}))
+	if _, err := conn.Exec(ctx, stmt, args...); err != nil {
+		return fmt.Errorf("rescheduling job %d: %w", job.ID, err)
+	}
+	job.Status, job.RunAt, job.LastError = status, runAt, message
+	if status == JobDead && w.opts.onDead != nil {
+		w.opts.onDead(job, err)
+	}
+	return nil
+}
+
+// handle runs the handler, turning a panic into an error.
+func (w *Worker) handle(ctx context.Context, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job handler panicked: %v", r)
+		}
+	}()
+	return w.handler(ctx, job)
+}
+
+// supportsSkipLocked reports whether the dialect has SELECT ... FOR UPDATE SKIP LOCKED.
+func supportsSkipLocked(dialect string) bool {
+	return dialect == "mysql" || dialect == "postgres"
+}
+
+// jobFields are the columns of Job read by the workers, in scan order.
+var jobFields = []string{"ID", "Queue", "Status", "RunAt", "Payload", "Attempts", "MaxAttempts", "LastError", "CreatedAt"}
+
+// jobSQL renders the statements of the queue table.
+type jobSQL struct {
+	db    *DB
+	model *schema.Model
+	table string
+}
+
+func newJobSQL(db *DB) jobSQL {
+	model, err := db.parser.Parse(&Job{})
+	if err != nil {
+		panic(fmt.Sprintf("typegorm: parsing Job: %v", err)) // Static model
+	}
+	return jobSQL{db: db, model: model, table: quoteTable(db.source.Dialect(), model)}
+}
+
+func (q jobSQL) col(name string) string {
+	return q.db.source.Dialect().Quote(q.model.FieldsByName[name].DBName)
+}
+
+func (q jobSQL) bind(i int) string {
+	return q.db.source.Dialect().BindVar(i)
+}
+
+func (q jobSQL) columns() string {
+	cols := make([]string, len(jobFields))
+	for i, name := range jobFields {
+		cols[i] = q.col(name)
+	}
+	return strings.Join(cols, ", ")
+}
+
+// scan reads the job of row; nil when there is none.
+func (q jobSQL) scan(row interface{ Scan(...any) error }) (*Job, error) {
+	job := &Job{}
+	elem := reflect.ValueOf(job).Elem()
+	dest := make([]any, len(jobFields))
+	for i, name := range jobFields {
+		dest[i] = elem.FieldByName(name).Addr().Interface()
+	}
+	if err := row.Scan(dest...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading job: %w", err)
+	}
+	return job, nil
+}
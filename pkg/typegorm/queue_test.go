@@ -0,0 +1,149 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var jobColumns = []string{"id", "queue", "status", "run_at", "payload", "attempts", "max_attempts", "last_error", "created_at"}
+
+func TestEnqueue_InCallerTransaction(t *testing.T) {
+	db, source := newMockDB()
+	frozen := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	db.UseNowFunc(func() time.Time { return frozen })
+
+	job, err := NewJob("", map[string]any{"fatura": 42})
+	require.NoError(t, err)
+	err = db.Transaction(context.Background(), func(ctx context.Context, tx *Tx) error {
+		return db.Enqueue(ctx, job)
+	})
+	require.NoError(t, err)
+
+	statements := source.Statements()
+	require.Len(t, statements, 3)
+	assert.Equal(t, "BEGIN", statements[0].SQL)
+	assert.Contains(t, statements[1].SQL, "INSERT INTO `typegorm_jobs`")
+	assert.Equal(t, "COMMIT", statements[2].SQL)
+	assert.Equal(t, "default", job.Queue)
+	assert.Equal(t, JobPending, job.Status)
+	assert.Equal(t, frozen, job.RunAt)
+	assert.Equal(t, DefaultJobAttempts, job.MaxAttempts)
+
+	var payload struct{ Fatura int }
+	require.NoError(t, job.Decode(&payload))
+	assert.Equal(t, 42, payload.Fatura)
+}
+
+func TestWorker_ClaimsWithSkipLocked(t *testing.T) {
+	db, source := newMockDB()
+	source.dialect = &mockDialect{name: "mysql"}
+	ctx := context.Background()
+	now := time.Now()
+	source.queueRows(jobColumns, []any{int64(7), "emails", "pending", now, []byte(`{}`), 0, 5, "", now})
+
+	var handled *Job
+	worker := db.NewWorker("emails", func(ctx context.Context, job *Job) error {
+		handled = job
+		return nil
+	})
+	ran, err := worker.RunOnce(ctx)
+	require.NoError(t, err)
+	require.True(t, ran)
+	require.NotNil(t, handled)
+	assert.Equal(t, int64(7), handled.ID)
+	assert.Equal(t, 1, handled.Attempts)
+	assert.Equal(t, JobRunning, handled.Status)
+
+	statements := source.Statements()
+	require.Len(t, statements, 5)
+	assert.Equal(t, "BEGIN", statements[0].SQL)
+	assert.True(t, strings.HasSuffix(statements[1].SQL, "ORDER BY `run_at`, `id` LIMIT 1 FOR UPDATE SKIP LOCKED"), statements[1].SQL)
+	assert.Contains(t, statements[1].SQL, "WHERE `queue` = ? AND ((`status` = ? AND `run_at` <= ?) OR (`status` = ? AND `locked_until` < ?))")
+	assert.Contains(t, statements[2].SQL, "UPDATE `typegorm_jobs` SET `status` = ?, `attempts` = ?, `locked_by` = ?")
+	assert.Equal(t, "COMMIT", statements[3].SQL)
+	assert.Equal(t, "DELETE FROM `typegorm_jobs` WHERE `id` = ? AND `locked_by` = ?", statements[4].SQL, "Done jobs are deleted")
+
+	ran, err = worker.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.False(t, ran, "Empty queue")
+}
+
+func TestWorker_RetriesThenDeadLetters(t *testing.T) {
+	db, source := newMockDB() // No SKIP LOCKED: optimistic claim
+	ctx := context.Background()
+	frozen := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	db.UseNowFunc(func() time.Time { return frozen })
+
+	var dead *Job
+	worker := db.NewWorker("emails", func(ctx context.Context, job *Job) error {
+		if job.Attempts == 2 {
+			panic("smtp down")
+		}
+		return errors.New("timeout")
+	}, WorkerBackoff(func(attempt int) time.Duration { return time.Duration(attempt) * time.Minute }),
+		OnDeadJob(func(job *Job, err error) { dead = job }))
+
+	source.queueRows(jobColumns, []any{int64(7), "emails", "pending", frozen, []byte(`{}`), 0, 2, "", frozen})
+	ran, err := worker.RunOnce(ctx)
+	require.NoError(t, err)
+	require.True(t, ran)
+	statements := source.Statements()
+	require.Len(t, statements, 3)
+	assert.NotContains(t, statements[0].SQL, "FOR UPDATE")
+	assert.Contains(t, statements[1].SQL, "WHERE `id` = ? AND `status` = ? AND `attempts` = ?", "Claimed only if unchanged")
+	claim := statements[1].Args[2]
+	assert.Equal(t, worker.owner+"#1", claim, "Each claim has its own token")
+	assert.Equal(t, []any{"pending", frozen.Add(time.Minute), "timeout", int64(7), claim}, statements[2].Args)
+	assert.Nil(t, dead)
+
+	source.queueRows(jobColumns, []any{int64(7), "emails", "pending", frozen, []byte(`{}`), 1, 2, "timeout", frozen})
+	_, err = worker.RunOnce(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, dead)
+	assert.Equal(t, JobDead, dead.Status)
+	assert.Equal(t, "job handler panicked: smtp down", dead.LastError)
+	assert.Equal(t, "dead", source.lastStatement().Args[0])
+
+	source.queueRows(jobColumns, []any{int64(8), "emails", "pending", frozen, []byte(`{}`), 0, 2, "", frozen})
+	source.mu.Lock()
+	source.affected = 0 // Another worker claimed it first
+	source.mu.Unlock()
+	ran, err = worker.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.False(t, ran)
+}
+
+func TestWorker_BuriesExpiredJobOutOfAttempts(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	frozen := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	db.UseNowFunc(func() time.Time { return frozen })
+
+	handled := false
+	var dead *Job
+	var deadErr error
+	worker := db.NewWorker("emails", func(ctx context.Context, job *Job) error {
+		handled = true
+		return nil
+	}, OnDeadJob(func(job *Job, err error) { dead, deadErr = job, err }))
+
+	// Running past LockedUntil, e.g. its worker crashed on the last attempt
+	source.queueRows(jobColumns, []any{int64(7), "emails", "running", frozen, []byte(`{}`), 2, 2, "", frozen})
+	ran, err := worker.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.True(t, ran)
+	assert.False(t, handled, "Not run beyond MaxAttempts")
+	require.NotNil(t, dead)
+	assert.Equal(t, JobDead, dead.Status)
+	assert.EqualError(t, deadErr, "attempt 2 did not finish within the visibility timeout")
+	statement := source.lastStatement()
+	assert.Contains(t, statement.SQL, "UPDATE `typegorm_jobs` SET `status` = ?, `last_error` = ?")
+	assert.Contains(t, statement.SQL, "WHERE `id` = ? AND `status` = ? AND `attempts` = ?")
+	assert.Equal(t, []any{"dead", deadErr.Error(), int64(7), "running", 2}, statement.Args)
+}
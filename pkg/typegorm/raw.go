@@ -0,0 +1,253 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Raw SQL ---
+
+// RawStatement is a SQL query written by hand, run by Scan (see DB.Raw). Unlike a
+// RawQuery (typegorm.Raw), which is a subquery of a built query, it runs on its own.
+type RawStatement struct {
+	ctx     context.Context
+	db      *DB
+	tx      *Tx
+	sql     string
+	args    []any
+	dialect common.Dialect
+	parser  *schema.Parser
+}
+
+// Raw prepares a SQL query with "?" placeholders for args (slices expand to lists, for
+// IN (?)), whose rows are scanned into a destination by Scan:
+//
+//	var totals []struct {
+//		UserID uint
+//		Total  float64
+//	}
+//	err := db.Raw(ctx, "SELECT user_id, SUM(amount) AS total FROM orders WHERE status IN (?) GROUP BY user_id",
+//		[]string{"paid", "shipped"}).Scan(&totals).Error
+//
+// Named parameters (":name") take their values from sql.Named or typegorm.Named
+// arguments, and every placeholder becomes a bind variable of the dialect:
+//
+//	db.Raw(ctx, "SELECT * FROM orders WHERE user_id = :user AND status = :status",
+//		typegorm.Named(map[string]any{"user": 7, "status": "paid"})).Scan(&orders)
+//
+// The query is read from the replicas like Find; use Tx.Raw to read within a transaction.
+func (db *DB) Raw(ctx context.Context, sql string, args ...any) *RawStatement {
+	return &RawStatement{ctx: ctx, db: db, sql: sql, args: args, dialect: db.source.Dialect(), parser: db.parser}
+}
+
+// Raw prepares a SQL query run within the transaction. See DB.Raw.
+func (tx *Tx) Raw(ctx context.Context, sql string, args ...any) *RawStatement {
+	return &RawStatement{ctx: ctx, tx: tx, sql: sql, args: args, dialect: tx.dialect, parser: tx.parser}
+}
+
+// NamedArgs holds the values of named parameters, by name (see Named).
+type NamedArgs map[string]any
+
+// Named passes the values of several named parameters (":name") in one argument of Raw,
+// Exec or a SQL condition, like one sql.Named per entry. A plain map[string]any is a
+// positional argument like any other value.
+func Named(values map[string]any) NamedArgs {
+	return NamedArgs(values)
+}
+
+// Scan runs the query and scans the rows into dest:
+//   - a pointer to a slice of structs (or struct pointers): one element per row;
+//   - a pointer to a struct: the first row, Result.Error is sql.ErrNoRows without rows;
+//   - a pointer to a slice of scalars (*[]string, ...) or to a scalar (*int64, ...):
+//     the first column, of every row or of the first one.
+//
+// Columns are matched to struct fields by column name (the field's DB name, e.g.
+// user_id for UserID, or its Go name), ignoring case; columns without a field are
+// discarded. RowsAffected is the number of rows scanned.
+func (q *RawStatement) Scan(dest any) (result *Result) {
+	defer recoverResult(&result, "Raw", dest)
+	result = &Result{}
+	query, args, err := bindPlaceholders(q.dialect, q.sql, q.args)
+	if err != nil {
+		result.Error = fmt.Errorf("raw query: %w", err)
+		return result
+	}
+	query = renumberBindVars(q.dialect, query)
+
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Pointer || destValue.IsNil() {
+		result.Error = fmt.Errorf("raw query: destination must be a non-nil pointer, got %T", dest)
+		return result
+	}
+
+	ctx := q.ctx
+	var rd reader
+	if q.tx != nil {
+		var leave func()
+		if ctx, leave, err = q.tx.enter(ctx, "Raw"); err != nil {
+			result.Error = err
+			return result
+		}
+		defer leave()
+		rd = q.tx.source
+	} else {
+		rd = q.db.reader(ctx)
+	}
+	logSQL("Executing SQL: %s | Args: %v\n", query, redactArgs(nil, args))
+	rows, err := rd.Query(ctx, query, args...)
+	if err != nil {
+		result.Error = fmt.Errorf("raw query failed: %w", err)
+		return result
+	}
+	defer rows.Close()
+
+	target := destValue.Elem()
+	many := target.Kind() == reflect.Slice && target.Type().Elem().Kind() != reflect.Uint8 // []byte is a scalar
+	elemType := target.Type()
+	if many {
+		elemType = elemType.Elem()
+		target.Set(reflect.MakeSlice(target.Type(), 0, 0))
+	}
+	elemIsPointer := elemType.Kind() == reflect.Pointer && elemType.Elem().Kind() == reflect.Struct
+	if elemIsPointer {
+		elemType = elemType.Elem()
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		result.Error = fmt.Errorf("raw query: reading columns: %w", err)
+		return result
+	}
+	bind, err := q.binder(elemType, columns)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	dests := make([]any, len(columns))
+	for rows.Next() {
+		elem := reflect.New(elemType).Elem()
+		bind(elem, dests)
+		if err := rows.Scan(dests...); err != nil {
+			result.Error = fmt.Errorf("raw query: scanning row %d: %w", result.RowsAffected+1, err)
+			return result
+		}
+		result.RowsAffected++
+		if elemIsPointer {
+			elem = elem.Addr()
+		}
+		if !many {
+			target.Set(elem)
+			break
+		}
+		target.Set(reflect.Append(target, elem))
+	}
+	if err := rows.Err(); err != nil {
+		result.Error = fmt.Errorf("raw query: iterating rows: %w", err)
+		return result
+	}
+	if !many && result.RowsAffected == 0 && elemType.Kind() == reflect.Struct {
+		result.Error = sql.ErrNoRows
+	}
+	return result
+}
+
+// rawScannerType detects struct types scanned as one value (e.g. sql.NullString).
+var rawScannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// binder returns how a row of columns is scanned into a value of typ: struct fields
+// matched by column name, or the first column for scalars.
+func (q *RawStatement) binder(typ reflect.Type, columns []string) (func(elem reflect.Value, dests []any), error) {
+	if typ.Kind() != reflect.Struct || typ == timeValueType || reflect.PointerTo(typ).Implements(rawScannerType) {
+		if len(columns) == 0 {
+			return nil, fmt.Errorf("raw query returned no columns")
+		}
+		return func(elem reflect.Value, dests []any) {
+			dests[0] = elem.Addr().Interface()
+			for i := 1; i < len(dests); i++ {
+				dests[i] = new(any) // Only the first column is scanned
+			}
+		}, nil
+	}
+
+	model, err := q.parser.Parse(reflect.New(typ).Interface())
+	if err != nil {
+		return nil, fmt.Errorf("raw query: failed to parse schema for type %s: %w", typ, err)
+	}
+	indexes := make([][]int, len(columns)) // nil: column without a field
+	for i, column := range columns {
+		for _, field := range model.Fields {
+			if field.IsIgnored || !(strings.EqualFold(field.DBName, column) || strings.EqualFold(field.GoName, column)) {
+				continue
+			}
+			if structField, ok := typ.FieldByName(field.GoName); ok {
+				indexes[i] = structField.Index
+			}
+			break
+		}
+	}
+	return func(elem reflect.Value, dests []any) {
+		for i, index := range indexes {
+			if index == nil {
+				dests[i] = new(any)
+				continue
+			}
+			dests[i] = elem.FieldByIndex(index).Addr().Interface()
+		}
+	}, nil
+}
+
+// Exec executes a SQL statement with "?" placeholders for args (slices expand to
+// lists) on the primary, e.g. a statement the ORM does not build:
+//
+//	res := db.Exec(ctx, "UPDATE accounts SET balance = balance * ? WHERE tier IN (?)", 1.01, tiers)
+//
+// Named parameters are accepted as in Raw. Result.RowsAffected and LastInsertID are
+// filled when the driver reports them.
+func (db *DB) Exec(ctx context.Context, sql string, args ...any) *Result {
+	return execRaw(ctx, db.conn(ctx), db.source.Dialect(), sql, args)
+}
+
+// Exec executes a SQL statement within the transaction. See DB.Exec.
+func (tx *Tx) Exec(ctx context.Context, sql string, args ...any) *Result {
+	err := tx.checkWritable("Exec")
+	if err == nil {
+		var leave func()
+		if ctx, leave, err = tx.enter(ctx, "Exec"); err == nil {
+			defer leave()
+		}
+	}
+	if err != nil {
+		return &Result{Error: err}
+	}
+	return execRaw(ctx, tx.source, tx.dialect, sql, args)
+}
+
+func execRaw(ctx context.Context, exec execer, dialect common.Dialect, sql string, args []any) *Result {
+	result := &Result{}
+	stmt, stmtArgs, err := bindPlaceholders(dialect, sql, args)
+	if err != nil {
+		result.Error = fmt.Errorf("exec: %w", err)
+		return result
+	}
+	stmt = renumberBindVars(dialect, stmt)
+	logSQL("Executing SQL: %s | Args: %v\n", stmt, redactArgs(nil, stmtArgs))
+	res, err := exec.Exec(ctx, stmt, stmtArgs...)
+	if err != nil {
+		result.Error = fmt.Errorf("exec failed: %w", err)
+		return result
+	}
+	if n, err := res.RowsAffected(); err == nil {
+		result.RowsAffected = n
+	}
+	if id, err := res.LastInsertId(); err == nil {
+		result.LastInsertID = id
+	}
+	return result
+}
@@ -0,0 +1,162 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type rawTotal struct {
+	UserID uint
+	Total  float64
+}
+
+func TestRaw_ScansColumnsByName(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	source.queueRows([]string{"user_id", "extra", "TOTAL"}, []any{uint(1), "x", 10.5}, []any{uint(2), "y", 3.0})
+
+	var totals []rawTotal
+	result := db.Raw(ctx, "SELECT user_id, 'x' AS extra, SUM(amount) AS TOTAL FROM orders WHERE status IN (?) AND id > ? GROUP BY user_id",
+		[]string{"paid", "shipped"}, 0).Scan(&totals)
+	require.NoError(t, result.Error)
+	assert.Equal(t, int64(2), result.RowsAffected)
+	assert.Equal(t, []rawTotal{{UserID: 1, Total: 10.5}, {UserID: 2, Total: 3}}, totals, "Unknown columns discarded, names matched ignoring case")
+	assert.Equal(t, "SELECT user_id, 'x' AS extra, SUM(amount) AS TOTAL FROM orders WHERE status IN (?, ?) AND id > ? GROUP BY user_id", source.lastStatement().SQL)
+	assert.Equal(t, []any{"paid", "shipped", 0}, source.lastStatement().Args)
+
+	source.queueRows([]string{"user_id", "total"}, []any{uint(3), 1.0})
+	var ptrs []*rawTotal
+	require.NoError(t, db.Raw(ctx, "SELECT 1").Scan(&ptrs).Error)
+	require.Len(t, ptrs, 1)
+	assert.Equal(t, uint(3), ptrs[0].UserID)
+
+	var total rawTotal
+	source.queueRows([]string{"user_id", "total"})
+	assert.ErrorIs(t, db.Raw(ctx, "SELECT 1").Scan(&total).Error, sql.ErrNoRows)
+}
+
+func TestRaw_ScansScalars(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	source.queueRows([]string{"count"}, []any{int64(42)})
+	var count int64
+	require.NoError(t, db.Raw(ctx, "SELECT COUNT(*) FROM users").Scan(&count).Error)
+	assert.Equal(t, int64(42), count)
+
+	source.queueRows([]string{"name", "age"}, []any{"ana", 30}, []any{"bia", 20})
+	var names []string
+	require.NoError(t, db.Raw(ctx, "SELECT name, age FROM users").Scan(&names).Error)
+	assert.Equal(t, []string{"ana", "bia"}, names, "First column")
+
+	assert.ErrorContains(t, db.Raw(ctx, "SELECT ?").Scan(&names).Error, "more placeholders than arguments")
+	assert.ErrorContains(t, db.Raw(ctx, "SELECT 1").Scan(names).Error, "must be a non-nil pointer")
+}
+
+func TestExec(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	source.affected = 3
+	source.lastID = 9
+
+	result := db.Exec(ctx, "UPDATE accounts SET balance = balance * ? WHERE tier IN (?)", 1.01, []int{1, 2})
+	require.NoError(t, result.Error)
+	assert.Equal(t, int64(3), result.RowsAffected)
+	assert.Equal(t, int64(9), result.LastInsertID)
+	assert.Equal(t, "UPDATE accounts SET balance = balance * ? WHERE tier IN (?, ?)", source.lastStatement().SQL)
+	assert.Equal(t, []any{1.01, 1, 2}, source.lastStatement().Args)
+
+	source.execErr = errors.New("boom")
+	assert.ErrorContains(t, db.Exec(ctx, "DELETE FROM x").Error, "exec failed: boom")
+	source.execErr = nil
+
+	err := db.RunInTransaction(ctx, func(tx *Tx) error {
+		source.queueRows([]string{"id"}, []any{int64(5)})
+		var id int64
+		if err := tx.Raw(ctx, "SELECT id FROM accounts WHERE tier = ? FOR UPDATE", 1).Scan(&id).Error; err != nil {
+			return err
+		}
+		return tx.Exec(ctx, "DELETE FROM accounts WHERE id = ?", id).Error
+	})
+	require.NoError(t, err)
+	statements := source.Statements()
+	assert.Equal(t, "DELETE FROM accounts WHERE id = ?", statements[len(statements)-2].SQL)
+	assert.Equal(t, []any{int64(5)}, statements[len(statements)-2].Args)
+	assert.Equal(t, "COMMIT", statements[len(statements)-1].SQL)
+
+	tx, err := db.BeginReadOnly(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	assert.ErrorIs(t, tx.Exec(ctx, "DELETE FROM accounts").Error, ErrReadOnlyTransaction)
+}
+
+// sqlServerDialect numbers bind variables like SQL Server (@p1, @p2...).
+type sqlServerDialect struct{ mockDialect }
+
+func (d *sqlServerDialect) BindVar(i int) string { return fmt.Sprintf("@p%d", i) }
+
+func TestBindPlaceholders_NamedParameters(t *testing.T) {
+	postgres := &numberedDialect{mockDialect{name: "postgres"}}
+	sqlServer := &sqlServerDialect{mockDialect{name: "sqlserver"}}
+	tests := []struct {
+		dialect  common.Dialect
+		raw      string
+		args     []any
+		want     string
+		wantArgs []any
+	}{
+		{postgres, "status = :status OR old_status = :status", []any{sql.Named("status", "paid")},
+			"status = $1 OR old_status = $2", []any{"paid", "paid"}},
+		{postgres, "a = ? AND b = :b AND c = ?", []any{1, sql.Named("b", 2), 3},
+			"a = $1 AND b = $2 AND c = $3", []any{1, 2, 3}},
+		{postgres, "id IN (:ids) AND x::int = :x AND note <> ':x'", []any{Named(map[string]any{"ids": []int{4, 5}, "x": 6})},
+			"id IN ($1, $2) AND x::int = $3 AND note <> ':x'", []any{4, 5, 6}},
+		{sqlServer, "id = @id AND name = :name AND @@ROWCOUNT > 0", []any{sql.Named("id", 1), sql.Named("name", "ana")},
+			"id = @p1 AND name = @p2 AND @@ROWCOUNT > 0", []any{1, "ana"}},
+		{&mockDialect{}, "id = @id AND t > '10:30'", []any{sql.Named("id", 1)},
+			"id = ? AND t > '10:30'", []any{1}},
+		{&mockDialect{}, "SET @counter = ?", []any{0}, // No named arguments: "@counter" is a variable
+			"SET @counter = ?", []any{0}},
+	}
+	for _, tt := range tests {
+		got, args, err := bindPlaceholders(tt.dialect, tt.raw, tt.args)
+		require.NoError(t, err, tt.raw)
+		assert.Equal(t, tt.want, renumberBindVars(tt.dialect, got), tt.raw)
+		assert.Equal(t, tt.wantArgs, args, tt.raw)
+	}
+
+	_, _, err := bindPlaceholders(postgres, "a = :a AND b = :b", []any{sql.Named("a", 1)})
+	assert.ErrorContains(t, err, "no argument for the parameter :b")
+}
+
+func TestNamedParameters_RawExecAndWhere(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	require.NoError(t, db.Exec(ctx, "UPDATE accounts SET tier = :tier WHERE id IN (:ids)",
+		Named(map[string]any{"tier": "gold", "ids": []int{1, 2}})).Error)
+	assert.Equal(t, "UPDATE accounts SET tier = ? WHERE id IN (?, ?)", source.lastStatement().SQL)
+	assert.Equal(t, []any{"gold", 1, 2}, source.lastStatement().Args)
+
+	settings := map[string]any{"theme": "dark"} // A plain map is a positional value
+	require.NoError(t, db.Exec(ctx, "UPDATE accounts SET settings = ? WHERE tier = :tier", settings, sql.Named("tier", "gold")).Error)
+	assert.Equal(t, []any{settings, "gold"}, source.lastStatement().Args)
+
+	source.queueRows([]string{"count"}, []any{int64(2)})
+	var count int64
+	require.NoError(t, db.Raw(ctx, "SELECT COUNT(*) FROM accounts WHERE tier = :tier", sql.Named("tier", "gold")).Scan(&count).Error)
+	assert.Equal(t, []any{"gold"}, source.lastStatement().Args)
+
+	source.queueRows([]string{"id"})
+	var rows []maskUser
+	require.NoError(t, db.Model(&maskUser{}).Where("name = :name OR name = :alias", sql.Named("name", "ana"), sql.Named("alias", "aninha")).Find(ctx, &rows).Error)
+	assert.Contains(t, source.lastStatement().SQL, "WHERE (name = ? OR name = ?)")
+	assert.Equal(t, []any{"ana", "aninha"}, source.lastStatement().Args)
+}
@@ -0,0 +1,107 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+)
+
+// --- Read-only Transactions ---
+
+// BeginReadOnly starts a read-only transaction, e.g. for report endpoints.
+// The driver opens it in the database's read-only mode (START TRANSACTION READ ONLY on
+// MySQL, BEGIN READ ONLY on Postgres), and the ORM additionally rejects Create, Updates,
+// UpdateIf and Delete on it with ErrReadOnlyTransaction before anything reaches the database.
+func (db *DB) BeginReadOnly(ctx context.Context) (*Tx, error) {
+	return db.Begin(ctx, &sql.TxOptions{ReadOnly: true})
+}
+
+// ReadOnly reports whether the transaction was started in read-only mode.
+func (tx *Tx) ReadOnly() bool {
+	return tx.readOnly
+}
+
+// checkWritable guards write operations on read-only transactions.
+func (tx *Tx) checkWritable(operation string) error {
+	if tx.readOnly {
+		return fmt.Errorf("tx: %s rejected: %w", operation, ErrReadOnlyTransaction)
+	}
+	return nil
+}
+
+// --- Read-only Databases ---
+
+// readOnlyKeywords are the statements a read-only database runs: reads, and session
+// settings. WITH is allowed unless one of its parts writes.
+var readOnlyKeywords = map[string]bool{
+	"SELECT": true, "WITH": true, "VALUES": true, "SHOW": true, "EXPLAIN": true,
+	"DESCRIBE": true, "DESC": true, "SET": true, "USE": true,
+}
+
+// writeGuard wraps ds so that it refuses writes when the config sets safety.readOnly
+// (the default of the production profile): statements other than reads fail with
+// ErrReadOnlyDatabase before reaching the database, and transactions are begun
+// read-only. Only statements run through the DB are checked; raw connections from
+// GetDataSource are not.
+func (db *DB) writeGuard(ds common.DataSource) common.DataSource {
+	if !db.config.Safety.ReadOnly {
+		return ds
+	}
+	return readOnlySource{DataSource: ds}
+}
+
+type readOnlySource struct {
+	common.DataSource
+}
+
+func (s readOnlySource) Exec(ctx context.Context, query string, args ...any) (common.Result, error) {
+	if err := checkReadOnlyStatement(query); err != nil {
+		return nil, err
+	}
+	return s.DataSource.Exec(ctx, query, args...)
+}
+
+func (s readOnlySource) Query(ctx context.Context, query string, args ...any) (common.Rows, error) {
+	if err := checkReadOnlyStatement(query); err != nil {
+		return nil, err
+	}
+	return s.DataSource.Query(ctx, query, args...)
+}
+
+func (s readOnlySource) QueryRow(ctx context.Context, query string, args ...any) common.RowScanner {
+	if err := checkReadOnlyStatement(query); err != nil {
+		return &firstRowScanner{err: err}
+	}
+	return s.DataSource.QueryRow(ctx, query, args...)
+}
+
+// BeginTx begins the transaction in the database's read-only mode, whatever opts ask.
+func (s readOnlySource) BeginTx(ctx context.Context, opts any) (common.Tx, error) {
+	txOpt, _ := opts.(sql.TxOptions)
+	txOpt.ReadOnly = true
+	return s.DataSource.BeginTx(ctx, txOpt)
+}
+
+// checkReadOnlyStatement rejects a statement that is not a read (see readOnlyKeywords).
+func checkReadOnlyStatement(query string) error {
+	words := strings.FieldsFunc(strings.ToUpper(query), func(r rune) bool {
+		return r != '_' && !unicode.IsLetter(r)
+	})
+	if len(words) > 0 && readOnlyKeywords[words[0]] {
+		if words[0] != "WITH" {
+			return nil
+		}
+		if !slices.ContainsFunc(words, func(word string) bool {
+			return word == "INSERT" || word == "UPDATE" || word == "DELETE" || word == "MERGE"
+		}) {
+			return nil
+		}
+	}
+	statement, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	return fmt.Errorf("%w: %s statement refused (safety.readOnly)", ErrReadOnlyDatabase, statement)
+}
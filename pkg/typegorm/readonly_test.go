@@ -0,0 +1,69 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBeginReadOnly_RejectsWrites(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	tx, err := db.BeginReadOnly(ctx)
+	require.NoError(t, err)
+	assert.True(t, tx.ReadOnly())
+
+	user := &maskUser{ID: 1, Name: "Ana"}
+	assert.ErrorIs(t, tx.Create(ctx, &maskUser{Name: "Bia"}).Error, ErrReadOnlyTransaction)
+	assert.ErrorIs(t, tx.Updates(ctx, user, map[string]any{"name": "Eva"}).Error, ErrReadOnlyTransaction)
+	assert.ErrorIs(t, tx.UpdateIf(ctx, user, map[string]any{"name": "Eva"}, nil).Error, ErrReadOnlyTransaction)
+	assert.ErrorIs(t, tx.Delete(ctx, user).Error, ErrReadOnlyTransaction)
+
+	source.queueRows([]string{"id", "name", "email", "age"}, []any{uint(1), "Ana", "", 30})
+	var users []maskUser
+	require.NoError(t, tx.Find(ctx, &users).Error, "reads are allowed")
+	require.NoError(t, tx.Rollback())
+
+	assert.Equal(t, []string{"BEGIN", "SELECT `id`, `name`, `email`, `age` FROM `mask_users`", "ROLLBACK"}, sqlOf(source.Statements()))
+}
+
+func TestSafetyReadOnly_RefusesWritesOfTheDB(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Safety.ReadOnly = true
+	source := newMockSource()
+	db := NewDB(source, schema.NewParser(nil), cfg)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, db.Create(ctx, &maskUser{Name: "Bia"}).Error, ErrReadOnlyDatabase)
+	assert.ErrorIs(t, db.Updates(ctx, &maskUser{ID: 1}, map[string]any{"name": "Eva"}).Error, ErrReadOnlyDatabase)
+	assert.ErrorIs(t, db.Exec(ctx, "WITH old AS (SELECT id FROM mask_users) DELETE FROM mask_users WHERE id IN (SELECT id FROM old)").Error, ErrReadOnlyDatabase)
+
+	source.queueRows([]string{"id", "name", "email", "age"}, []any{uint(1), "Ana", "", 30})
+	var users []maskUser
+	require.NoError(t, db.Find(ctx, &users).Error, "reads are allowed")
+
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	assert.True(t, tx.ReadOnly(), "transactions are read-only")
+	assert.ErrorIs(t, tx.Delete(ctx, &maskUser{ID: 1}).Error, ErrReadOnlyTransaction)
+	require.NoError(t, tx.Rollback())
+	assert.Equal(t, []string{"SELECT `id`, `name`, `email`, `age` FROM `mask_users`", "BEGIN", "ROLLBACK"}, sqlOf(source.Statements()), "no write reaches the database")
+}
+
+func TestLoggingLevel_HidesExecutedSQL(t *testing.T) {
+	t.Cleanup(func() { setLogLevel("") })
+	setLogLevel("warn")
+	assert.False(t, sqlLogging.Load())
+	setLogLevel("DEBUG")
+	assert.True(t, sqlLogging.Load())
+
+	cfg := config.NewDefaultConfig()
+	cfg.Logging.Level = "error"
+	NewDB(newMockSource(), schema.NewParser(nil), cfg)
+	assert.False(t, sqlLogging.Load(), "NewDB applies Logging.Level")
+}
@@ -0,0 +1,315 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- Application-Level Record Locks ---
+
+// ErrRecordLocked is returned by LockRecord when another owner holds an unexpired lock
+// on the record.
+var ErrRecordLocked = errors.New("typegorm: record locked")
+
+// ErrLockLost is reported by RecordLock.Err when the lock could not be renewed before
+// it expired, or was taken over by another owner after expiring.
+var ErrLockLost = errors.New("typegorm: record lock lost")
+
+// recordLockEntry is a row of the locks table.
+type recordLockEntry struct {
+	_         struct{}  `typegorm:"table:typegorm_locks"`
+	Resource  string    `typegorm:"primaryKey;size:191"` // "<table>:<primary key>"
+	Owner     string    `typegorm:"size:128;not null"`
+	ExpiresAt time.Time `typegorm:"not null"`
+}
+
+// RecordLock is a lock on a record held across transactions and processes, unlike
+// SELECT ... FOR UPDATE which ends with its transaction. It is kept alive by a
+// heartbeat renewing it every third of its TTL until Release; if the process dies, it
+// expires and another owner can take it.
+type RecordLock struct {
+	Resource string // Locked record: "<table>:<primary key>"
+	Owner    string // Unique to this lock: "<hostname>:<pid>:<token>"
+
+	db   *DB
+	ttl  time.Duration
+	now  NowFunc
+	stop chan struct{}
+	done chan struct{}
+	lost chan struct{}
+
+	mu       sync.Mutex
+	expires  time.Time
+	err      error
+	released bool
+}
+
+// LockRecord locks the record value points to (identified by its table and primary
+// key) for ttl, renewed in the background until Release:
+//
+//	lock, err := db.LockRecord(ctx, &invoice, 30*time.Second)
+//	if errors.Is(err, typegorm.ErrRecordLocked) {
+//		return // Someone else is processing the invoice
+//	}
+//	defer lock.Release(ctx)
+//	select {
+//	case <-lock.Lost(): // Stop: another process may take the record over
+//	...
+//	}
+//
+// The locks live in the table typegorm_locks, created on first use. Expiry uses the
+// clock of the DB (see UseNowFunc), so the clocks of the processes must be in sync.
+func (db *DB) LockRecord(ctx context.Context, value any, ttl time.Duration) (*RecordLock, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("lock record: ttl must be positive, got %s", ttl)
+	}
+	resource, err := db.lockResource(value)
+	if err != nil {
+		return nil, fmt.Errorf("lock record: %w", err)
+	}
+	return db.acquireLock(ctx, resource, ttl)
+}
+
+// acquireLock takes the lock on resource (any name) for ttl and starts its heartbeat.
+func (db *DB) acquireLock(ctx context.Context, resource string, ttl time.Duration) (*RecordLock, error) {
+	if err := db.ensureLockTable(ctx); err != nil {
+		return nil, err
+	}
+
+	lock := &RecordLock{
+		Resource: resource,
+		Owner:    lockOwner(),
+		db:       db,
+		ttl:      ttl,
+		now:      nowFunc(ctx, db.now),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		lost:     make(chan struct{}),
+	}
+	dialect := db.source.Dialect()
+	table := dialect.Quote("typegorm_locks")
+	conn := db.conn(ctx)
+	now := lock.clock()
+
+	// An expired lock is free: its owner stopped renewing it
+	expired := fmt.Sprintf("DELETE FROM %s WHERE %s = %s AND %s < %s", table,
+		dialect.Quote("resource"), dialect.BindVar(1), dialect.Quote("expires_at"), dialect.BindVar(2))
+	logSQL("Executing SQL: %s | Args: %v\n", expired, redactArgs(nil, []any{resource, now}))
+	if _, err := conn.Exec(ctx, expired, resource, now); err != nil {
+		return nil, fmt.Errorf("lock record %s: %w", resource, err)
+	}
+
+	lock.expires = now.Add(ttl)
+	insert := fmt.Sprintf("INSERT INTO %s (%s, %s, %s) VALUES (%s, %s, %s)", table,
+		dialect.Quote("resource"), dialect.Quote("owner"), dialect.Quote("expires_at"),
+		dialect.BindVar(1), dialect.BindVar(2), dialect.BindVar(3))
+	logSQL("Executing SQL: %s | Args: %v\n", insert, redactArgs(nil, []any{resource, lock.Owner, lock.expires}))
+	if _, err := conn.Exec(ctx, insert, resource, lock.Owner, lock.expires); err != nil {
+		if errors.Is(translateDuplicateKey(err, nil), ErrDuplicateKey) {
+			return nil, fmt.Errorf("lock record %s%s: %w", resource, db.lockHolder(ctx, resource), ErrRecordLocked)
+		}
+		return nil, fmt.Errorf("lock record %s: %w", resource, err)
+	}
+	fmt.Printf("Locked %s as %s until %s.\n", resource, lock.Owner, lock.expires.Format(time.RFC3339))
+	go lock.heartbeat()
+	return lock, nil
+}
+
+// lockResource identifies the record value points to: "<table>:<key>[,<key>...]".
+func (db *DB) lockResource(value any) (string, error) {
+	model, err := db.parser.Parse(value)
+	if err != nil {
+		return "", err
+	}
+	if len(model.PrimaryKeys) == 0 {
+		return "", fmt.Errorf("model %s has no primary key", model.Name)
+	}
+	record := reflect.ValueOf(value)
+	for record.Kind() == reflect.Pointer {
+		if record.IsNil() {
+			return "", fmt.Errorf("value must be a non-nil pointer to a struct")
+		}
+		record = record.Elem()
+	}
+	keys := make([]string, len(model.PrimaryKeys))
+	for i, pk := range model.PrimaryKeys {
+		key, ok := virtualKey(record.FieldByName(pk.GoName))
+		if !ok {
+			return "", fmt.Errorf("%s has a zero primary key (%s)", model.Name, pk.GoName)
+		}
+		keys[i] = fmt.Sprint(key)
+	}
+	return model.QualifiedTableName() + ":" + strings.Join(keys, ","), nil
+}
+
+// ensureLockTable creates the locks table once per DB.
+func (db *DB) ensureLockTable(ctx context.Context) error {
+	if db.lockTable.Load() {
+		return nil
+	}
+	if err := db.AutoMigrate(ctx, &recordLockEntry{}); err != nil {
+		return fmt.Errorf("lock record: creating the locks table: %w", err)
+	}
+	db.lockTable.Store(true)
+	return nil
+}
+
+// lockHolder describes the current holder of resource for error messages; empty when
+// it cannot be read.
+func (db *DB) lockHolder(ctx context.Context, resource string) string {
+	dialect := db.source.Dialect()
+	query := fmt.Sprintf("SELECT %s, %s FROM %s WHERE %s = %s", dialect.Quote("owner"), dialect.Quote("expires_at"),
+		dialect.Quote("typegorm_locks"), dialect.Quote("resource"), dialect.BindVar(1))
+	var entry recordLockEntry
+	if err := db.reader(ctx).QueryRow(ctx, query, resource).Scan(&entry.Owner, &entry.ExpiresAt); err != nil {
+		return ""
+	}
+	return fmt.Sprintf(" (held by %s until %s)", entry.Owner, entry.ExpiresAt.Format(time.RFC3339))
+}
+
+// lockOwner returns a new owner identity, readable in the locks table.
+func lockOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d:%s", host, os.Getpid(), UniqueToken(16))
+}
+
+func (l *RecordLock) clock() time.Time {
+	if l.now != nil {
+		return l.now()
+	}
+	return time.Now()
+}
+
+// heartbeat renews the lock every third of its TTL until Release or until it is lost.
+func (l *RecordLock) heartbeat() {
+	defer close(l.done)
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			if err := l.renew(); err != nil {
+				l.mu.Lock()
+				l.err = err
+				l.mu.Unlock()
+				fmt.Printf("Warning: %v\n", err)
+				close(l.lost)
+				return
+			}
+		}
+	}
+}
+
+// renew extends the lock; a failed renewal is retried at the next beat while the lock
+// has not expired.
+func (l *RecordLock) renew() error {
+	ctx, cancel := context.WithTimeout(context.Background(), l.ttl/3)
+	defer cancel()
+	dialect := l.db.source.Dialect()
+	now := l.clock()
+	expires := now.Add(l.ttl)
+	stmt := fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s = %s AND %s = %s", dialect.Quote("typegorm_locks"),
+		dialect.Quote("expires_at"), dialect.BindVar(1), dialect.Quote("resource"), dialect.BindVar(2),
+		dialect.Quote("owner"), dialect.BindVar(3))
+	logSQL("Executing SQL: %s | Args: %v\n", stmt, redactArgs(nil, []any{expires, l.Resource, l.Owner}))
+	result, err := l.db.conn(ctx).Exec(ctx, stmt, expires, l.Resource, l.Owner)
+	var affected int64
+	if err == nil {
+		affected, err = result.RowsAffected()
+	}
+	if err == nil && affected == 0 {
+		// MySQL counts changed rows: an unchanged expires_at (same second) reports 0
+		affected, err = l.owned(ctx)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch {
+	case err != nil && now.Before(l.expires):
+		fmt.Printf("Warning: renewing the lock on %s failed, retrying: %v\n", l.Resource, err)
+		return nil
+	case err != nil:
+		return fmt.Errorf("lock on %s expired, renewal failed: %v: %w", l.Resource, err, ErrLockLost)
+	case affected == 0:
+		return fmt.Errorf("lock on %s was taken over after expiring: %w", l.Resource, ErrLockLost)
+	}
+	l.expires = expires
+	return nil
+}
+
+// owned reads the owner of the lock row: 1 when it is still l.Owner, 0 otherwise.
+func (l *RecordLock) owned(ctx context.Context) (int64, error) {
+	dialect := l.db.source.Dialect()
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = %s", dialect.Quote("owner"), dialect.Quote("typegorm_locks"),
+		dialect.Quote("resource"), dialect.BindVar(1))
+	logSQL("Executing SQL: %s | Args: %v\n", query, redactArgs(nil, []any{l.Resource}))
+	var owner string
+	err := l.db.conn(ctx).QueryRow(ctx, query, l.Resource).Scan(&owner)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return 0, nil
+	case err != nil:
+		return 0, err
+	case owner != l.Owner:
+		return 0, nil
+	}
+	return 1, nil
+}
+
+// Lost returns a channel closed when the lock is lost: work relying on it must stop.
+func (l *RecordLock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Err returns why the lock was lost (matching ErrLockLost), or nil.
+func (l *RecordLock) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// ExpiresAt returns when the lock expires unless renewed.
+func (l *RecordLock) ExpiresAt() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.expires
+}
+
+// Release stops the heartbeat and frees the record. Releasing twice, or a lost lock,
+// is a no-op.
+func (l *RecordLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	if l.released {
+		l.mu.Unlock()
+		return nil
+	}
+	l.released = true
+	l.mu.Unlock()
+	close(l.stop)
+	<-l.done
+	if l.Err() != nil {
+		return nil
+	}
+
+	dialect := l.db.source.Dialect()
+	stmt := fmt.Sprintf("DELETE FROM %s WHERE %s = %s AND %s = %s", dialect.Quote("typegorm_locks"),
+		dialect.Quote("resource"), dialect.BindVar(1), dialect.Quote("owner"), dialect.BindVar(2))
+	logSQL("Executing SQL: %s | Args: %v\n", stmt, redactArgs(nil, []any{l.Resource, l.Owner}))
+	if _, err := l.db.conn(ctx).Exec(ctx, stmt, l.Resource, l.Owner); err != nil {
+		return fmt.Errorf("release lock on %s: %w", l.Resource, err)
+	}
+	fmt.Printf("Released the lock on %s.\n", l.Resource)
+	return nil
+}
@@ -0,0 +1,121 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type lockInvoice struct {
+	ID    uint `typegorm:"primaryKey"`
+	Total int
+}
+
+func hasStatement(source *mockSource, prefix string) bool {
+	for _, stmt := range source.Statements() {
+		if strings.HasPrefix(stmt.SQL, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLockRecord_AcquireRenewRelease(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	frozen := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	db.UseNowFunc(func() time.Time { return frozen })
+
+	lock, err := db.LockRecord(ctx, &lockInvoice{ID: 42}, 30*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, "lock_invoices:42", lock.Resource)
+	assert.Equal(t, frozen.Add(30*time.Millisecond), lock.ExpiresAt())
+
+	statements := source.Statements()
+	require.GreaterOrEqual(t, len(statements), 3)
+	assert.Contains(t, statements[0].SQL, "CREATE TABLE IF NOT EXISTS `typegorm_locks`")
+	assert.Equal(t, "DELETE FROM `typegorm_locks` WHERE `resource` = ? AND `expires_at` < ?", statements[1].SQL)
+	assert.Equal(t, []any{"lock_invoices:42", frozen}, statements[1].Args)
+	assert.Equal(t, "INSERT INTO `typegorm_locks` (`resource`, `owner`, `expires_at`) VALUES (?, ?, ?)", statements[2].SQL)
+	assert.Equal(t, lock.Owner, statements[2].Args[1])
+
+	require.Eventually(t, func() bool { return hasStatement(source, "UPDATE `typegorm_locks` SET `expires_at` = ?") },
+		time.Second, 5*time.Millisecond, "The heartbeat renews the lock")
+
+	require.NoError(t, lock.Release(ctx))
+	last := source.lastStatement()
+	assert.Equal(t, "DELETE FROM `typegorm_locks` WHERE `resource` = ? AND `owner` = ?", last.SQL)
+	assert.Equal(t, []any{"lock_invoices:42", lock.Owner}, last.Args)
+	require.NoError(t, lock.Release(ctx), "Releasing twice is a no-op")
+	assert.NoError(t, lock.Err())
+
+	other, err := db.LockRecord(ctx, &lockInvoice{ID: 43}, time.Minute)
+	require.NoError(t, err)
+	defer other.Release(ctx)
+	assert.NotEqual(t, lock.Owner, other.Owner)
+	creates := 0
+	for _, stmt := range source.Statements() {
+		if strings.HasPrefix(stmt.SQL, "CREATE TABLE") {
+			creates++
+		}
+	}
+	assert.Equal(t, 1, creates, "The locks table is created once")
+}
+
+func TestLockRecord_HeldAndLost(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	source.execErrs = []error{nil, nil, errors.New("Error 1062 (23000): Duplicate entry 'lock_invoices:42' for key 'PRIMARY'")}
+	until := time.Date(2026, 1, 1, 12, 0, 30, 0, time.UTC)
+	source.queueRows([]string{"owner", "expires_at"}, []any{"worker-2", until})
+	_, err := db.LockRecord(ctx, &lockInvoice{ID: 42}, time.Minute)
+	assert.ErrorIs(t, err, ErrRecordLocked)
+	assert.ErrorContains(t, err, "held by worker-2 until 2026-01-01T12:00:30Z")
+
+	_, err = db.LockRecord(ctx, &lockInvoice{}, time.Minute)
+	assert.ErrorContains(t, err, "zero primary key")
+
+	lock, err := db.LockRecord(ctx, &lockInvoice{ID: 7}, 30*time.Millisecond)
+	require.NoError(t, err)
+	source.mu.Lock()
+	source.affected = 0 // Another owner took the expired lock over
+	source.mu.Unlock()
+	select {
+	case <-lock.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("lock not reported lost")
+	}
+	assert.ErrorIs(t, lock.Err(), ErrLockLost)
+	count := len(source.Statements())
+	require.NoError(t, lock.Release(ctx))
+	assert.Len(t, source.Statements(), count, "A lost lock is not deleted: it belongs to its new owner")
+}
+
+func TestLockRecord_RenewalChangingNoRowKeepsTheLock(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	lock, err := db.LockRecord(ctx, &lockInvoice{ID: 8}, 30*time.Millisecond)
+	require.NoError(t, err)
+	source.mu.Lock()
+	source.affected = 0 // MySQL: expires_at unchanged, no row changed
+	source.mu.Unlock()
+	for i := 0; i < 100; i++ {
+		source.queueRows([]string{"owner"}, []any{lock.Owner})
+	}
+	require.Eventually(t, func() bool { return hasStatement(source, "SELECT `owner` FROM `typegorm_locks` WHERE `resource` = ?") },
+		time.Second, 5*time.Millisecond, "Ownership is checked again")
+	assert.NoError(t, lock.Err())
+	select {
+	case <-lock.Lost():
+		t.Fatal("lock reported lost while still owned")
+	default:
+	}
+	require.NoError(t, lock.Release(ctx))
+}
@@ -0,0 +1,155 @@
+package typegorm
+
+import (
+	"database/sql/driver"
+	"reflect"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Sensitive Value Redaction ---
+
+// redactedValue replaces the values of sensitive columns in SQL logs.
+const redactedValue = "[REDACTED]"
+
+// Sensitive marks an argument of Raw, Exec or a SQL condition as sensitive: it is bound
+// as its value, and logged as [REDACTED] like the "sensitive" fields of the models:
+//
+//	db.Exec(ctx, "UPDATE users SET password_hash = ? WHERE id = ?", typegorm.Sensitive(hash), id)
+//
+// The value must be a single driver value (not a slice expanded by IN).
+func Sensitive(value any) any {
+	return sensitiveValue{value: value}
+}
+
+// sensitiveValue is an argument wrapped with Sensitive.
+type sensitiveValue struct{ value any }
+
+// Value binds the wrapped value.
+func (v sensitiveValue) Value() (driver.Value, error) {
+	return driver.DefaultParameterConverter.ConvertValue(v.value)
+}
+
+// redactArgs returns args for logging, with the values bound to the model's sensitive
+// fields (tag "sensitive") and the arguments wrapped with Sensitive replaced by
+// [REDACTED]; model is nil for raw SQL. The values are taken from sources:
+// struct pointers (their sensitive fields), maps keyed by "column [operator]" (update
+// data and conditions) and []any of values already known to be sensitive. Other
+// sources, such as options, are ignored. Any argument equal to a sensitive value is
+// redacted, so an identical non-sensitive value may be hidden as well.
+func redactArgs(model *schema.Model, args []any, sources ...any) []any {
+	args = redactMarked(args)
+	if model == nil || !hasSensitiveFields(model) {
+		return args
+	}
+	var secrets []any
+	for _, source := range sources {
+		secrets = appendSensitiveValues(secrets, model, source)
+	}
+	if len(secrets) == 0 {
+		return args
+	}
+	redacted := make([]any, len(args))
+	for i, arg := range args {
+		redacted[i] = arg
+		for _, secret := range secrets {
+			if reflect.DeepEqual(arg, secret) {
+				redacted[i] = redactedValue
+				break
+			}
+		}
+	}
+	return redacted
+}
+
+// redactMarked replaces the arguments wrapped with Sensitive by [REDACTED].
+func redactMarked(args []any) []any {
+	var redacted []any
+	for i, arg := range args {
+		if _, ok := arg.(sensitiveValue); ok {
+			if redacted == nil {
+				redacted = append([]any(nil), args...)
+			}
+			redacted[i] = redactedValue
+		}
+	}
+	if redacted == nil {
+		return args
+	}
+	return redacted
+}
+
+func hasSensitiveFields(model *schema.Model) bool {
+	for _, field := range model.Fields {
+		if field.Sensitive {
+			return true
+		}
+	}
+	return false
+}
+
+// appendSensitiveValues appends the non-zero sensitive values found in source. Slices
+// bound to a sensitive column (IN conditions) contribute each element.
+func appendSensitiveValues(secrets []any, model *schema.Model, source any) []any {
+	switch src := source.(type) {
+	case nil:
+		return secrets
+	case chainWhere:
+		for _, cond := range src {
+			secrets = appendSensitiveValues(secrets, model, cond.cond)
+		}
+		return secrets
+	case Expr:
+		return appendExprSecrets(secrets, model, src)
+	case []any:
+		for _, value := range src {
+			secrets = appendSecret(secrets, reflect.ValueOf(value))
+		}
+		return secrets
+	case map[string]any:
+		for key, value := range src {
+			column, _, err := parseConditionKey(key)
+			if err != nil {
+				continue
+			}
+			field, ok := model.GetFieldByDBName(column)
+			if !ok {
+				field, ok = model.GetField(column)
+			}
+			if ok && field.Sensitive {
+				secrets = appendSecret(secrets, reflect.ValueOf(value))
+			}
+		}
+		return secrets
+	}
+
+	value := reflect.ValueOf(source)
+	if value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct || value.Type() != model.Type {
+		return secrets
+	}
+	for _, field := range model.Fields {
+		if field.Sensitive {
+			secrets = appendSecret(secrets, value.FieldByName(field.GoName))
+		}
+	}
+	return secrets
+}
+
+func appendSecret(secrets []any, value reflect.Value) []any {
+	if !value.IsValid() || value.IsZero() {
+		return secrets
+	}
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+	if (value.Kind() == reflect.Slice && value.Type().Elem().Kind() != reflect.Uint8) || value.Kind() == reflect.Array {
+		for i := 0; i < value.Len(); i++ {
+			secrets = appendSecret(secrets, value.Index(i))
+		}
+		return secrets
+	}
+	return append(secrets, value.Interface())
+}
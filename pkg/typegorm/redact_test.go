@@ -0,0 +1,68 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type secretAccount struct {
+	ID       uint `typegorm:"primaryKey;autoIncrement"`
+	Email    string
+	Password string `typegorm:"sensitive"`
+	Token    string `typegorm:"sensitive"`
+}
+
+func TestRedactArgs(t *testing.T) {
+	db, _ := newMockDB()
+	model, err := db.GetModel(&secretAccount{})
+	require.NoError(t, err)
+
+	account := &secretAccount{ID: 1, Email: "a@x.io", Password: "hunter2"}
+	assert.Equal(t, []any{"a@x.io", redactedValue}, redactArgs(model, []any{"a@x.io", "hunter2"}, account), "struct source")
+
+	data := map[string]any{"email": "b@x.io", "password": "pw"}
+	assert.Equal(t, []any{"b@x.io", redactedValue, uint(1)}, redactArgs(model, []any{"b@x.io", "pw", uint(1)}, data), "update data")
+
+	conds := map[string]any{"token IN": []string{"t1", "t2"}, "email": "c@x.io"}
+	assert.Equal(t, []any{redactedValue, redactedValue, "c@x.io"}, redactArgs(model, []any{"t1", "t2", "c@x.io"}, conds, Limit(1)), "conditions; options ignored")
+
+	assert.Equal(t, []any{redactedValue, "x"}, redactArgs(model, []any{"hash", "x"}, []any{"hash"}), "known values")
+
+	args := []any{"a@x.io", "hunter2"}
+	maskModel, err := db.GetModel(&maskUser{})
+	require.NoError(t, err)
+	assert.Equal(t, args, redactArgs(maskModel, args, &maskUser{Name: "hunter2"}), "models without sensitive fields are unchanged")
+
+	marked := []any{Sensitive("hunter2"), 7}
+	assert.Equal(t, []any{redactedValue, 7}, redactArgs(nil, marked), "raw SQL arguments marked Sensitive")
+	assert.Equal(t, []any{redactedValue, 7}, redactArgs(maskModel, marked))
+	assert.Equal(t, Sensitive("hunter2"), marked[0], "args are not changed")
+}
+
+func TestSensitive_BindsTheWrappedValue(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	require.NoError(t, db.Exec(ctx, "UPDATE secret_accounts SET password = :pw WHERE id = ?", sql.Named("pw", Sensitive("hunter2")), 4).Error)
+	arg := source.lastStatement().Args[0]
+	require.Implements(t, (*driver.Valuer)(nil), arg)
+	value, err := arg.(driver.Valuer).Value()
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", value)
+	value, err = Sensitive(uint16(3)).(driver.Valuer).Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), value, "converted like database/sql does")
+}
+
+func TestRedactArgsDoesNotChangeBoundValues(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	require.NoError(t, db.Updates(ctx, &secretAccount{ID: 4}, map[string]any{"password": "hunter2"}).Error)
+	assert.Equal(t, []any{"hunter2", uint(4)}, source.lastStatement().Args, "only the log is redacted")
+}
@@ -0,0 +1,139 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Table/Column Renames ---
+
+// RenameTableSQL builds the statement renaming the model's table from its previous name
+// (in the same schema).
+func RenameTableSQL(dialect common.Dialect, model *schema.Model, from string) string {
+	previous := &schema.Model{TableName: from, Schema: model.Schema}
+	switch dialect.Name() {
+	case "mysql":
+		return fmt.Sprintf("RENAME TABLE %s TO %s", quoteTable(dialect, previous), quoteTable(dialect, model))
+	case "sqlserver":
+		return fmt.Sprintf("EXEC sp_rename %s, %s", quoteComment(dialect, previous.QualifiedTableName()), quoteComment(dialect, model.TableName))
+	}
+	// The new name of ALTER TABLE ... RENAME TO is never schema-qualified
+	return fmt.Sprintf("ALTER TABLE %s RENAME TO %s", quoteTable(dialect, previous), dialect.Quote(model.TableName))
+}
+
+// RenameColumnSQL builds the statement renaming a column of the model's table.
+func RenameColumnSQL(dialect common.Dialect, model *schema.Model, from, to string) string {
+	if dialect.Name() == "sqlserver" {
+		return fmt.Sprintf("EXEC sp_rename %s, %s, 'COLUMN'", quoteComment(dialect, model.QualifiedTableName()+"."+from), quoteComment(dialect, to))
+	}
+	return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", quoteTable(dialect, model), dialect.Quote(from), dialect.Quote(to))
+}
+
+// RenameStatements builds the renames declared with the "renamedFrom" hints of a model,
+// from the most recent previous names, for migration scripts: the table first, then its
+// columns. Migrator.ApplyRenames applies them only where the old names still exist.
+func RenameStatements(dialect common.Dialect, model *schema.Model) []string {
+	var statements []string
+	if len(model.RenamedFrom) > 0 {
+		statements = append(statements, RenameTableSQL(dialect, model, model.RenamedFrom[0]))
+	}
+	for _, field := range model.Fields {
+		if !field.IsIgnored && len(field.RenamedFrom) > 0 {
+			statements = append(statements, RenameColumnSQL(dialect, model, field.RenamedFrom[0], field.DBName))
+		}
+	}
+	return statements
+}
+
+// hasRenames reports whether the model declares any "renamedFrom" hint.
+func hasRenames(model *schema.Model) bool {
+	if len(model.RenamedFrom) > 0 {
+		return true
+	}
+	for _, field := range model.Fields {
+		if len(field.RenamedFrom) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyRenames renames the tables and columns of the models (values or *schema.Model)
+// whose "renamedFrom" hints name an existing table or column while the current name does
+// not exist yet, preserving their data. It is idempotent; AutoMigrate calls it before
+// creating the tables.
+func (m *Migrator) ApplyRenames(ctx context.Context, values ...any) error {
+	for _, value := range values {
+		model, ok := value.(*schema.Model)
+		if !ok {
+			var err error
+			if model, err = m.db.GetModel(value); err != nil {
+				return fmt.Errorf("migrator: failed to parse schema for type %T: %w", value, err)
+			}
+		}
+		statements, err := m.renameStatements(ctx, model)
+		if err != nil {
+			return err
+		}
+		if err := m.exec(ctx, statements...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renameStatements compares the model's hints with the existing table.
+func (m *Migrator) renameStatements(ctx context.Context, model *schema.Model) ([]string, error) {
+	if !hasRenames(model) {
+		return nil, nil
+	}
+	dialect := m.db.source.Dialect()
+	var statements []string
+	columns, exists := m.tableColumns(ctx, model)
+	if !exists {
+		for _, from := range model.RenamedFrom {
+			previous := &schema.Model{TableName: from, Schema: model.Schema}
+			if columns, exists = m.tableColumns(ctx, previous); exists {
+				statements = append(statements, RenameTableSQL(dialect, model, from))
+				break
+			}
+		}
+	}
+	if !exists {
+		return nil, nil // New table: AutoMigrate creates it
+	}
+	for _, field := range model.Fields {
+		if field.IsIgnored || columns[strings.ToLower(field.DBName)] {
+			continue
+		}
+		for _, from := range field.RenamedFrom {
+			if columns[strings.ToLower(from)] {
+				statements = append(statements, RenameColumnSQL(dialect, model, from, field.DBName))
+				break
+			}
+		}
+	}
+	return statements, nil
+}
+
+// tableColumns returns the (lowercased) columns of the model's table, and whether it exists.
+func (m *Migrator) tableColumns(ctx context.Context, model *schema.Model) (map[string]bool, bool) {
+	rows, err := m.db.conn(ctx).Query(ctx, fmt.Sprintf("SELECT * FROM %s WHERE 1 = 0", quoteTable(m.db.source.Dialect(), model)))
+	if err != nil {
+		return nil, false // Missing table
+	}
+	defer rows.Close()
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, false
+	}
+	columns := make(map[string]bool, len(names))
+	for _, name := range names {
+		columns[strings.ToLower(name)] = true
+	}
+	return columns, true
+}
@@ -0,0 +1,58 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type renamedContact struct {
+	_     struct{} `typegorm:"table:contacts;renamedFrom:people"`
+	ID    uint     `typegorm:"primaryKey;autoIncrement"`
+	Email string   `typegorm:"column:email;renamedFrom:e_mail,mail"`
+	Name  string
+}
+
+func TestRenameStatements(t *testing.T) {
+	for dialect, expected := range map[string][]string{
+		"mysql": {
+			"RENAME TABLE `people` TO `contacts`",
+			"ALTER TABLE `contacts` RENAME COLUMN `e_mail` TO `email`",
+		},
+		"postgres": {
+			"ALTER TABLE `people` RENAME TO `contacts`",
+			"ALTER TABLE `contacts` RENAME COLUMN `e_mail` TO `email`",
+		},
+		"sqlserver": {
+			"EXEC sp_rename 'people', 'contacts'",
+			"EXEC sp_rename 'contacts.e_mail', 'email', 'COLUMN'",
+		},
+	} {
+		db, _ := newMockDBWithDialect(dialect)
+		model, err := db.GetModel(&renamedContact{})
+		require.NoError(t, err)
+		assert.Equal(t, expected, RenameStatements(db.source.Dialect(), model), dialect)
+	}
+}
+
+func TestApplyRenames(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	source.queueRows([]string{"id", "MAIL", "name"}) // The table exists with an older column name
+	require.NoError(t, db.Migrator().ApplyRenames(ctx, &renamedContact{}))
+	assert.Equal(t, "ALTER TABLE `contacts` RENAME COLUMN `mail` TO `email`", source.lastStatement().SQL)
+
+	before := len(source.Statements())
+	source.queueRows([]string{"id", "email", "name"})
+	require.NoError(t, db.Migrator().ApplyRenames(ctx, &renamedContact{}))
+	assert.Len(t, source.Statements(), before+1, "already renamed: only the introspection query")
+
+	db, source = newMockDB()
+	source.queryErr = errors.New("no such table")
+	require.NoError(t, db.AutoMigrate(ctx, &renamedContact{}))
+	assert.Contains(t, source.lastStatement().SQL, "CREATE TABLE IF NOT EXISTS `contacts`", "neither name exists: a new table")
+}
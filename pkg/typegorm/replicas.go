@@ -0,0 +1,181 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+)
+
+// --- Read Replicas ---
+
+// replicaSet holds the read replicas of a DB (config: database.replicas). Find,
+// FindFirst and FindByID outside of transactions are spread over the replicas in
+// round-robin; when a replica connection fails, the read is retried on the next
+// replica and finally on the primary (config: database.readAttempts).
+type replicaSet struct {
+	sources   []common.DataSource
+	next      atomic.Uint64
+	attempts  int          // Maximum attempts per read
+	failovers atomic.Int64 // Reads retried elsewhere after a connection failure
+}
+
+// openReplicas connects one DataSource per replica DSN with the primary's dialect and pool.
+func openReplicas(cfg config.DatabaseConfig) (*replicaSet, error) {
+	if len(cfg.Replicas) == 0 {
+		return nil, nil
+	}
+	set := &replicaSet{attempts: cfg.ReadAttempts}
+	for i, dsn := range cfg.Replicas {
+		replicaCfg := cfg
+		replicaCfg.DSN = dsn
+		replicaCfg.Hosts, replicaCfg.Socket, replicaCfg.TargetSessionAttrs = nil, "", "" // The primary's servers
+		replicaCfg.Failover = false
+		ds, err := connectSource(replicaCfg)
+		if err != nil {
+			set.close()
+			return nil, fmt.Errorf("failed to connect replica %d: %w", i+1, err)
+		}
+		set.sources = append(set.sources, ds)
+	}
+	fmt.Printf("Connected %d read replica(s).\n", len(set.sources))
+	return set, nil
+}
+
+func (r *replicaSet) close() error {
+	var errs []error
+	for _, ds := range r.sources {
+		if err := ds.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ReadFailovers returns how many reads were retried on another replica or the primary
+// after a replica connection failure.
+func (db *DB) ReadFailovers() int64 {
+	if db.replicas == nil {
+		return 0
+	}
+	return db.replicas.failovers.Load()
+}
+
+type primaryKey struct{}
+
+// WithPrimary makes reads using ctx go to the primary instead of a replica, e.g. to
+// read back a record right after writing it.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// reader is the part of a DataSource used by read operations.
+type reader interface {
+	Query(ctx context.Context, query string, args ...any) (common.Rows, error)
+	QueryRow(ctx context.Context, query string, args ...any) common.RowScanner
+}
+
+// reader returns where the reads of ctx go: the named pool selected by WithPool, the
+// primary, or the replicas with failover.
+func (db *DB) reader(ctx context.Context) reader {
+	if ds := db.namedPool(ctx); ds != nil {
+		return guardReader(ctx, ds)
+	}
+	if db.replicas == nil || len(db.replicas.sources) == 0 || ctx.Value(primaryKey{}) != nil {
+		return guardReader(ctx, db.primarySource())
+	}
+	return guardReader(ctx, &replicaReader{set: db.replicas, primary: db.primarySource()})
+}
+
+type replicaReader struct {
+	set     *replicaSet
+	primary common.DataSource
+}
+
+// Query runs the query on the next replica. Connection failures are retried on the
+// following replicas, then on the primary; other errors are returned as they are.
+// Rows already handed to the caller are not retried.
+func (r *replicaReader) Query(ctx context.Context, query string, args ...any) (common.Rows, error) {
+	candidates := make([]common.DataSource, 0, len(r.set.sources)+1)
+	start := int(r.set.next.Add(1)-1) % len(r.set.sources)
+	for i := range r.set.sources {
+		candidates = append(candidates, r.set.sources[(start+i)%len(r.set.sources)])
+	}
+	candidates = append(candidates, r.primary)
+	attempts := r.set.attempts
+	if attempts <= 0 || attempts > len(candidates) {
+		attempts = len(candidates)
+	}
+
+	var err error
+	for i := 0; i < attempts; i++ {
+		var rows common.Rows
+		rows, err = candidates[i].Query(ctx, query, args...)
+		if err == nil {
+			return rows, nil
+		}
+		if !isConnectionError(err) || ctx.Err() != nil || i == attempts-1 {
+			break
+		}
+		r.set.failovers.Add(1)
+		target := "next replica"
+		if i+1 == len(candidates)-1 {
+			target = "primary"
+		}
+		fmt.Printf("Warning: read failed on replica (attempt %d/%d), retrying on %s: %v\n", i+1, attempts, target, err)
+	}
+	return nil, err
+}
+
+// QueryRow runs through Query so that connection failures surface (and can be retried)
+// before the row is scanned.
+func (r *replicaReader) QueryRow(ctx context.Context, query string, args ...any) common.RowScanner {
+	rows, err := r.Query(ctx, query, args...)
+	return &firstRowScanner{rows: rows, err: err}
+}
+
+type firstRowScanner struct {
+	rows common.Rows
+	err  error
+}
+
+func (s *firstRowScanner) Scan(dest ...any) error {
+	if s.err != nil {
+		return s.err
+	}
+	defer s.rows.Close()
+	if !s.rows.Next() {
+		if err := s.rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	return s.rows.Scan(dest...)
+}
+
+// isConnectionError reports whether err means the connection itself failed (as
+// opposed to an error in the query), so the query can safely run elsewhere.
+func isConnectionError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, marker := range []string{"invalid connection", "bad connection", "connection refused", "connection reset", "broken pipe", "server has gone away", "lost connection"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
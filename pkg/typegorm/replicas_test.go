@@ -0,0 +1,95 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+)
+
+func newMockDBWithReplicas(n int, attempts int) (*DB, *mockSource, []*mockSource) {
+	db, primary := newMockDB()
+	set := &replicaSet{attempts: attempts}
+	replicas := make([]*mockSource, n)
+	for i := range replicas {
+		replicas[i] = newMockSource()
+		set.sources = append(set.sources, common.DataSource(replicas[i]))
+	}
+	db.replicas = set
+	return db, primary, replicas
+}
+
+func TestReplicaFailover_RetriesOnNextReplica(t *testing.T) {
+	db, primary, replicas := newMockDBWithReplicas(2, 0)
+	replicas[0].queryErr = driver.ErrBadConn
+	replicas[1].queueRows([]string{"id", "name", "email", "age"}, []any{int64(1), "Ana", "ana@x.io", int64(30)})
+
+	var users []maskUser
+	result := db.Find(context.Background(), &users)
+	require.NoError(t, result.Error)
+	require.Len(t, users, 1)
+	assert.Equal(t, "Ana", users[0].Name)
+	assert.Empty(t, primary.Statements())
+	assert.Equal(t, int64(1), db.ReadFailovers())
+}
+
+func TestReplicaFailover_FallsBackToPrimary(t *testing.T) {
+	db, primary, replicas := newMockDBWithReplicas(1, 0)
+	replicas[0].queryErr = fmt.Errorf("read tcp: %w", errors.New("connection reset by peer"))
+	primary.queueRows([]string{"id", "name", "email", "age"}, []any{int64(7), "Bia", "bia@x.io", int64(22)})
+
+	var user maskUser
+	result := db.FindByID(context.Background(), &user, 7)
+	require.NoError(t, result.Error)
+	assert.Equal(t, "Bia", user.Name)
+	assert.Len(t, primary.Statements(), 1)
+}
+
+func TestReplicaFailover_RespectsReadAttempts(t *testing.T) {
+	db, primary, replicas := newMockDBWithReplicas(2, 1)
+	replicas[0].queryErr = driver.ErrBadConn
+	replicas[1].queryErr = driver.ErrBadConn
+
+	var users []maskUser
+	result := db.Find(context.Background(), &users)
+	require.ErrorIs(t, result.Error, driver.ErrBadConn)
+	assert.Empty(t, primary.Statements())
+	assert.Zero(t, db.ReadFailovers())
+}
+
+func TestReplicaFailover_QueryErrorsAreNotRetried(t *testing.T) {
+	db, primary, replicas := newMockDBWithReplicas(1, 0)
+	replicas[0].queryErr = errors.New("Error 1054: Unknown column 'nope'")
+
+	var users []maskUser
+	result := db.Find(context.Background(), &users)
+	require.Error(t, result.Error)
+	assert.Empty(t, primary.Statements())
+	assert.Zero(t, db.ReadFailovers())
+}
+
+func TestReplicas_WithPrimaryAndRoundRobin(t *testing.T) {
+	db, primary, replicas := newMockDBWithReplicas(2, 0)
+
+	var users []maskUser
+	require.NoError(t, db.Find(WithPrimary(context.Background()), &users).Error)
+	assert.Len(t, primary.Statements(), 1)
+
+	require.NoError(t, db.Find(context.Background(), &users).Error)
+	require.NoError(t, db.Find(context.Background(), &users).Error)
+	assert.Len(t, replicas[0].Statements(), 1)
+	assert.Len(t, replicas[1].Statements(), 1)
+}
+
+func TestIsConnectionError(t *testing.T) {
+	assert.True(t, isConnectionError(driver.ErrBadConn))
+	assert.True(t, isConnectionError(errors.New("invalid connection")))
+	assert.True(t, isConnectionError(errors.New("dial tcp: connection refused")))
+	assert.False(t, isConnectionError(errors.New("Error 1062: Duplicate entry")))
+}
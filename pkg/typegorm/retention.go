@@ -0,0 +1,212 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Data Retention ---
+
+// retentionOptions holds the optional behaviors of RunRetention.
+type retentionOptions struct {
+	batchSize int                     // Rows removed per statement
+	archive   bool                    // Copy rows to <table>_archive before deleting them
+	progress  func(RetentionProgress) // Called after every batch
+	now       func() time.Time
+}
+
+// RetentionOption defines a function type that modifies retentionOptions.
+type RetentionOption func(*retentionOptions)
+
+// RetentionBatchSize sets how many expired rows are removed per batch (default 1000),
+// bounding lock time and transaction size.
+func RetentionBatchSize(n int) RetentionOption {
+	return func(opts *retentionOptions) { opts.batchSize = n }
+}
+
+// ArchiveExpired copies expired rows into "<table>_archive" before deleting them, in
+// the same transaction. The archive table must exist with the same columns.
+func ArchiveExpired() RetentionOption {
+	return func(opts *retentionOptions) { opts.archive = true }
+}
+
+// OnRetentionProgress registers a function called after every batch.
+func OnRetentionProgress(fn func(RetentionProgress)) RetentionOption {
+	return func(opts *retentionOptions) { opts.progress = fn }
+}
+
+// RetentionProgress reports the state of a table after a batch.
+type RetentionProgress struct {
+	Table   string
+	Batch   int   // Batches completed so far
+	Removed int64 // Rows removed so far
+}
+
+// RetentionReport summarizes the retention run of one model.
+type RetentionReport struct {
+	Model    string
+	Table    string
+	Cutoff   time.Time // Rows with the retention column before this instant expired
+	Removed  int64     // Rows deleted (and archived, with ArchiveExpired)
+	Batches  int
+	Archived bool
+}
+
+// RunRetention deletes the expired rows of the given models, those whose `retention`
+// column (e.g. `typegorm:"retention:90d"` on CreatedAt) is older than the period.
+// Models, *schema.Model values and RetentionOptions can be mixed:
+//
+//	reports, err := db.RunRetention(ctx, &AuditLog{}, &Session{}, typegorm.ArchiveExpired())
+//
+// Rows are removed in bounded batches by primary key; models without a retention column
+// are skipped. Use WithPool(ctx, "batch") to keep the job off the default pool.
+func (db *DB) RunRetention(ctx context.Context, values ...any) ([]RetentionReport, error) {
+	options := retentionOptions{batchSize: 1000, now: func() time.Time { return clock(ctx, db.now) }}
+	var models []*schema.Model
+	for _, value := range values {
+		switch v := value.(type) {
+		case RetentionOption:
+			v(&options)
+		case *schema.Model:
+			models = append(models, v)
+		default:
+			model, err := db.GetModel(value)
+			if err != nil {
+				return nil, fmt.Errorf("retention: failed to parse schema for type %T: %w", value, err)
+			}
+			models = append(models, model)
+		}
+	}
+	if options.batchSize <= 0 {
+		options.batchSize = 1000
+	}
+
+	var reports []RetentionReport
+	for _, model := range models {
+		if model.RetentionField == nil {
+			fmt.Printf("Retention: %s has no retention column, skipping.\n", model.TableName)
+			continue
+		}
+		report, err := db.runRetention(ctx, model, options)
+		reports = append(reports, report)
+		if err != nil {
+			return reports, err
+		}
+	}
+	return reports, nil
+}
+
+func (db *DB) runRetention(ctx context.Context, model *schema.Model, options retentionOptions) (RetentionReport, error) {
+	report := RetentionReport{
+		Model:    model.Name,
+		Table:    model.TableName,
+		Cutoff:   options.now().Add(-model.RetentionField.Retention),
+		Archived: options.archive,
+	}
+	if len(model.PrimaryKeys) != 1 {
+		return report, fmt.Errorf("retention: %s needs a single-column primary key", model.TableName)
+	}
+	dialect := db.source.Dialect()
+	table := quoteTable(dialect, model)
+	pk := dialect.Quote(model.PrimaryKeys[0].DBName)
+	selectSQL := fmt.Sprintf("SELECT %s FROM %s WHERE %s < %s ORDER BY %s LIMIT %d",
+		pk, table, dialect.Quote(model.RetentionField.DBName), dialect.BindVar(1), pk, options.batchSize)
+	fmt.Printf("Retention: removing rows of %s older than %s\n", model.TableName, report.Cutoff.Format(time.RFC3339))
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		ids, err := db.expiredIDs(ctx, selectSQL, report.Cutoff)
+		if err != nil {
+			return report, fmt.Errorf("retention: failed to select expired rows of %s: %w", model.TableName, err)
+		}
+		if len(ids) == 0 {
+			break
+		}
+		removed, err := db.removeExpired(ctx, model, ids, options.archive)
+		if err != nil {
+			return report, fmt.Errorf("retention: batch %d of %s failed: %w", report.Batches+1, model.TableName, err)
+		}
+		report.Batches++
+		report.Removed += removed
+		fmt.Printf("Retention: %s batch %d removed %d row(s) (%d total)\n", model.TableName, report.Batches, removed, report.Removed)
+		if options.progress != nil {
+			options.progress(RetentionProgress{Table: model.TableName, Batch: report.Batches, Removed: report.Removed})
+		}
+		if len(ids) < options.batchSize {
+			break
+		}
+	}
+	return report, nil
+}
+
+// expiredIDs returns the primary keys of the next batch of expired rows.
+func (db *DB) expiredIDs(ctx context.Context, selectSQL string, cutoff time.Time) ([]any, error) {
+	logSQL("Executing SQL: %s | Args: [%v]\n", selectSQL, cutoff)
+	rows, err := db.conn(ctx).Query(ctx, selectSQL, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []any
+	for rows.Next() {
+		var id any
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// removeExpired deletes one batch, copying it to the archive table first when requested.
+func (db *DB) removeExpired(ctx context.Context, model *schema.Model, ids []any, archive bool) (int64, error) {
+	dialect := db.source.Dialect()
+	table := quoteTable(dialect, model)
+	buf := getStmtBuffer(64 + 4*len(ids))
+	defer putStmtBuffer(buf)
+	buf.WriteString(" WHERE ")
+	buf.WriteQuoted(dialect, model.PrimaryKeys[0].DBName)
+	buf.WriteString(" IN (")
+	for i := range ids {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteBindVar(dialect, i+1)
+	}
+	buf.WriteByte(')')
+	where := buf.String()
+	deleteStmt := "DELETE FROM " + table + where
+
+	if !archive {
+		logSQL("Executing SQL: %s | Args: %v\n", deleteStmt, redactArgs(model, ids))
+		res, err := db.conn(ctx).Exec(ctx, deleteStmt, ids...)
+		if err != nil {
+			return 0, err
+		}
+		return res.RowsAffected()
+	}
+
+	columns, _ := selectColumns(dialect, model)
+	archiveSQL := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s%s",
+		quoteQualified(dialect, model.QualifiedTableName()+"_archive"), columns, columns, table, where)
+	var removed int64
+	err := db.RunInTransaction(ctx, func(tx *Tx) error {
+		logSQL("TX Executing SQL: %s | Args: %v\n", archiveSQL, redactArgs(model, ids))
+		if _, err := tx.source.Exec(ctx, archiveSQL, ids...); err != nil {
+			return fmt.Errorf("failed to archive rows: %w", err)
+		}
+		logSQL("TX Executing SQL: %s | Args: %v\n", deleteStmt, redactArgs(model, ids))
+		res, err := tx.source.Exec(ctx, deleteStmt, ids...)
+		if err != nil {
+			return err
+		}
+		removed, err = res.RowsAffected()
+		return err
+	})
+	return removed, err
+}
@@ -0,0 +1,51 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type auditLog struct {
+	ID        int64     `typegorm:"primaryKey;autoIncrement"`
+	Action    string    `typegorm:"size:50"`
+	CreatedAt time.Time `typegorm:"retention:30d"`
+}
+
+func TestRunRetention_DeletesInBatches(t *testing.T) {
+	db, source := newMockDB()
+	source.queueRows([]string{"id"}, []any{int64(1)}, []any{int64(2)})
+	source.queueRows([]string{"id"}, []any{int64(3)})
+
+	var progress []RetentionProgress
+	reports, err := db.RunRetention(context.Background(), &auditLog{}, &maskUser{},
+		RetentionBatchSize(2), OnRetentionProgress(func(p RetentionProgress) { progress = append(progress, p) }))
+	require.NoError(t, err)
+	require.Len(t, reports, 1) // maskUser has no retention column
+	assert.Equal(t, "audit_logs", reports[0].Table)
+	assert.Equal(t, 2, reports[0].Batches)
+	assert.Equal(t, int64(2), reports[0].Removed) // The mock reports 1 affected row per DELETE
+	assert.WithinDuration(t, time.Now().Add(-30*24*time.Hour), reports[0].Cutoff, time.Minute)
+	assert.Len(t, progress, 2)
+
+	stmts := source.Statements()
+	require.Len(t, stmts, 4)
+	assert.Contains(t, stmts[0].SQL, "WHERE `created_at` < ? ORDER BY `id` LIMIT 2")
+	assert.Equal(t, "DELETE FROM `audit_logs` WHERE `id` IN (?, ?)", stmts[1].SQL)
+	assert.Equal(t, []any{int64(1), int64(2)}, stmts[1].Args)
+	assert.Equal(t, "DELETE FROM `audit_logs` WHERE `id` IN (?)", stmts[3].SQL)
+}
+
+func TestRunRetention_Archive(t *testing.T) {
+	db, source := newMockDB()
+	source.queueRows([]string{"id"}, []any{int64(9)})
+
+	_, err := db.RunRetention(context.Background(), &auditLog{}, ArchiveExpired())
+	require.NoError(t, err)
+	assert.True(t, source.containsStatement("BEGIN"))
+	assert.True(t, source.containsStatement("INSERT INTO `audit_logs_archive` (`id`, `action`, `created_at`) SELECT `id`, `action`, `created_at` FROM `audit_logs` WHERE `id` IN (?)"))
+	assert.True(t, source.containsStatement("COMMIT"))
+}
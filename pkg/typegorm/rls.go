@@ -0,0 +1,205 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// --- Row-Level Security (Postgres) ---
+
+// Well-known session settings read by row-level security policies.
+// Policies usually reference them as current_setting('app.current_tenant').
+const (
+	SettingCurrentUser   = "app.current_user"
+	SettingCurrentTenant = "app.current_tenant"
+)
+
+// PolicyCommand is the statement type a row-level security policy applies to.
+type PolicyCommand string
+
+const (
+	PolicyAll    PolicyCommand = "ALL"
+	PolicySelect PolicyCommand = "SELECT"
+	PolicyInsert PolicyCommand = "INSERT"
+	PolicyUpdate PolicyCommand = "UPDATE"
+	PolicyDelete PolicyCommand = "DELETE"
+)
+
+// RowPolicy describes a CREATE POLICY statement.
+type RowPolicy struct {
+	Name        string        // Policy name (required)
+	Command     PolicyCommand // Defaults to PolicyAll
+	Roles       []string      // Roles the policy applies to; defaults to PUBLIC
+	Using       string        // USING expression, filters visible rows
+	WithCheck   string        // WITH CHECK expression, validates written rows
+	Restrictive bool          // AS RESTRICTIVE instead of the default PERMISSIVE
+}
+
+// EnableRowLevelSecurity turns on row-level security for the model's table.
+// With force set, policies also apply to the table owner.
+func (m *Migrator) EnableRowLevelSecurity(ctx context.Context, model any, force bool) error {
+	if err := m.requirePostgres("row-level security"); err != nil {
+		return err
+	}
+	_, table, err := m.tableFor(model)
+	if err != nil {
+		return err
+	}
+	statements := []string{fmt.Sprintf("ALTER TABLE %s ENABLE ROW LEVEL SECURITY", table)}
+	if force {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s FORCE ROW LEVEL SECURITY", table))
+	}
+	return m.exec(ctx, statements...)
+}
+
+// DisableRowLevelSecurity turns off row-level security for the model's table.
+func (m *Migrator) DisableRowLevelSecurity(ctx context.Context, model any) error {
+	if err := m.requirePostgres("row-level security"); err != nil {
+		return err
+	}
+	_, table, err := m.tableFor(model)
+	if err != nil {
+		return err
+	}
+	return m.exec(ctx,
+		fmt.Sprintf("ALTER TABLE %s NO FORCE ROW LEVEL SECURITY", table),
+		fmt.Sprintf("ALTER TABLE %s DISABLE ROW LEVEL SECURITY", table),
+	)
+}
+
+// CreatePolicy creates a row-level security policy on the model's table.
+func (m *Migrator) CreatePolicy(ctx context.Context, model any, policy RowPolicy) error {
+	if err := m.requirePostgres("row-level security"); err != nil {
+		return err
+	}
+	_, table, err := m.tableFor(model)
+	if err != nil {
+		return err
+	}
+	stmt, err := createPolicySQL(m.db.source.Dialect().Quote, table, policy)
+	if err != nil {
+		return err
+	}
+	return m.exec(ctx, stmt)
+}
+
+// DropPolicy drops a row-level security policy from the model's table, if it exists.
+func (m *Migrator) DropPolicy(ctx context.Context, model any, name string) error {
+	if err := m.requirePostgres("row-level security"); err != nil {
+		return err
+	}
+	_, table, err := m.tableFor(model)
+	if err != nil {
+		return err
+	}
+	return m.exec(ctx, fmt.Sprintf("DROP POLICY IF EXISTS %s ON %s", m.db.source.Dialect().Quote(name), table))
+}
+
+func (m *Migrator) requirePostgres(feature string) error {
+	if m.dialectName() != "postgres" {
+		return fmt.Errorf("%s requires postgres, got %s: %w", feature, m.dialectName(), ErrUnsupportedDialect)
+	}
+	return nil
+}
+
+// createPolicySQL builds the CREATE POLICY statement for an already quoted table.
+func createPolicySQL(quote func(string) string, table string, policy RowPolicy) (string, error) {
+	if policy.Name == "" {
+		return "", fmt.Errorf("policy name is required")
+	}
+	if policy.Using == "" && policy.WithCheck == "" {
+		return "", fmt.Errorf("policy %s needs a USING or WITH CHECK expression", policy.Name)
+	}
+	command := policy.Command
+	if command == "" {
+		command = PolicyAll
+	}
+	if command == PolicyInsert && policy.Using != "" {
+		return "", fmt.Errorf("policy %s: INSERT policies only accept WITH CHECK", policy.Name)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("CREATE POLICY ")
+	sb.WriteString(quote(policy.Name))
+	sb.WriteString(" ON ")
+	sb.WriteString(table)
+	if policy.Restrictive {
+		sb.WriteString(" AS RESTRICTIVE")
+	}
+	sb.WriteString(" FOR ")
+	sb.WriteString(string(command))
+	sb.WriteString(" TO ")
+	if len(policy.Roles) == 0 {
+		sb.WriteString("PUBLIC")
+	} else {
+		roles := make([]string, len(policy.Roles))
+		for i, role := range policy.Roles {
+			roles[i] = quote(role)
+		}
+		sb.WriteString(strings.Join(roles, ", "))
+	}
+	if policy.Using != "" {
+		sb.WriteString(" USING (")
+		sb.WriteString(policy.Using)
+		sb.WriteString(")")
+	}
+	if policy.WithCheck != "" {
+		sb.WriteString(" WITH CHECK (")
+		sb.WriteString(policy.WithCheck)
+		sb.WriteString(")")
+	}
+	return sb.String(), nil
+}
+
+// --- Session settings ---
+
+type sessionSettingsKey struct{}
+
+// WithSessionSetting returns a context carrying a session setting (Postgres GUC) that
+// is applied with set_config(..., true) at the start of every transaction begun with it,
+// so the value is scoped to that transaction. Typical use:
+//
+//	ctx = typegorm.WithSessionSetting(ctx, typegorm.SettingCurrentTenant, tenantID)
+//	tx, err := db.Begin(ctx)
+func WithSessionSetting(ctx context.Context, key, value string) context.Context {
+	current, _ := ctx.Value(sessionSettingsKey{}).(map[string]string)
+	settings := make(map[string]string, len(current)+1)
+	for k, v := range current {
+		settings[k] = v
+	}
+	settings[key] = value
+	return context.WithValue(ctx, sessionSettingsKey{}, settings)
+}
+
+// SessionSettings returns the session settings carried by ctx.
+func SessionSettings(ctx context.Context) map[string]string {
+	settings, _ := ctx.Value(sessionSettingsKey{}).(map[string]string)
+	return settings
+}
+
+// applySessionSettings sets the context's session settings inside a freshly begun transaction.
+func applySessionSettings(ctx context.Context, tx *Tx) error {
+	settings := SessionSettings(ctx)
+	if len(settings) == 0 {
+		return nil
+	}
+	if tx.dialect.Name() != "postgres" {
+		fmt.Printf("Warning: session settings are only applied on postgres, ignoring for %s\n", tx.dialect.Name())
+		return nil
+	}
+	keys := make([]string, 0, len(settings))
+	for k := range settings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys) // Deterministic order
+	for _, k := range keys {
+		query := fmt.Sprintf("SELECT set_config(%s, %s, true)", tx.dialect.BindVar(1), tx.dialect.BindVar(2))
+		var ignored string
+		if err := tx.source.QueryRow(ctx, query, k, settings[k]).Scan(&ignored); err != nil {
+			return fmt.Errorf("failed to apply session setting %s: %w", k, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type rlsDocument struct {
+	ID       uint `typegorm:"primaryKey;autoIncrement"`
+	TenantID string
+}
+
+func TestCreatePolicySQL(t *testing.T) {
+	quote := func(s string) string { return `"` + s + `"` }
+
+	stmt, err := createPolicySQL(quote, `"rls_documents"`, RowPolicy{
+		Name:  "tenant_isolation",
+		Roles: []string{"app_user"},
+		Using: "tenant_id = current_setting('app.current_tenant')",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `CREATE POLICY "tenant_isolation" ON "rls_documents" FOR ALL TO "app_user" USING (tenant_id = current_setting('app.current_tenant'))`, stmt)
+
+	stmt, err = createPolicySQL(quote, `"rls_documents"`, RowPolicy{
+		Name:        "insert_own",
+		Command:     PolicyInsert,
+		WithCheck:   "tenant_id = current_setting('app.current_tenant')",
+		Restrictive: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `CREATE POLICY "insert_own" ON "rls_documents" AS RESTRICTIVE FOR INSERT TO PUBLIC WITH CHECK (tenant_id = current_setting('app.current_tenant'))`, stmt)
+
+	_, err = createPolicySQL(quote, `"rls_documents"`, RowPolicy{Name: "bad", Command: PolicyInsert, Using: "true"})
+	assert.Error(t, err)
+}
+
+func TestMigratorRLS_RequiresPostgres(t *testing.T) {
+	db, _ := newMockDB()
+	err := db.Migrator().EnableRowLevelSecurity(context.Background(), &rlsDocument{}, false)
+	assert.True(t, errors.Is(err, ErrUnsupportedDialect))
+}
+
+func TestMigratorRLS_Postgres(t *testing.T) {
+	db, source := newMockDBWithDialect("postgres")
+	ctx := context.Background()
+
+	require.NoError(t, db.Migrator().EnableRowLevelSecurity(ctx, &rlsDocument{}, true))
+	require.NoError(t, db.Migrator().DropPolicy(ctx, &rlsDocument{}, "tenant_isolation"))
+
+	stmts := source.Statements()
+	require.Len(t, stmts, 3)
+	assert.Equal(t, "ALTER TABLE `rls_documents` ENABLE ROW LEVEL SECURITY", stmts[0].SQL)
+	assert.Equal(t, "ALTER TABLE `rls_documents` FORCE ROW LEVEL SECURITY", stmts[1].SQL)
+	assert.Equal(t, "DROP POLICY IF EXISTS `tenant_isolation` ON `rls_documents`", stmts[2].SQL)
+}
+
+func TestBegin_AppliesSessionSettings(t *testing.T) {
+	db, source := newMockDBWithDialect("postgres")
+	source.queueRows([]string{"set_config"}, []any{"acme"})
+
+	ctx := WithSessionSetting(context.Background(), SettingCurrentTenant, "acme")
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, tx)
+
+	last := source.lastStatement()
+	assert.Equal(t, "SELECT set_config(?, ?, true)", last.SQL)
+	assert.Equal(t, []any{SettingCurrentTenant, "acme"}, last.Args)
+}
@@ -0,0 +1,393 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Stored routines and triggers ---
+
+// RoutineKind tells whether a Routine is a function or a stored procedure.
+type RoutineKind string
+
+const (
+	RoutineFunction  RoutineKind = "FUNCTION"
+	RoutineProcedure RoutineKind = "PROCEDURE"
+)
+
+// Routine describes a stored function or procedure. The body is dialect specific, so
+// Bodies holds one complete body per dialect name ("mysql", "postgres"); creating the
+// routine on a dialect without a body fails with ErrUnsupportedDialect.
+type Routine struct {
+	Name            string
+	Kind            RoutineKind       // Defaults to RoutineFunction
+	Params          string            // Parameter list, e.g. "price DECIMAL(10,2), qty INT"
+	Returns         string            // Return type (functions only)
+	Characteristics string            // E.g. "DETERMINISTIC" (MySQL) or "IMMUTABLE" (Postgres)
+	Language        string            // Postgres only; defaults to plpgsql
+	Bodies          map[string]string // Routine body per dialect name, used verbatim
+}
+
+// TriggerTiming is when a trigger fires relative to the row change.
+type TriggerTiming string
+
+const (
+	TriggerBefore TriggerTiming = "BEFORE"
+	TriggerAfter  TriggerTiming = "AFTER"
+)
+
+// TriggerEvent is the row change that fires a trigger.
+type TriggerEvent string
+
+const (
+	TriggerInsert TriggerEvent = "INSERT"
+	TriggerUpdate TriggerEvent = "UPDATE"
+	TriggerDelete TriggerEvent = "DELETE"
+)
+
+// Trigger describes a row-level trigger. Exactly one of Model or Table must be set.
+// MySQL and SQLite accept a single event per trigger; on Postgres the body becomes a
+// trigger function named "<Name>_fn" that the trigger executes.
+type Trigger struct {
+	Name   string
+	Model  any    // Model value (e.g., &User{}); its table is used
+	Table  string // Raw table name, alternative to Model
+	Timing TriggerTiming
+	Events []TriggerEvent
+	Bodies map[string]string // Trigger body per dialect name, used verbatim
+}
+
+// CreateRoutine creates the function or procedure, replacing an existing definition
+// with the same name.
+func (m *Migrator) CreateRoutine(ctx context.Context, r Routine) error {
+	statements, err := CreateRoutineSQL(m.db.source.Dialect(), r)
+	if err != nil {
+		return err
+	}
+	return m.exec(ctx, statements...)
+}
+
+// DropRoutine drops the function or procedure if it exists.
+func (m *Migrator) DropRoutine(ctx context.Context, r Routine) error {
+	statements, err := DropRoutineSQL(m.db.source.Dialect(), r)
+	if err != nil {
+		return err
+	}
+	return m.exec(ctx, statements...)
+}
+
+// CreateTrigger creates the trigger, replacing an existing one with the same name.
+func (m *Migrator) CreateTrigger(ctx context.Context, t Trigger) error {
+	if err := m.resolveTriggerTable(&t); err != nil {
+		return err
+	}
+	statements, err := CreateTriggerSQL(m.db.source.Dialect(), t)
+	if err != nil {
+		return err
+	}
+	return m.exec(ctx, statements...)
+}
+
+// DropTrigger drops the trigger (and its Postgres trigger function) if it exists.
+func (m *Migrator) DropTrigger(ctx context.Context, t Trigger) error {
+	if err := m.resolveTriggerTable(&t); err != nil {
+		return err
+	}
+	statements, err := DropTriggerSQL(m.db.source.Dialect(), t)
+	if err != nil {
+		return err
+	}
+	return m.exec(ctx, statements...)
+}
+
+func (m *Migrator) resolveTriggerTable(t *Trigger) error {
+	if t.Model == nil {
+		return nil
+	}
+	parsed, err := m.db.GetModel(t.Model)
+	if err != nil {
+		return fmt.Errorf("migrator: failed to parse schema for type %T: %w", t.Model, err)
+	}
+	t.Table = parsed.QualifiedTableName()
+	return nil
+}
+
+// CreateRoutineSQL builds the statements creating (or replacing) a routine. MySQL has no
+// CREATE OR REPLACE, so the routine is dropped first. Useful inside Go migrations,
+// which only receive a *sql.DB.
+func CreateRoutineSQL(dialect common.Dialect, r Routine) ([]string, error) {
+	kind, body, err := routineParts(dialect, r)
+	if err != nil {
+		return nil, err
+	}
+	name := dialect.Quote(r.Name)
+
+	switch dialect.Name() {
+	case "postgres":
+		var b strings.Builder
+		fmt.Fprintf(&b, "CREATE OR REPLACE %s %s(%s)", kind, name, r.Params)
+		if kind == RoutineFunction {
+			fmt.Fprintf(&b, " RETURNS %s", r.Returns)
+		}
+		language := r.Language
+		if language == "" {
+			language = "plpgsql"
+		}
+		fmt.Fprintf(&b, " LANGUAGE %s", language)
+		if r.Characteristics != "" {
+			b.WriteString(" " + r.Characteristics)
+		}
+		b.WriteString(" AS " + dollarQuote(body))
+		return []string{b.String()}, nil
+	default:
+		var b strings.Builder
+		fmt.Fprintf(&b, "CREATE %s %s(%s)", kind, name, r.Params)
+		if kind == RoutineFunction {
+			fmt.Fprintf(&b, " RETURNS %s", r.Returns)
+		}
+		if r.Characteristics != "" {
+			b.WriteString(" " + r.Characteristics)
+		}
+		b.WriteString("\n" + body)
+		return []string{fmt.Sprintf("DROP %s IF EXISTS %s", kind, name), b.String()}, nil
+	}
+}
+
+// DropRoutineSQL builds the statement dropping a routine if it exists.
+func DropRoutineSQL(dialect common.Dialect, r Routine) ([]string, error) {
+	if r.Name == "" {
+		return nil, fmt.Errorf("routine: a name is required")
+	}
+	if !supportsRoutines(dialect.Name()) {
+		return nil, fmt.Errorf("routine: stored routines with %s: %w", dialect.Name(), ErrUnsupportedDialect)
+	}
+	return []string{fmt.Sprintf("DROP %s IF EXISTS %s", routineKind(r), dialect.Quote(r.Name))}, nil
+}
+
+// CreateTriggerSQL builds the statements creating (or replacing) a trigger. The table
+// must be set; Migrator.CreateTrigger resolves it from Model.
+func CreateTriggerSQL(dialect common.Dialect, t Trigger) ([]string, error) {
+	body, err := triggerParts(dialect, t)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]string, len(t.Events))
+	for i, event := range t.Events {
+		events[i] = strings.ToUpper(string(event))
+	}
+	timing := strings.ToUpper(string(t.Timing))
+	name, table := dialect.Quote(t.Name), quoteQualified(dialect, t.Table)
+
+	if dialect.Name() == "postgres" {
+		function := dialect.Quote(t.Name + "_fn")
+		return []string{
+			fmt.Sprintf("CREATE OR REPLACE FUNCTION %s() RETURNS trigger LANGUAGE plpgsql AS %s", function, dollarQuote(body)),
+			fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s", name, table),
+			fmt.Sprintf("CREATE TRIGGER %s %s %s ON %s FOR EACH ROW EXECUTE FUNCTION %s()",
+				name, timing, strings.Join(events, " OR "), table, function),
+		}, nil
+	}
+	return []string{
+		fmt.Sprintf("DROP TRIGGER IF EXISTS %s", name),
+		fmt.Sprintf("CREATE TRIGGER %s %s %s ON %s FOR EACH ROW\n%s", name, timing, events[0], table, body),
+	}, nil
+}
+
+// DropTriggerSQL builds the statements dropping a trigger (and, on Postgres, its
+// trigger function) if it exists.
+func DropTriggerSQL(dialect common.Dialect, t Trigger) ([]string, error) {
+	if t.Name == "" {
+		return nil, fmt.Errorf("trigger: a name is required")
+	}
+	name := dialect.Quote(t.Name)
+	if dialect.Name() == "postgres" {
+		if t.Table == "" {
+			return nil, fmt.Errorf("trigger: a model or table is required")
+		}
+		return []string{
+			fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s", name, quoteQualified(dialect, t.Table)),
+			fmt.Sprintf("DROP FUNCTION IF EXISTS %s()", dialect.Quote(t.Name+"_fn")),
+		}, nil
+	}
+	return []string{fmt.Sprintf("DROP TRIGGER IF EXISTS %s", name)}, nil
+}
+
+func routineKind(r Routine) RoutineKind {
+	if r.Kind == "" {
+		return RoutineFunction
+	}
+	return RoutineKind(strings.ToUpper(string(r.Kind)))
+}
+
+// supportsRoutines reports whether the dialect has stored functions and procedures;
+// SQLite only has triggers.
+func supportsRoutines(dialectName string) bool {
+	return dialectName == "mysql" || dialectName == "postgres"
+}
+
+func routineParts(dialect common.Dialect, r Routine) (RoutineKind, string, error) {
+	if r.Name == "" {
+		return "", "", fmt.Errorf("routine: a name is required")
+	}
+	kind := routineKind(r)
+	switch kind {
+	case RoutineFunction:
+		if r.Returns == "" {
+			return "", "", fmt.Errorf("routine %s: functions require a return type", r.Name)
+		}
+	case RoutineProcedure:
+		if r.Returns != "" {
+			return "", "", fmt.Errorf("routine %s: procedures have no return type", r.Name)
+		}
+	default:
+		return "", "", fmt.Errorf("routine %s: unknown kind %q", r.Name, r.Kind)
+	}
+	if !supportsRoutines(dialect.Name()) {
+		return "", "", fmt.Errorf("routine %s: stored routines with %s: %w", r.Name, dialect.Name(), ErrUnsupportedDialect)
+	}
+	body, ok := r.Bodies[dialect.Name()]
+	if !ok || strings.TrimSpace(body) == "" {
+		return "", "", fmt.Errorf("routine %s: no body for %s: %w", r.Name, dialect.Name(), ErrUnsupportedDialect)
+	}
+	return kind, strings.TrimSpace(body), nil
+}
+
+func triggerParts(dialect common.Dialect, t Trigger) (string, error) {
+	if t.Name == "" {
+		return "", fmt.Errorf("trigger: a name is required")
+	}
+	if t.Table == "" {
+		return "", fmt.Errorf("trigger %s: a model or table is required", t.Name)
+	}
+	switch strings.ToUpper(string(t.Timing)) {
+	case string(TriggerBefore), string(TriggerAfter):
+	default:
+		return "", fmt.Errorf("trigger %s: timing must be BEFORE or AFTER, got %q", t.Name, t.Timing)
+	}
+	if len(t.Events) == 0 {
+		return "", fmt.Errorf("trigger %s: at least one event is required", t.Name)
+	}
+	for _, event := range t.Events {
+		switch strings.ToUpper(string(event)) {
+		case string(TriggerInsert), string(TriggerUpdate), string(TriggerDelete):
+		default:
+			return "", fmt.Errorf("trigger %s: unknown event %q", t.Name, event)
+		}
+	}
+	if len(t.Events) > 1 && dialect.Name() != "postgres" {
+		return "", fmt.Errorf("trigger %s: %s triggers fire on a single event, create one trigger per event", t.Name, dialect.Name())
+	}
+	body, ok := t.Bodies[dialect.Name()]
+	if !ok || strings.TrimSpace(body) == "" {
+		return "", fmt.Errorf("trigger %s: no body for %s: %w", t.Name, dialect.Name(), ErrUnsupportedDialect)
+	}
+	return strings.TrimSpace(body), nil
+}
+
+// dollarQuote wraps a Postgres function body in a dollar-quoted string whose tag does
+// not appear in the body.
+func dollarQuote(body string) string {
+	tag := "$fn$"
+	for i := 1; strings.Contains(body, tag); i++ {
+		tag = fmt.Sprintf("$fn%d$", i)
+	}
+	return tag + "\n" + body + "\n" + tag
+}
+
+// --- Routine migrations ---
+
+// RoutineMigration creates routines and triggers in Up and drops them in Down. It
+// satisfies migration.GoMigration, so the definitions are applied, recorded and rolled
+// back by the migration runner like any other migration:
+//
+//	func init() {
+//		migration.RegisterGoMigration("20250101120000", &typegorm.RoutineMigration{
+//			Dialect:  mysqlDialect,
+//			Routines: []typegorm.Routine{orderTotal},
+//			Triggers: []typegorm.Trigger{auditOrders},
+//		})
+//	}
+//
+// Changing a definition later takes a new migration with the updated Routine/Trigger;
+// creation replaces the previous version.
+type RoutineMigration struct {
+	Dialect  common.Dialect
+	Routines []Routine
+	Triggers []Trigger // Created after the routines, which they may call
+}
+
+// Up creates the routines, then the triggers.
+func (m *RoutineMigration) Up(ctx context.Context, db *sql.DB) error {
+	var statements []string
+	for _, r := range m.Routines {
+		stmts, err := CreateRoutineSQL(m.Dialect, r)
+		if err != nil {
+			return err
+		}
+		statements = append(statements, stmts...)
+	}
+	for _, t := range m.Triggers {
+		if err := resolveTriggerModel(&t); err != nil {
+			return err
+		}
+		stmts, err := CreateTriggerSQL(m.Dialect, t)
+		if err != nil {
+			return err
+		}
+		statements = append(statements, stmts...)
+	}
+	return execRoutineStatements(ctx, db, statements)
+}
+
+// Down drops the triggers, then the routines, in reverse order.
+func (m *RoutineMigration) Down(ctx context.Context, db *sql.DB) error {
+	var statements []string
+	for i := len(m.Triggers) - 1; i >= 0; i-- {
+		t := m.Triggers[i]
+		if err := resolveTriggerModel(&t); err != nil {
+			return err
+		}
+		stmts, err := DropTriggerSQL(m.Dialect, t)
+		if err != nil {
+			return err
+		}
+		statements = append(statements, stmts...)
+	}
+	for i := len(m.Routines) - 1; i >= 0; i-- {
+		stmts, err := DropRoutineSQL(m.Dialect, m.Routines[i])
+		if err != nil {
+			return err
+		}
+		statements = append(statements, stmts...)
+	}
+	return execRoutineStatements(ctx, db, statements)
+}
+
+// resolveTriggerModel fills Table from Model using the default naming strategy; Go
+// migrations have no DB to share its parser with.
+func resolveTriggerModel(t *Trigger) error {
+	if t.Model == nil {
+		return nil
+	}
+	parsed, err := schema.Parse(t.Model)
+	if err != nil {
+		return fmt.Errorf("trigger %s: failed to parse schema for type %T: %w", t.Name, t.Model, err)
+	}
+	t.Table = parsed.QualifiedTableName()
+	return nil
+}
+
+func execRoutineStatements(ctx context.Context, db *sql.DB, statements []string) error {
+	for _, stmt := range statements {
+		logSQL("Executing SQL: %s\n", stmt)
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to execute %q: %w", stmt, err)
+		}
+	}
+	return nil
+}
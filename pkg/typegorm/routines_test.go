@@ -0,0 +1,104 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type triggerOrder struct {
+	ID    uint `typegorm:"primaryKey;autoIncrement"`
+	Total float64
+}
+
+var orderTotalRoutine = Routine{
+	Name:            "order_total",
+	Params:          "price DECIMAL(10,2), qty INT",
+	Returns:         "DECIMAL(10,2)",
+	Characteristics: "DETERMINISTIC",
+	Bodies: map[string]string{
+		"mysql":    "RETURN price * qty;",
+		"postgres": "BEGIN RETURN price * qty; END;",
+	},
+}
+
+func TestMigratorCreateRoutine_MySQL(t *testing.T) {
+	db, source := newMockDBWithDialect("mysql")
+	require.NoError(t, db.Migrator().CreateRoutine(context.Background(), orderTotalRoutine))
+
+	stmts := source.Statements()
+	require.Len(t, stmts, 2)
+	assert.Equal(t, "DROP FUNCTION IF EXISTS `order_total`", stmts[0].SQL)
+	assert.Equal(t, "CREATE FUNCTION `order_total`(price DECIMAL(10,2), qty INT) RETURNS DECIMAL(10,2) DETERMINISTIC\nRETURN price * qty;", stmts[1].SQL)
+}
+
+func TestCreateRoutineSQL_Postgres(t *testing.T) {
+	dialect := &mockDialect{name: "postgres"}
+	stmts, err := CreateRoutineSQL(dialect, orderTotalRoutine)
+	require.NoError(t, err)
+	require.Len(t, stmts, 1)
+	assert.Equal(t, "CREATE OR REPLACE FUNCTION `order_total`(price DECIMAL(10,2), qty INT) RETURNS DECIMAL(10,2) LANGUAGE plpgsql DETERMINISTIC AS $fn$\nBEGIN RETURN price * qty; END;\n$fn$", stmts[0])
+
+	proc := Routine{Name: "archive", Kind: RoutineProcedure, Bodies: map[string]string{"postgres": "BEGIN END;"}}
+	stmts, err = CreateRoutineSQL(dialect, proc)
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE OR REPLACE PROCEDURE `archive`() LANGUAGE plpgsql AS $fn$\nBEGIN END;\n$fn$", stmts[0])
+
+	stmts, err = DropRoutineSQL(dialect, proc)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DROP PROCEDURE IF EXISTS `archive`"}, stmts)
+}
+
+func TestCreateRoutineSQL_Errors(t *testing.T) {
+	_, err := CreateRoutineSQL(&mockDialect{name: "sqlite"}, orderTotalRoutine)
+	assert.True(t, errors.Is(err, ErrUnsupportedDialect))
+
+	_, err = CreateRoutineSQL(&mockDialect{name: "mysql"}, Routine{Name: "f", Returns: "INT", Bodies: map[string]string{"postgres": "x"}})
+	assert.True(t, errors.Is(err, ErrUnsupportedDialect), "missing body for the dialect")
+
+	_, err = CreateRoutineSQL(&mockDialect{name: "mysql"}, Routine{Name: "f", Bodies: map[string]string{"mysql": "RETURN 1;"}})
+	assert.Error(t, err, "functions need a return type")
+}
+
+func TestMigratorCreateTrigger(t *testing.T) {
+	trigger := Trigger{
+		Name:   "orders_touch",
+		Model:  &triggerOrder{},
+		Timing: TriggerBefore,
+		Events: []TriggerEvent{TriggerUpdate},
+		Bodies: map[string]string{
+			"mysql":    "SET NEW.total = ROUND(NEW.total, 2)",
+			"postgres": "BEGIN NEW.total := round(NEW.total, 2); RETURN NEW; END;",
+		},
+	}
+
+	db, source := newMockDBWithDialect("mysql")
+	require.NoError(t, db.Migrator().CreateTrigger(context.Background(), trigger))
+	stmts := source.Statements()
+	require.Len(t, stmts, 2)
+	assert.Equal(t, "DROP TRIGGER IF EXISTS `orders_touch`", stmts[0].SQL)
+	assert.Equal(t, "CREATE TRIGGER `orders_touch` BEFORE UPDATE ON `trigger_orders` FOR EACH ROW\nSET NEW.total = ROUND(NEW.total, 2)", stmts[1].SQL)
+
+	trigger.Events = []TriggerEvent{TriggerInsert, TriggerUpdate}
+	assert.Error(t, db.Migrator().CreateTrigger(context.Background(), trigger), "mysql triggers take one event")
+
+	db, source = newMockDBWithDialect("postgres")
+	require.NoError(t, db.Migrator().CreateTrigger(context.Background(), trigger))
+	stmts = source.Statements()
+	require.Len(t, stmts, 3)
+	assert.Equal(t, "CREATE OR REPLACE FUNCTION `orders_touch_fn`() RETURNS trigger LANGUAGE plpgsql AS $fn$\nBEGIN NEW.total := round(NEW.total, 2); RETURN NEW; END;\n$fn$", stmts[0].SQL)
+	assert.Equal(t, "DROP TRIGGER IF EXISTS `orders_touch` ON `trigger_orders`", stmts[1].SQL)
+	assert.Equal(t, "CREATE TRIGGER `orders_touch` BEFORE INSERT OR UPDATE ON `trigger_orders` FOR EACH ROW EXECUTE FUNCTION `orders_touch_fn`()", stmts[2].SQL)
+
+	require.NoError(t, db.Migrator().DropTrigger(context.Background(), trigger))
+	stmts = source.Statements()
+	assert.Equal(t, "DROP TRIGGER IF EXISTS `orders_touch` ON `trigger_orders`", stmts[3].SQL)
+	assert.Equal(t, "DROP FUNCTION IF EXISTS `orders_touch_fn`()", stmts[4].SQL)
+}
+
+func TestDollarQuote_AvoidsBodyTag(t *testing.T) {
+	assert.Equal(t, "$fn1$\nSELECT '$fn$'\n$fn1$", dollarQuote("SELECT '$fn$'"))
+}
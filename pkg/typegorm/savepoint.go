@@ -0,0 +1,98 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+)
+
+// --- Nested Transactions (Savepoints) ---
+
+type txContextKey struct{}
+
+// ContextWithTx returns a context carrying tx, so that Transaction and RunInTransaction
+// calls made with it nest inside tx instead of starting a second transaction.
+func ContextWithTx(ctx context.Context, tx *Tx) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+// TxFromContext returns the transaction carried by ctx, if any.
+func TxFromContext(ctx context.Context) (*Tx, bool) {
+	tx, ok := ctx.Value(txContextKey{}).(*Tx)
+	return tx, ok && tx != nil
+}
+
+// Transaction runs fn in a transaction, committing when it returns nil and rolling back
+// when it returns an error or panics (see RunInTransaction for the options). fn gets a
+// context carrying the transaction; when Transaction is called again with that context,
+// the inner fn runs in a savepoint of the same transaction: its error rolls back only
+// the inner work and is returned to the outer fn, which decides whether to continue:
+//
+//	err := db.Transaction(ctx, func(ctx context.Context, tx *typegorm.Tx) error {
+//		tx.Create(ctx, &order)
+//		_ = db.Transaction(ctx, func(ctx context.Context, tx *typegorm.Tx) error {
+//			return tx.Create(ctx, &optionalAudit).Error // Rolled back alone on failure
+//		})
+//		return nil
+//	})
+func (db *DB) Transaction(ctx context.Context, fn func(ctx context.Context, tx *Tx) error, opts ...TxOption) error {
+	if outer, ok := TxFromContext(ctx); ok {
+		if len(opts) > 0 {
+			fmt.Println("Note: transaction options are ignored for a nested transaction (savepoint)")
+		}
+		return outer.Transaction(ctx, fn)
+	}
+	return db.RunInTransaction(ctx, func(tx *Tx) error {
+		return fn(ContextWithTx(ctx, tx), tx)
+	}, opts...)
+}
+
+// Transaction runs fn in a savepoint of the transaction: SAVEPOINT before fn, RELEASE
+// SAVEPOINT when it returns nil, ROLLBACK TO SAVEPOINT when it returns an error or
+// panics. AfterCommit/AfterRollback functions registered by a rolled-back fn are dropped.
+func (tx *Tx) Transaction(ctx context.Context, fn func(ctx context.Context, tx *Tx) error) (err error) {
+	name := fmt.Sprintf("typegorm_sp%d", tx.savepoints.Add(1))
+	create, release, rollback := savepointSQL(tx.dialect.Name(), name)
+
+	logSQL("Executing SQL: %s\n", create)
+	if _, err := tx.source.Exec(ctx, create); err != nil {
+		return fmt.Errorf("tx: failed to create savepoint: %w", err)
+	}
+	commitHooks, rollbackHooks := len(tx.afterCommit), len(tx.afterRollback)
+	undo := func() error {
+		logSQL("Executing SQL: %s\n", rollback)
+		tx.afterCommit, tx.afterRollback = tx.afterCommit[:commitHooks], tx.afterRollback[:rollbackHooks]
+		if _, err := tx.source.Exec(ctx, rollback); err != nil {
+			return fmt.Errorf("tx: failed to roll back to savepoint: %w", err)
+		}
+		return nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			_ = undo()
+			panic(r)
+		}
+	}()
+	if err = fn(ContextWithTx(ctx, tx), tx); err != nil {
+		if undoErr := undo(); undoErr != nil {
+			return fmt.Errorf("%w (%v)", err, undoErr)
+		}
+		return err
+	}
+	if release != "" {
+		logSQL("Executing SQL: %s\n", release)
+		if _, err := tx.source.Exec(ctx, release); err != nil {
+			return fmt.Errorf("tx: failed to release savepoint: %w", err)
+		}
+	}
+	return nil
+}
+
+// savepointSQL returns the statements creating, releasing and rolling back to a
+// savepoint. SQL Server has no RELEASE; its savepoints end with the transaction.
+func savepointSQL(dialect, name string) (create, release, rollback string) {
+	if dialect == "sqlserver" || dialect == "mssql" {
+		return "SAVE TRANSACTION " + name, "", "ROLLBACK TRANSACTION " + name
+	}
+	return "SAVEPOINT " + name, "RELEASE SAVEPOINT " + name, "ROLLBACK TO SAVEPOINT " + name
+}
@@ -0,0 +1,54 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransaction_NestedUsesSavepoints(t *testing.T) {
+	db, source := newMockDBWithDialect("mysql")
+	ctx := context.Background()
+	innerErr := errors.New("optional step failed")
+
+	var committed []string
+	err := db.Transaction(ctx, func(ctx context.Context, outer *Tx) error {
+		require.NoError(t, db.Transaction(ctx, func(ctx context.Context, tx *Tx) error {
+			assert.Same(t, outer, tx)
+			tx.AfterCommit(func() { committed = append(committed, "kept") })
+			return nil
+		}))
+		err := db.RunInTransaction(ctx, func(tx *Tx) error {
+			tx.AfterCommit(func() { committed = append(committed, "dropped") })
+			return innerErr
+		})
+		assert.ErrorIs(t, err, innerErr)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"BEGIN",
+		"SAVEPOINT typegorm_sp1",
+		"RELEASE SAVEPOINT typegorm_sp1",
+		"SAVEPOINT typegorm_sp2",
+		"ROLLBACK TO SAVEPOINT typegorm_sp2",
+		"COMMIT",
+	}, sqlOf(source.Statements()))
+	assert.Equal(t, []string{"kept"}, committed)
+}
+
+func TestTxTransaction_RollsBackOnPanic(t *testing.T) {
+	db, source := newMockDBWithDialect("sqlserver")
+	tx, err := db.Begin(context.Background())
+	require.NoError(t, err)
+
+	assert.Panics(t, func() {
+		_ = tx.Transaction(context.Background(), func(ctx context.Context, tx *Tx) error { panic("boom") })
+	})
+	assert.Equal(t, "ROLLBACK TRANSACTION typegorm_sp1", source.lastStatement().SQL)
+	assert.True(t, source.containsStatement("SAVE TRANSACTION typegorm_sp1"))
+}
@@ -0,0 +1,120 @@
+package typegorm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Row Scanning ---
+
+// scanPlan caches how rows of one struct type and column list are scanned: the index
+// path of each destination field (instead of a FieldByName lookup per field and row)
+// and a pool of destination slices reused across queries.
+type scanPlan struct {
+	indexes [][]int
+	buffers sync.Pool // *[]any with len(indexes)
+}
+
+type scanPlanKey struct {
+	structType reflect.Type
+	columns    string
+}
+
+var scanPlans sync.Map // scanPlanKey -> *scanPlan
+
+// getScanPlan returns the cached plan for scanning fields into structType.
+func getScanPlan(structType reflect.Type, fields []*schema.Field) (*scanPlan, error) {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.GoName
+	}
+	key := scanPlanKey{structType: structType, columns: strings.Join(names, ",")}
+	if cached, ok := scanPlans.Load(key); ok {
+		return cached.(*scanPlan), nil
+	}
+
+	plan := &scanPlan{indexes: make([][]int, len(fields))}
+	for i, field := range fields {
+		structField, ok := structType.FieldByName(field.GoName)
+		if !ok {
+			return nil, fmt.Errorf("internal error: struct field %s not found in %s", field.GoName, structType.Name())
+		}
+		plan.indexes[i] = structField.Index
+	}
+	size := len(fields)
+	plan.buffers.New = func() any {
+		buf := make([]any, size)
+		return &buf
+	}
+	cached, _ := scanPlans.LoadOrStore(key, plan)
+	return cached.(*scanPlan), nil
+}
+
+// acquire returns a destination slice from the pool; give it back with release.
+func (p *scanPlan) acquire() *[]any {
+	return p.buffers.Get().(*[]any)
+}
+
+func (p *scanPlan) release(buf *[]any) {
+	clear(*buf) // Do not keep the scanned structs alive through the pool
+	p.buffers.Put(buf)
+}
+
+// bind points dest at the fields of elem (an addressable struct value).
+func (p *scanPlan) bind(elem reflect.Value, dest []any) {
+	for i, index := range p.indexes {
+		dest[i] = elem.FieldByIndex(index).Addr().Interface()
+	}
+}
+
+// scanInto scans all rows into sliceValue (a slice of structs or struct pointers) and
+// returns the number of rows. Every row is scanned into a fresh zero struct bound for
+// that row (for slices of structs, the new last element of the slice), so that maps,
+// slices and pointers filled by a Scanner are never shared between rows, and a Scanner
+// that ignores NULL does not keep the previous row's value. The pooled destination
+// slice is shared.
+func scanInto(rows interface {
+	Next() bool
+	Scan(dest ...any) error
+}, sliceValue reflect.Value, structType reflect.Type, elementIsPointer bool, plan *scanPlan, capacity int) (int, error) {
+	buf := plan.acquire()
+	defer plan.release(buf)
+	dest := *buf
+
+	sliceValue.Set(reflect.MakeSlice(sliceValue.Type(), 0, capacity))
+	rowCount := 0
+	for rows.Next() {
+		rowCount++
+		var elem reflect.Value
+		if elementIsPointer {
+			elem = reflect.New(structType).Elem()
+		} else {
+			sliceValue.Set(reflect.Append(sliceValue, reflect.Zero(structType)))
+			elem = sliceValue.Index(sliceValue.Len() - 1)
+		}
+		plan.bind(elem, dest)
+		if err := rows.Scan(dest...); err != nil {
+			if !elementIsPointer {
+				sliceValue.SetLen(sliceValue.Len() - 1)
+			}
+			return rowCount, err
+		}
+		if elementIsPointer {
+			sliceValue.Set(reflect.Append(sliceValue, elem.Addr()))
+		}
+	}
+	return rowCount, nil
+}
+
+// scanCapacity is the initial capacity of the result slice: the LIMIT when it is
+// small enough to preallocate, otherwise 0 (the slice grows as rows arrive).
+func scanCapacity(limit int) int {
+	if limit > 0 && limit <= 1024 {
+		return limit
+	}
+	return 0
+}
@@ -0,0 +1,97 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind_ReusedScanBuffersKeepRowsDistinct(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	cols := []string{"id", "name", "email", "age"}
+
+	source.queueRows(cols, []any{1, "Ana", "ana@x", 30}, []any{2, "Bia", nil, 25})
+	var values []maskUser
+	require.NoError(t, db.Find(ctx, &values).Error)
+	assert.Equal(t, []maskUser{{ID: 1, Name: "Ana", Email: "ana@x", Age: 30}, {ID: 2, Name: "Bia", Age: 25}}, values)
+
+	source.queueRows(cols, []any{3, "Eva", "eva@x", 41}, []any{4, "Ivo", "ivo@x", 19})
+	var pointers []*maskUser
+	require.NoError(t, db.Find(ctx, &pointers).Error)
+	require.Len(t, pointers, 2)
+	assert.NotSame(t, pointers[0], pointers[1])
+	assert.Equal(t, "Eva", pointers[0].Name)
+	assert.Equal(t, "Ivo", pointers[1].Name)
+}
+
+// scanTags is a Scanner that adds to the set it already holds and ignores NULL.
+type scanTags map[string]bool
+
+func (t *scanTags) Scan(value any) error {
+	if value == nil {
+		return nil
+	}
+	if *t == nil {
+		*t = scanTags{}
+	}
+	(*t)[value.(string)] = true
+	return nil
+}
+
+type taggedNote struct {
+	ID   uint `typegorm:"primaryKey"`
+	Tags scanTags
+}
+
+func TestFind_ScannerFieldsAreNotSharedBetweenRows(t *testing.T) {
+	db, source := newMockDB()
+	source.queueRows([]string{"id", "tags"}, []any{1, "red"}, []any{2, "blue"}, []any{3, nil})
+
+	var notes []taggedNote
+	require.NoError(t, db.Find(context.Background(), &notes).Error)
+	require.Len(t, notes, 3)
+	assert.Equal(t, scanTags{"red": true}, notes[0].Tags)
+	assert.Equal(t, scanTags{"blue": true}, notes[1].Tags, "each row gets its own map")
+	assert.Nil(t, notes[2].Tags, "NULL does not keep the previous row's value")
+}
+
+func TestScanPlan_Cached(t *testing.T) {
+	db, _ := newMockDB()
+	model, err := db.GetModel(&maskUser{})
+	require.NoError(t, err)
+
+	first, err := getScanPlan(model.Type, model.Fields)
+	require.NoError(t, err)
+	second, err := getScanPlan(model.Type, model.Fields)
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	other, err := getScanPlan(model.Type, model.Fields[:2])
+	require.NoError(t, err)
+	assert.NotSame(t, first, other, "plans are per column list")
+
+	buf := first.acquire()
+	assert.Len(t, *buf, len(model.Fields))
+	first.release(buf)
+}
+
+func BenchmarkFind_1000Rows(b *testing.B) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	cols := []string{"id", "name", "email", "age"}
+	rows := make([][]any, 1000)
+	for i := range rows {
+		rows[i] = []any{i + 1, "name", "mail", i}
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		source.queueRows(cols, rows...)
+		var users []maskUser
+		if res := db.Find(ctx, &users); res.Error != nil {
+			b.Fatal(res.Error)
+		}
+	}
+}
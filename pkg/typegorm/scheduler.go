@@ -0,0 +1,316 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// --- Scheduled Tasks ---
+
+// A Scheduler runs Go functions on cron schedules in one process of a deployment: the
+// schedulers of all the processes elect a leader through a lock in typegorm_locks (see
+// LockRecord), and only the leader runs the tasks. The state of the tasks (next run,
+// last error) and their run history are stored in typegorm_scheduled_tasks and
+// typegorm_task_runs (create them with db.AutoMigrate(ctx, &typegorm.ScheduledTask{},
+// &typegorm.TaskRun{})), so that a new leader picks up where the previous one stopped.
+
+// ScheduledTask is the state of a task registered with a Scheduler.
+type ScheduledTask struct {
+	_         struct{}   `typegorm:"table:typegorm_scheduled_tasks"`
+	Name      string     `typegorm:"primaryKey;size:128"`
+	Schedule  string     `typegorm:"size:128;not null"` // Cron expression
+	NextRunAt time.Time  `typegorm:"not null"`
+	LastRunAt *time.Time // Start of the last run
+	LastError string     `typegorm:"size:1024"` // Empty when the last run succeeded
+}
+
+// TaskRun is a row of the run history of the scheduled tasks.
+type TaskRun struct {
+	_          struct{}  `typegorm:"table:typegorm_task_runs"`
+	ID         int64     `typegorm:"primaryKey;autoIncrement"`
+	Task       string    `typegorm:"size:128;not null;index:idx_typegorm_task_runs_task"`
+	StartedAt  time.Time `typegorm:"not null"`
+	FinishedAt time.Time `typegorm:"not null"`
+	Error      string    `typegorm:"size:1024"`
+	Runner     string    `typegorm:"size:128"` // Leader that ran the task
+}
+
+// TaskFunc is the function of a scheduled task. Its context is canceled when the
+// scheduler stops or loses the leadership.
+type TaskFunc func(ctx context.Context) error
+
+// schedulerOptions holds the optional behaviors of a Scheduler.
+type schedulerOptions struct {
+	name     string
+	poll     time.Duration
+	leaseTTL time.Duration
+	location *time.Location
+}
+
+// SchedulerOption defines a function type that modifies schedulerOptions.
+type SchedulerOption func(*schedulerOptions)
+
+// SchedulerName sets the name of the leader election (default "default"): schedulers
+// with different names, e.g. of different services, each have a leader.
+func SchedulerName(name string) SchedulerOption {
+	return func(opts *schedulerOptions) { opts.name = name }
+}
+
+// SchedulerPollInterval sets how often the scheduler checks for due tasks and tries to
+// become the leader (default 10s).
+func SchedulerPollInterval(d time.Duration) SchedulerOption {
+	return func(opts *schedulerOptions) { opts.poll = d }
+}
+
+// SchedulerLeaseTTL sets how long the leadership outlives a leader that stopped
+// renewing it, e.g. after a crash (default 30s).
+func SchedulerLeaseTTL(d time.Duration) SchedulerOption {
+	return func(opts *schedulerOptions) { opts.leaseTTL = d }
+}
+
+// SchedulerLocation sets the time zone of the cron expressions (default time.Local).
+func SchedulerLocation(loc *time.Location) SchedulerOption {
+	return func(opts *schedulerOptions) { opts.location = loc }
+}
+
+// scheduledFunc is a task registered with a Scheduler.
+type scheduledFunc struct {
+	name     string
+	schedule *CronSchedule
+	fn       TaskFunc
+}
+
+// Scheduler runs registered tasks on their schedules while it is the leader.
+type Scheduler struct {
+	db   *DB
+	opts schedulerOptions
+
+	mu    sync.Mutex
+	tasks []*scheduledFunc
+	lease *RecordLock // Held while leader
+}
+
+// NewScheduler returns a scheduler; register its tasks, then start it with Run:
+//
+//	scheduler := db.NewScheduler()
+//	scheduler.Register("purge-sessions", "*/15 * * * *", func(ctx context.Context) error {
+//		return db.Model(&Session{}).Where(map[string]any{"expires_at <": time.Now()}).Delete(ctx).Error
+//	})
+//	go scheduler.Run(ctx)
+func (db *DB) NewScheduler(opts ...SchedulerOption) *Scheduler {
+	options := schedulerOptions{name: "default", poll: 10 * time.Second, leaseTTL: 30 * time.Second, location: time.Local}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	return &Scheduler{db: db, opts: options}
+}
+
+// Register adds a task running fn on the cron expression (see ParseCron). Names are
+// unique: they identify the task in the tables.
+func (s *Scheduler) Register(name, cron string, fn TaskFunc) error {
+	schedule, err := ParseCron(cron)
+	if err != nil {
+		return fmt.Errorf("register task %s: %w", name, err)
+	}
+	if schedule.Next(time.Now().In(s.opts.location)).IsZero() {
+		return fmt.Errorf("register task %s: cron %q never matches", name, cron)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, task := range s.tasks {
+		if task.name == name {
+			return fmt.Errorf("register task %s: already registered", name)
+		}
+	}
+	s.tasks = append(s.tasks, &scheduledFunc{name: name, schedule: schedule, fn: fn})
+	return nil
+}
+
+// Run checks for due tasks every poll interval until ctx is canceled, then gives the
+// leadership up.
+func (s *Scheduler) Run(ctx context.Context) error {
+	fmt.Printf("Scheduler %s started.\n", s.opts.name)
+	defer func() {
+		if err := s.Resign(context.WithoutCancel(ctx)); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+		fmt.Printf("Scheduler %s stopped.\n", s.opts.name)
+	}()
+	for {
+		if _, err := s.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			fmt.Printf("Warning: scheduler %s: %v\n", s.opts.name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(s.opts.poll):
+		}
+	}
+}
+
+// RunOnce becomes the leader if the leadership is free, then runs the due tasks one
+// after the other if it is the leader; it returns the number of tasks run. Errors are
+// those of the tables, not of the tasks (recorded in their history).
+func (s *Scheduler) RunOnce(ctx context.Context) (int, error) {
+	lease, err := s.leadership(ctx)
+	if err != nil || lease == nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	tasks := append([]*scheduledFunc(nil), s.tasks...)
+	s.mu.Unlock()
+
+	ran := 0
+	for _, task := range tasks {
+		due, err := s.claim(ctx, task)
+		if err != nil {
+			return ran, err
+		}
+		if !due {
+			continue
+		}
+		if err := s.run(ctx, lease, task); err != nil {
+			return ran, err
+		}
+		ran++
+	}
+	return ran, nil
+}
+
+// IsLeader reports whether the scheduler holds the leadership.
+func (s *Scheduler) IsLeader() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lease != nil && s.lease.Err() == nil
+}
+
+// Resign gives the leadership up, if held, so that another scheduler takes it over at
+// its next poll.
+func (s *Scheduler) Resign(ctx context.Context) error {
+	s.mu.Lock()
+	lease := s.lease
+	s.lease = nil
+	s.mu.Unlock()
+	if lease == nil {
+		return nil
+	}
+	return lease.Release(ctx)
+}
+
+// leadership returns the lease of the leader, taking it if it is free; nil when
+// another scheduler is the leader.
+func (s *Scheduler) leadership(ctx context.Context) (*RecordLock, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lease != nil {
+		if s.lease.Err() == nil {
+			return s.lease, nil
+		}
+		fmt.Printf("Warning: scheduler %s lost the leadership: %v\n", s.opts.name, s.lease.Err())
+		s.lease = nil
+	}
+	lease, err := s.db.acquireLock(ctx, "typegorm_scheduler:"+s.opts.name, s.opts.leaseTTL)
+	if errors.Is(err, ErrRecordLocked) {
+		return nil, nil // Another scheduler leads
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scheduler %s: %w", s.opts.name, err)
+	}
+	fmt.Printf("Scheduler %s is the leader (%s).\n", s.opts.name, lease.Owner)
+	s.lease = lease
+	return lease, nil
+}
+
+// claim reports whether task is due, and if so moves its next run to the following
+// occurrence. The update only applies if the row did not change since it was read,
+// so that an occurrence runs once even if two schedulers believe they lead.
+func (s *Scheduler) claim(ctx context.Context, task *scheduledFunc) (bool, error) {
+	now := clock(ctx, s.db.now).In(s.opts.location)
+	var state ScheduledTask
+	err := s.db.FindFirst(ctx, &state, map[string]any{"name": task.name}).Error
+	if errors.Is(err, sql.ErrNoRows) {
+		state = ScheduledTask{Name: task.name, Schedule: task.schedule.String(), NextRunAt: task.schedule.Next(now)}
+		if err := s.db.Create(ctx, &state).Error; err != nil {
+			return false, fmt.Errorf("task %s: %w", task.name, err)
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("task %s: %w", task.name, err)
+	}
+
+	if state.Schedule != task.schedule.String() {
+		// Rescheduled: the next run follows the new expression
+		result := s.db.Model(&ScheduledTask{}).Where(map[string]any{"name": task.name}).
+			Updates(ctx, map[string]any{"schedule": task.schedule.String(), "next_run_at": task.schedule.Next(now)})
+		if result.Error != nil {
+			return false, fmt.Errorf("task %s: %w", task.name, result.Error)
+		}
+		return false, nil
+	}
+	if state.NextRunAt.After(now) {
+		return false, nil
+	}
+	result := s.db.Model(&ScheduledTask{}).Where(map[string]any{"name": task.name, "next_run_at": state.NextRunAt}).
+		Update(ctx, "next_run_at", task.schedule.Next(now))
+	if result.Error != nil {
+		return false, fmt.Errorf("task %s: %w", task.name, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// run runs task and records the outcome; the task is canceled if the leadership is lost.
+func (s *Scheduler) run(ctx context.Context, lease *RecordLock, task *scheduledFunc) error {
+	taskCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-lease.Lost():
+			cancel()
+		case <-taskCtx.Done():
+		}
+	}()
+
+	started := clock(ctx, s.db.now)
+	fmt.Printf("Running scheduled task %s.\n", task.name)
+	err := runTask(taskCtx, task.fn)
+	finished := clock(ctx, s.db.now)
+	if err != nil {
+		fmt.Printf("Warning: scheduled task %s failed: %v\n", task.name, err)
+	}
+
+	ctx = context.WithoutCancel(ctx) // Record the outcome even when the scheduler is stopping
+	lastError := ""
+	if err != nil {
+		lastError = err.Error()
+	}
+	if len(lastError) > 1024 {
+		lastError = lastError[:1024]
+	}
+	result := s.db.Model(&ScheduledTask{}).Where(map[string]any{"name": task.name}).
+		Updates(ctx, map[string]any{"last_run_at": started, "last_error": lastError})
+	if result.Error != nil {
+		return fmt.Errorf("task %s: %w", task.name, result.Error)
+	}
+	history := TaskRun{Task: task.name, StartedAt: started, FinishedAt: finished, Error: lastError, Runner: lease.Owner}
+	if err := s.db.Create(ctx, &history).Error; err != nil {
+		return fmt.Errorf("task %s: recording the run: %w", task.name, err)
+	}
+	return nil
+}
+
+// runTask calls fn, turning a panic into an error.
+func runTask(ctx context.Context, fn TaskFunc) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task panicked: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
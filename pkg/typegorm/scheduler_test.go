@@ -0,0 +1,84 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var scheduledTaskColumns = []string{"name", "schedule", "next_run_at", "last_run_at", "last_error"}
+
+func TestScheduler_LeaderRunsDueTasks(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	frozen := time.Date(2026, 1, 1, 12, 2, 0, 0, time.UTC)
+	db.UseNowFunc(func() time.Time { return frozen })
+
+	scheduler := db.NewScheduler(SchedulerLocation(time.UTC))
+	runs := 0
+	require.NoError(t, scheduler.Register("purge", "*/5 * * * *", func(ctx context.Context) error {
+		runs++
+		return errors.New("boom")
+	}))
+	assert.ErrorContains(t, scheduler.Register("purge", "@daily", nil), "already registered")
+	assert.ErrorContains(t, scheduler.Register("never", "0 0 31 2 *", nil), "never matches")
+
+	ran, err := scheduler.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, ran, "New task: scheduled, not run")
+	assert.True(t, scheduler.IsLeader())
+	statements := source.Statements()
+	insert := statements[len(statements)-2] // Followed by the re-fetch of Create
+	assert.Contains(t, insert.SQL, "INSERT INTO `typegorm_scheduled_tasks`")
+	assert.Contains(t, insert.Args, time.Date(2026, 1, 1, 12, 5, 0, 0, time.UTC))
+
+	count := len(source.Statements())
+	source.queueRows(scheduledTaskColumns, []any{"purge", "*/5 * * * *", time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC), nil, ""})
+	ran, err = scheduler.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, ran)
+	assert.Equal(t, 1, runs)
+
+	statements = source.Statements()[count:]
+	require.Len(t, statements, 5, "Read, claim, outcome, history (and its re-fetch): the leadership is kept")
+	assert.Contains(t, statements[1].SQL, "UPDATE `typegorm_scheduled_tasks` SET `next_run_at` = ? WHERE")
+	assert.Contains(t, statements[1].SQL, "`next_run_at` = ?")
+	assert.Contains(t, statements[2].Args, "boom", "Last error recorded")
+	assert.Contains(t, statements[3].SQL, "INSERT INTO `typegorm_task_runs`")
+
+	require.NoError(t, scheduler.Resign(ctx))
+	assert.False(t, scheduler.IsLeader())
+	assert.True(t, strings.HasPrefix(source.lastStatement().SQL, "DELETE FROM `typegorm_locks`"))
+}
+
+func TestScheduler_FollowerAndRescheduling(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	scheduler := db.NewScheduler(SchedulerName("billing"))
+	require.NoError(t, scheduler.Register("invoice", "0 * * * *", func(ctx context.Context) error {
+		t.Fatal("not due")
+		return nil
+	}))
+
+	source.execErrs = []error{nil, nil, errors.New("Error 1062 (23000): Duplicate entry 'typegorm_scheduler:billing' for key 'PRIMARY'")}
+	ran, err := scheduler.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, ran)
+	assert.False(t, scheduler.IsLeader(), "Another scheduler leads")
+	for _, stmt := range source.Statements() {
+		assert.NotContains(t, stmt.SQL, "typegorm_scheduled_tasks")
+	}
+
+	source.queueRows(scheduledTaskColumns, []any{"invoice", "0 0 * * *", time.Now().Add(-time.Minute), nil, ""})
+	ran, err = scheduler.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, ran, "Rescheduled: the next run follows the new expression")
+	assert.Contains(t, source.lastStatement().SQL, "UPDATE `typegorm_scheduled_tasks` SET")
+	assert.Contains(t, source.lastStatement().Args, "0 * * * *")
+	require.NoError(t, scheduler.Resign(ctx))
+}
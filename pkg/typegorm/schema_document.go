@@ -0,0 +1,33 @@
+package typegorm
+
+import (
+	"fmt"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// SchemaDocument builds a machine-readable description of the given models, with SQL
+// types resolved by the current dialect. Values may be struct pointers (e.g., &User{})
+// or declarative *schema.Model values. Marshal the result with encoding/json.
+func (db *DB) SchemaDocument(values ...any) (*schema.Document, error) {
+	models := make([]*schema.Model, 0, len(values))
+	for _, value := range values {
+		if model, ok := value.(*schema.Model); ok {
+			models = append(models, model)
+			continue
+		}
+		model, err := db.GetModel(value)
+		if err != nil {
+			return nil, fmt.Errorf("schema document: failed to parse schema for type %T: %w", value, err)
+		}
+		models = append(models, model)
+	}
+
+	dialect := db.source.Dialect()
+	doc, err := schema.NewDocument(dialect.GetDataType, models...)
+	if err != nil {
+		return nil, fmt.Errorf("schema document: %w", err)
+	}
+	doc.Dialect = dialect.Name()
+	return doc, nil
+}
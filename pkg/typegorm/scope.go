@@ -0,0 +1,60 @@
+package typegorm
+
+import (
+	"reflect"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Default Scopes ---
+
+// DefaultScoper is implemented by models whose Find/FindFirst queries are always
+// filtered, e.g. to exclude archived rows. The conditions use the same map syntax as
+// Find and are combined (AND) with the caller's conditions:
+//
+//	func (Project) DefaultScope() map[string]any { return map[string]any{"archived": false} }
+type DefaultScoper interface {
+	DefaultScope() map[string]any
+}
+
+// DefaultOrderer is implemented by models that are always returned in a given order
+// by Find/FindFirst unless an explicit Order option is passed:
+//
+//	func (Step) DefaultOrder() string { return "position ASC" }
+type DefaultOrderer interface {
+	DefaultOrder() string
+}
+
+// Unscoped bypasses the model's DefaultScope and DefaultOrder for one query, and
+// includes soft-deleted rows.
+func Unscoped() FindOption {
+	return func(opts *queryOptions) {
+		opts.unscoped = true
+	}
+}
+
+// applyDefaultScope prepends the model's default scope conditions to the WHERE clause,
+// excludes soft-deleted rows and applies its default order when none was requested.
+func applyDefaultScope(dialect common.Dialect, model *schema.Model, whereClauses []string, whereArgs []any, options *queryOptions) ([]string, []any, error) {
+	if options.unscoped || model.Type == nil {
+		return whereClauses, whereArgs, nil
+	}
+	instance := reflect.New(model.Type).Interface() // Pointer method set covers both receiver kinds
+
+	if scoper, ok := instance.(DefaultScoper); ok {
+		scopeClauses, scopeArgs, err := buildWhereClause(dialect, model, scoper.DefaultScope())
+		if err != nil {
+			return nil, nil, err
+		}
+		whereClauses = append(scopeClauses, whereClauses...)
+		whereArgs = append(scopeArgs, whereArgs...)
+	}
+	if softDelete := model.SoftDeleteField; softDelete != nil {
+		whereClauses = append(whereClauses, dialect.Quote(softDelete.DBName)+" IS NULL")
+	}
+	if orderer, ok := instance.(DefaultOrderer); ok && options.orderBy == "" {
+		options.orderBy = orderer.DefaultOrder()
+	}
+	return whereClauses, whereArgs, nil
+}
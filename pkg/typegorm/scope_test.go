@@ -0,0 +1,57 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type scopedStep struct {
+	ID       uint `typegorm:"primaryKey;autoIncrement"`
+	Title    string
+	Position int
+	Archived bool
+}
+
+func (scopedStep) DefaultScope() map[string]any { return map[string]any{"archived": false} }
+func (scopedStep) DefaultOrder() string         { return "position ASC" }
+
+func TestFind_DefaultScopeAndOrder(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	columns := []string{"id", "title", "position", "archived"}
+
+	source.queueRows(columns)
+	var steps []scopedStep
+	require.NoError(t, db.Find(ctx, &steps, map[string]any{"title": "Setup"}).Error)
+	assert.Equal(t, "SELECT `id`, `title`, `position`, `archived` FROM `scoped_steps` WHERE `archived` = ? AND `title` = ? ORDER BY `position` ASC", source.lastStatement().SQL)
+	assert.Equal(t, []any{false, "Setup"}, source.lastStatement().Args)
+
+	source.queueRows(columns)
+	require.NoError(t, db.Find(ctx, &steps, Order("id DESC")).Error)
+	assert.Equal(t, "SELECT `id`, `title`, `position`, `archived` FROM `scoped_steps` WHERE `archived` = ? ORDER BY `id` DESC", source.lastStatement().SQL, "explicit Order wins")
+
+	source.queueRows(columns)
+	require.NoError(t, db.Find(ctx, &steps, Unscoped()).Error)
+	assert.Equal(t, "SELECT `id`, `title`, `position`, `archived` FROM `scoped_steps`", source.lastStatement().SQL)
+}
+
+func TestFindFirst_DefaultScope(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	source.queueRows([]string{"id", "title", "position", "archived"}, []any{uint(1), "Setup", 1, false})
+	var step scopedStep
+	require.NoError(t, db.FindFirst(ctx, &step).Error)
+	assert.Equal(t, "SELECT `id`, `title`, `position`, `archived` FROM `scoped_steps` WHERE `archived` = ? ORDER BY `position` ASC LIMIT 1", source.lastStatement().SQL)
+
+	tx, err := db.Begin(ctx, nil)
+	require.NoError(t, err)
+	source.queueRows([]string{"id", "title", "position", "archived"}, []any{uint(2), "Old", 2, true})
+	require.NoError(t, tx.FindFirst(ctx, &step, map[string]any{"id": 2}, Unscoped()).Error)
+	require.NoError(t, tx.Commit())
+	assert.True(t, source.containsStatement("FROM `scoped_steps` WHERE `id` = ? LIMIT 1"))
+	assert.Equal(t, "Old", step.Title)
+}
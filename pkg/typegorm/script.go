@@ -0,0 +1,65 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+)
+
+// --- Script Execution ---
+
+// ExecScript executes a multi-statement SQL script (e.g., a hand-written schema or seed
+// file), split with common.SplitScript for the dialect: ';' inside strings and comments
+// is ignored, MySQL DELIMITER blocks and SQL Server GO batches are honored. Statements
+// run in order and execution stops at the first failure; RowsAffected is the total.
+// Use Tx.ExecScript to apply the script atomically (where the database supports
+// transactional DDL).
+//
+// The statements share one reserved connection, so that the SET, USE and temporary
+// tables of a statement are seen by the next ones. The connection is then closed rather
+// than returned to the pool, as the script may have changed its session.
+func (db *DB) ExecScript(ctx context.Context, script string) *Result {
+	conn, err := db.pinConn(ctx)
+	if err != nil {
+		return &Result{Error: fmt.Errorf("script: %w", err)}
+	}
+	defer conn.conn.Discard()
+	return execScript(ctx, conn, db.source.Dialect(), script)
+}
+
+// ExecScript executes a multi-statement SQL script within the transaction. See DB.ExecScript.
+func (tx *Tx) ExecScript(ctx context.Context, script string) *Result {
+	err := tx.checkWritable("ExecScript")
+	if err == nil {
+		var leave func()
+		if ctx, leave, err = tx.enter(ctx, "ExecScript"); err == nil {
+			defer leave()
+		}
+	}
+	if err != nil {
+		return &Result{Error: err}
+	}
+	return execScript(ctx, tx.source, tx.dialect, script)
+}
+
+func execScript(ctx context.Context, exec execer, dialect common.Dialect, script string) *Result {
+	result := &Result{}
+	statements, err := common.SplitScript(dialect.Name(), script)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	for i, stmt := range statements {
+		logSQL("Executing SQL (%d/%d): %s\n", i+1, len(statements), stmt)
+		res, err := exec.Exec(ctx, stmt)
+		if err != nil {
+			result.Error = fmt.Errorf("script statement %d failed: %w", i+1, err)
+			return result
+		}
+		if n, err := res.RowsAffected(); err == nil {
+			result.RowsAffected += n
+		}
+	}
+	return result
+}
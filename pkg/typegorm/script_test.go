@@ -0,0 +1,74 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitScript_IgnoresSeparatorsInStringsAndComments(t *testing.T) {
+	script := `
+-- seed data; not a statement
+INSERT INTO notes (body) VALUES ('a; b'), ('it''s; fine');
+/* block; comment */ UPDATE notes SET body = "x;y";
+-- trailing comment`
+	stmts, err := common.SplitScript("postgres", script)
+	require.NoError(t, err)
+	require.Len(t, stmts, 2)
+	assert.Equal(t, "-- seed data; not a statement\nINSERT INTO notes (body) VALUES ('a; b'), ('it''s; fine')", stmts[0])
+	assert.Equal(t, `/* block; comment */ UPDATE notes SET body = "x;y"`, stmts[1])
+}
+
+func TestSplitScript_MySQLDelimiter(t *testing.T) {
+	script := "DROP PROCEDURE IF EXISTS bump;\n" +
+		"DELIMITER //\n" +
+		"CREATE PROCEDURE bump() BEGIN UPDATE counters SET n = n + 1; SELECT 'it\\'s;'; END //\n" +
+		"DELIMITER ;\n" +
+		"CALL bump(); # run it; once\n"
+	stmts, err := common.SplitScript("mysql", script)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"DROP PROCEDURE IF EXISTS bump",
+		"CREATE PROCEDURE bump() BEGIN UPDATE counters SET n = n + 1; SELECT 'it\\'s;'; END",
+		"CALL bump()",
+	}, stmts)
+}
+
+func TestSplitScript_PostgresDollarQuotes(t *testing.T) {
+	script := "CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END; $body$ LANGUAGE plpgsql;\nSELECT $1::int;"
+	stmts, err := common.SplitScript("postgres", script)
+	require.NoError(t, err)
+	require.Len(t, stmts, 2)
+	assert.Equal(t, "SELECT $1::int", stmts[1])
+}
+
+func TestSplitScript_SQLServerGoBatches(t *testing.T) {
+	script := "CREATE TABLE [a;b] (id INT);\nINSERT INTO [a;b] VALUES (1);\ngo\nSELECT 1\nGO\n"
+	stmts, err := common.SplitScript("sqlserver", script)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CREATE TABLE [a;b] (id INT);\nINSERT INTO [a;b] VALUES (1);", "SELECT 1"}, stmts)
+}
+
+func TestSplitScript_Unterminated(t *testing.T) {
+	_, err := common.SplitScript("mysql", "SELECT 'oops;")
+	assert.Error(t, err)
+	_, err = common.SplitScript("mysql", "SELECT 1; /* never closed")
+	assert.Error(t, err)
+}
+
+func TestExecScript(t *testing.T) {
+	db, source := newMockDBWithDialect("mysql")
+	source.affected = 2
+
+	result := db.ExecScript(context.Background(), "UPDATE a SET x = 1; UPDATE b SET y = ';';")
+	require.NoError(t, result.Error)
+	assert.Equal(t, int64(4), result.RowsAffected)
+	stmts := source.Statements()
+	require.Len(t, stmts, 4)
+	assert.Equal(t, "PIN", stmts[0].SQL)
+	assert.Equal(t, "UPDATE b SET y = ';'", stmts[2].SQL)
+	assert.Equal(t, "DISCARD", stmts[3].SQL, "the session changed by the script is not reused")
+}
@@ -0,0 +1,186 @@
+package typegorm
+
+import (
+	"context"
+)
+
+// --- Context-bound Sessions ---
+
+// handle is the set of operations shared by DB and Tx that a Session forwards.
+type handle interface {
+	Create(ctx context.Context, value any, opts ...CreateOption) *Result
+	Save(ctx context.Context, value any, opts ...CreateOption) *Result
+	FindByID(ctx context.Context, dest any, id any) *Result
+	FindFirst(ctx context.Context, dest any, conds ...any) *Result
+	Find(ctx context.Context, dest any, condsAndOpts ...any) *Result
+	FindQuery(ctx context.Context, dest any, query Subquery, opts ...FindOption) *Result
+	FindUnion(ctx context.Context, dest any, union *UnionQuery, opts ...FindOption) *Result
+	FindMatching(ctx context.Context, dest any, column string, keys any, condsAndOpts ...any) *Result
+	Updates(ctx context.Context, modelWithValue any, data map[string]any) *Result
+	UpdateIf(ctx context.Context, modelWithValue any, data map[string]any, conds any) *Result
+	UpdatesMask(ctx context.Context, modelWithValue any, mask []string) *Result
+	Update(ctx context.Context, modelWithValue any) *Result
+	UpdatesModel(ctx context.Context, modelWithValue any, changes any) *Result
+	Delete(ctx context.Context, value any) *Result
+	Restore(ctx context.Context, value any) *Result
+	DeleteWhere(ctx context.Context, model any, conds ...any) *Result
+	UpdateWhere(ctx context.Context, model any, data map[string]any, conds ...any) *Result
+	ExecScript(ctx context.Context, script string) *Result
+	SyncChildren(ctx context.Context, parent any, children any, opts SyncOptions) (*SyncResult, error)
+	LoadVirtual(ctx context.Context, dest any, names ...string) error
+}
+
+var (
+	_ handle = (*DB)(nil)
+	_ handle = (*Tx)(nil)
+)
+
+// Session is a DB bound to a context: its methods are those of DB without the ctx
+// argument. When the context carries a transaction (see Transaction and ContextWithTx),
+// the operations run in that transaction, so code holding a request-scoped context
+// takes part in the surrounding transaction without passing the Tx around:
+//
+//	s := db.WithContext(r.Context())
+//	s.Find(&users, typegorm.Limit(10))
+type Session struct {
+	db  *DB
+	ctx context.Context
+}
+
+// WithContext returns a Session whose operations use ctx.
+func (db *DB) WithContext(ctx context.Context) *Session {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &Session{db: db, ctx: ctx}
+}
+
+// WithContext returns a copy of the session bound to another context.
+func (s *Session) WithContext(ctx context.Context) *Session {
+	return s.db.WithContext(ctx)
+}
+
+// Context returns the session's context.
+func (s *Session) Context() context.Context { return s.ctx }
+
+// DB returns the underlying DB.
+func (s *Session) DB() *DB { return s.db }
+
+// Tx returns the transaction carried by the session's context, if any.
+func (s *Session) Tx() (*Tx, bool) { return TxFromContext(s.ctx) }
+
+// target is the transaction carried by the context, or the DB.
+func (s *Session) target() handle {
+	if tx, ok := TxFromContext(s.ctx); ok {
+		return tx
+	}
+	return s.db
+}
+
+// Transaction runs fn in a transaction (a savepoint when the session is already in
+// one), passing a session bound to it. See DB.Transaction.
+func (s *Session) Transaction(fn func(s *Session) error, opts ...TxOption) error {
+	return s.db.Transaction(s.ctx, func(ctx context.Context, _ *Tx) error {
+		return fn(s.db.WithContext(ctx))
+	}, opts...)
+}
+
+// Create inserts value. See DB.Create.
+func (s *Session) Create(value any, opts ...CreateOption) *Result {
+	return s.target().Create(s.ctx, value, opts...)
+}
+
+// Save inserts or updates value. See DB.Save.
+func (s *Session) Save(value any, opts ...CreateOption) *Result {
+	return s.target().Save(s.ctx, value, opts...)
+}
+
+// FindByID loads the record with the given primary key. See DB.FindByID.
+func (s *Session) FindByID(dest any, id any) *Result {
+	return s.target().FindByID(s.ctx, dest, id)
+}
+
+// FindFirst loads the first record matching conds. See DB.FindFirst.
+func (s *Session) FindFirst(dest any, conds ...any) *Result {
+	return s.target().FindFirst(s.ctx, dest, conds...)
+}
+
+// Find loads the records matching the conditions. See DB.Find.
+func (s *Session) Find(dest any, condsAndOpts ...any) *Result {
+	return s.target().Find(s.ctx, dest, condsAndOpts...)
+}
+
+// FindQuery runs a built query. See DB.FindQuery.
+func (s *Session) FindQuery(dest any, query Subquery, opts ...FindOption) *Result {
+	return s.target().FindQuery(s.ctx, dest, query, opts...)
+}
+
+// FindUnion runs a compound query. See DB.FindUnion.
+func (s *Session) FindUnion(dest any, union *UnionQuery, opts ...FindOption) *Result {
+	return s.target().FindUnion(s.ctx, dest, union, opts...)
+}
+
+// FindMatching loads the records whose column matches any of keys. See DB.FindMatching.
+func (s *Session) FindMatching(dest any, column string, keys any, condsAndOpts ...any) *Result {
+	return s.target().FindMatching(s.ctx, dest, column, keys, condsAndOpts...)
+}
+
+// Updates updates the record identified by modelWithValue. See DB.Updates.
+func (s *Session) Updates(modelWithValue any, data map[string]any) *Result {
+	return s.target().Updates(s.ctx, modelWithValue, data)
+}
+
+// UpdateIf updates the record only if conds hold. See DB.UpdateIf.
+func (s *Session) UpdateIf(modelWithValue any, data map[string]any, conds any) *Result {
+	return s.target().UpdateIf(s.ctx, modelWithValue, data, conds)
+}
+
+// UpdatesMask updates the fields named in mask. See DB.UpdatesMask.
+func (s *Session) UpdatesMask(modelWithValue any, mask []string) *Result {
+	return s.target().UpdatesMask(s.ctx, modelWithValue, mask)
+}
+
+// Update writes every column of the struct. See DB.Update.
+func (s *Session) Update(modelWithValue any) *Result {
+	return s.target().Update(s.ctx, modelWithValue)
+}
+
+// UpdatesModel updates the non-zero fields of changes. See DB.UpdatesModel.
+func (s *Session) UpdatesModel(modelWithValue any, changes any) *Result {
+	return s.target().UpdatesModel(s.ctx, modelWithValue, changes)
+}
+
+// Delete deletes value. See DB.Delete.
+func (s *Session) Delete(value any) *Result {
+	return s.target().Delete(s.ctx, value)
+}
+
+// Restore undeletes a soft-deleted record. See DB.Restore.
+func (s *Session) Restore(value any) *Result {
+	return s.target().Restore(s.ctx, value)
+}
+
+// DeleteWhere deletes the rows matching conds. See DB.DeleteWhere.
+func (s *Session) DeleteWhere(model any, conds ...any) *Result {
+	return s.target().DeleteWhere(s.ctx, model, conds...)
+}
+
+// UpdateWhere sets data on the rows matching conds. See DB.UpdateWhere.
+func (s *Session) UpdateWhere(model any, data map[string]any, conds ...any) *Result {
+	return s.target().UpdateWhere(s.ctx, model, data, conds...)
+}
+
+// ExecScript executes a multi-statement SQL script. See DB.ExecScript.
+func (s *Session) ExecScript(script string) *Result {
+	return s.target().ExecScript(s.ctx, script)
+}
+
+// SyncChildren syncs a child collection. See DB.SyncChildren.
+func (s *Session) SyncChildren(parent any, children any, opts SyncOptions) (*SyncResult, error) {
+	return s.target().SyncChildren(s.ctx, parent, children, opts)
+}
+
+// LoadVirtual resolves virtual relations. See DB.LoadVirtual.
+func (s *Session) LoadVirtual(dest any, names ...string) error {
+	return s.target().LoadVirtual(s.ctx, dest, names...)
+}
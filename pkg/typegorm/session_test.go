@@ -0,0 +1,49 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sessionCtxKey struct{}
+
+func TestSession_UsesBoundContext(t *testing.T) {
+	db, source := newMockDB()
+	queueMaskUsers(source, 1)
+
+	ctx := context.WithValue(context.Background(), sessionCtxKey{}, "req-1")
+	s := db.WithContext(ctx)
+	assert.Equal(t, "req-1", s.Context().Value(sessionCtxKey{}))
+
+	var users []maskUser
+	require.NoError(t, s.Find(&users, Limit(1)).Error)
+	assert.Len(t, users, 1)
+	_, inTx := s.Tx()
+	assert.False(t, inTx)
+}
+
+func TestSession_JoinsTransactionFromContext(t *testing.T) {
+	db, source := newMockDBWithDialect("mysql")
+
+	err := db.WithContext(context.Background()).Transaction(func(s *Session) error {
+		tx, ok := s.Tx()
+		require.True(t, ok)
+		require.NoError(t, s.Create(&maskUser{Name: "ana"}).Error)
+
+		// A session rebuilt from the request context runs in the same transaction.
+		again := db.WithContext(s.Context())
+		inner, _ := again.Tx()
+		assert.Same(t, tx, inner)
+		return again.Transaction(func(s *Session) error { return nil })
+	})
+	require.NoError(t, err)
+
+	stmts := sqlOf(source.Statements())
+	require.GreaterOrEqual(t, len(stmts), 4)
+	assert.Equal(t, "BEGIN", stmts[0])
+	assert.Contains(t, stmts, "SAVEPOINT typegorm_sp1")
+	assert.Equal(t, "COMMIT", stmts[len(stmts)-1])
+}
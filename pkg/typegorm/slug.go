@@ -0,0 +1,143 @@
+package typegorm
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Slugs and Unique Tokens ---
+
+// slugAttempts is how many numbered suffixes ("-2" ... ) are tried before falling back
+// to a random suffix.
+const slugAttempts = 10
+
+// slugFold maps accented letters to their ASCII base letter.
+var slugFold = map[rune]string{
+	'á': "a", 'à': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a",
+	'é': "e", 'è': "e", 'ê': "e", 'ë': "e",
+	'í': "i", 'ì': "i", 'î': "i", 'ï': "i",
+	'ó': "o", 'ò': "o", 'ô': "o", 'õ': "o", 'ö': "o",
+	'ú': "u", 'ù': "u", 'û': "u", 'ü': "u",
+	'ç': "c", 'ñ': "n", 'ß': "ss", 'æ': "ae", 'ø': "o", 'œ': "oe",
+}
+
+// Slugify converts s to a lowercase, URL-safe slug: accents are folded to ASCII and
+// runs of other characters become a single dash ("Olá, Mundo!" -> "ola-mundo").
+func Slugify(s string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if folded, ok := slugFold[r]; ok {
+			sb.WriteString(folded)
+			dash = false
+			continue
+		}
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			sb.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && sb.Len() > 0 {
+			sb.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "-")
+}
+
+// UniqueToken returns a random URL-safe token of n characters (e.g. for invite codes
+// or unguessable public IDs).
+func UniqueToken(n int) string {
+	buf := make([]byte, (n*6+7)/8)
+	if _, err := rand.Read(buf); err != nil {
+		panic(fmt.Sprintf("typegorm: reading random bytes: %v", err))
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)[:n]
+}
+
+// createWithSlug fills an empty `uniqueSlug` field from its source field and runs
+// insert, retrying with "-2", "-3", ... (and finally a random suffix) while the insert
+// fails with a duplicate key on the slug column. A slug set by the caller is kept as is.
+func createWithSlug(value any, model *schema.Model, insert func() *Result) *Result {
+	field := model.SlugField
+	structValue := reflect.ValueOf(value)
+	if structValue.Kind() != reflect.Pointer || structValue.IsNil() || structValue.Elem().Kind() != reflect.Struct {
+		return insert() // Let Create report the invalid input
+	}
+	slugValue := structValue.Elem().FieldByName(field.GoName)
+	if slugValue.String() != "" {
+		return insert()
+	}
+
+	base := Slugify(structValue.Elem().FieldByName(field.SlugSource).String())
+	if base == "" {
+		base = strings.ToLower(UniqueToken(8))
+	}
+	var result *Result
+	for attempt := 1; attempt <= slugAttempts+1; attempt++ {
+		var suffix string
+		switch {
+		case attempt == slugAttempts+1:
+			suffix = "-" + strings.ToLower(UniqueToken(6))
+		case attempt > 1:
+			suffix = "-" + strconv.Itoa(attempt)
+		}
+		slugValue.SetString(fitSlug(base, suffix, field.Size))
+		result = insert()
+		if !isSlugConflict(result.Error, field) {
+			return result
+		}
+		fmt.Printf("Slug '%s' for %s is taken, retrying with a suffix.\n", slugValue.String(), model.Name)
+	}
+	slugValue.SetString("")
+	return result
+}
+
+// fitSlug appends suffix to base, truncating base so that the result fits size (0 = unlimited).
+func fitSlug(base, suffix string, size int) string {
+	if size > 0 && len(base)+len(suffix) > size {
+		base = strings.TrimSuffix(base[:max(size-len(suffix), 0)], "-")
+	}
+	return base + suffix
+}
+
+// isSlugConflict reports whether err is a duplicate key on the slug column.
+func isSlugConflict(err error, field *schema.Field) bool {
+	var dupErr *DuplicateKeyError
+	if !errors.As(err, &dupErr) {
+		return false
+	}
+	return slices.Contains(dupErr.Columns, field.DBName) || strings.Contains(dupErr.Constraint, field.DBName)
+}
+
+// createWithSlugInTx is createWithSlug inside a transaction. Postgres aborts the whole
+// transaction on a failed statement, so each attempt runs under a savepoint there.
+func (tx *Tx) createWithSlugInTx(ctx context.Context, value any, model *schema.Model, insert func() *Result) *Result {
+	if tx.dialect.Name() != "postgres" {
+		return createWithSlug(value, model, insert)
+	}
+	return createWithSlug(value, model, func() *Result {
+		if _, err := tx.source.Exec(ctx, "SAVEPOINT typegorm_slug"); err != nil {
+			return &Result{Error: fmt.Errorf("tx: failed to create savepoint: %w", err)}
+		}
+		result := insert()
+		stmt := "RELEASE SAVEPOINT typegorm_slug"
+		if result.Error != nil {
+			stmt = "ROLLBACK TO SAVEPOINT typegorm_slug"
+		}
+		if _, err := tx.source.Exec(ctx, stmt); err != nil && result.Error == nil {
+			result.Error = fmt.Errorf("tx: failed to release savepoint: %w", err)
+		}
+		return result
+	})
+}
@@ -0,0 +1,72 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type slugArticle struct {
+	ID    int64  `typegorm:"primaryKey;autoIncrement"`
+	Title string `typegorm:"size:200"`
+	Slug  string `typegorm:"size:20;uniqueSlug:Title"`
+}
+
+var errSlugTaken = errors.New("Error 1062 (23000): Duplicate entry 'x' for key 'slug'")
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "ola-mundo", Slugify("Olá, Mundo!"))
+	assert.Equal(t, "acao-reacao-2024", Slugify("  Ação & Reação -- 2024 "))
+	assert.Equal(t, "", Slugify("!!!"))
+	assert.Len(t, UniqueToken(12), 12)
+}
+
+func TestCreate_GeneratesSlug(t *testing.T) {
+	db, source := newMockDB()
+	article := &slugArticle{Title: "Hello World"}
+	require.NoError(t, db.Create(context.Background(), article).Error)
+	assert.Equal(t, "hello-world", article.Slug)
+	assert.Contains(t, source.Statements()[0].Args, "hello-world")
+}
+
+func TestCreate_SlugRetriesWithSuffix(t *testing.T) {
+	db, source := newMockDB()
+	source.execErrs = []error{errSlugTaken, errSlugTaken, nil}
+
+	article := &slugArticle{Title: "A very long title for this article"}
+	require.NoError(t, db.Create(context.Background(), article).Error)
+	assert.Equal(t, "a-very-long-title-3", article.Slug)
+	assert.Len(t, article.Slug, 19)
+}
+
+func TestCreate_SlugKeepsCallerValueAndOtherConflicts(t *testing.T) {
+	db, source := newMockDB()
+	source.execErrs = []error{errSlugTaken}
+
+	article := &slugArticle{Title: "Hello", Slug: "custom"}
+	result := db.Create(context.Background(), article)
+	require.ErrorIs(t, result.Error, ErrDuplicateKey)
+	assert.Equal(t, "custom", article.Slug)
+
+	source.execErrs = []error{errors.New("Error 1062 (23000): Duplicate entry '1' for key 'PRIMARY'")}
+	article = &slugArticle{Title: "Hello"}
+	require.ErrorIs(t, db.Create(context.Background(), article).Error, ErrDuplicateKey)
+	assert.Len(t, source.Statements(), 2) // No retry for a conflict on another column
+}
+
+func TestTxCreate_SlugUsesSavepointsOnPostgres(t *testing.T) {
+	db, source := newMockDBWithDialect("postgres")
+	tx, err := db.Begin(context.Background())
+	require.NoError(t, err)
+	source.execErrs = []error{nil, errors.New(`pq: duplicate key value violates unique constraint "slug_articles_slug_key"`)}
+
+	article := &slugArticle{Title: "Hello"}
+	require.NoError(t, tx.Create(context.Background(), article).Error)
+	assert.Equal(t, "hello-2", article.Slug)
+	assert.True(t, source.containsStatement("ROLLBACK TO SAVEPOINT typegorm_slug"))
+	assert.True(t, source.containsStatement("RELEASE SAVEPOINT typegorm_slug"))
+	require.NoError(t, tx.Commit())
+}
@@ -0,0 +1,192 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Soft Deletes ---
+
+// Models with a nullable deletion timestamp (a *time.Time or sql.NullTime DeletedAt
+// field, or any such field tagged "softDelete") are soft-deleted: Delete, DeleteWhere
+// and Model(...).Delete set the timestamp instead of removing the rows, and every read
+// (Find, FindFirst, FindByID, Count, FindQuery, FindUnion) skips the rows where it is
+// set. Unscoped bypasses both, and Restore clears the timestamp:
+//
+//	db.Delete(ctx, &user)                                  // UPDATE users SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL
+//	db.Find(ctx, &users, typegorm.Unscoped())              // Deleted rows included
+//	db.WithContext(ctx).Unscoped().Delete(&user)           // DELETE FROM users WHERE id = ?
+//	db.Restore(ctx, &user)                                 // UPDATE users SET deleted_at = NULL WHERE id = ?
+
+type unscopedKey struct{}
+
+// WithUnscoped makes the operations using ctx see soft-deleted rows and delete rows
+// for good, and skip the models' DefaultScope and DefaultOrder (see Unscoped).
+func WithUnscoped(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unscopedKey{}, true)
+}
+
+// Unscoped returns a copy of the session whose operations (also in its transactions)
+// see soft-deleted rows and delete rows for good. See WithUnscoped.
+func (s *Session) Unscoped() *Session {
+	return &Session{db: s.db, ctx: WithUnscoped(s.ctx)}
+}
+
+// isUnscoped reports whether ctx comes from WithUnscoped.
+func isUnscoped(ctx context.Context) bool {
+	return ctx.Value(unscopedKey{}) != nil
+}
+
+// softDeleteField returns the deletion timestamp of the model, nil when the model is
+// not soft-deleted or ctx is unscoped.
+func softDeleteField(ctx context.Context, model *schema.Model) *schema.Field {
+	if isUnscoped(ctx) {
+		return nil
+	}
+	return model.SoftDeleteField
+}
+
+// softDeleteSQL renders the soft delete of a row by primary key, and sets the deletion
+// timestamp on the struct. The row is left untouched when it is already deleted.
+func softDeleteSQL(dialect common.Dialect, model *schema.Model, softDelete *schema.Field, now time.Time, structValue reflect.Value, pkArgs []any) (string, []any) {
+	where := make([]string, 0, len(model.PrimaryKeys)+1)
+	for i, pk := range model.PrimaryKeys {
+		where = append(where, assignment(dialect, pk.DBName, i+2))
+	}
+	where = append(where, dialect.Quote(softDelete.DBName)+" IS NULL")
+	setDeletedAt(structValue.FieldByName(softDelete.GoName), &now)
+	return updateSQL(dialect, model, []string{assignment(dialect, softDelete.DBName, 1)}, where), append([]any{now}, pkArgs...)
+}
+
+// setDeletedAt sets a deletion timestamp field (*time.Time, time.Time or sql.NullTime)
+// to t, or clears it when t is nil.
+func setDeletedAt(field reflect.Value, t *time.Time) {
+	if !field.IsValid() || !field.CanSet() {
+		return
+	}
+	if t == nil {
+		field.SetZero()
+		return
+	}
+	if field.Type() == reflect.TypeOf(sql.NullTime{}) {
+		field.Set(reflect.ValueOf(sql.NullTime{Time: *t, Valid: true}))
+		return
+	}
+	setTime(field, *t)
+}
+
+// Restore undeletes a soft-deleted record identified by the primary key of value, and
+// clears its deletion timestamp. RowsAffected is 0 when the row is not deleted (or
+// does not exist). Counter caches are incremented again, as Delete decremented them.
+func (db *DB) Restore(ctx context.Context, value any) *Result {
+	defer invalidateEntity(ctx, db.cache, db.parser, value)
+	return restore(ctx, db.conn(ctx), db.policies, db.parser, db.source.Dialect(), value)
+}
+
+// Restore undeletes a soft-deleted record within the transaction. See DB.Restore.
+func (tx *Tx) Restore(ctx context.Context, value any) *Result {
+	err := tx.checkWritable("Restore")
+	if err == nil {
+		var leave func()
+		if ctx, leave, err = tx.enter(ctx, "Restore"); err == nil {
+			defer leave()
+		}
+	}
+	if err != nil {
+		result := &Result{Error: err}
+		wrapOpError(&result, tx.parser, "Restore", value, new(string))
+		return result
+	}
+	defer tx.invalidateEntity(ctx, value)
+	return restore(ctx, tx.source, tx.policies, tx.parser, tx.dialect, value)
+}
+
+// restoreConn is what Restore needs of a DataSource or Tx: writes, and the reads of the
+// policy and counter cache checks.
+type restoreConn interface {
+	execer
+	reader
+}
+
+func restore(ctx context.Context, conn restoreConn, policies []PolicyFunc, parser *schema.Parser, dialect common.Dialect, value any) (result *Result) {
+	var sqlQuery string
+	defer wrapOpError(&result, parser, "Restore", value, &sqlQuery)
+	defer recoverResult(&result, "Restore", value)
+	result = &Result{}
+
+	reflectValue := reflect.ValueOf(value)
+	if reflectValue.Kind() != reflect.Pointer || reflectValue.IsNil() || reflectValue.Elem().Kind() != reflect.Struct {
+		result.Error = fmt.Errorf("value must be a non-nil pointer to a struct, got %T", value)
+		return result
+	}
+	structValue := reflectValue.Elem()
+	model, err := parser.Parse(value)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to parse schema for type %s: %w", structValue.Type().Name(), err)
+		return result
+	}
+	softDelete := model.SoftDeleteField
+	if softDelete == nil {
+		result.Error = fmt.Errorf("cannot restore: model %s has no soft delete column (DeletedAt or tag softDelete)", model.Name)
+		return result
+	}
+	if len(model.PrimaryKeys) == 0 {
+		result.Error = fmt.Errorf("cannot restore: model %s has no primary key defined", model.Name)
+		return result
+	}
+
+	pkArgs := make([]any, 0, len(model.PrimaryKeys))
+	pkWhereClauses := make([]string, 0, len(model.PrimaryKeys)+1)
+	for i, pkField := range model.PrimaryKeys {
+		pkValueField := structValue.FieldByName(pkField.GoName)
+		if !pkValueField.IsValid() || pkValueField.IsZero() {
+			result.Error = fmt.Errorf("cannot restore: primary key field %s has zero value", pkField.GoName)
+			return result
+		}
+		pkArgs = append(pkArgs, pkValueField.Interface())
+		pkWhereClauses = append(pkWhereClauses, assignment(dialect, pkField.DBName, i+1))
+	}
+
+	// --- Check the data-access policies ---
+	policyWhere, policyArgs, err := policyClause(ctx, policies, dialect, "Update", model, pkArgs)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	keys, err := counterKeys(ctx, conn, dialect, model, structValue, pkWhereClauses, pkArgs)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	where := append(pkWhereClauses, dialect.Quote(softDelete.DBName)+" IS NOT NULL")
+	sqlQuery = updateSQL(dialect, model, []string{dialect.Quote(softDelete.DBName) + " = NULL"}, where)
+	sqlQuery, args := withPolicyClause(dialect, sqlQuery, pkArgs, policyWhere, policyArgs)
+	logSQL("Executing SQL: %s | Args: %v\n", sqlQuery, redactArgs(model, args, value)) // Debug log
+	sqlResult, err := conn.Exec(ctx, sqlQuery, args...)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to execute restore for %s: %w", model.Name, err)
+		return result
+	}
+	affected, err := sqlResult.RowsAffected()
+	if err != nil {
+		fmt.Printf("Warning: could not get RowsAffected after restore: %v\n", err)
+	}
+	result.RowsAffected = affected
+	if affected == 0 {
+		fmt.Printf("Warning: Restore executed but no rows affected (record not deleted or missing).\n")
+		return result
+	}
+	setDeletedAt(structValue.FieldByName(softDelete.GoName), nil)
+	if err := applyCounterCaches(ctx, conn, dialect, keys, 1); err != nil {
+		result.Error = err
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type softNote struct {
+	ID        uint `typegorm:"primaryKey"`
+	Title     string
+	DeletedAt *time.Time
+}
+
+func TestSoftDelete_DeleteSetsTimestamp(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	frozen := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	db.UseNowFunc(func() time.Time { return frozen })
+
+	note := softNote{ID: 4}
+	require.NoError(t, db.Delete(ctx, &note).Error)
+	assert.Equal(t, "UPDATE `soft_notes` SET `deleted_at` = ? WHERE `id` = ? AND `deleted_at` IS NULL", source.lastStatement().SQL)
+	assert.Equal(t, []any{frozen, uint(4)}, source.lastStatement().Args)
+	require.NotNil(t, note.DeletedAt)
+	assert.Equal(t, frozen, *note.DeletedAt)
+
+	require.NoError(t, db.DeleteWhere(ctx, &softNote{}, Eq("title", "draft")).Error)
+	assert.Equal(t, "UPDATE `soft_notes` SET `deleted_at` = ? WHERE `title` = ? AND `deleted_at` IS NULL", source.lastStatement().SQL)
+
+	// Unscoped deletes for good
+	require.NoError(t, db.WithContext(ctx).Unscoped().Delete(&softNote{ID: 4}).Error)
+	assert.Equal(t, "DELETE FROM `soft_notes` WHERE `id` = ?", source.lastStatement().SQL)
+	require.NoError(t, db.Model(&softNote{}).Unscoped().Where(Eq("title", "draft")).Delete(ctx).Error)
+	assert.Equal(t, "DELETE FROM `soft_notes` WHERE `title` = ?", source.lastStatement().SQL)
+}
+
+func TestSoftDelete_ReadsSkipDeletedRows(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	var notes []softNote
+	require.NoError(t, db.Find(ctx, &notes, map[string]any{"title": "a"}).Error)
+	assert.Equal(t, "SELECT `id`, `title`, `deleted_at` FROM `soft_notes` WHERE `title` = ? AND `deleted_at` IS NULL", source.lastStatement().SQL)
+
+	source.queueRows([]string{"id", "title", "deleted_at"}, []any{uint(1), "a", nil})
+	var note softNote
+	require.NoError(t, db.FindByID(ctx, &note, uint(1)).Error)
+	assert.Equal(t, "SELECT `id`, `title`, `deleted_at` FROM `soft_notes` WHERE `id` = ? AND `deleted_at` IS NULL LIMIT 1", source.lastStatement().SQL)
+
+	source.queueRows([]string{"count"}, []any{int64(2)})
+	_, err := db.Model(&softNote{}).Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM `soft_notes` WHERE `deleted_at` IS NULL", source.lastStatement().SQL)
+
+	require.NoError(t, db.Find(ctx, &notes, Unscoped()).Error)
+	assert.Equal(t, "SELECT `id`, `title`, `deleted_at` FROM `soft_notes`", source.lastStatement().SQL)
+
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	source.queueRows([]string{"id", "title", "deleted_at"}, []any{uint(1), "a", time.Now()})
+	require.NoError(t, tx.FindByID(WithUnscoped(ctx), &note, uint(1)).Error)
+	assert.Equal(t, "SELECT `id`, `title`, `deleted_at` FROM `soft_notes` WHERE `id` = ? LIMIT 1", source.lastStatement().SQL)
+	require.NoError(t, tx.Rollback())
+}
+
+func TestSoftDelete_Restore(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	deletedAt := time.Now()
+	note := softNote{ID: 9, DeletedAt: &deletedAt}
+	res := db.WithContext(ctx).Restore(&note)
+	require.NoError(t, res.Error)
+	assert.Equal(t, int64(1), res.RowsAffected)
+	assert.Equal(t, "UPDATE `soft_notes` SET `deleted_at` = NULL WHERE `id` = ? AND `deleted_at` IS NOT NULL", source.lastStatement().SQL)
+	assert.Nil(t, note.DeletedAt)
+
+	assert.ErrorContains(t, db.Restore(ctx, &maskUser{ID: 1}).Error, "has no soft delete column")
+}
@@ -0,0 +1,186 @@
+package typegorm
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Statement Assembly ---
+
+// stmtBuffer assembles SQL statements in a pooled byte buffer, instead of
+// fmt.Sprintf and intermediate string slices:
+//
+//	buf := getStmtBuffer(statementSize(model))
+//	defer putStmtBuffer(buf)
+//	buf.WriteString("DELETE FROM ")
+//	buf.WriteString(quoteTable(dialect, model))
+//	sqlQuery = buf.String()
+type stmtBuffer struct {
+	b []byte
+}
+
+// maxPooledStmtBuffer keeps unusually large statements (bulk inserts) out of the pool.
+const maxPooledStmtBuffer = 64 << 10
+
+var stmtBuffers = sync.Pool{
+	New: func() any { return &stmtBuffer{b: make([]byte, 0, 512)} },
+}
+
+// getStmtBuffer returns an empty buffer from the pool with room for size bytes.
+func getStmtBuffer(size int) *stmtBuffer {
+	buf := stmtBuffers.Get().(*stmtBuffer)
+	if cap(buf.b) < size {
+		buf.b = make([]byte, 0, size)
+	}
+	return buf
+}
+
+func putStmtBuffer(buf *stmtBuffer) {
+	if cap(buf.b) > maxPooledStmtBuffer {
+		return
+	}
+	buf.b = buf.b[:0]
+	stmtBuffers.Put(buf)
+}
+
+func (s *stmtBuffer) WriteString(str string) { s.b = append(s.b, str...) }
+
+func (s *stmtBuffer) WriteByte(c byte) error {
+	s.b = append(s.b, c)
+	return nil
+}
+
+// WriteQuoted writes an identifier quoted for the dialect.
+func (s *stmtBuffer) WriteQuoted(dialect common.Dialect, identifier string) {
+	s.b = append(s.b, dialect.Quote(identifier)...)
+}
+
+// WriteBindVar writes the placeholder of the i-th argument (1-based).
+func (s *stmtBuffer) WriteBindVar(dialect common.Dialect, i int) {
+	s.b = append(s.b, dialect.BindVar(i)...)
+}
+
+// WriteJoined writes items separated by sep.
+func (s *stmtBuffer) WriteJoined(items []string, sep string) {
+	for i, item := range items {
+		if i > 0 {
+			s.b = append(s.b, sep...)
+		}
+		s.b = append(s.b, item...)
+	}
+}
+
+// WriteInt writes a decimal integer.
+func (s *stmtBuffer) WriteInt(n int64) { s.b = strconv.AppendInt(s.b, n, 10) }
+
+// Len returns the number of bytes written.
+func (s *stmtBuffer) Len() int { return len(s.b) }
+
+// String returns a copy of the statement; the buffer can be returned to the pool.
+func (s *stmtBuffer) String() string { return string(s.b) }
+
+// statementSize estimates the length of a statement on the model's table from its
+// metadata: every column quoted, with a placeholder, plus keywords.
+func statementSize(model *schema.Model) int {
+	size := 64 + len(model.TableName)
+	for _, field := range model.Fields {
+		size += len(field.DBName) + 10
+	}
+	return size
+}
+
+// --- Cached SELECT column lists ---
+
+type selectListKey struct {
+	model   *schema.Model
+	dialect string
+}
+
+type selectList struct {
+	columns string          // Quoted, comma-separated
+	fields  []*schema.Field // Fields in column order
+}
+
+var selectLists sync.Map // selectListKey -> *selectList
+
+// selectColumns returns the quoted column list of the model's selectable (non-ignored)
+// fields and the fields themselves, computed once per model and dialect.
+func selectColumns(dialect common.Dialect, model *schema.Model) (string, []*schema.Field) {
+	key := selectListKey{model: model, dialect: dialect.Name()}
+	if cached, ok := selectLists.Load(key); ok {
+		list := cached.(*selectList)
+		return list.columns, list.fields
+	}
+	quoted := make([]string, 0, len(model.Fields))
+	fields := make([]*schema.Field, 0, len(model.Fields))
+	for _, field := range model.Fields {
+		if !field.IsIgnored {
+			quoted = append(quoted, dialect.Quote(field.DBName))
+			fields = append(fields, field)
+		}
+	}
+	list := &selectList{columns: strings.Join(quoted, ", "), fields: fields}
+	selectLists.Store(key, list)
+	return list.columns, list.fields
+}
+
+// --- Statement helpers ---
+
+// assignment renders `"column" = <placeholder>` for SET and WHERE clauses.
+func assignment(dialect common.Dialect, column string, argIndex int) string {
+	return dialect.Quote(column) + " = " + dialect.BindVar(argIndex)
+}
+
+// selectByPKSQL renders the single-row lookup of FindByID; softDelete (nil for none)
+// excludes soft-deleted rows.
+func selectByPKSQL(dialect common.Dialect, model *schema.Model, selectList string, pk *schema.Field, softDelete *schema.Field, policyWhere string) string {
+	buf := getStmtBuffer(len(selectList) + statementSize(model))
+	defer putStmtBuffer(buf)
+	buf.WriteString("SELECT ")
+	buf.WriteString(selectList)
+	buf.WriteString(" FROM ")
+	buf.WriteString(quoteTable(dialect, model))
+	buf.WriteString(" WHERE ")
+	buf.WriteQuoted(dialect, pk.DBName)
+	buf.WriteString(" = ")
+	buf.WriteBindVar(dialect, 1)
+	if softDelete != nil {
+		buf.WriteString(" AND ")
+		buf.WriteQuoted(dialect, softDelete.DBName)
+		buf.WriteString(" IS NULL")
+	}
+	if policyWhere != "" {
+		buf.WriteString(" AND ")
+		buf.WriteString(policyWhere)
+	}
+	buf.WriteString(" LIMIT 1")
+	return buf.String()
+}
+
+// deleteSQL renders `DELETE FROM table WHERE <where AND ...>`.
+func deleteSQL(dialect common.Dialect, model *schema.Model, where []string) string {
+	buf := getStmtBuffer(statementSize(model))
+	defer putStmtBuffer(buf)
+	buf.WriteString("DELETE FROM ")
+	buf.WriteString(quoteTable(dialect, model))
+	buf.WriteString(" WHERE ")
+	buf.WriteJoined(where, " AND ")
+	return buf.String()
+}
+
+// updateSQL renders `UPDATE table SET <set, ...> WHERE <where AND ...>`.
+func updateSQL(dialect common.Dialect, model *schema.Model, set, where []string) string {
+	buf := getStmtBuffer(statementSize(model))
+	defer putStmtBuffer(buf)
+	buf.WriteString("UPDATE ")
+	buf.WriteString(quoteTable(dialect, model))
+	buf.WriteString(" SET ")
+	buf.WriteJoined(set, ", ")
+	buf.WriteString(" WHERE ")
+	buf.WriteJoined(where, " AND ")
+	return buf.String()
+}
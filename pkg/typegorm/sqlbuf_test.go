@@ -0,0 +1,56 @@
+package typegorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatementHelpers(t *testing.T) {
+	db, _ := newMockDB()
+	dialect := db.source.Dialect()
+	model, err := db.GetModel(&maskUser{})
+	require.NoError(t, err)
+
+	selectList, fields := selectColumns(dialect, model)
+	assert.Equal(t, "`id`, `name`, `email`, `age`", selectList)
+	assert.Len(t, fields, 4)
+	again, _ := selectColumns(dialect, model)
+	assert.Equal(t, selectList, again)
+
+	assert.Equal(t, "SELECT `id`, `name`, `email`, `age` FROM `mask_users` WHERE `id` = ? LIMIT 1",
+		selectByPKSQL(dialect, model, selectList, model.PrimaryKeys[0], nil, ""))
+	assert.Equal(t, "DELETE FROM `mask_users` WHERE `id` = ?",
+		deleteSQL(dialect, model, []string{assignment(dialect, "id", 1)}))
+	assert.Equal(t, "UPDATE `mask_users` SET `name` = ?, `age` = ? WHERE `id` = ?",
+		updateSQL(dialect, model, []string{assignment(dialect, "name", 1), assignment(dialect, "age", 2)}, []string{assignment(dialect, "id", 3)}))
+
+	insert, _, err := buildInsertSQL(dialect, model, []string{"name", "age"}, nil, createOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `mask_users` (`name`, `age`) VALUES (?, ?)", insert)
+}
+
+func TestStmtBuffer_StringOutlivesPool(t *testing.T) {
+	buf := getStmtBuffer(16)
+	buf.WriteString("SELECT 1")
+	first := buf.String()
+	putStmtBuffer(buf)
+
+	buf = getStmtBuffer(16)
+	buf.WriteString("DELETE")
+	putStmtBuffer(buf)
+	assert.Equal(t, "SELECT 1", first, "String copies the bytes before the buffer is reused")
+}
+
+func BenchmarkUpdateSQL(b *testing.B) {
+	db, _ := newMockDB()
+	dialect := db.source.Dialect()
+	model, _ := db.GetModel(&maskUser{})
+	set := []string{assignment(dialect, "name", 1), assignment(dialect, "email", 2), assignment(dialect, "age", 3)}
+	where := []string{assignment(dialect, "id", 4)}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = updateSQL(dialect, model, set, where)
+	}
+}
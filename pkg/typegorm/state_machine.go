@@ -0,0 +1,211 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- State Machines ---
+
+// StateMachine declares the allowed transitions of a status field:
+//
+//	db.RegisterStateMachine(&Order{}, typegorm.StateMachine{
+//		Field: "Status",
+//		Transitions: map[string][]string{
+//			"pending": {"paid", "cancelled"},
+//			"paid":    {"shipped", "refunded"},
+//		},
+//		RecordHistory: true,
+//	})
+//	db.Updates(ctx, &order, map[string]any{"status": "shipped"}) // *TransitionError unless paid
+//
+// Updates, UpdateIf and Save changing the field read the current state, reject
+// transitions not listed with a *TransitionError (errors.Is ErrInvalidTransition), and
+// only update the row if it is still in the state that was validated (Save: see its
+// doc). Multi-row updates (Chain.Updates, UpdateWhere) cannot set the field. With
+// RecordHistory, every transition is stored as a StateTransition row (create its table
+// with AutoMigrate(&StateTransition{})), atomically with the update when run through a Tx.
+type StateMachine struct {
+	Field         string              // Go field holding the state
+	Transitions   map[string][]string // Allowed target states per current state
+	RecordHistory bool                // Insert a StateTransition row for every transition
+
+	column string // DB column of Field
+}
+
+// CanTransition reports whether the machine allows moving from one state to another.
+// Keeping the same state is always allowed.
+func (m *StateMachine) CanTransition(from, to string) bool {
+	return from == to || slices.Contains(m.Transitions[from], to)
+}
+
+// StateTransition is a transition history row (see StateMachine.RecordHistory).
+type StateTransition struct {
+	ID        uint64    `typegorm:"primaryKey;autoIncrement"`
+	Model     string    `typegorm:"size:100;index:idx_state_transitions_record"`
+	RecordID  string    `typegorm:"size:100;index:idx_state_transitions_record"`
+	Field     string    `typegorm:"size:100"`
+	FromState string    `typegorm:"size:100"`
+	ToState   string    `typegorm:"size:100"`
+	CreatedAt time.Time `typegorm:"autoCreateTime"`
+}
+
+// TransitionError is returned by Updates for a transition the state machine does not
+// allow. It matches ErrInvalidTransition with errors.Is.
+type TransitionError struct {
+	Model string
+	Field string
+	From  string
+	To    string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s.%s cannot transition from '%s' to '%s'", e.Model, e.Field, e.From, e.To)
+}
+
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// stateMachines is the registry shared by a DB and the transactions it begins.
+type stateMachines struct {
+	mu     sync.RWMutex
+	byType map[reflect.Type]*StateMachine
+}
+
+func newStateMachines() *stateMachines {
+	return &stateMachines{byType: make(map[reflect.Type]*StateMachine)}
+}
+
+func (r *stateMachines) get(structType reflect.Type) *StateMachine {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.byType[structType]
+}
+
+// RegisterStateMachine registers the state machine of a model type, replacing any
+// previous one.
+func (db *DB) RegisterStateMachine(model any, machine StateMachine) error {
+	parsed, err := db.GetModel(model)
+	if err != nil {
+		return fmt.Errorf("state machine: failed to parse schema for type %T: %w", model, err)
+	}
+	field, ok := parsed.FieldsByName[machine.Field]
+	if !ok {
+		return fmt.Errorf("state machine: field '%s' not found in %s", machine.Field, parsed.Name)
+	}
+	if field.GoType.Kind() != reflect.String {
+		return fmt.Errorf("state machine: field %s.%s must be a string, got %s", parsed.Name, field.GoName, field.GoType)
+	}
+	machine.column = field.DBName
+
+	db.machines.mu.Lock()
+	defer db.machines.mu.Unlock()
+	db.machines.byType[parsed.Type] = &machine
+	return nil
+}
+
+// pendingTransition is a validated transition of an Updates call.
+type pendingTransition struct {
+	machine  *StateMachine
+	from, to string
+}
+
+// checkTransition validates the state change requested by an Updates call, if any.
+// It returns nil when the model has no state machine or data does not touch its field.
+func checkTransition(ctx context.Context, conn counterExecer, registry *stateMachines, dialect common.Dialect, model *schema.Model, data map[string]any, pkWhere []string, pkArgs []any) (*pendingTransition, error) {
+	machine := registry.get(model.Type)
+	if machine == nil {
+		return nil, nil
+	}
+	next, ok := data[machine.column]
+	if !ok {
+		return nil, nil
+	}
+	to := stateString(next)
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s", dialect.Quote(machine.column), quoteTable(dialect, model), strings.Join(pkWhere, " AND "))
+	logSQL("Executing SQL: %s | Args: %v\n", query, redactArgs(model, pkArgs))
+	var current any
+	if err := conn.QueryRow(ctx, query, pkArgs...).Scan(&current); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Nothing to transition; the update affects no rows
+		}
+		return nil, fmt.Errorf("state machine: failed to read current state of %s: %w", model.Name, err)
+	}
+	from := stateString(current)
+	if !machine.CanTransition(from, to) {
+		return nil, &TransitionError{Model: model.Name, Field: machine.Field, From: from, To: to}
+	}
+	return &pendingTransition{machine: machine, from: from, to: to}, nil
+}
+
+// rejectBulkTransition refuses a multi-row update (Chain.Updates, UpdateWhere) setting
+// the field of the model's state machine, as its transition is checked per record.
+func rejectBulkTransition(registry *stateMachines, model *schema.Model, data map[string]any) error {
+	machine := registry.get(model.Type)
+	if machine == nil {
+		return nil
+	}
+	if _, ok := data[machine.column]; !ok {
+		return nil
+	}
+	return fmt.Errorf("%w: %s.%s cannot be set on many rows at once; use Updates, UpdateIf or Save on each record",
+		ErrInvalidTransition, model.Name, machine.Field)
+}
+
+// guard adds "AND <state> = <validated state>" to the update, so a concurrent
+// transition in between makes it affect no rows.
+func (t *pendingTransition) guard(dialect common.Dialect, where []string, args []any) ([]string, []any) {
+	if t == nil {
+		return where, args
+	}
+	return append(where, assignment(dialect, t.machine.column, len(args)+1)), append(args, t.from)
+}
+
+// history returns the StateTransition row to record, or nil.
+func (t *pendingTransition) history(model *schema.Model, pkArgs []any, now time.Time) *StateTransition {
+	if t == nil || !t.machine.RecordHistory || t.from == t.to {
+		return nil
+	}
+	ids := make([]string, len(model.PrimaryKeys))
+	for i := range ids {
+		ids[i] = fmt.Sprint(pkArgs[i])
+	}
+	return &StateTransition{
+		Model:     model.Name,
+		RecordID:  strings.Join(ids, ","),
+		Field:     t.machine.Field,
+		FromState: t.from,
+		ToState:   t.to,
+		CreatedAt: now,
+	}
+}
+
+func stateString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.String {
+		return rv.String() // Named string types
+	}
+	return fmt.Sprint(value)
+}
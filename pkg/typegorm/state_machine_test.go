@@ -0,0 +1,110 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type smOrder struct {
+	ID     int64  `typegorm:"primaryKey;autoIncrement"`
+	Status string `typegorm:"size:20"`
+}
+
+func newStateMachineDB(t *testing.T, history bool) (*DB, *mockSource) {
+	db, source := newMockDB()
+	require.NoError(t, db.RegisterStateMachine(&smOrder{}, StateMachine{
+		Field: "Status",
+		Transitions: map[string][]string{
+			"pending": {"paid", "cancelled"},
+			"paid":    {"shipped"},
+		},
+		RecordHistory: history,
+	}))
+	return db, source
+}
+
+func TestStateMachine_AllowsTransition(t *testing.T) {
+	db, source := newStateMachineDB(t, false)
+	source.queueRows([]string{"status"}, []any{"pending"})
+
+	require.NoError(t, db.Updates(context.Background(), &smOrder{ID: 1}, map[string]any{"status": "paid"}).Error)
+	stmts := source.Statements()
+	require.Len(t, stmts, 2)
+	assert.Equal(t, "SELECT `status` FROM `sm_orders` WHERE `id` = ?", stmts[0].SQL)
+	assert.Equal(t, "UPDATE `sm_orders` SET `status` = ? WHERE `id` = ? AND `status` = ?", stmts[1].SQL)
+	assert.Equal(t, []any{"paid", int64(1), "pending"}, stmts[1].Args)
+}
+
+func TestStateMachine_RejectsTransition(t *testing.T) {
+	db, source := newStateMachineDB(t, false)
+	source.queueRows([]string{"status"}, []any{[]byte("pending")})
+
+	result := db.Updates(context.Background(), &smOrder{ID: 1}, map[string]any{"status": "shipped"})
+	require.ErrorIs(t, result.Error, ErrInvalidTransition)
+	var transErr *TransitionError
+	require.True(t, errors.As(result.Error, &transErr))
+	assert.Equal(t, "pending", transErr.From)
+	assert.Equal(t, "shipped", transErr.To)
+	assert.Len(t, source.Statements(), 1) // No UPDATE
+}
+
+func TestStateMachine_OtherColumnsAreNotChecked(t *testing.T) {
+	type smNote struct {
+		ID     int64  `typegorm:"primaryKey"`
+		Status string `typegorm:"size:20"`
+		Note   string `typegorm:"size:100"`
+	}
+	db, source := newMockDB()
+	require.NoError(t, db.RegisterStateMachine(&smNote{}, StateMachine{Field: "Status"}))
+	require.NoError(t, db.Updates(context.Background(), &smNote{ID: 1}, map[string]any{"note": "x"}).Error)
+	assert.Len(t, source.Statements(), 1)
+}
+
+func TestStateMachine_RecordsHistoryInTx(t *testing.T) {
+	db, source := newStateMachineDB(t, true)
+	tx, err := db.Begin(context.Background())
+	require.NoError(t, err)
+	source.queueRows([]string{"status"}, []any{"paid"})
+
+	require.NoError(t, tx.Updates(context.Background(), &smOrder{ID: 7}, map[string]any{"status": "shipped"}).Error)
+	require.NoError(t, tx.Commit())
+
+	insert := source.Statements()[3]
+	assert.Contains(t, insert.SQL, "INSERT INTO `state_transitions`")
+	assert.Equal(t, []any{"smOrder", "7", "Status", "paid", "shipped"}, insert.Args[:5])
+}
+
+func TestStateMachine_UpdateIfAndBulkUpdates(t *testing.T) {
+	ctx := context.Background()
+	db, source := newStateMachineDB(t, true)
+	source.queueRows([]string{"status"}, []any{"pending"})
+	result := db.UpdateIf(ctx, &smOrder{ID: 1}, map[string]any{"status": "shipped"}, map[string]any{"status": "pending"})
+	require.ErrorIs(t, result.Error, ErrInvalidTransition)
+	assert.Len(t, source.Statements(), 1) // No UPDATE
+
+	source.statements = nil
+	source.queueRows([]string{"status"}, []any{"pending"})
+	require.NoError(t, db.UpdateIf(ctx, &smOrder{ID: 1}, map[string]any{"status": "paid"}, map[string]any{"status": "pending"}).Error)
+	stmts := source.Statements()
+	require.GreaterOrEqual(t, len(stmts), 3)
+	assert.Equal(t, "UPDATE `sm_orders` SET `status` = ? WHERE `id` = ? AND `status` = ? AND `status` = ?", stmts[1].SQL)
+	assert.Equal(t, []any{"paid", int64(1), "pending", "pending"}, stmts[1].Args)
+	assert.Contains(t, stmts[2].SQL, "INSERT INTO `state_transitions`")
+
+	source.statements = nil
+	result = db.UpdateWhere(ctx, &smOrder{}, map[string]any{"status": "cancelled"}, map[string]any{"status": "pending"})
+	require.ErrorIs(t, result.Error, ErrInvalidTransition)
+	result = db.Model(&smOrder{}).AllRows().Update(ctx, "Status", "cancelled")
+	require.ErrorIs(t, result.Error, ErrInvalidTransition)
+	assert.Empty(t, source.Statements())
+}
+
+func TestRegisterStateMachine_Validates(t *testing.T) {
+	db, _ := newMockDB()
+	assert.ErrorContains(t, db.RegisterStateMachine(&smOrder{}, StateMachine{Field: "State"}), "not found")
+	assert.ErrorContains(t, db.RegisterStateMachine(&smOrder{}, StateMachine{Field: "ID"}), "must be a string")
+}
@@ -0,0 +1,187 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+)
+
+// --- Request-scoped Statement Guard ---
+
+// StatementGuard counts the statements executed with a context (typically one HTTP
+// request) and reports when they exceed a limit, which makes N+1 query regressions
+// visible in development and staging:
+//
+//	func middleware(next http.Handler) http.Handler {
+//		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//			ctx, guard := typegorm.WithStatementGuard(r.Context(), 50)
+//			next.ServeHTTP(w, r.WithContext(ctx))
+//			log.Printf("%s: %d statements", r.URL.Path, guard.Count())
+//		})
+//	}
+//
+// By default exceeding the limit logs a warning once; with FailOnExceed the statements
+// over the limit fail with ErrStatementQuotaExceeded. Statements of transactions begun
+// with a guarded context count against that guard.
+type StatementGuard struct {
+	limit  int64
+	fail   bool
+	count  atomic.Int64
+	warned atomic.Bool
+}
+
+// StatementGuardOption configures a StatementGuard.
+type StatementGuardOption func(*StatementGuard)
+
+// FailOnExceed makes statements over the limit fail with ErrStatementQuotaExceeded
+// instead of only logging a warning.
+func FailOnExceed() StatementGuardOption {
+	return func(g *StatementGuard) {
+		g.fail = true
+	}
+}
+
+type statementGuardKey struct{}
+
+// WithStatementGuard returns a context whose statements are counted by a new guard
+// allowing up to limit statements (limit <= 0 only counts).
+func WithStatementGuard(ctx context.Context, limit int, opts ...StatementGuardOption) (context.Context, *StatementGuard) {
+	guard := &StatementGuard{limit: int64(limit)}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(guard)
+		}
+	}
+	return context.WithValue(ctx, statementGuardKey{}, guard), guard
+}
+
+// StatementGuardFromContext returns the guard of ctx, if any.
+func StatementGuardFromContext(ctx context.Context) (*StatementGuard, bool) {
+	guard, ok := ctx.Value(statementGuardKey{}).(*StatementGuard)
+	return guard, ok
+}
+
+// Count returns the number of statements executed so far.
+func (g *StatementGuard) Count() int64 { return g.count.Load() }
+
+// Exceeded reports whether more statements than the limit were executed.
+func (g *StatementGuard) Exceeded() bool { return g.limit > 0 && g.count.Load() > g.limit }
+
+// record counts one statement; it returns an error only with FailOnExceed.
+func (g *StatementGuard) record(query string) error {
+	n := g.count.Add(1)
+	if g.limit <= 0 || n <= g.limit {
+		return nil
+	}
+	if g.fail {
+		return fmt.Errorf("%w: statement %d exceeds the limit of %d: %s", ErrStatementQuotaExceeded, n, g.limit, query)
+	}
+	if g.warned.CompareAndSwap(false, true) {
+		fmt.Printf("Warning: statement quota exceeded: more than %d statements in one context (possible N+1 queries), at: %s\n", g.limit, query)
+	}
+	return nil
+}
+
+// guardSource wraps ds so that its statements are counted by the guard of ctx.
+func guardSource(ctx context.Context, ds common.DataSource) common.DataSource {
+	if guard, ok := StatementGuardFromContext(ctx); ok {
+		return &guardedSource{DataSource: ds, guard: guard}
+	}
+	return ds
+}
+
+// guardReader wraps rd so that its queries are counted by the guard of ctx.
+func guardReader(ctx context.Context, rd reader) reader {
+	if guard, ok := StatementGuardFromContext(ctx); ok {
+		return &guardedReader{reader: rd, guard: guard}
+	}
+	return rd
+}
+
+// guardTx wraps the transaction begun with ctx so that all its statements are counted
+// by the guard of ctx, whatever the context of the later operations.
+func guardTx(ctx context.Context, tx common.Tx) common.Tx {
+	if guard, ok := StatementGuardFromContext(ctx); ok {
+		return &guardedTx{Tx: tx, guard: guard}
+	}
+	return tx
+}
+
+type guardedSource struct {
+	common.DataSource
+	guard *StatementGuard
+}
+
+func (s *guardedSource) Exec(ctx context.Context, query string, args ...any) (common.Result, error) {
+	if err := s.guard.record(query); err != nil {
+		return nil, err
+	}
+	return s.DataSource.Exec(ctx, query, args...)
+}
+
+func (s *guardedSource) Query(ctx context.Context, query string, args ...any) (common.Rows, error) {
+	if err := s.guard.record(query); err != nil {
+		return nil, err
+	}
+	return s.DataSource.Query(ctx, query, args...)
+}
+
+func (s *guardedSource) QueryRow(ctx context.Context, query string, args ...any) common.RowScanner {
+	if err := s.guard.record(query); err != nil {
+		return &firstRowScanner{err: err}
+	}
+	return s.DataSource.QueryRow(ctx, query, args...)
+}
+
+type guardedReader struct {
+	reader
+	guard *StatementGuard
+}
+
+func (r *guardedReader) Query(ctx context.Context, query string, args ...any) (common.Rows, error) {
+	if err := r.guard.record(query); err != nil {
+		return nil, err
+	}
+	return r.reader.Query(ctx, query, args...)
+}
+
+func (r *guardedReader) QueryRow(ctx context.Context, query string, args ...any) common.RowScanner {
+	if err := r.guard.record(query); err != nil {
+		return &firstRowScanner{err: err}
+	}
+	return r.reader.QueryRow(ctx, query, args...)
+}
+
+type guardedTx struct {
+	common.Tx
+	guard *StatementGuard
+}
+
+func (t *guardedTx) Exec(ctx context.Context, query string, args ...any) (common.Result, error) {
+	if err := t.guard.record(query); err != nil {
+		return nil, err
+	}
+	return t.Tx.Exec(ctx, query, args...)
+}
+
+func (t *guardedTx) Query(ctx context.Context, query string, args ...any) (common.Rows, error) {
+	if err := t.guard.record(query); err != nil {
+		return nil, err
+	}
+	return t.Tx.Query(ctx, query, args...)
+}
+
+func (t *guardedTx) QueryRow(ctx context.Context, query string, args ...any) common.RowScanner {
+	if err := t.guard.record(query); err != nil {
+		return &firstRowScanner{err: err}
+	}
+	return t.Tx.QueryRow(ctx, query, args...)
+}
+
+// SupportsConcurrentUse forwards the capability of the wrapped transaction.
+func (t *guardedTx) SupportsConcurrentUse() bool {
+	c, ok := t.Tx.(concurrentTx)
+	return ok && c.SupportsConcurrentUse()
+}
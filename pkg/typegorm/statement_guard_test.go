@@ -0,0 +1,56 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatementGuardCounts(t *testing.T) {
+	db, source := newMockDB()
+	ctx, guard := WithStatementGuard(context.Background(), 2)
+
+	for i := 0; i < 3; i++ {
+		queueMaskUsers(source, 1)
+		var users []maskUser
+		require.NoError(t, db.Find(ctx, &users).Error, "without FailOnExceed the guard only warns")
+	}
+	assert.EqualValues(t, 3, guard.Count())
+	assert.True(t, guard.Exceeded())
+
+	_, ok := StatementGuardFromContext(context.Background())
+	assert.False(t, ok)
+}
+
+func TestStatementGuardFailOnExceed(t *testing.T) {
+	db, source := newMockDB()
+	ctx, guard := WithStatementGuard(context.Background(), 1, FailOnExceed())
+
+	require.NoError(t, db.Updates(ctx, &maskUser{ID: 1}, map[string]any{"name": "a"}).Error)
+	before := len(source.Statements())
+
+	res := db.Updates(ctx, &maskUser{ID: 1}, map[string]any{"name": "b"})
+	require.Error(t, res.Error)
+	assert.True(t, errors.Is(res.Error, ErrStatementQuotaExceeded))
+	assert.Len(t, source.Statements(), before, "the statement over the limit is not executed")
+	assert.EqualValues(t, 2, guard.Count())
+
+	var user maskUser
+	assert.ErrorIs(t, db.FindFirst(ctx, &user).Error, ErrStatementQuotaExceeded)
+}
+
+func TestStatementGuardCountsTransactions(t *testing.T) {
+	db, _ := newMockDB()
+	ctx, guard := WithStatementGuard(context.Background(), 0)
+
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Updates(context.Background(), &maskUser{ID: 1}, map[string]any{"age": 3}).Error)
+	require.NoError(t, tx.Delete(context.Background(), &maskUser{ID: 1}).Error)
+	require.NoError(t, tx.Commit())
+	assert.EqualValues(t, 2, guard.Count(), "statements of a transaction begun with the context count")
+	assert.False(t, guard.Exceeded(), "a limit of 0 only counts")
+}
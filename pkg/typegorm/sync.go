@@ -0,0 +1,235 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Child Collection Sync ---
+
+// SyncOptions configures SyncChildren.
+type SyncOptions struct {
+	ForeignKey string   // Child column referencing the parent (e.g., "order_id"). Required.
+	ParentKey  string   // Parent Go field or column holding the referenced value (default: the parent's primary key)
+	NaturalKey []string // Child columns identifying a child (default: the child's primary keys)
+}
+
+// SyncResult reports the changes applied by SyncChildren.
+type SyncResult struct {
+	Inserted int64
+	Updated  int64
+	Deleted  int64
+}
+
+// SyncChildren makes the children stored for parent match the given collection, with
+// the minimal set of inserts, updates and deletes, in one transaction — the classic
+// "replace this order's line items":
+//
+//	res, err := db.SyncChildren(ctx, &order, &order.Items, typegorm.SyncOptions{ForeignKey: "order_id"})
+//
+// children must be a pointer to a slice of structs (or struct pointers); the foreign
+// key of every child is set to the parent's key, and generated IDs are written back.
+// Existing rows are matched by NaturalKey; matched rows are updated only when a column
+// differs, unmatched rows are inserted, and stored rows missing from the slice are deleted.
+func (db *DB) SyncChildren(ctx context.Context, parent any, children any, opts SyncOptions) (*SyncResult, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := tx.SyncChildren(ctx, parent, children, opts)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("sync children: commit failed: %w", err)
+	}
+	return res, nil
+}
+
+// SyncChildren syncs a child collection within an existing transaction. See DB.SyncChildren.
+func (tx *Tx) SyncChildren(ctx context.Context, parent any, children any, opts SyncOptions) (*SyncResult, error) {
+	if opts.ForeignKey == "" {
+		return nil, fmt.Errorf("sync children: ForeignKey is required")
+	}
+
+	// 1. Resolve the parent key value
+	parentValue := reflect.ValueOf(parent)
+	if parentValue.Kind() != reflect.Pointer || parentValue.IsNil() || parentValue.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("sync children: parent must be a non-nil pointer to a struct, got %T", parent)
+	}
+	parentModel, err := tx.parser.Parse(parent)
+	if err != nil {
+		return nil, fmt.Errorf("sync children: failed to parse parent schema: %w", err)
+	}
+	parentField, err := syncParentField(parentModel, opts.ParentKey)
+	if err != nil {
+		return nil, err
+	}
+	parentKey := parentValue.Elem().FieldByName(parentField.GoName)
+	if parentKey.IsZero() {
+		return nil, fmt.Errorf("sync children: parent key %s has zero value", parentField.GoName)
+	}
+
+	// 2. Resolve the children collection and model
+	sliceValue := reflect.ValueOf(children)
+	if sliceValue.Kind() != reflect.Pointer || sliceValue.IsNil() || sliceValue.Elem().Kind() != reflect.Slice {
+		return nil, fmt.Errorf("sync children: children must be a non-nil pointer to a slice, got %T", children)
+	}
+	sliceValue = sliceValue.Elem()
+	childType := sliceValue.Type().Elem()
+	if childType.Kind() == reflect.Pointer {
+		childType = childType.Elem()
+	}
+	childModel, err := tx.parser.Parse(reflect.New(childType).Interface())
+	if err != nil {
+		return nil, fmt.Errorf("sync children: failed to parse child schema: %w", err)
+	}
+	fkField, ok := childModel.GetFieldByDBName(opts.ForeignKey)
+	if !ok {
+		return nil, fmt.Errorf("sync children: unknown foreign key column '%s' for %s", opts.ForeignKey, childModel.Name)
+	}
+	keyFields, err := syncKeyFields(childModel, opts.NaturalKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Load the stored children
+	existing := reflect.New(reflect.SliceOf(reflect.PointerTo(childType)))
+	if res := tx.Find(ctx, existing.Interface(), map[string]any{fkField.DBName: parentKey.Interface()}); res.Error != nil {
+		return nil, fmt.Errorf("sync children: failed to load existing %s: %w", childModel.Name, res.Error)
+	}
+	stored := make(map[string]reflect.Value, existing.Elem().Len())
+	for i := 0; i < existing.Elem().Len(); i++ {
+		elem := existing.Elem().Index(i)
+		stored[syncKey(elem.Elem(), keyFields)] = elem
+	}
+
+	// 4. Insert or update each desired child
+	result := &SyncResult{}
+	for i := 0; i < sliceValue.Len(); i++ {
+		child := sliceValue.Index(i)
+		if child.Kind() == reflect.Pointer {
+			if child.IsNil() {
+				continue
+			}
+		} else {
+			child = child.Addr()
+		}
+		fk := child.Elem().FieldByName(fkField.GoName)
+		if !parentKey.Type().ConvertibleTo(fk.Type()) {
+			return nil, fmt.Errorf("sync children: parent key type %s cannot be assigned to %s", parentKey.Type(), fk.Type())
+		}
+		fk.Set(parentKey.Convert(fk.Type()))
+
+		key := syncKey(child.Elem(), keyFields)
+		current, found := stored[key]
+		if !found || syncKeyIsZero(child.Elem(), keyFields) {
+			if res := tx.Create(ctx, child.Interface()); res.Error != nil {
+				return nil, fmt.Errorf("sync children: insert failed: %w", res.Error)
+			}
+			result.Inserted++
+			continue
+		}
+		delete(stored, key)
+
+		// Matched: carry the stored primary keys over and update changed columns only
+		data := make(map[string]any)
+		for _, field := range childModel.Fields {
+			if field.IsIgnored {
+				continue
+			}
+			if field.IsPrimaryKey {
+				child.Elem().FieldByName(field.GoName).Set(current.Elem().FieldByName(field.GoName))
+				continue
+			}
+			if field.IsAutoCreateTime() || field.IsAutoUpdateTime() {
+				continue
+			}
+			newValue := child.Elem().FieldByName(field.GoName).Interface()
+			if !reflect.DeepEqual(newValue, current.Elem().FieldByName(field.GoName).Interface()) {
+				data[field.DBName] = newValue
+			}
+		}
+		if len(data) == 0 {
+			continue
+		}
+		if res := tx.Updates(ctx, child.Interface(), data); res.Error != nil {
+			return nil, fmt.Errorf("sync children: update failed: %w", res.Error)
+		}
+		result.Updated++
+	}
+
+	// 5. Delete stored children no longer present
+	for _, orphan := range stored {
+		if res := tx.Delete(ctx, orphan.Interface()); res.Error != nil {
+			return nil, fmt.Errorf("sync children: delete failed: %w", res.Error)
+		}
+		result.Deleted++
+	}
+
+	fmt.Printf("Synced %s children: %d inserted, %d updated, %d deleted\n", childModel.Name, result.Inserted, result.Updated, result.Deleted)
+	return result, nil
+}
+
+func syncParentField(model *schema.Model, name string) (*schema.Field, error) {
+	if name == "" {
+		if len(model.PrimaryKeys) != 1 {
+			return nil, fmt.Errorf("sync children: parent %s needs exactly one primary key or an explicit ParentKey", model.Name)
+		}
+		return model.PrimaryKeys[0], nil
+	}
+	if field, ok := model.GetField(name); ok {
+		return field, nil
+	}
+	if field, ok := model.GetFieldByDBName(name); ok {
+		return field, nil
+	}
+	return nil, fmt.Errorf("sync children: unknown parent key '%s' for %s", name, model.Name)
+}
+
+func syncKeyFields(model *schema.Model, columns []string) ([]*schema.Field, error) {
+	if len(columns) == 0 {
+		if len(model.PrimaryKeys) == 0 {
+			return nil, fmt.Errorf("sync children: %s has no primary key; set NaturalKey", model.Name)
+		}
+		return model.PrimaryKeys, nil
+	}
+	fields := make([]*schema.Field, len(columns))
+	for i, col := range columns {
+		field, ok := model.GetFieldByDBName(col)
+		if !ok {
+			return nil, fmt.Errorf("sync children: unknown natural key column '%s' for %s", col, model.Name)
+		}
+		fields[i] = field
+	}
+	return fields, nil
+}
+
+// syncKey renders the identifying values of a child as a comparable string.
+func syncKey(structValue reflect.Value, fields []*schema.Field) string {
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		value := reflect.Indirect(structValue.FieldByName(field.GoName))
+		if !value.IsValid() {
+			parts[i] = "<nil>"
+			continue
+		}
+		parts[i] = fmt.Sprint(value.Interface())
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// syncKeyIsZero reports whether a child has no identity yet (e.g., a new row with a zero ID).
+func syncKeyIsZero(structValue reflect.Value, fields []*schema.Field) bool {
+	for _, field := range fields {
+		if !structValue.FieldByName(field.GoName).IsZero() {
+			return false
+		}
+	}
+	return true
+}
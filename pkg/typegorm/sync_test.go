@@ -0,0 +1,60 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type syncOrder struct {
+	ID uint `typegorm:"primaryKey;autoIncrement"`
+}
+
+type syncLine struct {
+	ID       uint `typegorm:"primaryKey;autoIncrement"`
+	OrderID  uint
+	SKU      string `typegorm:"column:sku"`
+	Quantity int
+}
+
+func TestSyncChildren(t *testing.T) {
+	db, source := newMockDB()
+	source.lastID = 30
+
+	// Stored: A (qty 1), B (qty 2), C (qty 3)
+	source.queueRows([]string{"id", "order_id", "sku", "quantity"},
+		[]any{uint(10), uint(7), "A", 1},
+		[]any{uint(11), uint(7), "B", 2},
+		[]any{uint(12), uint(7), "C", 3},
+	)
+
+	// Desired: A unchanged, B with qty 5, D new; C must go
+	lines := []syncLine{{SKU: "A", Quantity: 1}, {SKU: "B", Quantity: 5}, {SKU: "D", Quantity: 1}}
+	res, err := db.SyncChildren(context.Background(), &syncOrder{ID: 7}, &lines, SyncOptions{ForeignKey: "order_id", NaturalKey: []string{"sku"}})
+	require.NoError(t, err)
+	assert.Equal(t, &SyncResult{Inserted: 1, Updated: 1, Deleted: 1}, res)
+
+	assert.Equal(t, uint(10), lines[0].ID, "matched rows keep the stored ID")
+	assert.Equal(t, uint(11), lines[1].ID)
+	assert.Equal(t, uint(30), lines[2].ID, "inserted rows receive the generated ID")
+	for _, line := range lines {
+		assert.Equal(t, uint(7), line.OrderID)
+	}
+
+	stmts := source.Statements()
+	assert.Equal(t, "BEGIN", stmts[0].SQL)
+	assert.Equal(t, "COMMIT", stmts[len(stmts)-1].SQL)
+	assert.True(t, source.containsStatement("UPDATE `sync_lines` SET `quantity` = ?"))
+	assert.True(t, source.containsStatement("INSERT INTO `sync_lines`"))
+	assert.True(t, source.containsStatement("DELETE FROM `sync_lines`"))
+}
+
+func TestSyncChildren_RequiresForeignKey(t *testing.T) {
+	db, source := newMockDB()
+	var lines []syncLine
+	_, err := db.SyncChildren(context.Background(), &syncOrder{ID: 7}, &lines, SyncOptions{})
+	require.Error(t, err)
+	assert.Equal(t, "ROLLBACK", source.lastStatement().SQL)
+}
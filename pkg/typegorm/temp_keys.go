@@ -0,0 +1,228 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+)
+
+// --- Temporary Key Tables ---
+
+// tempKeyColumn is the single column of a key table.
+const tempKeyColumn = "match_key"
+
+// tempKeyBatch bounds the rows per INSERT, well below the placeholder limits of the
+// drivers (65535 on MySQL/Postgres, 32766 on SQLite).
+const tempKeyBatch = 1000
+
+// TempKeys is a session temporary table holding a set of keys, created with
+// Tx.CreateTempKeys and matched with SelectQuery.JoinKeys. It lives on the
+// transaction's connection only.
+type TempKeys struct {
+	Name  string // Table name
+	Count int    // Distinct keys loaded
+}
+
+// CreateTempKeys creates a temporary table named name (a random name when empty) and
+// bulk-loads the distinct values of keys (a slice of integers or strings) into it. It
+// runs in a transaction because temporary tables belong to one connection:
+//
+//	keys, err := tx.CreateTempKeys(ctx, "", customerIDs)
+//	res := tx.FindQuery(ctx, &orders, typegorm.From(&Order{}).JoinKeys("customer_id", keys))
+//	err = tx.DropTempKeys(ctx, keys)
+//
+// This replaces IN lists with tens of thousands of values, which hit placeholder limits
+// and are slow to parse and plan. On Postgres the table is also dropped at commit.
+func (tx *Tx) CreateTempKeys(ctx context.Context, name string, keys any) (*TempKeys, error) {
+	ctx, leave, err := tx.enter(ctx, "CreateTempKeys")
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	values, keyType, err := distinctKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = "typegorm_keys_" + strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(UniqueToken(16)))
+	}
+
+	statements := []string{createTempKeysSQL(tx.dialect, name, keyType)}
+	for start := 0; start < len(values); start += tempKeyBatch {
+		end := min(start+tempKeyBatch, len(values))
+		statements = append(statements, insertTempKeysSQL(tx.dialect, name, end-start))
+	}
+
+	logSQL("Executing SQL: %s\n", statements[0])
+	if _, err := tx.source.Exec(ctx, statements[0]); err != nil {
+		return nil, fmt.Errorf("tx: failed to create temporary table %s: %w", name, err)
+	}
+	for i, stmt := range statements[1:] {
+		start := i * tempKeyBatch
+		batch := values[start:min(start+tempKeyBatch, len(values))]
+		if _, err := tx.source.Exec(ctx, stmt, batch...); err != nil {
+			return nil, fmt.Errorf("tx: failed to load keys into %s: %w", name, err)
+		}
+	}
+	fmt.Printf("Loaded %d key(s) into temporary table %s.\n", len(values), name)
+	return &TempKeys{Name: name, Count: len(values)}, nil
+}
+
+// DropTempKeys drops a key table before the end of the session.
+func (tx *Tx) DropTempKeys(ctx context.Context, keys *TempKeys) error {
+	if keys == nil {
+		return nil
+	}
+	ctx, leave, err := tx.enter(ctx, "DropTempKeys")
+	if err != nil {
+		return err
+	}
+	defer leave()
+
+	stmt := "DROP TABLE IF EXISTS " + tx.dialect.Quote(keys.Name)
+	if tx.dialect.Name() == "mysql" {
+		stmt = "DROP TEMPORARY TABLE IF EXISTS " + tx.dialect.Quote(keys.Name)
+	}
+	logSQL("Executing SQL: %s\n", stmt)
+	if _, err := tx.source.Exec(ctx, stmt); err != nil {
+		return fmt.Errorf("tx: failed to drop temporary table %s: %w", keys.Name, err)
+	}
+	return nil
+}
+
+// JoinKeys restricts the query to rows whose column matches a key of the table, with an
+// INNER JOIN on the key table (keys are distinct, so rows are not duplicated).
+func (q *SelectQuery) JoinKeys(column string, keys *TempKeys) *SelectQuery {
+	q.joinColumn, q.joinKeys = column, keys
+	return q
+}
+
+// FindMatching finds the records whose column matches any of keys, loading the keys
+// into a temporary table instead of building an IN list. condsAndOpts are those of Find:
+//
+//	res := db.FindMatching(ctx, &orders, "customer_id", customerIDs, typegorm.Order("id"))
+func (db *DB) FindMatching(ctx context.Context, dest any, column string, keys any, condsAndOpts ...any) *Result {
+	var result *Result
+	err := db.RunInTransaction(ctx, func(tx *Tx) error {
+		result = tx.FindMatching(ctx, dest, column, keys, condsAndOpts...)
+		return result.Error
+	})
+	if result == nil {
+		result = &Result{Error: err}
+	}
+	return result
+}
+
+// FindMatching finds the records matching keys within the transaction. See DB.FindMatching.
+func (tx *Tx) FindMatching(ctx context.Context, dest any, column string, keys any, condsAndOpts ...any) *Result {
+	destType := reflect.TypeOf(dest)
+	if destType == nil || destType.Kind() != reflect.Pointer || destType.Elem().Kind() != reflect.Slice {
+		return &Result{Error: fmt.Errorf("destination must be a non-nil pointer to a slice, got %T", dest)}
+	}
+	elemType := destType.Elem().Elem()
+	if elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+
+	table, err := tx.CreateTempKeys(ctx, "", keys)
+	if err != nil {
+		return &Result{Error: err}
+	}
+	defer func() {
+		if err := tx.DropTempKeys(ctx, table); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}()
+	query := From(reflect.New(elemType).Interface(), condsAndOpts...).JoinKeys(column, table)
+	return tx.FindQuery(ctx, dest, query)
+}
+
+// joinKeysSQL renders the INNER JOIN of SelectQuery.JoinKeys.
+func joinKeysSQL(dialect common.Dialect, table, column string, keys *TempKeys) string {
+	return fmt.Sprintf(" INNER JOIN %s ON %s.%s = %s.%s",
+		dialect.Quote(keys.Name), quoteQualified(dialect, table), dialect.Quote(column), dialect.Quote(keys.Name), dialect.Quote(tempKeyColumn))
+}
+
+func createTempKeysSQL(dialect common.Dialect, name string, keyType reflect.Kind) string {
+	columnType := "BIGINT"
+	if keyType == reflect.String {
+		columnType = "TEXT"
+		if dialect.Name() == "mysql" {
+			columnType = "VARCHAR(255)"
+		}
+	}
+	stmt := fmt.Sprintf("CREATE TEMPORARY TABLE %s (%s %s NOT NULL PRIMARY KEY)", dialect.Quote(name), dialect.Quote(tempKeyColumn), columnType)
+	if dialect.Name() == "postgres" {
+		stmt += " ON COMMIT DROP"
+	}
+	return stmt
+}
+
+func insertTempKeysSQL(dialect common.Dialect, name string, rows int) string {
+	buf := getStmtBuffer(64 + rows*6)
+	defer putStmtBuffer(buf)
+	buf.WriteString("INSERT INTO ")
+	buf.WriteQuoted(dialect, name)
+	buf.WriteString(" (")
+	buf.WriteQuoted(dialect, tempKeyColumn)
+	buf.WriteString(") VALUES ")
+	for i := 0; i < rows; i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteByte('(')
+		buf.WriteBindVar(dialect, i+1)
+		buf.WriteByte(')')
+	}
+	return buf.String()
+}
+
+// distinctKeys flattens a slice of integer or string keys, dropping duplicates (the key
+// column is the primary key) while keeping the first-seen order.
+func distinctKeys(keys any) ([]any, reflect.Kind, error) {
+	slice := reflect.ValueOf(keys)
+	if slice.Kind() != reflect.Slice && slice.Kind() != reflect.Array {
+		return nil, reflect.Invalid, fmt.Errorf("keys must be a slice, got %T", keys)
+	}
+	if slice.Len() == 0 {
+		return nil, reflect.Invalid, fmt.Errorf("keys must not be empty")
+	}
+
+	kind := reflect.Invalid
+	values := make([]any, 0, slice.Len())
+	seen := make(map[any]bool, slice.Len())
+	for i := 0; i < slice.Len(); i++ {
+		v := slice.Index(i)
+		if v.Kind() == reflect.Interface {
+			v = v.Elem()
+		}
+		var key any
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			key = v.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			key = int64(v.Uint())
+		case reflect.String:
+			key = v.String()
+		default:
+			return nil, reflect.Invalid, fmt.Errorf("keys must be integers or strings, got %s at index %d", v.Kind(), i)
+		}
+		keyKind := reflect.Int64
+		if v.Kind() == reflect.String {
+			keyKind = reflect.String
+		}
+		if kind != reflect.Invalid && kind != keyKind {
+			return nil, reflect.Invalid, fmt.Errorf("keys must all be integers or all be strings")
+		}
+		kind = keyKind
+		if !seen[key] {
+			seen[key] = true
+			values = append(values, key)
+		}
+	}
+	return values, kind, nil
+}
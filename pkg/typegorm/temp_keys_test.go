@@ -0,0 +1,71 @@
+package typegorm
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTempKeys_LoadsDistinctKeysInBatches(t *testing.T) {
+	db, source := newMockDBWithDialect("mysql")
+	tx, err := db.Begin(context.Background())
+	require.NoError(t, err)
+
+	keys := make([]int, 0, 2502)
+	for i := 0; i < 2500; i++ {
+		keys = append(keys, i)
+	}
+	keys = append(keys, 1, 2) // Duplicates are dropped
+
+	table, err := tx.CreateTempKeys(context.Background(), "wanted", keys)
+	require.NoError(t, err)
+	assert.Equal(t, 2500, table.Count)
+
+	stmts := source.Statements()[1:] // After BEGIN
+	require.Len(t, stmts, 4)
+	assert.Equal(t, "CREATE TEMPORARY TABLE `wanted` (`match_key` BIGINT NOT NULL PRIMARY KEY)", stmts[0].SQL)
+	assert.Len(t, stmts[1].Args, 1000)
+	assert.Len(t, stmts[3].Args, 500)
+	assert.True(t, strings.HasPrefix(stmts[3].SQL, "INSERT INTO `wanted` (`match_key`) VALUES (?), (?)"))
+
+	require.NoError(t, tx.DropTempKeys(context.Background(), table))
+	assert.Equal(t, "DROP TEMPORARY TABLE IF EXISTS `wanted`", source.lastStatement().SQL)
+}
+
+func TestCreateTempKeys_Postgres(t *testing.T) {
+	assert.Equal(t, "CREATE TEMPORARY TABLE `k` (`match_key` TEXT NOT NULL PRIMARY KEY) ON COMMIT DROP",
+		createTempKeysSQL(&mockDialect{name: "postgres"}, "k", reflect.String))
+}
+
+func TestFindMatching_JoinsKeyTable(t *testing.T) {
+	db, source := newMockDBWithDialect("mysql")
+	queueMaskUsers(source, 2)
+
+	var users []maskUser
+	result := db.FindMatching(context.Background(), &users, "Email", []string{"a@x.io", "b@x.io"}, map[string]any{"age >": 18})
+	require.NoError(t, result.Error)
+	assert.Len(t, users, 2)
+
+	require.True(t, source.containsStatement("CREATE TEMPORARY TABLE"))
+	var find string
+	for _, s := range source.Statements() {
+		if strings.HasPrefix(s.SQL, "SELECT") {
+			find = s.SQL
+		}
+	}
+	assert.Regexp(t, "^SELECT `id`, `name`, `email`, `age` FROM `mask_users` INNER JOIN `typegorm_keys_[a-z0-9]+` ON `mask_users`.`email` = `typegorm_keys_[a-z0-9]+`.`match_key` WHERE `age` > \\?$", find)
+	assert.True(t, source.containsStatement("DROP TEMPORARY TABLE IF EXISTS `typegorm_keys_"))
+}
+
+func TestDistinctKeys_Validation(t *testing.T) {
+	_, _, err := distinctKeys([]any{1, "a"})
+	assert.Error(t, err)
+	_, _, err = distinctKeys([]int{})
+	assert.Error(t, err)
+	_, _, err = distinctKeys(42)
+	assert.Error(t, err)
+}
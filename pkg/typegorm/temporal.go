@@ -0,0 +1,228 @@
+package typegorm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Temporal (History) Tables ---
+
+// Period columns of the history table: a row version is valid from ValidFromColumn
+// (inclusive) to ValidToColumn (exclusive, NULL for the current version when emulated).
+const (
+	ValidFromColumn = "valid_from"
+	ValidToColumn   = "valid_to"
+)
+
+// TemporalModel is implemented by models whose past states are kept in a history table,
+// queried with the AsOf find option:
+//
+//	func (Price) HistoryTable() string { return "" } // "<table>_history"
+//
+// On SQL Server AutoMigrate turns the table into a system-versioned temporal table (with
+// hidden period columns). Elsewhere the history is emulated: AutoMigrate creates the
+// history table, with the model's columns and the period columns, and triggers recording
+// every insert, update and delete. Rows existing before the triggers have no history.
+type TemporalModel interface {
+	HistoryTable() string
+}
+
+// AsOf returns the rows as they were at t, read from the history table of a
+// TemporalModel. Other models fail with an error.
+func AsOf(t time.Time) FindOption {
+	return func(opts *queryOptions) {
+		opts.asOf = &t
+	}
+}
+
+// historyTableOf returns the (unqualified) history table of a temporal model, or false.
+func historyTableOf(model *schema.Model) (string, bool) {
+	if model.Type == nil {
+		return "", false
+	}
+	temporal, ok := reflect.New(model.Type).Interface().(TemporalModel)
+	if !ok {
+		return "", false
+	}
+	if name := temporal.HistoryTable(); name != "" {
+		return name, true
+	}
+	return model.TableName + "_history", true
+}
+
+// historyModel returns the model's history table as a model (same schema).
+func historyModel(model *schema.Model, table string) *schema.Model {
+	return &schema.Model{Name: model.Name, TableName: table, Schema: model.Schema}
+}
+
+// TemporalSQL builds the statements versioning a TemporalModel's table: SQL Server
+// system versioning, or the emulated history table and its triggers. It returns nil for
+// other models.
+func TemporalSQL(dialect common.Dialect, model *schema.Model) ([]string, error) {
+	table, ok := historyTableOf(model)
+	if !ok {
+		return nil, nil
+	}
+	if dialect.Name() == "sqlserver" {
+		return systemVersioningSQL(dialect, model, table), nil
+	}
+	if len(model.PrimaryKeys) == 0 {
+		return nil, fmt.Errorf("temporal model %s needs a primary key", model.Name)
+	}
+	history := historyModel(model, table)
+	createSQL, err := historyTableSQL(dialect, model, history)
+	if err != nil {
+		return nil, err
+	}
+	triggers, err := historyTriggers(dialect, model, history)
+	if err != nil {
+		return nil, err
+	}
+	statements := []string{createSQL}
+	for _, trigger := range triggers {
+		triggerSQL, err := CreateTriggerSQL(dialect, trigger)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, triggerSQL...)
+	}
+	return statements, nil
+}
+
+// systemVersioningSQL adds the hidden period columns and turns system versioning on, once.
+func systemVersioningSQL(dialect common.Dialect, model *schema.Model, table string) []string {
+	schemaName := model.Schema
+	if schemaName == "" {
+		schemaName = "dbo" // HISTORY_TABLE must be schema-qualified
+	}
+	quoted := quoteTable(dialect, model)
+	escape := func(stmt string) string { return strings.ReplaceAll(stmt, "'", "''") }
+	addPeriod := fmt.Sprintf("ALTER TABLE %s ADD "+
+		"%s DATETIME2 GENERATED ALWAYS AS ROW START HIDDEN NOT NULL DEFAULT SYSUTCDATETIME(), "+
+		"%s DATETIME2 GENERATED ALWAYS AS ROW END HIDDEN NOT NULL DEFAULT CONVERT(DATETIME2, '9999-12-31 23:59:59.9999999'), "+
+		"PERIOD FOR SYSTEM_TIME (%s, %s)",
+		quoted, dialect.Quote(ValidFromColumn), dialect.Quote(ValidToColumn), dialect.Quote(ValidFromColumn), dialect.Quote(ValidToColumn))
+	versioning := fmt.Sprintf("ALTER TABLE %s SET (SYSTEM_VERSIONING = ON (HISTORY_TABLE = %s.%s))",
+		quoted, dialect.Quote(schemaName), dialect.Quote(table))
+	// EXEC defers the name resolution of the period columns to each statement
+	return []string{fmt.Sprintf("IF OBJECTPROPERTY(OBJECT_ID(N'%s'), 'TableTemporalType') = 0\nBEGIN\n  EXEC(N'%s');\n  EXEC(N'%s');\nEND",
+		escape(quoted), escape(addPeriod), escape(versioning))}
+}
+
+// historyTableSQL builds the emulated history table: the model's columns without keys,
+// defaults or constraints, plus the period columns.
+func historyTableSQL(dialect common.Dialect, model, history *schema.Model) (string, error) {
+	var columnDefs []string
+	for _, field := range model.Fields {
+		if field.IsIgnored {
+			continue
+		}
+		plain := *field
+		plain.IsPrimaryKey, plain.AutoIncrement, plain.Unique, plain.IsRequired = false, false, false, false
+		plain.DefaultValue = nil
+		colType, err := dialect.GetDataType(&plain)
+		if err != nil {
+			return "", fmt.Errorf("failed to get data type for field %s.%s: %w", model.Name, field.GoName, err)
+		}
+		columnDefs = append(columnDefs, dialect.Quote(field.DBName)+" "+colType)
+	}
+	timeType := periodType(dialect)
+	columnDefs = append(columnDefs,
+		fmt.Sprintf("%s %s NOT NULL", dialect.Quote(ValidFromColumn), timeType),
+		fmt.Sprintf("%s %s NULL", dialect.Quote(ValidToColumn), timeType))
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s);", quoteTable(dialect, history), strings.Join(columnDefs, ", ")), nil
+}
+
+// periodType and periodNow return the column type and current time of the period columns.
+func periodType(dialect common.Dialect) string {
+	switch dialect.Name() {
+	case "mysql":
+		return "DATETIME(6)"
+	case "postgres":
+		return "TIMESTAMP(6)"
+	}
+	return "TIMESTAMP"
+}
+
+func periodNow(dialect common.Dialect) string {
+	switch dialect.Name() {
+	case "mysql":
+		return "CURRENT_TIMESTAMP(6)"
+	case "postgres":
+		return "clock_timestamp()" // now() would give every change of a transaction the same time
+	case "sqlite", "sqlite3":
+		return "strftime('%Y-%m-%d %H:%M:%f', 'now')"
+	}
+	return "CURRENT_TIMESTAMP"
+}
+
+// historyTriggers builds the triggers recording the row versions: inserts and updates
+// add the new version, updates and deletes close the current one.
+func historyTriggers(dialect common.Dialect, model, history *schema.Model) ([]Trigger, error) {
+	var columns, newValues, match []string
+	for _, field := range model.Fields {
+		if field.IsIgnored {
+			continue
+		}
+		columns = append(columns, dialect.Quote(field.DBName))
+		newValues = append(newValues, "NEW."+dialect.Quote(field.DBName))
+	}
+	for _, pk := range model.PrimaryKeys {
+		match = append(match, fmt.Sprintf("%s = OLD.%s", dialect.Quote(pk.DBName), dialect.Quote(pk.DBName)))
+	}
+	table, now := quoteTable(dialect, history), periodNow(dialect)
+	insertVersion := fmt.Sprintf("INSERT INTO %s (%s, %s) VALUES (%s, %s);",
+		table, strings.Join(columns, ", "), dialect.Quote(ValidFromColumn), strings.Join(newValues, ", "), now)
+	closeVersion := fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s AND %s IS NULL;",
+		table, dialect.Quote(ValidToColumn), now, strings.Join(match, " AND "), dialect.Quote(ValidToColumn))
+
+	name := history.TableName
+	switch dialect.Name() {
+	case "postgres":
+		body := fmt.Sprintf("BEGIN\n  IF TG_OP IN ('UPDATE', 'DELETE') THEN\n    %s\n  END IF;\n"+
+			"  IF TG_OP IN ('INSERT', 'UPDATE') THEN\n    %s\n  END IF;\n  RETURN NULL;\nEND;", closeVersion, insertVersion)
+		return []Trigger{{
+			Name: name, Table: model.QualifiedTableName(), Timing: TriggerAfter,
+			Events: []TriggerEvent{TriggerInsert, TriggerUpdate, TriggerDelete},
+			Bodies: map[string]string{"postgres": body},
+		}}, nil
+	case "mysql", "sqlite", "sqlite3":
+		block := func(statements ...string) map[string]string {
+			return map[string]string{dialect.Name(): "BEGIN\n  " + strings.Join(statements, "\n  ") + "\nEND"}
+		}
+		return []Trigger{
+			{Name: name + "_ins", Table: model.QualifiedTableName(), Timing: TriggerAfter, Events: []TriggerEvent{TriggerInsert}, Bodies: block(insertVersion)},
+			{Name: name + "_upd", Table: model.QualifiedTableName(), Timing: TriggerAfter, Events: []TriggerEvent{TriggerUpdate}, Bodies: block(closeVersion, insertVersion)},
+			{Name: name + "_del", Table: model.QualifiedTableName(), Timing: TriggerAfter, Events: []TriggerEvent{TriggerDelete}, Bodies: block(closeVersion)},
+		}, nil
+	}
+	return nil, fmt.Errorf("temporal model %s with %s: %w", model.Name, dialect.Name(), ErrUnsupportedDialect)
+}
+
+// asOfSource returns the FROM source of a find and its conditions: the model's table, or
+// with AsOf the versions valid at that time. The history table is aliased as the model's
+// table, so that conditions qualified with the table name ("items.price > ?") still apply.
+func asOfSource(dialect common.Dialect, model *schema.Model, options queryOptions, whereClauses []string, whereArgs []any) (string, []string, []any, error) {
+	if options.asOf == nil {
+		return quoteTable(dialect, model), whereClauses, whereArgs, nil
+	}
+	table, ok := historyTableOf(model)
+	if !ok {
+		return "", nil, nil, fmt.Errorf("AsOf: model %s is not temporal (see TemporalModel)", model.Name)
+	}
+	if dialect.Name() == "sqlserver" {
+		// A literal keeps the numbering of the bind variables of the WHERE clause
+		return fmt.Sprintf("%s FOR SYSTEM_TIME AS OF '%s'", quoteTable(dialect, model), options.asOf.UTC().Format("2006-01-02T15:04:05.9999999")),
+			whereClauses, whereArgs, nil
+	}
+	from, to := dialect.Quote(ValidFromColumn), dialect.Quote(ValidToColumn)
+	whereClauses = append(whereClauses, fmt.Sprintf("%s <= %s AND (%s IS NULL OR %s > %s)",
+		from, dialect.BindVar(len(whereArgs)+1), to, to, dialect.BindVar(len(whereArgs)+2)))
+	whereArgs = append(whereArgs, *options.asOf, *options.asOf)
+	return quoteTable(dialect, historyModel(model, table)) + " AS " + dialect.Quote(model.TableName), whereClauses, whereArgs, nil
+}
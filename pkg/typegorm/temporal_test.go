@@ -0,0 +1,108 @@
+package typegorm
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pricedItem struct {
+	ID    uint `typegorm:"primaryKey;autoIncrement"`
+	Name  string
+	Price int
+}
+
+func (pricedItem) HistoryTable() string { return "" }
+
+func TestTemporalSQL_Emulated(t *testing.T) {
+	db, _ := newMockDBWithDialect("mysql")
+	model, err := db.GetModel(&pricedItem{})
+	require.NoError(t, err)
+
+	statements, err := TemporalSQL(db.source.Dialect(), model)
+	require.NoError(t, err)
+	require.Len(t, statements, 7, "history table and three DROP/CREATE trigger pairs")
+	assert.Equal(t, "CREATE TABLE IF NOT EXISTS `priced_items_history` (`id` INT, `name` TEXT, `price` INT, `valid_from` DATETIME(6) NOT NULL, `valid_to` DATETIME(6) NULL);", statements[0])
+	assert.Contains(t, statements[2], "CREATE TRIGGER `priced_items_history_ins` AFTER INSERT ON `priced_items`")
+	assert.Contains(t, statements[4], "UPDATE `priced_items_history` SET `valid_to` = CURRENT_TIMESTAMP(6) WHERE `id` = OLD.`id` AND `valid_to` IS NULL;")
+	assert.Contains(t, statements[4], "VALUES (NEW.`id`, NEW.`name`, NEW.`price`, CURRENT_TIMESTAMP(6));")
+	assert.NotContains(t, statements[6], "INSERT", "deletes only close the current version")
+
+	plain, err := db.GetModel(&maskUser{})
+	require.NoError(t, err)
+	statements, err = TemporalSQL(db.source.Dialect(), plain)
+	require.NoError(t, err)
+	assert.Nil(t, statements)
+}
+
+func TestTemporalSQL_Postgres(t *testing.T) {
+	db, _ := newMockDBWithDialect("postgres")
+	model, err := db.GetModel(&pricedItem{})
+	require.NoError(t, err)
+
+	statements, err := TemporalSQL(db.source.Dialect(), model)
+	require.NoError(t, err)
+	require.Len(t, statements, 4, "history table, trigger function, DROP/CREATE trigger")
+	assert.Contains(t, statements[1], "IF TG_OP IN ('UPDATE', 'DELETE') THEN")
+	assert.Contains(t, statements[1], "clock_timestamp()")
+	assert.Contains(t, statements[3], "AFTER INSERT OR UPDATE OR DELETE ON `priced_items`")
+}
+
+func TestTemporalSQL_SystemVersioned(t *testing.T) {
+	db, _ := newMockDBWithDialect("sqlserver")
+	model, err := db.GetModel(&pricedItem{})
+	require.NoError(t, err)
+
+	statements, err := TemporalSQL(db.source.Dialect(), model)
+	require.NoError(t, err)
+	require.Len(t, statements, 1)
+	assert.True(t, strings.HasPrefix(statements[0], "IF OBJECTPROPERTY(OBJECT_ID(N'`priced_items`'), 'TableTemporalType') = 0"))
+	assert.Contains(t, statements[0], "PERIOD FOR SYSTEM_TIME (`valid_from`, `valid_to`)")
+	assert.Contains(t, statements[0], "HISTORY_TABLE = `dbo`.`priced_items_history`")
+	assert.Contains(t, statements[0], "CONVERT(DATETIME2, ''9999-12-31 23:59:59.9999999'')", "quotes escaped inside EXEC")
+}
+
+func TestFind_AsOf(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var items []pricedItem
+	require.NoError(t, db.Find(ctx, &items, map[string]any{"name": "Lamp"}, AsOf(at)).Error)
+	stmt := source.lastStatement()
+	assert.Equal(t, "SELECT `id`, `name`, `price` FROM `priced_items_history` AS `priced_items` WHERE `name` = ? AND `valid_from` <= ? AND (`valid_to` IS NULL OR `valid_to` > ?)", stmt.SQL)
+	assert.Equal(t, []any{"Lamp", at, at}, stmt.Args)
+
+	var item pricedItem
+	db.FindFirst(ctx, &item, AsOf(at))
+	assert.Contains(t, source.lastStatement().SQL, "FROM `priced_items_history` AS `priced_items` WHERE `valid_from` <= ?")
+
+	var users []maskUser
+	assert.ErrorContains(t, db.Find(ctx, &users, AsOf(at)).Error, "is not temporal")
+}
+
+func TestFind_AsOfKeepsTableQualifiedConditions(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var items []pricedItem
+	require.NoError(t, db.Find(ctx, &items, SQL("priced_items.price > ?", 10), AsOf(at)).Error)
+	assert.Equal(t, "SELECT `id`, `name`, `price` FROM `priced_items_history` AS `priced_items` WHERE (priced_items.price > ?) AND `valid_from` <= ? AND (`valid_to` IS NULL OR `valid_to` > ?)", source.lastStatement().SQL)
+
+	err := db.Find(ctx, &items, Joins("JOIN suppliers ON suppliers.id = priced_items.id"), AsOf(at)).Error
+	assert.ErrorContains(t, err, "Joins cannot be combined with AsOf")
+}
+
+func TestFind_AsOfSystemVersioned(t *testing.T) {
+	db, source := newMockDBWithDialect("sqlserver")
+	at := time.Date(2026, 3, 1, 12, 0, 0, 500, time.UTC)
+
+	var items []pricedItem
+	require.NoError(t, db.Find(context.Background(), &items, AsOf(at)).Error)
+	assert.Contains(t, source.lastStatement().SQL, "FROM `priced_items` FOR SYSTEM_TIME AS OF '2026-03-01T12:00:00.0000005'")
+}
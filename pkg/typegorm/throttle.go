@@ -0,0 +1,297 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+)
+
+// --- Write Throttle ---
+
+// The write throttle limits the rate of the write statements (INSERT, UPDATE, DELETE,
+// REPLACE, MERGE) sent to the primary with token buckets, one for the whole DB and one
+// per throttled model: a write takes a token of each bucket that applies and waits
+// until they refill. It smooths large backfills and protects the primary from bursts,
+// and its limits can be changed at any time, e.g. from an admin endpoint:
+//
+//	db.ThrottleWrites(500, 50)                       // At most 500 writes/s on the DB
+//	db.ThrottleModelWrites(&AuditLog{}, 100, 10)     // and 100 writes/s on audit_logs
+//	defer db.ThrottleModelWrites(&AuditLog{}, 0, 0)  // Remove the model limit
+//
+// Each statement is one token, so a batch insert counts once. Writes of transactions
+// are throttled too when a limit was set before the transaction began.
+
+// Metrics reported to the MetricsCollector by the write throttle.
+const (
+	MetricThrottleWait     = "typegorm_write_throttle_wait_seconds"   // Duration: time waited by a throttled write
+	MetricThrottledWrites  = "typegorm_write_throttle_delayed_total"  // Counter: writes that had to wait
+	MetricThrottleCanceled = "typegorm_write_throttle_canceled_total" // Counter: writes whose context ended first
+)
+
+// ThrottleWrites limits the writes of the DB to perSecond statements per second, with
+// bursts of up to burst statements (at least 1); perSecond <= 0 removes the limit.
+// It is safe to call while the DB is in use.
+func (db *DB) ThrottleWrites(perSecond float64, burst int) {
+	db.throttle.setLimit("", perSecond, burst)
+}
+
+// ThrottleModelWrites limits the writes to the table of model like ThrottleWrites; the
+// limit applies on top of the DB one. It is safe to call while the DB is in use.
+func (db *DB) ThrottleModelWrites(model any, perSecond float64, burst int) error {
+	schemaModel, err := db.parser.Parse(model)
+	if err != nil {
+		return fmt.Errorf("failed to parse schema for %T: %w", model, err)
+	}
+	db.throttle.setLimit(schemaModel.TableName, perSecond, burst)
+	return nil
+}
+
+// writeThrottle holds the token buckets of the DB ("") and of the throttled tables.
+type writeThrottle struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+func newWriteThrottle() *writeThrottle {
+	return &writeThrottle{buckets: make(map[string]*tokenBucket)}
+}
+
+func (t *writeThrottle) setLimit(table string, perSecond float64, burst int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if perSecond <= 0 {
+		delete(t.buckets, table)
+		fmt.Printf("Write throttle removed for %s.\n", throttleScope(table))
+		return
+	}
+	burst = max(burst, 1)
+	if bucket, ok := t.buckets[table]; ok {
+		bucket.rate, bucket.burst = perSecond, float64(burst)
+		bucket.tokens = min(bucket.tokens, bucket.burst)
+	} else {
+		t.buckets[table] = &tokenBucket{rate: perSecond, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+	}
+	fmt.Printf("Write throttle for %s: %g writes/s, burst %d.\n", throttleScope(table), perSecond, burst)
+}
+
+func throttleScope(table string) string {
+	if table == "" {
+		return "the DB"
+	}
+	return "table " + table
+}
+
+// enabled reports whether any limit is set.
+func (t *writeThrottle) enabled() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.buckets) > 0
+}
+
+// wait takes the tokens of a write to table and blocks until they are available. It
+// fails with ErrWriteThrottled, giving the tokens back, if ctx ends first.
+func (t *writeThrottle) wait(ctx context.Context, table string, metrics MetricsCollector) error {
+	now := time.Now()
+	t.mu.Lock()
+	var taken []*tokenBucket
+	var delay time.Duration
+	scopes := []string{""}
+	if table != "" {
+		scopes = append(scopes, table)
+	}
+	for _, scope := range scopes {
+		if bucket, ok := t.buckets[scope]; ok {
+			delay = max(delay, bucket.reserve(now))
+			taken = append(taken, bucket)
+		}
+	}
+	t.mu.Unlock()
+	if len(taken) == 0 {
+		return nil
+	}
+	labels := map[string]string{"table": table}
+	if delay <= 0 {
+		observeDuration(metrics, MetricThrottleWait, labels, 0)
+		return nil
+	}
+
+	giveBack := func(cause error) error {
+		t.mu.Lock()
+		for _, bucket := range taken {
+			bucket.tokens = min(bucket.tokens+1, bucket.burst)
+		}
+		t.mu.Unlock()
+		incCounter(metrics, MetricThrottleCanceled, labels, 1)
+		return fmt.Errorf("%w: write to %s: %w", ErrWriteThrottled, throttleScope(table), cause)
+	}
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(delay)) {
+		return giveBack(fmt.Errorf("waiting %s would exceed the context deadline", delay))
+	}
+	incCounter(metrics, MetricThrottledWrites, labels, 1)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return giveBack(context.Cause(ctx))
+	case <-timer.C:
+	}
+	observeDuration(metrics, MetricThrottleWait, labels, time.Since(now))
+	return nil
+}
+
+// tokenBucket refills rate tokens per second up to burst. Tokens go negative when
+// writes reserve them ahead of time, which queues the writes in order.
+type tokenBucket struct {
+	rate, burst, tokens float64
+	last                time.Time
+}
+
+// reserve takes a token at now and returns how long to wait until it is refilled.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	if now.After(b.last) {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+	}
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// writeStatementTable reports whether query writes, and the table it writes to (empty
+// when it cannot be told, e.g. for a CTE).
+func writeStatementTable(query string) (string, bool) {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "", false
+	}
+	var rest []string
+	switch strings.ToUpper(fields[0]) {
+	case "INSERT", "REPLACE", "MERGE":
+		rest = skipKeywords(fields[1:], "LOW_PRIORITY", "DELAYED", "HIGH_PRIORITY", "IGNORE", "INTO")
+	case "UPDATE":
+		rest = skipKeywords(fields[1:], "LOW_PRIORITY", "IGNORE")
+	case "DELETE":
+		rest = skipKeywords(fields[1:], "LOW_PRIORITY", "QUICK", "IGNORE", "FROM")
+	case "WITH":
+		return "", !isReadStatement(query)
+	default:
+		return "", false
+	}
+	if len(rest) == 0 {
+		return "", true
+	}
+	table, _, _ := strings.Cut(rest[0], "(") // INSERT INTO t(a, b)
+	if i := strings.LastIndex(table, "."); i >= 0 {
+		table = table[i+1:] // Schema-qualified
+	}
+	return strings.Trim(table, "`\"[]"), true
+}
+
+// skipKeywords drops the leading fields that are one of keywords.
+func skipKeywords(fields []string, keywords ...string) []string {
+	for len(fields) > 0 {
+		matched := false
+		for _, keyword := range keywords {
+			if strings.EqualFold(fields[0], keyword) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			break
+		}
+		fields = fields[1:]
+	}
+	return fields
+}
+
+// throttleSource wraps ds so that its writes wait for the throttle, if any limit is set.
+func (db *DB) throttleSource(ds common.DataSource) common.DataSource {
+	if !db.throttle.enabled() {
+		return ds
+	}
+	return &throttledSource{DataSource: ds, throttle: db.throttle, metrics: db.metrics}
+}
+
+// throttleTx wraps tx like throttleSource.
+func (db *DB) throttleTx(tx common.Tx) common.Tx {
+	if !db.throttle.enabled() {
+		return tx
+	}
+	return &throttledTx{Tx: tx, throttle: db.throttle, metrics: db.metrics}
+}
+
+// throttleStatement waits for the throttle when query writes.
+func throttleStatement(ctx context.Context, t *writeThrottle, metrics MetricsCollector, query string) error {
+	table, ok := writeStatementTable(query)
+	if !ok {
+		return nil
+	}
+	return t.wait(ctx, table, metrics)
+}
+
+type throttledSource struct {
+	common.DataSource
+	throttle *writeThrottle
+	metrics  MetricsCollector
+}
+
+func (s *throttledSource) Exec(ctx context.Context, query string, args ...any) (common.Result, error) {
+	if err := throttleStatement(ctx, s.throttle, s.metrics, query); err != nil {
+		return nil, err
+	}
+	return s.DataSource.Exec(ctx, query, args...)
+}
+
+func (s *throttledSource) Query(ctx context.Context, query string, args ...any) (common.Rows, error) {
+	if err := throttleStatement(ctx, s.throttle, s.metrics, query); err != nil {
+		return nil, err
+	}
+	return s.DataSource.Query(ctx, query, args...)
+}
+
+func (s *throttledSource) QueryRow(ctx context.Context, query string, args ...any) common.RowScanner {
+	if err := throttleStatement(ctx, s.throttle, s.metrics, query); err != nil {
+		return &firstRowScanner{err: err}
+	}
+	return s.DataSource.QueryRow(ctx, query, args...)
+}
+
+type throttledTx struct {
+	common.Tx
+	throttle *writeThrottle
+	metrics  MetricsCollector
+}
+
+func (t *throttledTx) Exec(ctx context.Context, query string, args ...any) (common.Result, error) {
+	if err := throttleStatement(ctx, t.throttle, t.metrics, query); err != nil {
+		return nil, err
+	}
+	return t.Tx.Exec(ctx, query, args...)
+}
+
+func (t *throttledTx) Query(ctx context.Context, query string, args ...any) (common.Rows, error) {
+	if err := throttleStatement(ctx, t.throttle, t.metrics, query); err != nil {
+		return nil, err
+	}
+	return t.Tx.Query(ctx, query, args...)
+}
+
+func (t *throttledTx) QueryRow(ctx context.Context, query string, args ...any) common.RowScanner {
+	if err := throttleStatement(ctx, t.throttle, t.metrics, query); err != nil {
+		return &firstRowScanner{err: err}
+	}
+	return t.Tx.QueryRow(ctx, query, args...)
+}
+
+// SupportsConcurrentUse forwards the capability of the wrapped transaction.
+func (t *throttledTx) SupportsConcurrentUse() bool {
+	c, ok := t.Tx.(concurrentTx)
+	return ok && c.SupportsConcurrentUse()
+}
@@ -0,0 +1,103 @@
+package typegorm
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingCollector keeps the metrics it receives.
+type recordingCollector struct {
+	mu        sync.Mutex
+	counters  map[string]float64
+	durations map[string][]time.Duration
+}
+
+func newRecordingCollector() *recordingCollector {
+	return &recordingCollector{counters: map[string]float64{}, durations: map[string][]time.Duration{}}
+}
+
+func (c *recordingCollector) IncCounter(name string, labels map[string]string, delta float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counters[name+"{"+labels["table"]+"}"] += delta
+}
+
+func (c *recordingCollector) ObserveDuration(name string, labels map[string]string, d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.durations[name+"{"+labels["table"]+"}"] = append(c.durations[name+"{"+labels["table"]+"}"], d)
+}
+
+func TestWriteStatementTable(t *testing.T) {
+	for query, want := range map[string]string{
+		"INSERT INTO `mask_users` (`name`) VALUES (?)":   "mask_users",
+		"insert ignore into app.`orders`(id) VALUES (?)": "orders",
+		"UPDATE `mask_users` SET `age` = ?":              "mask_users",
+		"DELETE FROM \"public\".\"users\" WHERE id = $1": "users",
+		"REPLACE INTO counters VALUES (?, ?)":            "counters",
+	} {
+		table, ok := writeStatementTable(query)
+		assert.True(t, ok, query)
+		assert.Equal(t, want, table, query)
+	}
+	_, ok := writeStatementTable("SELECT * FROM `mask_users`")
+	assert.False(t, ok)
+	table, ok := writeStatementTable("WITH old AS (SELECT id FROM t) DELETE FROM t WHERE id IN (SELECT id FROM old)")
+	assert.True(t, ok, "a CTE feeding a write is throttled by the DB limit")
+	assert.Empty(t, table)
+}
+
+func TestThrottleWrites_SpacesWritesAndSkipsReads(t *testing.T) {
+	db, source := newMockDB()
+	collector := newRecordingCollector()
+	db.UseMetricsCollector(collector)
+	db.ThrottleWrites(20, 1) // One write every 50ms
+	ctx := context.Background()
+
+	started := time.Now()
+	for id := uint(1); id <= 3; id++ {
+		require.NoError(t, db.Delete(ctx, &maskUser{ID: id}).Error)
+	}
+	assert.GreaterOrEqual(t, time.Since(started), 90*time.Millisecond, "the burst of 1 spaces the 2 following writes")
+	assert.Equal(t, 2.0, collector.counters[MetricThrottledWrites+"{mask_users}"])
+	assert.Len(t, collector.durations[MetricThrottleWait+"{mask_users}"], 3)
+
+	started = time.Now()
+	var users []maskUser
+	for range 5 {
+		require.NoError(t, db.Find(ctx, &users).Error)
+	}
+	assert.Less(t, time.Since(started), 40*time.Millisecond, "reads are not throttled")
+	assert.Len(t, source.Statements(), 8)
+}
+
+func TestThrottleModelWrites_DeadlineAndRuntimeChanges(t *testing.T) {
+	db, _ := newMockDB()
+	collector := newRecordingCollector()
+	db.UseMetricsCollector(collector)
+	require.NoError(t, db.ThrottleModelWrites(&maskUser{}, 1, 1))
+	ctx := context.Background()
+
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Delete(ctx, &maskUser{ID: 1}).Error, "the burst token")
+	require.NoError(t, tx.Commit())
+
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	err = db.Delete(waitCtx, &maskUser{ID: 2}).Error
+	assert.ErrorIs(t, err, ErrWriteThrottled)
+	assert.Less(t, time.Since(started), 40*time.Millisecond, "fails without waiting past the deadline")
+	assert.Equal(t, 1.0, collector.counters[MetricThrottleCanceled+"{mask_users}"])
+
+	assert.NoError(t, db.Exec(waitCtx, "DELETE FROM `sessions`").Error, "other tables are not limited")
+
+	require.NoError(t, db.ThrottleModelWrites(&maskUser{}, 0, 0))
+	assert.NoError(t, db.Delete(waitCtx, &maskUser{ID: 2}).Error, "the limit is removed")
+}
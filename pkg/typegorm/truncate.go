@@ -0,0 +1,206 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Truncate ---
+
+// truncateOptions holds the optional behaviors of Truncate.
+type truncateOptions struct {
+	restartIdentity bool // Reset identity/auto-increment counters
+	cascade         bool // Postgres: also truncate tables referencing the given ones
+}
+
+// TruncateOption defines a function type that modifies truncateOptions.
+type TruncateOption func(*truncateOptions)
+
+// RestartIdentity resets identity/auto-increment counters of the truncated tables
+// (always the case for TRUNCATE on MySQL).
+func RestartIdentity() TruncateOption {
+	return func(opts *truncateOptions) { opts.restartIdentity = true }
+}
+
+// Cascade also truncates tables that reference the given ones (Postgres only).
+func Cascade() TruncateOption {
+	return func(opts *truncateOptions) { opts.cascade = true }
+}
+
+// Truncate removes all rows of the tables of the given models, primarily for test setup
+// and data resets. Models and TruncateOptions can be mixed, like Find conditions and options:
+//
+//	db.Truncate(ctx, &Order{}, &OrderLine{}, &User{}, typegorm.RestartIdentity())
+//
+// Tables are processed in foreign-key-safe order (referencing tables first), derived
+// from `references` tags. Postgres truncates all tables in one statement; MySQL disables
+// FOREIGN_KEY_CHECKS for the duration, on a reserved connection (the data source must
+// implement common.ConnPinner); SQLite and other dialects use DELETE FROM.
+func (db *DB) Truncate(ctx context.Context, values ...any) error {
+	var options truncateOptions
+	var models []*schema.Model
+	for _, value := range values {
+		if opt, ok := value.(TruncateOption); ok {
+			opt(&options)
+			continue
+		}
+		model, err := db.GetModel(value)
+		if err != nil {
+			return fmt.Errorf("truncate: failed to parse schema for type %T: %w", value, err)
+		}
+		models = append(models, model)
+	}
+	if len(models) == 0 {
+		return nil
+	}
+
+	ordered := truncateOrder(models)
+	dialect := db.source.Dialect()
+	tables := make([]string, len(ordered))
+	for i, model := range ordered {
+		tables[i] = quoteTable(dialect, model)
+	}
+
+	var statements []string
+	switch dialect.Name() {
+	case "postgres":
+		stmt := "TRUNCATE TABLE " + strings.Join(tables, ", ")
+		if options.restartIdentity {
+			stmt += " RESTART IDENTITY"
+		}
+		if options.cascade {
+			stmt += " CASCADE"
+		}
+		statements = append(statements, stmt)
+	case "mysql":
+		for _, table := range tables {
+			statements = append(statements, "TRUNCATE TABLE "+table)
+		}
+		return db.truncateWithoutForeignKeyChecks(ctx, statements)
+	case "sqlite", "sqlite3":
+		for _, table := range tables {
+			statements = append(statements, "DELETE FROM "+table)
+		}
+		if options.restartIdentity {
+			for _, model := range ordered {
+				statements = append(statements, fmt.Sprintf("DELETE FROM sqlite_sequence WHERE name = '%s'", strings.ReplaceAll(model.TableName, "'", "''")))
+			}
+		}
+	default:
+		for _, table := range tables {
+			statements = append(statements, "DELETE FROM "+table)
+		}
+		if options.restartIdentity {
+			fmt.Printf("Warning: RestartIdentity is not supported for dialect %s; counters are kept\n", dialect.Name())
+		}
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("truncate: %w", err)
+	}
+	for _, stmt := range statements {
+		logSQL("Executing SQL: %s\n", stmt)
+		if _, err := tx.source.Exec(ctx, stmt); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("truncate: failed to execute %q: %w", stmt, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("truncate: commit failed: %w", err)
+	}
+	return nil
+}
+
+// truncateWithoutForeignKeyChecks runs the MySQL TRUNCATEs on one reserved connection
+// with FOREIGN_KEY_CHECKS off (TRUNCATE commits implicitly, so a Tx would not help).
+// The checks are turned back on for that session whatever the outcome, even when ctx
+// is done; if that fails, the connection is discarded rather than returned to the
+// pool with the checks off.
+func (db *DB) truncateWithoutForeignKeyChecks(ctx context.Context, statements []string) (err error) {
+	conn, err := db.pinConn(ctx)
+	if err != nil {
+		return fmt.Errorf("truncate: %w", err)
+	}
+	defer func() {
+		const restore = "SET FOREIGN_KEY_CHECKS = 1"
+		logSQL("Executing SQL: %s\n", restore)
+		if _, restoreErr := conn.conn.Exec(context.WithoutCancel(ctx), restore); restoreErr != nil {
+			fmt.Printf("Warning: could not restore FOREIGN_KEY_CHECKS, discarding the connection: %v\n", restoreErr)
+			_ = conn.conn.Discard()
+			err = errors.Join(err, fmt.Errorf("truncate: failed to restore FOREIGN_KEY_CHECKS: %w", restoreErr))
+			return
+		}
+		_ = conn.conn.Close()
+	}()
+	for _, stmt := range append([]string{"SET FOREIGN_KEY_CHECKS = 0"}, statements...) {
+		logSQL("Executing SQL: %s\n", stmt)
+		if _, err := conn.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("truncate: failed to execute %q: %w", stmt, err)
+		}
+	}
+	return nil
+}
+
+// truncateOrder sorts models so that tables referencing others come first.
+// Cycles fall back to the given order for the remaining tables.
+func truncateOrder(models []*schema.Model) []*schema.Model {
+	byTable := make(map[string]*schema.Model, len(models))
+	for _, model := range models {
+		byTable[model.TableName] = model
+	}
+	// referencedBy[t] = tables (within the set) holding a foreign key to t
+	referencedBy := make(map[string][]string)
+	for _, model := range models {
+		for _, field := range model.Fields {
+			if field.References == "" {
+				continue
+			}
+			target, _, _ := strings.Cut(field.References, ".")
+			if _, ok := byTable[target]; ok && target != model.TableName {
+				referencedBy[target] = append(referencedBy[target], model.TableName)
+			}
+		}
+	}
+
+	ordered := make([]*schema.Model, 0, len(models))
+	done := make(map[string]bool, len(models))
+	for len(ordered) < len(models) {
+		progressed := false
+		for _, model := range models {
+			if done[model.TableName] {
+				continue
+			}
+			ready := true
+			for _, child := range referencedBy[model.TableName] {
+				if !done[child] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				ordered = append(ordered, model)
+				done[model.TableName] = true
+				progressed = true
+			}
+		}
+		if !progressed {
+			remaining := make([]string, 0)
+			for _, model := range models {
+				if !done[model.TableName] {
+					remaining = append(remaining, model.TableName)
+					ordered = append(ordered, model)
+					done[model.TableName] = true
+				}
+			}
+			sort.Strings(remaining)
+			fmt.Printf("Warning: circular foreign keys between %s; truncating in the given order\n", strings.Join(remaining, ", "))
+		}
+	}
+	return ordered
+}
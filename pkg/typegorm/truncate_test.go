@@ -0,0 +1,76 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type truncCustomer struct {
+	ID uint `typegorm:"primaryKey;autoIncrement"`
+}
+
+type truncInvoice struct {
+	ID         uint `typegorm:"primaryKey;autoIncrement"`
+	CustomerID uint `typegorm:"references:trunc_customers.id"`
+}
+
+type truncPayment struct {
+	ID        uint `typegorm:"primaryKey;autoIncrement"`
+	InvoiceID uint `typegorm:"references:trunc_invoices.id"`
+}
+
+func sqlOf(stmts []mockStatement) []string {
+	out := make([]string, len(stmts))
+	for i, s := range stmts {
+		out[i] = s.SQL
+	}
+	return out
+}
+
+func TestTruncate_Postgres(t *testing.T) {
+	db, source := newMockDBWithDialect("postgres")
+	err := db.Truncate(context.Background(), &truncCustomer{}, &truncInvoice{}, &truncPayment{}, RestartIdentity())
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"BEGIN",
+		"TRUNCATE TABLE `trunc_payments`, `trunc_invoices`, `trunc_customers` RESTART IDENTITY",
+		"COMMIT",
+	}, sqlOf(source.Statements()))
+}
+
+func TestTruncate_MySQL(t *testing.T) {
+	db, source := newMockDBWithDialect("mysql")
+	require.NoError(t, db.Truncate(context.Background(), &truncInvoice{}, &truncCustomer{}))
+	assert.Equal(t, []string{
+		"PIN",
+		"SET FOREIGN_KEY_CHECKS = 0",
+		"TRUNCATE TABLE `trunc_invoices`",
+		"TRUNCATE TABLE `trunc_customers`",
+		"SET FOREIGN_KEY_CHECKS = 1",
+		"RELEASE",
+	}, sqlOf(source.Statements()))
+}
+
+func TestTruncate_MySQLRestoresForeignKeyChecksOnFailure(t *testing.T) {
+	db, source := newMockDBWithDialect("mysql")
+	source.execErrs = []error{nil, errors.New("table is locked")}
+	err := db.Truncate(context.Background(), &truncInvoice{}, &truncCustomer{})
+	assert.ErrorContains(t, err, "table is locked")
+	assert.Equal(t, []string{
+		"PIN",
+		"SET FOREIGN_KEY_CHECKS = 0",
+		"TRUNCATE TABLE `trunc_invoices`",
+		"SET FOREIGN_KEY_CHECKS = 1",
+		"RELEASE",
+	}, sqlOf(source.Statements()))
+
+	source.statements = nil
+	source.execErrs = []error{errors.New("table is locked"), errors.New("connection lost")}
+	err = db.Truncate(context.Background(), &truncInvoice{})
+	assert.ErrorContains(t, err, "failed to restore FOREIGN_KEY_CHECKS")
+	assert.Equal(t, "DISCARD", source.lastStatement().SQL, "a session with the checks off is not pooled")
+}
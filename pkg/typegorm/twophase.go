@@ -0,0 +1,141 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+)
+
+// --- Two-Phase Commit ---
+
+// SupportsTwoPhaseCommit reports whether the connection can prepare transactions for
+// an external transaction manager: DataSources implementing common.TwoPhaseCommitter
+// (MySQL XA) and Postgres (PREPARE TRANSACTION, requires max_prepared_transactions > 0).
+func (db *DB) SupportsTwoPhaseCommit() bool {
+	if _, ok := db.source.(common.TwoPhaseCommitter); ok {
+		return true
+	}
+	return db.source.Dialect().Name() == "postgres"
+}
+
+// BeginTwoPhase starts a transaction identified by the global transaction ID xid.
+// Do the work through the returned Tx, then call tx.Prepare; the transaction manager
+// later resolves it with CommitPrepared or RollbackPrepared:
+//
+//	tx, err := db.BeginTwoPhase(ctx, "order-42")
+//	tx.Create(ctx, &payment)
+//	err = tx.Prepare(ctx)                 // Phase 1
+//	err = db.CommitPrepared(ctx, "order-42") // Phase 2
+func (db *DB) BeginTwoPhase(ctx context.Context, xid string) (*Tx, error) {
+	if err := common.ValidateXID(xid); err != nil {
+		return nil, err
+	}
+
+	var source common.Tx
+	if committer, ok := db.poolSource(ctx).(common.TwoPhaseCommitter); ok {
+		xaTx, err := committer.BeginTwoPhase(ctx, xid)
+		if err != nil {
+			return nil, fmt.Errorf("failed to begin two-phase transaction: %w", err)
+		}
+		source = xaTx
+	} else if db.source.Dialect().Name() == "postgres" {
+		pgTx, err := db.conn(ctx).BeginTx(ctx, sql.TxOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to begin two-phase transaction: %w", err)
+		}
+		source = &pgPreparableTx{Tx: pgTx, xid: xid}
+	} else {
+		return nil, fmt.Errorf("two-phase commit with %s: %w", db.source.Dialect().Name(), ErrUnsupportedDialect)
+	}
+
+	fmt.Printf("Two-phase transaction %s begun.\n", xid)
+	tx := &Tx{
+		source:    source,
+		parser:    db.parser,
+		dialect:   db.source.Dialect(),
+		relations: db.relations,
+		machines:  db.machines,
+		callbacks: db.callbacks,
+		cache:     db.cache,
+		maxRows:   db.config.Query.MaxRows,
+	}
+	db.startWatchdog(tx)
+	db.trackTx(tx)
+	if err := applySessionSettings(ctx, tx); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	return tx, nil
+}
+
+// Prepare runs the first phase of a two-phase commit. Afterwards the Tx cannot be used
+// anymore; the outcome is decided with DB.CommitPrepared or DB.RollbackPrepared.
+func (tx *Tx) Prepare(ctx context.Context) error {
+	preparable, ok := tx.source.(common.PreparableTx)
+	if !ok {
+		return fmt.Errorf("transaction was not started with BeginTwoPhase")
+	}
+	if err := tx.stopWatchdog(); err != nil {
+		return err
+	}
+	fmt.Println("Preparing two-phase transaction...")
+	if err := preparable.Prepare(ctx); err != nil {
+		return fmt.Errorf("failed to prepare transaction: %w", err)
+	}
+	return nil
+}
+
+// CommitPrepared commits a prepared transaction.
+func (db *DB) CommitPrepared(ctx context.Context, xid string) error {
+	return db.resolvePrepared(ctx, xid, true)
+}
+
+// RollbackPrepared rolls back a prepared transaction.
+func (db *DB) RollbackPrepared(ctx context.Context, xid string) error {
+	return db.resolvePrepared(ctx, xid, false)
+}
+
+func (db *DB) resolvePrepared(ctx context.Context, xid string, commit bool) error {
+	if err := common.ValidateXID(xid); err != nil {
+		return err
+	}
+	if committer, ok := db.poolSource(ctx).(common.TwoPhaseCommitter); ok {
+		if commit {
+			return committer.CommitPrepared(ctx, xid)
+		}
+		return committer.RollbackPrepared(ctx, xid)
+	}
+	if db.source.Dialect().Name() != "postgres" {
+		return fmt.Errorf("two-phase commit with %s: %w", db.source.Dialect().Name(), ErrUnsupportedDialect)
+	}
+	stmt := "ROLLBACK PREPARED '" + xid + "'"
+	if commit {
+		stmt = "COMMIT PREPARED '" + xid + "'"
+	}
+	logSQL("Executing SQL: %s\n", stmt)
+	if _, err := db.conn(ctx).Exec(ctx, stmt); err != nil {
+		return fmt.Errorf("failed to resolve prepared transaction %s: %w", xid, err)
+	}
+	return nil
+}
+
+// pgPreparableTx adds PREPARE TRANSACTION to a regular Postgres transaction.
+type pgPreparableTx struct {
+	common.Tx
+	xid string
+}
+
+func (t *pgPreparableTx) Prepare(ctx context.Context) error {
+	if _, err := t.Tx.Exec(ctx, "PREPARE TRANSACTION '"+t.xid+"'"); err != nil {
+		_ = t.Tx.Rollback()
+		return err
+	}
+	// The session is no longer in a transaction; end the database/sql Tx to release
+	// the connection (the server only notes that no transaction is in progress).
+	if err := t.Tx.Commit(); err != nil {
+		fmt.Printf("Note: releasing connection after PREPARE TRANSACTION: %v\n", err)
+	}
+	return nil
+}
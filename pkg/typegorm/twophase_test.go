@@ -0,0 +1,37 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoPhaseCommit_Postgres(t *testing.T) {
+	db, source := newMockDBWithDialect("postgres")
+	ctx := context.Background()
+	require.True(t, db.SupportsTwoPhaseCommit())
+
+	tx, err := db.BeginTwoPhase(ctx, "order-42")
+	require.NoError(t, err)
+	require.NoError(t, tx.Create(ctx, &maskUser{Name: "Ana"}).Error)
+	require.NoError(t, tx.Prepare(ctx))
+	require.NoError(t, db.CommitPrepared(ctx, "order-42"))
+
+	stmts := sqlOf(source.Statements())
+	assert.Equal(t, "BEGIN", stmts[0])
+	assert.Equal(t, []string{"PREPARE TRANSACTION 'order-42'", "COMMIT", "COMMIT PREPARED 'order-42'"}, stmts[len(stmts)-3:])
+}
+
+func TestTwoPhaseCommit_Unsupported(t *testing.T) {
+	db, _ := newMockDB()
+	assert.False(t, db.SupportsTwoPhaseCommit())
+	_, err := db.BeginTwoPhase(context.Background(), "x1")
+	assert.True(t, errors.Is(err, ErrUnsupportedDialect))
+
+	pg, _ := newMockDBWithDialect("postgres")
+	_, err = pg.BeginTwoPhase(context.Background(), "bad'; DROP TABLE users; --")
+	assert.Error(t, err, "unsafe transaction ids are rejected")
+}
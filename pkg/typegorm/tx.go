@@ -7,8 +7,7 @@ import (
 	"fmt"
 	"math"
 	"reflect"
-	"strconv"
-	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/chmenegatti/typegorm/pkg/dialects/common"
@@ -18,10 +17,30 @@ import (
 
 // Tx represents an active database transaction.
 // It provides ORM methods that operate within this transaction.
+//
+// A Tx is bound to a single connection and must not be used from several goroutines
+// at once: overlapping operations fail with ErrConcurrentTxUse instead of corrupting
+// the connection state. Calls made by hooks running inside an operation are allowed.
 type Tx struct {
-	source  common.Tx      // The underlying transaction object from the DataSource
-	parser  *schema.Parser // Schema parser (inherited from DB)
-	dialect common.Dialect // Dialect (inherited from DB)
+	source        common.Tx         // The underlying transaction object from the DataSource
+	parser        *schema.Parser    // Schema parser (inherited from DB)
+	dialect       common.Dialect    // Dialect (inherited from DB)
+	relations     *virtualRelations // Virtual relations (inherited from DB)
+	machines      *stateMachines    // State machines (inherited from DB)
+	callbacks     *CallbackRegistry // Lifecycle callbacks (inherited from DB)
+	cache         EntityCache       // Entity cache invalidated by writes (inherited from DB)
+	now           NowFunc           // Clock (inherited from DB)
+	ids           IDGenerator       // Primary key generator (inherited from DB)
+	rewriter      ConditionRewriter // Condition rewriter (inherited from DB)
+	policies      []PolicyFunc      // Data-access policies (inherited from DB)
+	readOnly      bool              // Started with sql.TxOptions.ReadOnly: writes are rejected by the ORM
+	watchdog      *txWatchdog       // Long transaction watchdog (nil when disabled)
+	release       func()            // Marks the transaction as finished for leak detection (nil when disabled)
+	afterCommit   []func()          // Run once the transaction has committed (see AfterCommit)
+	afterRollback []func()          // Run once the transaction has rolled back (see AfterRollback)
+	busy          atomic.Bool       // An ORM operation is running (see enter)
+	maxRows       int               // Default Find row guard (config: query.maxRows)
+	savepoints    atomic.Int64      // Savepoints created by nested Transaction calls
 	// We might need context or config here later?
 }
 
@@ -30,12 +49,24 @@ func (tx *Tx) Commit() error {
 	if tx.source == nil {
 		return fmt.Errorf("transaction source is nil, cannot commit")
 	}
+	if tx.busy.Load() {
+		return fmt.Errorf("tx: Commit called while an operation is running: %w", ErrConcurrentTxUse)
+	}
+	if tx.release != nil {
+		tx.release()
+	}
+	if err := tx.stopWatchdog(); err != nil {
+		tx.finish(false)
+		return err
+	}
 	fmt.Println("Committing transaction...")
 	err := tx.source.Commit()
 	if err == nil {
 		fmt.Println("Transaction committed successfully.")
+		tx.finish(true)
 	} else {
 		fmt.Printf("Transaction commit failed: %v\n", err)
+		tx.finish(false)
 	}
 	return err
 }
@@ -45,8 +76,16 @@ func (tx *Tx) Rollback() error {
 	if tx.source == nil {
 		return fmt.Errorf("transaction source is nil, cannot rollback")
 	}
+	if tx.release != nil {
+		tx.release()
+	}
+	if err := tx.stopWatchdog(); err != nil {
+		tx.finish(false)
+		return nil // Already rolled back by the watchdog
+	}
 	fmt.Println("Rolling back transaction...")
 	err := tx.source.Rollback()
+	defer tx.finish(false) // Side effects of rolled back changes are dropped
 	// According to database/sql docs, Rollback error should be checked but often
 	// indicates the tx was already rolled back or committed.
 	if err != nil && !errors.Is(err, sql.ErrTxDone) {
@@ -61,96 +100,59 @@ func (tx *Tx) Rollback() error {
 	return nil // Typically return nil unless Rollback itself caused a new error
 }
 
-// Helper function to call hook methods using reflection
-// Handles both value and pointer receivers.
-func callHook(ctx context.Context, dbContext hooks.ContextDB, methodValue reflect.Value, instanceValue reflect.Value) error {
-
-	// Check if method expects pointer receiver and instance is not addressable
-	// This check might be overly complex depending on how Implements was checked.
-	// If Implements checked both value and pointer, we might just need to ensure we call on the right one.
-	// Let's try calling on Addr() first if possible, then on value.
-
-	var callArgs = []reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(dbContext)}
-	var results []reflect.Value
-
-	// Try calling on pointer receiver first if possible
-	if instanceValue.CanAddr() {
-		instancePtr := instanceValue.Addr()
-		methodOnPtr := instancePtr.MethodByName(methodValue.Type().Name()) // Get method by name on pointer
-		if methodOnPtr.IsValid() && methodOnPtr.Type().NumIn() == 2 {      // Check if method exists on pointer and takes correct args
-			fmt.Printf("Calling hook %s on pointer receiver\n", methodValue.Type().Name())
-			results = methodOnPtr.Call(callArgs)
-			if len(results) > 0 && !results[0].IsNil() {
-				if err, ok := results[0].Interface().(error); ok {
-					return err // Return error from hook
-				}
-			}
-			return nil // Hook succeeded or returned nil error
-		}
+// callAfterScan runs the AfterScan hook on a freshly scanned struct (value or pointer).
+func callAfterScan(model *schema.Model, value reflect.Value) {
+	if !model.HasAfterScan {
+		return
 	}
-
-	// If pointer call didn't work or wasn't possible, try on value receiver
-	methodOnValue := instanceValue.MethodByName(methodValue.Type().Name())
-	if methodOnValue.IsValid() && methodOnValue.Type().NumIn() == 2 {
-		fmt.Printf("Calling hook %s on value receiver\n", methodValue.Type().Name())
-		results = methodOnValue.Call(callArgs)
-		if len(results) > 0 && !results[0].IsNil() {
-			if err, ok := results[0].Interface().(error); ok {
-				return err // Return error from hook
-			}
+	if value.Kind() != reflect.Pointer {
+		if !value.CanAddr() {
+			return
 		}
-		return nil // Hook succeeded or returned nil error
+		value = value.Addr() // Pointer method set covers both receiver kinds
+	}
+	if scanner, ok := value.Interface().(hooks.AfterScanner); ok {
+		scanner.AfterScan()
 	}
-
-	// This shouldn't happen if HasX flag was true, indicates inconsistency
-	// fmt.Printf("Warning: Hook method %s found by parser but not callable via reflection.\n", methodValue.Type().Name())
-	return nil // Or return an internal error?
 }
 
-// Helper function to call hook methods that modify data (e.g., BeforeUpdate)
-func callHookWithData(ctx context.Context, dbContext hooks.ContextDB, methodValue reflect.Value, instanceValue reflect.Value, data map[string]any) error {
-
-	var callArgs = []reflect.Value{
-		reflect.ValueOf(ctx),
-		reflect.ValueOf(dbContext),
-		reflect.ValueOf(data), // Pass the data map
-	}
-	var results []reflect.Value
-
-	// Try pointer receiver first
-	if instanceValue.CanAddr() {
-		instancePtr := instanceValue.Addr()
-		methodOnPtr := instancePtr.MethodByName(methodValue.Type().Name())
-		if methodOnPtr.IsValid() && methodOnPtr.Type().NumIn() == 3 {
-			fmt.Printf("Calling hook %s on pointer receiver with data\n", methodValue.Type().Name())
-			results = methodOnPtr.Call(callArgs)
-			if len(results) > 0 && !results[0].IsNil() {
-				if err, ok := results[0].Interface().(error); ok {
-					return err
-				}
-			}
-			return nil
+// Create inserts a new record within the transaction. An empty `uniqueSlug` field is
+// generated as in DB.Create.
+func (tx *Tx) Create(ctx context.Context, value any, opts ...CreateOption) *Result {
+	if options := applyCreateOptions(opts); options.associations {
+		var result *Result
+		err := tx.Transaction(ctx, func(ctx context.Context, tx *Tx) error { // All or nothing
+			result = createWithAssociations(ctx, tx, value, opts, options.associationNames)
+			return result.Error
+		})
+		if result == nil || (result.Error == nil && err != nil) {
+			result = &Result{Error: err}
 		}
+		return result
 	}
-
-	// Try value receiver
-	methodOnValue := instanceValue.MethodByName(methodValue.Type().Name())
-	if methodOnValue.IsValid() && methodOnValue.Type().NumIn() == 3 {
-		fmt.Printf("Calling hook %s on value receiver with data\n", methodValue.Type().Name())
-		results = methodOnValue.Call(callArgs)
-		if len(results) > 0 && !results[0].IsNil() {
-			if err, ok := results[0].Interface().(error); ok {
-				return err
-			}
-		}
-		return nil
+	defer tx.invalidateEntity(ctx, value)
+	if model, err := tx.parser.Parse(value); err == nil && model.SlugField != nil {
+		return tx.createWithSlugInTx(ctx, value, model, func() *Result { return tx.create(ctx, value, opts...) })
 	}
-	return nil
+	return tx.create(ctx, value, opts...)
 }
 
-// Create inserts a new record within the transaction.
-func (tx *Tx) Create(ctx context.Context, value any) *Result {
-	result := &Result{}
+func (tx *Tx) create(ctx context.Context, value any, opts ...CreateOption) (result *Result) {
+	var sqlQuery string
+	defer wrapOpError(&result, tx.parser, "Create", value, &sqlQuery)
+	defer recoverResult(&result, "Create", value)
+	result = &Result{}
+	ctx, leave, busyErr := tx.enter(ctx, "Create")
+	if busyErr != nil {
+		result.Error = busyErr
+		return result
+	}
+	defer leave()
+	if err := tx.checkWritable("Create"); err != nil {
+		result.Error = err
+		return result
+	}
+	options := applyCreateOptions(opts)
 	reflectValue := reflect.ValueOf(value)
 	if reflectValue.Kind() != reflect.Pointer || reflectValue.IsNil() {
 		result.Error = fmt.Errorf("input value must be a non-nil pointer to a struct, got %T", value)
@@ -169,22 +171,31 @@ func (tx *Tx) Create(ctx context.Context, value any) *Result {
 	}
 
 	// --- Call BeforeCreate Hook ---
-	if model.HasBeforeCreate {
-		hookMethod := reflect.ValueOf(value).MethodByName("BeforeCreate") // Get method value
-		if err := callHook(ctx, tx, hookMethod, structValue); err != nil {
-			result.Error = fmt.Errorf("BeforeCreate hook failed: %w", err)
-			return result
-		}
+	if err := runCallbacks(ctx, tx.callbacks, &HookContext{Event: EventBeforeCreate, Model: model, Value: value, DB: tx}); err != nil {
+		result.Error = fmt.Errorf("BeforeCreate hook failed: %w", err)
+		return result
 	}
 	// --- End Hook Call ---
 
+	if err := checkCreatePolicies(ctx, tx.policies, model, value); err != nil {
+		result.Error = err
+		return result
+	}
+	if err := options.validateFields(model); err != nil {
+		result.Error = err
+		return result
+	}
+	if err := applyCreateDefaults(ctx, nowFunc(ctx, tx.now), idGenerator(ctx, tx.ids), model, structValue, options.updatesOnConflict()); err != nil {
+		result.Error = err
+		return result
+	}
+
 	var columns []string
-	var placeholders []string
 	var args []any
-	tableName := model.TableName
+	var sensitive []any
 	dialect := tx.dialect // Use tx.dialect
 	for _, field := range model.Fields {
-		if field.IsIgnored {
+		if field.IsIgnored || !options.includes(field) {
 			continue
 		}
 		fieldValue := structValue.FieldByName(field.GoName)
@@ -194,7 +205,7 @@ func (tx *Tx) Create(ctx context.Context, value any) *Result {
 		if field.IsPrimaryKey && field.AutoIncrement && fieldValue.IsZero() {
 			continue
 		}
-		if field.GoName == "CreatedAt" || field.GoName == "UpdatedAt" {
+		if field.IsAutoCreateTime() || field.IsAutoUpdateTime() {
 			isZeroTime := false
 			if fieldValue.Kind() == reflect.Struct && fieldValue.Type() == reflect.TypeOf(time.Time{}) {
 				isZeroTime = fieldValue.Interface().(time.Time).IsZero()
@@ -210,16 +221,27 @@ func (tx *Tx) Create(ctx context.Context, value any) *Result {
 				continue
 			}
 		}
-		columns = append(columns, dialect.Quote(field.DBName))
-		placeholders = append(placeholders, dialect.BindVar(len(args)+1))
-		args = append(args, fieldValue.Interface())
+		value, err := interceptField(ctx, model, field, fieldValue)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		columns = append(columns, field.DBName)
+		args = append(args, value)
+		if field.Sensitive {
+			sensitive = append(sensitive, value)
+		}
 	}
 	if len(columns) == 0 {
 		result.Error = fmt.Errorf("tx: no columns available for insert in type %s", structType.Name())
 		return result
 	}
-	sqlQuery := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", dialect.Quote(tableName), strings.Join(columns, ", "), strings.Join(placeholders, ", "))
-	fmt.Printf("TX Executing SQL: %s | Args: %v\n", sqlQuery, args)
+	sqlQuery, args, err = buildInsertSQL(dialect, model, columns, args, options)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	logSQL("TX Executing SQL: %s | Args: %v\n", sqlQuery, redactArgs(model, args, sensitive))
 	// *** Use tx.source.Exec ***
 	sqlResult, err := tx.source.Exec(ctx, sqlQuery, args...)
 	if err != nil {
@@ -231,10 +253,14 @@ func (tx *Tx) Create(ctx context.Context, value any) *Result {
 	} else {
 		fmt.Printf("tx Warning: could not get RowsAffected after insert: %v\n", errAff)
 	}
+	if options.onConflictDoNothing && result.RowsAffected == 0 {
+		fmt.Printf("tx: Insert skipped for %s: conflicting row already exists.\n", structType.Name())
+		return result
+	}
 	var pkField *schema.Field = nil
 	if len(model.PrimaryKeys) == 1 && model.PrimaryKeys[0].AutoIncrement {
 		pkField = model.PrimaryKeys[0]
-		if lastID, errID := sqlResult.LastInsertId(); errID == nil {
+		if lastID, errID := sqlResult.LastInsertId(); errID == nil && (lastID != 0 || !options.onConflictUpdate) { // An upsert that updated reports no ID
 			result.LastInsertID = lastID
 			pkValueField := structValue.FieldByName(pkField.GoName)
 			if pkValueField.IsValid() && pkValueField.CanSet() {
@@ -250,7 +276,7 @@ func (tx *Tx) Create(ctx context.Context, value any) *Result {
 			} else {
 				fmt.Printf("tx Warning: Cannot set auto-increment ID back on PK field %s (invalid or not settable)\n", pkField.GoName)
 			}
-		} else {
+		} else if errID != nil {
 			fmt.Printf("tx Warning: could not get LastInsertId after insert (driver/DB may not support it): %v\n", errID)
 		}
 	}
@@ -259,21 +285,37 @@ func (tx *Tx) Create(ctx context.Context, value any) *Result {
 	// or make it optional, as the state isn't final until commit.
 	// Let's omit re-fetch for Tx.Create for now. The user can tx.FindByID if needed.
 
+	// --- Counter caches of referenced parents ---
+	keys, err := counterKeys(ctx, tx.source, tx.dialect, model, structValue, nil, nil)
+	if err == nil {
+		err = options.saved.applyCounterCaches(ctx, tx.source, tx.dialect, keys, result.RowsAffected)
+	}
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
 	// --- Call AfterCreate Hook ---
-	if model.HasAfterCreate {
-		hookMethod := reflect.ValueOf(value).MethodByName("AfterCreate")
-		if err := callHook(ctx, tx, hookMethod, structValue); err != nil {
-			// Log error but don't fail the main operation
-			fmt.Printf("tx Warning: AfterCreate hook failed: %v\n", err)
-		}
+	if err := runCallbacks(ctx, tx.callbacks, &HookContext{Event: EventAfterCreate, Model: model, Value: value, DB: tx}); err != nil {
+		// Log error but don't fail the main operation
+		fmt.Printf("tx Warning: AfterCreate hook failed: %v\n", err)
 	}
 	// --- End Hook Call ---
 	return result
 }
 
 // FindByID finds a record by primary key within the transaction.
-func (tx *Tx) FindByID(ctx context.Context, dest any, id any) *Result {
-	result := &Result{}
+func (tx *Tx) FindByID(ctx context.Context, dest any, id any) (result *Result) {
+	var sqlQuery string
+	defer wrapOpError(&result, tx.parser, "FindByID", dest, &sqlQuery)
+	defer recoverResult(&result, "FindByID", dest)
+	result = &Result{}
+	ctx, leave, busyErr := tx.enter(ctx, "FindByID")
+	if busyErr != nil {
+		result.Error = busyErr
+		return result
+	}
+	defer leave()
 	destValue := reflect.ValueOf(dest)
 	if destValue.Kind() != reflect.Pointer || destValue.IsNil() {
 		result.Error = fmt.Errorf("tx: destination must be a non-nil pointer to a struct, got %T", dest)
@@ -296,24 +338,26 @@ func (tx *Tx) FindByID(ctx context.Context, dest any, id any) *Result {
 	}
 	pkField := model.PrimaryKeys[0]
 	dialect := tx.dialect
-	selectCols := []string{}
-	scanFields := []*schema.Field{}
-	for _, field := range model.Fields {
-		if !field.IsIgnored {
-			selectCols = append(selectCols, dialect.Quote(field.DBName))
-			scanFields = append(scanFields, field)
-		}
+	policyCtx, mask := withColumnMask(ctx, tx.policies)
+	policyWhere, policyArgs, err := policyClause(policyCtx, tx.policies, dialect, "FindByID", model, []any{id})
+	if err != nil {
+		result.Error = err
+		return result
 	}
-	if len(selectCols) == 0 {
+	masked := mask.maskedFields(model)
+	selectList, scanFields := maskedSelectColumns(dialect, model, masked)
+	if len(scanFields) == 0 {
 		result.Error = fmt.Errorf("tx: no selectable columns found for model %s", model.Name)
 		return result
 	}
-	tableNameQuoted := dialect.Quote(model.TableName)
-	pkColNameQuoted := dialect.Quote(pkField.DBName)
-	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = %s LIMIT 1", strings.Join(selectCols, ", "), tableNameQuoted, pkColNameQuoted, dialect.BindVar(1))
-	fmt.Printf("TX Executing SQL: %s | Args: [%v]\n", query, id)
+	sqlQuery = selectByPKSQL(dialect, model, selectList, pkField, softDeleteField(ctx, model), policyWhere)
+	args := append([]any{id}, policyArgs...)
+	if policyWhere != "" {
+		sqlQuery = renumberBindVars(dialect, sqlQuery)
+	}
+	logSQL("TX Executing SQL: %s | Args: %v\n", sqlQuery, redactArgs(model, args))
 	// *** Use tx.source.QueryRow ***
-	rowScanner := tx.source.QueryRow(ctx, query, id)
+	rowScanner := tx.source.QueryRow(ctx, sqlQuery, args...)
 	scanDest := make([]any, len(scanFields))
 	for i, field := range scanFields {
 		fieldValue := destElem.FieldByName(field.GoName)
@@ -337,13 +381,14 @@ func (tx *Tx) FindByID(ctx context.Context, dest any, id any) *Result {
 		return result
 	}
 	result.RowsAffected = 1
+	zeroMaskedFields(destElem, masked)
+
+	// --- Populate computed fields ---
+	callAfterScan(model, destValue)
 
 	// --- Call AfterFind Hook ---
-	if model.HasAfterFind {
-		hookMethod := destValue.MethodByName("AfterFind") // Call on the pointer receiver 'dest'
-		if err := callHook(ctx, tx, hookMethod, destElem); err != nil {
-			fmt.Printf("tx Warning: AfterFind hook failed for ID %v: %v\n", id, err)
-		}
+	if err := runCallbacks(ctx, tx.callbacks, &HookContext{Event: EventAfterFind, Model: model, Value: dest, DB: tx}); err != nil {
+		fmt.Printf("tx Warning: AfterFind hook failed for ID %v: %v\n", id, err)
 	}
 	// --- End Hook Call ---
 
@@ -351,8 +396,22 @@ func (tx *Tx) FindByID(ctx context.Context, dest any, id any) *Result {
 }
 
 // Delete deletes a record by primary key within the transaction.
-func (tx *Tx) Delete(ctx context.Context, value any) *Result {
-	result := &Result{}
+func (tx *Tx) Delete(ctx context.Context, value any) (result *Result) {
+	var sqlQuery string
+	defer wrapOpError(&result, tx.parser, "Delete", value, &sqlQuery)
+	defer recoverResult(&result, "Delete", value)
+	result = &Result{}
+	defer tx.invalidateEntity(ctx, value)
+	ctx, leave, busyErr := tx.enter(ctx, "Delete")
+	if busyErr != nil {
+		result.Error = busyErr
+		return result
+	}
+	defer leave()
+	if err := tx.checkWritable("Delete"); err != nil {
+		result.Error = err
+		return result
+	}
 	reflectValue := reflect.ValueOf(value)
 	if reflectValue.Kind() != reflect.Pointer || reflectValue.IsNil() {
 		result.Error = fmt.Errorf("tx: input value must be a non-nil pointer to a struct, got %T", value)
@@ -372,12 +431,10 @@ func (tx *Tx) Delete(ctx context.Context, value any) *Result {
 	}
 
 	// --- Call BeforeDelete Hook ---
-	if model.HasBeforeDelete {
-		hookMethod := reflectValue.MethodByName("BeforeDelete")
-		if err := callHook(ctx, tx, hookMethod, structValue); err != nil {
-			result.Error = fmt.Errorf("BeforeDelete hook failed: %w", err)
-			return result
-		}
+	softDelete := softDeleteField(ctx, model)
+	if err := runCallbacks(ctx, tx.callbacks, &HookContext{Event: EventBeforeDelete, Model: model, Value: value, DB: tx, SoftDelete: softDelete != nil}); err != nil {
+		result.Error = fmt.Errorf("BeforeDelete hook failed: %w", err)
+		return result
 	}
 	// --- End Hook Call ---
 
@@ -399,11 +456,25 @@ func (tx *Tx) Delete(ctx context.Context, value any) *Result {
 			return result
 		}
 		pkArgs = append(pkArgs, pkValueField.Interface())
-		pkWhereClauses = append(pkWhereClauses, fmt.Sprintf("%s = %s", dialect.Quote(pkField.DBName), dialect.BindVar(i+1)))
+		pkWhereClauses = append(pkWhereClauses, assignment(dialect, pkField.DBName, i+1))
 	}
-	tableNameQuoted := dialect.Quote(model.TableName)
-	sqlQuery := fmt.Sprintf("DELETE FROM %s WHERE %s", tableNameQuoted, strings.Join(pkWhereClauses, " AND "))
-	fmt.Printf("TX Executing SQL: %s | Args: %v\n", sqlQuery, pkArgs)
+	policyWhere, policyArgs, err := policyClause(ctx, tx.policies, dialect, "Delete", model, pkArgs)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	keys, err := counterKeys(ctx, tx.source, dialect, model, structValue, pkWhereClauses, pkArgs)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	if softDelete != nil {
+		sqlQuery, pkArgs = softDeleteSQL(dialect, model, softDelete, clock(ctx, tx.now), structValue, pkArgs)
+	} else {
+		sqlQuery = deleteSQL(dialect, model, pkWhereClauses)
+	}
+	sqlQuery, pkArgs = withPolicyClause(dialect, sqlQuery, pkArgs, policyWhere, policyArgs)
+	logSQL("TX Executing SQL: %s | Args: %v\n", sqlQuery, redactArgs(model, pkArgs, value))
 	// *** Use tx.source.Exec ***
 	sqlResult, err := tx.source.Exec(ctx, sqlQuery, pkArgs...)
 	if err != nil {
@@ -419,10 +490,16 @@ func (tx *Tx) Delete(ctx context.Context, value any) *Result {
 		fmt.Printf("tx Warning: Delete executed but no rows affected (record with PK probably didn't exist).\n")
 	}
 
+	if affected > 0 {
+		if err := applyCounterCaches(ctx, tx.source, dialect, keys, -1); err != nil {
+			result.Error = err
+			return result
+		}
+	}
+
 	// --- Call AfterDelete Hook ---
-	if model.HasAfterDelete && affected > 0 { // Only call if delete likely succeeded
-		hookMethod := reflectValue.MethodByName("AfterDelete")
-		if err := callHook(ctx, tx, hookMethod, structValue); err != nil {
+	if affected > 0 {
+		if err := runCallbacks(ctx, tx.callbacks, &HookContext{Event: EventAfterDelete, Model: model, Value: value, DB: tx, SoftDelete: softDelete != nil}); err != nil {
 			fmt.Printf("tx Warning: AfterDelete hook failed: %v\n", err)
 		}
 	}
@@ -432,8 +509,17 @@ func (tx *Tx) Delete(ctx context.Context, value any) *Result {
 }
 
 // FindFirst finds the first record matching conditions within the transaction.
-func (tx *Tx) FindFirst(ctx context.Context, dest any, conds ...any) *Result {
-	result := &Result{}
+func (tx *Tx) FindFirst(ctx context.Context, dest any, conds ...any) (result *Result) {
+	var sqlQuery string
+	defer wrapOpError(&result, tx.parser, "FindFirst", dest, &sqlQuery)
+	defer recoverResult(&result, "FindFirst", dest)
+	result = &Result{}
+	ctx, leave, busyErr := tx.enter(ctx, "FindFirst")
+	if busyErr != nil {
+		result.Error = busyErr
+		return result
+	}
+	defer leave()
 	destValue := reflect.ValueOf(dest)
 	if destValue.Kind() != reflect.Pointer || destValue.IsNil() {
 		result.Error = fmt.Errorf("tx: destination must be a non-nil pointer to a struct, got %T", dest)
@@ -451,41 +537,76 @@ func (tx *Tx) FindFirst(ctx context.Context, dest any, conds ...any) *Result {
 		return result
 	}
 	dialect := tx.dialect
-	condition, _, err := processFindArgs(conds...) // Use helper from query_options.go
+	condition, options, err := processFindArgs(conds...) // Use helper from query_options.go
+	if err == nil && len(options.joins) > 0 {
+		err = errors.New("FindFirst does not support Joins: use Find with Limit(1)")
+	}
 	if err != nil {
 		result.Error = err
 		return result
 	}
+	policyCtx, mask := withColumnMask(ctx, tx.policies)
+	userCondition := condition
+	if condition, err = rewriteCondition(policyCtx, tx.rewriter, tx.policies, "FindFirst", model, condition); err != nil {
+		result.Error = err
+		return result
+	}
+	if err = checkMaskedUse("FindFirst", model, mask.maskedFields(model), userCondition, options); err != nil {
+		result.Error = err
+		return result
+	}
 	whereClauses, whereArgs, err := buildWhereClause(dialect, model, condition)
 	if err != nil {
 		result.Error = err
 		return result
 	} // Use helper
-	selectCols := []string{}
-	scanFields := []*schema.Field{}
-	for _, field := range model.Fields {
-		if !field.IsIgnored {
-			selectCols = append(selectCols, dialect.Quote(field.DBName))
-			scanFields = append(scanFields, field)
+	options.unscoped = options.unscoped || isUnscoped(ctx) // Session.Unscoped / WithUnscoped
+	whereClauses, whereArgs, err = applyDefaultScope(dialect, model, whereClauses, whereArgs, &options)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	masked := mask.maskedFields(model)
+	selectList, scanFields := maskedSelectColumns(dialect, model, masked)
+	if len(options.columns) > 0 {
+		if selectList, scanFields, err = options.selectedColumns(dialect, model); err != nil {
+			result.Error = err
+			return result
 		}
 	}
-	if len(selectCols) == 0 {
+	if len(scanFields) == 0 {
 		result.Error = fmt.Errorf("tx: no selectable columns found for model %s", model.Name)
 		return result
 	}
-	tableNameQuoted := dialect.Quote(model.TableName)
-	queryBuilder := strings.Builder{}
-	queryBuilder.WriteString("SELECT ")
-	queryBuilder.WriteString(strings.Join(selectCols, ", "))
+	tableNameQuoted, whereClauses, whereArgs, err := asOfSource(dialect, model, options, whereClauses, whereArgs)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	groupBy, havingArgs, err := options.groupClause(dialect, model)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	queryBuilder := getStmtBuffer(statementSize(model))
+	defer putStmtBuffer(queryBuilder)
+	queryBuilder.WriteString(options.selectKeyword())
+	queryBuilder.WriteString(selectList)
 	queryBuilder.WriteString(" FROM ")
 	queryBuilder.WriteString(tableNameQuoted)
 	if len(whereClauses) > 0 {
 		queryBuilder.WriteString(" WHERE ")
-		queryBuilder.WriteString(strings.Join(whereClauses, " AND "))
+		queryBuilder.WriteJoined(whereClauses, " AND ")
+	}
+	queryBuilder.WriteString(groupBy)
+	if options.orderBy != "" {
+		queryBuilder.WriteString(" ORDER BY ")
+		queryBuilder.WriteString(quoteOrderBy(dialect, model, options.orderBy))
 	}
 	queryBuilder.WriteString(" LIMIT 1")
-	sqlQuery := queryBuilder.String()
-	fmt.Printf("TX Executing SQL: %s | Args: %v\n", sqlQuery, whereArgs)
+	sqlQuery = renumberBindVars(dialect, queryBuilder.String())
+	whereArgs = append(whereArgs, havingArgs...)
+	logSQL("TX Executing SQL: %s | Args: %v\n", sqlQuery, redactArgs(model, whereArgs, conds...))
 	rowScanner := tx.source.QueryRow(ctx, sqlQuery, whereArgs...)
 	scanDest := make([]any, len(scanFields))
 	for i, field := range scanFields {
@@ -511,21 +632,40 @@ func (tx *Tx) FindFirst(ctx context.Context, dest any, conds ...any) *Result {
 	}
 	result.RowsAffected = 1
 
+	// --- Populate computed fields ---
+	zeroMaskedFields(destElem, masked)
+	callAfterScan(model, destValue)
+
 	// --- Call AfterFind Hook ---
-	if model.HasAfterFind {
-		hookMethod := destValue.MethodByName("AfterFind") // Call on the pointer receiver 'dest'
-		if err := callHook(ctx, tx, hookMethod, destElem); err != nil {
-			fmt.Printf("tx Warning: AfterFind hook failed for FindFirst: %v\n", err)
-		}
+	if err := runCallbacks(ctx, tx.callbacks, &HookContext{Event: EventAfterFind, Model: model, Value: dest, DB: tx}); err != nil {
+		fmt.Printf("tx Warning: AfterFind hook failed for FindFirst: %v\n", err)
 	}
 	// --- End Hook Call ---
 
+	// --- Load the relations requested with Preload ---
+	if err := preload(ctx, tx.relationSource(), tx.parser, tx.relations, destValue, options.preload, options.preloadConds); err != nil {
+		result.Error = err
+	}
 	return result
 }
 
 // Updates updates specific fields within the transaction.
-func (tx *Tx) Updates(ctx context.Context, modelWithValue any, data map[string]any) *Result {
-	result := &Result{}
+func (tx *Tx) Updates(ctx context.Context, modelWithValue any, data map[string]any) (result *Result) {
+	var sqlQuery string
+	defer wrapOpError(&result, tx.parser, "Updates", modelWithValue, &sqlQuery)
+	defer recoverResult(&result, "Updates", modelWithValue)
+	result = &Result{}
+	defer tx.invalidateEntity(ctx, modelWithValue)
+	ctx, leave, busyErr := tx.enter(ctx, "Updates")
+	if busyErr != nil {
+		result.Error = busyErr
+		return result
+	}
+	defer leave()
+	if err := tx.checkWritable("Updates"); err != nil {
+		result.Error = err
+		return result
+	}
 	reflectValue := reflect.ValueOf(modelWithValue)
 	if reflectValue.Kind() != reflect.Pointer || reflectValue.IsNil() {
 		result.Error = fmt.Errorf("tx: modelWithValue must be a non-nil pointer to a struct, got %T", modelWithValue)
@@ -544,15 +684,12 @@ func (tx *Tx) Updates(ctx context.Context, modelWithValue any, data map[string]a
 	}
 
 	// --- Call BeforeUpdate Hook ---
-	if model.HasBeforeUpdate {
-		// Pass a copy of the map? Or allow modification? Let's allow modification for now.
-		hookMethod := reflectValue.MethodByName("BeforeUpdate")
-		if err := callHookWithData(ctx, tx, hookMethod, structValue, data); err != nil {
-			result.Error = fmt.Errorf("BeforeUpdate hook failed: %w", err)
-			return result
-		}
+	if err := runCallbacks(ctx, tx.callbacks, &HookContext{Event: EventBeforeUpdate, Model: model, Value: modelWithValue, DB: tx, Data: data}); err != nil {
+		result.Error = fmt.Errorf("BeforeUpdate hook failed: %w", err)
+		return result
 	}
 	// --- End Hook Call ---
+	data = touchUpdatedAt(clock(ctx, tx.now), model, structValue, data)
 
 	if len(model.PrimaryKeys) == 0 {
 		result.Error = fmt.Errorf("tx: cannot update: model %s has no primary key defined", model.Name)
@@ -572,10 +709,22 @@ func (tx *Tx) Updates(ctx context.Context, modelWithValue any, data map[string]a
 			return result
 		}
 		pkArgs = append(pkArgs, pkValueField.Interface())
-		pkWhereClauses = append(pkWhereClauses, fmt.Sprintf("%s = %s", dialect.Quote(pkField.DBName), dialect.BindVar(i+1)))
+		pkWhereClauses = append(pkWhereClauses, assignment(dialect, pkField.DBName, i+1))
+	}
+	policyWhere, policyArgs, err := policyClause(ctx, tx.policies, dialect, "Update", model, pkArgs)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	transition, err := checkTransition(ctx, tx.source, tx.machines, dialect, model, data, pkWhereClauses, pkArgs)
+	if err != nil {
+		result.Error = err
+		return result
 	}
+	pkWhereClauses, pkArgs = transition.guard(dialect, pkWhereClauses, pkArgs)
 	setClauses := []string{}
 	setArgs := []any{}
+	var sensitive []any
 	placeholderOffset := len(pkArgs)
 	for dbColName, value := range data {
 		field, ok := model.GetFieldByDBName(dbColName)
@@ -586,17 +735,25 @@ func (tx *Tx) Updates(ctx context.Context, modelWithValue any, data map[string]a
 		if field.IsIgnored || field.IsPrimaryKey {
 			continue
 		}
-		setClauses = append(setClauses, fmt.Sprintf("%s = %s", dialect.Quote(dbColName), dialect.BindVar(placeholderOffset+len(setArgs)+1)))
+		value, err := interceptValue(ctx, model, dbColName, value)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		setClauses = append(setClauses, assignment(dialect, dbColName, placeholderOffset+len(setArgs)+1))
 		setArgs = append(setArgs, value)
+		if field.Sensitive {
+			sensitive = append(sensitive, value)
+		}
 	}
 	if len(setClauses) == 0 {
 		result.Error = fmt.Errorf("tx: no valid fields provided for update")
 		return result
 	}
-	tableNameQuoted := dialect.Quote(model.TableName)
-	sqlQuery := fmt.Sprintf("UPDATE %s SET %s WHERE %s", tableNameQuoted, strings.Join(setClauses, ", "), strings.Join(pkWhereClauses, " AND "))
+	sqlQuery = updateSQL(dialect, model, setClauses, pkWhereClauses)
 	allArgs := append(setArgs, pkArgs...)
-	fmt.Printf("TX Executing SQL: %s | Args: %v\n", sqlQuery, allArgs)
+	sqlQuery, allArgs = withPolicyClause(dialect, sqlQuery, allArgs, policyWhere, policyArgs)
+	logSQL("TX Executing SQL: %s | Args: %v\n", sqlQuery, redactArgs(model, allArgs, sensitive))
 	// *** Use tx.source.Exec ***
 	sqlResult, err := tx.source.Exec(ctx, sqlQuery, allArgs...)
 	if err != nil {
@@ -612,10 +769,16 @@ func (tx *Tx) Updates(ctx context.Context, modelWithValue any, data map[string]a
 		fmt.Printf("tx Warning: Update executed but no rows affected (record with PK might not exist or values were the same).\n")
 	}
 
+	if history := transition.history(model, pkArgs, clock(ctx, tx.now)); history != nil && affected > 0 {
+		if err := tx.create(ctx, history).Error; err != nil {
+			result.Error = fmt.Errorf("tx: state machine: failed to record transition: %w", err)
+			return result
+		}
+	}
+
 	// --- Call AfterUpdate Hook ---
-	if model.HasAfterUpdate && affected > 0 { // Only call if update likely succeeded
-		hookMethod := reflectValue.MethodByName("AfterUpdate")
-		if err := callHook(ctx, tx, hookMethod, structValue); err != nil {
+	if affected > 0 {
+		if err := runCallbacks(ctx, tx.callbacks, &HookContext{Event: EventAfterUpdate, Model: model, Value: modelWithValue, DB: tx}); err != nil {
 			fmt.Printf("tx Warning: AfterUpdate hook failed: %v\n", err)
 		}
 	}
@@ -625,8 +788,17 @@ func (tx *Tx) Updates(ctx context.Context, modelWithValue any, data map[string]a
 }
 
 // Find retrieves multiple records within the transaction.
-func (tx *Tx) Find(ctx context.Context, dest any, condsAndOpts ...any) *Result {
-	result := &Result{}
+func (tx *Tx) Find(ctx context.Context, dest any, condsAndOpts ...any) (result *Result) {
+	var sqlQuery string
+	defer wrapOpError(&result, tx.parser, "Find", dest, &sqlQuery)
+	defer recoverResult(&result, "Find", dest)
+	result = &Result{}
+	ctx, leave, busyErr := tx.enter(ctx, "Find")
+	if busyErr != nil {
+		result.Error = busyErr
+		return result
+	}
+	defer leave()
 
 	// 1. Validate dest input
 	destValue := reflect.ValueOf(dest)
@@ -663,45 +835,84 @@ func (tx *Tx) Find(ctx context.Context, dest any, condsAndOpts ...any) *Result {
 		result.Error = err
 		return result
 	}
+	policyCtx, mask := withColumnMask(ctx, tx.policies)
+	userCondition := condition
+	if condition, err = rewriteCondition(policyCtx, tx.rewriter, tx.policies, "Find", model, condition); err != nil {
+		result.Error = err
+		return result
+	}
+	if err = checkMaskedUse("Find", model, mask.maskedFields(model), userCondition, options); err != nil {
+		result.Error = err
+		return result
+	}
 
 	// 3. Build WHERE clause and arguments
 	dialect := tx.dialect
-	whereClauses, whereArgs, err := buildWhereClause(dialect, model, condition) // Use helper
+	joins, err := planJoins(&queryBuild{dialect: dialect, parser: tx.parser, ctx: ctx, rewriter: tx.rewriter, policies: tx.policies}, model, options)
+	if err == nil {
+		err = joins.check("Find", model, userCondition, options)
+	}
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	whereClauses, whereArgs, err := buildWhereClause(joins.columns(dialect), model, condition) // Use helper
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	options.unscoped = options.unscoped || isUnscoped(ctx) // Session.Unscoped / WithUnscoped
+	whereClauses, whereArgs, err = applyDefaultScope(joins.columns(dialect), model, whereClauses, whereArgs, &options)
 	if err != nil {
 		result.Error = err
 		return result
 	}
 
 	// 4. Build SELECT SQL (including ORDER BY, LIMIT, OFFSET)
-	selectCols := []string{}
-	scanFields := []*schema.Field{}
-	for _, field := range model.Fields {
-		if !field.IsIgnored {
-			selectCols = append(selectCols, dialect.Quote(field.DBName))
-			scanFields = append(scanFields, field)
+	masked := mask.maskedFields(model)
+	selectList, scanFields := maskedSelectColumns(dialect, model, masked)
+	selectList = joins.selectList(dialect, selectList, scanFields)
+	if len(options.columns) > 0 {
+		if selectList, scanFields, err = options.selectedColumns(joins.columns(dialect), model); err != nil {
+			result.Error = err
+			return result
 		}
+		selectList = joins.withJoined(selectList)
 	}
-	if len(selectCols) == 0 {
+	if len(scanFields) == 0 {
 		result.Error = fmt.Errorf("tx: no selectable columns found for model %s", model.Name)
 		return result
 	}
-	tableNameQuoted := dialect.Quote(model.TableName)
-	queryBuilder := strings.Builder{}
-	queryBuilder.WriteString("SELECT ")
-	queryBuilder.WriteString(strings.Join(selectCols, ", "))
+	tableNameQuoted, whereClauses, whereArgs, err := asOfSource(dialect, model, options, whereClauses, whereArgs)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	groupBy, havingArgs, err := options.groupClause(joins.columns(dialect), model)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	queryBuilder := getStmtBuffer(statementSize(model))
+	defer putStmtBuffer(queryBuilder)
+	queryBuilder.WriteString(options.selectKeyword())
+	queryBuilder.WriteString(selectList)
 	queryBuilder.WriteString(" FROM ")
 	queryBuilder.WriteString(tableNameQuoted)
+	queryBuilder.WriteString(joins.from())
 	if len(whereClauses) > 0 {
 		queryBuilder.WriteString(" WHERE ")
-		queryBuilder.WriteString(strings.Join(whereClauses, " AND "))
+		queryBuilder.WriteJoined(whereClauses, " AND ")
 	}
+	queryBuilder.WriteString(groupBy)
 	// *** NEW: Append optional clauses ***
 	if options.orderBy != "" {
 		queryBuilder.WriteString(" ORDER BY ")
-		queryBuilder.WriteString(options.orderBy)
+		queryBuilder.WriteString(quoteOrderBy(joins.columns(dialect), model, options.orderBy))
 	}
-	effectiveLimit := options.limit
-	if options.offset > 0 && options.limit <= 0 {
+	maxRows := resolveMaxRows(options, tx.maxRows)
+	effectiveLimit := guardedLimit(options.limit, maxRows)
+	if options.offset > 0 && effectiveLimit <= 0 {
 		// Set a large default limit if offset is used without limit
 		// Use math.MaxInt64 which is suitable for most DB limits
 		effectiveLimit = math.MaxInt64
@@ -709,16 +920,17 @@ func (tx *Tx) Find(ctx context.Context, dest any, condsAndOpts ...any) *Result {
 	}
 	if effectiveLimit > 0 { // Append LIMIT if it's positive (either user-set or default)
 		queryBuilder.WriteString(" LIMIT ")
-		queryBuilder.WriteString(strconv.FormatInt(int64(effectiveLimit), 10)) // Use FormatInt for safety
+		queryBuilder.WriteInt(int64(effectiveLimit)) // Use FormatInt for safety
 	}
 	if options.offset > 0 { // Append OFFSET if it's positive
 		queryBuilder.WriteString(" OFFSET ")
-		queryBuilder.WriteString(strconv.Itoa(options.offset))
+		queryBuilder.WriteInt(int64(options.offset))
 	}
-	sqlQuery := queryBuilder.String()
+	sqlQuery = renumberBindVars(dialect, queryBuilder.String()) // Conditions are built with BindVar(1)
+	whereArgs = append(joins.whereArgs(whereArgs), havingArgs...)
 
 	// 5. Execute Query using Query()
-	fmt.Printf("TX Executing SQL: %s | Args: %v\n", sqlQuery, whereArgs)
+	logSQL("TX Executing SQL: %s | Args: %v\n", sqlQuery, redactArgs(model, whereArgs, condsAndOpts...))
 	// *** Use tx.source.Query ***
 	rows, err := tx.source.Query(ctx, sqlQuery, whereArgs...)
 	if err != nil {
@@ -727,63 +939,50 @@ func (tx *Tx) Find(ctx context.Context, dest any, condsAndOpts ...any) *Result {
 	}
 	defer rows.Close()
 
-	// 6. Iterate and Scan Rows into Slice (remains the same logic)
-	sliceValue.Set(reflect.MakeSlice(sliceValue.Type(), 0, 0))
-	rowCount := 0
-
-	var addedElements []reflect.Value
-	for rows.Next() {
-		rowCount++
-		newElemInstance := reflect.New(schemaType).Elem()
-		scanDest := make([]any, len(scanFields))
-		for i, field := range scanFields {
-			fieldValue := newElemInstance.FieldByName(field.GoName)
-			if !fieldValue.IsValid() {
-				result.Error = fmt.Errorf("tx internal error: struct field %s not found in new element", field.GoName)
-				return result
-			}
-			if !fieldValue.CanAddr() {
-				result.Error = fmt.Errorf("tx internal error: struct field %s is not addressable", field.GoName)
-				return result
-			}
-			scanDest[i] = fieldValue.Addr().Interface()
-		}
-		if err := rows.Scan(scanDest...); err != nil {
-			result.Error = fmt.Errorf("tx: failed to scan row for model %s: %w", model.Name, err)
-			return result
-		}
-		if elementIsPointer {
-			elemPtr := newElemInstance.Addr()
-			sliceValue.Set(reflect.Append(sliceValue, elemPtr))
-			addedElements = append(addedElements, elemPtr) // Store pointer
-		} else {
-			sliceValue.Set(reflect.Append(sliceValue, newElemInstance))
-			addedElements = append(addedElements, newElemInstance) // Store value
-		}
+	// 6. Iterate and Scan Rows into Slice (pooled destinations, see scanPlan)
+	plan, err := getScanPlan(schemaType, scanFields)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	rowCount, err := joins.scan(rows, sliceValue, schemaType, elementIsPointer, plan, scanCapacity(options.limit))
+	if err != nil {
+		result.Error = fmt.Errorf("tx: failed to scan row for model %s: %w", model.Name, err)
+		return result
 	}
 	if err := rows.Err(); err != nil {
 		result.Error = fmt.Errorf("tx: error iterating query results for %s: %w", model.Name, err)
 		return result
 	}
+	if err := checkMaxRows(rowCount, maxRows, sliceValue, model.Name); err != nil {
+		result.Error = err
+		return result
+	}
 	result.RowsAffected = int64(rowCount)
 
+	// --- Populate computed fields ---
+	for i := 0; i < sliceValue.Len(); i++ {
+		callAfterScan(model, sliceValue.Index(i)) // Slice elements, not the pre-append copies
+	}
+
 	// --- Call AfterFind Hook for each found element ---
-	if model.HasAfterFind && rowCount > 0 {
-		for _, elemValue := range addedElements {
-			instanceValue := elemValue // This is either the struct value or pointer value
-			hookMethod := instanceValue.MethodByName("AfterFind")
-			if hookMethod.IsValid() { // Check if method exists on the specific value/pointer
-				// Need the underlying struct value for callHook if elem is pointer
-				structValForHook := instanceValue
-				if instanceValue.Kind() == reflect.Pointer {
-					structValForHook = instanceValue.Elem()
-				}
-				if err := callHook(ctx, tx, hookMethod, structValForHook); err != nil {
-					fmt.Printf("tx Warning: AfterFind hook failed for element: %v\n", err)
-				}
-			}
+	for i := 0; i < sliceValue.Len(); i++ {
+		elem := sliceValue.Index(i) // Slice elements, not the pre-append copies
+		if elem.Kind() != reflect.Pointer {
+			elem = elem.Addr()
+		}
+		if err := runCallbacks(ctx, tx.callbacks, &HookContext{Event: EventAfterFind, Model: model, Value: elem.Interface(), DB: tx}); err != nil {
+			fmt.Printf("tx Warning: AfterFind hook failed for element: %v\n", err)
 		}
 	}
 	// --- End Hook Call ---
+
+	// --- Load the relations requested with Preload ---
+	if len(options.preload) > 0 && rowCount > 0 {
+		if err := preload(ctx, tx.relationSource(), tx.parser, tx.relations, destValue, options.preload, options.preloadConds); err != nil {
+			result.Error = err
+			return result
+		}
+	}
 	return result
 }
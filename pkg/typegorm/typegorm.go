@@ -33,12 +33,48 @@ func Open(cfg config.Config) (*DB, error) {
 		return nil, fmt.Errorf("failed to connect data source for dialect '%s': %w", dialectName, err)
 	}
 
-	// 3. Create Schema Parser (using default naming strategy for now)
-	// TODO: Allow configuration of naming strategy
-	parser := schema.NewParser(nil)
+	// 3. Create Schema Parser
+	var parserOpts []schema.ParserOption
+	if cfg.Schema.GormTags {
+		parserOpts = append(parserOpts, schema.WithGormTags())
+	}
+	if cfg.Schema.Comments {
+		parserOpts = append(parserOpts, schema.WithComments())
+	}
+	if cfg.Schema.DefaultSchema != "" {
+		parserOpts = append(parserOpts, schema.WithDefaultSchema(cfg.Schema.DefaultSchema))
+	}
+	if cfg.Schema.StrictTags {
+		parserOpts = append(parserOpts, schema.WithStrictTags())
+	}
+	var naming schema.NamingStrategy // nil: snake_case (DefaultNamingStrategy)
+	if cfg.Schema.PreserveCase {
+		naming = schema.PreserveCaseNamingStrategy{}
+	}
+	parser := schema.NewParser(naming, parserOpts...)
+	if cfg.Schema.Comments && len(cfg.Schema.CommentSources) > 0 {
+		if err := parser.LoadDocComments(cfg.Schema.CommentSources...); err != nil {
+			_ = ds.Close()
+			return nil, err
+		}
+	}
 
 	// 4. Create and return the DB handle
 	db := NewDB(ds, parser, cfg) // Pass ds, parser, and cfg
+	replicas, err := openReplicas(cfg.Database)
+	if err != nil {
+		_ = ds.Close()
+		return nil, err
+	}
+	db.replicas = replicas
+	pools, err := openPools(cfg.Database)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	db.pools = pools
+	db.secrets = startCredentialRefresh(db, cfg.Database)
+	db.failover = newPrimaryFailover(ds, cfg.Database)
 
 	fmt.Printf("TypeGORM DB handle created successfully for dialect '%s'.\n", dialectName)
 	return db, nil
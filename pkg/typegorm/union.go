@@ -0,0 +1,452 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/hooks"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Query Builder: SELECT and UNION / UNION ALL ---
+
+// Subquery is a query built without executing it: a SelectQuery (From), a UnionQuery
+// (Union, UnionAll) or a RawQuery (Raw). Subqueries are combined with Union and With,
+// and run with DB.FindQuery.
+type Subquery interface {
+	// subquerySQL renders the query selecting the columns of the given model (its own
+	// model when nil). compound reports whether it needs parentheses as a UNION operand.
+	subquerySQL(qb *queryBuild, columns *schema.Model) (query string, args []any, compound bool, err error)
+}
+
+// queryBuild carries what rendering a Subquery needs. The conditions of every SELECT
+// go through the condition rewriter and the policies (as a "Find"), and the columns
+// the policies mask are collected in masked.
+type queryBuild struct {
+	dialect  common.Dialect
+	parser   *schema.Parser
+	ctx      context.Context
+	rewriter ConditionRewriter
+	policies []PolicyFunc
+	masked   columnMask
+}
+
+// SelectQuery is one SELECT over a model's table, filtered with the same conditions and
+// options as Find. Inside a union, Order and Limit apply to that SELECT only (it is
+// parenthesized); pass them to FindQuery/FindUnion to order the combined rows.
+type SelectQuery struct {
+	model        any
+	condsAndOpts []any
+	table        string // Relation read instead of the model's table (see Table)
+	ctes         []cte
+	joinColumn   string    // Column matched against joinKeys (see JoinKeys)
+	joinKeys     *TempKeys // Temporary key table joined to the query
+}
+
+// From builds a SelectQuery:
+//
+//	recent := typegorm.From(&Order{}, map[string]any{"created_at >": since})
+//	archived := typegorm.From(&ArchivedOrder{}, map[string]any{"customer_id": id})
+func From(model any, condsAndOpts ...any) *SelectQuery {
+	return &SelectQuery{model: model, condsAndOpts: condsAndOpts}
+}
+
+// UnionQuery combines queries with UNION (distinct rows) or UNION ALL.
+type UnionQuery struct {
+	queries []Subquery
+	all     bool
+	ctes    []cte
+}
+
+// Union combines the queries with UNION, removing duplicate rows.
+func Union(queries ...Subquery) *UnionQuery {
+	return &UnionQuery{queries: queries}
+}
+
+// UnionAll combines the queries with UNION ALL, keeping duplicates (and skipping the
+// database's deduplication work).
+func UnionAll(queries ...Subquery) *UnionQuery {
+	return &UnionQuery{queries: queries, all: true}
+}
+
+// FindQuery runs a built query and scans the rows into dest, a pointer to a slice of the
+// destination model. The query must select the destination model's columns: SelectQuerys
+// over other models (e.g., a table and its archive) must have all of them, and they are
+// selected in the same order. opts apply to the whole statement: they are added to a
+// SelectQuery's own options, and order/limit the combined rows of a union:
+//
+//	res := db.FindQuery(ctx, &orders, typegorm.UnionAll(recent, archived),
+//		typegorm.Order("created_at DESC"), typegorm.Limit(50))
+func (db *DB) FindQuery(ctx context.Context, dest any, query Subquery, opts ...FindOption) *Result {
+	return findQuery(ctx, db.reader(ctx), db, db.callbacks, db.relationSource(), db.relations, db.parser, db.source.Dialect(), db.rewriter, db.policies, db.config.Query.MaxRows, "FindQuery", dest, query, opts)
+}
+
+// FindQuery runs a built query within the transaction. See DB.FindQuery.
+func (tx *Tx) FindQuery(ctx context.Context, dest any, query Subquery, opts ...FindOption) *Result {
+	return tx.findQuery(ctx, "FindQuery", dest, query, opts)
+}
+
+// FindUnion runs a compound query; it is FindQuery for a UnionQuery. Order, Limit,
+// Offset and MaxRows given here apply to the combined result.
+func (db *DB) FindUnion(ctx context.Context, dest any, union *UnionQuery, opts ...FindOption) *Result {
+	return findQuery(ctx, db.reader(ctx), db, db.callbacks, db.relationSource(), db.relations, db.parser, db.source.Dialect(), db.rewriter, db.policies, db.config.Query.MaxRows, "FindUnion", dest, union, opts)
+}
+
+// FindUnion runs the compound query within the transaction. See DB.FindUnion.
+func (tx *Tx) FindUnion(ctx context.Context, dest any, union *UnionQuery, opts ...FindOption) *Result {
+	return tx.findQuery(ctx, "FindUnion", dest, union, opts)
+}
+
+func (tx *Tx) findQuery(ctx context.Context, operation string, dest any, query Subquery, opts []FindOption) *Result {
+	ctx, leave, err := tx.enter(ctx, operation)
+	if err != nil {
+		result := &Result{Error: err}
+		wrapOpError(&result, tx.parser, operation, dest, new(string))
+		return result
+	}
+	defer leave()
+	return findQuery(ctx, tx.source, tx, tx.callbacks, tx.relationSource(), tx.relations, tx.parser, tx.dialect, tx.rewriter, tx.policies, tx.maxRows, operation, dest, query, opts)
+}
+
+func findQuery(ctx context.Context, rd reader, hookDB hooks.ContextDB, callbacks *CallbackRegistry, related relationSource, relations *virtualRelations, parser *schema.Parser, dialect common.Dialect, rewriter ConditionRewriter, policies []PolicyFunc, defaultMaxRows int, operation string, dest any, query Subquery, opts []FindOption) (result *Result) {
+	var sqlQuery string
+	defer wrapOpError(&result, parser, operation, dest, &sqlQuery)
+	defer recoverResult(&result, operation, dest)
+	result = &Result{}
+
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Pointer || destValue.IsNil() || destValue.Elem().Kind() != reflect.Slice {
+		result.Error = fmt.Errorf("destination must be a non-nil pointer to a slice, got %T", dest)
+		return result
+	}
+	sliceValue := destValue.Elem()
+	elementType := sliceValue.Type().Elem()
+	elementIsPointer := elementType.Kind() == reflect.Pointer
+	schemaType := elementType
+	if elementIsPointer {
+		schemaType = elementType.Elem()
+	}
+	if schemaType.Kind() != reflect.Struct {
+		result.Error = fmt.Errorf("destination slice elements must be structs or pointers to structs, underlying type is %s", schemaType.Kind())
+		return result
+	}
+	model, err := parser.Parse(reflect.New(schemaType).Interface())
+	if err != nil {
+		result.Error = fmt.Errorf("failed to parse schema for slice element type %s: %w", elementType.String(), err)
+		return result
+	}
+	if query == nil || reflect.ValueOf(query).IsNil() {
+		result.Error = fmt.Errorf("%s: query is nil", operation)
+		return result
+	}
+
+	qb := &queryBuild{dialect: dialect, parser: parser, ctx: ctx, rewriter: rewriter, policies: policies}
+	var (
+		statement string
+		args      []any
+		options   queryOptions
+		maxRows   int
+	)
+	if selectQuery, ok := query.(*SelectQuery); ok {
+		// The statement is the SELECT itself: opts join its own options.
+		extra := make([]any, len(opts))
+		for i, opt := range opts {
+			extra[i] = opt
+		}
+		if options, err = selectQuery.options(extra); err != nil {
+			result.Error = err
+			return result
+		}
+		maxRows = resolveMaxRows(options, defaultMaxRows)
+		statement, args, _, err = selectQuery.selectSQL(qb, model, extra, guardedLimit(options.limit, maxRows))
+	} else {
+		options = queryOptions{limit: -1}
+		for _, opt := range opts {
+			if opt != nil {
+				opt(&options)
+			}
+		}
+		maxRows = resolveMaxRows(options, defaultMaxRows)
+		statement, args, _, err = query.subquerySQL(qb, model)
+		if err == nil {
+			err = checkMaskedUse(operation, model, qb.masked.maskedFields(model), nil, options)
+		}
+		if err == nil {
+			statement += trailingClauses(dialect, model, options.orderBy, guardedLimit(options.limit, maxRows), options.offset)
+		}
+	}
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	masked := qb.masked.maskedFields(model)
+	sqlQuery = renumberBindVars(dialect, statement)
+
+	var conds []any
+	if selectQuery, ok := query.(*SelectQuery); ok {
+		conds = selectQuery.condsAndOpts
+	}
+	logSQL("Executing SQL: %s | Args: %v\n", sqlQuery, redactArgs(model, args, conds...))
+	rows, err := rd.Query(ctx, sqlQuery, args...)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to execute query for %s: %w", model.Name, err)
+		return result
+	}
+	defer rows.Close()
+
+	_, scanFields := selectColumns(dialect, model)
+	plan, err := getScanPlan(schemaType, scanFields)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	rowCount, err := scanInto(rows, sliceValue, schemaType, elementIsPointer, plan, scanCapacity(options.limit))
+	if err != nil {
+		result.Error = fmt.Errorf("failed to scan row for model %s: %w", model.Name, err)
+		return result
+	}
+	if err := rows.Err(); err != nil {
+		result.Error = fmt.Errorf("error iterating query results for %s: %w", model.Name, err)
+		return result
+	}
+	if err := checkMaxRows(rowCount, maxRows, sliceValue, model.Name); err != nil {
+		result.Error = err
+		return result
+	}
+	result.RowsAffected = int64(rowCount)
+
+	for i := 0; i < sliceValue.Len(); i++ {
+		elem := sliceValue.Index(i)
+		if elem.Kind() == reflect.Pointer {
+			zeroMaskedFields(elem.Elem(), masked)
+		} else {
+			zeroMaskedFields(elem, masked)
+		}
+		callAfterScan(model, elem)
+		if elem.Kind() != reflect.Pointer {
+			elem = elem.Addr()
+		}
+		if err := runCallbacks(ctx, callbacks, &HookContext{Event: EventAfterFind, Model: model, Value: elem.Interface(), DB: hookDB}); err != nil {
+			fmt.Printf("Warning: AfterFind hook failed for element: %v\n", err)
+		}
+	}
+	if len(options.preload) > 0 && rowCount > 0 {
+		if err := preload(ctx, related, parser, relations, destValue, options.preload, options.preloadConds); err != nil {
+			result.Error = err
+			return result
+		}
+	}
+	return result
+}
+
+// trailingClauses renders ORDER BY / LIMIT / OFFSET; limit <= 0 means no limit.
+func trailingClauses(dialect common.Dialect, model *schema.Model, orderBy string, limit, offset int) string {
+	var b strings.Builder
+	if orderBy != "" {
+		b.WriteString(" ORDER BY " + quoteOrderBy(dialect, model, orderBy))
+	}
+	if offset > 0 && limit <= 0 {
+		limit = math.MaxInt64 // OFFSET requires a LIMIT on MySQL and SQLite
+	}
+	if limit > 0 {
+		fmt.Fprintf(&b, " LIMIT %d", limit)
+	}
+	if offset > 0 {
+		fmt.Fprintf(&b, " OFFSET %d", offset)
+	}
+	return b.String()
+}
+
+func (q *SelectQuery) options(extra []any) (queryOptions, error) {
+	_, options, err := processFindArgs(append(append([]any{}, q.condsAndOpts...), extra...)...)
+	return options, err
+}
+
+func (q *SelectQuery) subquerySQL(qb *queryBuild, columns *schema.Model) (string, []any, bool, error) {
+	return q.selectSQL(qb, columns, nil, 0)
+}
+
+// selectSQL renders the SELECT with extra options appended to its own; a positive limit
+// overrides the Limit option (FindQuery passes the max-rows guarded limit).
+func (q *SelectQuery) selectSQL(qb *queryBuild, columns *schema.Model, extra []any, limit int) (string, []any, bool, error) {
+	if q.model == nil {
+		return "", nil, false, fmt.Errorf("query: From requires a model")
+	}
+	model, err := qb.parser.Parse(q.model)
+	if err != nil {
+		return "", nil, false, fmt.Errorf("query: failed to parse schema for type %T: %w", q.model, err)
+	}
+	if columns == nil {
+		columns = model
+	} else if err := checkUnionColumns(columns, model); err != nil {
+		return "", nil, false, err
+	}
+
+	condition, options, err := processFindArgs(append(append([]any{}, q.condsAndOpts...), extra...)...)
+	if err != nil {
+		return "", nil, false, fmt.Errorf("query %s: %w", model.Name, err)
+	}
+	policyCtx, mask := withColumnMask(qb.ctx, qb.policies)
+	userCondition := condition
+	if condition, err = rewriteCondition(policyCtx, qb.rewriter, qb.policies, "Find", model, condition); err != nil {
+		return "", nil, false, err
+	}
+	masked := mask.maskedFields(model)
+	if err := checkMaskedUse("Find", model, masked, userCondition, options); err != nil {
+		return "", nil, false, err
+	}
+	for _, field := range masked { // Cleared in the destination rows, by column name
+		qb.masked.names = append(qb.masked.names, field.DBName)
+	}
+	whereClauses, whereArgs, err := buildWhereClause(qb.dialect, model, condition)
+	if err != nil {
+		return "", nil, false, fmt.Errorf("query %s: %w", model.Name, err)
+	}
+	whereClauses, whereArgs, err = applyDefaultScope(qb.dialect, model, whereClauses, whereArgs, &options)
+	if err != nil {
+		return "", nil, false, fmt.Errorf("query %s: %w", model.Name, err)
+	}
+	if limit <= 0 {
+		limit = options.limit
+	}
+
+	withSQL, args, err := renderCTEs(qb, q.ctes)
+	if err != nil {
+		return "", nil, false, err
+	}
+	table := model.QualifiedTableName()
+	if q.table != "" {
+		table = q.table
+	}
+	selectList, _ := selectColumns(qb.dialect, columns)
+
+	groupBy, havingArgs, err := options.groupClause(qb.dialect, model)
+	if err != nil {
+		return "", nil, false, fmt.Errorf("query %s: %w", model.Name, err)
+	}
+
+	var b strings.Builder
+	b.WriteString(withSQL)
+	b.WriteString(options.selectKeyword() + selectList + " FROM " + quoteQualified(qb.dialect, table))
+	if q.joinKeys != nil {
+		field, ok := model.GetFieldByDBName(q.joinColumn)
+		if !ok {
+			if field, ok = model.GetField(q.joinColumn); !ok {
+				return "", nil, false, fmt.Errorf("query %s: unknown column %q for JoinKeys", model.Name, q.joinColumn)
+			}
+		}
+		b.WriteString(joinKeysSQL(qb.dialect, table, field.DBName, q.joinKeys))
+	}
+	if len(whereClauses) > 0 {
+		b.WriteString(" WHERE " + strings.Join(whereClauses, " AND "))
+	}
+	b.WriteString(groupBy)
+	b.WriteString(trailingClauses(qb.dialect, model, options.orderBy, limit, options.offset))
+	compound := withSQL != "" || options.orderBy != "" || limit > 0 || options.offset > 0
+	return b.String(), append(append(args, whereArgs...), havingArgs...), compound, nil
+}
+
+// subquerySQL renders the operands joined by UNION [ALL]. Without a column model, the
+// first SelectQuery operand's model defines the columns of every operand. Operands with
+// their own ORDER BY, LIMIT or WITH are parenthesized (SQLite, which rejects
+// parenthesized operands, gets a derived table instead).
+func (u *UnionQuery) subquerySQL(qb *queryBuild, columns *schema.Model) (string, []any, bool, error) {
+	if len(u.queries) < 2 {
+		return "", nil, false, fmt.Errorf("union: at least two queries are required")
+	}
+	if columns == nil {
+		if first, ok := u.queries[0].(*SelectQuery); ok && first.model != nil {
+			model, err := qb.parser.Parse(first.model)
+			if err != nil {
+				return "", nil, false, fmt.Errorf("union: failed to parse schema for type %T: %w", first.model, err)
+			}
+			columns = model
+		}
+	}
+	operator := " UNION "
+	if u.all {
+		operator = " UNION ALL "
+	}
+
+	withSQL, args, err := renderCTEs(qb, u.ctes)
+	if err != nil {
+		return "", nil, false, err
+	}
+	parts := make([]string, len(u.queries))
+	for i, q := range u.queries {
+		if q == nil || reflect.ValueOf(q).IsNil() {
+			return "", nil, false, fmt.Errorf("union: query %d is nil", i+1)
+		}
+		part, partArgs, compound, err := q.subquerySQL(qb, columns)
+		if err != nil {
+			return "", nil, false, fmt.Errorf("union: query %d: %w", i+1, err)
+		}
+		if compound {
+			if name := qb.dialect.Name(); name == "sqlite" || name == "sqlite3" {
+				part = "SELECT * FROM (" + part + ")"
+			} else {
+				part = "(" + part + ")"
+			}
+		}
+		parts[i] = part
+		args = append(args, partArgs...)
+	}
+	return withSQL + strings.Join(parts, operator), args, true, nil
+}
+
+// checkUnionColumns verifies that a SELECT's model has every column of the destination
+// model, so the combined rows line up.
+func checkUnionColumns(destModel, model *schema.Model) error {
+	if model == destModel {
+		return nil
+	}
+	for _, field := range destModel.Fields {
+		if field.IsIgnored {
+			continue
+		}
+		if other, ok := model.GetFieldByDBName(field.DBName); !ok || other.IsIgnored {
+			return fmt.Errorf("union: %s has no column %q of %s; combined queries must have compatible columns", model.Name, field.DBName, destModel.Name)
+		}
+	}
+	return nil
+}
+
+// renumberBindVars rewrites the placeholders of a statement assembled from independently
+// built parts, so numbered placeholders ($1, $2, ...) follow the order of the arguments.
+// Dialects with positional placeholders (?) are left untouched.
+func renumberBindVars(dialect common.Dialect, query string) string {
+	first := dialect.BindVar(1)
+	if first == dialect.BindVar(2) || !strings.HasSuffix(first, "1") {
+		return query
+	}
+	prefix := strings.TrimSuffix(first, "1")
+
+	var b strings.Builder
+	b.Grow(len(query))
+	n := 0
+	inString := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		if c == '\'' {
+			inString = !inString
+		}
+		if !inString && strings.HasPrefix(query[i:], prefix) {
+			j := i + len(prefix)
+			for j < len(query) && query[j] >= '0' && query[j] <= '9' {
+				j++
+			}
+			if j > i+len(prefix) {
+				n++
+				b.WriteString(dialect.BindVar(n))
+				i = j - 1
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type archivedMaskUser struct {
+	ID         uint `typegorm:"primaryKey"`
+	Name       string
+	Email      string
+	Age        int
+	ArchivedAt time.Time
+}
+
+type numberedDialect struct{ mockDialect }
+
+func (d *numberedDialect) BindVar(i int) string { return "$" + string(rune('0'+i)) }
+
+func TestFindUnion_CombinesQueries(t *testing.T) {
+	db, source := newMockDB()
+	queueMaskUsers(source, 2)
+
+	var users []maskUser
+	union := UnionAll(
+		From(&maskUser{}, map[string]any{"age >": 30}),
+		From(&archivedMaskUser{}, map[string]any{"name": "ana"}, Order("age DESC"), Limit(5)),
+	)
+	result := db.FindUnion(context.Background(), &users, union, Order("name"), Limit(10))
+	require.NoError(t, result.Error)
+	assert.Len(t, users, 2)
+
+	last := source.lastStatement()
+	assert.Equal(t, "SELECT `id`, `name`, `email`, `age` FROM `mask_users` WHERE `age` > ?"+
+		" UNION ALL (SELECT `id`, `name`, `email`, `age` FROM `archived_mask_users` WHERE `name` = ? ORDER BY `age` DESC LIMIT 5)"+
+		" ORDER BY `name` LIMIT 10", last.SQL)
+	assert.Equal(t, []any{30, "ana"}, last.Args)
+}
+
+func TestFindUnion_RejectsIncompatibleQueries(t *testing.T) {
+	db, _ := newMockDB()
+	var users []archivedMaskUser
+	result := db.FindUnion(context.Background(), &users, Union(From(&archivedMaskUser{}), From(&maskUser{})))
+	assert.ErrorContains(t, result.Error, "archived_at")
+
+	result = db.FindUnion(context.Background(), &users, Union(From(&archivedMaskUser{})))
+	assert.Error(t, result.Error)
+}
+
+func TestUnionSQL_SQLiteNestsOrderedQueries(t *testing.T) {
+	db, _ := newMockDB()
+	qb := &queryBuild{dialect: &mockDialect{name: "sqlite"}, parser: db.parser}
+
+	query, _, _, err := Union(From(&maskUser{}), From(&maskUser{}, Limit(1))).subquerySQL(qb, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT `id`, `name`, `email`, `age` FROM `mask_users` UNION SELECT * FROM (SELECT `id`, `name`, `email`, `age` FROM `mask_users` LIMIT 1)", query)
+}
+
+func TestFindQuery_SelectMergesOptions(t *testing.T) {
+	db, source := newMockDB()
+	queueMaskUsers(source, 1)
+
+	var users []maskUser
+	query := From(&maskUser{}, map[string]any{"age >": 18}, Order("name"))
+	require.NoError(t, db.FindQuery(context.Background(), &users, query, Limit(5)).Error)
+	assert.Equal(t, "SELECT `id`, `name`, `email`, `age` FROM `mask_users` WHERE `age` > ? ORDER BY `name` LIMIT 5", source.lastStatement().SQL)
+}
+
+func TestRenumberBindVars(t *testing.T) {
+	dialect := &numberedDialect{mockDialect{name: "postgres"}}
+	assert.Equal(t, "a = $1 UNION b = $2 AND c IN ($3, $4) AND d = '$1'",
+		renumberBindVars(dialect, "a = $1 UNION b = $1 AND c IN ($1, $2) AND d = '$1'"))
+	assert.Equal(t, "a = ?", renumberBindVars(&mockDialect{name: "mysql"}, "a = ?"))
+}
@@ -0,0 +1,186 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/hooks"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Conditional Updates ---
+
+// execer is the write side shared by common.DataSource and common.Tx.
+type execer interface {
+	Exec(ctx context.Context, query string, args ...any) (common.Result, error)
+}
+
+// UpdateIf updates the record identified by the primary key of modelWithValue only if
+// the extra conditions also hold, in a single atomic statement:
+//
+//	res := db.UpdateIf(ctx, &order, map[string]any{"status": "paid"}, map[string]any{"status": "pending"})
+//	if res.Error == nil && res.RowsAffected == 0 { /* illegal transition or record gone */ }
+//
+// conds accepts the same forms as Find (struct pointer or map with operators).
+// RowsAffected is 0 when the conditions do not match; that is not an error. A change of
+// a state machine field is validated as in Updates (see StateMachine).
+func (db *DB) UpdateIf(ctx context.Context, modelWithValue any, data map[string]any, conds any) *Result {
+	defer invalidateEntity(ctx, db.cache, db.parser, modelWithValue)
+	result, history := updateIf(ctx, db.conn(ctx), db, db.callbacks, db.machines, clock(ctx, db.now), db.parser, db.source.Dialect(), db.rewriter, db.policies, modelWithValue, data, conds)
+	if history != nil && result.Error == nil {
+		if err := db.create(ctx, history).Error; err != nil {
+			result.Error = fmt.Errorf("state machine: failed to record transition: %w", err)
+		}
+	}
+	return result
+}
+
+// UpdateIf performs a conditional update within the transaction. See DB.UpdateIf.
+func (tx *Tx) UpdateIf(ctx context.Context, modelWithValue any, data map[string]any, conds any) *Result {
+	err := tx.checkWritable("UpdateIf")
+	if err == nil {
+		var leave func()
+		if ctx, leave, err = tx.enter(ctx, "UpdateIf"); err == nil {
+			defer leave()
+		}
+	}
+	if err != nil {
+		result := &Result{Error: err}
+		wrapOpError(&result, tx.parser, "UpdateIf", modelWithValue, new(string))
+		return result
+	}
+	defer tx.invalidateEntity(ctx, modelWithValue)
+	result, history := updateIf(ctx, tx.source, tx, tx.callbacks, tx.machines, clock(ctx, tx.now), tx.parser, tx.dialect, tx.rewriter, tx.policies, modelWithValue, data, conds)
+	if history != nil && result.Error == nil {
+		if err := tx.create(ctx, history).Error; err != nil {
+			result.Error = fmt.Errorf("tx: state machine: failed to record transition: %w", err)
+		}
+	}
+	return result
+}
+
+// updateIf runs the conditional update, and returns the state transition to record
+// when it changed a state machine field of the row.
+func updateIf(ctx context.Context, exec counterExecer, hookDB hooks.ContextDB, callbacks *CallbackRegistry, machines *stateMachines, now time.Time, parser *schema.Parser, dialect common.Dialect, rewriter ConditionRewriter, policies []PolicyFunc, modelWithValue any, data map[string]any, conds any) (result *Result, history *StateTransition) {
+	var sqlQuery string
+	defer wrapOpError(&result, parser, "UpdateIf", modelWithValue, &sqlQuery)
+	defer recoverResult(&result, "UpdateIf", modelWithValue)
+	result = &Result{}
+
+	reflectValue := reflect.ValueOf(modelWithValue)
+	if reflectValue.Kind() != reflect.Pointer || reflectValue.IsNil() || reflectValue.Elem().Kind() != reflect.Struct {
+		result.Error = fmt.Errorf("modelWithValue must be a non-nil pointer to a struct, got %T", modelWithValue)
+		return result, nil
+	}
+	structValue := reflectValue.Elem()
+	model, err := parser.Parse(modelWithValue)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to parse schema for type %s: %w", structValue.Type().Name(), err)
+		return result, nil
+	}
+	if len(model.PrimaryKeys) == 0 {
+		result.Error = fmt.Errorf("cannot update: model %s has no primary key defined", model.Name)
+		return result, nil
+	}
+
+	// --- Call BeforeUpdate Hook ---
+	if err := runCallbacks(ctx, callbacks, &HookContext{Event: EventBeforeUpdate, Model: model, Value: modelWithValue, DB: hookDB, Data: data}); err != nil {
+		result.Error = fmt.Errorf("BeforeUpdate hook failed: %w", err)
+		return result, nil
+	}
+	// --- End Hook Call ---
+	data = touchUpdatedAt(now, model, structValue, data)
+
+	// 1. SET clause
+	setClauses := []string{}
+	args := []any{}
+	var sensitive []any
+	for dbColName, value := range data {
+		field, ok := model.GetFieldByDBName(dbColName)
+		if !ok {
+			result.Error = fmt.Errorf("invalid column name '%s' provided in update data for model %s", dbColName, model.Name)
+			return result, nil
+		}
+		if field.IsIgnored || field.IsPrimaryKey {
+			fmt.Printf("Warning: Skipping update for primary key or ignored field '%s'\n", dbColName)
+			continue
+		}
+		value, err := interceptValue(ctx, model, dbColName, value)
+		if err != nil {
+			result.Error = err
+			return result, nil
+		}
+		args = append(args, value)
+		setClauses = append(setClauses, assignment(dialect, dbColName, len(args)))
+		if field.Sensitive {
+			sensitive = append(sensitive, value)
+		}
+	}
+	if len(setClauses) == 0 {
+		result.Error = fmt.Errorf("no valid fields provided for update")
+		return result, nil
+	}
+
+	// 2. WHERE: primary key (and the state validated by the state machine) plus the extra
+	// conditions, after the condition rewriter and the policies (as an "Update" of the
+	// extra conditions)
+	pkWhere := make([]string, 0, len(model.PrimaryKeys))
+	pkArgs := make([]any, 0, len(model.PrimaryKeys))
+	for i, pkField := range model.PrimaryKeys {
+		pkValue := structValue.FieldByName(pkField.GoName)
+		if pkValue.IsZero() {
+			result.Error = fmt.Errorf("cannot update: primary key field %s has zero value", pkField.GoName)
+			return result, nil
+		}
+		pkArgs = append(pkArgs, pkValue.Interface())
+		pkWhere = append(pkWhere, assignment(dialect, pkField.DBName, i+1))
+	}
+	transition, err := checkTransition(ctx, exec, machines, dialect, model, data, pkWhere, pkArgs)
+	if err != nil {
+		result.Error = err
+		return result, nil
+	}
+	history = transition.history(model, pkArgs, now)
+	whereClauses, whereArgs := transition.guard(dialect, pkWhere, pkArgs)
+	args = append(args, whereArgs...)
+	cond, err := rewriteCondition(ctx, rewriter, policies, "Update", model, conds)
+	if err != nil {
+		result.Error = err
+		return result, nil
+	}
+	condClauses, condArgs, err := buildWhereClause(dialect, model, cond)
+	if err != nil {
+		result.Error = err
+		return result, nil
+	}
+	whereClauses = append(whereClauses, condClauses...)
+	args = append(args, condArgs...)
+
+	sqlQuery = renumberBindVars(dialect, updateSQL(dialect, model, setClauses, whereClauses))
+
+	// 3. Execute
+	logSQL("Executing SQL: %s | Args: %v\n", sqlQuery, redactArgs(model, args, sensitive, conds))
+	sqlResult, err := exec.Exec(ctx, sqlQuery, args...)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to execute conditional update for %s: %w", model.Name, err)
+		return result, nil
+	}
+	affected, err := sqlResult.RowsAffected()
+	if err != nil {
+		fmt.Printf("Warning: could not get RowsAffected after update: %v\n", err)
+	}
+	result.RowsAffected = affected
+	if affected == 0 {
+		fmt.Printf("Conditional update did not match any row for %s.\n", model.Name)
+		return result, nil
+	}
+
+	// --- Call AfterUpdate Hook ---
+	if err := runCallbacks(ctx, callbacks, &HookContext{Event: EventAfterUpdate, Model: model, Value: modelWithValue, DB: hookDB}); err != nil {
+		fmt.Printf("Warning: AfterUpdate hook failed: %v\n", err)
+	}
+	return result, history
+}
@@ -0,0 +1,29 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateIf(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	user := &maskUser{ID: 3}
+
+	result := db.UpdateIf(ctx, user, map[string]any{"age": 41}, map[string]any{"age": 40})
+	require.NoError(t, result.Error)
+	assert.Equal(t, int64(1), result.RowsAffected)
+	stmt := source.lastStatement()
+	assert.Equal(t, "UPDATE `mask_users` SET `age` = ? WHERE `id` = ? AND `age` = ?", stmt.SQL)
+	assert.Equal(t, []any{41, uint(3), 40}, stmt.Args)
+
+	source.affected = 0
+	result = db.UpdateIf(ctx, user, map[string]any{"age": 41}, map[string]any{"age": 40})
+	require.NoError(t, result.Error, "a condition that does not match is not an error")
+	assert.Zero(t, result.RowsAffected)
+
+	assert.Error(t, db.UpdateIf(ctx, user, map[string]any{"age": 41}, map[string]any{"unknown": 1}).Error)
+}
@@ -0,0 +1,72 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Field Mask Updates ---
+
+// UpdatesMask updates only the fields named in mask, reading their values from the
+// struct. It bridges map-based Updates and a full-struct save for APIs that receive
+// update masks (e.g. google.protobuf.FieldMask):
+//
+//	db.UpdatesMask(ctx, &user, []string{"Name", "Email"})
+//
+// Mask entries may be Go field names or column names. Zero values are written too,
+// which is the point of a mask. Primary keys cannot be part of the mask.
+func (db *DB) UpdatesMask(ctx context.Context, modelWithValue any, mask []string) *Result {
+	model, err := db.GetModel(modelWithValue)
+	if err != nil {
+		return &Result{Error: fmt.Errorf("failed to parse schema for %T: %w", modelWithValue, err)}
+	}
+	data, err := maskedValues(model, modelWithValue, mask)
+	if err != nil {
+		return &Result{Error: err}
+	}
+	return db.Updates(ctx, modelWithValue, data)
+}
+
+// UpdatesMask updates only the masked fields within the transaction. See DB.UpdatesMask.
+func (tx *Tx) UpdatesMask(ctx context.Context, modelWithValue any, mask []string) *Result {
+	model, err := tx.parser.Parse(modelWithValue)
+	if err != nil {
+		return &Result{Error: fmt.Errorf("tx: failed to parse schema for %T: %w", modelWithValue, err)}
+	}
+	data, err := maskedValues(model, modelWithValue, mask)
+	if err != nil {
+		return &Result{Error: err}
+	}
+	return tx.Updates(ctx, modelWithValue, data)
+}
+
+// maskedValues builds the column -> value map for the masked fields of a struct pointer.
+func maskedValues(model *schema.Model, modelWithValue any, mask []string) (map[string]any, error) {
+	if len(mask) == 0 {
+		return nil, fmt.Errorf("update mask is empty")
+	}
+	reflectValue := reflect.ValueOf(modelWithValue)
+	if reflectValue.Kind() != reflect.Pointer || reflectValue.IsNil() || reflectValue.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("modelWithValue must be a non-nil pointer to a struct, got %T", modelWithValue)
+	}
+	structValue := reflectValue.Elem()
+
+	data := make(map[string]any, len(mask))
+	for _, name := range mask {
+		field, ok := model.GetField(name)
+		if !ok {
+			field, ok = model.GetFieldByDBName(name)
+		}
+		if !ok {
+			return nil, fmt.Errorf("update mask: unknown field '%s' for model %s", name, model.Name)
+		}
+		if field.IsPrimaryKey {
+			return nil, fmt.Errorf("update mask: primary key field '%s' cannot be updated", name)
+		}
+		data[field.DBName] = structValue.FieldByName(field.GoName).Interface()
+	}
+	return data, nil
+}
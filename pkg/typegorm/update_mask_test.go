@@ -0,0 +1,32 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type maskUser struct {
+	ID    uint `typegorm:"primaryKey;autoIncrement"`
+	Name  string
+	Email string
+	Age   int
+}
+
+func TestUpdatesMask(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	user := &maskUser{ID: 3, Name: "Ana", Email: "", Age: 40}
+
+	require.NoError(t, db.UpdatesMask(ctx, user, []string{"Name", "email"}).Error)
+	stmt := source.lastStatement()
+	assert.Contains(t, stmt.SQL, "UPDATE `mask_users` SET ")
+	assert.NotContains(t, stmt.SQL, "`age`")
+	assert.ElementsMatch(t, []any{"Ana", "", uint(3)}, stmt.Args, "zero values in the mask are written")
+
+	assert.Error(t, db.UpdatesMask(ctx, user, []string{"Nickname"}).Error)
+	assert.Error(t, db.UpdatesMask(ctx, user, []string{"ID"}).Error)
+	assert.Error(t, db.UpdatesMask(ctx, user, nil).Error)
+}
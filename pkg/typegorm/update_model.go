@@ -0,0 +1,137 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Struct Updates ---
+
+// Update writes every column of the struct but the primary key and the timestamps, zero
+// values included, to the row with its primary key (UpdatedAt is set to now). The soft
+// delete timestamp (see Restore) and the foreign keys with a counter cache are left
+// out too, so a stale struct neither undeletes the row nor moves it to another parent
+// behind the counters' back; use Updates to change them:
+//
+//	user.Name, user.Age = "Ana", 0
+//	db.Update(ctx, &user)
+func (db *DB) Update(ctx context.Context, modelWithValue any) *Result {
+	model, err := db.GetModel(modelWithValue)
+	if err != nil {
+		return &Result{Error: fmt.Errorf("failed to parse schema for %T: %w", modelWithValue, err)}
+	}
+	data, err := structValues(model, modelWithValue, false)
+	if err != nil {
+		return &Result{Error: err}
+	}
+	return db.Updates(ctx, modelWithValue, data)
+}
+
+// Update writes every column of the struct within the transaction. See DB.Update.
+func (tx *Tx) Update(ctx context.Context, modelWithValue any) *Result {
+	model, err := tx.parser.Parse(modelWithValue)
+	if err != nil {
+		return &Result{Error: fmt.Errorf("tx: failed to parse schema for %T: %w", modelWithValue, err)}
+	}
+	data, err := structValues(model, modelWithValue, false)
+	if err != nil {
+		return &Result{Error: err}
+	}
+	return tx.Updates(ctx, modelWithValue, data)
+}
+
+// UpdatesModel updates the row of modelWithValue with the non-zero fields of changes, a
+// pointer to the same struct type, and copies them onto modelWithValue once written:
+//
+//	db.UpdatesModel(ctx, &user, &User{Name: "Ana", Age: 5})
+//
+// Zero values cannot be written this way (use Update, UpdatesMask or Updates), and
+// primary keys in changes are ignored.
+func (db *DB) UpdatesModel(ctx context.Context, modelWithValue any, changes any) *Result {
+	model, err := db.GetModel(modelWithValue)
+	if err != nil {
+		return &Result{Error: fmt.Errorf("failed to parse schema for %T: %w", modelWithValue, err)}
+	}
+	data, err := changedValues(model, modelWithValue, changes)
+	if err != nil {
+		return &Result{Error: err}
+	}
+	result := db.Updates(ctx, modelWithValue, data)
+	if result.Error == nil {
+		copyChanges(model, modelWithValue, changes, data)
+	}
+	return result
+}
+
+// UpdatesModel updates the non-zero fields of changes within the transaction. See
+// DB.UpdatesModel.
+func (tx *Tx) UpdatesModel(ctx context.Context, modelWithValue any, changes any) *Result {
+	model, err := tx.parser.Parse(modelWithValue)
+	if err != nil {
+		return &Result{Error: fmt.Errorf("tx: failed to parse schema for %T: %w", modelWithValue, err)}
+	}
+	data, err := changedValues(model, modelWithValue, changes)
+	if err != nil {
+		return &Result{Error: err}
+	}
+	result := tx.Updates(ctx, modelWithValue, data)
+	if result.Error == nil {
+		copyChanges(model, modelWithValue, changes, data)
+	}
+	return result
+}
+
+// structValues builds the column -> value map of the updatable fields of a struct
+// pointer: all of them but the soft delete and counter cache columns, or only the
+// non-zero ones.
+func structValues(model *schema.Model, value any, nonZero bool) (map[string]any, error) {
+	reflectValue := reflect.ValueOf(value)
+	if reflectValue.Kind() != reflect.Pointer || reflectValue.IsNil() || reflectValue.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("value must be a non-nil pointer to a struct, got %T", value)
+	}
+	structValue := reflectValue.Elem()
+
+	data := make(map[string]any, len(model.Fields))
+	for _, field := range model.Fields {
+		if field.IsIgnored || field.IsComputed || field.IsPrimaryKey || field.IsAutoCreateTime() || field.IsAutoUpdateTime() {
+			continue
+		}
+		fieldValue := structValue.FieldByName(field.GoName)
+		if !fieldValue.IsValid() || (nonZero && fieldValue.IsZero()) {
+			continue
+		}
+		if !nonZero && (field == model.SoftDeleteField || field.CounterCache != "") {
+			continue
+		}
+		data[field.DBName] = fieldValue.Interface()
+	}
+	return data, nil
+}
+
+// changedValues builds the column -> value map of the non-zero fields of changes.
+func changedValues(model *schema.Model, modelWithValue, changes any) (map[string]any, error) {
+	if reflect.TypeOf(changes) != reflect.TypeOf(modelWithValue) {
+		return nil, fmt.Errorf("changes must be a %T like the updated value, got %T", modelWithValue, changes)
+	}
+	data, err := structValues(model, changes, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no non-zero fields to update in %T", changes)
+	}
+	return data, nil
+}
+
+// copyChanges sets the written fields of changes onto modelWithValue.
+func copyChanges(model *schema.Model, modelWithValue, changes any, data map[string]any) {
+	target, source := reflect.ValueOf(modelWithValue).Elem(), reflect.ValueOf(changes).Elem()
+	for column := range data {
+		if field, ok := model.GetFieldByDBName(column); ok {
+			target.FieldByName(field.GoName).Set(source.FieldByName(field.GoName))
+		}
+	}
+}
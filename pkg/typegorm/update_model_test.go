@@ -0,0 +1,57 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdate_WritesEveryColumn(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+
+	require.NoError(t, db.Update(ctx, &maskUser{ID: 3, Name: "Ana"}).Error)
+	stmt := source.lastStatement()
+	assert.Contains(t, stmt.SQL, "UPDATE `mask_users` SET ")
+	assert.Contains(t, stmt.SQL, "`email` = ?")
+	assert.NotContains(t, stmt.SQL, "SET `id`")
+	assert.ElementsMatch(t, []any{"Ana", "", 0, uint(3)}, stmt.Args, "zero values are written")
+
+	assert.Error(t, db.Update(ctx, &maskUser{Name: "Ana"}).Error, "the primary key is required")
+
+	type softCounterPost struct {
+		ID        int64 `typegorm:"primaryKey;autoIncrement"`
+		UserID    int64 `typegorm:"references:users.id;counterCache:posts_count"`
+		Title     string
+		DeletedAt *time.Time `typegorm:"softDelete"`
+	}
+	require.NoError(t, db.Update(ctx, &softCounterPost{ID: 4, Title: "hi"}).Error)
+	stmt = source.lastStatement()
+	assert.NotContains(t, stmt.SQL, "`deleted_at` =", "a stale struct does not undelete the row")
+	assert.NotContains(t, stmt.SQL, "`user_id` =", "counter cache keys are not moved")
+	assert.Equal(t, []any{"hi", int64(4)}, stmt.Args)
+}
+
+func TestUpdatesModel_WritesNonZeroFields(t *testing.T) {
+	db, source := newMockDB()
+	ctx := context.Background()
+	user := &maskUser{ID: 3, Name: "Ana", Email: "ana@example.com", Age: 40}
+
+	require.NoError(t, db.UpdatesModel(ctx, user, &maskUser{ID: 9, Name: "Bia", Age: 5}).Error)
+	stmt := source.lastStatement()
+	assert.NotContains(t, stmt.SQL, "`email`")
+	assert.ElementsMatch(t, []any{"Bia", 5, uint(3)}, stmt.Args)
+	assert.Equal(t, &maskUser{ID: 3, Name: "Bia", Email: "ana@example.com", Age: 5}, user, "the changes are copied onto the value")
+
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.UpdatesModel(ctx, user, &maskUser{Email: "bia@example.com"}).Error)
+	assert.ElementsMatch(t, []any{"bia@example.com", uint(3)}, source.lastStatement().Args)
+	require.NoError(t, tx.Rollback())
+
+	assert.ErrorContains(t, db.UpdatesModel(ctx, user, &maskUser{}).Error, "no non-zero fields")
+	assert.ErrorContains(t, db.UpdatesModel(ctx, user, maskUser{Name: "x"}).Error, "changes must be a *typegorm.maskUser")
+}
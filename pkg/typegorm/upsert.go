@@ -0,0 +1,273 @@
+package typegorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// --- Upsert ---
+
+// Save inserts value when its primary key is zero, and otherwise inserts or updates the
+// row with that key in one statement (see OnConflict): every inserted column but the
+// key and the creation timestamp is overwritten.
+//
+//	user := User{ID: 42, Name: "Ana"}
+//	db.Save(ctx, &user) // Row 42 now has the name Ana, whether it existed or not
+//
+// When the row may exist, Save first reads its counter cache foreign keys and state
+// (see StateMachine): a state change the machine does not allow is rejected with a
+// *TransitionError, and an updated row moves its counters to the new parent instead
+// of incrementing them. The read and the upsert are separate statements: run Save in
+// a Tx that locks the row when concurrent writers may change it in between.
+func (db *DB) Save(ctx context.Context, value any, opts ...CreateOption) (result *Result) {
+	model, err := db.GetModel(value)
+	if err != nil {
+		return db.Create(ctx, value, opts...) // Reports the invalid value
+	}
+	opts, row, err := saveOptions(ctx, db.conn(ctx), db.machines, db.source.Dialect(), model, value, opts)
+	if err != nil {
+		result = &Result{Error: err}
+		wrapOpError(&result, db.parser, "Save", value, new(string))
+		return result
+	}
+	result = db.Create(ctx, value, opts...)
+	if history := row.history(model, clock(ctx, db.now)); history != nil && result.Error == nil {
+		if err := db.create(ctx, history).Error; err != nil {
+			result.Error = fmt.Errorf("state machine: failed to record transition: %w", err)
+		}
+	}
+	return result
+}
+
+// Save inserts or updates value within the transaction. See DB.Save.
+func (tx *Tx) Save(ctx context.Context, value any, opts ...CreateOption) (result *Result) {
+	model, err := tx.parser.Parse(value)
+	if err != nil {
+		return tx.Create(ctx, value, opts...)
+	}
+	opts, row, err := saveOptions(ctx, tx.source, tx.machines, tx.dialect, model, value, opts)
+	if err != nil {
+		result = &Result{Error: err}
+		wrapOpError(&result, tx.parser, "Save", value, new(string))
+		return result
+	}
+	result = tx.Create(ctx, value, opts...)
+	if history := row.history(model, clock(ctx, tx.now)); history != nil && result.Error == nil {
+		if err := tx.create(ctx, history).Error; err != nil {
+			result.Error = fmt.Errorf("tx: state machine: failed to record transition: %w", err)
+		}
+	}
+	return result
+}
+
+// saveOptions adds the upsert on the primary key when value has one, with the row it
+// may update (see savedRow).
+func saveOptions(ctx context.Context, conn counterExecer, registry *stateMachines, dialect common.Dialect, model *schema.Model, value any, opts []CreateOption) ([]CreateOption, *savedRow, error) {
+	structValue := reflect.Indirect(reflect.ValueOf(value))
+	if structValue.Kind() != reflect.Struct || len(model.PrimaryKeys) == 0 {
+		return opts, nil, nil
+	}
+	key := make([]string, 0, len(model.PrimaryKeys))
+	pkWhere := make([]string, 0, len(model.PrimaryKeys))
+	pkArgs := make([]any, 0, len(model.PrimaryKeys))
+	for i, pk := range model.PrimaryKeys {
+		pkValue := structValue.FieldByName(pk.GoName)
+		if !pkValue.IsValid() || pkValue.IsZero() {
+			return opts, nil, nil // New row: a plain insert
+		}
+		key = append(key, pk.DBName)
+		pkWhere = append(pkWhere, assignment(dialect, pk.DBName, i+1))
+		pkArgs = append(pkArgs, pkValue.Interface())
+	}
+	row, err := readSavedRow(ctx, conn, registry, dialect, model, structValue, pkWhere, pkArgs)
+	if err != nil {
+		return nil, nil, err
+	}
+	saved := func(opts *createOptions) { opts.saved = row }
+	return append([]CreateOption{OnConflict(Conflict{Columns: key}), saved}, opts...), row, nil
+}
+
+// savedRow is the row a Save may update, as read before its upsert.
+type savedRow struct {
+	existed    bool
+	updated    bool         // The upsert updated the row (set by Create)
+	keys       []counterKey // Counter cache foreign keys of the row (nil values when NULL)
+	transition *pendingTransition
+	pkArgs     []any
+}
+
+// readSavedRow reads the counter cache foreign keys and the state of the row with the
+// primary key of a Save, and validates the state transition.
+func readSavedRow(ctx context.Context, conn counterExecer, registry *stateMachines, dialect common.Dialect, model *schema.Model, structValue reflect.Value, pkWhere []string, pkArgs []any) (*savedRow, error) {
+	row := &savedRow{pkArgs: pkArgs}
+	fields := append([]*schema.Field{}, model.CounterCaches...)
+	machine := registry.get(model.Type)
+	if machine != nil {
+		fields = append(fields, model.FieldsByName[machine.Field])
+	}
+	if len(fields) == 0 {
+		return row, nil
+	}
+
+	columns := make([]string, len(fields))
+	dest := make([]any, len(fields))
+	for i, field := range fields {
+		columns[i] = dialect.Quote(field.DBName)
+		dest[i] = new(any)
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s", strings.Join(columns, ", "), quoteTable(dialect, model), strings.Join(pkWhere, " AND "))
+	logSQL("Executing SQL: %s | Args: %v\n", query, redactArgs(model, pkArgs))
+	if err := conn.QueryRow(ctx, query, pkArgs...).Scan(dest...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return row, nil // Save inserts the row
+		}
+		return nil, fmt.Errorf("save: failed to read the current row of %s: %w", model.Name, err)
+	}
+	row.existed = true
+	for i, field := range model.CounterCaches {
+		row.keys = append(row.keys, counterKey{field: field, value: joinKey(*(dest[i].(*any)))})
+	}
+	if machine != nil {
+		from := stateString(*(dest[len(dest)-1].(*any)))
+		to := stateString(structValue.FieldByName(machine.Field).Interface())
+		if !machine.CanTransition(from, to) {
+			return nil, &TransitionError{Model: model.Name, Field: machine.Field, From: from, To: to}
+		}
+		row.transition = &pendingTransition{machine: machine, from: from, to: to}
+	}
+	return row, nil
+}
+
+// applyCounterCaches maintains the counter caches of the parents referenced by keys
+// after an insert: incremented for an inserted row, and for a row updated by Save,
+// moved from the previous parent to the new one when the foreign key changed.
+func (r *savedRow) applyCounterCaches(ctx context.Context, conn counterExecer, dialect common.Dialect, keys []counterKey, affected int64) error {
+	if r == nil {
+		return applyCounterCaches(ctx, conn, dialect, keys, 1)
+	}
+	r.updated = r.existed
+	if dialect.Name() == "mysql" {
+		r.updated = affected != 1 // 1 for an inserted row, 2 for an updated one, 0 when unchanged
+	}
+	if !r.updated {
+		return applyCounterCaches(ctx, conn, dialect, keys, 1)
+	}
+	var removed, added []counterKey
+	for _, previous := range r.keys {
+		var current *counterKey
+		for i := range keys {
+			if keys[i].field == previous.field {
+				current = &keys[i]
+			}
+		}
+		if current != nil && previous.value != nil && relationKey(current.value) == relationKey(previous.value) {
+			continue
+		}
+		if previous.value != nil {
+			removed = append(removed, previous)
+		}
+		if current != nil {
+			added = append(added, *current)
+		}
+	}
+	if err := applyCounterCaches(ctx, conn, dialect, removed, -1); err != nil {
+		return err
+	}
+	return applyCounterCaches(ctx, conn, dialect, added, 1)
+}
+
+// history returns the StateTransition row to record for a row updated by Save, or nil.
+func (r *savedRow) history(model *schema.Model, now time.Time) *StateTransition {
+	if r == nil || !r.updated {
+		return nil
+	}
+	return r.transition.history(model, r.pkArgs, now)
+}
+
+// conflictUpdateColumns resolves the columns updated on conflict: the requested ones,
+// which must be inserted, or every inserted column outside the conflict target, the
+// primary key and the creation timestamps. The update timestamps are always updated.
+func conflictUpdateColumns(model *schema.Model, columns, conflict, requested []string) ([]string, error) {
+	inserted := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		inserted[col] = true
+	}
+	if len(requested) > 0 {
+		updates := make([]string, 0, len(requested))
+		for _, name := range requested {
+			field, ok := model.GetField(name)
+			if !ok {
+				field, ok = model.GetFieldByDBName(name)
+			}
+			if !ok || field.IsIgnored {
+				return nil, fmt.Errorf("invalid update column '%s' in OnConflict for model %s", name, model.Name)
+			}
+			if !inserted[field.DBName] {
+				return nil, fmt.Errorf("OnConflict update column '%s' of model %s is not inserted, so it has no new value", name, model.Name)
+			}
+			updates = append(updates, field.DBName)
+		}
+		for _, field := range model.Fields {
+			if !field.IsIgnored && field.IsAutoUpdateTime() && inserted[field.DBName] && !slices.Contains(updates, field.DBName) {
+				updates = append(updates, field.DBName)
+			}
+		}
+		return updates, nil
+	}
+	target := make(map[string]bool, len(conflict))
+	for _, col := range conflict {
+		target[col] = true
+	}
+	var updates []string
+	for _, col := range columns {
+		field, ok := model.GetFieldByDBName(col)
+		if target[col] || (ok && (field.IsPrimaryKey || field.IsAutoCreateTime())) {
+			continue
+		}
+		updates = append(updates, col)
+	}
+	return updates, nil
+}
+
+// buildUpsertSQL renders an INSERT updating the conflicting row with the clause of the
+// dialect (common.Upserter), or the standard ON CONFLICT ... DO UPDATE.
+func buildUpsertSQL(dialect common.Dialect, model *schema.Model, columns []string, args []any, conflict, updates []string) (string, []any, error) {
+	var clause string
+	if upserter, ok := dialect.(common.Upserter); ok {
+		clause = upserter.UpsertClause(conflict, updates)
+	} else {
+		switch dialect.Name() {
+		case "sqlserver", "mssql":
+			return "", nil, fmt.Errorf("%w: OnConflict updates on %s", ErrUnsupportedDialect, dialect.Name())
+		}
+		if len(conflict) == 0 {
+			return "", nil, fmt.Errorf("OnConflict updates on %s require conflict columns (model %s has no primary key)", dialect.Name(), model.Name)
+		}
+		target := make([]string, len(conflict))
+		for i, col := range conflict {
+			target[i] = dialect.Quote(col)
+		}
+		assignments := make([]string, len(updates))
+		for i, col := range updates {
+			assignments[i] = fmt.Sprintf("%s = EXCLUDED.%s", dialect.Quote(col), dialect.Quote(col))
+		}
+		clause = fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(target, ", "), strings.Join(assignments, ", "))
+	}
+
+	buf := getStmtBuffer(statementSize(model))
+	defer putStmtBuffer(buf)
+	buf.WriteString("INSERT INTO ")
+	writeInsertColumns(buf, dialect, model.QualifiedTableName(), columns)
+	buf.WriteString(" ")
+	buf.WriteString(clause)
+	return buf.String(), args, nil
+}
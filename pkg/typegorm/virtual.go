@@ -0,0 +1,222 @@
+package typegorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// --- Virtual Relations (foreign data) ---
+
+// VirtualLoader loads related values for a batch of keys, typically from another
+// service. It returns the loaded values indexed by key; keys missing from the map
+// leave the target field untouched. Map keys must have the same dynamic type as the
+// key field values (e.g., uint for a uint field).
+type VirtualLoader func(ctx context.Context, keys []any) (map[any]any, error)
+
+// VirtualRelation describes a relation resolved by a loader function instead of a
+// database join, e.g. a user profile served by another service:
+//
+//	db.RegisterVirtualRelation(&Order{}, typegorm.VirtualRelation{
+//		Name:   "Customer",
+//		Field:  "Customer",   // Go field receiving the loaded value (ignored by the schema: `typegorm:"-"`)
+//		Key:    "CustomerID", // Go field holding the key passed to the loader
+//		Loader: customerClient.BatchGet,
+//	})
+//	db.Find(ctx, &orders, typegorm.Preload("Customer"))
+//
+// All keys of a result set are collected and the loader is called once per relation,
+// avoiding N+1 cross-service calls.
+type VirtualRelation struct {
+	Name   string
+	Field  string
+	Key    string
+	Loader VirtualLoader
+}
+
+// virtualRelations is the registry shared by a DB and the transactions it begins.
+type virtualRelations struct {
+	mu     sync.RWMutex
+	byType map[reflect.Type]map[string]*VirtualRelation
+}
+
+func newVirtualRelations() *virtualRelations {
+	return &virtualRelations{byType: make(map[reflect.Type]map[string]*VirtualRelation)}
+}
+
+func (r *virtualRelations) get(structType reflect.Type, name string) (*VirtualRelation, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	rel, ok := r.byType[structType][name]
+	return rel, ok
+}
+
+// RegisterVirtualRelation registers a virtual relation for the model type of 'model'.
+// Registering a relation with an existing name replaces it.
+func (db *DB) RegisterVirtualRelation(model any, rel VirtualRelation) error {
+	structType := reflect.TypeOf(model)
+	for structType != nil && structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType == nil || structType.Kind() != reflect.Struct {
+		return fmt.Errorf("virtual relation model must be a struct or pointer to struct, got %T", model)
+	}
+	if rel.Name == "" || rel.Loader == nil {
+		return fmt.Errorf("virtual relation requires a name and a loader")
+	}
+	if rel.Field == "" {
+		rel.Field = rel.Name
+	}
+	if _, ok := structType.FieldByName(rel.Field); !ok {
+		return fmt.Errorf("virtual relation %s: field '%s' not found in %s", rel.Name, rel.Field, structType.Name())
+	}
+	if _, ok := structType.FieldByName(rel.Key); !ok {
+		return fmt.Errorf("virtual relation %s: key field '%s' not found in %s", rel.Name, rel.Key, structType.Name())
+	}
+
+	db.relations.mu.Lock()
+	defer db.relations.mu.Unlock()
+	if db.relations.byType[structType] == nil {
+		db.relations.byType[structType] = make(map[string]*VirtualRelation)
+	}
+	db.relations.byType[structType][rel.Name] = &rel
+	return nil
+}
+
+// LoadVirtual resolves the named virtual relations for dest, which must be a pointer
+// to a struct or to a slice of structs (or struct pointers).
+func (db *DB) LoadVirtual(ctx context.Context, dest any, names ...string) error {
+	return loadVirtualRelations(ctx, db.relations, reflect.ValueOf(dest), names)
+}
+
+// LoadVirtual resolves the named virtual relations for dest within the transaction.
+func (tx *Tx) LoadVirtual(ctx context.Context, dest any, names ...string) error {
+	return loadVirtualRelations(ctx, tx.relations, reflect.ValueOf(dest), names)
+}
+
+// loadVirtualRelations collects the keys of every element in target, calls each
+// loader once and assigns the results.
+func loadVirtualRelations(ctx context.Context, registry *virtualRelations, target reflect.Value, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	elems, err := preloadElems("virtual relation", target)
+	if err != nil || len(elems) == 0 {
+		return err
+	}
+	structType := elems[0].Type()
+
+	for _, name := range names {
+		rel, ok := registry.get(structType, name)
+		if !ok {
+			return fmt.Errorf("no virtual relation named '%s' registered for %s", name, structType.Name())
+		}
+
+		keys := make([]any, 0, len(elems))
+		seen := make(map[any]bool, len(elems))
+		for _, elem := range elems {
+			key, ok := virtualKey(elem.FieldByName(rel.Key))
+			if !ok || seen[key] {
+				continue
+			}
+			seen[key] = true
+			keys = append(keys, key)
+		}
+		if len(keys) == 0 {
+			continue
+		}
+
+		fmt.Printf("Loading virtual relation %s for %d key(s)\n", rel.Name, len(keys))
+		loaded, err := rel.Loader(ctx, keys)
+		if err != nil {
+			return fmt.Errorf("virtual relation %s: loader failed: %w", rel.Name, err)
+		}
+
+		for _, elem := range elems {
+			key, ok := virtualKey(elem.FieldByName(rel.Key))
+			if !ok {
+				continue
+			}
+			related, found := loaded[key]
+			if !found || related == nil {
+				continue
+			}
+			if err := assignVirtual(elem.FieldByName(rel.Field), related); err != nil {
+				return fmt.Errorf("virtual relation %s: %w", rel.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// preloadElems returns the addressable structs of target, a pointer to a struct or to
+// a slice of structs (or struct pointers, nil ones skipped).
+func preloadElems(what string, target reflect.Value) ([]reflect.Value, error) {
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return nil, fmt.Errorf("%s target must be a non-nil pointer, got %s", what, target.Kind())
+	}
+	var elems []reflect.Value
+	value := target.Elem()
+	switch value.Kind() {
+	case reflect.Struct:
+		elems = append(elems, value)
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			elem := value.Index(i)
+			if elem.Kind() == reflect.Pointer {
+				if elem.IsNil() {
+					continue
+				}
+				elem = elem.Elem()
+			}
+			elems = append(elems, elem)
+		}
+	default:
+		return nil, fmt.Errorf("%s target must point to a struct or slice, got %s", what, value.Kind())
+	}
+	return elems, nil
+}
+
+// virtualKey returns the comparable key held by a key field, dereferencing pointers.
+// Zero keys (nil pointers, 0, "") are skipped.
+func virtualKey(field reflect.Value) (any, bool) {
+	for field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			return nil, false
+		}
+		field = field.Elem()
+	}
+	if !field.IsValid() || field.IsZero() || !field.Type().Comparable() {
+		return nil, false
+	}
+	return field.Interface(), true
+}
+
+// assignVirtual stores a loaded value into the target field, adapting between
+// values and pointers when needed. A nil value (also a typed-nil pointer stored in a
+// value field) sets the zero value.
+func assignVirtual(field reflect.Value, related any) error {
+	value := reflect.ValueOf(related)
+	switch {
+	case !value.IsValid():
+		field.SetZero()
+	case value.Type().AssignableTo(field.Type()):
+		field.Set(value)
+	case value.Kind() == reflect.Pointer && value.Type().Elem().AssignableTo(field.Type()):
+		if value.IsNil() {
+			value = reflect.New(value.Type().Elem())
+		}
+		field.Set(value.Elem())
+	case field.Kind() == reflect.Pointer && value.Type().AssignableTo(field.Type().Elem()):
+		ptr := reflect.New(field.Type().Elem())
+		ptr.Elem().Set(value)
+		field.Set(ptr)
+	default:
+		return fmt.Errorf("cannot assign loaded value of type %s to field of type %s", value.Type(), field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package typegorm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type virtualProfile struct {
+	Handle string
+}
+
+type virtualOrder struct {
+	ID         uint            `typegorm:"primaryKey;autoIncrement"`
+	CustomerID uint            `typegorm:"column:customer_id"`
+	Customer   *virtualProfile `typegorm:"-"`
+}
+
+func TestPreloadVirtualRelation(t *testing.T) {
+	db, source := newMockDB()
+
+	var calls [][]any
+	err := db.RegisterVirtualRelation(&virtualOrder{}, VirtualRelation{
+		Name: "Customer",
+		Key:  "CustomerID",
+		Loader: func(ctx context.Context, keys []any) (map[any]any, error) {
+			calls = append(calls, keys)
+			return map[any]any{uint(7): virtualProfile{Handle: "ana"}, uint(9): &virtualProfile{Handle: "bia"}}, nil
+		},
+	})
+	require.NoError(t, err)
+
+	source.queueRows([]string{"id", "customer_id"},
+		[]any{uint(1), uint(7)}, []any{uint(2), uint(9)}, []any{uint(3), uint(7)}, []any{uint(4), uint(0)})
+
+	var orders []virtualOrder
+	result := db.Find(context.Background(), &orders, Preload("Customer"))
+	require.NoError(t, result.Error)
+	require.Len(t, orders, 4)
+
+	require.Len(t, calls, 1, "keys are batched into a single loader call")
+	assert.ElementsMatch(t, []any{uint(7), uint(9)}, calls[0])
+	assert.Equal(t, "ana", orders[0].Customer.Handle)
+	assert.Equal(t, "bia", orders[1].Customer.Handle)
+	assert.Equal(t, "ana", orders[2].Customer.Handle)
+	assert.Nil(t, orders[3].Customer, "zero keys are not loaded")
+}
+
+func TestLoadVirtual_Errors(t *testing.T) {
+	db, _ := newMockDB()
+	ctx := context.Background()
+
+	assert.Error(t, db.RegisterVirtualRelation(&virtualOrder{}, VirtualRelation{Name: "Customer", Key: "Missing", Loader: func(context.Context, []any) (map[any]any, error) { return nil, nil }}))
+
+	order := &virtualOrder{CustomerID: 1}
+	assert.Error(t, db.LoadVirtual(ctx, order, "Customer"), "relation not registered")
+
+	loaderErr := errors.New("service unavailable")
+	require.NoError(t, db.RegisterVirtualRelation(&virtualOrder{}, VirtualRelation{
+		Name:   "Customer",
+		Key:    "CustomerID",
+		Loader: func(context.Context, []any) (map[any]any, error) { return nil, loaderErr },
+	}))
+	assert.ErrorIs(t, db.LoadVirtual(ctx, order, "Customer"), loaderErr)
+}
+
+func TestAssignVirtual_NilValues(t *testing.T) {
+	var profile virtualProfile
+	field := reflect.ValueOf(&profile).Elem()
+	require.NoError(t, assignVirtual(field, &virtualProfile{Handle: "ana"}))
+	assert.Equal(t, "ana", profile.Handle)
+	require.NoError(t, assignVirtual(field, (*virtualProfile)(nil)), "typed nil pointer")
+	assert.Equal(t, virtualProfile{}, profile)
+
+	order := virtualOrder{Customer: &virtualProfile{Handle: "bia"}}
+	require.NoError(t, assignVirtual(reflect.ValueOf(&order).Elem().FieldByName("Customer"), nil))
+	assert.Nil(t, order.Customer)
+}
@@ -0,0 +1,61 @@
+package typegorm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// --- Schema Warm-Up ---
+
+// warmUpOptions holds the optional behaviors of WarmUp.
+type warmUpOptions struct {
+	lenient bool // Only warn about unknown tag options
+}
+
+// WarmUpOption defines a function type that modifies warmUpOptions.
+type WarmUpOption func(*warmUpOptions)
+
+// LenientTags makes WarmUp accept unknown tag options (they are still logged by the parser).
+func LenientTags() WarmUpOption {
+	return func(opts *warmUpOptions) { opts.lenient = true }
+}
+
+// WarmUp parses and caches the schema of the given models at startup, so that tag
+// mistakes surface immediately instead of on the first query. It is strict by default:
+// unknown tag options (typos like "primaryKy" or "sise:100") fail with a
+// *schema.TagError listing them with suggestions. Models and WarmUpOptions can be mixed:
+//
+//	if err := db.WarmUp(&User{}, &Order{}); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// Errors of all models are reported together.
+func (db *DB) WarmUp(values ...any) error {
+	var options warmUpOptions
+	var models []any
+	for _, value := range values {
+		if opt, ok := value.(WarmUpOption); ok {
+			opt(&options)
+			continue
+		}
+		models = append(models, value)
+	}
+
+	var errs []error
+	for _, value := range models {
+		if !options.lenient {
+			if err := db.parser.CheckTags(value); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		if _, err := db.GetModel(value); err != nil {
+			errs = append(errs, fmt.Errorf("failed to parse schema for type %T: %w", value, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("warm-up failed: %w", errors.Join(errs...))
+	}
+	fmt.Printf("Schema warm-up complete: %d model(s) cached.\n", len(models))
+	return nil
+}
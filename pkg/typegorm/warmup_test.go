@@ -0,0 +1,37 @@
+package typegorm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type typoUser struct {
+	ID   uint   `typegorm:"primaryKey;autoIncremnt"`
+	Name string `typegorm:"sise:100"`
+}
+
+func TestWarmUp_StrictByDefault(t *testing.T) {
+	db, _ := newMockDB()
+
+	err := db.WarmUp(&maskUser{}, &typoUser{})
+	require.Error(t, err)
+
+	var tagErr *schema.TagError
+	require.True(t, errors.As(err, &tagErr))
+	assert.Equal(t, "typoUser", tagErr.Model)
+	assert.Contains(t, err.Error(), "ID: 'autoIncremnt' (did you mean 'autoIncrement'?)")
+	assert.Contains(t, err.Error(), "Name: 'sise' (did you mean 'size'?)")
+}
+
+func TestWarmUp_Lenient(t *testing.T) {
+	db, _ := newMockDB()
+
+	require.NoError(t, db.WarmUp(&typoUser{}, LenientTags()))
+	model, err := db.GetModel(&typoUser{})
+	require.NoError(t, err)
+	assert.Equal(t, "typo_users", model.TableName)
+}
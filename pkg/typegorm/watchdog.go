@@ -0,0 +1,55 @@
+package typegorm
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+)
+
+// --- Long Transaction Watchdog ---
+
+// txWatchdog fires when a transaction stays open longer than the configured threshold
+// (config: transaction.watchdogThreshold), logging where it was begun and optionally
+// rolling it back (transaction.watchdogRollback).
+type txWatchdog struct {
+	timer      *time.Timer
+	started    time.Time
+	stack      []byte      // Stack trace captured at Begin
+	rolledBack atomic.Bool // Set when the watchdog forced a rollback
+}
+
+// startWatchdog arms the watchdog for a new transaction, if enabled in the config.
+func (db *DB) startWatchdog(tx *Tx) {
+	threshold := db.config.Transaction.WatchdogThreshold
+	if threshold <= 0 {
+		return
+	}
+	wd := &txWatchdog{started: time.Now(), stack: debug.Stack()}
+	forceRollback := db.config.Transaction.WatchdogRollback
+	wd.timer = time.AfterFunc(threshold, func() {
+		fmt.Printf("Warning: transaction open for more than %s (since %s). Begun at:\n%s\n",
+			threshold, wd.started.Format(time.RFC3339), wd.stack)
+		if forceRollback {
+			wd.rolledBack.Store(true)
+			fmt.Println("Watchdog: forcing rollback of long-running transaction.")
+			if err := tx.source.Rollback(); err != nil {
+				fmt.Printf("Watchdog: forced rollback failed: %v\n", err)
+			}
+		}
+	})
+	tx.watchdog = wd
+}
+
+// stopWatchdog disarms the watchdog when the transaction ends. It returns
+// ErrTransactionTimeout if the watchdog already rolled the transaction back.
+func (tx *Tx) stopWatchdog() error {
+	if tx.watchdog == nil {
+		return nil
+	}
+	tx.watchdog.timer.Stop()
+	if tx.watchdog.rolledBack.Load() {
+		return fmt.Errorf("tx: open for %s: %w", time.Since(tx.watchdog.started).Round(time.Millisecond), ErrTransactionTimeout)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package typegorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchdog_ForcesRollback(t *testing.T) {
+	db, source := newMockDB()
+	db.config.Transaction.WatchdogThreshold = 10 * time.Millisecond
+	db.config.Transaction.WatchdogRollback = true
+
+	tx, err := db.Begin(context.Background())
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return source.containsStatement("ROLLBACK") }, time.Second, 5*time.Millisecond)
+
+	assert.ErrorIs(t, tx.Commit(), ErrTransactionTimeout)
+	assert.NoError(t, tx.Rollback(), "rolling back an already rolled back transaction is a no-op")
+	assert.Equal(t, []string{"BEGIN", "ROLLBACK"}, sqlOf(source.Statements()))
+}
+
+func TestWatchdog_WarnOnly(t *testing.T) {
+	db, source := newMockDB()
+	db.config.Transaction.WatchdogThreshold = 5 * time.Millisecond
+
+	tx, err := db.Begin(context.Background())
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, tx.Commit())
+	assert.Equal(t, []string{"BEGIN", "COMMIT"}, sqlOf(source.Statements()))
+}
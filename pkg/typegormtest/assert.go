@@ -0,0 +1,146 @@
+package typegormtest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/typegorm"
+)
+
+// --- Persisted State Assertions ---
+
+// SampleRows is the number of rows AssertExists and AssertCount print when they fail.
+var SampleRows = 5
+
+// AssertExists checks that at least one row of the model's table matches conds, which
+// take the forms of Chain.Where: a struct pointer, a map (with operators), or SQL
+// followed by its arguments:
+//
+//	typegormtest.AssertExists(t, db, &User{}, map[string]any{"email": "ana@example.com"})
+//	typegormtest.AssertExists(t, db, &User{}, "age > ? AND active = ?", 18, true)
+//
+// On failure it prints the condition and a sample of the table's rows. The model's
+// DefaultScope applies (soft-deleted rows are not seen).
+func AssertExists(t testing.TB, db *typegorm.DB, model any, conds ...any) bool {
+	t.Helper()
+	count, err := countRows(db, model, conds)
+	if err != nil {
+		t.Errorf("typegormtest: AssertExists %T: %v", model, err)
+		return false
+	}
+	if count == 0 {
+		t.Errorf("typegormtest: no %s row matches %s\n%s", typeName(model), describeConds(conds), sampleRows(db, model, nil))
+		return false
+	}
+	return true
+}
+
+// AssertCount checks that exactly want rows of the model's table match conds (see
+// AssertExists; no conds counts every row). On failure it prints the matching rows.
+func AssertCount(t testing.TB, db *typegorm.DB, model any, want int64, conds ...any) bool {
+	t.Helper()
+	count, err := countRows(db, model, conds)
+	if err != nil {
+		t.Errorf("typegormtest: AssertCount %T: %v", model, err)
+		return false
+	}
+	if count != want {
+		t.Errorf("typegormtest: %d %s row(s) match %s, want %d\n%s", count, typeName(model), describeConds(conds), want, sampleRows(db, model, conds))
+		return false
+	}
+	return true
+}
+
+// AssertDeleted checks that the row of value (a struct pointer with its primary key
+// set) no longer exists, or is hidden by the DefaultScope (soft delete). On failure
+// it prints the row found.
+func AssertDeleted(t testing.TB, db *typegorm.DB, value any) bool {
+	t.Helper()
+	model, err := db.GetModel(value)
+	if err != nil {
+		t.Errorf("typegormtest: AssertDeleted %T: %v", value, err)
+		return false
+	}
+	if len(model.PrimaryKeys) == 0 {
+		t.Errorf("typegormtest: AssertDeleted %T: model %s has no primary key", value, model.Name)
+		return false
+	}
+	structValue := reflect.Indirect(reflect.ValueOf(value))
+	key := make(map[string]any, len(model.PrimaryKeys))
+	for _, pk := range model.PrimaryKeys {
+		pkValue := structValue.FieldByName(pk.GoName)
+		if !pkValue.IsValid() || pkValue.IsZero() {
+			t.Errorf("typegormtest: AssertDeleted %T: primary key %s is not set", value, pk.GoName)
+			return false
+		}
+		key[pk.DBName] = pkValue.Interface()
+	}
+	count, err := countRows(db, value, []any{key})
+	if err != nil {
+		t.Errorf("typegormtest: AssertDeleted %T: %v", value, err)
+		return false
+	}
+	if count != 0 {
+		t.Errorf("typegormtest: %s row %s was not deleted\n%s", model.Name, describeConds([]any{key}), sampleRows(db, value, []any{key}))
+		return false
+	}
+	return true
+}
+
+func countRows(db *typegorm.DB, model any, conds []any) (int64, error) {
+	return where(db, model, conds).Count(context.Background())
+}
+
+func where(db *typegorm.DB, model any, conds []any) *typegorm.Chain {
+	chain := db.Model(model)
+	if len(conds) > 0 {
+		chain = chain.Where(conds[0], conds[1:]...)
+	}
+	return chain
+}
+
+// sampleRows formats up to SampleRows rows matching conds, for failure messages.
+func sampleRows(db *typegorm.DB, model any, conds []any) string {
+	modelType := reflect.TypeOf(model)
+	for modelType.Kind() == reflect.Pointer {
+		modelType = modelType.Elem()
+	}
+	rows := reflect.New(reflect.SliceOf(modelType))
+	if res := where(db, model, conds).Limit(SampleRows).Find(context.Background(), rows.Interface()); res.Error != nil {
+		return fmt.Sprintf("(cannot read the rows: %v)", res.Error)
+	}
+	if rows.Elem().Len() == 0 {
+		label := "table"
+		if len(conds) > 0 {
+			label = "matching rows"
+		}
+		return fmt.Sprintf("%s: none", label)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "first %d row(s):", rows.Elem().Len())
+	for i := 0; i < rows.Elem().Len(); i++ {
+		fmt.Fprintf(&b, "\n  %+v", rows.Elem().Index(i).Interface())
+	}
+	return b.String()
+}
+
+func describeConds(conds []any) string {
+	switch {
+	case len(conds) == 0:
+		return "(no conditions)"
+	case len(conds) == 1:
+		return fmt.Sprintf("%+v", reflect.Indirect(reflect.ValueOf(conds[0])).Interface())
+	}
+	return fmt.Sprintf("%v with %v", conds[0], conds[1:])
+}
+
+func typeName(model any) string {
+	modelType := reflect.TypeOf(model)
+	for modelType.Kind() == reflect.Pointer {
+		modelType = modelType.Elem()
+	}
+	return modelType.Name()
+}
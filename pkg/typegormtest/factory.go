@@ -0,0 +1,276 @@
+package typegormtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+	"github.com/chmenegatti/typegorm/pkg/typegorm"
+)
+
+// --- Data Factory ---
+
+// FactoryChunkSize is the number of rows CreateBatch inserts per transaction.
+var FactoryChunkSize = 500
+
+var (
+	firstNames = []string{"Ana", "Bruno", "Carla", "Diego", "Eva", "Felipe", "Gabriela", "Hugo", "Isabel", "Joao", "Karen", "Lucas", "Marina", "Nuno", "Olivia", "Pedro"}
+	lastNames  = []string{"Almeida", "Barbosa", "Costa", "Dias", "Ferreira", "Gomes", "Lima", "Martins", "Oliveira", "Pereira", "Ribeiro", "Santos", "Silva", "Souza"}
+	words      = []string{"alpha", "bravo", "cedar", "delta", "ember", "fjord", "grove", "harbor", "iris", "juniper", "kite", "lumen", "maple", "nova", "orbit", "pixel", "quartz", "river", "summit", "tidal"}
+)
+
+// FactoryBuilder generates randomized records of T for seeding benchmarks and load
+// tests. Values follow the model's constraints: strings fit their size, unique
+// columns get distinct values, decimals fit their precision, and nullable columns,
+// auto-increment keys, timestamps filled by the database and columns with a default
+// are left alone. Columns named like email, name, phone or url get realistic values:
+//
+//	users, err := typegormtest.Factory[User](db).With("Age", 30).CreateBatch(1000)
+//
+// Foreign keys cannot be guessed: set them with With or WithFunc.
+type FactoryBuilder[T any] struct {
+	db     *typegorm.DB
+	values map[string]func(i int) any // Go field name -> value of the i-th record
+	rand   *rand.Rand
+	token  string // Random prefix making unique values distinct across runs
+	seq    int    // Records generated so far
+}
+
+// Factory returns a factory of T records stored in db.
+func Factory[T any](db *typegorm.DB) *FactoryBuilder[T] {
+	return (&FactoryBuilder[T]{db: db, values: make(map[string]func(int) any)}).Seed(time.Now().UnixNano())
+}
+
+// Seed makes the generated values reproducible.
+func (f *FactoryBuilder[T]) Seed(seed int64) *FactoryBuilder[T] {
+	f.rand = rand.New(rand.NewPCG(uint64(seed), uint64(seed)>>1))
+	f.token = strconv.FormatUint(f.rand.Uint64()%(36*36*36*36), 36)
+	return f
+}
+
+// With sets a field (Go name) of every record to value.
+func (f *FactoryBuilder[T]) With(field string, value any) *FactoryBuilder[T] {
+	f.values[field] = func(int) any { return value }
+	return f
+}
+
+// WithFunc sets a field (Go name) of the i-th record (from 0, counting every record
+// of the factory) to fn(i), e.g. to spread rows over parent rows.
+func (f *FactoryBuilder[T]) WithFunc(field string, fn func(i int) any) *FactoryBuilder[T] {
+	f.values[field] = fn
+	return f
+}
+
+// Build returns a generated record without storing it.
+func (f *FactoryBuilder[T]) Build() (T, error) {
+	records, err := f.BuildBatch(1)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return records[0], nil
+}
+
+// BuildBatch returns n generated records without storing them.
+func (f *FactoryBuilder[T]) BuildBatch(n int) ([]T, error) {
+	model, err := f.db.GetModel(new(T))
+	if err != nil {
+		return nil, fmt.Errorf("typegormtest: factory: %w", err)
+	}
+	for name := range f.values {
+		if _, ok := model.GetField(name); !ok {
+			return nil, fmt.Errorf("typegormtest: factory: model %s has no field %s", model.Name, name)
+		}
+	}
+	records := make([]T, n)
+	for i := range records {
+		value := reflect.ValueOf(&records[i]).Elem()
+		for _, field := range model.Fields {
+			if err := f.fill(model, field, value.FieldByName(field.GoName)); err != nil {
+				return nil, fmt.Errorf("typegormtest: factory: %s.%s: %w", model.Name, field.GoName, err)
+			}
+		}
+		f.seq++
+	}
+	return records, nil
+}
+
+// Create stores a generated record.
+func (f *FactoryBuilder[T]) Create() (T, error) {
+	records, err := f.CreateBatch(1)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return records[0], nil
+}
+
+// CreateBatch stores n generated records, FactoryChunkSize per transaction, and
+// returns them with their generated keys.
+func (f *FactoryBuilder[T]) CreateBatch(n int) ([]T, error) {
+	records, err := f.BuildBatch(n)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	chunk := max(FactoryChunkSize, 1)
+	for start := 0; start < len(records); start += chunk {
+		end := min(start+chunk, len(records))
+		err := f.db.Transaction(ctx, func(ctx context.Context, tx *typegorm.Tx) error {
+			for i := start; i < end; i++ {
+				if res := tx.Create(ctx, &records[i]); res.Error != nil {
+					return fmt.Errorf("record %d: %w", i, res.Error)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return records[:start], fmt.Errorf("typegormtest: factory: %w", err)
+		}
+	}
+	return records, nil
+}
+
+// fill sets a field of the record being built, from With/WithFunc or generated.
+func (f *FactoryBuilder[T]) fill(model *schema.Model, field *schema.Field, value reflect.Value) error {
+	if !value.IsValid() || !value.CanSet() {
+		return nil
+	}
+	if fn, ok := f.values[field.GoName]; ok {
+		return setValue(value, fn(f.seq))
+	}
+	if field.IsIgnored || field.IsComputed || field.AutoIncrement || field.IsNullable() ||
+		field.IsAutoCreateTime() || field.IsAutoUpdateTime() || field.DefaultValue != nil || field.References != "" {
+		return nil
+	}
+	unique := field.IsPrimaryKey || field.Unique || field.IsUniqueIndex || isUniqueIndexed(model, field)
+	generated, ok := f.generate(field, value.Type(), unique)
+	if !ok {
+		return nil // Types the factory cannot generate keep their zero value
+	}
+	return setValue(value, generated)
+}
+
+// generate returns a random value of type typ for the field.
+func (f *FactoryBuilder[T]) generate(field *schema.Field, typ reflect.Type, unique bool) (any, bool) {
+	if typ == reflect.TypeOf(time.Time{}) {
+		return time.Now().Add(-time.Duration(f.rand.Int64N(int64(365 * 24 * time.Hour)))).Truncate(time.Second).UTC(), true
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		return f.text(field, unique), true
+	case reflect.Bool:
+		return f.rand.IntN(2) == 1, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch name := strings.ToLower(field.DBName); {
+		case unique && typ.Bits() >= 32:
+			return int64(f.rand.IntN(1000))*1_000_000 + int64(f.seq) + 1, true
+		case unique:
+			return int64(f.seq) + 1, true
+		case name == "age" || strings.HasSuffix(name, "_age"):
+			return 18 + f.rand.Int64N(63), true
+		case typ.Bits() < 16:
+			return f.rand.Int64N(100), true
+		}
+		return f.rand.Int64N(1000), true
+	case reflect.Float32, reflect.Float64:
+		value := f.rand.Float64() * 1000
+		if field.Precision > 0 {
+			value = math.Mod(value, math.Pow10(field.Precision-field.Scale))
+			scale := math.Pow10(field.Scale)
+			value = math.Floor(value*scale) / scale
+		}
+		return value, true
+	}
+	return nil, false
+}
+
+// text returns a realistic string for the column, distinct when unique and cut to its size.
+func (f *FactoryBuilder[T]) text(field *schema.Field, unique bool) string {
+	name := strings.ToLower(field.DBName)
+	first, last := firstNames[f.rand.IntN(len(firstNames))], lastNames[f.rand.IntN(len(lastNames))]
+	suffix := ""
+	if unique {
+		suffix = f.token + strconv.Itoa(f.seq)
+	}
+	var text string
+	switch {
+	case strings.Contains(name, "email"):
+		const domain = "@example.com"
+		size := field.Size
+		if size > 0 {
+			size = max(size-len(domain), 1)
+		}
+		if unique {
+			suffix = "." + suffix
+		}
+		return fitSize(strings.ToLower(first+"."+last), size, suffix) + domain
+	case strings.Contains(name, "first_name"):
+		text = first
+	case strings.Contains(name, "last_name"):
+		text = last
+	case strings.Contains(name, "username") || strings.Contains(name, "login"):
+		text = strings.ToLower(first + last)
+	case strings.Contains(name, "name"):
+		text = first + " " + last
+	case strings.Contains(name, "phone"):
+		text = fmt.Sprintf("+1555%07d", f.rand.IntN(10_000_000))
+	case strings.Contains(name, "url"):
+		text = "https://example.com/" + words[f.rand.IntN(len(words))]
+	default:
+		text = words[f.rand.IntN(len(words))] + " " + words[f.rand.IntN(len(words))]
+	}
+	if unique {
+		return fitSize(text, field.Size, "-"+suffix)
+	}
+	return fitSize(text, field.Size, "")
+}
+
+// fitSize cuts text so that text+suffix fits size (0 = unlimited), keeping the suffix.
+func fitSize(text string, size int, suffix string) string {
+	if size > 0 && len(text)+len(suffix) > size {
+		text = text[:max(size-len(suffix), 0)]
+		if len(suffix) > size {
+			suffix = suffix[len(suffix)-size:]
+		}
+	}
+	return text + suffix
+}
+
+func isUniqueIndexed(model *schema.Model, field *schema.Field) bool {
+	for _, index := range model.Indexes {
+		if index.IsUnique && len(index.Fields) == 1 && index.Fields[0] == field {
+			return true
+		}
+	}
+	return false
+}
+
+// setValue assigns (or converts) value to a field; nil leaves it zero.
+func setValue(field reflect.Value, value any) error {
+	if value == nil {
+		field.SetZero()
+		return nil
+	}
+	v := reflect.ValueOf(value)
+	switch {
+	case v.Type().AssignableTo(field.Type()):
+		field.Set(v)
+	case field.Kind() == reflect.Pointer && v.Type().AssignableTo(field.Type().Elem()):
+		ptr := reflect.New(field.Type().Elem())
+		ptr.Elem().Set(v)
+		field.Set(ptr)
+	case v.CanConvert(field.Type()) && (v.Kind() != reflect.String) == (field.Kind() != reflect.String):
+		field.Set(v.Convert(field.Type()))
+	default:
+		return fmt.Errorf("cannot set %T to a %s field", value, field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package typegormtest
+
+import (
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/typegorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// modelOnlySource is a DataSource for tests that only parse models.
+type modelOnlySource struct{ common.DataSource }
+
+type factoryUser struct {
+	ID       uint    `typegorm:"primaryKey;autoIncrement"`
+	Email    string  `typegorm:"size:40;unique"`
+	Name     string  `typegorm:"size:8"`
+	Age      int     `typegorm:"not null"`
+	Balance  float64 `typegorm:"precision:5;scale:2"`
+	Status   string  `typegorm:"default:'active'"`
+	Nickname *string
+	TeamID   uint `typegorm:"references:teams.id"`
+}
+
+func TestFactory_BuildBatchHonorsConstraints(t *testing.T) {
+	db := typegorm.NewDB(modelOnlySource{}, nil, config.Config{})
+	users, err := Factory[factoryUser](db).Seed(1).WithFunc("TeamID", func(i int) any { return i%2 + 1 }).BuildBatch(50)
+	require.NoError(t, err)
+	require.Len(t, users, 50)
+
+	emails := make(map[string]bool)
+	for i, user := range users {
+		assert.Zero(t, user.ID, "auto-increment keys are left to the database")
+		assert.Regexp(t, `^[a-z]+\.[a-z]+\.[0-9a-z]+@example\.com$`, user.Email)
+		assert.LessOrEqual(t, len(user.Email), 40)
+		emails[user.Email] = true
+		assert.NotEmpty(t, user.Name)
+		assert.LessOrEqual(t, len(user.Name), 8)
+		assert.GreaterOrEqual(t, user.Age, 18)
+		assert.Less(t, user.Balance, 1000.0)
+		assert.Empty(t, user.Status, "columns with a default are left to the database")
+		assert.Nil(t, user.Nickname, "nullable columns are left NULL")
+		assert.Equal(t, uint(i%2+1), user.TeamID)
+	}
+	assert.Len(t, emails, 50, "unique columns get distinct values")
+
+	again, err := Factory[factoryUser](db).Seed(1).WithFunc("TeamID", func(i int) any { return i%2 + 1 }).BuildBatch(50)
+	require.NoError(t, err)
+	assert.Equal(t, users, again, "seeded factories are reproducible")
+}
+
+func TestFactory_With(t *testing.T) {
+	db := typegorm.NewDB(modelOnlySource{}, nil, config.Config{})
+	user, err := Factory[factoryUser](db).With("Age", 30).With("Nickname", "ace").Build()
+	require.NoError(t, err)
+	assert.Equal(t, 30, user.Age)
+	require.NotNil(t, user.Nickname)
+	assert.Equal(t, "ace", *user.Nickname)
+
+	_, err = Factory[factoryUser](db).With("Nope", 1).Build()
+	assert.ErrorContains(t, err, "has no field Nope")
+	_, err = Factory[factoryUser](db).With("Age", "thirty").Build()
+	assert.ErrorContains(t, err, "cannot set string")
+}
+
+func TestFitSize(t *testing.T) {
+	assert.Equal(t, "alpha bravo", fitSize("alpha bravo", 0, ""))
+	assert.Equal(t, "alp-x1", fitSize("alpha bravo", 6, "-x1"))
+	assert.Equal(t, "x1", fitSize("alpha", 2, "-x1"))
+}
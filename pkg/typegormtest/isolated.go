@@ -0,0 +1,99 @@
+package typegormtest
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/dialects"
+	"github.com/chmenegatti/typegorm/pkg/typegorm"
+)
+
+// --- Isolated Databases ---
+
+// sharedServer is the database server shared by the isolated databases of a dialect.
+type sharedServer struct {
+	container *Container // nil with TYPEGORM_TEST_DSN
+	template  *Template  // Empty template the databases are created from
+}
+
+var shared = struct {
+	sync.Mutex
+	servers map[string]*sharedServer
+}{servers: make(map[string]*sharedServer)}
+
+// NewIsolatedDB returns a connected DB on a database of its own, created on a server
+// shared by every test of the package (started on first use, or TYPEGORM_TEST_DSN) and
+// dropped when the test ends. Tests using it can run with t.Parallel():
+//
+//	func TestOrders(t *testing.T) {
+//		t.Parallel()
+//		db := typegormtest.NewIsolatedDB(t, "postgres")
+//		...
+//	}
+//
+// Call StopShared from TestMain to remove the shared containers once the tests are done.
+func NewIsolatedDB(t testing.TB, dialect string) *typegorm.DB {
+	t.Helper()
+	server, err := sharedServerFor(t, dialect)
+	if err != nil {
+		t.Fatalf("typegormtest: %v", err)
+	}
+	return server.template.Database(t)
+}
+
+// sharedServerFor returns the shared server of a dialect, starting it on first use.
+func sharedServerFor(t testing.TB, dialect string) (*sharedServer, error) {
+	t.Helper()
+	if dialects.Get(dialect) == nil {
+		t.Skipf("typegormtest: dialect %q is not registered; import its driver package", dialect)
+	}
+	shared.Lock()
+	defer shared.Unlock()
+	if server, ok := shared.servers[dialect]; ok {
+		return server, nil
+	}
+
+	server := &sharedServer{}
+	dsn := ""
+	if os.Getenv("TYPEGORM_TEST_DIALECT") == dialect {
+		dsn = os.Getenv("TYPEGORM_TEST_DSN")
+	}
+	if dsn == "" && !isSQLite(dialect) {
+		if _, err := exec.LookPath("docker"); err != nil {
+			t.Skip("typegormtest: docker not available and TYPEGORM_TEST_DSN not set")
+		}
+		container, err := Start(dialect)
+		if err != nil {
+			return nil, err
+		}
+		server.container, dsn = container, container.DSN
+	}
+	template, err := NewTemplate(dialect, dsn, nil)
+	if err != nil {
+		_ = server.container.Stop()
+		return nil, err
+	}
+	server.template = template
+	shared.servers[dialect] = server
+	return server, nil
+}
+
+// StopShared drops the templates and removes the containers started by NewIsolatedDB.
+func StopShared() error {
+	shared.Lock()
+	defer shared.Unlock()
+	var firstErr error
+	for dialect, server := range shared.servers {
+		if err := server.template.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("closing %s template: %w", dialect, err)
+		}
+		if err := server.container.Stop(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(shared.servers, dialect)
+	}
+	return firstErr
+}
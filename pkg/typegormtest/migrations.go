@@ -0,0 +1,246 @@
+package typegormtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/migration"
+	"github.com/chmenegatti/typegorm/pkg/schema"
+)
+
+// CheckMigrations applies every migration of dir to a throwaway database, reverts them
+// all, applies them again and checks that:
+//
+//   - the down migrations remove every table created by the up migrations;
+//   - re-applying them gives the same tables, columns and column types;
+//   - the final schema has the tables and columns of the models.
+//
+// Broken down migrations are caught before a production rollback needs them:
+//
+//	func TestMigrations(t *testing.T) {
+//		typegormtest.CheckMigrations(t, "postgres", "../../migrations", &User{}, &Order{})
+//	}
+//
+// Go migrations must be registered (import their package). Destructive operations are
+// allowed. Columns are compared by name only with the models, since column types
+// are dialect-specific; tables not backing a model are not checked against the models.
+func CheckMigrations(t *testing.T, dialect, dir string, models ...any) {
+	t.Helper()
+	db, dsn := startDatabase(t, dialect)
+	ctx := context.Background()
+	cfg := config.NewDefaultConfig()
+	cfg.Database.Dialect = dialect
+	cfg.Database.DSN = dsn
+	cfg.Migration.Directory = dir
+	cfg.Migration.AllowDestructive = true // Nothing to lose in a throwaway database
+
+	parsed := make([]*schema.Model, len(models))
+	for i, model := range models {
+		var err error
+		if parsed[i], err = db.GetModel(model); err != nil {
+			t.Fatalf("typegormtest: parsing %T: %v", model, err)
+		}
+	}
+
+	if err := migration.RunUp(cfg); err != nil {
+		t.Fatalf("typegormtest: migrate up: %v", err)
+	}
+	applied := mustSnapshot(t, ctx, db.GetDataSource(), cfg.Migration.TableName)
+
+	steps, err := appliedMigrations(ctx, db.GetDataSource(), cfg.Migration.TableName)
+	if err != nil {
+		t.Fatalf("typegormtest: %v", err)
+	}
+	if err := migration.RunDown(cfg, steps); err != nil {
+		t.Fatalf("typegormtest: migrate down: %v", err)
+	}
+	reverted := mustSnapshot(t, ctx, db.GetDataSource(), cfg.Migration.TableName)
+	for _, table := range reverted.tableNames() {
+		t.Errorf("typegormtest: table %s is left behind after migrating down", table)
+	}
+
+	if err := migration.RunUp(cfg); err != nil {
+		t.Fatalf("typegormtest: migrate up after down: %v", err)
+	}
+	reapplied := mustSnapshot(t, ctx, db.GetDataSource(), cfg.Migration.TableName)
+	for _, diff := range diffSnapshots(applied, reapplied) {
+		t.Errorf("typegormtest: migrating up after down changed the schema: %s", diff)
+	}
+	for _, diff := range diffModels(parsed, reapplied) {
+		t.Errorf("typegormtest: schema differs from the models: %s", diff)
+	}
+}
+
+// schemaSnapshot is the set of tables and columns of a database. Tables of the current
+// schema are keyed by their name, others by "schema.table"; names are lowercased.
+type schemaSnapshot struct {
+	current string
+	tables  map[string]map[string]string // Table -> column -> data type
+}
+
+func (s schemaSnapshot) key(schemaName, table string) string {
+	if schemaName == "" || strings.EqualFold(schemaName, s.current) {
+		return strings.ToLower(table)
+	}
+	return strings.ToLower(schemaName + "." + table)
+}
+
+func (s schemaSnapshot) tableNames() []string {
+	names := make([]string, 0, len(s.tables))
+	for name := range s.tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// snapshotQueries returns the query of the current schema and the query listing the
+// columns (schema, table, column, data type) of the user tables, by dialect.
+func snapshotQueries(dialect string) (string, string, error) {
+	const columns = "SELECT table_schema, table_name, column_name, data_type FROM information_schema.columns"
+	switch dialect {
+	case "mysql":
+		return "SELECT DATABASE()", columns + " WHERE table_schema NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')", nil
+	case "postgres":
+		return "SELECT current_schema()", columns + " WHERE table_schema NOT IN ('pg_catalog', 'information_schema') AND table_schema NOT LIKE 'pg_toast%'", nil
+	case "sqlserver":
+		return "SELECT SCHEMA_NAME()", columns, nil
+	}
+	return "", "", fmt.Errorf("schema snapshots are not supported for dialect %q", dialect)
+}
+
+// snapshotSchema reads the tables and columns of the database, without the migration
+// history table.
+func snapshotSchema(ctx context.Context, ds common.DataSource, historyTable string) (schemaSnapshot, error) {
+	currentQuery, columnsQuery, err := snapshotQueries(ds.Dialect().Name())
+	if err != nil {
+		return schemaSnapshot{}, err
+	}
+	snapshot := schemaSnapshot{tables: make(map[string]map[string]string)}
+	if err := ds.QueryRow(ctx, currentQuery).Scan(&snapshot.current); err != nil {
+		return snapshot, fmt.Errorf("reading the current schema: %w", err)
+	}
+	rows, err := ds.Query(ctx, columnsQuery)
+	if err != nil {
+		return snapshot, fmt.Errorf("reading the columns: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var schemaName, table, column, dataType string
+		if err := rows.Scan(&schemaName, &table, &column, &dataType); err != nil {
+			return snapshot, fmt.Errorf("reading the columns: %w", err)
+		}
+		key := snapshot.key(schemaName, table)
+		if snapshot.tables[key] == nil {
+			snapshot.tables[key] = make(map[string]string)
+		}
+		snapshot.tables[key][strings.ToLower(column)] = strings.ToLower(dataType)
+	}
+	if err := rows.Err(); err != nil {
+		return snapshot, fmt.Errorf("reading the columns: %w", err)
+	}
+	delete(snapshot.tables, snapshot.key(schema.SplitQualifiedName(historyTable)))
+	return snapshot, nil
+}
+
+func mustSnapshot(t *testing.T, ctx context.Context, ds common.DataSource, historyTable string) schemaSnapshot {
+	t.Helper()
+	snapshot, err := snapshotSchema(ctx, ds, historyTable)
+	if err != nil {
+		t.Fatalf("typegormtest: %v", err)
+	}
+	return snapshot
+}
+
+// appliedMigrations counts the rows of the migration history table.
+func appliedMigrations(ctx context.Context, ds common.DataSource, historyTable string) (int, error) {
+	dialect := ds.Dialect()
+	schemaName, table := schema.SplitQualifiedName(historyTable)
+	quoted := dialect.Quote(table)
+	if schemaName != "" {
+		quoted = dialect.Quote(schemaName) + "." + quoted
+	}
+	var count int
+	if err := ds.QueryRow(ctx, "SELECT COUNT(*) FROM "+quoted).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting applied migrations: %w", err)
+	}
+	return count, nil
+}
+
+// diffSnapshots lists the tables, columns and column types differing between two snapshots.
+func diffSnapshots(before, after schemaSnapshot) []string {
+	var diffs []string
+	for _, table := range before.tableNames() {
+		columns, ok := after.tables[table]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("table %s is missing", table))
+			continue
+		}
+		for _, column := range sortedKeys(before.tables[table]) {
+			oldType := before.tables[table][column]
+			newType, ok := columns[column]
+			switch {
+			case !ok:
+				diffs = append(diffs, fmt.Sprintf("column %s.%s is missing", table, column))
+			case newType != oldType:
+				diffs = append(diffs, fmt.Sprintf("column %s.%s is %s instead of %s", table, column, newType, oldType))
+			}
+		}
+		for _, column := range sortedKeys(columns) {
+			if _, ok := before.tables[table][column]; !ok {
+				diffs = append(diffs, fmt.Sprintf("column %s.%s is new", table, column))
+			}
+		}
+	}
+	for _, table := range after.tableNames() {
+		if _, ok := before.tables[table]; !ok {
+			diffs = append(diffs, fmt.Sprintf("table %s is new", table))
+		}
+	}
+	return diffs
+}
+
+// diffModels lists the tables and columns of the models missing from the snapshot, and
+// the columns of their tables the models do not have.
+func diffModels(models []*schema.Model, snapshot schemaSnapshot) []string {
+	var diffs []string
+	for _, model := range models {
+		table := snapshot.key(model.Schema, model.TableName)
+		columns, ok := snapshot.tables[table]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("table %s of model %s does not exist", table, model.Name))
+			continue
+		}
+		expected := make(map[string]bool, len(model.Fields))
+		for _, field := range model.Fields {
+			if field.IsIgnored {
+				continue
+			}
+			column := strings.ToLower(field.DBName)
+			expected[column] = true
+			if _, ok := columns[column]; !ok {
+				diffs = append(diffs, fmt.Sprintf("column %s.%s of field %s.%s does not exist", table, column, model.Name, field.GoName))
+			}
+		}
+		for _, column := range sortedKeys(columns) {
+			if !expected[column] {
+				diffs = append(diffs, fmt.Sprintf("column %s.%s has no field in model %s", table, column, model.Name))
+			}
+		}
+	}
+	return diffs
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,780 @@
+package typegormtest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+	"github.com/chmenegatti/typegorm/pkg/dialects"
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/typegorm"
+)
+
+// --- Record and Replay ---
+
+// RecordReplay returns a DB for tests that run against a real database once and
+// then without one. When TYPEGORM_RECORD=1 or the fixture file does not exist, the
+// test runs on the database returned by connect and every statement with its result
+// is written to the fixture when the test ends. Otherwise the statements are answered
+// from the fixture, and the test fails if it runs other statements or fewer of them:
+//
+//	func TestCheckout(t *testing.T) {
+//		db := typegormtest.RecordReplay(t, "testdata/checkout.json", "mysql", func(t testing.TB) *typegorm.DB {
+//			return typegormtest.StartDatabase(t, "mysql")
+//		})
+//		db.UseNowFunc(func() time.Time { return frozen }) // Bound values must not change between runs
+//		...
+//	}
+//
+// Replayed statements must be issued in the recorded order, with the same bound
+// values: freeze time and generated IDs (DB.UseNowFunc, DB.UseIDGenerator) and avoid
+// concurrent queries. Errors are replayed by message only (sql.ErrNoRows keeps its
+// identity); driver-specific error types are lost.
+func RecordReplay(t testing.TB, fixture, dialect string, connect func(t testing.TB) *typegorm.DB) *typegorm.DB {
+	t.Helper()
+	cfg := config.NewDefaultConfig()
+	cfg.Database.Dialect = dialect
+
+	_, statErr := os.Stat(fixture)
+	if os.Getenv("TYPEGORM_RECORD") == "1" || errors.Is(statErr, os.ErrNotExist) {
+		recorder := NewRecorder(connect(t).GetDataSource())
+		t.Cleanup(func() {
+			if t.Failed() {
+				t.Logf("typegormtest: test failed, fixture %s not written", fixture)
+				return
+			}
+			if err := recorder.Save(fixture); err != nil {
+				t.Errorf("typegormtest: %v", err)
+			}
+		})
+		return typegorm.NewDB(recorder, nil, cfg)
+	}
+
+	replayer, err := LoadReplayer(fixture)
+	if err != nil {
+		t.Fatalf("typegormtest: %v", err)
+	}
+	if replayer.fixture.Dialect != dialect {
+		t.Fatalf("typegormtest: fixture %s was recorded with %s, not %s", fixture, replayer.fixture.Dialect, dialect)
+	}
+	t.Cleanup(func() {
+		if err := replayer.Done(); err != nil {
+			t.Errorf("typegormtest: %v", err)
+		}
+	})
+	return typegorm.NewDB(replayer, nil, cfg)
+}
+
+// fixture is the file format of recorded statements.
+type fixture struct {
+	Dialect      string         `json:"dialect"`
+	Interactions []*interaction `json:"interactions"`
+}
+
+// interaction is a recorded call: "exec", "query", "query_row", "begin", "commit" or "rollback".
+type interaction struct {
+	Kind         string           `json:"kind"`
+	SQL          string           `json:"sql,omitempty"`
+	Args         []fixtureValue   `json:"args,omitempty"`
+	Columns      []string         `json:"columns,omitempty"`
+	Rows         [][]fixtureValue `json:"rows,omitempty"`
+	RowsAffected int64            `json:"rows_affected,omitempty"`
+	LastInsertID int64            `json:"last_insert_id,omitempty"`
+	NoInsertID   string           `json:"no_insert_id,omitempty"` // Error of LastInsertId
+	Error        string           `json:"error,omitempty"`
+	NoRows       bool             `json:"no_rows,omitempty"` // The error is sql.ErrNoRows
+	RowsError    string           `json:"rows_error,omitempty"`
+}
+
+// fixtureValue is a driver value with its type, so that it is replayed unchanged.
+type fixtureValue struct {
+	Type  string `json:"t"`
+	Value string `json:"v,omitempty"`
+}
+
+// --- Recorder ---
+
+// Recorder is a DataSource recording the statements run on another one, and their
+// results, to a fixture (see RecordReplay).
+type Recorder struct {
+	inner common.DataSource
+	mu    sync.Mutex
+	log   fixture
+}
+
+// NewRecorder returns a Recorder of the statements run on inner.
+func NewRecorder(inner common.DataSource) *Recorder {
+	return &Recorder{inner: inner, log: fixture{Dialect: inner.Dialect().Name()}}
+}
+
+// Save writes the recorded statements to path, creating its directory.
+func (r *Recorder) Save(path string) error {
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.log, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("encoding fixture %s: %w", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("writing fixture %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing fixture %s: %w", path, err)
+	}
+	return nil
+}
+
+func (r *Recorder) add(entry *interaction) *interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.log.Interactions = append(r.log.Interactions, entry)
+	return entry
+}
+
+func (r *Recorder) Close() error                            { return r.inner.Close() }
+func (r *Recorder) Connect(cfg config.DatabaseConfig) error { return r.inner.Connect(cfg) }
+func (r *Recorder) Ping(ctx context.Context) error          { return r.inner.Ping(ctx) }
+func (r *Recorder) Dialect() common.Dialect                 { return r.inner.Dialect() }
+
+func (r *Recorder) Exec(ctx context.Context, query string, args ...any) (common.Result, error) {
+	return recordExec(ctx, r, r.inner.Exec, query, args)
+}
+
+func (r *Recorder) QueryRow(ctx context.Context, query string, args ...any) common.RowScanner {
+	return recordQueryRow(ctx, r, r.inner.QueryRow, query, args)
+}
+
+func (r *Recorder) Query(ctx context.Context, query string, args ...any) (common.Rows, error) {
+	return recordQuery(ctx, r, r.inner.Query, query, args)
+}
+
+func (r *Recorder) BeginTx(ctx context.Context, opts any) (common.Tx, error) {
+	tx, err := r.inner.BeginTx(ctx, opts)
+	r.add(&interaction{Kind: "begin", Error: errorMessage(err)})
+	if err != nil {
+		return nil, err
+	}
+	return &recordingTx{recorder: r, inner: tx}, nil
+}
+
+type recordingTx struct {
+	recorder *Recorder
+	inner    common.Tx
+}
+
+func (tx *recordingTx) Commit() error {
+	err := tx.inner.Commit()
+	tx.recorder.add(&interaction{Kind: "commit", Error: errorMessage(err)})
+	return err
+}
+
+func (tx *recordingTx) Rollback() error {
+	err := tx.inner.Rollback()
+	tx.recorder.add(&interaction{Kind: "rollback", Error: errorMessage(err)})
+	return err
+}
+
+func (tx *recordingTx) Exec(ctx context.Context, query string, args ...any) (common.Result, error) {
+	return recordExec(ctx, tx.recorder, tx.inner.Exec, query, args)
+}
+
+func (tx *recordingTx) QueryRow(ctx context.Context, query string, args ...any) common.RowScanner {
+	return recordQueryRow(ctx, tx.recorder, tx.inner.QueryRow, query, args)
+}
+
+func (tx *recordingTx) Query(ctx context.Context, query string, args ...any) (common.Rows, error) {
+	return recordQuery(ctx, tx.recorder, tx.inner.Query, query, args)
+}
+
+type (
+	execFunc     func(ctx context.Context, query string, args ...any) (common.Result, error)
+	queryRowFunc func(ctx context.Context, query string, args ...any) common.RowScanner
+	queryFunc    func(ctx context.Context, query string, args ...any) (common.Rows, error)
+)
+
+func recordExec(ctx context.Context, r *Recorder, exec execFunc, query string, args []any) (common.Result, error) {
+	res, err := exec(ctx, query, args...)
+	entry := &interaction{Kind: "exec", SQL: query, Args: encodeArgs(args), Error: errorMessage(err)}
+	r.add(entry)
+	if err != nil {
+		return nil, err
+	}
+	entry.RowsAffected, _ = res.RowsAffected()
+	var idErr error
+	if entry.LastInsertID, idErr = res.LastInsertId(); idErr != nil {
+		entry.NoInsertID = idErr.Error()
+	}
+	return res, nil
+}
+
+func recordQueryRow(ctx context.Context, r *Recorder, queryRow queryRowFunc, query string, args []any) common.RowScanner {
+	entry := r.add(&interaction{Kind: "query_row", SQL: query, Args: encodeArgs(args)})
+	return recordingRow{entry: entry, inner: queryRow(ctx, query, args...)}
+}
+
+func recordQuery(ctx context.Context, r *Recorder, query queryFunc, sqlQuery string, args []any) (common.Rows, error) {
+	rows, err := query(ctx, sqlQuery, args...)
+	entry := r.add(&interaction{Kind: "query", SQL: sqlQuery, Args: encodeArgs(args), Error: errorMessage(err)})
+	if err != nil {
+		return nil, err
+	}
+	entry.Columns, _ = rows.Columns()
+	return &recordingRows{entry: entry, inner: rows}, nil
+}
+
+// recordingRow scans the raw driver values, records them and assigns them to dest
+// as a replay would, so that conversion problems show up while recording.
+type recordingRow struct {
+	entry *interaction
+	inner common.RowScanner
+}
+
+func (row recordingRow) Scan(dest ...any) error {
+	raw, ptrs := rawDest(len(dest))
+	if err := row.inner.Scan(ptrs...); err != nil {
+		row.entry.Error, row.entry.NoRows = err.Error(), errors.Is(err, sql.ErrNoRows)
+		return err
+	}
+	row.entry.Rows = [][]fixtureValue{encodeValues(raw)}
+	return assignRow(dest, raw)
+}
+
+type recordingRows struct {
+	entry *interaction
+	inner common.Rows
+}
+
+func (rows *recordingRows) Next() bool                 { return rows.inner.Next() }
+func (rows *recordingRows) Columns() ([]string, error) { return rows.inner.Columns() }
+func (rows *recordingRows) Close() error               { return rows.inner.Close() }
+
+func (rows *recordingRows) Err() error {
+	err := rows.inner.Err()
+	rows.entry.RowsError = errorMessage(err)
+	return err
+}
+
+func (rows *recordingRows) Scan(dest ...any) error {
+	raw, ptrs := rawDest(len(dest))
+	if err := rows.inner.Scan(ptrs...); err != nil {
+		return err
+	}
+	rows.entry.Rows = append(rows.entry.Rows, encodeValues(raw))
+	return assignRow(dest, raw)
+}
+
+// --- Replayer ---
+
+// Replayer is a DataSource answering statements from a fixture written by a Recorder,
+// without a database (see RecordReplay).
+type Replayer struct {
+	fixture fixture
+	dialect common.Dialect
+	mu      sync.Mutex
+	next    int
+}
+
+// LoadReplayer reads a fixture. The driver of its dialect must be registered, for the
+// dialect's SQL syntax.
+func LoadReplayer(path string) (*Replayer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading fixture: %w", err)
+	}
+	r := &Replayer{}
+	if err := json.Unmarshal(data, &r.fixture); err != nil {
+		return nil, fmt.Errorf("decoding fixture %s: %w", path, err)
+	}
+	factory := dialects.Get(r.fixture.Dialect)
+	if factory == nil {
+		return nil, fmt.Errorf("fixture %s: dialect %q is not registered; import its driver package", path, r.fixture.Dialect)
+	}
+	r.dialect = factory().Dialect()
+	return r, nil
+}
+
+// Done returns an error when recorded statements were not replayed.
+func (r *Replayer) Done() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if left := len(r.fixture.Interactions) - r.next; left > 0 {
+		next := r.fixture.Interactions[r.next]
+		return fmt.Errorf("replay: %d recorded call(s) not made, starting with %s %s", left, next.Kind, next.SQL)
+	}
+	return nil
+}
+
+// take returns the next recorded call, checking that it is the one being made.
+func (r *Replayer) take(kind, query string, args []any) (*interaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.next >= len(r.fixture.Interactions) {
+		return nil, fmt.Errorf("replay: unexpected %s %s: all %d recorded calls were made", kind, query, len(r.fixture.Interactions))
+	}
+	entry := r.fixture.Interactions[r.next]
+	encoded := encodeArgs(args)
+	if entry.Kind != kind || entry.SQL != query || !reflect.DeepEqual(entry.Args, encoded) {
+		return nil, fmt.Errorf("replay: call %d is %s %s %v, but %s %s %v was recorded",
+			r.next+1, kind, query, encoded, entry.Kind, entry.SQL, entry.Args)
+	}
+	r.next++
+	return entry, nil
+}
+
+func (r *Replayer) Close() error                        { return nil }
+func (r *Replayer) Connect(config.DatabaseConfig) error { return nil }
+func (r *Replayer) Ping(context.Context) error          { return nil }
+func (r *Replayer) Dialect() common.Dialect             { return r.dialect }
+
+func (r *Replayer) Exec(_ context.Context, query string, args ...any) (common.Result, error) {
+	return r.exec(query, args)
+}
+
+func (r *Replayer) QueryRow(_ context.Context, query string, args ...any) common.RowScanner {
+	return r.queryRow(query, args)
+}
+
+func (r *Replayer) Query(_ context.Context, query string, args ...any) (common.Rows, error) {
+	return r.query(query, args)
+}
+
+func (r *Replayer) BeginTx(context.Context, any) (common.Tx, error) {
+	entry, err := r.take("begin", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := replayedError(entry); err != nil {
+		return nil, err
+	}
+	return replayTx{r}, nil
+}
+
+func (r *Replayer) exec(query string, args []any) (common.Result, error) {
+	entry, err := r.take("exec", query, args)
+	if err != nil {
+		return nil, err
+	}
+	if err := replayedError(entry); err != nil {
+		return nil, err
+	}
+	return replayResult{entry}, nil
+}
+
+func (r *Replayer) queryRow(query string, args []any) common.RowScanner {
+	entry, err := r.take("query_row", query, args)
+	return replayRow{entry: entry, err: err}
+}
+
+func (r *Replayer) query(query string, args []any) (common.Rows, error) {
+	entry, err := r.take("query", query, args)
+	if err != nil {
+		return nil, err
+	}
+	if err := replayedError(entry); err != nil {
+		return nil, err
+	}
+	return &replayRows{entry: entry, row: -1}, nil
+}
+
+type replayTx struct{ r *Replayer }
+
+func (tx replayTx) Commit() error   { return tx.end("commit") }
+func (tx replayTx) Rollback() error { return tx.end("rollback") }
+
+func (tx replayTx) end(kind string) error {
+	entry, err := tx.r.take(kind, "", nil)
+	if err != nil {
+		return err
+	}
+	return replayedError(entry)
+}
+
+func (tx replayTx) Exec(_ context.Context, query string, args ...any) (common.Result, error) {
+	return tx.r.exec(query, args)
+}
+
+func (tx replayTx) QueryRow(_ context.Context, query string, args ...any) common.RowScanner {
+	return tx.r.queryRow(query, args)
+}
+
+func (tx replayTx) Query(_ context.Context, query string, args ...any) (common.Rows, error) {
+	return tx.r.query(query, args)
+}
+
+type replayResult struct{ entry *interaction }
+
+func (res replayResult) RowsAffected() (int64, error) { return res.entry.RowsAffected, nil }
+
+func (res replayResult) LastInsertId() (int64, error) {
+	if res.entry.NoInsertID != "" {
+		return 0, errors.New(res.entry.NoInsertID)
+	}
+	return res.entry.LastInsertID, nil
+}
+
+type replayRow struct {
+	entry *interaction
+	err   error
+}
+
+func (row replayRow) Scan(dest ...any) error {
+	if row.err != nil {
+		return row.err
+	}
+	if err := replayedError(row.entry); err != nil {
+		return err
+	}
+	if len(row.entry.Rows) == 0 {
+		return sql.ErrNoRows
+	}
+	return assignEncodedRow(dest, row.entry.Rows[0])
+}
+
+type replayRows struct {
+	entry *interaction
+	row   int
+}
+
+func (rows *replayRows) Next() bool {
+	if rows.row+1 >= len(rows.entry.Rows) {
+		return false
+	}
+	rows.row++
+	return true
+}
+
+func (rows *replayRows) Scan(dest ...any) error {
+	if rows.row < 0 || rows.row >= len(rows.entry.Rows) {
+		return fmt.Errorf("replay: Scan called without a row")
+	}
+	return assignEncodedRow(dest, rows.entry.Rows[rows.row])
+}
+
+func (rows *replayRows) Columns() ([]string, error) { return rows.entry.Columns, nil }
+func (rows *replayRows) Close() error               { return nil }
+
+func (rows *replayRows) Err() error {
+	if rows.entry.RowsError != "" && rows.row+1 >= len(rows.entry.Rows) {
+		return errors.New(rows.entry.RowsError)
+	}
+	return nil
+}
+
+func replayedError(entry *interaction) error {
+	switch {
+	case entry.NoRows:
+		return sql.ErrNoRows
+	case entry.Error != "":
+		return errors.New(entry.Error)
+	}
+	return nil
+}
+
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// --- Value Encoding ---
+
+// rawDest returns destinations receiving the raw driver values of a row.
+func rawDest(n int) ([]any, []any) {
+	raw := make([]any, n)
+	ptrs := make([]any, n)
+	for i := range raw {
+		ptrs[i] = &raw[i]
+	}
+	return raw, ptrs
+}
+
+func encodeArgs(args []any) []fixtureValue {
+	values := make([]any, len(args))
+	for i, arg := range args {
+		value, err := driver.DefaultParameterConverter.ConvertValue(arg)
+		if err != nil {
+			value = fmt.Sprintf("%v", arg) // Driver-specific types: compared by their text
+		}
+		values[i] = value
+	}
+	return encodeValues(values)
+}
+
+func encodeValues(values []any) []fixtureValue {
+	if len(values) == 0 {
+		return nil
+	}
+	encoded := make([]fixtureValue, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case nil:
+			encoded[i] = fixtureValue{Type: "null"}
+		case int64:
+			encoded[i] = fixtureValue{Type: "int64", Value: strconv.FormatInt(v, 10)}
+		case uint64:
+			encoded[i] = fixtureValue{Type: "uint64", Value: strconv.FormatUint(v, 10)}
+		case float64:
+			encoded[i] = fixtureValue{Type: "float64", Value: strconv.FormatFloat(v, 'g', -1, 64)}
+		case bool:
+			encoded[i] = fixtureValue{Type: "bool", Value: strconv.FormatBool(v)}
+		case []byte:
+			encoded[i] = fixtureValue{Type: "bytes", Value: base64.StdEncoding.EncodeToString(v)}
+		case time.Time:
+			encoded[i] = fixtureValue{Type: "time", Value: v.Format(time.RFC3339Nano)}
+		case string:
+			encoded[i] = fixtureValue{Type: "string", Value: v}
+		default:
+			encoded[i] = fixtureValue{Type: "string", Value: fmt.Sprintf("%v", v)}
+		}
+	}
+	return encoded
+}
+
+func decodeValue(value fixtureValue) (any, error) {
+	switch value.Type {
+	case "null":
+		return nil, nil
+	case "int64":
+		return strconv.ParseInt(value.Value, 10, 64)
+	case "uint64":
+		return strconv.ParseUint(value.Value, 10, 64)
+	case "float64":
+		return strconv.ParseFloat(value.Value, 64)
+	case "bool":
+		return strconv.ParseBool(value.Value)
+	case "bytes":
+		return base64.StdEncoding.DecodeString(value.Value)
+	case "time":
+		return time.Parse(time.RFC3339Nano, value.Value)
+	case "string":
+		return value.Value, nil
+	}
+	return nil, fmt.Errorf("unknown value type %q", value.Type)
+}
+
+func assignEncodedRow(dest []any, row []fixtureValue) error {
+	if len(dest) != len(row) {
+		return fmt.Errorf("replay: %d destination(s) for %d recorded column(s)", len(dest), len(row))
+	}
+	raw := make([]any, len(row))
+	for i, value := range row {
+		var err error
+		if raw[i], err = decodeValue(value); err != nil {
+			return fmt.Errorf("replay: column %d: %w", i, err)
+		}
+	}
+	return assignRow(dest, raw)
+}
+
+func assignRow(dest, raw []any) error {
+	for i := range dest {
+		if err := assignValue(dest[i], raw[i]); err != nil {
+			return fmt.Errorf("converting column %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// assignValue stores a driver value in a Scan destination, converting it like
+// database/sql does for the usual destination types.
+func assignValue(dest, src any) error {
+	if scanner, ok := dest.(sql.Scanner); ok {
+		return scanner.Scan(src)
+	}
+	ptr := reflect.ValueOf(dest)
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() {
+		return fmt.Errorf("destination must be a non-nil pointer, got %T", dest)
+	}
+	return setScanned(ptr.Elem(), src)
+}
+
+func setScanned(target reflect.Value, src any) error {
+	if src == nil {
+		switch target.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+			target.SetZero()
+			return nil
+		}
+		return fmt.Errorf("converting NULL to %s is unsupported", target.Type())
+	}
+	if b, ok := src.([]byte); ok {
+		src = append([]byte(nil), b...) // The driver may reuse its buffer
+	}
+	if target.Kind() == reflect.Interface {
+		target.Set(reflect.ValueOf(src))
+		return nil
+	}
+	if target.Kind() == reflect.Pointer {
+		elem := reflect.New(target.Type().Elem())
+		if scanner, ok := elem.Interface().(sql.Scanner); ok {
+			if err := scanner.Scan(src); err != nil {
+				return err
+			}
+		} else if err := setScanned(elem.Elem(), src); err != nil {
+			return err
+		}
+		target.Set(elem)
+		return nil
+	}
+
+	value := reflect.ValueOf(src)
+	if value.Type().AssignableTo(target.Type()) {
+		target.Set(value)
+		return nil
+	}
+	text, isText := src.(string)
+	if b, ok := src.([]byte); ok {
+		text, isText = string(b), true
+	}
+	switch target.Kind() {
+	case reflect.String:
+		switch v := src.(type) {
+		case time.Time:
+			target.SetString(v.Format(time.RFC3339Nano))
+		default:
+			if isText {
+				target.SetString(text)
+			} else {
+				target.SetString(fmt.Sprintf("%v", src))
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if target.Type().Elem().Kind() == reflect.Uint8 && isText {
+			target.SetBytes([]byte(text))
+			return nil
+		}
+	case reflect.Bool:
+		if isText {
+			parsed, err := strconv.ParseBool(text)
+			if err != nil {
+				return err
+			}
+			target.SetBool(parsed)
+			return nil
+		}
+		if n, ok := src.(int64); ok {
+			target.SetBool(n != 0)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if isText {
+			if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+				value = reflect.ValueOf(i)
+			} else if u, err := strconv.ParseUint(text, 10, 64); err == nil {
+				value = reflect.ValueOf(u)
+			} else if f, err := strconv.ParseFloat(text, 64); err == nil {
+				value = reflect.ValueOf(f)
+			} else {
+				return err
+			}
+		}
+		if ok, err := setNumber(target, value); ok || err != nil {
+			return err
+		}
+	case reflect.Struct:
+		if target.Type() == reflect.TypeOf(time.Time{}) && isText {
+			for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02"} {
+				if parsed, err := time.Parse(layout, text); err == nil {
+					target.Set(reflect.ValueOf(parsed))
+					return nil
+				}
+			}
+		}
+	}
+	return fmt.Errorf("converting %T to %s is unsupported", src, target.Type())
+}
+
+// setNumber stores a numeric value in a numeric target; it reports false when value is
+// not a number. Conversions losing information fail: integers out of the target's range,
+// floats with a fractional part or out of range stored in integers, and integers a
+// float target cannot represent exactly.
+func setNumber(target reflect.Value, value reflect.Value) (bool, error) {
+	lossy := func() (bool, error) {
+		return true, fmt.Errorf("converting %v (%s) to %s loses information", value.Interface(), value.Type(), target.Type())
+	}
+	var f float64
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := value.Int()
+		switch target.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if target.OverflowInt(n) {
+				return lossy()
+			}
+			target.SetInt(n)
+			return true, nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if n < 0 || target.OverflowUint(uint64(n)) {
+				return lossy()
+			}
+			target.SetUint(uint64(n))
+			return true, nil
+		}
+		if f = float64(n); f >= 1<<63 || int64(f) != n {
+			return lossy()
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := value.Uint()
+		switch target.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if u > math.MaxInt64 || target.OverflowInt(int64(u)) {
+				return lossy()
+			}
+			target.SetInt(int64(u))
+			return true, nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if target.OverflowUint(u) {
+				return lossy()
+			}
+			target.SetUint(u)
+			return true, nil
+		}
+		if f = float64(u); f >= 1<<64 || uint64(f) != u {
+			return lossy()
+		}
+	case reflect.Float32, reflect.Float64:
+		f = value.Float()
+		switch target.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if f != math.Trunc(f) || f < -(1<<63) || f >= 1<<63 || target.OverflowInt(int64(f)) {
+				return lossy()
+			}
+			target.SetInt(int64(f))
+			return true, nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if f != math.Trunc(f) || f < 0 || f >= 1<<64 || target.OverflowUint(uint64(f)) {
+				return lossy()
+			}
+			target.SetUint(uint64(f))
+			return true, nil
+		}
+	default:
+		return false, nil
+	}
+	// Float target
+	if target.OverflowFloat(f) {
+		return lossy()
+	}
+	if value.Kind() != reflect.Float32 && value.Kind() != reflect.Float64 && target.Kind() == reflect.Float32 && float64(float32(f)) != f {
+		return lossy() // Integer beyond the 24-bit mantissa of a float32
+	}
+	target.SetFloat(f)
+	return true, nil
+}
@@ -0,0 +1,199 @@
+package typegormtest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+	"github.com/chmenegatti/typegorm/pkg/dialects"
+	"github.com/chmenegatti/typegorm/pkg/dialects/common"
+	"github.com/chmenegatti/typegorm/pkg/typegorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cannedDialect is registered as "canned" for the replay tests.
+type cannedDialect struct{ common.Dialect }
+
+func (cannedDialect) Name() string             { return "canned" }
+func (cannedDialect) Quote(name string) string { return "`" + name + "`" }
+func (cannedDialect) BindVar(int) string       { return "?" }
+
+// cannedSource answers every query with the same rows of replayUser, as a database would.
+type cannedSource struct {
+	common.DataSource
+	execs int
+}
+
+func (s *cannedSource) Dialect() common.Dialect { return cannedDialect{} }
+func (s *cannedSource) Close() error            { return nil }
+
+func (s *cannedSource) BeginTx(context.Context, any) (common.Tx, error) { return cannedTx{s}, nil }
+
+func (s *cannedSource) Exec(context.Context, string, ...any) (common.Result, error) {
+	s.execs++
+	return cannedResult(s.execs), nil
+}
+
+func (s *cannedSource) QueryRow(_ context.Context, query string, args ...any) common.RowScanner {
+	return cannedRow{args: args}
+}
+
+func (s *cannedSource) Query(context.Context, string, ...any) (common.Rows, error) {
+	return &cannedRows{row: -1}, nil
+}
+
+type cannedTx struct{ s *cannedSource }
+
+func (tx cannedTx) Commit() error   { return nil }
+func (tx cannedTx) Rollback() error { return nil }
+func (tx cannedTx) Exec(ctx context.Context, query string, args ...any) (common.Result, error) {
+	return tx.s.Exec(ctx, query, args...)
+}
+func (tx cannedTx) QueryRow(ctx context.Context, query string, args ...any) common.RowScanner {
+	return tx.s.QueryRow(ctx, query, args...)
+}
+func (tx cannedTx) Query(ctx context.Context, query string, args ...any) (common.Rows, error) {
+	return tx.s.Query(ctx, query, args...)
+}
+
+type cannedResult int64
+
+func (r cannedResult) LastInsertId() (int64, error) { return int64(r), nil }
+func (r cannedResult) RowsAffected() (int64, error) { return 1, nil }
+
+var cannedData = [][]any{{int64(1), []byte("Ana"), int64(30)}, {int64(2), []byte("Bia"), nil}}
+
+type cannedRow struct{ args []any }
+
+func (row cannedRow) Scan(dest ...any) error {
+	if len(row.args) > 0 && row.args[0] == "missing" {
+		return sql.ErrNoRows
+	}
+	for i, value := range cannedData[0] {
+		*dest[i].(*any) = value
+	}
+	return nil
+}
+
+type cannedRows struct{ row int }
+
+func (rows *cannedRows) Next() bool                 { rows.row++; return rows.row < len(cannedData) }
+func (rows *cannedRows) Columns() ([]string, error) { return []string{"id", "name", "age"}, nil }
+func (rows *cannedRows) Err() error                 { return nil }
+func (rows *cannedRows) Close() error               { return nil }
+func (rows *cannedRows) Scan(dest ...any) error {
+	for i, value := range cannedData[rows.row] {
+		*dest[i].(*any) = value
+	}
+	return nil
+}
+
+type replayUser struct {
+	ID   uint `typegorm:"primaryKey;autoIncrement"`
+	Name string
+	Age  *int
+}
+
+var registerCanned sync.Once
+
+// exercise runs the same operations in record and replay mode, returning what they read.
+func exercise(t *testing.T, db *typegorm.DB) string {
+	ctx := context.Background()
+	var created, first, missing replayUser
+	var users []replayUser
+	err := db.Transaction(ctx, func(ctx context.Context, tx *typegorm.Tx) error {
+		created = replayUser{Name: "Caio"}
+		return tx.Create(ctx, &created).Error
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Find(ctx, &users, map[string]any{"name": "Ana"}).Error)
+	require.NoError(t, db.FindFirst(ctx, &first, map[string]any{"name": "Ana"}).Error)
+	assert.ErrorIs(t, db.FindFirst(ctx, &missing, map[string]any{"name": "missing"}).Error, sql.ErrNoRows)
+	return fmt.Sprintf("%d %d:%s:%d %d %s %d", created.ID, first.ID, first.Name, *first.Age, len(users), users[1].Name, *users[0].Age)
+}
+
+func TestRecordReplay_RoundTrip(t *testing.T) {
+	registerCanned.Do(func() {
+		dialects.Register("canned", func() common.DataSource { return &cannedSource{} })
+	})
+	fixture := filepath.Join(t.TempDir(), "testdata", "roundtrip.json")
+	var recorded, replayed string
+
+	t.Run("record", func(t *testing.T) {
+		db := RecordReplay(t, fixture, "canned", func(testing.TB) *typegorm.DB {
+			return typegorm.NewDB(&cannedSource{}, nil, config.Config{})
+		})
+		recorded = exercise(t, db)
+	})
+	require.FileExists(t, fixture)
+
+	t.Run("replay", func(t *testing.T) {
+		db := RecordReplay(t, fixture, "canned", func(t testing.TB) *typegorm.DB {
+			t.Fatal("replay must not connect")
+			return nil
+		})
+		replayed = exercise(t, db)
+	})
+	assert.Equal(t, recorded, replayed)
+	assert.Equal(t, "1 1:Ana:30 2 Bia 30", replayed)
+
+	replayer, err := LoadReplayer(fixture)
+	require.NoError(t, err)
+	_, err = replayer.Exec(context.Background(), "DELETE FROM `replay_users`")
+	assert.ErrorContains(t, err, "call 1 is exec DELETE FROM `replay_users` [], but begin  [] was recorded")
+	assert.ErrorContains(t, replayer.Done(), "not made, starting with begin")
+
+	_, err = LoadReplayer(filepath.Join(t.TempDir(), "none.json"))
+	assert.Error(t, err)
+	require.NoError(t, os.WriteFile(fixture, []byte(`{"dialect":"nope"}`), 0o644))
+	_, err = LoadReplayer(fixture)
+	assert.ErrorContains(t, err, `dialect "nope" is not registered`)
+}
+
+func TestAssignValue(t *testing.T) {
+	var s string
+	var n int32
+	var f float64
+	var b bool
+	var p *int
+	var ns sql.NullString
+	require.NoError(t, assignValue(&s, []byte("x")))
+	require.NoError(t, assignValue(&n, []byte("42")))
+	require.NoError(t, assignValue(&f, int64(3)))
+	require.NoError(t, assignValue(&b, int64(1)))
+	require.NoError(t, assignValue(&p, int64(7)))
+	require.NoError(t, assignValue(&ns, "y"))
+	assert.Equal(t, "x", s)
+	assert.Equal(t, int32(42), n)
+	assert.Equal(t, 3.0, f)
+	assert.True(t, b)
+	assert.Equal(t, 7, *p)
+	assert.Equal(t, sql.NullString{String: "y", Valid: true}, ns)
+
+	require.NoError(t, assignValue(&p, nil))
+	assert.Nil(t, p)
+	assert.ErrorContains(t, assignValue(&n, nil), "converting NULL to int32")
+	assert.Error(t, assignValue(&n, []byte("abc")))
+
+	// Lossy conversions fail instead of wrapping or truncating
+	var u8 uint8
+	var u64 uint64
+	var f32 float32
+	assert.ErrorContains(t, assignValue(&n, int64(1)<<40), "loses information")
+	assert.Error(t, assignValue(&u8, int64(-1)))
+	assert.Error(t, assignValue(&n, 1.5))
+	assert.Error(t, assignValue(&n, []byte("2.5")))
+	assert.Error(t, assignValue(&f, uint64(1)<<63+1), "beyond the float64 mantissa")
+	assert.Error(t, assignValue(&f32, int64(1)<<24+1))
+	require.NoError(t, assignValue(&n, 42.0))
+	assert.Equal(t, int32(42), n)
+	require.NoError(t, assignValue(&u64, []byte("18446744073709551615")))
+	assert.Equal(t, uint64(math.MaxUint64), u64)
+}
@@ -0,0 +1,112 @@
+package typegormtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/chmenegatti/typegorm/pkg/typegorm"
+)
+
+// SuiteRecord is the model used by RunSuite.
+type SuiteRecord struct {
+	ID        uint   `typegorm:"primaryKey;autoIncrement"`
+	Email     string `typegorm:"size:191;unique"`
+	Name      string `typegorm:"size:100;not null"`
+	Group     string `typegorm:"column:group;size:50"` // Reserved word as column name
+	Score     int    `typegorm:"default:0"`
+	CreatedAt time.Time
+}
+
+// RunSuite runs the shared integration suite (migration, CRUD, ordering, transactions,
+// error translation) against db, so every dialect is held to the same behavior:
+//
+//	typegormtest.RunSuite(t, typegormtest.StartDatabase(t, "postgres"))
+func RunSuite(t *testing.T, db *typegorm.DB) {
+	ctx := context.Background()
+	if err := db.AutoMigrate(ctx, &SuiteRecord{}); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	model, err := db.GetModel(&SuiteRecord{})
+	if err != nil {
+		t.Fatalf("GetModel: %v", err)
+	}
+	t.Cleanup(func() {
+		dropSQL := fmt.Sprintf("DROP TABLE IF EXISTS %s", db.GetDataSource().Dialect().Quote(model.TableName))
+		if _, err := db.GetDataSource().Exec(context.Background(), dropSQL); err != nil {
+			t.Logf("dropping %s: %v", model.TableName, err)
+		}
+	})
+
+	t.Run("CreateAndFindByID", func(t *testing.T) {
+		record := &SuiteRecord{Email: "ana@example.com", Name: "Ana", Group: "admin", Score: 10}
+		if res := db.Create(ctx, record); res.Error != nil {
+			t.Fatalf("Create: %v", res.Error)
+		}
+		if record.ID == 0 {
+			t.Fatal("Create did not set the auto-increment ID")
+		}
+		var found SuiteRecord
+		if res := db.FindByID(ctx, &found, record.ID); res.Error != nil {
+			t.Fatalf("FindByID: %v", res.Error)
+		}
+		if found.Email != record.Email || found.Group != "admin" {
+			t.Errorf("FindByID returned %+v, want %+v", found, *record)
+		}
+	})
+
+	t.Run("DuplicateKey", func(t *testing.T) {
+		res := db.Create(ctx, &SuiteRecord{Email: "ana@example.com", Name: "Ana 2"})
+		if !errors.Is(res.Error, typegorm.ErrDuplicateKey) {
+			t.Fatalf("Create duplicate: got %v, want ErrDuplicateKey", res.Error)
+		}
+	})
+
+	t.Run("UpdatesAndOrder", func(t *testing.T) {
+		bia := &SuiteRecord{Email: "bia@example.com", Name: "Bia", Group: "users", Score: 5}
+		if res := db.Create(ctx, bia); res.Error != nil {
+			t.Fatalf("Create: %v", res.Error)
+		}
+		if res := db.Updates(ctx, bia, map[string]any{"score": 20}); res.Error != nil || res.RowsAffected != 1 {
+			t.Fatalf("Updates: %v (rows %d)", res.Error, res.RowsAffected)
+		}
+		var records []SuiteRecord
+		if res := db.Find(ctx, &records, typegorm.Order("group DESC")); res.Error != nil {
+			t.Fatalf("Find: %v", res.Error)
+		}
+		if len(records) != 2 || records[0].Name != "Bia" || records[0].Score != 20 {
+			t.Errorf("Find ordered by group returned %+v", records)
+		}
+	})
+
+	t.Run("TransactionRollback", func(t *testing.T) {
+		tx, err := db.Begin(ctx)
+		if err != nil {
+			t.Fatalf("Begin: %v", err)
+		}
+		if res := tx.Create(ctx, &SuiteRecord{Email: "eva@example.com", Name: "Eva"}); res.Error != nil {
+			_ = tx.Rollback()
+			t.Fatalf("tx.Create: %v", res.Error)
+		}
+		if err := tx.Rollback(); err != nil {
+			t.Fatalf("Rollback: %v", err)
+		}
+		AssertCount(t, db, &SuiteRecord{}, 0, map[string]any{"email": "eva@example.com"})
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		var records []SuiteRecord
+		if res := db.Find(ctx, &records); res.Error != nil {
+			t.Fatalf("Find: %v", res.Error)
+		}
+		for i := range records {
+			if res := db.Delete(ctx, &records[i]); res.Error != nil || res.RowsAffected != 1 {
+				t.Fatalf("Delete: %v (rows %d)", res.Error, res.RowsAffected)
+			}
+			AssertDeleted(t, db, &records[i])
+		}
+		AssertCount(t, db, &SuiteRecord{}, 0)
+	})
+}
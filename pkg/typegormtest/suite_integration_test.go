@@ -0,0 +1,129 @@
+//go:build integration
+
+// To run: go test -tags=integration ./pkg/typegormtest/... -v
+// TYPEGORM_TEST_DIALECTS selects the dialects (default: mysql,postgres,sqlserver);
+// dialects whose driver is not registered are skipped.
+
+package typegormtest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "github.com/chmenegatti/typegorm/pkg/dialects/mysql"
+)
+
+func TestSuite_AllDialects(t *testing.T) {
+	list := os.Getenv("TYPEGORM_TEST_DIALECTS")
+	if list == "" {
+		list = "mysql,postgres,sqlserver"
+	}
+	for _, dialect := range strings.Split(list, ",") {
+		dialect = strings.TrimSpace(dialect)
+		t.Run(dialect, func(t *testing.T) {
+			RunSuite(t, StartDatabase(t, dialect))
+		})
+	}
+}
+
+type roundTripItem struct {
+	_    struct{} `typegorm:"table:roundtrip_items"`
+	ID   int      `typegorm:"primaryKey"`
+	Name string   `typegorm:"size:100"`
+}
+
+func TestCheckMigrations_AllDialects(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"20240101000000_create_items.sql": "-- +migrate Up\nCREATE TABLE roundtrip_items (id INT PRIMARY KEY);\n-- +migrate Down\nDROP TABLE roundtrip_items;\n",
+		"20240101000001_add_name.sql":     "-- +migrate Up\nALTER TABLE roundtrip_items ADD name VARCHAR(100) NULL;\n-- +migrate Down\nALTER TABLE roundtrip_items DROP COLUMN name;\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list := os.Getenv("TYPEGORM_TEST_DIALECTS")
+	if list == "" {
+		list = "mysql,postgres,sqlserver"
+	}
+	for _, dialect := range strings.Split(list, ",") {
+		dialect = strings.TrimSpace(dialect)
+		t.Run(dialect, func(t *testing.T) {
+			CheckMigrations(t, dialect, dir, &roundTripItem{})
+		})
+	}
+}
+
+func TestTemplate_AllDialects(t *testing.T) {
+	list := os.Getenv("TYPEGORM_TEST_DIALECTS")
+	if list == "" {
+		list = "mysql,postgres,sqlserver"
+	}
+	for _, dialect := range strings.Split(list, ",") {
+		dialect = strings.TrimSpace(dialect)
+		t.Run(dialect, func(t *testing.T) {
+			_, dsn := startDatabase(t, dialect)
+			tpl, err := NewTemplate(dialect, dsn, AutoMigrated(&SuiteRecord{}))
+			if err != nil {
+				t.Fatalf("NewTemplate: %v", err)
+			}
+			defer tpl.Close()
+
+			ctx := context.Background()
+			first := tpl.Database(t)
+			if res := first.Create(ctx, &SuiteRecord{Email: "ana@example.com", Name: "Ana"}); res.Error != nil {
+				t.Fatalf("Create: %v", res.Error)
+			}
+			var records []SuiteRecord
+			if res := tpl.Database(t).Find(ctx, &records); res.Error != nil || len(records) != 0 {
+				t.Errorf("a fresh copy returned %d record(s) (error %v), want an empty table", len(records), res.Error)
+			}
+		})
+	}
+}
+
+func TestNewIsolatedDB_Parallel(t *testing.T) {
+	dialect := strings.TrimSpace(strings.Split(os.Getenv("TYPEGORM_TEST_DIALECTS")+",mysql", ",")[0])
+	if dialect == "" {
+		dialect = "mysql"
+	}
+	t.Cleanup(func() { _ = StopShared() })
+	for i := 0; i < 3; i++ {
+		t.Run(fmt.Sprintf("test%d", i), func(t *testing.T) {
+			t.Parallel()
+			db := NewIsolatedDB(t, dialect)
+			ctx := context.Background()
+			if err := db.AutoMigrate(ctx, &SuiteRecord{}); err != nil {
+				t.Fatalf("AutoMigrate: %v", err)
+			}
+			// The same unique email in every test: each one has its own table
+			if res := db.Create(ctx, &SuiteRecord{Email: "ana@example.com", Name: "Ana"}); res.Error != nil {
+				t.Fatalf("Create: %v", res.Error)
+			}
+		})
+	}
+}
+
+func TestFactory_CreateBatch(t *testing.T) {
+	db := StartDatabase(t, "mysql")
+	ctx := context.Background()
+	if err := db.AutoMigrate(ctx, &SuiteRecord{}); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	FactoryChunkSize = 40
+	t.Cleanup(func() { FactoryChunkSize = 500 })
+
+	records, err := Factory[SuiteRecord](db).With("Group", "load").CreateBatch(100)
+	if err != nil {
+		t.Fatalf("CreateBatch: %v", err)
+	}
+	if records[99].ID == 0 {
+		t.Error("CreateBatch did not set the generated IDs")
+	}
+	AssertCount(t, db, &SuiteRecord{}, 100, map[string]any{"group": "load"})
+}
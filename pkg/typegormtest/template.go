@@ -0,0 +1,271 @@
+package typegormtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/config"
+	"github.com/chmenegatti/typegorm/pkg/migration"
+	"github.com/chmenegatti/typegorm/pkg/typegorm"
+)
+
+// --- Template Databases ---
+
+// Setup brings an empty database to the schema (and fixtures) the tests expect. dsn
+// is the database's DSN, for tools opening their own connection.
+type Setup func(ctx context.Context, db *typegorm.DB, dsn string) error
+
+// AutoMigrated is a Setup creating the models' tables with AutoMigrate.
+func AutoMigrated(models ...any) Setup {
+	return func(ctx context.Context, db *typegorm.DB, _ string) error {
+		return db.AutoMigrate(ctx, models...)
+	}
+}
+
+// Migrated is a Setup applying the migrations of dir.
+func Migrated(dir string) Setup {
+	return func(_ context.Context, db *typegorm.DB, dsn string) error {
+		cfg := config.NewDefaultConfig()
+		cfg.Database.Dialect = db.GetDataSource().Dialect().Name()
+		cfg.Database.DSN = dsn
+		cfg.Migration.Directory = dir
+		cfg.Migration.AllowDestructive = true
+		return migration.RunUp(cfg)
+	}
+}
+
+// Template is a database set up once and cloned for every test, so that each test
+// gets a fresh, migrated database in milliseconds:
+//
+//   - Postgres: CREATE DATABASE ... TEMPLATE of the migrated database;
+//   - SQLite: a copy of the migrated database file.
+//
+// Other dialects cannot clone a database: each test gets a new database on the same
+// server, set up again. Share one Template (and container) between the tests of a
+// package from TestMain:
+//
+//	var tpl *typegormtest.Template
+//
+//	func TestMain(m *testing.M) {
+//		container, _ := typegormtest.Start("postgres")
+//		tpl, _ = typegormtest.NewTemplate("postgres", container.DSN, typegormtest.Migrated("../../migrations"))
+//		code := m.Run()
+//		tpl.Close()
+//		container.Stop()
+//		os.Exit(code)
+//	}
+//
+//	func TestOrders(t *testing.T) {
+//		db := tpl.Database(t)
+//		...
+//	}
+type Template struct {
+	dialect string
+	dsn     string       // Server connection (SQLite: the template file)
+	admin   *typegorm.DB // Connection creating and dropping databases (nil for SQLite)
+	name    string       // Template database (SQLite: temporary directory)
+	setup   Setup
+	clones  atomic.Int64
+}
+
+// templateSeq numbers the databases created by templates of this process.
+var templateSeq atomic.Int64
+
+// NewTemplate connects to the server of dsn (waiting up to StartupTimeout for it to
+// accept connections), then creates and sets up the template database. For SQLite, dsn
+// is ignored and the template is a temporary file.
+func NewTemplate(dialect, dsn string, setup Setup) (*Template, error) {
+	tpl := &Template{dialect: dialect, dsn: dsn, setup: setup}
+	ctx := context.Background()
+	if isSQLite(dialect) {
+		dir, err := os.MkdirTemp("", "typegorm-template-")
+		if err != nil {
+			return nil, err
+		}
+		tpl.name, tpl.dsn = dir, filepath.Join(dir, "template.db")
+		if err := tpl.runSetup(ctx, tpl.dsn); err != nil {
+			_ = os.RemoveAll(dir)
+			return nil, err
+		}
+		return tpl, nil
+	}
+
+	admin, err := connect(dialect, dsn, StartupTimeout)
+	if err != nil {
+		return nil, err
+	}
+	tpl.admin = admin
+	if dialect != "postgres" {
+		return tpl, nil // Set up per database
+	}
+	tpl.name = fmt.Sprintf("typegorm_tpl_%d_%d", os.Getpid(), templateSeq.Add(1))
+	if err := tpl.createDatabase(ctx, tpl.name, ""); err != nil {
+		_ = admin.Close()
+		return nil, err
+	}
+	templateDSN, err := withDatabase(dialect, dsn, tpl.name)
+	if err == nil {
+		err = tpl.runSetup(ctx, templateDSN)
+	}
+	if err != nil {
+		_ = tpl.Close()
+		return nil, err
+	}
+	return tpl, nil
+}
+
+// runSetup sets up the database of dsn, closing the connection so that it can be cloned.
+func (tpl *Template) runSetup(ctx context.Context, dsn string) error {
+	db, err := connect(tpl.dialect, dsn, StartupTimeout)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if tpl.setup == nil {
+		return nil
+	}
+	if err := tpl.setup(ctx, db, dsn); err != nil {
+		return fmt.Errorf("setting up the template database: %w", err)
+	}
+	return nil
+}
+
+func (tpl *Template) createDatabase(ctx context.Context, name, template string) error {
+	quote := tpl.admin.GetDataSource().Dialect().Quote
+	createSQL := "CREATE DATABASE " + quote(name)
+	if template != "" {
+		createSQL += " TEMPLATE " + quote(template)
+	}
+	if _, err := tpl.admin.GetDataSource().Exec(ctx, createSQL); err != nil {
+		return fmt.Errorf("creating database %s: %w", name, err)
+	}
+	return nil
+}
+
+func (tpl *Template) dropDatabase(ctx context.Context, name string) error {
+	dropSQL := "DROP DATABASE IF EXISTS " + tpl.admin.GetDataSource().Dialect().Quote(name)
+	if _, err := tpl.admin.GetDataSource().Exec(ctx, dropSQL); err != nil {
+		return fmt.Errorf("dropping database %s: %w", name, err)
+	}
+	return nil
+}
+
+// Database returns a connected DB on a fresh copy of the template. The DB is closed
+// and the copy dropped when the test ends.
+func (tpl *Template) Database(t testing.TB) *typegorm.DB {
+	t.Helper()
+	ctx := context.Background()
+	n := tpl.clones.Add(1)
+
+	var dsn string
+	if isSQLite(tpl.dialect) {
+		dsn = filepath.Join(t.TempDir(), fmt.Sprintf("test_%d.db", n))
+		if err := copyFile(tpl.dsn, dsn); err != nil {
+			t.Fatalf("typegormtest: copying the template database: %v", err)
+		}
+	} else {
+		name := fmt.Sprintf("typegorm_test_%d_%d", os.Getpid(), templateSeq.Add(1))
+		if err := tpl.createDatabase(ctx, name, tpl.name); err != nil {
+			t.Fatalf("typegormtest: %v", err)
+		}
+		t.Cleanup(func() {
+			if err := tpl.dropDatabase(context.Background(), name); err != nil {
+				t.Logf("typegormtest: %v", err)
+			}
+		})
+		var err error
+		if dsn, err = withDatabase(tpl.dialect, tpl.dsn, name); err != nil {
+			t.Fatalf("typegormtest: %v", err)
+		}
+		if tpl.dialect != "postgres" {
+			if err := tpl.runSetup(ctx, dsn); err != nil {
+				t.Fatalf("typegormtest: %v", err)
+			}
+		}
+	}
+
+	db, err := connect(tpl.dialect, dsn, StartupTimeout)
+	if err != nil {
+		t.Fatalf("typegormtest: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() }) // Runs before the database is dropped
+	return db
+}
+
+// Close drops the template database and closes the server connection.
+func (tpl *Template) Close() error {
+	if tpl == nil {
+		return nil
+	}
+	if isSQLite(tpl.dialect) {
+		return os.RemoveAll(tpl.name)
+	}
+	var err error
+	if tpl.name != "" {
+		err = tpl.dropDatabase(context.Background(), tpl.name)
+	}
+	if closeErr := tpl.admin.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func isSQLite(dialect string) bool {
+	return dialect == "sqlite" || dialect == "sqlite3"
+}
+
+// withDatabase returns dsn pointing at another database of the same server.
+func withDatabase(dialect, dsn, name string) (string, error) {
+	switch dialect {
+	case "postgres", "sqlserver":
+		u, err := url.Parse(dsn)
+		if err != nil || u.Scheme == "" {
+			return "", fmt.Errorf("%s DSN must be a URL to select a database", dialect)
+		}
+		if dialect == "postgres" {
+			u.Path = "/" + name
+		} else {
+			query := u.Query()
+			query.Set("database", name)
+			u.RawQuery = query.Encode()
+		}
+		return u.String(), nil
+	case "mysql":
+		// user:password@tcp(host:port)/dbname?params
+		base, params, hasParams := strings.Cut(dsn, "?")
+		slash := strings.LastIndex(base, "/")
+		if slash < 0 {
+			return "", fmt.Errorf("cannot find the database name in the mysql DSN")
+		}
+		dsn = base[:slash+1] + name
+		if hasParams {
+			dsn += "?" + params
+		}
+		return dsn, nil
+	}
+	return "", fmt.Errorf("template databases are not supported for dialect %q", dialect)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,103 @@
+package typegormtest
+
+import (
+	"testing"
+
+	"github.com/chmenegatti/typegorm/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMappedPort(t *testing.T) {
+	port, err := parseMappedPort("127.0.0.1:49153\n")
+	require.NoError(t, err)
+	assert.Equal(t, "49153", port)
+
+	port, err = parseMappedPort("0.0.0.0:32768\n[::]:32768\n")
+	require.NoError(t, err)
+	assert.Equal(t, "32768", port)
+
+	_, err = parseMappedPort("")
+	assert.Error(t, err)
+}
+
+func TestStartDatabase_SkipsUnregisteredDialect(t *testing.T) {
+	skipped := t.Run("unregistered", func(t *testing.T) {
+		StartDatabase(t, "no-such-dialect")
+		t.Error("StartDatabase should have skipped the test")
+	})
+	assert.True(t, skipped, "a skipped subtest reports success")
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	before := schemaSnapshot{current: "public", tables: map[string]map[string]string{
+		"users":  {"id": "integer", "email": "varchar"},
+		"orders": {"id": "integer"},
+	}}
+	after := schemaSnapshot{current: "public", tables: map[string]map[string]string{
+		"users": {"id": "bigint", "name": "varchar"},
+		"items": {"id": "integer"},
+	}}
+	assert.Equal(t, []string{
+		"table orders is missing",
+		"column users.email is missing",
+		"column users.id is bigint instead of integer",
+		"column users.name is new",
+		"table items is new",
+	}, diffSnapshots(before, after))
+	assert.Empty(t, diffSnapshots(before, before))
+}
+
+func TestDiffModels(t *testing.T) {
+	snapshot := schemaSnapshot{current: "public", tables: map[string]map[string]string{
+		"users":           {"id": "integer", "legacy": "text"},
+		"billing.invoice": {"id": "integer"},
+	}}
+	models := []*schema.Model{
+		{Name: "User", TableName: "users", Fields: []*schema.Field{
+			{GoName: "ID", DBName: "id"}, {GoName: "Email", DBName: "email"}, {GoName: "Temp", IsIgnored: true},
+		}},
+		{Name: "Invoice", TableName: "invoice", Schema: "billing", Fields: []*schema.Field{{GoName: "ID", DBName: "id"}}},
+		{Name: "Order", TableName: "orders", Schema: "public"},
+	}
+	assert.Equal(t, []string{
+		"column users.email of field User.Email does not exist",
+		"column users.legacy has no field in model User",
+		"table orders of model Order does not exist",
+	}, diffModels(models, snapshot))
+}
+
+func TestWithDatabase(t *testing.T) {
+	dsn, err := withDatabase("postgres", "postgres://postgres:pw@127.0.0.1:5432/typegorm?sslmode=disable", "typegorm_test_1")
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://postgres:pw@127.0.0.1:5432/typegorm_test_1?sslmode=disable", dsn)
+
+	dsn, err = withDatabase("mysql", "root:pw@tcp(127.0.0.1:3306)/typegorm?parseTime=true", "typegorm_test_2")
+	require.NoError(t, err)
+	assert.Equal(t, "root:pw@tcp(127.0.0.1:3306)/typegorm_test_2?parseTime=true", dsn)
+
+	dsn, err = withDatabase("sqlserver", "sqlserver://sa:pw@127.0.0.1:1433?database=master", "typegorm_test_3")
+	require.NoError(t, err)
+	assert.Equal(t, "sqlserver://sa:pw@127.0.0.1:1433?database=typegorm_test_3", dsn)
+
+	_, err = withDatabase("postgres", "host=127.0.0.1 dbname=typegorm", "x")
+	assert.Error(t, err)
+	_, err = withDatabase("oracle", "x", "y")
+	assert.Error(t, err)
+}
+
+func TestNewIsolatedDB_SkipsUnregisteredDialect(t *testing.T) {
+	skipped := t.Run("unregistered", func(t *testing.T) {
+		NewIsolatedDB(t, "no-such-dialect")
+		t.Error("NewIsolatedDB should have skipped the test")
+	})
+	assert.True(t, skipped, "a skipped subtest reports success")
+	assert.NoError(t, StopShared())
+}
+
+func TestDescribeConds(t *testing.T) {
+	assert.Equal(t, "(no conditions)", describeConds(nil))
+	assert.Equal(t, "map[email:ana@example.com]", describeConds([]any{map[string]any{"email": "ana@example.com"}}))
+	assert.Contains(t, describeConds([]any{&SuiteRecord{Email: "ana@example.com"}}), "Email:ana@example.com")
+	assert.Equal(t, "age > ? AND active = ? with [18 true]", describeConds([]any{"age > ? AND active = ?", 18, true}))
+}